@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// runCheck validates the configuration and scans, parses, and generates from the
+// documents the way the server does at startup, without serving. Any error counts,
+// whether or not --ignore-errors is set, so that the error returned exits non-zero
+// when the configuration is invalid, a source or document fails, or no tools result.
+func runCheck(cmd *cobra.Command) error {
+	// A failing check is not a usage error, and Execute prints the error
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	d := &doctor{out: cmd.OutOrStdout()}
+
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		d.fail("Configuration", err.Error(),
+			"Fix the reported settings in the config file, the WX_MCP_* environment variables, or the flags")
+		return d.result()
+	}
+	d.ok("Configuration", "valid")
+
+	// The checks are printed instead of logs
+	resolvedConfig.Logging.Enabled = false
+	stats, err := collectGenerationStats(resolvedConfig, utils.NewLogger(resolvedConfig.Logging))
+	if err != nil {
+		d.fail("Generation", err.Error(), "Fix the reported setting or prompt template")
+		return d.result()
+	}
+
+	for _, scanError := range stats.Errors {
+		d.fail("Scan "+scanError.Path, scanError.Error,
+			"Check that the path exists and holds valid swagger documents, or that the URL can be fetched")
+	}
+	for _, document := range stats.Documents {
+		if document.Error != "" {
+			d.fail("Document "+document.Path, document.Error,
+				"Fix the document, or leave it out with --package-ids or the TWC and dynamic filters")
+		}
+	}
+
+	if stats.Tools == 0 {
+		d.fail("Tools", "no tools were generated",
+			"Check the swagger paths and URLs and that the filters match some documents")
+	} else {
+		d.ok("Tools", fmt.Sprintf("%d tools from %d documents", stats.Tools, stats.FilteredDocuments))
+	}
+	return d.result()
+}
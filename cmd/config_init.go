@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/config"
+)
+
+var (
+	// config init command flags
+	configInitOutput string
+	configInitForce  bool
+)
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a starter config file from the current flags and environment variables",
+	Long: `Write a commented swagger-mcp.config.yaml holding the swagger paths and URLs,
+filters, and server settings given by the server flags and WX_MCP_* environment
+variables, so that a long command line can move into a config file.
+
+The API key is not written to the file; keep it in WX_MCP_API_KEY instead.`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runConfigInit,
+}
+
+// runConfigInit writes the starter config file
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	content, err := config.NewManager().Scaffold(buildConfigOverrides(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to generate config file: %w", err)
+	}
+
+	if configInitOutput == "-" {
+		fmt.Print(string(content))
+		return nil
+	}
+
+	if _, err := os.Stat(configInitOutput); err == nil && !configInitForce {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", configInitOutput)
+	}
+	if err := os.WriteFile(configInitOutput, content, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", configInitOutput)
+	return nil
+}
+
+func init() {
+	configInitCmd.Flags().StringVarP(&configInitOutput, "output", "o", "swagger-mcp.config.yaml", "config file to write, or - for standard output")
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "overwrite the config file if it exists")
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+var (
+	// diff command flags
+	diffJSON           bool
+	diffFailOnBreaking bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <previous> <current>",
+	Short: "Compare the tools generated from two sets of swagger documents",
+	Long: `Generate the tools of two sets of swagger documents and report the tools added,
+removed, and renamed, and the tools whose arguments changed, to review the effect of
+a document change on MCP clients before rolling it out.
+
+Each set is a swagger file or directory, an http(s) URL, or a registry snapshot file
+(*.db, as written by --snapshot-path) holding a previous scan. The other server flags,
+such as the filters and tool generation options, apply to both sets.`,
+	Args:          cobra.ExactArgs(2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runDiff,
+}
+
+// runDiff reports the differences between the tools of two sets of documents
+func runDiff(cmd *cobra.Command, args []string) error {
+	// The two sets of documents are compared instead of the configured documents, so
+	// that none need to be configured
+	overrides := buildConfigOverrides(cmd)
+	if len(overrides.SwaggerPaths) == 0 && len(overrides.SwaggerURLs) == 0 {
+		overrides.SwaggerPaths = args
+	}
+	resolvedConfig, err := loadCommandConfigWithOverrides(cmd, overrides)
+	if err != nil {
+		return err
+	}
+
+	previous, err := loadDiffTools(args[0], resolvedConfig)
+	if err != nil {
+		return err
+	}
+	current, err := loadDiffTools(args[1], resolvedConfig)
+	if err != nil {
+		return err
+	}
+	diff := server.DiffTools(previous, current)
+
+	if diffJSON {
+		encoded, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode diff: %w", err)
+		}
+		fmt.Println(string(encoded))
+	} else {
+		printToolDiff(diff, len(previous), len(current))
+	}
+
+	if diffFailOnBreaking && diff.Breaking() {
+		return fmt.Errorf("the tools changed in ways that may break clients")
+	}
+	return nil
+}
+
+// loadDiffTools returns the generated tools of one side of a diff: the tools of a
+// registry snapshot, or the tools generated from a swagger path or URL
+func loadDiffTools(source string, config *types.ResolvedConfig) ([]*types.GeneratedTool, error) {
+	// The diff is printed instead of logs, and must not overwrite the snapshot
+	sourceConfig := *config
+	sourceConfig.Logging.Enabled = false
+	sourceConfig.Server.SnapshotPath = ""
+	logger := utils.NewLogger(sourceConfig.Logging)
+
+	if filepath.Ext(source) == ".db" {
+		if _, err := os.Stat(source); err != nil {
+			return nil, fmt.Errorf("failed to open registry snapshot: %w", err)
+		}
+		registry := server.NewToolRegistry()
+		registry.SetSchemaLoader(swagger.NewToolGeneratorWithConfig(logger, &sourceConfig.ToolGeneration).AttachInputSchema)
+		if _, err := server.NewRegistrySnapshot(source, logger).Load(registry, ""); err != nil {
+			return nil, err
+		}
+		return registry.GetAllTools(), nil
+	}
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		sourceConfig.SwaggerPaths = nil
+		sourceConfig.SwaggerURLs = []string{source}
+	} else {
+		if _, err := os.Stat(source); err != nil {
+			return nil, fmt.Errorf("failed to open swagger path: %w", err)
+		}
+		sourceConfig.SwaggerPaths = []string{source}
+		sourceConfig.SwaggerURLs = nil
+	}
+
+	mcpServer := server.NewMCPServer(&sourceConfig, logger)
+	if err := mcpServer.ScanTools(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to generate tools from %s: %w", source, err)
+	}
+
+	// Composite tools come from the configuration, which is the same on both sides
+	var tools []*types.GeneratedTool
+	for _, tool := range mcpServer.Tools() {
+		if tool.Composite == nil {
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// printToolDiff prints a diff for reading
+func printToolDiff(diff server.ToolDiff, previousCount, currentCount int) {
+	fmt.Printf("Tools: %d -> %d\n", previousCount, currentCount)
+	if diff.IsEmpty() {
+		fmt.Println("No changes.")
+		return
+	}
+
+	if len(diff.Added) > 0 {
+		fmt.Printf("\nAdded (%d):\n", len(diff.Added))
+		for _, name := range diff.Added {
+			fmt.Printf("  + %s\n", name)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("\nRemoved (%d):\n", len(diff.Removed))
+		for _, name := range diff.Removed {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+	if len(diff.Renamed) > 0 {
+		fmt.Printf("\nRenamed (%d):\n", len(diff.Renamed))
+		for _, rename := range diff.Renamed {
+			fmt.Printf("  ~ %s -> %s (%s)\n", rename.From, rename.To, rename.Endpoint)
+		}
+	}
+	if len(diff.Changed) > 0 {
+		fmt.Printf("\nArguments changed (%d):\n", len(diff.Changed))
+		for _, change := range diff.Changed {
+			fmt.Printf("  * %s\n", change.Tool)
+			printArguments("added", change.Added)
+			printArguments("removed", change.Removed)
+			printArguments("changed", change.Changed)
+			printArguments("now required", change.NowRequired)
+			printArguments("no longer required", change.NoLongerRequired)
+		}
+	}
+
+	if diff.Breaking() {
+		fmt.Println("\nSome changes may break clients calling the previous tools.")
+	}
+}
+
+// printArguments prints one kind of argument change of a tool, if there are any
+func printArguments(kind string, names []string) {
+	if len(names) > 0 {
+		fmt.Printf("      %s: %s\n", kind, strings.Join(names, ", "))
+	}
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "print the diff as JSON")
+	diffCmd.Flags().BoolVar(&diffFailOnBreaking, "fail-on-breaking", false, "exit non-zero when tools were removed or renamed, or arguments removed, changed, or made required")
+}
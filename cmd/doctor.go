@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	httpclient "swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration and the reachability of documents and APIs",
+	Long: `Check the configuration, the swagger paths and URLs, the API key, and the base
+URLs that tool calls are sent to, printing how to fix each failing check. Takes the
+same flags as the server, so the server's flags can be copied unchanged.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runDoctor,
+}
+
+// doctor prints the results of checks and counts the failing ones
+type doctor struct {
+	out      io.Writer
+	failures int
+}
+
+// ok reports a passing check
+func (d *doctor) ok(check, detail string) {
+	fmt.Fprintf(d.out, "[ok]   %s: %s\n", check, detail)
+}
+
+// warn reports a check that passed with a caveat, and how to address it
+func (d *doctor) warn(check, detail, remedy string) {
+	fmt.Fprintf(d.out, "[warn] %s: %s\n       -> %s\n", check, detail, remedy)
+}
+
+// fail reports a failing check and how to fix it
+func (d *doctor) fail(check, detail, remedy string) {
+	d.failures++
+	fmt.Fprintf(d.out, "[fail] %s: %s\n       -> %s\n", check, detail, remedy)
+}
+
+// result returns an error when any check failed, so that the command exits non-zero
+func (d *doctor) result() error {
+	if d.failures > 0 {
+		return fmt.Errorf("%d checks failed", d.failures)
+	}
+	fmt.Fprintln(d.out, "All checks passed.")
+	return nil
+}
+
+// runDoctor runs the checks of the doctor command
+func runDoctor(cmd *cobra.Command, args []string) error {
+	d := &doctor{out: cmd.OutOrStdout()}
+
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		d.fail("Configuration", err.Error(),
+			"Fix the reported settings in the config file, the WX_MCP_* environment variables, or the flags")
+		return d.result()
+	}
+	d.ok("Configuration", "valid")
+
+	d.checkSources(resolvedConfig)
+	d.checkAPIKey(resolvedConfig)
+	d.checkBaseURLs(resolvedConfig)
+	return d.result()
+}
+
+// checkSources checks that swagger documents are configured and that their paths
+// exist and their URLs can be fetched
+func (d *doctor) checkSources(config *types.ResolvedConfig) {
+	if len(config.SwaggerPaths) == 0 && len(config.SwaggerURLs) == 0 {
+		d.fail("Documents", "no swagger paths or URLs are configured",
+			"Pass --swagger-path or --swagger-url, set WX_MCP_PATHS or WX_MCP_URLS, or add swaggerPaths to the config file")
+		return
+	}
+
+	for _, path := range config.SwaggerPaths {
+		check := "Swagger path " + path
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			workingDir, _ := os.Getwd()
+			d.fail(check, "does not exist",
+				fmt.Sprintf("Check the path; relative paths are resolved from the working directory, %s", workingDir))
+		case err != nil:
+			d.fail(check, err.Error(), "Check that the path is readable by this user")
+		case info.IsDir():
+			d.ok(check, "directory exists")
+		default:
+			d.ok(check, "file exists")
+		}
+	}
+
+	for _, rawURL := range config.SwaggerURLs {
+		d.checkURL(rawURL, config)
+	}
+}
+
+// checkURL checks that a swagger URL can be fetched the way the scanner fetches it
+func (d *doctor) checkURL(rawURL string, config *types.ResolvedConfig) {
+	check := "Swagger URL " + rawURL
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		d.fail(check, "not an http or https URL", "Use the full URL of the document, including http:// or https://")
+		return
+	}
+	hosts := httpclient.NewHostPolicy(config)
+	if err := hosts.Check(parsed); err != nil {
+		d.fail(check, fmt.Sprintf("refused: %v", err), "Add the host to --allowed-hosts, or remove it from --denied-hosts")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.HTTP.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		d.fail(check, err.Error(), "Use the full URL of the document, including http:// or https://")
+		return
+	}
+	req.Header.Set("Accept", "application/json, application/yaml, text/yaml, */*")
+	req.Header.Set("User-Agent", "swagger-docs-mcp/1.0.0")
+
+	resp, err := hosts.Client(config.HTTP.Timeout).Do(req)
+	if err != nil {
+		d.fail(check, fmt.Sprintf("unreachable: %v", err),
+			"Check the host name, the network access of this machine, and its HTTPS_PROXY setting")
+		return
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		d.ok(check, "reachable")
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		d.fail(check, resp.Status,
+			"Documents are fetched without credentials; download the document and pass it with --swagger-path instead")
+	default:
+		d.fail(check, resp.Status, "Check the URL; the document must be served with status 200")
+	}
+}
+
+// checkAPIKey checks that tool calls have credentials to send
+func (d *doctor) checkAPIKey(config *types.ResolvedConfig) {
+	switch {
+	case config.Mock.Enabled:
+		d.ok("API key", "not needed, tool calls are mocked")
+	case config.Auth.APIKey != "":
+		d.ok("API key", fmt.Sprintf("set (%d characters)", len(config.Auth.APIKey)))
+	case len(config.Auth.Credentials) > 0:
+		d.warn("API key", fmt.Sprintf("not set, only the %d configured credentials are sent", len(config.Auth.Credentials)),
+			"Pass --api-key or set WX_MCP_API_KEY if APIs without credentials of their own need a key")
+	default:
+		d.fail("API key", "not set, tool calls will be sent without credentials",
+			"Pass --api-key, set WX_MCP_API_KEY, or set auth.apiKey in the config file")
+	}
+}
+
+// checkBaseURLs generates the tools and checks that the base URLs their calls are
+// sent to are valid, by document
+func (d *doctor) checkBaseURLs(config *types.ResolvedConfig) {
+	if len(config.SwaggerPaths) == 0 && len(config.SwaggerURLs) == 0 {
+		return
+	}
+
+	// The checks are printed instead of logs, and must not overwrite the snapshot
+	scanConfig := *config
+	scanConfig.Logging.Enabled = false
+	scanConfig.Server.SnapshotPath = ""
+	scanConfig.Server.UsagePath = ""
+	logger := utils.NewLogger(scanConfig.Logging)
+
+	mcpServer := server.NewMCPServer(&scanConfig, logger)
+	if err := mcpServer.ScanTools(context.Background()); err != nil {
+		d.fail("Tool generation", err.Error(),
+			"Fix the reported document, or pass --ignore-errors to skip documents that fail to parse")
+		return
+	}
+
+	documents := make(map[string]*baseURLCheck)
+	toolCount := 0
+	client := httpclient.NewClient(&scanConfig, logger)
+	for _, tool := range mcpServer.Tools() {
+		if tool.Endpoint == nil {
+			continue
+		}
+		toolCount++
+
+		name := "(no document)"
+		if tool.DocumentInfo != nil {
+			name = tool.DocumentInfo.Title
+			if name == "" {
+				name = tool.DocumentInfo.FilePath
+			}
+		}
+		document, exists := documents[name]
+		if !exists {
+			document = &baseURLCheck{baseURLs: make(map[string]bool)}
+			documents[name] = document
+		}
+
+		baseURL, err := client.ResolveBaseURL(tool.Endpoint)
+		if err == nil {
+			err = validateBaseURL(baseURL)
+		}
+		if err != nil {
+			document.problems = append(document.problems, fmt.Sprintf("%s: %v", tool.Name, err))
+			continue
+		}
+		document.baseURLs[baseURL] = true
+	}
+
+	if toolCount == 0 {
+		d.fail("Tool generation", "no tools were generated",
+			"Check that the paths hold swagger documents and that --package-id and the TWC filters match them")
+		return
+	}
+	d.ok("Tool generation", fmt.Sprintf("%d tools from %d documents", toolCount, len(documents)))
+
+	names := make([]string, 0, len(documents))
+	for name := range documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		document := documents[name]
+		check := "Base URL of " + name
+		if len(document.problems) > 0 {
+			detail := document.problems[0]
+			if len(document.problems) > 1 {
+				detail += fmt.Sprintf(" (and %d more tools)", len(document.problems)-1)
+			}
+			d.fail(check, detail,
+				"Declare an absolute http or https server URL in the document, with defaults for its server variables")
+			continue
+		}
+
+		baseURLs := make([]string, 0, len(document.baseURLs))
+		for baseURL := range document.baseURLs {
+			baseURLs = append(baseURLs, baseURL)
+		}
+		sort.Strings(baseURLs)
+		d.ok(check, strings.Join(baseURLs, ", "))
+	}
+}
+
+// baseURLCheck collects the base URLs of the tools of a document and their problems
+type baseURLCheck struct {
+	baseURLs map[string]bool
+	problems []string
+}
+
+// validateBaseURL returns an error unless a base URL is an absolute http or https
+// URL with all of its server variables replaced
+func validateBaseURL(baseURL string) error {
+	if strings.ContainsAny(baseURL, "{}") {
+		return fmt.Errorf("server URL %s has variables without defaults", baseURL)
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL %s: %w", baseURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("server URL %s is not an absolute http or https URL", baseURL)
+	}
+	return nil
+}
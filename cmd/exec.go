@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/config"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// completionCacheTTL is how long the tool names cached for shell completion are used
+// before the documents are scanned again
+const completionCacheTTL = 10 * time.Minute
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec <tool> [arguments]",
+	Short: "Call a generated tool from the command line",
+	Long: `Scan the swagger documents and call one of the generated tools, printing its
+result. Arguments are given as a JSON object, e.g.
+
+  swagger-docs-mcp exec get_v3_wx_conditions_current '{"geocode": "33.74,-84.39"}'
+
+With shell completion enabled (see "swagger-docs-mcp completion --help"), tool
+names are completed from a cached scan of the documents.`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeToolNames,
+	RunE:              runExec,
+}
+
+// runExec calls a tool with the arguments given on the command line
+func runExec(cmd *cobra.Command, args []string) error {
+	arguments := make(map[string]interface{})
+	if len(args) > 1 {
+		if err := json.Unmarshal([]byte(args[1]), &arguments); err != nil {
+			return fmt.Errorf("tool arguments must be a JSON object: %w", err)
+		}
+	}
+
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	logger := utils.NewLogger(resolvedConfig.Logging)
+	defer func() {
+		_ = logger.Close()
+	}()
+	if debug || resolvedConfig.Debug {
+		logger.UpdateConfig(types.LoggingConfig{
+			Enabled: true,
+			Level:   "debug",
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shared := server.NewSharedState(resolvedConfig, logger)
+	defer shared.Close()
+	mcpServer := server.NewSharedMCPServer(resolvedConfig, logger, shared, true)
+	if err := mcpServer.ScanTools(ctx); err != nil {
+		return err
+	}
+
+	result, err := mcpServer.CallTool(ctx, args[0], arguments)
+	if err != nil {
+		return err
+	}
+	for _, content := range result.Content {
+		fmt.Println(content.Text)
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s returned an error", args[0])
+	}
+	return nil
+}
+
+// completeToolNames completes the tool argument of exec with the names of the tools
+// generated under the configuration of the command line. The tools are cached in a
+// registry snapshot, the configured one or one in the user cache directory, so that
+// completion only scans the documents when the cache is missing or stale.
+func completeToolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+	// Logs would be printed among the completions
+	resolvedConfig.Logging.Enabled = false
+	logger := utils.NewLogger(resolvedConfig.Logging)
+
+	// Completions keep their own cache rather than writing to the server's snapshot,
+	// which a running server may hold open. A cache of another configuration is
+	// rejected by its fingerprint and replaced.
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+	resolvedConfig.Server.SnapshotPath = filepath.Join(cacheDir, "swagger-docs-mcp", "completion.db")
+
+	// Tools generated from a stale cache may no longer exist, so the cache is only
+	// used while it is recent
+	mcpServer := server.NewMCPServer(resolvedConfig, logger)
+	info, err := os.Stat(resolvedConfig.Server.SnapshotPath)
+	if err != nil || time.Since(info.ModTime()) > completionCacheTTL || mcpServer.LoadSnapshot() == 0 {
+		if err := mcpServer.ScanTools(context.Background()); err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+	}
+
+	tools := mcpServer.Tools()
+	completions := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if strings.HasPrefix(tool.Name, toComplete) {
+			completions = append(completions, tool.Name+"\t"+completionDescription(tool.Description))
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionDescription shortens a tool description to its first line for display
+// next to the tool name
+func completionDescription(description string) string {
+	description, _, _ = strings.Cut(strings.TrimSpace(description), "\n")
+	if len(description) > 80 {
+		description = description[:77] + "..."
+	}
+	return description
+}
+
+// loadCommandConfig loads the configuration of a command taking the server flags
+func loadCommandConfig(cmd *cobra.Command) (*types.ResolvedConfig, error) {
+	return loadCommandConfigWithOverrides(cmd, buildConfigOverrides(cmd))
+}
+
+// loadCommandConfigWithOverrides loads the configuration of a command taking the
+// server flags, with overrides adjusted by the command
+func loadCommandConfigWithOverrides(cmd *cobra.Command, overrides *types.ResolvedConfig) (*types.ResolvedConfig, error) {
+	configManager := config.NewManager()
+
+	var resolvedConfig *types.ResolvedConfig
+	var err error
+	if len(configFiles) > 0 {
+		resolvedConfig, err = configManager.LoadFromFiles(configFiles, overrides)
+	} else {
+		resolvedConfig, err = configManager.Load(overrides)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	applyFlagSwitches(cmd, resolvedConfig)
+	return resolvedConfig, nil
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"swagger-docs-mcp/pkg/config"
+	"swagger-docs-mcp/pkg/types"
+)
+
+var (
+	// install command flags
+	installName   string
+	installOutput string
+	installDryRun bool
+)
+
+// installPathFlags are the flags holding file paths, which are made absolute because
+// MCP clients start the server from another working directory
+var installPathFlags = map[string]bool{
+	"config":        true,
+	"swagger-paths": true,
+	"swagger-path":  true,
+	"snapshot-path": true,
+	"cassette-dir":  true,
+}
+
+// installSkippedFlags are the flags left out of installed configurations: MCP clients
+// run the server over stdio
+var installSkippedFlags = map[string]bool{
+	"sse":           true,
+	"mcp-http":      true,
+	"transports":    true,
+	"port":          true,
+	"mcp-http-port": true,
+	"version":       true,
+	"api-key":       true,
+}
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:       "install <claude|cursor|vscode>",
+	Short:     "Add this server to the configuration of an MCP client",
+	ValidArgs: []string{"claude", "cursor", "vscode"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Long: `Add this server to the MCP server configuration of Claude Desktop, Cursor, or
+VS Code, with the path of this binary and the flags given to this command. WX_MCP_*
+environment variables and --api-key are passed as environment variables. Other
+servers in the configuration file are kept.
+
+  claude   claude_desktop_config.json in the Claude Desktop configuration directory
+  cursor   ~/.cursor/mcp.json
+  vscode   .vscode/mcp.json in the current directory`,
+	RunE: runInstall,
+}
+
+// runInstall writes the server entry to the configuration file of an MCP client
+func runInstall(cmd *cobra.Command, args []string) error {
+	// Refuse to install a configuration the server would fail to load
+	configManager := config.NewManager()
+	overrides := buildConfigOverrides(cmd)
+	var resolvedConfig *types.ResolvedConfig
+	var err error
+	if len(configFiles) > 0 {
+		resolvedConfig, err = configManager.LoadFromFiles(configFiles, overrides)
+	} else {
+		resolvedConfig, err = configManager.Load(overrides)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	applyFlagSwitches(cmd, resolvedConfig)
+
+	client := args[0]
+	path := installOutput
+	if path == "" {
+		if path, err = clientConfigPath(client); err != nil {
+			return err
+		}
+	}
+
+	command, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the swagger-docs-mcp binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(command); err == nil {
+		command = resolved
+	}
+	serverArgs, err := installArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	entry := map[string]interface{}{
+		"command": command,
+		"args":    serverArgs,
+	}
+	if env := installEnv(); len(env) > 0 {
+		entry["env"] = env
+	}
+	serversKey := "mcpServers"
+	if client == "vscode" {
+		serversKey = "servers"
+		entry["type"] = "stdio"
+	}
+
+	clientConfig := make(map[string]interface{})
+	if content, err := os.ReadFile(path); err == nil {
+		if len(strings.TrimSpace(string(content))) > 0 {
+			if err := json.Unmarshal(content, &clientConfig); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	servers, _ := clientConfig[serversKey].(map[string]interface{})
+	if servers == nil {
+		servers = make(map[string]interface{})
+	}
+	servers[installName] = entry
+	clientConfig[serversKey] = servers
+
+	content, err := json.MarshalIndent(clientConfig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode client configuration: %w", err)
+	}
+	content = append(content, '\n')
+
+	if installDryRun {
+		fmt.Printf("# %s\n%s", path, content)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	// The entry may hold an API key, so new files are only readable by their owner
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("Installed %s in %s\n", installName, path)
+	fmt.Printf("Restart %s to load the server.\n", clientDisplayName(client))
+	return nil
+}
+
+// clientConfigPath returns the default path of the MCP configuration file of a client
+func clientConfigPath(client string) (string, error) {
+	switch client {
+	case "claude":
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the user configuration directory: %w", err)
+		}
+		return filepath.Join(configDir, "Claude", "claude_desktop_config.json"), nil
+	case "cursor":
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".cursor", "mcp.json"), nil
+	case "vscode":
+		return filepath.Join(".vscode", "mcp.json"), nil
+	}
+	return "", fmt.Errorf("unsupported MCP client: %s", client)
+}
+
+// clientDisplayName returns the name of a client as shown to users
+func clientDisplayName(client string) string {
+	switch client {
+	case "claude":
+		return "Claude Desktop"
+	case "cursor":
+		return "Cursor"
+	case "vscode":
+		return "VS Code"
+	}
+	return client
+}
+
+// installArgs returns the server flags given to the install command as arguments of
+// the installed server, with file paths made absolute
+func installArgs(cmd *cobra.Command) ([]string, error) {
+	serverArgs := []string{}
+	var err error
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if err != nil || rootCmd.Flags().Lookup(flag.Name) == nil || installSkippedFlags[flag.Name] {
+			return
+		}
+
+		values := []string{flag.Value.String()}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values = slice.GetSlice()
+		}
+		for _, value := range values {
+			if installPathFlags[flag.Name] && !config.IsRemoteConfig(value) {
+				if value, err = filepath.Abs(value); err != nil {
+					err = fmt.Errorf("failed to resolve --%s path: %w", flag.Name, err)
+					return
+				}
+			}
+			serverArgs = append(serverArgs, fmt.Sprintf("--%s=%s", flag.Name, value))
+		}
+	})
+	return serverArgs, err
+}
+
+// installEnv returns the environment variables of the installed server: the WX_MCP_*
+// variables of this process and the API key given with --api-key
+func installEnv() map[string]string {
+	env := make(map[string]string)
+	for _, variable := range os.Environ() {
+		name, value, found := strings.Cut(variable, "=")
+		if found && strings.HasPrefix(name, "WX_MCP_") {
+			env[name] = value
+		}
+	}
+	if apiKey != "" {
+		env["WX_MCP_API_KEY"] = apiKey
+	}
+	return env
+}
+
+func init() {
+	installCmd.Flags().StringVar(&installName, "name", "swagger-docs-mcp", "name of the server entry in the client configuration")
+	installCmd.Flags().StringVar(&installOutput, "output", "", "client configuration file to write instead of the client's default")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "print the client configuration instead of writing it")
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+var (
+	// probe command flags
+	probeCount int
+	probeTools []string
+)
+
+// probeCmd represents the probe command
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Call a sample of GET tools against the live API",
+	Long: `Call a sample of the generated GET tools with the example values documented for
+their parameters and report the status and latency of each call, to check the API key,
+credentials, and base URLs end to end. Tools with required parameters that have no
+example, default, or enum value are left out. The sample is spread across documents,
+and can be narrowed with --tools.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runProbe,
+}
+
+// probeCall is a tool call of the probe with the arguments it is called with
+type probeCall struct {
+	tool      *types.GeneratedTool
+	arguments map[string]interface{}
+}
+
+// runProbe calls the sampled tools and reports their results
+func runProbe(cmd *cobra.Command, args []string) error {
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		return err
+	}
+	// The probe checks the live API, so calls are neither mocked nor replayed, and the
+	// results are printed instead of logs
+	resolvedConfig.Mock.Enabled = false
+	resolvedConfig.Cassette.Dir = ""
+	resolvedConfig.Server.SnapshotPath = ""
+	resolvedConfig.Server.UsagePath = ""
+	resolvedConfig.Logging.Enabled = false
+	logger := utils.NewLogger(resolvedConfig.Logging)
+
+	ctx := context.Background()
+	shared := server.NewSharedState(resolvedConfig, logger)
+	if err := server.NewSharedMCPServer(resolvedConfig, logger, shared, true).ScanTools(ctx); err != nil {
+		return err
+	}
+
+	calls, skipped := sampleProbeCalls(shared.Tools.GetAllTools(), probeCount, probeTools)
+	if len(calls) == 0 {
+		return fmt.Errorf("no GET tools to probe (%d left out for lack of example values)", skipped)
+	}
+
+	failures := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOOL\tENDPOINT\tSTATUS\tLATENCY")
+	for _, call := range calls {
+		started := time.Now()
+		response, err := shared.Executor.Execute(ctx, shared.HTTPClient, call.tool, call.arguments)
+		latency := time.Since(started).Round(time.Millisecond)
+
+		var status string
+		if err != nil {
+			status = "error: " + err.Error()
+		} else {
+			status = strconv.Itoa(response.StatusCode)
+		}
+		if err != nil || response.StatusCode >= 400 {
+			failures++
+		}
+		fmt.Fprintf(writer, "%s\t%s %s\t%s\t%s\n", call.tool.Name, call.tool.Endpoint.Method, call.tool.Endpoint.Path, status, latency)
+	}
+	writer.Flush()
+
+	fmt.Printf("\n%d probed, %d failed", len(calls), failures)
+	if skipped > 0 {
+		fmt.Printf(", %d GET tools left out for lack of example values", skipped)
+	}
+	fmt.Println()
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d probes failed", failures, len(calls))
+	}
+	return nil
+}
+
+// sampleProbeCalls picks up to count GET tools matching the patterns, if any, taking
+// one tool of each document in turn so that every document's base URL is covered. It
+// returns the calls and how many tools were left out for lack of example values.
+func sampleProbeCalls(tools []*types.GeneratedTool, count int, patterns []string) ([]probeCall, int) {
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	var documents []string
+	byDocument := make(map[string][]probeCall)
+	skipped := 0
+	for _, tool := range tools {
+		if tool.Endpoint == nil || !strings.EqualFold(tool.Endpoint.Method, "GET") || !matchesAnyPattern(tool.Name, patterns) {
+			continue
+		}
+		// GraphQL, gRPC, and AsyncAPI operations are not plain GET requests
+		if tool.Endpoint.Protocol != "" {
+			continue
+		}
+
+		arguments, missing := swagger.ExampleArguments(tool.Endpoint)
+		if len(missing) > 0 {
+			skipped++
+			continue
+		}
+
+		document := ""
+		if tool.DocumentInfo != nil {
+			document = tool.DocumentInfo.FilePath
+		}
+		if _, exists := byDocument[document]; !exists {
+			documents = append(documents, document)
+		}
+		byDocument[document] = append(byDocument[document], probeCall{tool: tool, arguments: arguments})
+	}
+
+	var calls []probeCall
+	for round := 0; len(calls) < count; round++ {
+		added := false
+		for _, document := range documents {
+			if round < len(byDocument[document]) && len(calls) < count {
+				calls = append(calls, byDocument[document][round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return calls, skipped
+}
+
+// matchesAnyPattern reports whether a tool name matches one of the glob patterns, or
+// whether there are none
+func matchesAnyPattern(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	probeCmd.Flags().IntVar(&probeCount, "count", 10, "maximum number of tools to call")
+	probeCmd.Flags().StringSliceVar(&probeTools, "tools", nil, "glob patterns of the tools to sample from, e.g. get_v3_*")
+}
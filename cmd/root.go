@@ -1,29 +1,38 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 	"swagger-docs-mcp/pkg/config"
+	httpclient "swagger-docs-mcp/pkg/http"
 	"swagger-docs-mcp/pkg/mcp"
 	"swagger-docs-mcp/pkg/server"
 	"swagger-docs-mcp/pkg/sse"
 	"swagger-docs-mcp/pkg/swagger"
 	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/update"
 	"swagger-docs-mcp/pkg/utils"
 	"swagger-docs-mcp/pkg/version"
 )
 
 var (
 	// CLI flags
-	configFile        string
+	configFiles       []string
+	profile           string
+	configChecksums   []string
+	configRefresh     time.Duration
 	swaggerPaths      []string
 	swaggerPath       []string
 	swaggerURLs       []string
@@ -36,19 +45,78 @@ var (
 	apiKey            string
 	debug             bool
 	logLevel          string
+	egressSampleRate  float64
 	timeout           time.Duration
 	maxTools          int
 	validateDocuments bool
 	resolveReferences bool
 	ignoreErrors      bool
+	watchFiles        bool
+	refreshInterval   time.Duration
+	staleGracePeriod  time.Duration
+	allowedRefHosts   []string
+	graphQLEndpoint   string
+	grpcGateway       string
 	userAgent         string
 	retries           int
 	sseMode           bool
 	mcpHTTPMode       bool
+	transports        []string
+	toolPriority      []string
+	eventSensitive    []string
+	eventMaxResult    int
+	eventLogPath      string
+	eventLogMaxAge    time.Duration
+	quotaPerMinute    int
+	quotaPerDay       int
+	webhookURLs       []string
+	busType           string
+	busBrokers        []string
+	busTopic          string
+	sharedRegistryURL string
+	refreshLeaderOnly bool
+	mcpHTTPPort       int
+	listenAddress     string
+	mcpHTTPListen     string
+	socketMode        string
+	maxMessageSize    int
+	blobMaxInline     int
+	blobMaxInlineText int
+	maxResponseChars  int
+	telemetryMode     string
+	telemetryEndpoint string
+	checkForUpdates   bool
+	updateRepository  string
+	pluginSpecs       []string
+	blobTTL           time.Duration
+	mcpAuthTokens     []string
+	mcpOIDCIssuer     string
+	mcpOIDCAudience   string
+	snapshotPath      string
+	usagePath         string
+	docsAssetsURL     string
+	mockMode          bool
+	mockDocuments     []string
+	validateResponses bool
+	hedgeDelay        time.Duration
+	allowedHosts      []string
+	maxConcurrent     int
+	maxPerHost        int
+	requestQueueSize  int
+	deniedHosts       []string
+	cassetteDir       string
+	cassetteMode      string
+	maxPages          int
 	port              int
 	showVersion       bool
+	checkMode         bool
 	ignoreFormats     []string
+	disabledTools     []string
+	unsupportedAuth   string
 	preferFormat      string
+	omitFilterArg     bool
+	lazySchemas       bool
+	gatewayMode       bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -71,7 +139,10 @@ func Execute() {
 
 func init() {
 	// Configuration flags
-	rootCmd.Flags().StringVarP(&configFile, "config", "c", "", "config file path")
+	rootCmd.Flags().StringArrayVarP(&configFiles, "config", "c", nil, "config file path; repeat to overlay files in order")
+	rootCmd.Flags().StringVar(&profile, "profile", "", "config file profile to apply, e.g. dev, staging, or prod")
+	rootCmd.Flags().StringArrayVar(&configChecksums, "config-checksum", nil, "sha256:<hex> digest, or URL of a checksum file, that remote config files must match")
+	rootCmd.Flags().DurationVar(&configRefresh, "config-refresh", 0, "interval for re-fetching remote config files to restart when they change (0 disables)")
 
 	// Swagger document sources
 	rootCmd.Flags().StringSliceVar(&swaggerPaths, "swagger-paths", []string{}, "comma-separated list of swagger document paths")
@@ -94,29 +165,104 @@ func init() {
 	// Server configuration
 	rootCmd.Flags().BoolVarP(&debug, "debug", "v", false, "enable verbose/debug logging")
 	rootCmd.Flags().StringVarP(&logLevel, "log-level", "l", "info", "log level (error, warn, info, debug)")
+	rootCmd.Flags().Float64Var(&egressSampleRate, "log-egress-sample-rate", 0, "log this fraction (0 to 1) of upstream requests in full, with credentials redacted")
 	rootCmd.Flags().DurationVarP(&timeout, "timeout", "t", 30*time.Second, "server timeout")
 	rootCmd.Flags().IntVarP(&maxTools, "max-tools", "m", 1000, "maximum number of tools to generate")
+	rootCmd.Flags().StringSliceVar(&toolPriority, "tool-priority", []string{}, "glob patterns of tool names kept first when there are more tools than --max-tools")
+	rootCmd.Flags().StringSliceVar(&eventSensitive, "event-sensitive-fields", []string{}, "argument names or glob patterns redacted from tool_execution events broadcast to SSE clients")
+	rootCmd.Flags().IntVar(&quotaPerMinute, "quota-per-minute", 0, "tool calls each API key may make per minute through the SSE endpoints (0 is unlimited)")
+	rootCmd.Flags().IntVar(&quotaPerDay, "quota-per-day", 0, "tool calls each API key may make per UTC day through the SSE endpoints (0 is unlimited)")
+	rootCmd.Flags().IntVar(&eventMaxResult, "event-max-result-size", 0, "omit results larger than this many bytes from tool_execution events (0 keeps all)")
+	rootCmd.Flags().StringVar(&eventLogPath, "event-log-path", "", "file the events broadcast to SSE clients are appended to, for /events/history and Last-Event-ID replay")
+	rootCmd.Flags().DurationVar(&eventLogMaxAge, "event-log-max-age", 0, "drop events older than this from the event log (default 24h)")
+	rootCmd.Flags().StringVar(&busType, "bus-type", "", "message bus tool call and tool change events are published to: nats, or kafka through a Kafka REST proxy")
+	rootCmd.Flags().StringSliceVar(&busBrokers, "bus-brokers", []string{}, "NATS server or Kafka REST proxy URLs of the message bus")
+	rootCmd.Flags().StringVar(&busTopic, "bus-topic", "", "NATS subject or Kafka topic events are published to")
+	rootCmd.Flags().StringVar(&sharedRegistryURL, "shared-registry-url", "", "Redis URL (redis:// or rediss://) of the tool catalog shared by the replicas of a deployment")
+	rootCmd.Flags().BoolVar(&refreshLeaderOnly, "refresh-leader-only", false, "leave periodic refreshes of remote documents to the replica leading the shared registry")
+	rootCmd.Flags().StringSliceVar(&webhookURLs, "webhook-url", []string{}, "URL every tool call is posted to as a JSON tool_execution event, and failed calls also as tool_error (repeatable)")
 
 	// Swagger processing
 	rootCmd.Flags().BoolVarP(&validateDocuments, "validate-documents", "d", false, "validate swagger documents")
 	rootCmd.Flags().BoolVarP(&resolveReferences, "resolve-references", "R", true, "resolve $ref references in swagger documents")
 	rootCmd.Flags().BoolVarP(&ignoreErrors, "ignore-errors", "i", true, "ignore errors in swagger documents")
+	rootCmd.Flags().StringSliceVar(&allowedRefHosts, "allowed-ref-hosts", []string{}, "hosts allowed for remote $ref resolution (* allows any)")
+	rootCmd.Flags().BoolVar(&watchFiles, "watch-files", false, "reload local swagger files when they change on disk")
+	rootCmd.Flags().DurationVar(&refreshInterval, "refresh-interval", 0, "interval for re-fetching remote swagger URLs (0 disables)")
+	rootCmd.Flags().DurationVar(&staleGracePeriod, "stale-grace-period", 0, "evict tools of remote swagger URLs that keep failing to refresh for this long (0 keeps them)")
+	rootCmd.Flags().StringVar(&grpcGateway, "grpc-gateway", "", "HTTP/JSON gateway URL used to execute tools generated from gRPC descriptor sets")
+	rootCmd.Flags().StringVar(&graphQLEndpoint, "graphql-endpoint", "", "GraphQL HTTP endpoint used to execute tools generated from GraphQL schemas")
 
 	// HTTP configuration
 	rootCmd.Flags().StringVarP(&userAgent, "user-agent", "a", "swagger-docs-mcp/1.0.0", "HTTP user agent")
 	rootCmd.Flags().IntVarP(&retries, "retries", "r", 3, "number of HTTP retries")
+	rootCmd.Flags().DurationVar(&hedgeDelay, "hedge-delay", 0, "send a second copy of GET requests that have not completed after this delay, taking the first response (0 disables)")
+	rootCmd.Flags().IntVar(&maxConcurrent, "max-concurrent-requests", 0, "maximum upstream requests in flight across all hosts (0 is unlimited)")
+	rootCmd.Flags().IntVar(&maxPerHost, "max-concurrent-per-host", 0, "maximum upstream requests in flight per host (0 is unlimited)")
+	rootCmd.Flags().IntVar(&requestQueueSize, "request-queue-size", 0, "tool calls waiting for a request slot beyond the concurrency limits before calls are rejected")
+	rootCmd.Flags().StringSliceVar(&allowedHosts, "allowed-hosts", []string{}, "only send requests to these hosts, host:port, *.domain, IP, or CIDR patterns and the configured URLs (repeatable)")
+	rootCmd.Flags().StringSliceVar(&deniedHosts, "denied-hosts", []string{}, "never send requests to these host, host:port, *.domain, IP, or CIDR patterns (repeatable)")
+
+	// Mock execution
+	rootCmd.Flags().BoolVar(&mockMode, "mock", false, "return example responses instead of calling the upstream API")
+	rootCmd.Flags().StringSliceVar(&mockDocuments, "mock-document", []string{}, "return example responses for tools of documents matching this pattern (repeatable)")
+
+	// Response validation
+	rootCmd.Flags().BoolVar(&validateResponses, "validate-responses", false, "check tool call responses against the declared response schemas and attach mismatch warnings to results")
+
+	// Record/replay
+	rootCmd.Flags().StringVar(&cassetteDir, "cassette-dir", "", "directory to record tool call responses to and replay them from")
+	rootCmd.Flags().StringVar(&cassetteMode, "cassette-mode", "", "when to replay and record tool calls (auto, record, replay)")
+
+	// Pagination
+	rootCmd.Flags().IntVar(&maxPages, "max-pages", 0, "maximum number of pages merged for tool calls with _fetchAll (default 10)")
+
+	// Tool execution
+	rootCmd.Flags().IntVar(&maxResponseChars, "max-response-chars", 0, "truncate text tool responses longer than this many characters to their head and tail (0 returns any length)")
+
+	// Telemetry
+	rootCmd.Flags().StringVar(&telemetryMode, "telemetry", "", "send anonymous usage counts: off or on (default off; see the telemetry command for what is sent)")
+	rootCmd.Flags().StringVar(&telemetryEndpoint, "telemetry-endpoint", "", "URL telemetry reports are posted to")
+
+	// Update check
+	rootCmd.Flags().BoolVar(&checkForUpdates, "check-for-updates", false, "log a notice at startup when a newer release is available on GitHub")
+	rootCmd.Flags().StringVar(&updateRepository, "update-repository", "", "GitHub repository (owner/name) releases are looked up in (default "+update.DefaultRepository+")")
+
+	// Plugins
+	rootCmd.Flags().StringArrayVar(&pluginSpecs, "plugin", []string{}, "executable hooked into upstream requests as hook=command, with hook pre-request, auth, or post-response (repeatable)")
 
 	// Server mode
 	rootCmd.Flags().BoolVar(&sseMode, "sse", false, "run as SSE server instead of MCP server")
 	rootCmd.Flags().BoolVarP(&mcpHTTPMode, "mcp-http", "H", false, "run as MCP HTTP server instead of stdio MCP server")
 	rootCmd.Flags().IntVarP(&port, "port", "p", 8080, "port for SSE/MCP HTTP server")
+	rootCmd.Flags().StringSliceVar(&transports, "transports", []string{}, "comma-separated list of transports to serve from one process (stdio, sse, mcp-http)")
+	rootCmd.Flags().IntVar(&maxMessageSize, "max-message-size", 0, "size in bytes of the largest JSON-RPC message read from stdin (default 16777216)")
+	rootCmd.Flags().IntVar(&blobMaxInline, "blob-max-inline-size", 0, "size in bytes of the largest binary tool response returned as base64 content (default 1048576)")
+	rootCmd.Flags().IntVar(&blobMaxInlineText, "blob-max-inline-text-size", 0, "size in bytes of the largest text tool response returned inline; larger ones are offloaded to resources (0 returns any size)")
+	rootCmd.Flags().DurationVar(&blobTTL, "blob-ttl", 0, "how long larger binary tool responses can be read as resources (default 15m)")
+	rootCmd.Flags().StringVar(&listenAddress, "listen", "", "address for SSE/MCP HTTP server instead of --port, host:port or unix:/path.sock")
+	rootCmd.Flags().StringVar(&socketMode, "socket-mode", "", "octal file mode of Unix domain sockets (default 0600)")
+	rootCmd.Flags().StringVar(&mcpHTTPListen, "mcp-http-listen", "", "address for the MCP HTTP transport when served alongside SSE, host:port or unix:/path.sock")
+	rootCmd.Flags().IntVar(&mcpHTTPPort, "mcp-http-port", 0, "port for the MCP HTTP transport when served alongside SSE (default --port + 1)")
+	rootCmd.Flags().StringSliceVar(&mcpAuthTokens, "mcp-auth-token", []string{}, "static bearer token accepted by the MCP HTTP endpoint (repeatable)")
+	rootCmd.Flags().StringVar(&mcpOIDCIssuer, "mcp-oidc-issuer", "", "OIDC issuer URL whose JWTs are accepted by the MCP HTTP endpoint")
+	rootCmd.Flags().StringVar(&mcpOIDCAudience, "mcp-oidc-audience", "", "expected audience of OIDC JWTs presented to the MCP HTTP endpoint")
+	rootCmd.Flags().StringVar(&snapshotPath, "snapshot-path", "", "file to persist the tool registry to for fast startup")
+	rootCmd.Flags().StringVar(&usagePath, "usage-path", "", "file to persist tool usage and API key quota usage to across restarts")
+	rootCmd.Flags().StringVar(&docsAssetsURL, "docs-assets-url", "", "npm CDN the Swagger UI and Redoc pages of /docs load their assets from (default https://cdn.jsdelivr.net/npm)")
+	rootCmd.Flags().BoolVar(&gatewayMode, "gateway", false, "serve search_operations and call_api tools instead of one tool per operation")
 	
 	// Format filtering
 	rootCmd.Flags().StringSliceVar(&ignoreFormats, "ignore-formats", []string{}, "comma-separated list of formats to ignore (e.g., xml,yaml)")
+	rootCmd.Flags().StringSliceVar(&disabledTools, "disabled-tools", []string{}, "tool names or glob patterns of tools not to generate")
+	rootCmd.Flags().StringVar(&unsupportedAuth, "unsupported-auth", "", "what to do with endpoints whose security the configured auth cannot meet (include, mark, skip)")
 	rootCmd.Flags().StringVar(&preferFormat, "prefer-format", "", "preferred format when multiple formats exist (e.g., json, xml)")
+	rootCmd.Flags().BoolVar(&omitFilterArg, "omit-filter-argument", false, "leave the _filter JMESPath response filter argument out of generated tools")
+	rootCmd.Flags().BoolVar(&lazySchemas, "lazy-schemas", false, "generate tool input schemas on first use instead of at startup")
 	
 	// Version flag
 	rootCmd.Flags().BoolVar(&showVersion, "version", false, "show version information and exit")
+	rootCmd.Flags().BoolVar(&checkMode, "check", false, "validate the configuration and generate the tools, then exit 0 or 1 without serving")
 }
 
 // runServer runs the server in MCP or SSE mode
@@ -126,7 +272,23 @@ func runServer(cmd *cobra.Command, args []string) error {
 		fmt.Printf("swagger-docs-mcp %s\n", version.GetVersionWithBuildInfo())
 		return nil
 	}
+
+	if checkMode {
+		return runCheck(cmd)
+	}
 	
+	// Serve until stopped, starting again whenever a remote config file changes
+	for {
+		reloaded, err := serveConfiguration(cmd)
+		if err != nil || !reloaded {
+			return err
+		}
+	}
+}
+
+// serveConfiguration loads the configuration and runs the servers it selects until they
+// stop, reporting whether they stopped to restart with a changed remote config
+func serveConfiguration(cmd *cobra.Command) (bool, error) {
 	// Create configuration manager
 	configManager := config.NewManager()
 
@@ -137,15 +299,16 @@ func runServer(cmd *cobra.Command, args []string) error {
 	var resolvedConfig *types.ResolvedConfig
 	var err error
 
-	if configFile != "" {
-		resolvedConfig, err = configManager.LoadFromFile(configFile, overrides)
+	if len(configFiles) > 0 {
+		resolvedConfig, err = configManager.LoadFromFiles(configFiles, overrides)
 	} else {
 		resolvedConfig, err = configManager.Load(overrides)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return false, fmt.Errorf("failed to load configuration: %w", err)
 	}
+	applyFlagSwitches(cmd, resolvedConfig)
 
 	// Create logger
 	logger := utils.NewLogger(resolvedConfig.Logging)
@@ -160,12 +323,29 @@ func runServer(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	// A single transport runs as if selected by its flag. The flags are left as they
+	// are, as a restart with a changed remote config may select other transports.
+	serverTransports := resolvedConfig.Server.Transports
+	useSSE, useMCPHTTP := sseMode, mcpHTTPMode
+	if len(serverTransports) == 1 {
+		useSSE = serverTransports[0] == types.TransportSSE
+		useMCPHTTP = serverTransports[0] == types.TransportMCPHTTP
+		serverTransports = nil
+	}
+
 	serverMode := "MCP"
-	if sseMode {
+	if useSSE {
 		serverMode = "SSE"
-	} else if mcpHTTPMode {
+	} else if useMCPHTTP {
 		serverMode = "MCP-HTTP"
 	}
+	if len(serverTransports) > 0 {
+		modes := make([]string, len(serverTransports))
+		for i, transport := range serverTransports {
+			modes[i] = strings.ToUpper(string(transport))
+		}
+		serverMode = strings.Join(modes, "+")
+	}
 
 	logger.Info("Starting swagger-docs server",
 		zap.String("mode", serverMode),
@@ -176,27 +356,107 @@ func runServer(cmd *cobra.Command, args []string) error {
 		zap.Bool("debug", resolvedConfig.Debug),
 	)
 
+	if resolvedConfig.Mock.Enabled || len(resolvedConfig.Mock.Documents) > 0 {
+		logger.Info("Mock execution enabled; mocked tool calls will not reach the upstream API",
+			zap.Bool("allDocuments", resolvedConfig.Mock.Enabled),
+			zap.Strings("documents", resolvedConfig.Mock.Documents),
+		)
+	}
+	if resolvedConfig.Cassette.Dir != "" {
+		logger.Info("Recording and replaying tool calls",
+			zap.String("dir", resolvedConfig.Cassette.Dir),
+			zap.String("mode", string(resolvedConfig.Cassette.Mode)),
+		)
+	}
+
 	// Set up signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if resolvedConfig.Update.Check {
+		go update.LogNewerRelease(ctx, update.NewClient(resolvedConfig.Update.Repository, resolvedConfig.Update.APIURL), logger)
+	}
+
+	// Restart with the new configuration when a remote config file changes. The stdio
+	// transport cannot restart without losing its client's session.
+	var reloaded atomic.Bool
+	if resolvedConfig.ConfigRefresh > 0 && configManager.HasRemoteConfig() {
+		stdioMode := len(serverTransports) == 0 && !useSSE && !useMCPHTTP
+		for _, transport := range serverTransports {
+			stdioMode = stdioMode || transport == types.TransportStdio
+		}
+		if stdioMode {
+			logger.Warn("Ignoring --config-refresh: the stdio transport cannot restart with a new configuration")
+		} else {
+			go watchRemoteConfig(ctx, configManager, overrides, resolvedConfig.ConfigRefresh, logger, func() {
+				reloaded.Store(true)
+				cancel()
+			})
+		}
+	}
+
 	// Create appropriate server based on mode
-	if sseMode {
-		return runSSEServer(ctx, resolvedConfig, logger)
-	} else if mcpHTTPMode {
-		return runMCPHTTPServer(ctx, resolvedConfig, logger)
+	if len(serverTransports) > 0 {
+		err = runTransports(ctx, resolvedConfig, logger, serverTransports)
+	} else if useSSE {
+		err = runSSEServer(ctx, resolvedConfig, logger)
+	} else if useMCPHTTP {
+		err = runMCPHTTPServer(ctx, resolvedConfig, logger)
 	} else {
-		return runMCPServer(ctx, resolvedConfig, logger)
+		err = runMCPServer(ctx, resolvedConfig, logger)
+	}
+	return reloaded.Load(), err
+}
+
+// watchRemoteConfig fetches the remote config files again every interval, and calls
+// restart once they change to a configuration that loads and verifies
+func watchRemoteConfig(ctx context.Context, configManager *config.Manager, overrides *types.ResolvedConfig, interval time.Duration, logger *utils.Logger, restart func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// A change that fails to load is checked again on every tick, e.g. until its
+	// checksum file catches up, but only logged when the failure changes
+	lastFailure := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := configManager.RemoteConfigChanged()
+		if err != nil {
+			logger.Warn("Failed to check remote config files for changes", zap.Error(err))
+		}
+		if !changed {
+			continue
+		}
+
+		// Keep serving the current configuration rather than restart into a broken one
+		if _, err := config.NewManager().LoadFromFiles(configFiles, overrides); err != nil {
+			if err.Error() != lastFailure {
+				logger.Error("Ignoring changed remote config", zap.Error(err))
+				lastFailure = err.Error()
+			}
+			continue
+		}
+		logger.Info("Remote config changed, restarting with the new configuration")
+		restart()
+		return
 	}
 }
 
 // runSSEServer runs the SSE server
 func runSSEServer(ctx context.Context, config *types.ResolvedConfig, logger *utils.Logger) error {
-	sseServer := sse.NewSSEServer(config, logger)
+	shared := server.NewSharedState(config, logger)
+	defer shared.Close()
+	sseServer := sse.NewSharedSSEServer(config, logger, shared)
+	go server.NewTelemetry(config, logger).Run(ctx, []types.Transport{types.TransportSSE}, shared.Ready(), shared.TelemetryCounts)
 	
 	// Set up signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	// Start server in goroutine
 	serverErr := make(chan error, 1)
@@ -209,6 +469,8 @@ func runSSEServer(ctx context.Context, config *types.ResolvedConfig, logger *uti
 	case sig := <-sigChan:
 		logger.Info("Received signal, shutting down SSE server...", zap.String("signal", sig.String()))
 		sseServer.Stop()
+		// Wait for the listener to close, which removes a Unix domain socket
+		<-serverErr
 	case err := <-serverErr:
 		if err != nil {
 			return fmt.Errorf("SSE server error: %w", err)
@@ -221,11 +483,15 @@ func runSSEServer(ctx context.Context, config *types.ResolvedConfig, logger *uti
 
 // runMCPServer runs the original MCP server (stdio)
 func runMCPServer(ctx context.Context, config *types.ResolvedConfig, logger *utils.Logger) error {
-	mcpServer := server.NewMCPServer(config, logger)
+	shared := server.NewSharedState(config, logger)
+	defer shared.Close()
+	mcpServer := server.NewSharedMCPServer(config, logger, shared, true)
+	go server.NewTelemetry(config, logger).Run(ctx, []types.Transport{types.TransportStdio}, shared.Ready(), shared.TelemetryCounts)
 	
 	// Set up signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	// Start server in goroutine
 	serverErr := make(chan error, 1)
@@ -254,6 +520,7 @@ func runMCPHTTPServer(ctx context.Context, config *types.ResolvedConfig, logger
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
 	}
+	defer mcpServer.Close()
 
 	// Initialize tools from swagger documents
 	err = initializeSimpleMCPTools(mcpServer, config, logger)
@@ -261,12 +528,18 @@ func runMCPHTTPServer(ctx context.Context, config *types.ResolvedConfig, logger
 		return fmt.Errorf("failed to initialize MCP tools: %w", err)
 	}
 
+	// The tools are loaded by now
+	ready := make(chan struct{})
+	close(ready)
+	go server.NewTelemetry(config, logger).Run(ctx, []types.Transport{types.TransportMCPHTTP}, ready, mcpServer.TelemetryCounts)
+
 	// Set up signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	// Start HTTP server
-	addr := fmt.Sprintf(":%d", config.Server.Port)
+	addr := server.ListenAddress(config.Server.Listen, config.Server.Port)
 	
 	// Start server in goroutine
 	serverErr := make(chan error, 1)
@@ -289,12 +562,128 @@ func runMCPHTTPServer(ctx context.Context, config *types.ResolvedConfig, logger
 	return nil
 }
 
+// runTransports runs servers for several transports side by side, sharing one scan of
+// the documents. The SSE server loads the documents if it runs, since it also generates
+// prompts; otherwise the stdio server loads them without waiting for its client.
+func runTransports(ctx context.Context, config *types.ResolvedConfig, logger *utils.Logger, transports []types.Transport) error {
+	enabled := make(map[types.Transport]bool)
+	for _, transport := range transports {
+		enabled[transport] = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	shared := server.NewSharedState(config, logger)
+	defer shared.Close()
+	go server.NewTelemetry(config, logger).Run(ctx, transports, shared.Ready(), shared.TelemetryCounts)
+	serverErr := make(chan error, len(transports))
+	running := 0
+
+	var sseServer *sse.SSEServer
+	if enabled[types.TransportSSE] {
+		sseServer = sse.NewSharedSSEServer(config, logger, shared)
+		running++
+		go func() {
+			if err := sseServer.Start(ctx); err != nil {
+				serverErr <- fmt.Errorf("SSE server error: %w", err)
+				return
+			}
+			serverErr <- nil
+		}()
+	}
+
+	var stdioServer *server.MCPServer
+	if enabled[types.TransportStdio] {
+		stdioServer = server.NewSharedMCPServer(config, logger, shared, sseServer == nil)
+		running++
+		go func() {
+			if err := stdioServer.Start(ctx); err != nil {
+				serverErr <- fmt.Errorf("MCP server error: %w", err)
+				return
+			}
+			serverErr <- nil
+		}()
+		go stdioServer.LoadDocuments()
+	}
+
+	if enabled[types.TransportMCPHTTP] {
+		httpServer, err := mcp.NewSharedSimpleMCPServer(config, logger, shared)
+		if err != nil {
+			return fmt.Errorf("failed to create MCP server: %w", err)
+		}
+
+		// Served alone, the MCP HTTP transport takes the address of --listen or --port
+		addr := server.ListenAddress(config.Server.Listen, config.Server.Port)
+		if sseServer != nil {
+			mcpPort := config.Server.MCPHTTPPort
+			if mcpPort == 0 {
+				mcpPort = config.Server.Port + 1
+			}
+			addr = server.ListenAddress(config.Server.MCPHTTPListen, mcpPort)
+		}
+
+		running++
+		go func() {
+			select {
+			case <-shared.Ready():
+			case <-ctx.Done():
+				serverErr <- nil
+				return
+			}
+			if err := httpServer.AddSharedTools(); err != nil {
+				serverErr <- fmt.Errorf("failed to initialize MCP tools: %w", err)
+				return
+			}
+			if err := httpServer.StartHTTP(ctx, addr); err != nil {
+				serverErr <- fmt.Errorf("MCP HTTP server error: %w", err)
+				return
+			}
+			serverErr <- nil
+		}()
+	}
+
+	// Set up signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// The first server to stop, e.g. stdio when the client goes away, stops all of them
+	var err error
+	select {
+	case sig := <-sigChan:
+		logger.Info("Received signal, shutting down servers...", zap.String("signal", sig.String()))
+	case err = <-serverErr:
+	}
+
+	if sseServer != nil {
+		sseServer.Stop()
+	}
+	if stdioServer != nil {
+		stdioServer.Stop()
+	}
+
+	// A restart waits for every server to release its port
+	if ctx.Err() != nil {
+		for ; running > 1; running-- {
+			<-serverErr
+		}
+	}
+
+	logger.Info("Server shutdown complete")
+	return err
+}
+
 // initializeSimpleMCPTools scans swagger documents and registers them as MCP tools
 func initializeSimpleMCPTools(mcpServer *mcp.SimpleMCPServer, config *types.ResolvedConfig, logger *utils.Logger) error {
 	// Import swagger scanning and generation logic
+	hosts := httpclient.NewHostPolicy(config)
 	scanner := swagger.NewScanner(logger)
-	parser := swagger.NewParser(logger)
+	scanner.SetHostPolicy(hosts)
+	parser := swagger.NewParserWithConfig(logger, &config.SwaggerProcessing)
+	parser.SetHostPolicy(hosts)
 	generator := swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration)
+	generator.SetAuth(&config.Auth)
 
 	// Scan swagger documents
 	scanResult, err := scanner.ScanPaths(config.SwaggerPaths, types.DefaultScanOptions())
@@ -317,9 +706,14 @@ func initializeSimpleMCPTools(mcpServer *mcp.SimpleMCPServer, config *types.Reso
 			logger.Error("Failed to parse swagger document", 
 				zap.String("filePath", docInfo.FilePath),
 				zap.Error(err))
+			if !config.SwaggerProcessing.IgnoreErrors {
+				return fmt.Errorf("failed to parse swagger document %s: %w", docInfo.FilePath, err)
+			}
 			continue
 		}
 
+		mcpServer.AddSwaggerDocument(&docInfo, swaggerDoc)
+
 		// Generate tools from swagger document
 		tools, err := generator.GenerateToolsFromDocument(swaggerDoc, &docInfo)
 		if err != nil {
@@ -342,6 +736,10 @@ func initializeSimpleMCPTools(mcpServer *mcp.SimpleMCPServer, config *types.Reso
 		}
 	}
 
+	if err := mcpServer.AddCompositeTools(); err != nil {
+		return err
+	}
+
 	logger.Info("MCP tool initialization complete",
 		zap.Int("documentsProcessed", len(scanResult.Documents)),
 		zap.Int("toolsRegistered", toolCount))
@@ -349,9 +747,48 @@ func initializeSimpleMCPTools(mcpServer *mcp.SimpleMCPServer, config *types.Reso
 	return nil
 }
 
+// applyFlagSwitches applies explicitly set boolean CLI flags to the resolved config.
+// Overrides are merged by non-zero value, so a flag set to false is applied here instead.
+func applyFlagSwitches(cmd *cobra.Command, config *types.ResolvedConfig) {
+	if cmd.Flags().Changed("validate-documents") {
+		config.SwaggerProcessing.ValidateDocuments = validateDocuments
+	}
+	if cmd.Flags().Changed("resolve-references") {
+		config.SwaggerProcessing.ResolveReferences = resolveReferences
+	}
+	if cmd.Flags().Changed("ignore-errors") {
+		config.SwaggerProcessing.IgnoreErrors = ignoreErrors
+	}
+	if cmd.Flags().Changed("watch-files") {
+		config.SwaggerProcessing.WatchFiles = watchFiles
+	}
+	if cmd.Flags().Changed("omit-filter-argument") {
+		config.ToolGeneration.OmitFilterArgument = omitFilterArg
+	}
+	if cmd.Flags().Changed("lazy-schemas") {
+		config.ToolGeneration.LazySchemas = lazySchemas
+	}
+	if cmd.Flags().Changed("mock") {
+		config.Mock.Enabled = mockMode
+	}
+	if cmd.Flags().Changed("validate-responses") {
+		config.ResponseValidation.Enabled = validateResponses
+	}
+	if cmd.Flags().Changed("gateway") {
+		config.Server.Gateway = gatewayMode
+	}
+	if cmd.Flags().Changed("check-for-updates") {
+		config.Update.Check = checkForUpdates
+	}
+}
+
 // buildConfigOverrides builds configuration overrides from CLI flags
 func buildConfigOverrides(cmd *cobra.Command) *types.ResolvedConfig {
-	overrides := &types.ResolvedConfig{}
+	overrides := &types.ResolvedConfig{
+		Profile:         profile,
+		ConfigChecksums: configChecksums,
+		ConfigRefresh:   configRefresh,
+	}
 
 	// Combine swagger paths from both flags
 	allSwaggerPaths := append(swaggerPaths, swaggerPath...)
@@ -395,6 +832,9 @@ func buildConfigOverrides(cmd *cobra.Command) *types.ResolvedConfig {
 		overrides.Logging.Level = logLevel
 		overrides.Logging.Enabled = true
 	}
+	if egressSampleRate > 0 {
+		overrides.Logging.EgressSampleRate = egressSampleRate
+	}
 
 	// Server configuration
 	if timeout > 0 {
@@ -403,19 +843,155 @@ func buildConfigOverrides(cmd *cobra.Command) *types.ResolvedConfig {
 	if maxTools > 0 {
 		overrides.Server.MaxTools = maxTools
 	}
-	if port > 0 {
+	if len(toolPriority) > 0 {
+		overrides.Server.ToolPriority = toolPriority
+	}
+	if len(eventSensitive) > 0 {
+		overrides.Server.Events.SensitiveFields = eventSensitive
+	}
+	if eventMaxResult > 0 {
+		overrides.Server.Events.MaxResultSize = eventMaxResult
+	}
+	if eventLogPath != "" {
+		overrides.Server.Events.Log.Path = eventLogPath
+	}
+	if eventLogMaxAge > 0 {
+		overrides.Server.Events.Log.MaxAge = eventLogMaxAge
+	}
+	if quotaPerMinute > 0 {
+		overrides.Server.Quotas.PerMinute = quotaPerMinute
+	}
+	if quotaPerDay > 0 {
+		overrides.Server.Quotas.PerDay = quotaPerDay
+	}
+	if busType != "" {
+		overrides.Server.Bus.Type = busType
+	}
+	if len(busBrokers) > 0 {
+		overrides.Server.Bus.Brokers = busBrokers
+	}
+	if busTopic != "" {
+		overrides.Server.Bus.Topic = busTopic
+	}
+	if sharedRegistryURL != "" {
+		overrides.Server.SharedRegistry.URL = sharedRegistryURL
+	}
+	if refreshLeaderOnly {
+		overrides.Server.SharedRegistry.RefreshLeaderOnly = true
+	}
+	for _, webhookURL := range webhookURLs {
+		overrides.Server.Webhooks = append(overrides.Server.Webhooks, types.WebhookConfig{URL: webhookURL})
+	}
+	// The default port must not replace one from the config file
+	if port > 0 && cmd.Flags().Changed("port") {
 		overrides.Server.Port = port
 	}
+	if len(mcpAuthTokens) > 0 {
+		overrides.Server.Auth.BearerTokens = mcpAuthTokens
+	}
+	if mcpOIDCIssuer != "" {
+		overrides.Server.Auth.OIDCIssuer = mcpOIDCIssuer
+	}
+	if mcpOIDCAudience != "" {
+		overrides.Server.Auth.OIDCAudience = mcpOIDCAudience
+	}
+	if snapshotPath != "" {
+		overrides.Server.SnapshotPath = snapshotPath
+	}
+	if usagePath != "" {
+		overrides.Server.UsagePath = usagePath
+	}
+	if docsAssetsURL != "" {
+		overrides.Server.DocsAssetsURL = docsAssetsURL
+	}
+	for _, transport := range transports {
+		overrides.Server.Transports = append(overrides.Server.Transports, types.Transport(transport))
+	}
+	if mcpHTTPPort > 0 {
+		overrides.Server.MCPHTTPPort = mcpHTTPPort
+	}
+	if listenAddress != "" {
+		overrides.Server.Listen = listenAddress
+	}
+	if maxMessageSize > 0 {
+		overrides.Server.MaxMessageSize = maxMessageSize
+	}
+	if blobMaxInline > 0 {
+		overrides.Server.Blobs.MaxInlineSize = blobMaxInline
+	}
+	if blobMaxInlineText > 0 {
+		overrides.Server.Blobs.MaxInlineTextSize = blobMaxInlineText
+	}
+	if blobTTL > 0 {
+		overrides.Server.Blobs.TTL = blobTTL
+	}
+	if socketMode != "" {
+		overrides.Server.SocketMode = socketMode
+	}
+	if mcpHTTPListen != "" {
+		overrides.Server.MCPHTTPListen = mcpHTTPListen
+	}
 
-	// Swagger processing
-	if cmd.Flags().Changed("validate-documents") {
-		overrides.SwaggerProcessing.ValidateDocuments = validateDocuments
+	// Swagger processing (boolean switches are applied by applyFlagSwitches)
+	if refreshInterval > 0 {
+		overrides.SwaggerProcessing.RefreshInterval = refreshInterval
 	}
-	if cmd.Flags().Changed("resolve-references") {
-		overrides.SwaggerProcessing.ResolveReferences = resolveReferences
+	if staleGracePeriod > 0 {
+		overrides.SwaggerProcessing.StaleGracePeriod = staleGracePeriod
 	}
-	if cmd.Flags().Changed("ignore-errors") {
-		overrides.SwaggerProcessing.IgnoreErrors = ignoreErrors
+	if len(allowedRefHosts) > 0 {
+		overrides.SwaggerProcessing.AllowedRefHosts = allowedRefHosts
+	}
+
+	// Mock execution (--mock is applied by applyFlagSwitches)
+	if len(mockDocuments) > 0 {
+		overrides.Mock.Documents = mockDocuments
+	}
+
+	// Record/replay cassettes
+	if cassetteDir != "" {
+		overrides.Cassette.Dir = cassetteDir
+	}
+	if cassetteMode != "" {
+		overrides.Cassette.Mode = types.CassetteMode(cassetteMode)
+	}
+
+	// Pagination
+	if maxPages > 0 {
+		overrides.Pagination.MaxPages = maxPages
+	}
+
+	// Tool execution
+	if maxResponseChars > 0 {
+		overrides.ToolExecution.MaxResponseChars = maxResponseChars
+	}
+
+	// Telemetry
+	if telemetryMode != "" {
+		overrides.Telemetry.Mode = types.TelemetryMode(telemetryMode)
+	}
+	if telemetryEndpoint != "" {
+		overrides.Telemetry.Endpoint = telemetryEndpoint
+	}
+
+	// Update check
+	if updateRepository != "" {
+		overrides.Update.Repository = updateRepository
+	}
+
+	// Plugins
+	for _, spec := range pluginSpecs {
+		overrides.Plugins = append(overrides.Plugins, types.ParsePluginSpec(spec))
+	}
+
+	// GraphQL configuration
+	if graphQLEndpoint != "" {
+		overrides.GraphQL.Endpoint = graphQLEndpoint
+	}
+
+	// gRPC configuration
+	if grpcGateway != "" {
+		overrides.GRPC.GatewayURL = grpcGateway
 	}
 
 	// HTTP configuration
@@ -425,6 +1001,24 @@ func buildConfigOverrides(cmd *cobra.Command) *types.ResolvedConfig {
 	if retries >= 0 {
 		overrides.HTTP.Retries = retries
 	}
+	if hedgeDelay > 0 {
+		overrides.HTTP.HedgeDelay = hedgeDelay
+	}
+	if len(allowedHosts) > 0 {
+		overrides.HTTP.AllowedHosts = allowedHosts
+	}
+	if maxConcurrent > 0 {
+		overrides.HTTP.Concurrency.MaxRequests = maxConcurrent
+	}
+	if maxPerHost > 0 {
+		overrides.HTTP.Concurrency.MaxPerHost = maxPerHost
+	}
+	if requestQueueSize > 0 {
+		overrides.HTTP.Concurrency.QueueSize = requestQueueSize
+	}
+	if len(deniedHosts) > 0 {
+		overrides.HTTP.DeniedHosts = deniedHosts
+	}
 
 	// Format filtering
 	if len(ignoreFormats) > 0 {
@@ -433,6 +1027,12 @@ func buildConfigOverrides(cmd *cobra.Command) *types.ResolvedConfig {
 	if preferFormat != "" {
 		overrides.ToolGeneration.PreferFormat = preferFormat
 	}
+	if len(disabledTools) > 0 {
+		overrides.ToolGeneration.DisabledTools = disabledTools
+	}
+	if unsupportedAuth != "" {
+		overrides.ToolGeneration.UnsupportedAuth = types.UnsupportedAuthPolicy(unsupportedAuth)
+	}
 
 	return overrides
 }
@@ -453,6 +1053,9 @@ commit hash, Go version, and build user.`,
 	},
 }
 
+// configFormat is the output format of the config command
+var configFormat string
+
 // configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
@@ -464,8 +1067,8 @@ var configCmd = &cobra.Command{
 		var resolvedConfig *types.ResolvedConfig
 		var err error
 
-		if configFile != "" {
-			resolvedConfig, err = configManager.LoadFromFile(configFile, overrides)
+		if len(configFiles) > 0 {
+			resolvedConfig, err = configManager.LoadFromFiles(configFiles, overrides)
 		} else {
 			resolvedConfig, err = configManager.Load(overrides)
 		}
@@ -473,11 +1076,28 @@ var configCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		applyFlagSwitches(cmd, resolvedConfig)
+
+		switch configFormat {
+		case "json", "yaml":
+			encoded, err := encodeResolvedConfig(config.Redact(resolvedConfig), configFormat)
+			if err != nil {
+				return fmt.Errorf("failed to encode configuration: %w", err)
+			}
+			fmt.Print(string(encoded))
+			return nil
+		case "text":
+		default:
+			return fmt.Errorf("unknown format %q: use text, json, or yaml", configFormat)
+		}
 
 		// Print configuration
 		fmt.Printf("Configuration:\n")
 		fmt.Printf("  Name: %s\n", resolvedConfig.Name)
 		fmt.Printf("  Version: %s\n", resolvedConfig.Version)
+		if resolvedConfig.Profile != "" {
+			fmt.Printf("  Profile: %s\n", resolvedConfig.Profile)
+		}
 		fmt.Printf("  Debug: %t\n", resolvedConfig.Debug)
 		fmt.Printf("  Log Level: %s\n", resolvedConfig.Logging.Level)
 		fmt.Printf("  Swagger Paths: %s\n", strings.Join(resolvedConfig.SwaggerPaths, ", "))
@@ -525,13 +1145,90 @@ var configCmd = &cobra.Command{
 	},
 }
 
+// encodeResolvedConfig encodes a configuration as indented JSON, or as YAML with the
+// same keys. Durations are in nanoseconds, as time.Duration encodes to JSON.
+func encodeResolvedConfig(resolvedConfig *types.ResolvedConfig, format string) ([]byte, error) {
+	encoded, err := json.MarshalIndent(resolvedConfig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return append(encoded, '\n'), nil
+	}
+
+	// Convert through JSON so that YAML follows the json tags of ResolvedConfig
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(decodeJSONNumbers(document))
+}
+
+// decodeJSONNumbers replaces the json.Numbers under a decoded JSON value with integers,
+// or floats when they have a fraction, which YAML writes unquoted
+func decodeJSONNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		}
+		float, _ := typed.Float64()
+		return float
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = decodeJSONNumbers(item)
+		}
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = decodeJSONNumbers(item)
+		}
+	}
+	return value
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(probeCmd)
+	rootCmd.AddCommand(telemetryCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
 
 	// Add flags to version command
 	versionCmd.Flags().BoolP("detailed", "d", false, "show detailed version information")
 
 	// Add global flags to config command
 	configCmd.Flags().AddFlagSet(rootCmd.Flags())
+	configCmd.Flags().StringVar(&configFormat, "format", "text", "output format: text, or json or yaml with secrets redacted")
+	configCmd.AddCommand(configInitCmd)
+
+	// Add the server flags to config init command, whose settings it writes
+	configInitCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to install command, to be passed on to the installed server
+	installCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to exec command, which loads the same tools
+	execCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to doctor command, so that it checks what the server would load
+	doctorCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to diff command, to generate tools the way the server does
+	diffCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to stats command, to scan the documents the server would
+	statsCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to probe command, to call the API the way the server does
+	probeCmd.Flags().AddFlagSet(rootCmd.Flags())
+
+	// Add the server flags to telemetry command, to show what the server would report
+	telemetryCmd.Flags().AddFlagSet(rootCmd.Flags())
 }
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/update"
+	"swagger-docs-mcp/pkg/version"
+)
+
+// Flags of the self-update command
+var (
+	selfUpdateCheck      bool
+	selfUpdateTo         string
+	selfUpdateFile       string
+	selfUpdateChecksum   string
+	selfUpdateRepository string
+	selfUpdateAPIURL     string
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Check for a newer release and replace this binary with it",
+	Long: `Look up the latest release on GitHub, or the one given with --to, and replace this
+binary with the release binary for this platform once its SHA-256 checksum matches the
+one published with the release. --check only reports whether a newer release exists.
+
+For hosts without access to GitHub, download the binary and the checksums file of a
+release elsewhere and install them with --file and --checksum; nothing is fetched.`,
+	Example: `  swagger-docs-mcp self-update --check
+  swagger-docs-mcp self-update
+  swagger-docs-mcp self-update --to v1.4.0
+  swagger-docs-mcp self-update --file ./swagger-docs-mcp-linux-amd64 --checksum ./checksums.txt`,
+	SilenceUsage: true,
+	RunE:         runSelfUpdate,
+}
+
+// runSelfUpdate checks for a newer release, or installs one from GitHub or a local file
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the swagger-docs-mcp binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	if selfUpdateFile != "" {
+		return installLocalBinary(executable)
+	}
+	if selfUpdateChecksum != "" {
+		return fmt.Errorf("--checksum is only used with --file; releases are checked against their published checksums")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client := update.NewClient(firstNonEmpty(selfUpdateRepository, os.Getenv("WX_MCP_UPDATE_REPOSITORY")),
+		firstNonEmpty(selfUpdateAPIURL, os.Getenv("WX_MCP_UPDATE_API_URL")))
+	var release *update.Release
+	if selfUpdateTo != "" {
+		release, err = client.Release(ctx, selfUpdateTo)
+	} else {
+		release, err = client.Latest(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	current := version.GetSemanticVersion()
+	name := update.CurrentAssetName()
+	newer := update.Newer(release.Version(), current)
+
+	if selfUpdateCheck {
+		fmt.Printf("Current version: %s\n", current)
+		fmt.Printf("Latest version:  %s (%s)\n", release.Version(), release.HTMLURL)
+		if !newer {
+			fmt.Println("swagger-docs-mcp is up to date")
+			return nil
+		}
+		fmt.Println("A newer version is available. Run swagger-docs-mcp self-update to install it, or")
+		fmt.Printf("download %s and checksums.txt from the release and run:\n", name)
+		fmt.Printf("  swagger-docs-mcp self-update --file %s --checksum checksums.txt\n", name)
+		return nil
+	}
+
+	// An explicit version is installed even if it is not newer, e.g. to roll back
+	if !newer && selfUpdateTo == "" {
+		fmt.Printf("swagger-docs-mcp %s is up to date\n", current)
+		return nil
+	}
+
+	asset := release.Asset(name)
+	if asset == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksum, err := client.Checksum(ctx, release, name)
+	if err != nil {
+		return fmt.Errorf("refusing to install an unverified binary: %w", err)
+	}
+
+	fmt.Printf("Downloading %s %s...\n", name, release.TagName)
+	data, err := client.Download(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if err := update.Verify(data, checksum); err != nil {
+		return fmt.Errorf("refusing to install %s: %w", name, err)
+	}
+	if err := update.Replace(executable, data); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated %s from %s to %s (SHA-256 verified). Restart running servers to use it.\n",
+		executable, current, release.Version())
+	return nil
+}
+
+// installLocalBinary replaces this binary with the one given with --file, verified
+// against the checksum given with --checksum, without any network access
+func installLocalBinary(executable string) error {
+	if selfUpdateChecksum == "" {
+		return fmt.Errorf("--checksum is required with --file: the SHA-256 checksum of the binary, or a checksums file listing it")
+	}
+
+	data, err := os.ReadFile(selfUpdateFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", selfUpdateFile, err)
+	}
+
+	// The checksum is given directly, or in a file listing it under the binary's name
+	checksum := selfUpdateChecksum
+	if content, err := os.ReadFile(selfUpdateChecksum); err == nil {
+		if checksum, err = update.ParseChecksum(string(content), filepath.Base(selfUpdateFile)); err != nil {
+			return fmt.Errorf("%s: %w", selfUpdateChecksum, err)
+		}
+	}
+	if err := update.Verify(data, checksum); err != nil {
+		return fmt.Errorf("refusing to install %s: %w", selfUpdateFile, err)
+	}
+	if err := update.Replace(executable, data); err != nil {
+		return err
+	}
+
+	fmt.Printf("Replaced %s with %s (SHA-256 verified). Restart running servers to use it.\n", executable, selfUpdateFile)
+	return nil
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func init() {
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "only report whether a newer release is available")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateTo, "to", "", "release tag to install instead of the latest, e.g. v1.4.0")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateFile, "file", "", "install this downloaded binary instead of fetching one")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateChecksum, "checksum", "", "SHA-256 checksum of --file, or a checksums file listing it")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateRepository, "repository", "", "GitHub repository (owner/name) releases are looked up in (default "+update.DefaultRepository+")")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateAPIURL, "api-url", "", "GitHub API releases are read from (default "+update.DefaultAPIURL+")")
+}
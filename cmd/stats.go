@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	httpclient "swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+const (
+	// skipReasonMaxTools skips the tools of the lowest priority beyond the maximum
+	// number of tools
+	skipReasonMaxTools = "max-tools"
+	// skipReasonNameConflict skips tools named like a tool of an earlier document
+	skipReasonNameConflict = "name-conflict"
+)
+
+// statsJSON is the stats command flag printing the statistics as JSON
+var statsJSON bool
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the documents scanned and the tools, prompts, and resources generated",
+	Long: `Scan the swagger documents as the server would and print the documents found,
+their endpoints, the tools, prompts, and resources generated from them, and the
+endpoints no tool was generated for by reason, in total and by document.`,
+	SilenceUsage: true,
+	RunE:         runStats,
+}
+
+// generationStats summarizes a scan of the documents and the generation from them
+type generationStats struct {
+	Files             int                    `json:"files"`
+	ValidDocuments    int                    `json:"validDocuments"`
+	ScanErrors        int                    `json:"scanErrors"`
+	Errors            []types.ScanError      `json:"errors,omitempty"`
+	FilteredDocuments int                    `json:"filteredDocuments"`
+	Endpoints         int                    `json:"endpoints"`
+	Tools             int                    `json:"tools"`
+	Prompts           int                    `json:"prompts"`
+	Resources         int                    `json:"resources"`
+	Skipped           map[string]int         `json:"skipped"`
+	ToolStatistics    map[string]interface{} `json:"toolStatistics"`
+	Documents         []*documentStats       `json:"documents"`
+}
+
+// documentStats summarizes the generation from one document
+type documentStats struct {
+	Title     string         `json:"title"`
+	Path      string         `json:"path"`
+	Version   string         `json:"version,omitempty"`
+	Endpoints int            `json:"endpoints"`
+	Tools     int            `json:"tools"`
+	Prompts   int            `json:"prompts"`
+	Resources int            `json:"resources"`
+	Skipped   map[string]int `json:"skipped,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// runStats scans the documents and prints the statistics of the generation from them
+func runStats(cmd *cobra.Command, args []string) error {
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		return err
+	}
+	// The statistics are printed instead of logs
+	resolvedConfig.Logging.Enabled = false
+	logger := utils.NewLogger(resolvedConfig.Logging)
+
+	stats, err := collectGenerationStats(resolvedConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	if statsJSON {
+		encoded, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode statistics: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+	printGenerationStats(stats)
+	return nil
+}
+
+// collectGenerationStats scans, filters, and generates from the documents the way the
+// server does, counting what is generated and skipped
+func collectGenerationStats(config *types.ResolvedConfig, logger *utils.Logger) (*generationStats, error) {
+	scanner := swagger.NewScanner(logger)
+	scanner.SetHostPolicy(httpclient.NewHostPolicy(config))
+	parser := swagger.NewParserWithConfig(logger, &config.SwaggerProcessing)
+	parser.SetHostPolicy(httpclient.NewHostPolicy(config))
+	generator := swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration)
+	generator.SetAuth(&config.Auth)
+	promptGenerator := swagger.NewPromptGenerator(logger, &config.Prompts)
+	resourceGenerator := swagger.NewResourceGenerator(logger, &config.Resources)
+	promptGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+	resourceGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+	if config.Prompts.Enabled {
+		if err := promptGenerator.LoadTemplates(); err != nil {
+			return nil, err
+		}
+	}
+
+	scanResult, err := scanner.ScanPathsAndURLs(config.SwaggerPaths, config.SwaggerURLs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan swagger documents: %w", err)
+	}
+
+	documents := scanResult.Documents
+	if len(config.PackageIDs) > 0 {
+		documents = scanner.FilterDocumentsByPackageIDs(documents, config.PackageIDs)
+	}
+	if config.TWCFilters != nil {
+		documents = scanner.FilterDocumentsByTWCFilters(documents, config.TWCFilters)
+	}
+	if len(config.DynamicFilters) > 0 {
+		documents = scanner.FilterDocumentsByDynamicFilters(documents, config.DynamicFilters)
+	}
+
+	stats := &generationStats{
+		Files:             scanResult.Stats.TotalFiles,
+		ValidDocuments:    scanResult.Stats.ValidDocuments,
+		ScanErrors:        scanResult.Stats.Errors,
+		Errors:            scanResult.Errors,
+		FilteredDocuments: len(documents),
+		Skipped:           make(map[string]int),
+	}
+
+	var current *documentStats
+	generator.OnSkip(func(endpoint *types.SwaggerEndpoint, reason swagger.SkipReason) {
+		current.skip(string(reason), 1)
+	})
+
+	var allTools []*types.GeneratedTool
+	toolNames := make(map[string]bool)
+	byPath := make(map[string]*documentStats)
+	for i := range documents {
+		docInfo := &documents[i]
+		current = &documentStats{
+			Title:   docInfo.Title,
+			Path:    docInfo.FilePath,
+			Version: docInfo.Version,
+			Skipped: make(map[string]int),
+		}
+		stats.Documents = append(stats.Documents, current)
+		byPath[docInfo.FilePath] = current
+
+		var document *types.SwaggerDocument
+		if docInfo.IsRemote && len(docInfo.Content) > 0 {
+			document, err = parser.ParseDocumentWithContent(docInfo)
+		} else {
+			document, err = parser.ParseDocument(docInfo.FilePath)
+		}
+		if err != nil {
+			current.Error = err.Error()
+			continue
+		}
+
+		if endpoints, err := parser.ExtractEndpoints(document); err == nil {
+			current.Endpoints = len(endpoints)
+		}
+
+		tools, err := generator.GenerateToolsFromDocument(document, docInfo)
+		if err != nil {
+			current.Error = err.Error()
+			continue
+		}
+		for _, tool := range tools {
+			if toolNames[tool.Name] {
+				current.skip(skipReasonNameConflict, 1)
+				continue
+			}
+			toolNames[tool.Name] = true
+			allTools = append(allTools, tool)
+			current.Tools++
+		}
+
+		if config.Prompts.Enabled {
+			if prompts, err := promptGenerator.GeneratePromptsFromDocument(document, docInfo); err == nil {
+				current.Prompts = len(prompts)
+			}
+		}
+		if config.Resources.Enabled {
+			if resources, err := resourceGenerator.GenerateResourcesFromDocument(document, docInfo); err == nil {
+				current.Resources = len(resources)
+			}
+		}
+	}
+
+	allTools, dropped := server.LimitTools(allTools, generator, config)
+	for _, tool := range dropped {
+		if document := byPath[tool.DocumentInfo.FilePath]; document != nil {
+			document.Tools--
+			document.skip(skipReasonMaxTools, 1)
+		}
+	}
+
+	for _, document := range stats.Documents {
+		stats.Endpoints += document.Endpoints
+		stats.Tools += document.Tools
+		stats.Prompts += document.Prompts
+		stats.Resources += document.Resources
+		for reason, count := range document.Skipped {
+			stats.Skipped[reason] += count
+		}
+	}
+	if config.Prompts.Enabled {
+		stats.Prompts += len(promptGenerator.TemplatePrompts())
+	}
+	stats.ToolStatistics = generator.GetToolStatistics(allTools)
+
+	return stats, nil
+}
+
+// skip counts endpoints of the document no tool was generated for
+func (d *documentStats) skip(reason string, count int) {
+	if count > 0 {
+		d.Skipped[reason] += count
+	}
+}
+
+// printGenerationStats prints statistics for reading
+func printGenerationStats(stats *generationStats) {
+	fmt.Printf("Documents:  %d files, %d valid, %d scan errors, %d after filters\n",
+		stats.Files, stats.ValidDocuments, stats.ScanErrors, stats.FilteredDocuments)
+	fmt.Printf("Endpoints:  %d\n", stats.Endpoints)
+	fmt.Printf("Tools:      %d\n", stats.Tools)
+	fmt.Printf("Prompts:    %d\n", stats.Prompts)
+	fmt.Printf("Resources:  %d\n", stats.Resources)
+	if len(stats.Skipped) > 0 {
+		fmt.Printf("Skipped:    %s\n", formatCounts(stats.Skipped))
+	}
+	if byMethod, ok := stats.ToolStatistics["toolsByMethod"].(map[string]int); ok && len(byMethod) > 0 {
+		fmt.Printf("By method:  %s\n", formatCounts(byMethod))
+	}
+	if byVersion, ok := stats.ToolStatistics["toolsByVersion"].(map[string]int); ok && len(byVersion) > 0 {
+		fmt.Printf("By version: %s\n", formatCounts(byVersion))
+	}
+
+	if len(stats.Documents) == 0 {
+		return
+	}
+	fmt.Println("\nBy document:")
+	for _, document := range stats.Documents {
+		fmt.Printf("  %s (%s)\n", document.Title, document.Path)
+		if document.Error != "" {
+			fmt.Printf("    error: %s\n", document.Error)
+			continue
+		}
+		fmt.Printf("    %d endpoints, %d tools, %d prompts, %d resources\n",
+			document.Endpoints, document.Tools, document.Prompts, document.Resources)
+		if len(document.Skipped) > 0 {
+			fmt.Printf("    skipped: %s\n", formatCounts(document.Skipped))
+		}
+	}
+}
+
+// formatCounts formats counts by name as "name count" pairs ordered by name
+func formatCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s %d", name, counts[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print the statistics as JSON")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// telemetryCmd represents the telemetry command
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Show the telemetry data policy and the report the server would send",
+	Long: `Print what the opt-in telemetry reports hold and what they leave out, whether
+telemetry is on for the current flags, environment variables, and config file, and
+the exact report a server started with them would send. Nothing is sent.`,
+	SilenceUsage: true,
+	RunE:         runTelemetry,
+}
+
+// runTelemetry prints the telemetry data policy, status, and report
+func runTelemetry(cmd *cobra.Command, args []string) error {
+	resolvedConfig, err := loadCommandConfig(cmd)
+	if err != nil {
+		return err
+	}
+	// The report is printed instead of logs
+	resolvedConfig.Logging.Enabled = false
+	logger := utils.NewLogger(resolvedConfig.Logging)
+
+	fmt.Println(server.TelemetryPolicy)
+	fmt.Println()
+
+	switch {
+	case server.TelemetryEnabled(resolvedConfig.Telemetry):
+		fmt.Printf("Status: on, reporting to %s\n", resolvedConfig.Telemetry.Endpoint)
+	case resolvedConfig.Telemetry.Mode == types.TelemetryOn:
+		// On with an endpoint, as validated, so only DO_NOT_TRACK turns it off
+		fmt.Println("Status: off, DO_NOT_TRACK is set")
+	default:
+		fmt.Println("Status: off")
+	}
+
+	stats, err := collectGenerationStats(resolvedConfig, logger)
+	if err != nil {
+		return err
+	}
+	counts := server.TelemetryCounts{Documents: stats.FilteredDocuments, Tools: stats.Tools}
+	encoded, err := json.MarshalIndent(server.NewTelemetryReport(servedTransports(resolvedConfig), counts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+	fmt.Println()
+	fmt.Println("Report for the current configuration:")
+	fmt.Println(string(encoded))
+	return nil
+}
+
+// servedTransports returns the transports the server serves with the configuration
+// and the transport flags
+func servedTransports(config *types.ResolvedConfig) []types.Transport {
+	switch {
+	case len(config.Server.Transports) > 0:
+		return config.Server.Transports
+	case sseMode:
+		return []types.Transport{types.TransportSSE}
+	case mcpHTTPMode:
+		return []types.Transport{types.TransportMCPHTTP}
+	}
+	return []types.Transport{types.TransportStdio}
+}
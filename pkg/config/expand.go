@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// envPlaceholderPattern matches the ${VAR} and ${VAR:-default} placeholders of config
+// values, and the $${ escape of a literal ${
+var envPlaceholderPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv replaces the placeholders of a config value with environment variables.
+// ${VAR:-default} falls back to the default when the variable is unset or empty, and
+// ${VAR} to an empty string.
+func expandEnv(value string) string {
+	return envPlaceholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		groups := envPlaceholderPattern.FindStringSubmatch(match)
+		if variable := os.Getenv(groups[1]); variable != "" {
+			return variable
+		}
+		return groups[2]
+	})
+}
+
+// unmarshalYAMLConfig parses a YAML config file, expanding the placeholders of its
+// keys and values. Unquoted values are typed after expansion, so "port: ${PORT}"
+// holds a number.
+func unmarshalYAMLConfig(content []byte, config *types.ConfigFile) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return err
+	}
+	expandYAMLNode(&document)
+	return document.Decode(config)
+}
+
+// expandYAMLNode expands the placeholders of the scalars under a YAML node
+func expandYAMLNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if expanded := expandEnv(node.Value); expanded != node.Value {
+			node.Value = expanded
+			if node.Style == 0 {
+				// Resolve the tag of the expanded value rather than the placeholder
+				node.Tag = ""
+			}
+		}
+		return
+	}
+	for _, child := range node.Content {
+		expandYAMLNode(child)
+	}
+}
+
+// unmarshalJSONConfig parses a JSON config file, expanding the placeholders of its
+// keys and string values
+func unmarshalJSONConfig(content []byte, config *types.ConfigFile) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return err
+	}
+
+	expanded, err := json.Marshal(expandJSONValue(document))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(expanded, config)
+}
+
+// expandJSONValue expands the placeholders of the strings under a decoded JSON value
+func expandJSONValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return expandEnv(typed)
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = expandJSONValue(item)
+		}
+		return typed
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			expanded[expandEnv(key)] = expandJSONValue(item)
+		}
+		return expanded
+	}
+	return value
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("WX_TEST_HOST", "api.example.com")
+	t.Setenv("WX_TEST_EMPTY", "")
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "no placeholders", value: "plain", want: "plain"},
+		{name: "set variable", value: "https://${WX_TEST_HOST}/v1", want: "https://api.example.com/v1"},
+		{name: "unset variable", value: "${WX_TEST_UNSET}", want: ""},
+		{name: "default of an unset variable", value: "${WX_TEST_UNSET:-localhost}", want: "localhost"},
+		{name: "default of an empty variable", value: "${WX_TEST_EMPTY:-localhost}", want: "localhost"},
+		{name: "default of a set variable", value: "${WX_TEST_HOST:-localhost}", want: "api.example.com"},
+		{name: "empty default", value: "${WX_TEST_UNSET:-}", want: ""},
+		{name: "default with separators", value: "${WX_TEST_UNSET:-http://localhost:8080/a-b}", want: "http://localhost:8080/a-b"},
+		{name: "several placeholders", value: "${WX_TEST_HOST}:${WX_TEST_UNSET:-443}", want: "api.example.com:443"},
+		{name: "escaped placeholder", value: "$${WX_TEST_HOST}", want: "${WX_TEST_HOST}"},
+		{name: "invalid variable name", value: "${1HOST}", want: "${1HOST}"},
+		{name: "dollar without braces", value: "$WX_TEST_HOST", want: "$WX_TEST_HOST"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := expandEnv(test.value); got != test.want {
+				t.Errorf("expandEnv(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalYAMLConfig(t *testing.T) {
+	t.Setenv("WX_TEST_PORT", "9090")
+	t.Setenv("WX_TEST_DEBUG", "true")
+	t.Setenv("WX_TEST_NAME", "api")
+
+	tests := []struct {
+		name    string
+		content string
+		want    types.ConfigFile
+		wantErr bool
+	}{
+		{
+			name:    "unquoted placeholders are typed after expansion",
+			content: "server:\n  port: ${WX_TEST_PORT}\ndebug: ${WX_TEST_DEBUG}\n",
+			want:    types.ConfigFile{Server: &types.ServerConfig{Port: 9090}, Debug: true},
+		},
+		{
+			name:    "defaults are typed",
+			content: "server:\n  port: ${WX_TEST_UNSET:-8080}\n  maxTools: ${WX_TEST_UNSET:-25}\n",
+			want:    types.ConfigFile{Server: &types.ServerConfig{Port: 8080, MaxTools: 25}},
+		},
+		{
+			name:    "quoted placeholders stay strings",
+			content: "name: \"${WX_TEST_PORT}\"\nversion: '${WX_TEST_UNSET:-1.0}'\n",
+			want:    types.ConfigFile{Name: "9090", Version: "1.0"},
+		},
+		{
+			name:    "a quoted number is not a port",
+			content: "server:\n  port: \"${WX_TEST_PORT}\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "sequences and keys",
+			content: "swaggerPaths:\n  - ./${WX_TEST_NAME}.yaml\n  - ${WX_TEST_UNSET:-./default.yaml}\nbaseUrls:\n  ${WX_TEST_NAME}: https://${WX_TEST_NAME}.example.com\n",
+			want: types.ConfigFile{
+				SwaggerPaths: []string{"./api.yaml", "./default.yaml"},
+				BaseURLs:     map[string]string{"api": "https://api.example.com"},
+			},
+		},
+		{
+			name:    "escaped placeholders",
+			content: "name: $${WX_TEST_NAME}\n",
+			want:    types.ConfigFile{Name: "${WX_TEST_NAME}"},
+		},
+		{
+			name:    "an expanded value that is not a number",
+			content: "server:\n  port: ${WX_TEST_NAME}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got types.ConfigFile
+			err := unmarshalYAMLConfig([]byte(test.content), &got)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("unmarshalYAMLConfig() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unmarshalYAMLConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unmarshalYAMLConfig() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONConfig(t *testing.T) {
+	t.Setenv("WX_TEST_NAME", "api")
+
+	tests := []struct {
+		name    string
+		content string
+		want    types.ConfigFile
+		wantErr bool
+	}{
+		{
+			name:    "strings are expanded",
+			content: `{"name": "${WX_TEST_NAME}", "version": "${WX_TEST_UNSET:-2.0}"}`,
+			want:    types.ConfigFile{Name: "api", Version: "2.0"},
+		},
+		{
+			name:    "numbers are kept",
+			content: `{"server": {"port": 8080, "maxTools": 12345678901}}`,
+			want:    types.ConfigFile{Server: &types.ServerConfig{Port: 8080, MaxTools: 12345678901}},
+		},
+		{
+			name:    "sequences and keys",
+			content: `{"swaggerPaths": ["./${WX_TEST_NAME}.json"], "baseUrls": {"${WX_TEST_NAME}": "https://${WX_TEST_NAME}.example.com"}}`,
+			want: types.ConfigFile{
+				SwaggerPaths: []string{"./api.json"},
+				BaseURLs:     map[string]string{"api": "https://api.example.com"},
+			},
+		},
+		{
+			name:    "placeholders do not type strings",
+			content: `{"server": {"port": "${WX_TEST_UNSET:-8080}"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"name": `,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got types.ConfigFile
+			err := unmarshalJSONConfig([]byte(test.content), &got)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("unmarshalJSONConfig() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unmarshalJSONConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unmarshalJSONConfig() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,23 +1,35 @@
 package config
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/jmespath/go-jmespath"
 	"swagger-docs-mcp/pkg/types"
 )
 
 // Manager handles configuration loading and validation
 type Manager struct {
 	configFileNames []string
+	// remoteDigests are the digests of the remote config files of the last load
+	remoteDigests map[string][sha256.Size]byte
+	// remoteClient fetches the remote config files of the last load through its
+	// outbound host policy
+	remoteClient *http.Client
 }
 
 // NewManager creates a new configuration manager
@@ -48,9 +60,15 @@ func (m *Manager) Load(overrides *types.ResolvedConfig) (*types.ResolvedConfig,
 		config = m.mergeConfig(config, fileConfig)
 	}
 
-	// Load from environment variables
+	// Apply the selected profile over the file, below the environment and flags
 	envConfig := m.loadEnvironmentConfig()
+	if config, err = m.applyProfile(config, fileConfig, selectedProfile(envConfig, overrides)); err != nil {
+		return nil, err
+	}
+
+	// Load from environment variables
 	config = m.mergeOverrides(config, envConfig)
+	m.applyEnvironmentSwitches(config)
 
 	// Apply overrides
 	if overrides != nil {
@@ -67,9 +85,18 @@ func (m *Manager) Load(overrides *types.ResolvedConfig) (*types.ResolvedConfig,
 
 // LoadFromFile loads configuration from a specific file
 func (m *Manager) LoadFromFile(configPath string, overrides *types.ResolvedConfig) (*types.ResolvedConfig, error) {
+	return m.LoadFromFiles([]string{configPath}, overrides)
+}
+
+// LoadFromFiles loads configuration from config files merged in order, each overlaying
+// the values it sets onto the files before it
+func (m *Manager) LoadFromFiles(configPaths []string, overrides *types.ResolvedConfig) (*types.ResolvedConfig, error) {
 	config := types.DefaultConfig()
+	envConfig := m.loadEnvironmentConfig()
 
-	fileConfig, err := m.loadConfigFile(configPath)
+	checksums := configChecksums(envConfig, overrides)
+	m.remoteClient = remoteConfigClient(configPaths, checksums, envConfig, overrides)
+	fileConfig, err := m.loadConfigFiles(configPaths, checksums)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config file: %w", err)
 	}
@@ -77,8 +104,13 @@ func (m *Manager) LoadFromFile(configPath string, overrides *types.ResolvedConfi
 		config = m.mergeConfig(config, fileConfig)
 	}
 
-	envConfig := m.loadEnvironmentConfig()
+	// Apply the selected profile over the file, below the environment and flags
+	if config, err = m.applyProfile(config, fileConfig, selectedProfile(envConfig, overrides)); err != nil {
+		return nil, err
+	}
+
 	config = m.mergeOverrides(config, envConfig)
+	m.applyEnvironmentSwitches(config)
 
 	if overrides != nil {
 		config = m.mergeOverrides(config, overrides)
@@ -117,40 +149,154 @@ func (m *Manager) loadConfigFile(configPath string) (*types.ConfigFile, error) {
 		return nil, nil
 	}
 
+	var config types.ConfigFile
+	if err := decodeConfigFile(filePath, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// loadConfigFiles loads config files in order into one configuration. Each file is
+// decoded onto the files before it, so it replaces only the values it sets: sections
+// and maps merge key by key, while lists are replaced as a whole. Files may be http(s)
+// URLs, which are verified against the checksums when any are given.
+func (m *Manager) loadConfigFiles(configPaths []string, checksums []string) (*types.ConfigFile, error) {
+	m.remoteDigests = nil
+
+	var config *types.ConfigFile
+	for _, filePath := range configPaths {
+		var content []byte
+		var err error
+		ext := strings.ToLower(filepath.Ext(filePath))
+		if IsRemoteConfig(filePath) {
+			if content, err = m.fetchRemoteConfig(filePath, checksums); err != nil {
+				return nil, err
+			}
+			ext = remoteConfigExt(filePath)
+		} else {
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				continue
+			}
+			if content, err = ioutil.ReadFile(filePath); err != nil {
+				return nil, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+			}
+		}
+
+		if config == nil {
+			config = &types.ConfigFile{}
+		}
+		if err := decodeConfigContent(filePath, ext, content, config); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// decodeConfigFile parses a config file onto a configuration
+func decodeConfigFile(filePath string, config *types.ConfigFile) error {
 	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+		return fmt.Errorf("failed to read config file %s: %w", filePath, err)
 	}
+	return decodeConfigContent(filePath, strings.ToLower(filepath.Ext(filePath)), content, config)
+}
 
-	var config types.ConfigFile
-
-	// Determine file format and parse
-	ext := strings.ToLower(filepath.Ext(filePath))
+// decodeConfigContent parses the content of a config file onto a configuration in the
+// format of its extension, expanding ${VAR} placeholders
+func decodeConfigContent(filePath string, ext string, content []byte, config *types.ConfigFile) error {
 	switch ext {
 	case ".json":
-		if err := json.Unmarshal(content, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse JSON config file %s: %w", filePath, err)
+		if err := unmarshalJSONConfig(content, config); err != nil {
+			return fmt.Errorf("failed to parse JSON config file %s: %w", filePath, err)
 		}
 	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(content, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML config file %s: %w", filePath, err)
+		if err := unmarshalYAMLConfig(content, config); err != nil {
+			return fmt.Errorf("failed to parse YAML config file %s: %w", filePath, err)
 		}
 	default:
-		// Try JSON first, then YAML
-		if err := json.Unmarshal(content, &config); err != nil {
-			if err := yaml.Unmarshal(content, &config); err != nil {
-				return nil, fmt.Errorf("failed to parse config file %s as JSON or YAML: %w", filePath, err)
+		// Parse JSON as JSON and anything else as YAML. The format is chosen up front
+		// because a failed attempt may have decoded part of the file already.
+		if json.Valid(content) {
+			if err := unmarshalJSONConfig(content, config); err != nil {
+				return fmt.Errorf("failed to parse JSON config file %s: %w", filePath, err)
 			}
+		} else if err := unmarshalYAMLConfig(content, config); err != nil {
+			return fmt.Errorf("failed to parse config file %s as JSON or YAML: %w", filePath, err)
 		}
 	}
 
-	return &config, nil
+	return nil
+}
+
+// selectedProfile returns the name of the profile selected by --profile, or else by
+// WX_MCP_PROFILE
+func selectedProfile(envConfig *types.ResolvedConfig, overrides *types.ResolvedConfig) string {
+	if overrides != nil && overrides.Profile != "" {
+		return overrides.Profile
+	}
+	return envConfig.Profile
+}
+
+// configChecksums returns the remote config checksums given by --config-checksum, or
+// else by WX_MCP_CONFIG_CHECKSUM
+func configChecksums(envConfig *types.ResolvedConfig, overrides *types.ResolvedConfig) []string {
+	if overrides != nil && len(overrides.ConfigChecksums) > 0 {
+		return overrides.ConfigChecksums
+	}
+	return envConfig.ConfigChecksums
+}
+
+// applyProfile merges a profile of the config file over the configuration
+func (m *Manager) applyProfile(config *types.ResolvedConfig, fileConfig *types.ConfigFile, name string) (*types.ResolvedConfig, error) {
+	if name == "" {
+		return config, nil
+	}
+	if fileConfig == nil {
+		return nil, fmt.Errorf("profile %s is selected but no config file was found", name)
+	}
+
+	profile, exists := fileConfig.Profiles[name]
+	if !exists {
+		names := make([]string, 0, len(fileConfig.Profiles))
+		for profileName := range fileConfig.Profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("profile %s is not defined: the config file has no profiles", name)
+		}
+		return nil, fmt.Errorf("profile %s is not defined; use one of %s", name, strings.Join(names, ", "))
+	}
+
+	config = m.mergeConfig(config, &types.ConfigFile{
+		SwaggerPaths: profile.SwaggerPaths,
+		SwaggerURLs:  profile.SwaggerURLs,
+		Auth:         profile.Auth,
+		BaseURLs:     profile.BaseURLs,
+	})
+	config.Profile = name
+	return config, nil
 }
 
 // loadEnvironmentConfig loads configuration from environment variables
 func (m *Manager) loadEnvironmentConfig() *types.ResolvedConfig {
 	config := &types.ResolvedConfig{}
 
+	// Config file profile
+	if profile := os.Getenv("WX_MCP_PROFILE"); profile != "" {
+		config.Profile = profile
+	}
+
+	// Remote config files
+	if checksums := os.Getenv("WX_MCP_CONFIG_CHECKSUM"); checksums != "" {
+		config.ConfigChecksums = strings.Split(checksums, ",")
+	}
+	if refresh := os.Getenv("WX_MCP_CONFIG_REFRESH"); refresh != "" {
+		if interval, err := time.ParseDuration(refresh); err == nil {
+			config.ConfigRefresh = interval
+		}
+	}
+
 	// Swagger paths and URLs
 	if paths := os.Getenv("WX_MCP_PATHS"); paths != "" {
 		config.SwaggerPaths = strings.Split(paths, ",")
@@ -260,6 +406,148 @@ func (m *Manager) loadEnvironmentConfig() *types.ResolvedConfig {
 		}
 	}
 
+	if authTokens := os.Getenv("WX_MCP_HTTP_AUTH_TOKENS"); authTokens != "" {
+		config.Server.Auth.BearerTokens = strings.Split(authTokens, ",")
+	}
+	if oidcIssuer := os.Getenv("WX_MCP_OIDC_ISSUER"); oidcIssuer != "" {
+		config.Server.Auth.OIDCIssuer = oidcIssuer
+	}
+	if oidcAudience := os.Getenv("WX_MCP_OIDC_AUDIENCE"); oidcAudience != "" {
+		config.Server.Auth.OIDCAudience = oidcAudience
+	}
+	if snapshotPath := os.Getenv("WX_MCP_SNAPSHOT_PATH"); snapshotPath != "" {
+		config.Server.SnapshotPath = snapshotPath
+	}
+	if usagePath := os.Getenv("WX_MCP_USAGE_PATH"); usagePath != "" {
+		config.Server.UsagePath = usagePath
+	}
+	if docsAssetsURL := os.Getenv("WX_MCP_DOCS_ASSETS_URL"); docsAssetsURL != "" {
+		config.Server.DocsAssetsURL = docsAssetsURL
+	}
+	if transports := os.Getenv("WX_MCP_TRANSPORTS"); transports != "" {
+		config.Server.Transports = nil
+		for _, transport := range strings.Split(transports, ",") {
+			config.Server.Transports = append(config.Server.Transports, types.Transport(strings.TrimSpace(transport)))
+		}
+	}
+	if maxMessageSize := os.Getenv("WX_MCP_MAX_MESSAGE_SIZE"); maxMessageSize != "" {
+		if size, err := strconv.Atoi(maxMessageSize); err == nil {
+			config.Server.MaxMessageSize = size
+		}
+	}
+	if maxInlineSize := os.Getenv("WX_MCP_BLOB_MAX_INLINE_SIZE"); maxInlineSize != "" {
+		if size, err := strconv.Atoi(maxInlineSize); err == nil {
+			config.Server.Blobs.MaxInlineSize = size
+		}
+	}
+	if maxInlineTextSize := os.Getenv("WX_MCP_BLOB_MAX_INLINE_TEXT_SIZE"); maxInlineTextSize != "" {
+		if size, err := strconv.Atoi(maxInlineTextSize); err == nil {
+			config.Server.Blobs.MaxInlineTextSize = size
+		}
+	}
+	if blobTTL := os.Getenv("WX_MCP_BLOB_TTL"); blobTTL != "" {
+		if ttl, err := time.ParseDuration(blobTTL); err == nil {
+			config.Server.Blobs.TTL = ttl
+		}
+	}
+	if maxStoredSize := os.Getenv("WX_MCP_BLOB_MAX_STORED_SIZE"); maxStoredSize != "" {
+		if size, err := strconv.Atoi(maxStoredSize); err == nil {
+			config.Server.Blobs.MaxStoredSize = size
+		}
+	}
+	if listen := os.Getenv("WX_MCP_LISTEN"); listen != "" {
+		config.Server.Listen = listen
+	}
+	if socketMode := os.Getenv("WX_MCP_SOCKET_MODE"); socketMode != "" {
+		config.Server.SocketMode = socketMode
+	}
+	if mcpHTTPListen := os.Getenv("WX_MCP_MCP_HTTP_LISTEN"); mcpHTTPListen != "" {
+		config.Server.MCPHTTPListen = mcpHTTPListen
+	}
+	if mcpHTTPPort := os.Getenv("WX_MCP_MCP_HTTP_PORT"); mcpHTTPPort != "" {
+		if port, err := strconv.Atoi(mcpHTTPPort); err == nil {
+			config.Server.MCPHTTPPort = port
+		}
+	}
+	if gateway := os.Getenv("WX_MCP_GATEWAY"); gateway != "" {
+		config.Server.Gateway = strings.ToLower(gateway) == "true"
+	}
+	if toolPriority := os.Getenv("WX_MCP_TOOL_PRIORITY"); toolPriority != "" {
+		config.Server.ToolPriority = nil
+		for _, pattern := range strings.Split(toolPriority, ",") {
+			config.Server.ToolPriority = append(config.Server.ToolPriority, strings.TrimSpace(pattern))
+		}
+	}
+	if sensitiveFields := os.Getenv("WX_MCP_EVENT_SENSITIVE_FIELDS"); sensitiveFields != "" {
+		config.Server.Events.SensitiveFields = nil
+		for _, field := range strings.Split(sensitiveFields, ",") {
+			config.Server.Events.SensitiveFields = append(config.Server.Events.SensitiveFields, strings.TrimSpace(field))
+		}
+	}
+	if maxResultSize := os.Getenv("WX_MCP_EVENT_MAX_RESULT_SIZE"); maxResultSize != "" {
+		if size, err := strconv.Atoi(maxResultSize); err == nil {
+			config.Server.Events.MaxResultSize = size
+		}
+	}
+	if eventLogPath := os.Getenv("WX_MCP_EVENT_LOG_PATH"); eventLogPath != "" {
+		config.Server.Events.Log.Path = eventLogPath
+	}
+	if maxAge := os.Getenv("WX_MCP_EVENT_LOG_MAX_AGE"); maxAge != "" {
+		if duration, err := time.ParseDuration(maxAge); err == nil {
+			config.Server.Events.Log.MaxAge = duration
+		}
+	}
+	if maxEvents := os.Getenv("WX_MCP_EVENT_LOG_MAX_EVENTS"); maxEvents != "" {
+		if n, err := strconv.Atoi(maxEvents); err == nil {
+			config.Server.Events.Log.MaxEvents = n
+		}
+	}
+	if perMinute := os.Getenv("WX_MCP_QUOTA_PER_MINUTE"); perMinute != "" {
+		if n, err := strconv.Atoi(perMinute); err == nil {
+			config.Server.Quotas.PerMinute = n
+		}
+	}
+	if perDay := os.Getenv("WX_MCP_QUOTA_PER_DAY"); perDay != "" {
+		if n, err := strconv.Atoi(perDay); err == nil {
+			config.Server.Quotas.PerDay = n
+		}
+	}
+	if busType := os.Getenv("WX_MCP_BUS_TYPE"); busType != "" {
+		config.Server.Bus.Type = busType
+	}
+	if brokers := os.Getenv("WX_MCP_BUS_BROKERS"); brokers != "" {
+		config.Server.Bus.Brokers = nil
+		for _, broker := range strings.Split(brokers, ",") {
+			config.Server.Bus.Brokers = append(config.Server.Bus.Brokers, strings.TrimSpace(broker))
+		}
+	}
+	if topic := os.Getenv("WX_MCP_BUS_TOPIC"); topic != "" {
+		config.Server.Bus.Topic = topic
+	}
+	if serialization := os.Getenv("WX_MCP_BUS_SERIALIZATION"); serialization != "" {
+		config.Server.Bus.Serialization = serialization
+	}
+	if registryURL := os.Getenv("WX_MCP_SHARED_REGISTRY_URL"); registryURL != "" {
+		config.Server.SharedRegistry.URL = registryURL
+	}
+	if registryKey := os.Getenv("WX_MCP_SHARED_REGISTRY_KEY"); registryKey != "" {
+		config.Server.SharedRegistry.Key = registryKey
+	}
+	if syncInterval := os.Getenv("WX_MCP_SHARED_REGISTRY_SYNC_INTERVAL"); syncInterval != "" {
+		if duration, err := time.ParseDuration(syncInterval); err == nil {
+			config.Server.SharedRegistry.SyncInterval = duration
+		}
+	}
+	if leaderOnly := os.Getenv("WX_MCP_SHARED_REGISTRY_REFRESH_LEADER_ONLY"); leaderOnly != "" {
+		config.Server.SharedRegistry.RefreshLeaderOnly = strings.ToLower(leaderOnly) == "true"
+	}
+	if webhookURLs := os.Getenv("WX_MCP_WEBHOOK_URLS"); webhookURLs != "" {
+		config.Server.Webhooks = nil
+		for _, webhookURL := range strings.Split(webhookURLs, ",") {
+			config.Server.Webhooks = append(config.Server.Webhooks, types.WebhookConfig{URL: strings.TrimSpace(webhookURL)})
+		}
+	}
+
 	// Logging
 	if logLevel := os.Getenv("WX_MCP_LOG_LEVEL"); logLevel != "" {
 		validLevels := []string{"error", "warn", "info", "debug"}
@@ -270,8 +558,206 @@ func (m *Manager) loadEnvironmentConfig() *types.ResolvedConfig {
 			}
 		}
 	}
+	if sampleRate := os.Getenv("WX_MCP_LOG_EGRESS_SAMPLE_RATE"); sampleRate != "" {
+		if rate, err := strconv.ParseFloat(sampleRate, 64); err == nil {
+			config.Logging.EgressSampleRate = rate
+		}
+	}
+
+	// Swagger processing (boolean switches are applied by applyEnvironmentSwitches)
+	if refreshInterval := os.Getenv("WX_MCP_REFRESH_INTERVAL"); refreshInterval != "" {
+		if d, err := time.ParseDuration(refreshInterval); err == nil {
+			config.SwaggerProcessing.RefreshInterval = d
+		} else if ms, err := strconv.Atoi(refreshInterval); err == nil {
+			config.SwaggerProcessing.RefreshInterval = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if gracePeriod := os.Getenv("WX_MCP_STALE_GRACE_PERIOD"); gracePeriod != "" {
+		if d, err := time.ParseDuration(gracePeriod); err == nil {
+			config.SwaggerProcessing.StaleGracePeriod = d
+		} else if ms, err := strconv.Atoi(gracePeriod); err == nil {
+			config.SwaggerProcessing.StaleGracePeriod = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	if refHosts := os.Getenv("WX_MCP_ALLOWED_REF_HOSTS"); refHosts != "" {
+		config.SwaggerProcessing.AllowedRefHosts = strings.Split(refHosts, ",")
+	}
+
+	// Prompts
+	if conflictPolicy := os.Getenv("WX_MCP_PROMPT_CONFLICT_POLICY"); conflictPolicy != "" {
+		config.Prompts.ConflictPolicy = types.PromptConflictPolicy(conflictPolicy)
+	}
+	if templateDir := os.Getenv("WX_MCP_PROMPT_TEMPLATE_DIR"); templateDir != "" {
+		config.Prompts.TemplateDir = templateDir
+	}
+
+	// Resources
+	if uriScheme := os.Getenv("WX_MCP_RESOURCE_URI_SCHEME"); uriScheme != "" {
+		config.Resources.URIScheme = uriScheme
+	}
+	if documentNaming := os.Getenv("WX_MCP_RESOURCE_DOCUMENT_NAMING"); documentNaming != "" {
+		config.Resources.DocumentNaming = types.ResourceDocumentNaming(documentNaming)
+	}
+
+	// GraphQL
+	if graphQLEndpoint := os.Getenv("WX_MCP_GRAPHQL_ENDPOINT"); graphQLEndpoint != "" {
+		config.GraphQL.Endpoint = graphQLEndpoint
+	}
+
+	// gRPC
+	if grpcGateway := os.Getenv("WX_MCP_GRPC_GATEWAY"); grpcGateway != "" {
+		config.GRPC.GatewayURL = grpcGateway
+	}
+
+	// Mock execution
+	if mockDocuments := os.Getenv("WX_MCP_MOCK_DOCUMENTS"); mockDocuments != "" {
+		config.Mock.Documents = strings.Split(mockDocuments, ",")
+	}
+
+	// Record/replay cassettes
+	if cassetteDir := os.Getenv("WX_MCP_CASSETTE_DIR"); cassetteDir != "" {
+		config.Cassette.Dir = cassetteDir
+	}
+	if cassetteMode := os.Getenv("WX_MCP_CASSETTE_MODE"); cassetteMode != "" {
+		config.Cassette.Mode = types.CassetteMode(cassetteMode)
+	}
+
+	// Pagination
+	if maxPages := os.Getenv("WX_MCP_PAGINATION_MAX_PAGES"); maxPages != "" {
+		if mp, err := strconv.Atoi(maxPages); err == nil {
+			config.Pagination.MaxPages = mp
+		}
+	}
+
+	// Tool execution
+	if maxResponseChars := os.Getenv("WX_MCP_TOOL_EXECUTION_MAX_RESPONSE_CHARS"); maxResponseChars != "" {
+		if n, err := strconv.Atoi(maxResponseChars); err == nil {
+			config.ToolExecution.MaxResponseChars = n
+		}
+	}
+
+	// Telemetry
+	if telemetry := os.Getenv("WX_MCP_TELEMETRY"); telemetry != "" {
+		config.Telemetry.Mode = types.TelemetryMode(telemetry)
+	}
+	if telemetryEndpoint := os.Getenv("WX_MCP_TELEMETRY_ENDPOINT"); telemetryEndpoint != "" {
+		config.Telemetry.Endpoint = telemetryEndpoint
+	}
+
+	// Update check
+	if updateCheck := os.Getenv("WX_MCP_UPDATE_CHECK"); updateCheck != "" {
+		config.Update.Check = strings.ToLower(updateCheck) == "true"
+	}
+	if updateRepository := os.Getenv("WX_MCP_UPDATE_REPOSITORY"); updateRepository != "" {
+		config.Update.Repository = updateRepository
+	}
+	if updateAPIURL := os.Getenv("WX_MCP_UPDATE_API_URL"); updateAPIURL != "" {
+		config.Update.APIURL = updateAPIURL
+	}
+
+	// Plugins, as comma-separated hook=command entries
+	if plugins := os.Getenv("WX_MCP_PLUGINS"); plugins != "" {
+		config.Plugins = nil
+		for _, spec := range strings.Split(plugins, ",") {
+			config.Plugins = append(config.Plugins, types.ParsePluginSpec(spec))
+		}
+	}
+
+	// HTTP transport
+	if maxIdleConns := os.Getenv("WX_MCP_HTTP_MAX_IDLE_CONNS"); maxIdleConns != "" {
+		if n, err := strconv.Atoi(maxIdleConns); err == nil {
+			config.HTTP.Transport.MaxIdleConns = n
+		}
+	}
+	if maxIdleConnsPerHost := os.Getenv("WX_MCP_HTTP_MAX_IDLE_CONNS_PER_HOST"); maxIdleConnsPerHost != "" {
+		if n, err := strconv.Atoi(maxIdleConnsPerHost); err == nil {
+			config.HTTP.Transport.MaxIdleConnsPerHost = n
+		}
+	}
+	if maxConnsPerHost := os.Getenv("WX_MCP_HTTP_MAX_CONNS_PER_HOST"); maxConnsPerHost != "" {
+		if n, err := strconv.Atoi(maxConnsPerHost); err == nil {
+			config.HTTP.Transport.MaxConnsPerHost = n
+		}
+	}
+	if idleConnTimeout := os.Getenv("WX_MCP_HTTP_IDLE_CONN_TIMEOUT"); idleConnTimeout != "" {
+		if d, err := time.ParseDuration(idleConnTimeout); err == nil {
+			config.HTTP.Transport.IdleConnTimeout = d
+		}
+	}
+	if tlsHandshakeTimeout := os.Getenv("WX_MCP_HTTP_TLS_HANDSHAKE_TIMEOUT"); tlsHandshakeTimeout != "" {
+		if d, err := time.ParseDuration(tlsHandshakeTimeout); err == nil {
+			config.HTTP.Transport.TLSHandshakeTimeout = d
+		}
+	}
+	if disableHTTP2 := os.Getenv("WX_MCP_HTTP_DISABLE_HTTP2"); disableHTTP2 != "" {
+		config.HTTP.Transport.DisableHTTP2 = strings.ToLower(disableHTTP2) == "true"
+	}
+
+	// DNS resolution
+	if cacheTTL := os.Getenv("WX_MCP_DNS_CACHE_TTL"); cacheTTL != "" {
+		if d, err := time.ParseDuration(cacheTTL); err == nil {
+			config.HTTP.DNS.CacheTTL = d
+		}
+	}
+	if dnsServers := os.Getenv("WX_MCP_DNS_SERVERS"); dnsServers != "" {
+		config.HTTP.DNS.Servers = strings.Split(dnsServers, ",")
+	}
+	if dnsHosts := os.Getenv("WX_MCP_DNS_HOSTS"); dnsHosts != "" {
+		config.HTTP.DNS.Hosts = make(map[string]string)
+		for _, entry := range strings.Split(dnsHosts, ",") {
+			if host, address, found := strings.Cut(entry, "="); found {
+				config.HTTP.DNS.Hosts[strings.TrimSpace(host)] = strings.TrimSpace(address)
+			}
+		}
+	}
+
+	// Outbound host restrictions
+	if allowedHosts := os.Getenv("WX_MCP_ALLOWED_HOSTS"); allowedHosts != "" {
+		config.HTTP.AllowedHosts = strings.Split(allowedHosts, ",")
+	}
+	if deniedHosts := os.Getenv("WX_MCP_DENIED_HOSTS"); deniedHosts != "" {
+		config.HTTP.DeniedHosts = strings.Split(deniedHosts, ",")
+	}
+
+	// Concurrency limits
+	if maxRequests := os.Getenv("WX_MCP_MAX_CONCURRENT_REQUESTS"); maxRequests != "" {
+		if n, err := strconv.Atoi(maxRequests); err == nil {
+			config.HTTP.Concurrency.MaxRequests = n
+		}
+	}
+	if maxPerHost := os.Getenv("WX_MCP_MAX_CONCURRENT_PER_HOST"); maxPerHost != "" {
+		if n, err := strconv.Atoi(maxPerHost); err == nil {
+			config.HTTP.Concurrency.MaxPerHost = n
+		}
+	}
+	if queueSize := os.Getenv("WX_MCP_REQUEST_QUEUE_SIZE"); queueSize != "" {
+		if n, err := strconv.Atoi(queueSize); err == nil {
+			config.HTTP.Concurrency.QueueSize = n
+		}
+	}
+	if queueTimeout := os.Getenv("WX_MCP_REQUEST_QUEUE_TIMEOUT"); queueTimeout != "" {
+		if d, err := time.ParseDuration(queueTimeout); err == nil {
+			config.HTTP.Concurrency.QueueTimeout = d
+		}
+	}
+	if rejection := os.Getenv("WX_MCP_REQUEST_REJECTION"); rejection != "" {
+		config.HTTP.Concurrency.Rejection = types.ConcurrencyRejection(strings.ToLower(rejection))
+	}
+
+	// Hedged requests
+	if hedgeDelay := os.Getenv("WX_MCP_HEDGE_DELAY"); hedgeDelay != "" {
+		if d, err := time.ParseDuration(hedgeDelay); err == nil {
+			config.HTTP.HedgeDelay = d
+		}
+	}
+
+	return config
+}
 
-	// Swagger processing
+// applyEnvironmentSwitches applies boolean environment variables directly to the config.
+// Unlike mergeOverrides, this lets an environment variable switch a setting off.
+func (m *Manager) applyEnvironmentSwitches(config *types.ResolvedConfig) {
 	if validateDocs := os.Getenv("WX_MCP_VALIDATE_DOCUMENTS"); validateDocs != "" {
 		config.SwaggerProcessing.ValidateDocuments = strings.ToLower(validateDocs) == "true"
 	}
@@ -284,7 +770,36 @@ func (m *Manager) loadEnvironmentConfig() *types.ResolvedConfig {
 		config.SwaggerProcessing.IgnoreErrors = strings.ToLower(ignoreErrors) == "true"
 	}
 
-	return config
+	if watchFiles := os.Getenv("WX_MCP_WATCH_FILES"); watchFiles != "" {
+		config.SwaggerProcessing.WatchFiles = strings.ToLower(watchFiles) == "true"
+	}
+
+	if omitFilter := os.Getenv("WX_MCP_OMIT_FILTER_ARGUMENT"); omitFilter != "" {
+		config.ToolGeneration.OmitFilterArgument = strings.ToLower(omitFilter) == "true"
+	}
+	if lazySchemas := os.Getenv("WX_MCP_LAZY_SCHEMAS"); lazySchemas != "" {
+		config.ToolGeneration.LazySchemas = strings.ToLower(lazySchemas) == "true"
+	}
+	if descriptionTemplate := os.Getenv("WX_MCP_DESCRIPTION_TEMPLATE"); descriptionTemplate != "" {
+		config.ToolGeneration.DescriptionTemplate = descriptionTemplate
+	}
+	if unsupportedAuth := os.Getenv("WX_MCP_UNSUPPORTED_AUTH"); unsupportedAuth != "" {
+		config.ToolGeneration.UnsupportedAuth = types.UnsupportedAuthPolicy(unsupportedAuth)
+	}
+	if disabledTools := os.Getenv("WX_MCP_DISABLED_TOOLS"); disabledTools != "" {
+		config.ToolGeneration.DisabledTools = nil
+		for _, pattern := range strings.Split(disabledTools, ",") {
+			config.ToolGeneration.DisabledTools = append(config.ToolGeneration.DisabledTools, strings.TrimSpace(pattern))
+		}
+	}
+
+	if mock := os.Getenv("WX_MCP_MOCK"); mock != "" {
+		config.Mock.Enabled = strings.ToLower(mock) == "true"
+	}
+
+	if validateResponses := os.Getenv("WX_MCP_VALIDATE_RESPONSES"); validateResponses != "" {
+		config.ResponseValidation.Enabled = strings.ToLower(validateResponses) == "true"
+	}
 }
 
 // mergeConfig merges a config file into the resolved config
@@ -317,6 +832,92 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 		if override.Server.MaxTools > 0 {
 			base.Server.MaxTools = override.Server.MaxTools
 		}
+		if len(override.Server.Auth.BearerTokens) > 0 {
+			base.Server.Auth.BearerTokens = override.Server.Auth.BearerTokens
+		}
+		if override.Server.Auth.OIDCIssuer != "" {
+			base.Server.Auth.OIDCIssuer = override.Server.Auth.OIDCIssuer
+		}
+		if override.Server.Auth.OIDCAudience != "" {
+			base.Server.Auth.OIDCAudience = override.Server.Auth.OIDCAudience
+		}
+		if override.Server.Auth.Tenants != nil {
+			base.Server.Auth.Tenants = override.Server.Auth.Tenants
+		}
+		if override.Server.Port > 0 {
+			base.Server.Port = override.Server.Port
+		}
+		if override.Server.SnapshotPath != "" {
+			base.Server.SnapshotPath = override.Server.SnapshotPath
+		}
+		if override.Server.UsagePath != "" {
+			base.Server.UsagePath = override.Server.UsagePath
+		}
+		if override.Server.DocsAssetsURL != "" {
+			base.Server.DocsAssetsURL = override.Server.DocsAssetsURL
+		}
+		if len(override.Server.Transports) > 0 {
+			base.Server.Transports = override.Server.Transports
+		}
+		if override.Server.MCPHTTPPort > 0 {
+			base.Server.MCPHTTPPort = override.Server.MCPHTTPPort
+		}
+		if override.Server.MCPHTTPListen != "" {
+			base.Server.MCPHTTPListen = override.Server.MCPHTTPListen
+		}
+		if override.Server.Listen != "" {
+			base.Server.Listen = override.Server.Listen
+		}
+		if override.Server.SocketMode != "" {
+			base.Server.SocketMode = override.Server.SocketMode
+		}
+		if override.Server.MaxMessageSize > 0 {
+			base.Server.MaxMessageSize = override.Server.MaxMessageSize
+		}
+		if override.Server.Blobs.MaxInlineSize > 0 {
+			base.Server.Blobs.MaxInlineSize = override.Server.Blobs.MaxInlineSize
+		}
+		if override.Server.Blobs.MaxInlineTextSize > 0 {
+			base.Server.Blobs.MaxInlineTextSize = override.Server.Blobs.MaxInlineTextSize
+		}
+		if override.Server.Blobs.TTL > 0 {
+			base.Server.Blobs.TTL = override.Server.Blobs.TTL
+		}
+		if override.Server.Blobs.MaxStoredSize > 0 {
+			base.Server.Blobs.MaxStoredSize = override.Server.Blobs.MaxStoredSize
+		}
+		if len(override.Server.ToolPriority) > 0 {
+			base.Server.ToolPriority = override.Server.ToolPriority
+		}
+		if override.Server.Gateway {
+			base.Server.Gateway = override.Server.Gateway
+		}
+		if len(override.Server.Events.SensitiveFields) > 0 {
+			base.Server.Events.SensitiveFields = override.Server.Events.SensitiveFields
+		}
+		if override.Server.Events.MaxResultSize > 0 {
+			base.Server.Events.MaxResultSize = override.Server.Events.MaxResultSize
+		}
+		if override.Server.Events.Log.Path != "" {
+			base.Server.Events.Log.Path = override.Server.Events.Log.Path
+		}
+		if override.Server.Events.Log.MaxAge > 0 {
+			base.Server.Events.Log.MaxAge = override.Server.Events.Log.MaxAge
+		}
+		if override.Server.Events.Log.MaxEvents > 0 {
+			base.Server.Events.Log.MaxEvents = override.Server.Events.Log.MaxEvents
+		}
+		if override.Server.Quotas.PerMinute > 0 {
+			base.Server.Quotas.PerMinute = override.Server.Quotas.PerMinute
+		}
+		if override.Server.Quotas.PerDay > 0 {
+			base.Server.Quotas.PerDay = override.Server.Quotas.PerDay
+		}
+		if len(override.Server.Webhooks) > 0 {
+			base.Server.Webhooks = override.Server.Webhooks
+		}
+		mergeBusConfig(&base.Server.Bus, override.Server.Bus)
+		mergeSharedRegistryConfig(&base.Server.SharedRegistry, override.Server.SharedRegistry)
 	}
 	if override.HTTP != nil {
 		if override.HTTP.Timeout > 0 {
@@ -328,6 +929,18 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 		if override.HTTP.UserAgent != "" {
 			base.HTTP.UserAgent = override.HTTP.UserAgent
 		}
+		if override.HTTP.HedgeDelay > 0 {
+			base.HTTP.HedgeDelay = override.HTTP.HedgeDelay
+		}
+		mergeTransportConfig(&base.HTTP.Transport, override.HTTP.Transport)
+		mergeDNSConfig(&base.HTTP.DNS, override.HTTP.DNS)
+		mergeConcurrencyConfig(&base.HTTP.Concurrency, override.HTTP.Concurrency)
+		if len(override.HTTP.AllowedHosts) > 0 {
+			base.HTTP.AllowedHosts = override.HTTP.AllowedHosts
+		}
+		if len(override.HTTP.DeniedHosts) > 0 {
+			base.HTTP.DeniedHosts = override.HTTP.DeniedHosts
+		}
 	}
 	if override.Auth != nil {
 		if override.Auth.APIKey != "" {
@@ -339,6 +952,9 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 		if override.Auth.Credentials != nil {
 			base.Auth.Credentials = override.Auth.Credentials
 		}
+		if len(override.Auth.Signing) > 0 {
+			base.Auth.Signing = override.Auth.Signing
+		}
 	}
 	if override.Debug {
 		base.Debug = override.Debug
@@ -348,6 +964,9 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 			base.Logging.Level = override.Logging.Level
 		}
 		base.Logging.Enabled = override.Logging.Enabled
+		if override.Logging.EgressSampleRate > 0 {
+			base.Logging.EgressSampleRate = override.Logging.EgressSampleRate
+		}
 	}
 	if override.ToolGeneration != nil {
 		base.ToolGeneration.IncludeDeprecated = override.ToolGeneration.IncludeDeprecated
@@ -358,11 +977,48 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 		if override.ToolGeneration.TagPrefix != "" {
 			base.ToolGeneration.TagPrefix = override.ToolGeneration.TagPrefix
 		}
+		if override.ToolGeneration.OmitFilterArgument {
+			base.ToolGeneration.OmitFilterArgument = override.ToolGeneration.OmitFilterArgument
+		}
+		if override.ToolGeneration.LazySchemas {
+			base.ToolGeneration.LazySchemas = override.ToolGeneration.LazySchemas
+		}
+		if override.ToolGeneration.SchemaCacheSize != 0 {
+			base.ToolGeneration.SchemaCacheSize = override.ToolGeneration.SchemaCacheSize
+		}
+		if len(override.ToolGeneration.DisabledTools) > 0 {
+			base.ToolGeneration.DisabledTools = override.ToolGeneration.DisabledTools
+		}
+		if len(override.ToolGeneration.IgnoreFormats) > 0 {
+			base.ToolGeneration.IgnoreFormats = override.ToolGeneration.IgnoreFormats
+		}
+		if override.ToolGeneration.PreferFormat != "" {
+			base.ToolGeneration.PreferFormat = override.ToolGeneration.PreferFormat
+		}
+		if override.ToolGeneration.DocumentFormats != nil {
+			base.ToolGeneration.DocumentFormats = override.ToolGeneration.DocumentFormats
+		}
+		if override.ToolGeneration.UnsupportedAuth != "" {
+			base.ToolGeneration.UnsupportedAuth = override.ToolGeneration.UnsupportedAuth
+		}
+		if override.ToolGeneration.DescriptionTemplate != "" {
+			base.ToolGeneration.DescriptionTemplate = override.ToolGeneration.DescriptionTemplate
+		}
 	}
 	if override.SwaggerProcessing != nil {
 		base.SwaggerProcessing.ValidateDocuments = override.SwaggerProcessing.ValidateDocuments
 		base.SwaggerProcessing.ResolveReferences = override.SwaggerProcessing.ResolveReferences
 		base.SwaggerProcessing.IgnoreErrors = override.SwaggerProcessing.IgnoreErrors
+		base.SwaggerProcessing.WatchFiles = override.SwaggerProcessing.WatchFiles
+		if override.SwaggerProcessing.RefreshInterval > 0 {
+			base.SwaggerProcessing.RefreshInterval = override.SwaggerProcessing.RefreshInterval
+		}
+		if override.SwaggerProcessing.StaleGracePeriod > 0 {
+			base.SwaggerProcessing.StaleGracePeriod = override.SwaggerProcessing.StaleGracePeriod
+		}
+		if len(override.SwaggerProcessing.AllowedRefHosts) > 0 {
+			base.SwaggerProcessing.AllowedRefHosts = override.SwaggerProcessing.AllowedRefHosts
+		}
 	}
 	if override.Prompts != nil {
 		base.Prompts.Enabled = override.Prompts.Enabled
@@ -371,18 +1027,116 @@ func (m *Manager) mergeConfig(base *types.ResolvedConfig, override *types.Config
 		if len(override.Prompts.Categories) > 0 {
 			base.Prompts.Categories = override.Prompts.Categories
 		}
+		if override.Prompts.ConflictPolicy != "" {
+			base.Prompts.ConflictPolicy = override.Prompts.ConflictPolicy
+		}
+		if override.Prompts.TemplateDir != "" {
+			base.Prompts.TemplateDir = override.Prompts.TemplateDir
+		}
+		if len(override.Prompts.CategoryDefinitions) > 0 {
+			base.Prompts.CategoryDefinitions = override.Prompts.CategoryDefinitions
+		}
+		if override.Prompts.DisableWeatherCategories {
+			base.Prompts.DisableWeatherCategories = true
+		}
+		if len(override.Prompts.DocumentPrompts) > 0 {
+			base.Prompts.DocumentPrompts = override.Prompts.DocumentPrompts
+		}
 	}
 	if override.Resources != nil {
 		base.Resources.Enabled = override.Resources.Enabled
 		base.Resources.ExposeSwaggerDocs = override.Resources.ExposeSwaggerDocs
 		base.Resources.EnableDocumentationSearch = override.Resources.EnableDocumentationSearch
 		base.Resources.AllowEndpointDiscovery = override.Resources.AllowEndpointDiscovery
+		if override.Resources.URIScheme != "" {
+			base.Resources.URIScheme = override.Resources.URIScheme
+		}
+		if override.Resources.DocumentNaming != "" {
+			base.Resources.DocumentNaming = override.Resources.DocumentNaming
+		}
+		if len(override.Resources.DocumentIDs) > 0 {
+			base.Resources.DocumentIDs = override.Resources.DocumentIDs
+		}
+		if override.Resources.ContentCacheSize != 0 {
+			base.Resources.ContentCacheSize = override.Resources.ContentCacheSize
+		}
 	}
-
-	return base
-}
-
-// mergeOverrides merges override config into the resolved config
+	if override.GraphQL != nil {
+		if override.GraphQL.Endpoint != "" {
+			base.GraphQL.Endpoint = override.GraphQL.Endpoint
+		}
+	}
+	if override.GRPC != nil {
+		if override.GRPC.GatewayURL != "" {
+			base.GRPC.GatewayURL = override.GRPC.GatewayURL
+		}
+	}
+	if override.Mock != nil {
+		base.Mock.Enabled = override.Mock.Enabled
+		if len(override.Mock.Documents) > 0 {
+			base.Mock.Documents = override.Mock.Documents
+		}
+	}
+	if override.ResponseValidation != nil {
+		base.ResponseValidation.Enabled = override.ResponseValidation.Enabled
+	}
+	if len(override.ResponseFilters) > 0 {
+		base.ResponseFilters = override.ResponseFilters
+	}
+	if override.Cassette != nil {
+		if override.Cassette.Dir != "" {
+			base.Cassette.Dir = override.Cassette.Dir
+		}
+		if override.Cassette.Mode != "" {
+			base.Cassette.Mode = override.Cassette.Mode
+		}
+	}
+	if override.Pagination != nil {
+		if override.Pagination.MaxPages > 0 {
+			base.Pagination.MaxPages = override.Pagination.MaxPages
+		}
+	}
+	if override.ToolExecution != nil {
+		if override.ToolExecution.MaxResponseChars > 0 {
+			base.ToolExecution.MaxResponseChars = override.ToolExecution.MaxResponseChars
+		}
+	}
+	if override.Telemetry != nil {
+		if override.Telemetry.Mode != "" {
+			base.Telemetry.Mode = override.Telemetry.Mode
+		}
+		if override.Telemetry.Endpoint != "" {
+			base.Telemetry.Endpoint = override.Telemetry.Endpoint
+		}
+	}
+	if override.Update != nil {
+		if override.Update.Check {
+			base.Update.Check = override.Update.Check
+		}
+		if override.Update.Repository != "" {
+			base.Update.Repository = override.Update.Repository
+		}
+		if override.Update.APIURL != "" {
+			base.Update.APIURL = override.Update.APIURL
+		}
+	}
+	if len(override.Plugins) > 0 {
+		base.Plugins = override.Plugins
+	}
+	if len(override.CompositeTools) > 0 {
+		base.CompositeTools = override.CompositeTools
+	}
+	if len(override.ToolOverrides) > 0 {
+		base.ToolOverrides = override.ToolOverrides
+	}
+	if len(override.BaseURLs) > 0 {
+		base.BaseURLs = override.BaseURLs
+	}
+
+	return base
+}
+
+// mergeOverrides merges override config into the resolved config
 func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.ResolvedConfig) *types.ResolvedConfig {
 	if override.Name != "" {
 		base.Name = override.Name
@@ -396,6 +1150,12 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 	if len(override.SwaggerURLs) > 0 {
 		base.SwaggerURLs = override.SwaggerURLs
 	}
+	if len(override.ConfigChecksums) > 0 {
+		base.ConfigChecksums = override.ConfigChecksums
+	}
+	if override.ConfigRefresh > 0 {
+		base.ConfigRefresh = override.ConfigRefresh
+	}
 	if len(override.PackageIDs) > 0 {
 		base.PackageIDs = override.PackageIDs
 	}
@@ -411,6 +1171,92 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 	if override.Server.MaxTools > 0 {
 		base.Server.MaxTools = override.Server.MaxTools
 	}
+	if len(override.Server.Auth.BearerTokens) > 0 {
+		base.Server.Auth.BearerTokens = override.Server.Auth.BearerTokens
+	}
+	if override.Server.Auth.OIDCIssuer != "" {
+		base.Server.Auth.OIDCIssuer = override.Server.Auth.OIDCIssuer
+	}
+	if override.Server.Auth.OIDCAudience != "" {
+		base.Server.Auth.OIDCAudience = override.Server.Auth.OIDCAudience
+	}
+	if override.Server.Auth.Tenants != nil {
+		base.Server.Auth.Tenants = override.Server.Auth.Tenants
+	}
+	if override.Server.Port > 0 {
+		base.Server.Port = override.Server.Port
+	}
+	if override.Server.SnapshotPath != "" {
+		base.Server.SnapshotPath = override.Server.SnapshotPath
+	}
+	if override.Server.UsagePath != "" {
+		base.Server.UsagePath = override.Server.UsagePath
+	}
+	if override.Server.DocsAssetsURL != "" {
+		base.Server.DocsAssetsURL = override.Server.DocsAssetsURL
+	}
+	if len(override.Server.Transports) > 0 {
+		base.Server.Transports = override.Server.Transports
+	}
+	if override.Server.MCPHTTPPort > 0 {
+		base.Server.MCPHTTPPort = override.Server.MCPHTTPPort
+	}
+	if override.Server.MCPHTTPListen != "" {
+		base.Server.MCPHTTPListen = override.Server.MCPHTTPListen
+	}
+	if override.Server.Listen != "" {
+		base.Server.Listen = override.Server.Listen
+	}
+	if override.Server.SocketMode != "" {
+		base.Server.SocketMode = override.Server.SocketMode
+	}
+	if override.Server.MaxMessageSize > 0 {
+		base.Server.MaxMessageSize = override.Server.MaxMessageSize
+	}
+	if override.Server.Blobs.MaxInlineSize > 0 {
+		base.Server.Blobs.MaxInlineSize = override.Server.Blobs.MaxInlineSize
+	}
+	if override.Server.Blobs.MaxInlineTextSize > 0 {
+		base.Server.Blobs.MaxInlineTextSize = override.Server.Blobs.MaxInlineTextSize
+	}
+	if override.Server.Blobs.TTL > 0 {
+		base.Server.Blobs.TTL = override.Server.Blobs.TTL
+	}
+	if override.Server.Blobs.MaxStoredSize > 0 {
+		base.Server.Blobs.MaxStoredSize = override.Server.Blobs.MaxStoredSize
+	}
+	if len(override.Server.ToolPriority) > 0 {
+		base.Server.ToolPriority = override.Server.ToolPriority
+	}
+	if override.Server.Gateway {
+		base.Server.Gateway = override.Server.Gateway
+	}
+	if len(override.Server.Events.SensitiveFields) > 0 {
+		base.Server.Events.SensitiveFields = override.Server.Events.SensitiveFields
+	}
+	if override.Server.Events.MaxResultSize > 0 {
+		base.Server.Events.MaxResultSize = override.Server.Events.MaxResultSize
+	}
+	if override.Server.Events.Log.Path != "" {
+		base.Server.Events.Log.Path = override.Server.Events.Log.Path
+	}
+	if override.Server.Events.Log.MaxAge > 0 {
+		base.Server.Events.Log.MaxAge = override.Server.Events.Log.MaxAge
+	}
+	if override.Server.Events.Log.MaxEvents > 0 {
+		base.Server.Events.Log.MaxEvents = override.Server.Events.Log.MaxEvents
+	}
+	if override.Server.Quotas.PerMinute > 0 {
+		base.Server.Quotas.PerMinute = override.Server.Quotas.PerMinute
+	}
+	if override.Server.Quotas.PerDay > 0 {
+		base.Server.Quotas.PerDay = override.Server.Quotas.PerDay
+	}
+	if len(override.Server.Webhooks) > 0 {
+		base.Server.Webhooks = override.Server.Webhooks
+	}
+	mergeBusConfig(&base.Server.Bus, override.Server.Bus)
+	mergeSharedRegistryConfig(&base.Server.SharedRegistry, override.Server.SharedRegistry)
 	if override.HTTP.Timeout > 0 {
 		base.HTTP.Timeout = override.HTTP.Timeout
 	}
@@ -420,6 +1266,18 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 	if override.HTTP.UserAgent != "" {
 		base.HTTP.UserAgent = override.HTTP.UserAgent
 	}
+	if override.HTTP.HedgeDelay > 0 {
+		base.HTTP.HedgeDelay = override.HTTP.HedgeDelay
+	}
+	mergeTransportConfig(&base.HTTP.Transport, override.HTTP.Transport)
+	mergeDNSConfig(&base.HTTP.DNS, override.HTTP.DNS)
+	mergeConcurrencyConfig(&base.HTTP.Concurrency, override.HTTP.Concurrency)
+	if len(override.HTTP.AllowedHosts) > 0 {
+		base.HTTP.AllowedHosts = override.HTTP.AllowedHosts
+	}
+	if len(override.HTTP.DeniedHosts) > 0 {
+		base.HTTP.DeniedHosts = override.HTTP.DeniedHosts
+	}
 	if override.Auth.APIKey != "" {
 		base.Auth.APIKey = override.Auth.APIKey
 	}
@@ -429,6 +1287,9 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 	if override.Auth.Credentials != nil {
 		base.Auth.Credentials = override.Auth.Credentials
 	}
+	if len(override.Auth.Signing) > 0 {
+		base.Auth.Signing = override.Auth.Signing
+	}
 	if override.Debug {
 		base.Debug = override.Debug
 	}
@@ -436,6 +1297,9 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 		base.Logging.Level = override.Logging.Level
 	}
 	base.Logging.Enabled = override.Logging.Enabled
+	if override.Logging.EgressSampleRate > 0 {
+		base.Logging.EgressSampleRate = override.Logging.EgressSampleRate
+	}
 
 	// Tool Generation configuration
 	if override.ToolGeneration.IncludeDeprecated {
@@ -456,10 +1320,237 @@ func (m *Manager) mergeOverrides(base *types.ResolvedConfig, override *types.Res
 	if override.ToolGeneration.PreferFormat != "" {
 		base.ToolGeneration.PreferFormat = override.ToolGeneration.PreferFormat
 	}
+	if override.ToolGeneration.OmitFilterArgument {
+		base.ToolGeneration.OmitFilterArgument = override.ToolGeneration.OmitFilterArgument
+	}
+	if override.ToolGeneration.LazySchemas {
+		base.ToolGeneration.LazySchemas = override.ToolGeneration.LazySchemas
+	}
+	if override.ToolGeneration.SchemaCacheSize != 0 {
+		base.ToolGeneration.SchemaCacheSize = override.ToolGeneration.SchemaCacheSize
+	}
+	if len(override.ToolGeneration.DisabledTools) > 0 {
+		base.ToolGeneration.DisabledTools = override.ToolGeneration.DisabledTools
+	}
+	if override.ToolGeneration.DocumentFormats != nil {
+		base.ToolGeneration.DocumentFormats = override.ToolGeneration.DocumentFormats
+	}
+	if override.ToolGeneration.UnsupportedAuth != "" {
+		base.ToolGeneration.UnsupportedAuth = override.ToolGeneration.UnsupportedAuth
+	}
+	if override.ToolGeneration.DescriptionTemplate != "" {
+		base.ToolGeneration.DescriptionTemplate = override.ToolGeneration.DescriptionTemplate
+	}
+
+	// Swagger processing configuration
+	if override.SwaggerProcessing.RefreshInterval > 0 {
+		base.SwaggerProcessing.RefreshInterval = override.SwaggerProcessing.RefreshInterval
+	}
+	if override.SwaggerProcessing.StaleGracePeriod > 0 {
+		base.SwaggerProcessing.StaleGracePeriod = override.SwaggerProcessing.StaleGracePeriod
+	}
+	if len(override.SwaggerProcessing.AllowedRefHosts) > 0 {
+		base.SwaggerProcessing.AllowedRefHosts = override.SwaggerProcessing.AllowedRefHosts
+	}
+
+	// Prompts configuration
+	if override.Prompts.ConflictPolicy != "" {
+		base.Prompts.ConflictPolicy = override.Prompts.ConflictPolicy
+	}
+	if override.Prompts.TemplateDir != "" {
+		base.Prompts.TemplateDir = override.Prompts.TemplateDir
+	}
+	if len(override.Prompts.CategoryDefinitions) > 0 {
+		base.Prompts.CategoryDefinitions = override.Prompts.CategoryDefinitions
+	}
+	if override.Prompts.DisableWeatherCategories {
+		base.Prompts.DisableWeatherCategories = true
+	}
+	if len(override.Prompts.DocumentPrompts) > 0 {
+		base.Prompts.DocumentPrompts = override.Prompts.DocumentPrompts
+	}
+
+	// Resources configuration
+	if override.Resources.URIScheme != "" {
+		base.Resources.URIScheme = override.Resources.URIScheme
+	}
+	if override.Resources.DocumentNaming != "" {
+		base.Resources.DocumentNaming = override.Resources.DocumentNaming
+	}
+	if len(override.Resources.DocumentIDs) > 0 {
+		base.Resources.DocumentIDs = override.Resources.DocumentIDs
+	}
+	if override.Resources.ContentCacheSize != 0 {
+		base.Resources.ContentCacheSize = override.Resources.ContentCacheSize
+	}
+
+	// GraphQL configuration
+	if override.GraphQL.Endpoint != "" {
+		base.GraphQL.Endpoint = override.GraphQL.Endpoint
+	}
+
+	// gRPC configuration
+	if override.GRPC.GatewayURL != "" {
+		base.GRPC.GatewayURL = override.GRPC.GatewayURL
+	}
+
+	// Mock execution
+	if override.Mock.Enabled {
+		base.Mock.Enabled = override.Mock.Enabled
+	}
+	if len(override.Mock.Documents) > 0 {
+		base.Mock.Documents = override.Mock.Documents
+	}
+
+	// Response validation
+	if override.ResponseValidation.Enabled {
+		base.ResponseValidation.Enabled = override.ResponseValidation.Enabled
+	}
+
+	// Record/replay cassettes
+	if override.Cassette.Dir != "" {
+		base.Cassette.Dir = override.Cassette.Dir
+	}
+	if override.Cassette.Mode != "" {
+		base.Cassette.Mode = override.Cassette.Mode
+	}
+
+	// Pagination
+	if override.Pagination.MaxPages > 0 {
+		base.Pagination.MaxPages = override.Pagination.MaxPages
+	}
+
+	// Tool execution
+	if override.ToolExecution.MaxResponseChars > 0 {
+		base.ToolExecution.MaxResponseChars = override.ToolExecution.MaxResponseChars
+	}
+
+	// Telemetry
+	if override.Telemetry.Mode != "" {
+		base.Telemetry.Mode = override.Telemetry.Mode
+	}
+	if override.Telemetry.Endpoint != "" {
+		base.Telemetry.Endpoint = override.Telemetry.Endpoint
+	}
+
+	// Update check
+	if override.Update.Check {
+		base.Update.Check = override.Update.Check
+	}
+	if override.Update.Repository != "" {
+		base.Update.Repository = override.Update.Repository
+	}
+	if override.Update.APIURL != "" {
+		base.Update.APIURL = override.Update.APIURL
+	}
+
+	// Plugins
+	if len(override.Plugins) > 0 {
+		base.Plugins = override.Plugins
+	}
 
 	return base
 }
 
+// promptCategoryNamePattern matches the names prompt categories may have, as they are
+// part of prompt names
+var promptCategoryNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// resourceURISchemePattern matches URI schemes, per RFC 3986
+var resourceURISchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*$`)
+
+// resourceDocumentIDPattern matches the document IDs resource URIs may have as their host
+var resourceDocumentIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// mergeTransportConfig merges the transport settings that are set over the base ones
+func mergeTransportConfig(base *types.TransportConfig, override types.TransportConfig) {
+	if override.MaxIdleConns > 0 {
+		base.MaxIdleConns = override.MaxIdleConns
+	}
+	if override.MaxIdleConnsPerHost > 0 {
+		base.MaxIdleConnsPerHost = override.MaxIdleConnsPerHost
+	}
+	if override.MaxConnsPerHost > 0 {
+		base.MaxConnsPerHost = override.MaxConnsPerHost
+	}
+	if override.IdleConnTimeout > 0 {
+		base.IdleConnTimeout = override.IdleConnTimeout
+	}
+	if override.TLSHandshakeTimeout > 0 {
+		base.TLSHandshakeTimeout = override.TLSHandshakeTimeout
+	}
+	if override.DisableHTTP2 {
+		base.DisableHTTP2 = true
+	}
+}
+
+// mergeDNSConfig merges the DNS settings that are set over the base ones
+func mergeDNSConfig(base *types.DNSConfig, override types.DNSConfig) {
+	if override.CacheTTL > 0 {
+		base.CacheTTL = override.CacheTTL
+	}
+	if len(override.Servers) > 0 {
+		base.Servers = override.Servers
+	}
+	if len(override.Hosts) > 0 {
+		base.Hosts = override.Hosts
+	}
+}
+
+// mergeConcurrencyConfig merges the concurrency limits that are set over the base ones
+func mergeConcurrencyConfig(base *types.ConcurrencyConfig, override types.ConcurrencyConfig) {
+	if override.MaxRequests > 0 {
+		base.MaxRequests = override.MaxRequests
+	}
+	if override.MaxPerHost > 0 {
+		base.MaxPerHost = override.MaxPerHost
+	}
+	if override.QueueSize > 0 {
+		base.QueueSize = override.QueueSize
+	}
+	if override.QueueTimeout > 0 {
+		base.QueueTimeout = override.QueueTimeout
+	}
+	if override.Rejection != "" {
+		base.Rejection = override.Rejection
+	}
+}
+
+// mergeBusConfig overlays the set message bus settings of an override
+func mergeBusConfig(base *types.BusConfig, override types.BusConfig) {
+	if override.Type != "" {
+		base.Type = override.Type
+	}
+	if len(override.Brokers) > 0 {
+		base.Brokers = override.Brokers
+	}
+	if override.Topic != "" {
+		base.Topic = override.Topic
+	}
+	if override.Serialization != "" {
+		base.Serialization = override.Serialization
+	}
+	if len(override.Headers) > 0 {
+		base.Headers = override.Headers
+	}
+}
+
+// mergeSharedRegistryConfig overlays the set shared registry settings of an override
+func mergeSharedRegistryConfig(base *types.SharedRegistryConfig, override types.SharedRegistryConfig) {
+	if override.URL != "" {
+		base.URL = override.URL
+	}
+	if override.Key != "" {
+		base.Key = override.Key
+	}
+	if override.SyncInterval > 0 {
+		base.SyncInterval = override.SyncInterval
+	}
+	if override.RefreshLeaderOnly {
+		base.RefreshLeaderOnly = override.RefreshLeaderOnly
+	}
+}
+
 // validateConfig validates the final configuration
 func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 	var errors []string
@@ -481,6 +1572,10 @@ func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 		errors = append(errors, "at least one of swaggerPaths or swaggerUrls must be provided with a non-empty array")
 	}
 
+	if config.ConfigRefresh < 0 {
+		errors = append(errors, "config refresh interval must not be negative")
+	}
+
 	// Validate swagger URLs if provided
 	for _, swaggerURL := range config.SwaggerURLs {
 		if _, err := url.Parse(swaggerURL); err != nil {
@@ -496,6 +1591,78 @@ func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 	if config.Server.MaxTools <= 0 {
 		errors = append(errors, "server.maxTools must be a positive number")
 	}
+	seenTransports := make(map[types.Transport]bool)
+	for _, transport := range config.Server.Transports {
+		switch transport {
+		case types.TransportStdio, types.TransportSSE, types.TransportMCPHTTP:
+		default:
+			errors = append(errors, fmt.Sprintf("server.transports must hold stdio, sse, or mcp-http: %s", transport))
+		}
+		if seenTransports[transport] {
+			errors = append(errors, fmt.Sprintf("server.transports holds %s more than once", transport))
+		}
+		seenTransports[transport] = true
+	}
+	if config.Server.MCPHTTPPort < 0 {
+		errors = append(errors, "server.mcpHttpPort must be a non-negative number")
+	}
+	if config.Server.Blobs.MaxInlineSize < 0 || config.Server.Blobs.MaxInlineTextSize < 0 || config.Server.Blobs.MaxStoredSize < 0 {
+		errors = append(errors, "server.blobs maxInlineSize, maxInlineTextSize, and maxStoredSize must be non-negative")
+	}
+	if config.Server.Blobs.TTL <= 0 {
+		errors = append(errors, "server.blobs.ttl must be positive")
+	}
+	if config.Server.MaxMessageSize < 1024 {
+		errors = append(errors, "server.maxMessageSize must be at least 1024 bytes")
+	}
+	errors = append(errors, validateListenAddress("server.listen", config.Server.Listen)...)
+	errors = append(errors, validateListenAddress("server.mcpHttpListen", config.Server.MCPHTTPListen)...)
+	if config.Server.SocketMode != "" {
+		if mode, err := strconv.ParseUint(config.Server.SocketMode, 8, 32); err != nil || mode > 0777 {
+			errors = append(errors, fmt.Sprintf("server.socketMode must be an octal file mode such as 0660, got %s", config.Server.SocketMode))
+		}
+	}
+	for _, pattern := range config.Server.ToolPriority {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("server.toolPriority holds an invalid pattern: %s", pattern))
+		}
+	}
+	for _, pattern := range config.Server.Events.SensitiveFields {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("server.events.sensitiveFields holds an invalid pattern: %s", pattern))
+		}
+	}
+	if config.Server.Events.MaxResultSize < 0 {
+		errors = append(errors, "server.events.maxResultSize must be a non-negative number")
+	}
+	if config.Server.Events.Log.MaxAge < 0 || config.Server.Events.Log.MaxEvents < 0 {
+		errors = append(errors, "server.events.log maxAge and maxEvents must be non-negative")
+	}
+	if config.Server.Quotas.PerMinute < 0 || config.Server.Quotas.PerDay < 0 {
+		errors = append(errors, "server.quotas must be non-negative numbers")
+	}
+	for _, tenant := range config.Server.Auth.Tenants {
+		if tenant.Quotas.PerMinute < 0 || tenant.Quotas.PerDay < 0 {
+			errors = append(errors, fmt.Sprintf("server.auth.tenants quotas of %s must be non-negative numbers", tenant.Name))
+		}
+	}
+	errors = append(errors, validateBusConfig(config.Server.Bus)...)
+	errors = append(errors, validateSharedRegistryConfig(config.Server.SharedRegistry)...)
+	if config.Server.DocsAssetsURL != "" {
+		if parsed, err := url.Parse(config.Server.DocsAssetsURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errors = append(errors, fmt.Sprintf("server.docsAssetsUrl must be an http or https URL, got %s", config.Server.DocsAssetsURL))
+		}
+	}
+	for _, webhook := range config.Server.Webhooks {
+		if parsed, err := url.Parse(webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errors = append(errors, fmt.Sprintf("server.webhooks holds an invalid URL: %s", webhook.URL))
+		}
+		for _, event := range webhook.Events {
+			if event != types.WebhookToolExecution && event != types.WebhookToolError {
+				errors = append(errors, fmt.Sprintf("server.webhooks event must be %s or %s, got %s", types.WebhookToolExecution, types.WebhookToolError, event))
+			}
+		}
+	}
 
 	// Validate HTTP config
 	if config.HTTP.Timeout <= 0 {
@@ -504,6 +1671,338 @@ func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 	if config.HTTP.Retries < 0 {
 		errors = append(errors, "http.retries must be a non-negative number")
 	}
+	if config.HTTP.HedgeDelay < 0 {
+		errors = append(errors, "http.hedgeDelay must be a non-negative duration")
+	}
+	transport := config.HTTP.Transport
+	if transport.MaxIdleConns < 0 || transport.MaxIdleConnsPerHost < 0 || transport.MaxConnsPerHost < 0 {
+		errors = append(errors, "http.transport connection limits must be non-negative numbers")
+	}
+	if transport.IdleConnTimeout < 0 || transport.TLSHandshakeTimeout < 0 {
+		errors = append(errors, "http.transport timeouts must be non-negative durations")
+	}
+	if config.HTTP.DNS.CacheTTL < 0 {
+		errors = append(errors, "http.dns.cacheTtl must be a non-negative duration")
+	}
+	for _, server := range config.HTTP.DNS.Servers {
+		if strings.TrimSpace(server) == "" {
+			errors = append(errors, "http.dns.servers must not contain empty entries")
+			continue
+		}
+		// A server is "host:port", or a host or IP address without a port
+		if _, _, err := net.SplitHostPort(server); err != nil && strings.Contains(server, ":") && net.ParseIP(server) == nil {
+			errors = append(errors, fmt.Sprintf("invalid DNS server in http.dns.servers: %s", server))
+		}
+	}
+	for host, address := range config.HTTP.DNS.Hosts {
+		if net.ParseIP(address) == nil {
+			errors = append(errors, fmt.Sprintf("http.dns.hosts.%s must be an IP address, got %q", host, address))
+		}
+	}
+
+	concurrency := config.HTTP.Concurrency
+	if concurrency.MaxRequests < 0 || concurrency.MaxPerHost < 0 || concurrency.QueueSize < 0 {
+		errors = append(errors, "http.concurrency limits and queue size must be non-negative numbers")
+	}
+	if concurrency.QueueTimeout < 0 {
+		errors = append(errors, "http.concurrency.queueTimeout must be a non-negative duration")
+	}
+	switch concurrency.Rejection {
+	case "", types.RejectionError, types.RejectionBusy:
+	default:
+		errors = append(errors, fmt.Sprintf("http.concurrency.rejection must be error or busy: %s", concurrency.Rejection))
+	}
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{{"http.allowedHosts", config.HTTP.AllowedHosts}, {"http.deniedHosts", config.HTTP.DeniedHosts}} {
+		for _, pattern := range field.patterns {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				errors = append(errors, fmt.Sprintf("%s must not contain empty entries", field.name))
+				continue
+			}
+			if strings.Contains(pattern, "/") {
+				if _, _, err := net.ParseCIDR(pattern); err != nil {
+					errors = append(errors, fmt.Sprintf("invalid CIDR range in %s: %s", field.name, pattern))
+				}
+			} else if _, err := path.Match(pattern, ""); err != nil {
+				errors = append(errors, fmt.Sprintf("invalid host pattern in %s: %s", field.name, pattern))
+			}
+		}
+	}
+
+	// Validate tool generation config
+	if config.ToolGeneration.SchemaCacheSize < 0 {
+		errors = append(errors, "toolGeneration.schemaCacheSize must be a non-negative number")
+	}
+	for _, pattern := range config.ToolGeneration.DisabledTools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("toolGeneration.disabledTools holds an invalid pattern: %s", pattern))
+		}
+	}
+	switch config.ToolGeneration.UnsupportedAuth {
+	case "", types.UnsupportedAuthInclude, types.UnsupportedAuthMark, types.UnsupportedAuthSkip:
+	default:
+		errors = append(errors, fmt.Sprintf("toolGeneration.unsupportedAuth must be one of include, mark, skip: %s", config.ToolGeneration.UnsupportedAuth))
+	}
+	if config.ToolGeneration.DescriptionTemplate != "" {
+		// Executing the template catches references to fields that do not exist
+		descriptionTemplate, err := types.ParseDescriptionTemplate(config.ToolGeneration.DescriptionTemplate)
+		if err == nil {
+			err = descriptionTemplate.Execute(io.Discard, types.ToolDescriptionData{})
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("toolGeneration.descriptionTemplate is invalid: %s", err.Error()))
+		}
+	}
+	for key := range config.ToolGeneration.DocumentFormats {
+		if _, err := filepath.Match(key, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern in toolGeneration.documentFormats: %s", key))
+		}
+	}
+
+	// Validate swagger processing config
+	if config.SwaggerProcessing.RefreshInterval < 0 {
+		errors = append(errors, "swaggerProcessing.refreshInterval must be a non-negative duration")
+	}
+	if config.SwaggerProcessing.StaleGracePeriod < 0 {
+		errors = append(errors, "swaggerProcessing.staleGracePeriod must be a non-negative duration")
+	}
+
+	// Validate prompts config
+	switch config.Prompts.ConflictPolicy {
+	case "", types.PromptConflictError, types.PromptConflictSuffix, types.PromptConflictKeepFirst:
+	default:
+		errors = append(errors, fmt.Sprintf("prompts.conflictPolicy must be one of error, suffix-with-document, keep-first: %s", config.Prompts.ConflictPolicy))
+	}
+	categoryNames := make(map[string]bool)
+	for i, definition := range config.Prompts.CategoryDefinitions {
+		if !promptCategoryNamePattern.MatchString(definition.Name) {
+			errors = append(errors, fmt.Sprintf("prompts.categoryDefinitions[%d].name must consist of letters, digits, hyphens, and underscores: %q", i, definition.Name))
+		} else if categoryNames[definition.Name] {
+			errors = append(errors, fmt.Sprintf("prompts.categoryDefinitions[%d].name is defined more than once: %s", i, definition.Name))
+		}
+		categoryNames[definition.Name] = true
+		if len(definition.Keywords) == 0 {
+			errors = append(errors, fmt.Sprintf("prompts.categoryDefinitions[%d].keywords must not be empty", i))
+		}
+	}
+	for key, definitions := range config.Prompts.DocumentPrompts {
+		if _, err := filepath.Match(key, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern in prompts.documentPrompts: %s", key))
+		}
+		for i, definition := range definitions {
+			if definition.Name == "" || strings.Contains(definition.Name, "@") {
+				errors = append(errors, fmt.Sprintf("prompts.documentPrompts[%s][%d].name must be set and must not contain @: %q", key, i, definition.Name))
+			}
+			if strings.TrimSpace(definition.Template) == "" && len(definition.Messages) == 0 {
+				errors = append(errors, fmt.Sprintf("prompts.documentPrompts[%s][%d] must have a template or messages", key, i))
+			}
+		}
+	}
+
+	// Validate resources config
+	if config.Resources.URIScheme != "" && !resourceURISchemePattern.MatchString(config.Resources.URIScheme) {
+		errors = append(errors, fmt.Sprintf("resources.uriScheme is not a valid URI scheme: %s", config.Resources.URIScheme))
+	}
+	switch config.Resources.DocumentNaming {
+	case "", types.ResourceNamingFileName, types.ResourceNamingFileNameHash:
+	default:
+		errors = append(errors, fmt.Sprintf("resources.documentNaming must be one of file-name, file-name-hash: %s", config.Resources.DocumentNaming))
+	}
+	if config.Resources.ContentCacheSize < 0 {
+		errors = append(errors, "resources.contentCacheSize must be a non-negative number")
+	}
+	for key, id := range config.Resources.DocumentIDs {
+		if _, err := filepath.Match(key, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern in resources.documentIds: %s", key))
+		}
+		if !resourceDocumentIDPattern.MatchString(id) {
+			errors = append(errors, fmt.Sprintf("resources.documentIds[%s] must consist of letters, digits, dots, hyphens, and underscores: %q", key, id))
+		}
+	}
+
+	// Validate GraphQL config
+	if config.GraphQL.Endpoint != "" {
+		if parsed, err := url.Parse(config.GraphQL.Endpoint); err != nil || !parsed.IsAbs() {
+			errors = append(errors, fmt.Sprintf("graphql.endpoint must be an absolute URL: %s", config.GraphQL.Endpoint))
+		}
+	}
+
+	// Validate MCP HTTP auth config
+	if config.Server.Auth.OIDCIssuer != "" {
+		if parsed, err := url.Parse(config.Server.Auth.OIDCIssuer); err != nil || !parsed.IsAbs() {
+			errors = append(errors, fmt.Sprintf("server.auth.oidcIssuer must be an absolute URL: %s", config.Server.Auth.OIDCIssuer))
+		}
+		if config.Server.Auth.OIDCAudience == "" {
+			errors = append(errors, "server.auth.oidcAudience is required when server.auth.oidcIssuer is set")
+		}
+	}
+	for key, tenant := range config.Server.Auth.Tenants {
+		if strings.TrimSpace(key) == "" {
+			errors = append(errors, "server.auth.tenants keys must not be empty")
+			continue
+		}
+		if tenant.Name == "" {
+			errors = append(errors, "server.auth.tenants entries must have a name")
+		}
+	}
+
+	// Validate gRPC config
+	if config.GRPC.GatewayURL != "" {
+		if parsed, err := url.Parse(config.GRPC.GatewayURL); err != nil || !parsed.IsAbs() {
+			errors = append(errors, fmt.Sprintf("grpc.gatewayUrl must be an absolute URL: %s", config.GRPC.GatewayURL))
+		}
+	}
+
+	// Validate mock config
+	for _, pattern := range config.Mock.Documents {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern in mock.documents: %s", pattern))
+		}
+	}
+
+	// Validate credentials
+	for key, credential := range config.Auth.Credentials {
+		if _, err := filepath.Match(key, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern in auth.credentials: %s", key))
+		}
+		switch credential.Scheme {
+		case "", types.CredentialSchemeBearer, types.CredentialSchemeAPIKey, types.CredentialSchemeQuery:
+		case types.CredentialSchemeBasic:
+			if !strings.Contains(credential.Secret, ":") {
+				errors = append(errors, fmt.Sprintf("auth.credentials.%s.secret must have the form user:password", key))
+			}
+		case types.CredentialSchemeJWT:
+			if credential.JWT.Issuer == "" {
+				errors = append(errors, fmt.Sprintf("auth.credentials.%s.jwt.issuer must be a non-empty string", key))
+			}
+			if credential.JWT.Audience == "" {
+				errors = append(errors, fmt.Sprintf("auth.credentials.%s.jwt.audience must be a non-empty string", key))
+			}
+			if credential.JWT.TTL < 0 {
+				errors = append(errors, fmt.Sprintf("auth.credentials.%s.jwt.ttl must be a non-negative duration", key))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("auth.credentials.%s.scheme must be one of bearer, apikey, basic, query, jwt: %s", key, credential.Scheme))
+		}
+		// The private key of a jwt credential may be read from a file instead
+		if credential.Secret == "" && (credential.Scheme != types.CredentialSchemeJWT || credential.JWT.PrivateKeyFile == "") {
+			errors = append(errors, fmt.Sprintf("auth.credentials.%s.secret must be a non-empty string", key))
+		}
+	}
+
+	// Validate request signing
+	for i, signing := range config.Auth.Signing {
+		if signing.Key == "" {
+			errors = append(errors, fmt.Sprintf("auth.signing[%d].key must be a non-empty string", i))
+		}
+		switch signing.Algorithm {
+		case "", types.SigningAlgorithmSHA256, types.SigningAlgorithmSHA1, types.SigningAlgorithmSHA512:
+		default:
+			errors = append(errors, fmt.Sprintf("auth.signing[%d].algorithm must be one of hmac-sha256, hmac-sha1, hmac-sha512: %s", i, signing.Algorithm))
+		}
+		switch signing.Encoding {
+		case "", "hex", "base64":
+		default:
+			errors = append(errors, fmt.Sprintf("auth.signing[%d].encoding must be one of hex, base64: %s", i, signing.Encoding))
+		}
+		for _, host := range signing.Hosts {
+			if _, err := filepath.Match(host, ""); err != nil {
+				errors = append(errors, fmt.Sprintf("invalid pattern in auth.signing[%d].hosts: %s", i, host))
+			}
+		}
+	}
+
+	// Validate response filters
+	for host, baseURL := range config.BaseURLs {
+		if parsed, err := url.Parse(baseURL); err != nil || !parsed.IsAbs() || parsed.Host == "" {
+			errors = append(errors, fmt.Sprintf("baseUrls.%s must be an absolute URL: %s", host, baseURL))
+		}
+	}
+
+	for toolName, expression := range config.ResponseFilters {
+		if _, err := jmespath.Compile(expression); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid JMESPath expression in responseFilters for %s: %v", toolName, err))
+		}
+	}
+
+	// Validate cassette config
+	switch config.Cassette.Mode {
+	case "", types.CassetteModeAuto, types.CassetteModeRecord, types.CassetteModeReplay:
+	default:
+		errors = append(errors, fmt.Sprintf("cassette.mode must be one of auto, record, replay: %s", config.Cassette.Mode))
+	}
+
+	// Validate pagination config
+	if config.Pagination.MaxPages <= 0 {
+		errors = append(errors, "pagination.maxPages must be a positive number")
+	}
+
+	// Validate tool execution config; a limit below the marker would grow responses
+	if config.ToolExecution.MaxResponseChars < 0 || (config.ToolExecution.MaxResponseChars > 0 && config.ToolExecution.MaxResponseChars < 100) {
+		errors = append(errors, "toolExecution.maxResponseChars must be 0 or at least 100")
+	}
+
+	// Validate telemetry config
+	switch config.Telemetry.Mode {
+	case "", types.TelemetryOff:
+	case types.TelemetryOn:
+		if config.Telemetry.Endpoint == "" {
+			errors = append(errors, "telemetry.endpoint is required when telemetry is on")
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("telemetry.mode must be one of off, on: %s", config.Telemetry.Mode))
+	}
+	if config.Telemetry.Endpoint != "" {
+		if parsed, err := url.Parse(config.Telemetry.Endpoint); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errors = append(errors, fmt.Sprintf("telemetry.endpoint must be an http or https URL: %s", config.Telemetry.Endpoint))
+		}
+	}
+
+	// Validate plugins
+	for i, plugin := range config.Plugins {
+		switch plugin.Hook {
+		case types.PluginHookPreRequest, types.PluginHookAuth, types.PluginHookPostResponse:
+		default:
+			errors = append(errors, fmt.Sprintf("plugins[%d].hook must be one of pre-request, auth, post-response: %s", i, plugin.Hook))
+		}
+		if len(plugin.Command) == 0 || plugin.Command[0] == "" {
+			errors = append(errors, fmt.Sprintf("plugins[%d].command must name an executable", i))
+		}
+		if plugin.Timeout < 0 {
+			errors = append(errors, fmt.Sprintf("plugins[%d].timeout must be a non-negative duration", i))
+		}
+	}
+
+	// Validate update config
+	if repository := config.Update.Repository; repository != "" {
+		if parts := strings.Split(repository, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errors = append(errors, fmt.Sprintf("update.repository must be owner/name: %s", repository))
+		}
+	}
+	if config.Update.APIURL != "" {
+		if parsed, err := url.Parse(config.Update.APIURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errors = append(errors, fmt.Sprintf("update.apiUrl must be an http or https URL: %s", config.Update.APIURL))
+		}
+	}
+
+	// Validate tool overrides
+	for toolName, toolOverride := range config.ToolOverrides {
+		if toolOverride.Timeout < 0 {
+			errors = append(errors, fmt.Sprintf("toolOverrides.%s.timeout must be a non-negative duration", toolName))
+		}
+		if toolOverride.Retries != nil && *toolOverride.Retries < 0 {
+			errors = append(errors, fmt.Sprintf("toolOverrides.%s.retries must be a non-negative number", toolName))
+		}
+		if toolOverride.HedgeDelay < 0 {
+			errors = append(errors, fmt.Sprintf("toolOverrides.%s.hedgeDelay must be a non-negative duration", toolName))
+		}
+	}
+
+	// Validate composite tools
+	errors = append(errors, validateCompositeTools(config.CompositeTools)...)
 
 	// Validate logging config
 	validLevels := []string{"error", "warn", "info", "debug"}
@@ -517,6 +2016,9 @@ func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 	if !validLevel {
 		errors = append(errors, fmt.Sprintf("logging.level must be one of: %s", strings.Join(validLevels, ", ")))
 	}
+	if config.Logging.EgressSampleRate < 0 || config.Logging.EgressSampleRate > 1 {
+		errors = append(errors, "logging.egressSampleRate must be between 0 and 1")
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf(strings.Join(errors, "; "))
@@ -524,3 +2026,143 @@ func (m *Manager) validateConfig(config *types.ResolvedConfig) error {
 
 	return nil
 }
+
+// validateSharedRegistryConfig checks the shared registry configuration
+func validateSharedRegistryConfig(registry types.SharedRegistryConfig) []string {
+	if registry.URL == "" {
+		if registry.RefreshLeaderOnly {
+			return []string{"server.sharedRegistry.refreshLeaderOnly requires server.sharedRegistry.url"}
+		}
+		return nil
+	}
+
+	var errors []string
+	if parsed, err := url.Parse(registry.URL); err != nil || (parsed.Scheme != "redis" && parsed.Scheme != "rediss") || parsed.Host == "" {
+		errors = append(errors, fmt.Sprintf("server.sharedRegistry.url must be a redis:// or rediss:// URL, got %s", registry.URL))
+	} else if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if n, err := strconv.Atoi(db); err != nil || n < 0 {
+			errors = append(errors, fmt.Sprintf("server.sharedRegistry.url must name the database as a number, got %s", db))
+		}
+	}
+	if registry.Key == "" || strings.ContainsAny(registry.Key, " \t\r\n") {
+		errors = append(errors, "server.sharedRegistry.key must be set, without whitespace")
+	}
+	if registry.SyncInterval < time.Second {
+		errors = append(errors, "server.sharedRegistry.syncInterval must be at least 1s")
+	}
+	return errors
+}
+
+// validateListenAddress checks a listen address, either host:port or unix:/path.sock
+func validateListenAddress(field, address string) []string {
+	if address == "" {
+		return nil
+	}
+	if socketPath, ok := strings.CutPrefix(address, "unix:"); ok {
+		if socketPath == "" {
+			return []string{fmt.Sprintf("%s must name the socket file after unix:", field)}
+		}
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(address); err != nil || port == "" {
+		return []string{fmt.Sprintf("%s must be host:port or unix:/path.sock, got %s", field, address)}
+	}
+	return nil
+}
+
+// validateBusConfig checks the message bus configuration, if a bus is set
+func validateBusConfig(bus types.BusConfig) []string {
+	var errors []string
+	switch bus.Type {
+	case "":
+		return nil
+	case types.BusNATS, types.BusKafka:
+	default:
+		return []string{fmt.Sprintf("server.bus.type must be %s or %s, got %s", types.BusNATS, types.BusKafka, bus.Type)}
+	}
+
+	if len(bus.Brokers) == 0 {
+		errors = append(errors, "server.bus.brokers must list at least one broker")
+	}
+	for _, broker := range bus.Brokers {
+		parsed, err := url.Parse(broker)
+		valid := err == nil && parsed.Host != ""
+		if bus.Type == types.BusNATS {
+			valid = valid && (parsed.Scheme == "nats" || parsed.Scheme == "tls")
+		} else {
+			valid = valid && (parsed.Scheme == "http" || parsed.Scheme == "https")
+		}
+		if !valid {
+			errors = append(errors, fmt.Sprintf("server.bus.brokers holds an invalid %s broker URL: %s", bus.Type, broker))
+		}
+	}
+	if bus.Topic == "" || strings.ContainsAny(bus.Topic, " \t\r\n") {
+		errors = append(errors, "server.bus.topic must be set, without whitespace")
+	}
+	if bus.Serialization != "" && bus.Serialization != types.BusSerializationJSON && bus.Serialization != types.BusSerializationCloudEvents {
+		errors = append(errors, fmt.Sprintf("server.bus.serialization must be %s or %s, got %s", types.BusSerializationJSON, types.BusSerializationCloudEvents, bus.Serialization))
+	}
+	return errors
+}
+
+// validateCompositeTools returns the problems of composite tool definitions: missing
+// names, tools, or step IDs, duplicates, and template expressions that are not valid
+// JMESPath
+func validateCompositeTools(composites []types.CompositeToolConfig) []string {
+	var errors []string
+	names := make(map[string]bool)
+	for i, composite := range composites {
+		if composite.Name == "" {
+			errors = append(errors, fmt.Sprintf("compositeTools[%d].name must be a non-empty string", i))
+		} else if names[composite.Name] {
+			errors = append(errors, fmt.Sprintf("compositeTools[%d].name is a duplicate: %s", i, composite.Name))
+		}
+		names[composite.Name] = true
+
+		if len(composite.Steps) == 0 {
+			errors = append(errors, fmt.Sprintf("compositeTools[%d].steps must not be empty", i))
+		}
+		ids := make(map[string]bool)
+		for j, step := range composite.Steps {
+			if step.ID == "" {
+				errors = append(errors, fmt.Sprintf("compositeTools[%d].steps[%d].id must be a non-empty string", i, j))
+			} else if ids[step.ID] {
+				errors = append(errors, fmt.Sprintf("compositeTools[%d].steps[%d].id is a duplicate: %s", i, j, step.ID))
+			}
+			ids[step.ID] = true
+			if step.Tool == "" {
+				errors = append(errors, fmt.Sprintf("compositeTools[%d].steps[%d].tool must be a non-empty string", i, j))
+			}
+			for _, err := range templateErrors(step.Arguments) {
+				errors = append(errors, fmt.Sprintf("compositeTools[%d].steps[%d].arguments: %s", i, j, err))
+			}
+		}
+		for _, err := range templateErrors(composite.Output) {
+			errors = append(errors, fmt.Sprintf("compositeTools[%d].output: %s", i, err))
+		}
+	}
+	return errors
+}
+
+// templateErrors returns the invalid JMESPath expressions of the placeholders in the
+// strings of a composite tool template
+func templateErrors(template interface{}) []string {
+	var errors []string
+	switch value := template.(type) {
+	case string:
+		for _, match := range types.CompositeTemplatePlaceholder.FindAllStringSubmatch(value, -1) {
+			if _, err := jmespath.Compile(match[1]); err != nil {
+				errors = append(errors, fmt.Sprintf("invalid expression %q: %v", match[1], err))
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			errors = append(errors, templateErrors(item)...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			errors = append(errors, templateErrors(item)...)
+		}
+	}
+	return errors
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// redactedValue replaces the secrets of a redacted configuration
+const redactedValue = "[REDACTED]"
+
+// Redact returns a copy of a configuration with its secrets replaced, so it can be
+// shown without disclosing them: the API key, credential secrets, signing keys, bearer
+// tokens, webhook and message bus header values, credentials in message bus broker
+// and shared registry URLs, and the tenant keys, which become "[REDACTED 1]", "[REDACTED 2]", and so on
+// in the order of the tenant names.
+func Redact(config *types.ResolvedConfig) *types.ResolvedConfig {
+	redacted := *config
+
+	redacted.Auth.APIKey = redactString(config.Auth.APIKey)
+	if config.Auth.Credentials != nil {
+		redacted.Auth.Credentials = make(map[string]types.CredentialConfig, len(config.Auth.Credentials))
+		for key, credential := range config.Auth.Credentials {
+			credential.Secret = redactString(credential.Secret)
+			redacted.Auth.Credentials[key] = credential
+		}
+	}
+	if config.Auth.Signing != nil {
+		redacted.Auth.Signing = make([]types.SigningConfig, len(config.Auth.Signing))
+		for i, signing := range config.Auth.Signing {
+			signing.Key = redactString(signing.Key)
+			redacted.Auth.Signing[i] = signing
+		}
+	}
+
+	if config.Server.Auth.BearerTokens != nil {
+		redacted.Server.Auth.BearerTokens = make([]string, len(config.Server.Auth.BearerTokens))
+		for i, token := range config.Server.Auth.BearerTokens {
+			redacted.Server.Auth.BearerTokens[i] = redactString(token)
+		}
+	}
+	if config.Server.Auth.Tenants != nil {
+		keys := make([]string, 0, len(config.Server.Auth.Tenants))
+		for key := range config.Server.Auth.Tenants {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return config.Server.Auth.Tenants[keys[i]].Name < config.Server.Auth.Tenants[keys[j]].Name
+		})
+		redacted.Server.Auth.Tenants = make(map[string]types.TenantConfig, len(keys))
+		for i, key := range keys {
+			redacted.Server.Auth.Tenants[fmt.Sprintf("[REDACTED %d]", i+1)] = config.Server.Auth.Tenants[key]
+		}
+	}
+
+	if config.Server.Webhooks != nil {
+		redacted.Server.Webhooks = make([]types.WebhookConfig, len(config.Server.Webhooks))
+		for i, webhook := range config.Server.Webhooks {
+			if webhook.Headers != nil {
+				headers := make(map[string]string, len(webhook.Headers))
+				for name, value := range webhook.Headers {
+					headers[name] = redactString(value)
+				}
+				webhook.Headers = headers
+			}
+			redacted.Server.Webhooks[i] = webhook
+		}
+	}
+
+	if config.Server.Bus.Brokers != nil {
+		redacted.Server.Bus.Brokers = make([]string, len(config.Server.Bus.Brokers))
+		for i, broker := range config.Server.Bus.Brokers {
+			redacted.Server.Bus.Brokers[i] = redactURL(broker)
+		}
+	}
+	redacted.Server.SharedRegistry.URL = redactURL(config.Server.SharedRegistry.URL)
+	if config.Server.Bus.Headers != nil {
+		redacted.Server.Bus.Headers = make(map[string]string, len(config.Server.Bus.Headers))
+		for name, value := range config.Server.Bus.Headers {
+			redacted.Server.Bus.Headers[name] = redactString(value)
+		}
+	}
+
+	return &redacted
+}
+
+// redactString replaces a secret, leaving an unset one empty
+func redactString(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// redactURL replaces the credentials in the user info of a URL
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+	parsed.User = nil
+	return parsed.Scheme + "://" + redactedValue + "@" + strings.TrimPrefix(parsed.String(), parsed.Scheme+"://")
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	httpclient "swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/types"
+)
+
+const (
+	// remoteConfigTimeout bounds the fetch of a remote config file or checksum file
+	remoteConfigTimeout = 30 * time.Second
+	// maxRemoteConfigSize bounds the size of a remote config file or checksum file
+	maxRemoteConfigSize = 10 << 20
+)
+
+// IsRemoteConfig reports whether a config file path is an http(s) URL
+func IsRemoteConfig(configPath string) bool {
+	lower := strings.ToLower(configPath)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// remoteConfigExt returns the extension of the path of a config URL, which selects its
+// format like the extension of a local file
+func remoteConfigExt(configURL string) string {
+	parsed, err := url.Parse(configURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Ext(parsed.Path))
+}
+
+// isPlainHTTP reports whether a URL is fetched over unencrypted http
+func isPlainHTTP(rawURL string) bool {
+	return strings.HasPrefix(strings.ToLower(rawURL), "http://")
+}
+
+// remoteConfigClient returns the client remote config files and checksum files are
+// fetched with. It applies the outbound host policy of the environment and flags, as
+// the fetched files cannot be trusted to set their own, allowing the hosts of the
+// config and checksum URLs given like the configured swagger URLs.
+func remoteConfigClient(configPaths []string, checksums []string, envConfig *types.ResolvedConfig, overrides *types.ResolvedConfig) *http.Client {
+	policyConfig := &types.ResolvedConfig{HTTP: envConfig.HTTP}
+	if overrides != nil {
+		if len(overrides.HTTP.AllowedHosts) > 0 {
+			policyConfig.HTTP.AllowedHosts = overrides.HTTP.AllowedHosts
+		}
+		if len(overrides.HTTP.DeniedHosts) > 0 {
+			policyConfig.HTTP.DeniedHosts = overrides.HTTP.DeniedHosts
+		}
+	}
+	for _, rawURL := range append(append([]string{}, configPaths...), checksums...) {
+		if IsRemoteConfig(rawURL) {
+			policyConfig.SwaggerURLs = append(policyConfig.SwaggerURLs, rawURL)
+		}
+	}
+	return httpclient.NewHostPolicy(policyConfig).Client(remoteConfigTimeout)
+}
+
+// fetchRemote downloads the content of a URL, up to maxRemoteConfigSize bytes
+func (m *Manager) fetchRemote(rawURL string) ([]byte, error) {
+	client := m.remoteClient
+	if client == nil {
+		client = httpclient.NewHostPolicy(&types.ResolvedConfig{}).Client(remoteConfigTimeout)
+	}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxRemoteConfigSize)
+	}
+	return content, nil
+}
+
+// fetchRemoteConfig downloads a remote config file and verifies it against the
+// checksums, when any are given. Config files may declare plugin commands, webhooks,
+// and credentials, so plain http URLs, which anyone in the network path can tamper
+// with, are only loaded when verified by a checksum.
+func (m *Manager) fetchRemoteConfig(configURL string, checksums []string) ([]byte, error) {
+	if isPlainHTTP(configURL) && len(checksums) == 0 {
+		return nil, fmt.Errorf("refusing to load config file %s over plain http without --config-checksum; use https or pin its checksum", configURL)
+	}
+	content, err := m.fetchRemote(configURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config file %s: %w", configURL, err)
+	}
+
+	digest := sha256.Sum256(content)
+	if len(checksums) > 0 {
+		accepted, err := m.acceptedDigests(checksums)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted[hex.EncodeToString(digest[:])] {
+			return nil, fmt.Errorf("config file %s does not match any configured checksum (sha256:%s)",
+				configURL, hex.EncodeToString(digest[:]))
+		}
+	}
+
+	if m.remoteDigests == nil {
+		m.remoteDigests = make(map[string][sha256.Size]byte)
+	}
+	m.remoteDigests[configURL] = digest
+	return content, nil
+}
+
+// acceptedDigests collects the SHA-256 digests remote config files may have. Each
+// checksum is either "sha256:<hex>" or the URL of a checksum file listing digests in
+// sha256sum format, which is fetched again on every load so it can follow updates.
+// Checksum files must be fetched over https, as one fetched over plain http verifies
+// nothing.
+func (m *Manager) acceptedDigests(checksums []string) (map[string]bool, error) {
+	accepted := make(map[string]bool)
+	for _, checksum := range checksums {
+		if IsRemoteConfig(checksum) {
+			if isPlainHTTP(checksum) {
+				return nil, fmt.Errorf("refusing to fetch config checksum file %s over plain http; use https", checksum)
+			}
+			content, err := m.fetchRemote(checksum)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch config checksum file %s: %w", checksum, err)
+			}
+			scanner := bufio.NewScanner(bytes.NewReader(content))
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+				if len(fields) == 0 {
+					continue
+				}
+				digest, err := parseDigest(strings.TrimPrefix(fields[0], "sha256:"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid config checksum file %s: %w", checksum, err)
+				}
+				accepted[digest] = true
+			}
+			continue
+		}
+
+		hexDigest, found := strings.CutPrefix(checksum, "sha256:")
+		if !found {
+			return nil, fmt.Errorf("invalid config checksum %q: use sha256:<hex> or the URL of a checksum file", checksum)
+		}
+		digest, err := parseDigest(hexDigest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config checksum %q: %w", checksum, err)
+		}
+		accepted[digest] = true
+	}
+	return accepted, nil
+}
+
+// parseDigest validates a hex SHA-256 digest and returns it in lower case
+func parseDigest(value string) (string, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("%q is not a hex SHA-256 digest", value)
+	}
+	return hex.EncodeToString(decoded), nil
+}
+
+// HasRemoteConfig reports whether the last load fetched any remote config file
+func (m *Manager) HasRemoteConfig() bool {
+	return len(m.remoteDigests) > 0
+}
+
+// RemoteConfigChanged reports whether any remote config file fetched by the last load
+// now has different content. Changed content is not verified here; loading it again
+// verifies it.
+func (m *Manager) RemoteConfigChanged() (bool, error) {
+	for configURL, loaded := range m.remoteDigests {
+		content, err := m.fetchRemote(configURL)
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch config file %s: %w", configURL, err)
+		}
+		if sha256.Sum256(content) != loaded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// scaffoldTemplate is the starter config file written by config init
+var scaffoldTemplate = template.Must(template.New("swagger-mcp.config.yaml").Funcs(template.FuncMap{
+	"yaml": scaffoldValue,
+}).Parse(`# swagger-docs-mcp configuration, generated by "swagger-docs-mcp config init".
+# Environment variables and flags override these settings. Values may refer to
+# environment variables as ${VAR}, or ${VAR:-default} to fall back to a default.
+
+name: {{ yaml .Name }}
+version: {{ yaml .Version }}
+
+# Swagger documents to generate tools from: files or directories of local documents,
+# and URLs of remote ones. At least one path or URL is required.
+swaggerPaths: {{ yaml .SwaggerPaths }}
+swaggerUrls: {{ yaml .SwaggerURLs }}
+
+# Only generate tools for the documents of these package IDs
+packageIds: {{ yaml .PackageIDs }}
+
+# Only generate tools for the documents matching these TWC metadata filters
+twcFilters:
+  portfolios: {{ yaml .TWCFilters.Portfolios }}
+  domains: {{ yaml .TWCFilters.Domains }}
+  usageClassifications: {{ yaml .TWCFilters.UsageClassifications }}
+  geographies: {{ yaml .TWCFilters.Geographies }}
+{{- if .DynamicFilters }}
+
+# Only generate tools for the documents whose metadata matches these filters
+dynamicFilters: {{ yaml .DynamicFilters }}
+{{- end }}
+
+auth:
+  # Keep the API key out of this file: set WX_MCP_API_KEY, or refer to a variable
+  # apiKey: ${WX_MCP_API_KEY}
+{{- if .Auth.DefaultScheme }}
+  defaultScheme: {{ yaml .Auth.DefaultScheme }}
+{{- end }}
+
+server:
+  # Port of the SSE and MCP HTTP servers
+  port: {{ .Server.Port }}
+  timeout: {{ .Server.Timeout }}
+  # Tools of the lowest priority beyond this number are dropped
+  maxTools: {{ .Server.MaxTools }}
+
+http:
+  # Timeout and retries of the requests tool calls make to the APIs
+  timeout: {{ .HTTP.Timeout }}
+  retries: {{ .HTTP.Retries }}
+  userAgent: {{ yaml .HTTP.UserAgent }}
+
+logging:
+  enabled: {{ .Logging.Enabled }}
+  # error, warn, info, or debug
+  level: {{ yaml .Logging.Level }}
+`))
+
+// Scaffold renders a commented starter config file holding the settings of the
+// environment variables and flags over the defaults, so that a long command line can
+// move into a config file. The API key is left out, to keep it out of the file.
+func (m *Manager) Scaffold(overrides *types.ResolvedConfig) ([]byte, error) {
+	config := m.mergeOverrides(types.DefaultConfig(), m.loadEnvironmentConfig())
+	if overrides != nil {
+		config = m.mergeOverrides(config, overrides)
+	}
+	if config.TWCFilters == nil {
+		config.TWCFilters = &types.TWCFilters{}
+	}
+
+	var content bytes.Buffer
+	if err := scaffoldTemplate.Execute(&content, config); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// scaffoldValue renders a value of the starter config file on one line, quoted where
+// YAML requires
+func scaffoldValue(value interface{}) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", err
+	}
+	node.Style = yaml.FlowStyle
+
+	encoded, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(encoded)), nil
+}
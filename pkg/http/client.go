@@ -2,11 +2,16 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,6 +25,15 @@ type Client struct {
 	config     *types.ResolvedConfig
 	logger     *utils.Logger
 	httpClient *http.Client
+	signers    []RequestSigner
+	plugins    []*Plugin
+	jwts       jwtAssertions
+	// hosts restricts the hosts requests are sent to, or is nil to allow any host
+	hosts *HostPolicy
+	// limiter bounds concurrent requests, or is nil when they are unlimited
+	limiter *concurrencyLimiter
+	// sessionKey replaces the API key and the secrets of the credentials when set
+	sessionKey string
 }
 
 // Response represents an HTTP response
@@ -27,43 +41,230 @@ type Response struct {
 	StatusCode int
 	Headers    map[string]string
 	Body       []byte
+	// URL is the final URL of the request, after redirects, with credentials in its
+	// query redacted. It is empty for responses not received from the API.
+	URL string
+	// Source is where the response came from, one of the Source constants
+	Source string
+	// Warnings are problems found with the response that do not fail the call, e.g.
+	// mismatches with the response schema its endpoint declares
+	Warnings []string
+	// Attempts is the number of times the request was sent, retries included. It is
+	// zero for responses that were not received from the API, e.g. mocked ones.
+	Attempts int
+	// Retries is the number of attempts that failed before the response was received
+	Retries int
+	// Duration is the time from sending the first attempt to receiving the response,
+	// the backoff between retries included
+	Duration time.Duration
+}
+
+// Sources of responses
+const (
+	// SourceUpstream responses were received from the upstream API
+	SourceUpstream = "upstream"
+	// SourceCassette responses were replayed from a cassette recording
+	SourceCassette = "cassette"
+	// SourceMock responses were generated from the response schema of the endpoint
+	SourceMock = "mock"
+	// SourceLocal responses were built by the server itself, e.g. the outputs of
+	// composite tools and the rejections of the concurrency limits
+	SourceLocal = "local"
+)
+
+// RequestError is the error of a request whose attempts all failed without a response
+// to return, e.g. because they timed out
+type RequestError struct {
+	// URL is the URL of the request, with credentials in its query redacted
+	URL string
+	// Attempts is the number of times the request was sent
+	Attempts int
+	// Duration is the time from sending the first attempt to giving up
+	Duration time.Duration
+	Err      error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// RequestPolicy overrides the timeout and retries of a request, e.g. for a tool whose
+// endpoint is known to be slow
+type RequestPolicy struct {
+	// Timeout is the deadline of the request, retries included, and the timeout of
+	// each attempt. Zero uses the configured per-attempt timeout.
+	Timeout time.Duration
+	// Retries replaces the configured number of retries when set
+	Retries *int
+	// Credential authenticates the request in place of the credential of its host and
+	// the API key, e.g. the credential configured for the document of a tool
+	Credential *types.CredentialConfig
+	// HedgeDelay replaces the configured delay after which a GET request is hedged
+	// when set
+	HedgeDelay time.Duration
 }
 
 // NewClient creates a new HTTP client
 func NewClient(config *types.ResolvedConfig, logger *utils.Logger) *Client {
-	httpClient := &http.Client{
-		Timeout: config.HTTP.Timeout,
+	// Attempts are timed out through their contexts, so that a request policy can
+	// extend the timeout beyond the configured one
+	hosts := NewHostPolicy(config)
+	httpClient := &http.Client{Transport: sharedTransport(config.HTTP, hosts)}
+	if hosts != nil {
+		httpClient.CheckRedirect = hosts.CheckRedirect
 	}
 
-	return &Client{
+	client := &Client{
 		config:     config,
 		logger:     logger.Child("http-client"),
 		httpClient: httpClient,
+		hosts:      hosts,
+		limiter:    sharedLimiter(config.HTTP.Concurrency),
 	}
+	for _, signing := range config.Auth.Signing {
+		signer, err := NewHMACSigner(signing)
+		if err != nil {
+			// The configuration is validated on load, so this only skips unvalidated configs
+			client.logger.Error("Skipping invalid request signing configuration", zap.Error(err))
+			continue
+		}
+		client.AddSigner(signer)
+	}
+	for _, config := range config.Plugins {
+		plugin, err := NewPlugin(config)
+		if err != nil {
+			client.logger.Error("Skipping invalid plugin configuration", zap.Error(err))
+			continue
+		}
+		client.AddPlugin(plugin)
+	}
+
+	return client
+}
+
+// NewSessionClient creates an HTTP client that authenticates with the API key of a
+// session: in place of the configured API key, and of the secret of any credential
+// that sends a key. Requests to hosts or documents with a basic or jwt credential are
+// sent the session key with the default scheme instead.
+func NewSessionClient(config *types.ResolvedConfig, apiKey string, logger *utils.Logger) *Client {
+	// Clone the config so the key applies to this client only
+	sessionConfig := *config
+	sessionConfig.Auth.APIKey = apiKey
+	client := NewClient(&sessionConfig, logger)
+	client.sessionKey = apiKey
+	return client
 }
 
 // ExecuteRequest executes an HTTP request for a swagger endpoint
 func (c *Client) ExecuteRequest(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}) (*Response, error) {
+	return c.ExecuteRequestContext(context.Background(), endpoint, arguments)
+}
+
+// ExecuteRequestContext executes an HTTP request for a swagger endpoint, aborting the
+// upstream request and any pending retries when the context is cancelled
+func (c *Client) ExecuteRequestContext(ctx context.Context, endpoint *types.SwaggerEndpoint, arguments map[string]interface{}) (*Response, error) {
+	return c.ExecuteRequestWithPolicy(ctx, endpoint, arguments, RequestPolicy{})
+}
+
+// ExecuteRequestWithPolicy executes an HTTP request for a swagger endpoint with the
+// timeout and retries of the policy in place of the configured ones
+func (c *Client) ExecuteRequestWithPolicy(ctx context.Context, endpoint *types.SwaggerEndpoint, arguments map[string]interface{}, policy RequestPolicy) (*Response, error) {
 	c.logger.Debug("Executing request", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.Any("arguments", arguments))
 
+	attemptTimeout := c.config.HTTP.Timeout
+	if policy.Timeout > 0 {
+		attemptTimeout = policy.Timeout
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+	retries := c.config.HTTP.Retries
+	if policy.Retries != nil {
+		retries = *policy.Retries
+	}
+	hedgeDelay := c.config.HTTP.HedgeDelay
+	if policy.HedgeDelay > 0 {
+		hedgeDelay = policy.HedgeDelay
+	}
+
+	// AsyncAPI channels are not request/response; describe the subscription instead
+	if endpoint.Protocol == types.EndpointProtocolAsyncAPI {
+		return c.describeSubscription(endpoint, arguments)
+	}
+
 	// Build the request
 	req, err := c.buildRequest(endpoint, arguments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build HTTP request for %s %s (args: %v): %w", endpoint.Method, endpoint.Path, arguments, err)
 	}
+	req = req.WithContext(ctx)
+
+	// Let pre-request plugins change the request before its host is checked
+	if err := c.runPreRequestPlugins(req); err != nil {
+		return nil, fmt.Errorf("pre-request plugin failed for %s %s: %w", endpoint.Method, endpoint.Path, err)
+	}
+
+	// Refuse hosts outside the outbound host policy before credentials are added
+	if err := c.hosts.Check(req.URL); err != nil {
+		return nil, fmt.Errorf("request to %s refused for %s %s: %w", req.URL.Host, endpoint.Method, endpoint.Path, err)
+	}
 
 	// Add authentication
-	if err := c.addAuthentication(req); err != nil {
+	if err := c.addAuthentication(req, policy.Credential); err != nil {
 		return nil, fmt.Errorf("failed to add authentication to request %s %s (scheme: %s): %w", endpoint.Method, endpoint.Path, c.config.Auth.DefaultScheme, err)
 	}
 
 	// Add default headers
 	c.addDefaultHeaders(req)
 
+	// Sign the request as it will be sent, after plugins may have rewritten it and its
+	// credential was added
+	if err := c.signRequest(req); err != nil {
+		return nil, fmt.Errorf("failed to sign request %s %s: %w", endpoint.Method, endpoint.Path, err)
+	}
+
+	// Let auth plugins authenticate or sign the otherwise complete request
+	if err := c.runAuthPlugins(req); err != nil {
+		return nil, fmt.Errorf("auth plugin failed for %s %s: %w", endpoint.Method, endpoint.Path, err)
+	}
+
+	// Only idempotent requests are safe to send twice
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		hedgeDelay = 0
+	}
+
+	// Wait for a request slot; the retries and hedges of a call share its slot
+	if c.limiter != nil {
+		release, err := c.limiter.acquire(ctx, req.URL.Host)
+		if err != nil {
+			if errors.Is(err, ErrConcurrencyLimit) && c.config.HTTP.Concurrency.Rejection == types.RejectionBusy {
+				return busyResponse(err), nil
+			}
+			return nil, fmt.Errorf("HTTP request rejected for %s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+		defer release()
+	}
+
 	// Execute with retries
-	response, err := c.executeWithRetries(req)
+	started := time.Now()
+	response, err := c.executeWithRetries(req, retries, attemptTimeout, hedgeDelay)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request execution failed for %s %s (URL: %s, retries: %d): %w", endpoint.Method, endpoint.Path, req.URL.String(), c.config.HTTP.Retries, err)
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			requestErr.Duration = time.Since(started)
+		}
+		return nil, fmt.Errorf("HTTP request execution failed for %s %s (URL: %s, retries: %d): %w", endpoint.Method, endpoint.Path, c.redactURL(req.URL), retries, err)
+	}
+
+	response.Duration = time.Since(started)
+
+	// Let post-response plugins change the final response, e.g. to scrub it
+	if err := c.runPostResponsePlugins(req, response); err != nil {
+		return nil, fmt.Errorf("post-response plugin failed for %s %s: %w", endpoint.Method, endpoint.Path, err)
 	}
 
 	c.logger.Debug("Request completed", zap.Int("statusCode", response.StatusCode), zap.String("status", http.StatusText(response.StatusCode)))
@@ -72,6 +273,10 @@ func (c *Client) ExecuteRequest(endpoint *types.SwaggerEndpoint, arguments map[s
 
 // buildRequest builds an HTTP request from endpoint and arguments
 func (c *Client) buildRequest(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}) (*http.Request, error) {
+	if endpoint.Protocol == types.EndpointProtocolGraphQL {
+		return c.buildGraphQLRequest(endpoint, arguments)
+	}
+
 	// Start with the endpoint path
 	requestPath := endpoint.Path
 
@@ -122,8 +327,17 @@ func (c *Client) buildRequest(endpoint *types.SwaggerEndpoint, arguments map[str
 		headers["Content-Type"] = "application/json"
 	}
 
-	// Build full URL (assume single server for now)
-	baseURL := c.getBaseURL()
+	// Ask for the representation selected with the format argument
+	if format, ok := arguments[FormatArgument].(string); ok && format != "" {
+		mediaType, err := FormatMediaType(endpoint, format)
+		if err != nil {
+			return nil, err
+		}
+		headers["Accept"] = mediaType
+	}
+
+	// Build full URL from the most specific server declared for the endpoint
+	baseURL := c.resolveBaseURL(endpoint)
 	if baseURL == "" {
 		return nil, fmt.Errorf("no base URL configured - cannot build full URL for endpoint %s %s", endpoint.Method, endpoint.Path)
 	}
@@ -134,7 +348,7 @@ func (c *Client) buildRequest(endpoint *types.SwaggerEndpoint, arguments map[str
 	}
 
 	// Create request
-	var bodyReader *bytes.Reader
+	var bodyReader io.Reader
 	if requestBody != nil {
 		bodyReader = bytes.NewReader(requestBody)
 	}
@@ -152,8 +366,20 @@ func (c *Client) buildRequest(endpoint *types.SwaggerEndpoint, arguments map[str
 	return req, nil
 }
 
-// addAuthentication adds authentication to the request
-func (c *Client) addAuthentication(req *http.Request) error {
+// addAuthentication adds authentication to the request: the given credential if any,
+// otherwise the credential configured for the request's host, otherwise the API key.
+// The client of a session sends the session key with the credential instead.
+func (c *Client) addAuthentication(req *http.Request, credential *types.CredentialConfig) error {
+	if credential == nil {
+		credential = c.hostCredential(req.URL)
+	}
+	if credential != nil && c.sessionKey != "" {
+		credential = sessionCredential(credential, c.sessionKey)
+	}
+	if credential != nil {
+		return c.applyCredential(req, credential)
+	}
+
 	if c.config.Auth.APIKey != "" {
 		// Add API key authentication
 		switch c.config.Auth.DefaultScheme {
@@ -172,6 +398,80 @@ func (c *Client) addAuthentication(req *http.Request) error {
 	return nil
 }
 
+// hostCredential returns the credential configured for the host of a request URL: by
+// host and port, by host name, or by the first matching host pattern in key order
+func (c *Client) hostCredential(requestURL *url.URL) *types.CredentialConfig {
+	credentials := c.config.Auth.Credentials
+	for _, key := range []string{requestURL.Host, requestURL.Hostname()} {
+		if credential, exists := credentials[key]; exists {
+			return &credential
+		}
+	}
+
+	keys := make([]string, 0, len(credentials))
+	for key := range credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if matched, _ := path.Match(key, requestURL.Hostname()); matched {
+			credential := credentials[key]
+			return &credential
+		}
+	}
+	return nil
+}
+
+// sessionCredential returns a credential sending the key of a session in place of its
+// secret, or nil when the credential cannot send a key and the key is sent as the API key
+func sessionCredential(credential *types.CredentialConfig, key string) *types.CredentialConfig {
+	switch credential.Scheme {
+	case "", types.CredentialSchemeBearer, types.CredentialSchemeAPIKey, types.CredentialSchemeQuery:
+		override := *credential
+		override.Secret = key
+		return &override
+	default:
+		return nil
+	}
+}
+
+// applyCredential sends a credential's secret with a request according to its scheme
+func (c *Client) applyCredential(req *http.Request, credential *types.CredentialConfig) error {
+	switch credential.Scheme {
+	case "", types.CredentialSchemeBearer:
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", credential.Secret))
+	case types.CredentialSchemeAPIKey:
+		name := credential.Name
+		if name == "" {
+			name = "X-API-Key"
+		}
+		req.Header.Set(name, credential.Secret)
+	case types.CredentialSchemeBasic:
+		user, password, found := strings.Cut(credential.Secret, ":")
+		if !found {
+			return fmt.Errorf("basic credential secret must have the form user:password")
+		}
+		req.SetBasicAuth(user, password)
+	case types.CredentialSchemeQuery:
+		name := credential.Name
+		if name == "" {
+			name = "apiKey"
+		}
+		query := req.URL.Query()
+		query.Set(name, credential.Secret)
+		req.URL.RawQuery = query.Encode()
+	case types.CredentialSchemeJWT:
+		token, err := c.jwts.token(credential)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	default:
+		return fmt.Errorf("unsupported credential scheme: %s", credential.Scheme)
+	}
+	return nil
+}
+
 // addDefaultHeaders adds default headers to the request
 func (c *Client) addDefaultHeaders(req *http.Request) {
 	// Set user agent
@@ -187,24 +487,38 @@ func (c *Client) addDefaultHeaders(req *http.Request) {
 	}
 }
 
-// executeWithRetries executes the request with retry logic
-func (c *Client) executeWithRetries(req *http.Request) (*Response, error) {
+// executeWithRetries executes the request with retry logic, timing out each attempt
+// after attemptTimeout and hedging it after hedgeDelay, unless zero
+func (c *Client) executeWithRetries(req *http.Request, maxRetries int, attemptTimeout, hedgeDelay time.Duration) (*Response, error) {
 	var lastErr error
-	maxRetries := c.config.HTTP.Retries
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Wait before retrying (exponential backoff)
 			backoffDuration := time.Duration(attempt*attempt) * time.Second
 			c.logger.Debug("Retrying request", zap.Duration("backoffDuration", backoffDuration), zap.Int("attempt", attempt), zap.Int("maxRetries", maxRetries))
-			time.Sleep(backoffDuration)
+			timer := time.NewTimer(backoffDuration)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
 		}
 
-		// Clone the request for retry
-		clonedReq := c.cloneRequest(req)
-
-		response, err := c.executeRequest(clonedReq)
+		var response *Response
+		var err error
+		if hedgeDelay > 0 {
+			response, err = c.executeHedged(req, attemptTimeout, hedgeDelay)
+		} else {
+			// Clone the request for retry
+			response, err = c.executeAttempt(c.cloneRequest(req), attemptTimeout)
+		}
 		if err != nil {
+			// A cancelled caller is not a transient failure worth retrying
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			lastErr = err
 			c.logger.Error("Request attempt failed", zap.Int("attempt", attempt+1), zap.Error(err))
 			continue
@@ -217,19 +531,93 @@ func (c *Client) executeWithRetries(req *http.Request) (*Response, error) {
 			continue
 		}
 
+		response.Attempts = attempt + 1
+		response.Retries = attempt
 		return response, nil
 	}
 
-	return nil, fmt.Errorf("request failed after %d attempts (URL: %s, last error: %w)", maxRetries+1, req.URL.String(), lastErr)
+	return nil, &RequestError{
+		URL:      c.redactURL(req.URL),
+		Attempts: maxRetries + 1,
+		Err:      fmt.Errorf("request failed after %d attempts (URL: %s, last error: %w)", maxRetries+1, c.redactURL(req.URL), lastErr),
+	}
+}
+
+// executeHedged executes an attempt of an idempotent request, sending a second copy of
+// it when the first has not completed after delay. The first response received wins
+// and the other request is cancelled; an error is returned only when both fail.
+func (c *Client) executeHedged(req *http.Request, timeout, delay time.Duration) (*Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	type attemptResult struct {
+		response *Response
+		err      error
+	}
+	results := make(chan attemptResult, 2)
+	launch := func() {
+		attemptReq := c.cloneRequest(req.WithContext(ctx))
+		go func() {
+			response, err := c.executeAttempt(attemptReq, timeout)
+			results <- attemptResult{response, err}
+		}()
+	}
+
+	launch()
+	pending := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			c.logger.Debug("Hedging slow request", zap.String("url", c.redactURL(req.URL)), zap.Duration("hedgeDelay", delay))
+			launch()
+			pending++
+		case result := <-results:
+			pending--
+			if result.err == nil || pending == 0 {
+				return result.response, result.err
+			}
+		}
+	}
+}
+
+// executeAttempt executes a single HTTP request, timing it out after timeout
+func (c *Client) executeAttempt(req *http.Request, timeout time.Duration) (*Response, error) {
+	if timeout <= 0 {
+		return c.executeRequest(req, timeout)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	return c.executeRequest(req.WithContext(ctx), timeout)
 }
 
 // executeRequest executes a single HTTP request
-func (c *Client) executeRequest(req *http.Request) (*Response, error) {
-	c.logger.Debug("Making HTTP request", zap.String("method", req.Method), zap.String("url", req.URL.String()))
+func (c *Client) executeRequest(req *http.Request, timeout time.Duration) (*Response, error) {
+	c.logger.Debug("Making HTTP request", zap.String("method", req.Method), zap.String("url", c.redactURL(req.URL)))
+
+	// A sample of requests is logged in full to debug upstream issues
+	sampled := c.logger.SampleEgress()
+	var requestBody []byte
+	if sampled {
+		requestBody = egressRequestBody(req)
+	}
+	start := time.Now()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed (URL: %s, timeout: %v): %w", req.URL.String(), c.config.HTTP.Timeout, err)
+		// The error of the transport quotes the URL, credentials in its query included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			if parsed, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+				urlErr.URL = c.redactURL(parsed)
+			}
+		}
+		if sampled {
+			c.logEgress(req, requestBody, nil, nil, err, time.Since(start))
+		}
+		return nil, fmt.Errorf("HTTP request failed (URL: %s, timeout: %v): %w", c.redactURL(req.URL), timeout, err)
 	}
 	defer resp.Body.Close()
 
@@ -238,6 +626,9 @@ func (c *Client) executeRequest(req *http.Request) (*Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body (status: %d %s, content-length: %s): %w", resp.StatusCode, resp.Status, resp.Header.Get("Content-Length"), err)
 	}
+	if sampled {
+		c.logEgress(req, requestBody, resp, body, nil, time.Since(start))
+	}
 
 	// Extract headers
 	headers := make(map[string]string)
@@ -251,6 +642,8 @@ func (c *Client) executeRequest(req *http.Request) (*Response, error) {
 		StatusCode: resp.StatusCode,
 		Headers:    headers,
 		Body:       body,
+		URL:        c.redactURL(resp.Request.URL),
+		Source:     SourceUpstream,
 	}, nil
 }
 
@@ -271,6 +664,13 @@ func (c *Client) cloneRequest(req *http.Request) *http.Request {
 
 // shouldRetry determines if a request should be retried based on status code
 func (c *Client) shouldRetry(statusCode int) bool {
+	return IsRetryableStatus(statusCode)
+}
+
+// IsRetryableStatus reports whether a request that failed with a status code may
+// succeed when retried: 429 Too Many Requests, and the 500, 502, 503, and 504 server
+// errors
+func IsRetryableStatus(statusCode int) bool {
 	// Retry on server errors (5xx) and some client errors
 	retryableCodes := []int{
 		429, // Too Many Requests
@@ -289,6 +689,9 @@ func (c *Client) shouldRetry(statusCode int) bool {
 	return false
 }
 
+// DefaultBaseURL is the base URL of endpoints whose documents declare no servers
+const DefaultBaseURL = "https://api.weather.com"
+
 // getBaseURL returns the base URL for API requests
 func (c *Client) getBaseURL() string {
 	// TODO: This should be extracted from swagger documents or configuration
@@ -296,10 +699,112 @@ func (c *Client) getBaseURL() string {
 	if baseURL := c.config.Auth.DefaultScheme; baseURL != "" {
 		// This is a hack - we're reusing the defaultScheme field for base URL
 		// In a real implementation, this should be properly configured
-		return "https://api.weather.com"
+		return DefaultBaseURL
+	}
+
+	return DefaultBaseURL
+}
+
+// resolveBaseURL returns the base URL for an endpoint
+func (c *Client) resolveBaseURL(endpoint *types.SwaggerEndpoint) string {
+	baseURL, err := c.ResolveBaseURL(endpoint)
+	if err != nil {
+		c.logger.Warn("Invalid server URL, using default base URL", zap.Error(err))
+	}
+	return baseURL
+}
+
+// ResolveBaseURL returns the base URL requests to an endpoint are sent to, and the
+// error of an invalid server URL, in place of which the default base URL is used
+func (c *Client) ResolveBaseURL(endpoint *types.SwaggerEndpoint) (string, error) {
+	// gRPC methods are transcoded by the configured HTTP/JSON gateway
+	if endpoint.Protocol == types.EndpointProtocolGRPC && c.config.GRPC.GatewayURL != "" {
+		return c.config.GRPC.GatewayURL, nil
+	}
+
+	baseURL, err := EndpointBaseURL(endpoint, c.getBaseURL())
+	return c.rewriteBaseURL(baseURL), err
+}
+
+// rewriteBaseURL sends a base URL to the base URL configured for its host: by host and
+// port, by host name, or by the first matching host pattern in key order
+func (c *Client) rewriteBaseURL(baseURL string) string {
+	if len(c.config.BaseURLs) == 0 {
+		return baseURL
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+
+	replacement, found := "", false
+	for _, key := range []string{parsed.Host, parsed.Hostname()} {
+		if replacement, found = c.config.BaseURLs[key]; found {
+			break
+		}
+	}
+	if !found {
+		keys := make([]string, 0, len(c.config.BaseURLs))
+		for key := range c.config.BaseURLs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if matched, _ := path.Match(key, parsed.Hostname()); matched {
+				replacement, found = c.config.BaseURLs[key], true
+				break
+			}
+		}
+	}
+	if !found {
+		return baseURL
+	}
+
+	target, err := url.Parse(replacement)
+	if err != nil {
+		return baseURL
+	}
+	parsed.Scheme = target.Scheme
+	parsed.Host = target.Host
+	parsed.Path = strings.TrimSuffix(target.Path, "/") + parsed.Path
+	parsed.RawPath = ""
+	return parsed.String()
+}
+
+// EndpointBaseURL returns the base URL of an endpoint. The endpoint's servers are
+// already narrowed to the most specific level (operation, path, or document); the
+// first one is used, with server variables replaced by their defaults. Relative
+// server URLs are resolved against defaultBaseURL, which is also returned when the
+// endpoint declares no servers or an invalid one.
+func EndpointBaseURL(endpoint *types.SwaggerEndpoint, defaultBaseURL string) (string, error) {
+	if len(endpoint.Servers) == 0 {
+		return defaultBaseURL, nil
+	}
+
+	server := endpoint.Servers[0]
+	serverURL := server.URL
+	for name, variable := range server.Variables {
+		if variableMap, ok := variable.(map[string]interface{}); ok {
+			if defaultValue, ok := variableMap["default"]; ok {
+				serverURL = strings.ReplaceAll(serverURL, fmt.Sprintf("{%s}", name), fmt.Sprintf("%v", defaultValue))
+			}
+		}
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return defaultBaseURL, fmt.Errorf("invalid server URL %s: %w", serverURL, err)
+	}
+
+	if !parsed.IsAbs() {
+		base, err := url.Parse(strings.TrimSuffix(defaultBaseURL, "/") + "/")
+		if err != nil {
+			return defaultBaseURL, nil
+		}
+		return base.ResolveReference(&url.URL{Path: strings.TrimPrefix(parsed.Path, "/")}).String(), nil
 	}
 
-	return "https://api.weather.com" // Default weather API base URL
+	return serverURL, nil
 }
 
 // SetBaseURL sets the base URL for requests (for testing)
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// dnsResolver resolves the hosts of upstream requests through the host overrides, the
+// cache, and the configured DNS servers or the system resolver, in that order
+type dnsResolver struct {
+	hosts    map[string]string
+	ttl      time.Duration
+	resolver *net.Resolver
+	cache    map[string]dnsCacheEntry
+	mutex    sync.Mutex
+}
+
+// dnsCacheEntry holds the addresses of a host until they expire
+type dnsCacheEntry struct {
+	addresses []string
+	expires   time.Time
+}
+
+// newDNSResolver creates the resolver of a DNS configuration, or returns nil when it
+// changes nothing about how hosts are resolved
+func newDNSResolver(config types.DNSConfig) *dnsResolver {
+	if config.CacheTTL <= 0 && len(config.Servers) == 0 && len(config.Hosts) == 0 {
+		return nil
+	}
+
+	r := &dnsResolver{
+		hosts:    make(map[string]string, len(config.Hosts)),
+		ttl:      config.CacheTTL,
+		resolver: net.DefaultResolver,
+		cache:    make(map[string]dnsCacheEntry),
+	}
+	for host, address := range config.Hosts {
+		r.hosts[strings.ToLower(host)] = address
+	}
+
+	if len(config.Servers) > 0 {
+		servers := make([]string, len(config.Servers))
+		for i, server := range config.Servers {
+			servers[i] = dnsServerAddress(server)
+		}
+		dialer := &net.Dialer{Timeout: 5 * time.Second}
+		r.resolver = &net.Resolver{
+			PreferGo: true,
+			// Query the servers in order, moving on when one cannot be reached
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var lastErr error
+				for _, server := range servers {
+					conn, err := dialer.DialContext(ctx, network, server)
+					if err == nil {
+						return conn, nil
+					}
+					lastErr = err
+				}
+				return nil, lastErr
+			},
+		}
+	}
+
+	return r
+}
+
+// dnsServerAddress adds the DNS port to a server given without one
+func dnsServerAddress(server string) string {
+	server = strings.TrimSpace(server)
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// lookup returns the IP addresses of a host
+func (r *dnsResolver) lookup(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	key := strings.ToLower(strings.TrimSuffix(host, "."))
+	if address, exists := r.hosts[key]; exists {
+		return []string{address}, nil
+	}
+
+	if r.ttl > 0 {
+		r.mutex.Lock()
+		entry, exists := r.cache[key]
+		r.mutex.Unlock()
+		if exists && time.Now().Before(entry.expires) {
+			return entry.addresses, nil
+		}
+	}
+
+	addresses, err := r.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if r.ttl > 0 {
+		r.mutex.Lock()
+		r.cache[key] = dnsCacheEntry{addresses: addresses, expires: time.Now().Add(r.ttl)}
+		r.mutex.Unlock()
+	}
+	return addresses, nil
+}
+
+// dialContext returns a dial function that resolves hosts with the resolver and dials
+// their addresses in turn with dialer until one connects
+func (r *dnsResolver) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		addresses, err := r.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErrors []error
+		for _, ip := range addresses {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErrors = append(dialErrors, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if len(dialErrors) == 0 {
+			return nil, fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, errors.Join(dialErrors...)
+	}
+}
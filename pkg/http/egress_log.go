@@ -0,0 +1,124 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxEgressLogBody bounds the request and response bodies of an egress log entry
+const maxEgressLogBody = 4096
+
+// redactedHeader replaces the values of redacted headers and query parameters
+const redactedHeader = "[REDACTED]"
+
+// sensitiveNameParts mark the header and query parameter names whose values are
+// redacted from egress logs, matched case-insensitively anywhere in the name
+var sensitiveNameParts = []string{"auth", "cookie", "key", "token", "secret", "password", "signature", "session"}
+
+// logEgress logs a sampled upstream request in full: its method, URL, headers, and
+// body, and the response or error it got. Credentials in headers and query
+// parameters are redacted, and bodies are truncated.
+func (c *Client) logEgress(req *http.Request, requestBody []byte, resp *http.Response, responseBody []byte, err error, duration time.Duration) {
+	fields := []interface{}{
+		zap.String("method", req.Method),
+		zap.String("url", c.redactURL(req.URL)),
+		zap.Any("requestHeaders", c.redactHeaders(req.Header)),
+		zap.Duration("duration", duration),
+	}
+	if len(requestBody) > 0 {
+		fields = append(fields, zap.String("requestBody", truncateBody(requestBody)))
+	}
+	if err != nil {
+		c.logger.Info("Upstream request", append(fields, zap.Error(err))...)
+		return
+	}
+
+	fields = append(fields,
+		zap.Int("statusCode", resp.StatusCode),
+		zap.Any("responseHeaders", c.redactHeaders(resp.Header)))
+	if len(responseBody) > 0 {
+		fields = append(fields, zap.String("responseBody", truncateBody(responseBody)))
+	}
+	c.logger.Info("Upstream request", fields...)
+}
+
+// egressRequestBody returns a copy of the body of a request to log, leaving the
+// request's own body unread
+func egressRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	content, _ := io.ReadAll(io.LimitReader(body, maxEgressLogBody+1))
+	return content
+}
+
+// redactHeaders returns headers with the values of credential headers replaced
+func (c *Client) redactHeaders(headers http.Header) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if c.isSensitiveName(name) {
+			redacted[name] = redactedHeader
+		} else {
+			redacted[name] = strings.Join(values, ", ")
+		}
+	}
+	return redacted
+}
+
+// redactURL returns a URL with the values of credential query parameters replaced
+func (c *Client) redactURL(requestURL *url.URL) string {
+	query := requestURL.Query()
+	changed := false
+	for name := range query {
+		if c.isSensitiveName(name) {
+			query.Set(name, redactedHeader)
+			changed = true
+		}
+	}
+	if !changed {
+		return requestURL.String()
+	}
+	redacted := *requestURL
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// isSensitiveName reports whether a header or query parameter may carry a credential:
+// the names configured for credentials and signatures, and names resembling them
+func (c *Client) isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	for _, credential := range c.config.Auth.Credentials {
+		if credential.Name != "" && strings.EqualFold(credential.Name, name) {
+			return true
+		}
+	}
+	for _, signing := range c.config.Auth.Signing {
+		if signing.Header != "" && strings.EqualFold(signing.Header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBody returns a body as text, cut to maxEgressLogBody bytes
+func truncateBody(body []byte) string {
+	if len(body) > maxEgressLogBody {
+		return string(body[:maxEgressLogBody]) + "...(truncated)"
+	}
+	return string(body)
+}
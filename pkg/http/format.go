@@ -0,0 +1,52 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// FormatArgument is the optional tool argument that selects the representation of the
+// response among the media types the endpoint produces. It is sent as the Accept header.
+const FormatArgument = "_format"
+
+// MediaTypeFormat returns the format name of a media type: its subtype without
+// parameters, e.g. "json" for application/json and "geo+json" for application/geo+json
+func MediaTypeFormat(mediaType string) string {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	if slash := strings.Index(mediaType, "/"); slash >= 0 {
+		mediaType = mediaType[slash+1:]
+	}
+	return strings.ToLower(mediaType)
+}
+
+// EndpointFormats returns the sorted format names of the media types an endpoint produces
+func EndpointFormats(endpoint *types.SwaggerEndpoint) []string {
+	seen := make(map[string]bool)
+	var formats []string
+	for _, mediaType := range endpoint.Produces {
+		format := MediaTypeFormat(mediaType)
+		if format != "" && !seen[format] {
+			seen[format] = true
+			formats = append(formats, format)
+		}
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// FormatMediaType returns the media type an endpoint produces for a format name. A full
+// media type the endpoint produces is accepted as well.
+func FormatMediaType(endpoint *types.SwaggerEndpoint, format string) (string, error) {
+	for _, mediaType := range endpoint.Produces {
+		if strings.EqualFold(mediaType, format) || MediaTypeFormat(mediaType) == strings.ToLower(format) {
+			return mediaType, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported %s %q for %s %s (available: %s)", FormatArgument, format, endpoint.Method, endpoint.Path, strings.Join(EndpointFormats(endpoint), ", "))
+}
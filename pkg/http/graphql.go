@@ -0,0 +1,51 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// buildGraphQLRequest builds a POST request executing the endpoint's GraphQL operation.
+// Tool arguments are passed as operation variables.
+func (c *Client) buildGraphQLRequest(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}) (*http.Request, error) {
+	variables := make(map[string]interface{})
+	for _, param := range endpoint.Parameters {
+		argValue, exists := arguments[param.Name]
+		if !exists {
+			if param.Required {
+				return nil, fmt.Errorf("required argument '%s' (type: %s) is missing from arguments: %v", param.Name, getParamType(&param), arguments)
+			}
+			continue
+		}
+		variables[param.Name] = argValue
+	}
+
+	body, err := json.Marshal(graphQLRequest{Query: endpoint.Query, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal GraphQL request for %s: %w", endpoint.OperationID, err)
+	}
+
+	endpointURL := c.config.GraphQL.Endpoint
+	if endpointURL == "" {
+		endpointURL = strings.TrimSuffix(c.resolveBaseURL(endpoint), "/") + "/graphql"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL request (URL: %s): %w", endpointURL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxRedirects is the number of redirects followed, as by Go's default client
+const maxRedirects = 10
+
+// HostPolicy decides which hosts upstream requests and document fetches may be sent
+// to, so that a malicious document cannot turn the server into a proxy for internal
+// hosts through its server URLs, redirects, or the URLs an index lists. A nil policy
+// allows any host.
+type HostPolicy struct {
+	// allowed are the allowed hosts, or nil to allow any host that is not denied
+	allowed []hostPattern
+	denied  []hostPattern
+}
+
+// hostPattern matches hosts by name, name and port, or IP address range
+type hostPattern struct {
+	pattern string
+	host    string
+	port    string
+	network *net.IPNet
+}
+
+// NewHostPolicy creates the host policy of a configuration, or returns nil when it
+// allows any host. When allowed hosts are configured, the hosts of the configured
+// swagger URLs, base URLs, gRPC gateway, and GraphQL endpoint are allowed too.
+func NewHostPolicy(config *types.ResolvedConfig) *HostPolicy {
+	if len(config.HTTP.AllowedHosts) == 0 && len(config.HTTP.DeniedHosts) == 0 {
+		return nil
+	}
+
+	policy := &HostPolicy{denied: parseHostPatterns(config.HTTP.DeniedHosts)}
+	if len(config.HTTP.AllowedHosts) > 0 {
+		policy.allowed = parseHostPatterns(config.HTTP.AllowedHosts)
+
+		configured := append([]string{}, config.SwaggerURLs...)
+		for _, baseURL := range config.BaseURLs {
+			configured = append(configured, baseURL)
+		}
+		configured = append(configured, config.GRPC.GatewayURL, config.GraphQL.Endpoint)
+		for _, rawURL := range configured {
+			if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+				policy.allowed = append(policy.allowed, parseHostPattern(parsed.Host))
+			}
+		}
+	}
+	return policy
+}
+
+// parseHostPatterns parses the host patterns of a configuration, skipping empty ones
+func parseHostPatterns(patterns []string) []hostPattern {
+	parsed := make([]hostPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			parsed = append(parsed, parseHostPattern(pattern))
+		}
+	}
+	return parsed
+}
+
+// parseHostPattern parses a host name, "host:port", "*.example.com", IP address, or
+// CIDR range
+func parseHostPattern(pattern string) hostPattern {
+	parsed := hostPattern{pattern: pattern, host: strings.ToLower(pattern)}
+	if _, network, err := net.ParseCIDR(pattern); err == nil {
+		parsed.network = network
+		return parsed
+	}
+	if host, port, err := net.SplitHostPort(pattern); err == nil {
+		parsed.host, parsed.port = strings.ToLower(host), port
+	}
+	parsed.host = strings.Trim(parsed.host, "[]")
+	return parsed
+}
+
+// matches reports whether the pattern matches a host and port
+func (p hostPattern) matches(host, port string) bool {
+	if p.network != nil {
+		ip := net.ParseIP(host)
+		return ip != nil && p.network.Contains(ip)
+	}
+	if p.port != "" && p.port != port {
+		return false
+	}
+	if ip := net.ParseIP(p.host); ip != nil {
+		return ip.Equal(net.ParseIP(host))
+	}
+	matched, _ := path.Match(p.host, host)
+	return matched
+}
+
+// matchesIP reports whether an IP address or CIDR pattern matches an IP address
+func (p hostPattern) matchesIP(ip net.IP) bool {
+	if p.network != nil {
+		return p.network.Contains(ip)
+	}
+	patternIP := net.ParseIP(p.host)
+	return patternIP != nil && patternIP.Equal(ip)
+}
+
+// Check returns an error when the host of a URL may not be contacted
+func (p *HostPolicy) Check(target *url.URL) error {
+	if p == nil {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	port := target.Port()
+	if port == "" {
+		switch target.Scheme {
+		case "http", "ws":
+			port = "80"
+		case "https", "wss":
+			port = "443"
+		}
+	}
+
+	for _, denied := range p.denied {
+		if denied.matches(host, port) {
+			return fmt.Errorf("host %q is denied by %q in http.deniedHosts", target.Host, denied.pattern)
+		}
+	}
+	if p.allowed == nil {
+		return nil
+	}
+	for _, allowed := range p.allowed {
+		if allowed.matches(host, port) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %q is not in http.allowedHosts or the configured URLs", target.Host)
+}
+
+// CheckRedirect checks the target of a redirect, for http.Client.CheckRedirect
+func (p *HostPolicy) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if err := p.Check(req.URL); err != nil {
+		return fmt.Errorf("redirect refused: %w", err)
+	}
+	return nil
+}
+
+// Client returns an HTTP client with a timeout whose requests, redirects, and
+// connections are checked against the policy
+func (p *HostPolicy) Client(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if p == nil {
+		return client
+	}
+	client.CheckRedirect = p.CheckRedirect
+	if p.deniesAddresses() {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = p.dialer().DialContext
+		client.Transport = transport
+	}
+	return client
+}
+
+// deniesAddresses reports whether the policy denies IP addresses, which are checked
+// again when connecting to catch host names resolving to denied addresses
+func (p *HostPolicy) deniesAddresses() bool {
+	if p == nil {
+		return false
+	}
+	for _, denied := range p.denied {
+		if denied.network != nil || net.ParseIP(denied.host) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedKey identifies the denied addresses of the policy, for the transport cache
+func (p *HostPolicy) deniedKey() []string {
+	if !p.deniesAddresses() {
+		return nil
+	}
+	patterns := make([]string, len(p.denied))
+	for i, denied := range p.denied {
+		patterns[i] = denied.pattern
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// dialer returns a dialer with the settings of Go's default transport that refuses to
+// connect to denied addresses
+func (p *HostPolicy) dialer() *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if p.deniesAddresses() {
+		dialer.Control = p.control
+	}
+	return dialer
+}
+
+// control refuses connections to denied addresses, for net.Dialer.Control
+func (p *HostPolicy) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	// Link-local IPv6 addresses carry the zone of their interface
+	if zone := strings.IndexByte(host, '%'); zone >= 0 {
+		host = host[:zone]
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.New("cannot check the address of a connection: " + address)
+	}
+	for _, denied := range p.denied {
+		if denied.matchesIP(ip) {
+			return fmt.Errorf("address %s is denied by %q in http.deniedHosts", ip, denied.pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"swagger-docs-mcp/pkg/types"
+)
+
+const (
+	// defaultJWTTTL is how long JWTs are valid when the credential sets no TTL
+	defaultJWTTTL = 5 * time.Minute
+	// jwtRefreshMargin is how long before they expire JWTs are replaced, so that a
+	// JWT does not expire in flight
+	jwtRefreshMargin = 30 * time.Second
+)
+
+// jwtAssertions mints the JWTs of jwt credentials and reuses them until shortly
+// before they expire
+type jwtAssertions struct {
+	mutex   sync.Mutex
+	sources map[string]*jwtSource
+}
+
+// jwtSource is the signer and current JWT of a jwt credential
+type jwtSource struct {
+	config types.JWTAssertionConfig
+	signer jose.Signer
+	token  string
+	expiry time.Time
+}
+
+// token returns a valid JWT for a jwt credential, minting one when there is none yet
+// or the current one is about to expire
+func (a *jwtAssertions) token(credential *types.CredentialConfig) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	key := fmt.Sprintf("%+v", *credential)
+	source, exists := a.sources[key]
+	if !exists {
+		var err error
+		if source, err = newJWTSource(credential); err != nil {
+			return "", err
+		}
+		if a.sources == nil {
+			a.sources = make(map[string]*jwtSource)
+		}
+		a.sources[key] = source
+	}
+
+	now := time.Now()
+	if source.token == "" || now.After(source.expiry.Add(-jwtRefreshMargin)) {
+		if err := source.mint(now); err != nil {
+			return "", err
+		}
+	}
+	return source.token, nil
+}
+
+// newJWTSource creates the source of the JWTs of a jwt credential from its private key
+func newJWTSource(credential *types.CredentialConfig) (*jwtSource, error) {
+	config := credential.JWT
+	keyPEM := []byte(credential.Secret)
+	if config.PrivateKeyFile != "" {
+		content, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+		keyPEM = content
+	}
+
+	privateKey, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := signatureAlgorithm(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	options := (&jose.SignerOptions{}).WithType("JWT")
+	if config.KeyID != "" {
+		options = options.WithHeader("kid", config.KeyID)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: algorithm, Key: privateKey}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWT signer: %w", err)
+	}
+
+	if config.Subject == "" {
+		config.Subject = config.Issuer
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultJWTTTL
+	}
+	return &jwtSource{config: config, signer: signer}, nil
+}
+
+// mint signs a new JWT issued at now
+func (s *jwtSource) mint(now time.Time) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("failed to generate JWT ID: %w", err)
+	}
+
+	expiry := now.Add(s.config.TTL)
+	claims := jwt.Claims{
+		Issuer:   s.config.Issuer,
+		Subject:  s.config.Subject,
+		Audience: jwt.Audience{s.config.Audience},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(expiry),
+		ID:       hex.EncodeToString(id),
+	}
+
+	builder := jwt.Signed(s.signer).Claims(claims)
+	if len(s.config.Claims) > 0 {
+		builder = builder.Claims(s.config.Claims)
+	}
+	token, err := builder.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	s.token, s.expiry = token, expiry
+	return nil
+}
+
+// parsePrivateKey parses a PEM private key in PKCS #8, PKCS #1, or SEC 1 form
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("JWT private key is not PEM encoded")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWT private key type %T", key)
+	}
+	return signer, nil
+}
+
+// signatureAlgorithm returns the JWS algorithm of a private key
+func signatureAlgorithm(key crypto.Signer) (jose.SignatureAlgorithm, error) {
+	switch typed := key.(type) {
+	case *rsa.PrivateKey:
+		return jose.RS256, nil
+	case *ecdsa.PrivateKey:
+		switch typed.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		case elliptic.P521():
+			return jose.ES512, nil
+		}
+		return "", fmt.Errorf("unsupported JWT private key curve %s", typed.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return jose.EdDSA, nil
+	}
+	return "", fmt.Errorf("unsupported JWT private key type %T", key)
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// ErrConcurrencyLimit is returned for requests rejected by the concurrency limits
+var ErrConcurrencyLimit = errors.New("too many concurrent upstream requests")
+
+var (
+	// limiters are the limiters of concurrency configurations, keyed by their JSON
+	// encoding, so that the clients of all sessions count against the same limits
+	limiters      = make(map[string]*concurrencyLimiter)
+	limitersMutex sync.Mutex
+)
+
+// concurrencyLimiter bounds the upstream requests in flight across all hosts and per
+// host with semaphores, queueing a bounded number of requests for a slot
+type concurrencyLimiter struct {
+	config  types.ConcurrencyConfig
+	global  chan struct{}
+	hosts   map[string]chan struct{}
+	waiting int
+	mutex   sync.Mutex
+}
+
+// sharedLimiter returns the limiter of a concurrency configuration, or nil when it
+// sets no limits
+func sharedLimiter(config types.ConcurrencyConfig) *concurrencyLimiter {
+	if config.MaxRequests <= 0 && config.MaxPerHost <= 0 {
+		return nil
+	}
+
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil
+	}
+
+	limitersMutex.Lock()
+	defer limitersMutex.Unlock()
+	if limiter, exists := limiters[string(key)]; exists {
+		return limiter
+	}
+
+	limiter := &concurrencyLimiter{
+		config: config,
+		hosts:  make(map[string]chan struct{}),
+	}
+	if config.MaxRequests > 0 {
+		limiter.global = make(chan struct{}, config.MaxRequests)
+	}
+	limiters[string(key)] = limiter
+	return limiter
+}
+
+// semaphores returns the semaphores a request to a host takes a slot of, the host's
+// first so that a queued request does not hold a global slot while waiting for it
+func (l *concurrencyLimiter) semaphores(host string) []chan struct{} {
+	var semaphores []chan struct{}
+	if l.config.MaxPerHost > 0 {
+		host = strings.ToLower(host)
+		l.mutex.Lock()
+		semaphore, exists := l.hosts[host]
+		if !exists {
+			semaphore = make(chan struct{}, l.config.MaxPerHost)
+			l.hosts[host] = semaphore
+		}
+		l.mutex.Unlock()
+		semaphores = append(semaphores, semaphore)
+	}
+	if l.global != nil {
+		semaphores = append(semaphores, l.global)
+	}
+	return semaphores
+}
+
+// acquire takes a request slot for a host, waiting in the queue when none is free. It
+// returns the function releasing the slot, or ErrConcurrencyLimit when the queue is
+// full or the wait exceeds the queue timeout.
+func (l *concurrencyLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	semaphores := l.semaphores(host)
+	release := func(taken []chan struct{}) {
+		for _, semaphore := range taken {
+			<-semaphore
+		}
+	}
+
+	// Take free slots without queueing
+	taken := 0
+	for taken < len(semaphores) && tryAcquire(semaphores[taken]) {
+		taken++
+	}
+	if taken == len(semaphores) {
+		return func() { release(semaphores) }, nil
+	}
+	release(semaphores[:taken])
+
+	l.mutex.Lock()
+	if l.waiting >= l.config.QueueSize {
+		l.mutex.Unlock()
+		return nil, fmt.Errorf("%w: no free request slot for %s and the queue of %d is full", ErrConcurrencyLimit, host, l.config.QueueSize)
+	}
+	l.waiting++
+	l.mutex.Unlock()
+	defer func() {
+		l.mutex.Lock()
+		l.waiting--
+		l.mutex.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if l.config.QueueTimeout > 0 {
+		timer := time.NewTimer(l.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for i, semaphore := range semaphores {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			release(semaphores[:i])
+			return nil, ctx.Err()
+		case <-timeout:
+			release(semaphores[:i])
+			return nil, fmt.Errorf("%w: no request slot for %s freed up within %s", ErrConcurrencyLimit, host, l.config.QueueTimeout)
+		}
+	}
+	return func() { release(semaphores) }, nil
+}
+
+// tryAcquire takes a slot of a semaphore if one is free
+func tryAcquire(semaphore chan struct{}) bool {
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// busyResponse is the 503 Service Unavailable response of a request rejected by the
+// concurrency limits, for the busy rejection
+func busyResponse(err error) *Response {
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+	return &Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"Retry-After":  "1",
+		},
+		Body:   body,
+		Source: SourceLocal,
+	}
+}
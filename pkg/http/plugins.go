@@ -0,0 +1,388 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+const (
+	// defaultPluginTimeout bounds each run of a plugin without a configured timeout
+	defaultPluginTimeout = 5 * time.Second
+	// maxPluginOutput bounds the output a plugin may write to stdout
+	maxPluginOutput = 64 << 20
+	// maxPluginStderr bounds the stderr of a failed plugin quoted in its error
+	maxPluginStderr = 1024
+	// pluginWaitDelay bounds the wait for the output of a plugin killed at its timeout
+	pluginWaitDelay = time.Second
+)
+
+// errPluginOutputTooLarge is returned when a plugin writes more than maxPluginOutput
+var errPluginOutputTooLarge = fmt.Errorf("plugin output exceeds %d bytes", maxPluginOutput)
+
+// Plugin is an external executable hooked into the upstream requests of the client.
+// It reads a pluginInput document from stdin and writes a pluginOutput document to
+// stdout; empty output leaves the request or response unchanged.
+type Plugin struct {
+	config types.PluginConfig
+}
+
+// pluginInput is the JSON document a plugin reads from stdin
+type pluginInput struct {
+	Hook     types.PluginHook `json:"hook"`
+	Request  *pluginRequest   `json:"request"`
+	Response *pluginResponse  `json:"response,omitempty"`
+}
+
+// pluginOutput is the JSON document a plugin writes to stdout. Only the fields of the
+// plugin's hook are read.
+type pluginOutput struct {
+	// Error refuses the call with the given message
+	Error string `json:"error,omitempty"`
+	// Request replaces the fields it sets of the request, for pre-request plugins
+	Request *pluginRequest `json:"request,omitempty"`
+	// Response replaces the fields it sets of the response, for post-response plugins
+	Response *pluginResponse `json:"response,omitempty"`
+	// Headers are set on the request, for auth plugins
+	Headers map[string]string `json:"headers,omitempty"`
+	// Query parameters are set on the request URL, for auth plugins
+	Query map[string]string `json:"query,omitempty"`
+}
+
+// pluginRequest describes an upstream request. Bodies that are not valid UTF-8 are
+// given base64-encoded in BodyBase64 instead of Body.
+type pluginRequest struct {
+	Method     string            `json:"method,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Headers    map[string]string `json:"headers"`
+	Body       *string           `json:"body,omitempty"`
+	BodyBase64 *string           `json:"bodyBase64,omitempty"`
+}
+
+// pluginResponse describes an upstream response, with bodies encoded as for requests
+type pluginResponse struct {
+	Status     int               `json:"status,omitempty"`
+	Headers    map[string]string `json:"headers"`
+	Body       *string           `json:"body,omitempty"`
+	BodyBase64 *string           `json:"bodyBase64,omitempty"`
+}
+
+// NewPlugin creates a plugin for a plugin configuration, filling in its defaults
+func NewPlugin(config types.PluginConfig) (*Plugin, error) {
+	switch config.Hook {
+	case types.PluginHookPreRequest, types.PluginHookAuth, types.PluginHookPostResponse:
+	default:
+		return nil, fmt.Errorf("unsupported plugin hook: %s", config.Hook)
+	}
+	if len(config.Command) == 0 || config.Command[0] == "" {
+		return nil, fmt.Errorf("plugin has no command")
+	}
+	if config.Name == "" {
+		config.Name = filepath.Base(config.Command[0])
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultPluginTimeout
+	}
+	return &Plugin{config: config}, nil
+}
+
+// Name returns the name of the plugin
+func (p *Plugin) Name() string {
+	return p.config.Name
+}
+
+// Hook returns the hook the plugin runs at
+func (p *Plugin) Hook() types.PluginHook {
+	return p.config.Hook
+}
+
+// applies reports whether the plugin runs for requests to the host of a URL
+func (p *Plugin) applies(requestURL *url.URL) bool {
+	if len(p.config.Hosts) == 0 {
+		return true
+	}
+	for _, host := range p.config.Hosts {
+		if host == requestURL.Host {
+			return true
+		}
+		if matched, _ := path.Match(host, requestURL.Hostname()); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs the plugin with an input document and decodes its output
+func (p *Plugin) run(ctx context.Context, input *pluginInput) (*pluginOutput, error) {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: failed to encode input: %w", p.config.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.config.Command[0], p.config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(encoded)
+	stdout := &cappedBuffer{limit: maxPluginOutput}
+	stderr := &cappedBuffer{limit: maxPluginStderr, truncate: true}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children of a killed plugin may hold its output open; stop waiting for them
+	cmd.WaitDelay = pluginWaitDelay
+
+	if err := cmd.Run(); err != nil {
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return nil, fmt.Errorf("plugin %s timed out after %s", p.config.Name, p.config.Timeout)
+		case errors.Is(err, errPluginOutputTooLarge):
+			return nil, fmt.Errorf("plugin %s: %w", p.config.Name, err)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("plugin %s failed: %w: %s", p.config.Name, err, message)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", p.config.Name, err)
+	}
+
+	output := &pluginOutput{}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return output, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), output); err != nil {
+		return nil, fmt.Errorf("plugin %s wrote invalid output: %w", p.config.Name, err)
+	}
+	if output.Error != "" {
+		return nil, fmt.Errorf("plugin %s refused the request: %s", p.config.Name, output.Error)
+	}
+	return output, nil
+}
+
+// cappedBuffer collects the output of a plugin up to a limit. Past the limit, it
+// fails the write, or drops the rest when truncate is set.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit    int
+	truncate bool
+}
+
+func (b *cappedBuffer) Write(data []byte) (int, error) {
+	if room := b.limit - b.Len(); len(data) > room {
+		if !b.truncate {
+			return 0, errPluginOutputTooLarge
+		}
+		if room > 0 {
+			b.Buffer.Write(data[:room])
+		}
+		return len(data), nil
+	}
+	return b.Buffer.Write(data)
+}
+
+// AddPlugin adds a plugin that runs for the requests of the client after the
+// configured ones of its hook
+func (c *Client) AddPlugin(plugin *Plugin) {
+	c.plugins = append(c.plugins, plugin)
+}
+
+// runPreRequestPlugins lets the pre-request plugins change a request before it is
+// authenticated and sent
+func (c *Client) runPreRequestPlugins(req *http.Request) error {
+	for _, plugin := range c.plugins {
+		if plugin.Hook() != types.PluginHookPreRequest || !plugin.applies(req.URL) {
+			continue
+		}
+		input, err := describePluginRequest(req)
+		if err != nil {
+			return err
+		}
+		c.logger.Debug("Running plugin", zap.String("plugin", plugin.Name()), zap.String("hook", string(plugin.Hook())))
+		output, err := plugin.run(req.Context(), &pluginInput{Hook: plugin.Hook(), Request: input})
+		if err != nil {
+			return err
+		}
+		if output.Request != nil {
+			if err := applyPluginRequest(req, output.Request); err != nil {
+				return fmt.Errorf("plugin %s: %w", plugin.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// runAuthPlugins lets the auth plugins add headers and query parameters to a request
+// once it is otherwise complete, e.g. to sign it
+func (c *Client) runAuthPlugins(req *http.Request) error {
+	for _, plugin := range c.plugins {
+		if plugin.Hook() != types.PluginHookAuth || !plugin.applies(req.URL) {
+			continue
+		}
+		input, err := describePluginRequest(req)
+		if err != nil {
+			return err
+		}
+		c.logger.Debug("Running plugin", zap.String("plugin", plugin.Name()), zap.String("hook", string(plugin.Hook())))
+		output, err := plugin.run(req.Context(), &pluginInput{Hook: plugin.Hook(), Request: input})
+		if err != nil {
+			return err
+		}
+		for name, value := range output.Headers {
+			req.Header.Set(name, value)
+		}
+		if len(output.Query) > 0 {
+			query := req.URL.Query()
+			for name, value := range output.Query {
+				query.Set(name, value)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+	}
+	return nil
+}
+
+// runPostResponsePlugins lets the post-response plugins change a response before it
+// is returned. They are told the method and URL of the request, with the credentials
+// of its query redacted, but not its headers or body, which may hold credentials.
+func (c *Client) runPostResponsePlugins(req *http.Request, response *Response) error {
+	for _, plugin := range c.plugins {
+		if plugin.Hook() != types.PluginHookPostResponse || !plugin.applies(req.URL) {
+			continue
+		}
+		input := &pluginInput{
+			Hook:    plugin.Hook(),
+			Request: &pluginRequest{Method: req.Method, URL: c.redactURL(req.URL)},
+			Response: &pluginResponse{
+				Status:  response.StatusCode,
+				Headers: response.Headers,
+			},
+		}
+		input.Response.Body, input.Response.BodyBase64 = encodePluginBody(response.Body)
+		c.logger.Debug("Running plugin", zap.String("plugin", plugin.Name()), zap.String("hook", string(plugin.Hook())))
+		output, err := plugin.run(req.Context(), input)
+		if err != nil {
+			return err
+		}
+		if output.Response == nil {
+			continue
+		}
+		if output.Response.Status != 0 {
+			response.StatusCode = output.Response.Status
+		}
+		if output.Response.Headers != nil {
+			response.Headers = output.Response.Headers
+		}
+		body, ok, err := decodePluginBody(output.Response.Body, output.Response.BodyBase64)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", plugin.Name(), err)
+		}
+		if ok {
+			response.Body = body
+		}
+	}
+	return nil
+}
+
+// describePluginRequest describes a request for a plugin
+func describePluginRequest(req *http.Request) (*pluginRequest, error) {
+	described := &pluginRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: make(map[string]string, len(req.Header)),
+	}
+	for name, values := range req.Header {
+		described.Headers[name] = strings.Join(values, ", ")
+	}
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for plugin: %w", err)
+		}
+		body, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for plugin: %w", err)
+		}
+		described.Body, described.BodyBase64 = encodePluginBody(body)
+	}
+	return described, nil
+}
+
+// applyPluginRequest replaces the fields of a request set by a pre-request plugin.
+// Headers replace all headers of the request.
+func applyPluginRequest(req *http.Request, changes *pluginRequest) error {
+	if changes.Method != "" {
+		req.Method = strings.ToUpper(changes.Method)
+	}
+	if changes.URL != "" {
+		parsed, err := url.Parse(changes.URL)
+		if err != nil {
+			return fmt.Errorf("invalid request URL: %w", err)
+		}
+		if !parsed.IsAbs() {
+			parsed = req.URL.ResolveReference(parsed)
+		}
+		req.URL = parsed
+		req.Host = ""
+	}
+	if changes.Headers != nil {
+		req.Header = make(http.Header, len(changes.Headers))
+		for name, value := range changes.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+	body, ok, err := decodePluginBody(changes.Body, changes.BodyBase64)
+	if err != nil {
+		return err
+	}
+	if ok {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.ContentLength = int64(len(body))
+	}
+	return nil
+}
+
+// encodePluginBody encodes a body for a plugin, as text when it is valid UTF-8 and as
+// base64 otherwise
+func encodePluginBody(body []byte) (*string, *string) {
+	if body == nil {
+		return nil, nil
+	}
+	if utf8.Valid(body) {
+		text := string(body)
+		return &text, nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(body)
+	return nil, &encoded
+}
+
+// decodePluginBody decodes a body written by a plugin, reporting whether it set one
+func decodePluginBody(text, encoded *string) ([]byte, bool, error) {
+	switch {
+	case encoded != nil:
+		body, err := base64.StdEncoding.DecodeString(*encoded)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid bodyBase64: %w", err)
+		}
+		return body, true, nil
+	case text != nil:
+		return []byte(*text), true, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// RequestSigner signs upstream requests before they are sent, e.g. by adding
+// signature headers
+type RequestSigner interface {
+	Sign(req *http.Request) error
+}
+
+// HMACSigner signs requests with an HMAC signature of a message built from the request
+type HMACSigner struct {
+	config types.SigningConfig
+	hash   func() hash.Hash
+	now    func() time.Time
+}
+
+// NewHMACSigner creates a signer for a signing configuration, filling in its defaults
+func NewHMACSigner(config types.SigningConfig) (*HMACSigner, error) {
+	var hashFunc func() hash.Hash
+	switch config.Algorithm {
+	case "", types.SigningAlgorithmSHA256:
+		hashFunc = sha256.New
+	case types.SigningAlgorithmSHA1:
+		hashFunc = sha1.New
+	case types.SigningAlgorithmSHA512:
+		hashFunc = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", config.Algorithm)
+	}
+	switch config.Encoding {
+	case "":
+		config.Encoding = "hex"
+	case "hex", "base64":
+	default:
+		return nil, fmt.Errorf("unsupported signature encoding: %s", config.Encoding)
+	}
+
+	if config.Message == "" {
+		config.Message = "{timestamp}{path}"
+	}
+	if config.Header == "" {
+		config.Header = "X-Signature"
+	}
+	if config.HeaderTemplate == "" {
+		config.HeaderTemplate = "{signature}"
+	}
+	if config.TimestampHeader == "" {
+		config.TimestampHeader = "X-Timestamp"
+	}
+
+	return &HMACSigner{config: config, hash: hashFunc, now: time.Now}, nil
+}
+
+// Sign adds the signature and timestamp headers to requests to the signer's hosts
+func (s *HMACSigner) Sign(req *http.Request) error {
+	if !s.signs(req) {
+		return nil
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+		body, err = io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+	}
+	bodySum := sha256.Sum256(body)
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	placeholders := []string{
+		"{timestamp}", timestamp,
+		"{method}", req.Method,
+		"{host}", req.URL.Host,
+		"{path}", req.URL.EscapedPath(),
+		"{query}", req.URL.RawQuery,
+		"{body}", string(body),
+		"{bodySha256}", hex.EncodeToString(bodySum[:]),
+	}
+	message := strings.NewReplacer(placeholders...).Replace(s.config.Message)
+
+	mac := hmac.New(s.hash, []byte(s.config.Key))
+	mac.Write([]byte(message))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	if s.config.Encoding == "base64" {
+		signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	headerValue := strings.NewReplacer(append(placeholders, "{signature}", signature)...).Replace(s.config.HeaderTemplate)
+	req.Header.Set(s.config.Header, headerValue)
+	req.Header.Set(s.config.TimestampHeader, timestamp)
+	return nil
+}
+
+// signs reports whether requests to the host of a request are signed
+func (s *HMACSigner) signs(req *http.Request) bool {
+	if len(s.config.Hosts) == 0 {
+		return true
+	}
+	for _, host := range s.config.Hosts {
+		if host == req.URL.Host {
+			return true
+		}
+		if matched, _ := path.Match(host, req.URL.Hostname()); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSigner adds a signer that signs the requests of the client after the configured ones
+func (c *Client) AddSigner(signer RequestSigner) {
+	c.signers = append(c.signers, signer)
+}
+
+// signRequest signs an otherwise complete request with the client's signers
+func (c *Client) signRequest(req *http.Request) error {
+	for _, signer := range c.signers {
+		if err := signer.Sign(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
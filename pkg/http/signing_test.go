@@ -0,0 +1,141 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// receivedRequest is a request as the test upstream received it
+type receivedRequest struct {
+	method    string
+	path      string
+	query     string
+	body      string
+	signature string
+	timestamp string
+}
+
+// signedMessage builds the message of the signing configuration of the tests
+func signedMessage(timestamp, method, path, query, body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return timestamp + "\n" + method + "\n" + path + "\n" + query + "\n" + hex.EncodeToString(sum[:])
+}
+
+func TestSignedRequestMatchesSentRequest(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("plugins are run with sh")
+	}
+
+	const key = "signing-secret"
+
+	tests := []struct {
+		name string
+		// plugin is the output of a pre-request plugin, if any
+		plugin     string
+		credential *types.CredentialConfig
+		want       receivedRequest
+	}{
+		{
+			name: "unchanged request",
+			want: receivedRequest{method: "POST", path: "/pets", body: `{"name":"rex"}`},
+		},
+		{
+			name:   "plugin rewrites the path and body",
+			plugin: `{"request": {"url": "/v2/pets?source=plugin", "body": "{\"name\":\"max\"}"}}`,
+			want:   receivedRequest{method: "POST", path: "/v2/pets", query: "source=plugin", body: `{"name":"max"}`},
+		},
+		{
+			name:   "plugin rewrites the method",
+			plugin: `{"request": {"method": "put"}}`,
+			want:   receivedRequest{method: "PUT", path: "/pets", body: `{"name":"rex"}`},
+		},
+		{
+			name:       "query credential",
+			credential: &types.CredentialConfig{Scheme: types.CredentialSchemeQuery, Secret: "query-secret"},
+			want:       receivedRequest{method: "POST", path: "/pets", query: "apiKey=query-secret", body: `{"name":"rex"}`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			received := make(chan receivedRequest, 1)
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received <- receivedRequest{
+					method:    r.Method,
+					path:      r.URL.EscapedPath(),
+					query:     r.URL.RawQuery,
+					body:      string(body),
+					signature: r.Header.Get("X-Signature"),
+					timestamp: r.Header.Get("X-Timestamp"),
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer upstream.Close()
+
+			config := types.DefaultConfig()
+			config.HTTP.Retries = 0
+			if test.credential != nil {
+				host, _ := url.Parse(upstream.URL)
+				config.Auth.Credentials = map[string]types.CredentialConfig{host.Host: *test.credential}
+			}
+			client := NewClient(config, utils.NewLogger(types.LoggingConfig{Level: "error"}))
+
+			signer, err := NewHMACSigner(types.SigningConfig{
+				Key:     key,
+				Message: "{timestamp}\n{method}\n{path}\n{query}\n{bodySha256}",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.AddSigner(signer)
+
+			if test.plugin != "" {
+				script := filepath.Join(t.TempDir(), "plugin.sh")
+				content := "#!/bin/sh\ncat >/dev/null\ncat <<'EOF'\n" + test.plugin + "\nEOF\n"
+				if err := os.WriteFile(script, []byte(content), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				plugin, err := NewPlugin(types.PluginConfig{Hook: types.PluginHookPreRequest, Command: []string{"sh", script}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				client.AddPlugin(plugin)
+			}
+
+			endpoint := &types.SwaggerEndpoint{
+				Path:    "/pets",
+				Method:  "post",
+				Servers: []types.SwaggerServer{{URL: upstream.URL}},
+			}
+			arguments := map[string]interface{}{"requestBody": map[string]interface{}{"name": "rex"}}
+			if _, err := client.ExecuteRequest(endpoint, arguments); err != nil {
+				t.Fatalf("ExecuteRequest() error = %v", err)
+			}
+
+			got := <-received
+			if got.method != test.want.method || got.path != test.want.path || got.query != test.want.query || got.body != test.want.body {
+				t.Errorf("upstream received %s %s?%s %s, want %s %s?%s %s", got.method, got.path, got.query, got.body,
+					test.want.method, test.want.path, test.want.query, test.want.body)
+			}
+
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(signedMessage(got.timestamp, got.method, got.path, got.query, got.body)))
+			if want := hex.EncodeToString(mac.Sum(nil)); got.signature != want {
+				t.Errorf("signature = %q, want %q over the request as sent", got.signature, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Subscription describes how to subscribe to an AsyncAPI channel. Channels are served
+// over brokers and streaming protocols (Kafka, MQTT, WebSockets, ...) rather than
+// request/response HTTP, so tool calls return the connection details instead of data.
+type Subscription struct {
+	Channel  string                 `json:"channel"`
+	Address  string                 `json:"address"`
+	Action   string                 `json:"action"`
+	Servers  []types.SwaggerServer  `json:"servers,omitempty"`
+	Messages []interface{}          `json:"messages,omitempty"`
+	Filters  map[string]interface{} `json:"filters,omitempty"`
+}
+
+// describeSubscription builds a synthetic response describing an AsyncAPI channel subscription
+func (c *Client) describeSubscription(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}) (*Response, error) {
+	address := endpoint.Path
+	filters := make(map[string]interface{})
+
+	for _, param := range endpoint.Parameters {
+		argValue, exists := arguments[param.Name]
+		if !exists {
+			if param.Required {
+				return nil, fmt.Errorf("required channel parameter '%s' is missing from arguments: %v", param.Name, arguments)
+			}
+			continue
+		}
+
+		placeholder := fmt.Sprintf("{%s}", param.Name)
+		if strings.Contains(address, placeholder) {
+			address = strings.ReplaceAll(address, placeholder, fmt.Sprintf("%v", argValue))
+		} else {
+			filters[param.Name] = argValue
+		}
+	}
+
+	subscription := Subscription{
+		Channel:  endpoint.Path,
+		Address:  address,
+		Action:   strings.ToLower(endpoint.Method),
+		Servers:  endpoint.Servers,
+		Messages: endpoint.Messages,
+	}
+	if len(filters) > 0 {
+		subscription.Filters = filters
+	}
+
+	body, err := json.Marshal(subscription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode subscription for channel %s: %w", endpoint.Path, err)
+	}
+
+	c.logger.Debug("Described channel subscription", zap.String("channel", endpoint.Path), zap.String("address", address))
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+		Source:     SourceLocal,
+	}, nil
+}
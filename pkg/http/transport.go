@@ -0,0 +1,76 @@
+package http
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+var (
+	// transports are the transports of tuned transport, DNS, and denied address
+	// configurations, keyed by their JSON encoding. Clients created with the same
+	// configuration, e.g. for the API keys of different sessions, share one transport
+	// so that their connections are pooled together and their DNS cache is shared.
+	transports      = make(map[string]*http.Transport)
+	transportsMutex sync.Mutex
+)
+
+// sharedTransport returns the transport of the transport and DNS settings of an HTTP
+// configuration and the denied addresses of a host policy: a copy of Go's default
+// transport with the configured settings, or nil to use the default transport itself
+// when nothing is configured
+func sharedTransport(config types.HTTPConfig, hosts *HostPolicy) http.RoundTripper {
+	resolver := newDNSResolver(config.DNS)
+	if config.Transport == (types.TransportConfig{}) && resolver == nil && !hosts.deniesAddresses() {
+		return nil
+	}
+
+	key, err := json.Marshal(struct {
+		Transport     types.TransportConfig
+		DNS           types.DNSConfig
+		DeniedAddress []string
+	}{config.Transport, config.DNS, hosts.deniedKey()})
+	if err != nil {
+		return nil
+	}
+
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+	if transport, exists := transports[string(key)]; exists {
+		return transport
+	}
+
+	settings := config.Transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.MaxIdleConns > 0 {
+		transport.MaxIdleConns = settings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	}
+	if settings.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = settings.MaxConnsPerHost
+	}
+	if settings.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = settings.IdleConnTimeout
+	}
+	if settings.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = settings.TLSHandshakeTimeout
+	}
+	if settings.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto map turns off HTTP/2 negotiation
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	if resolver != nil {
+		transport.DialContext = resolver.dialContext(hosts.dialer())
+	} else if hosts.deniesAddresses() {
+		transport.DialContext = hosts.dialer().DialContext
+	}
+
+	transports[string(key)] = transport
+	return transport
+}
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.uber.org/zap"
+	toolserver "swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// authRealm is advertised in WWW-Authenticate challenges
+const authRealm = "swagger-docs-mcp"
+
+// Authenticator validates bearer credentials presented to the MCP HTTP endpoint
+type Authenticator struct {
+	tokens   [][]byte
+	tenants  *toolserver.Tenants
+	verifier *oidc.IDTokenVerifier
+	logger   *utils.Logger
+}
+
+// NewAuthenticator creates an authenticator from the server auth configuration.
+// When an OIDC issuer is configured its discovery document is fetched up front
+// so that misconfiguration is reported at startup rather than on first request.
+func NewAuthenticator(ctx context.Context, config types.ServerAuthConfig, logger *utils.Logger) (*Authenticator, error) {
+	a := &Authenticator{
+		tenants: toolserver.NewTenants(config.Tenants),
+		logger:  logger.Child("auth"),
+	}
+
+	for _, token := range config.BearerTokens {
+		token = strings.TrimSpace(token)
+		if token != "" {
+			a.tokens = append(a.tokens, []byte(token))
+		}
+	}
+
+	if config.OIDCIssuer != "" {
+		provider, err := oidc.NewProvider(ctx, config.OIDCIssuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover OIDC issuer %s: %w", config.OIDCIssuer, err)
+		}
+		a.verifier = provider.Verifier(&oidc.Config{ClientID: config.OIDCAudience})
+	}
+
+	return a, nil
+}
+
+// Enabled reports whether any credential check is configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0 || a.tenants.Enabled() || a.verifier != nil
+}
+
+// Middleware rejects requests that do not carry a valid bearer credential. Requests
+// carrying a tenant's API key are restricted to the tenant's tools.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := toolserver.BearerToken(r)
+		if !ok {
+			a.challenge(w, "")
+			return
+		}
+
+		if tenant := a.tenants.Lookup(token); tenant != nil {
+			next.ServeHTTP(w, r.WithContext(toolserver.WithTenant(r.Context(), tenant)))
+			return
+		}
+
+		if err := a.authenticate(r.Context(), token); err != nil {
+			a.logger.Debug("Rejected MCP HTTP request",
+				zap.String("remote", r.RemoteAddr),
+				zap.Error(err))
+			a.challenge(w, "invalid_token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate checks a token against the static tokens, then the OIDC verifier
+func (a *Authenticator) authenticate(ctx context.Context, token string) error {
+	presented := []byte(token)
+	for _, expected := range a.tokens {
+		if subtle.ConstantTimeCompare(presented, expected) == 1 {
+			return nil
+		}
+	}
+
+	if a.verifier != nil {
+		if _, err := a.verifier.Verify(ctx, token); err != nil {
+			return fmt.Errorf("JWT verification failed: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("token does not match any configured bearer token")
+}
+
+// challenge writes a 401 response with a Bearer challenge
+func (a *Authenticator) challenge(w http.ResponseWriter, errorCode string) {
+	challenge := fmt.Sprintf("Bearer realm=%q", authRealm)
+	if errorCode != "" {
+		challenge += fmt.Sprintf(", error=%q", errorCode)
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	httpclient "swagger-docs-mcp/pkg/http"
+	toolserver "swagger-docs-mcp/pkg/server"
+)
+
+// sessionIdleTimeout is how long an MCP HTTP session may go unused before its state is dropped
+const sessionIdleTimeout = 30 * time.Minute
+
+// sessionAPIKeyHeader carries a per-session upstream API key override
+const sessionAPIKeyHeader = "X-API-Key"
+
+// SessionState holds the settings and usage counters of one MCP HTTP client session.
+// Settings are taken from the request that created the session and may be replaced
+// by any later request that carries them.
+type SessionState struct {
+	ID        string
+	CreatedAt time.Time
+
+	mutex     sync.RWMutex
+	apiKey    string
+	filter    toolserver.ToolFilter
+	lastSeen  time.Time
+	toolCalls map[string]int
+	errors    int
+
+	// client is the HTTP client of the session's API key override, built on first use
+	// and rebuilt when the key changes
+	client    *httpclient.Client
+	clientKey string
+}
+
+// SessionStats is a point-in-time snapshot of a session's usage
+type SessionStats struct {
+	ID        string                `json:"id"`
+	CreatedAt time.Time             `json:"createdAt"`
+	LastSeen  time.Time             `json:"lastSeen"`
+	APIKeySet bool                  `json:"apiKeySet"`
+	Filter    toolserver.ToolFilter `json:"filter"`
+	Calls     int                   `json:"calls"`
+	Errors    int                   `json:"errors"`
+	ToolCalls map[string]int        `json:"toolCalls,omitempty"`
+}
+
+// APIKey returns the session's upstream API key override, if any
+func (s *SessionState) APIKey() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.apiKey
+}
+
+// HTTPClient returns the HTTP client of the session's API key override, building it
+// with build on first use and again after the key changed
+func (s *SessionState) HTTPClient(apiKey string, build func(apiKey string) *httpclient.Client) *httpclient.Client {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.client == nil || s.clientKey != apiKey {
+		s.client, s.clientKey = build(apiKey), apiKey
+	}
+	return s.client
+}
+
+// Filter returns the session's dynamic tool filter
+func (s *SessionState) Filter() toolserver.ToolFilter {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.filter
+}
+
+// RecordCall increments the session's usage counters for a tool call
+func (s *SessionState) RecordCall(toolName string, failed bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.toolCalls[toolName]++
+	if failed {
+		s.errors++
+	}
+}
+
+// Stats returns a snapshot of the session's usage
+func (s *SessionState) Stats() SessionStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats := SessionStats{
+		ID:        s.ID,
+		CreatedAt: s.CreatedAt,
+		LastSeen:  s.lastSeen,
+		APIKeySet: s.apiKey != "",
+		Filter:    s.filter,
+		Errors:    s.errors,
+		ToolCalls: make(map[string]int, len(s.toolCalls)),
+	}
+	for name, count := range s.toolCalls {
+		stats.ToolCalls[name] = count
+		stats.Calls += count
+	}
+	return stats
+}
+
+// update applies settings carried by an HTTP request and marks the session active
+func (s *SessionState) update(r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastSeen = time.Now()
+	if apiKey := r.Header.Get(sessionAPIKeyHeader); apiKey != "" {
+		s.apiKey = apiKey
+	}
+	if filter := toolserver.ToolFilterFromQuery(r.URL.Query()); !filter.IsEmpty() {
+		s.filter = filter
+	}
+}
+
+// idleSince reports whether the session has been unused since the given time
+func (s *SessionState) idleSince(cutoff time.Time) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastSeen.Before(cutoff)
+}
+
+// SessionStore tracks per-session state for the MCP HTTP transport
+type SessionStore struct {
+	sessions map[string]*SessionState
+	mutex    sync.RWMutex
+}
+
+// NewSessionStore creates an empty session store
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string]*SessionState),
+	}
+}
+
+// Bind returns the state for a session, creating it on first use, and applies the
+// settings carried by the request
+func (st *SessionStore) Bind(sessionID string, r *http.Request) *SessionState {
+	st.mutex.Lock()
+	state, exists := st.sessions[sessionID]
+	if !exists {
+		state = &SessionState{
+			ID:        sessionID,
+			CreatedAt: time.Now(),
+			toolCalls: make(map[string]int),
+		}
+		st.sessions[sessionID] = state
+	}
+	st.mutex.Unlock()
+
+	state.update(r)
+	return state
+}
+
+// Delete removes a session's state
+func (st *SessionStore) Delete(sessionID string) *SessionState {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	state := st.sessions[sessionID]
+	delete(st.sessions, sessionID)
+	return state
+}
+
+// Expire removes sessions that have been idle longer than the given timeout
+func (st *SessionStore) Expire(timeout time.Duration) []*SessionState {
+	cutoff := time.Now().Add(-timeout)
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	var expired []*SessionState
+	for id, state := range st.sessions {
+		if state.idleSince(cutoff) {
+			expired = append(expired, state)
+			delete(st.sessions, id)
+		}
+	}
+	return expired
+}
+
+// Stats returns usage snapshots for all sessions, oldest first
+func (st *SessionStore) Stats() []SessionStats {
+	st.mutex.RLock()
+	stats := make([]SessionStats, 0, len(st.sessions))
+	for _, state := range st.sessions {
+		stats = append(stats, state.Stats())
+	}
+	st.mutex.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].CreatedAt.Before(stats[j].CreatedAt)
+	})
+	return stats
+}
+
+// sessionStateKey is the context key for the current session's state
+type sessionStateKey struct{}
+
+// withSessionState attaches session state to a context
+func withSessionState(ctx context.Context, state *SessionState) context.Context {
+	return context.WithValue(ctx, sessionStateKey{}, state)
+}
+
+// SessionStateFromContext returns the session state for the current MCP HTTP request, if any
+func SessionStateFromContext(ctx context.Context) *SessionState {
+	state, _ := ctx.Value(sessionStateKey{}).(*SessionState)
+	return state
+}
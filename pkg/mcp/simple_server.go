@@ -2,11 +2,17 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	httpclient "swagger-docs-mcp/pkg/http"
+	toolserver "swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/swagger"
 	"swagger-docs-mcp/pkg/types"
 	"swagger-docs-mcp/pkg/utils"
 	"swagger-docs-mcp/pkg/version"
@@ -15,28 +21,104 @@ import (
 
 // SimpleMCPServer wraps the mcp-go server for swagger tools
 type SimpleMCPServer struct {
-	mcpServer *server.MCPServer
-	config    *types.ResolvedConfig
-	logger    *utils.Logger
-	toolCount int
+	mcpServer  *server.MCPServer
+	config     *types.ResolvedConfig
+	logger     *utils.Logger
+	httpClient *httpclient.Client
+	tools      *toolserver.ToolRegistry
+	documents  *toolserver.DocumentStore
+	executor   *toolserver.ToolExecutor
+	sessions   *SessionStore
+	shared     *toolserver.SharedState
+	usage      *toolserver.UsageStore
+	webhooks   *toolserver.Webhooks
+	bus        *toolserver.EventBus
+	catalog    *toolserver.SharedCatalog
+	blobs      *toolserver.BlobStore
+	toolCount  int
 }
 
 // NewSimpleMCPServer creates a new MCP server using mcp-go library
 func NewSimpleMCPServer(config *types.ResolvedConfig, logger *utils.Logger) (*SimpleMCPServer, error) {
+	s := &SimpleMCPServer{
+		config:     config,
+		logger:     logger,
+		httpClient: httpclient.NewClient(config, logger),
+		tools:      toolserver.NewToolRegistry(),
+		documents:  toolserver.NewDocumentStore(),
+		sessions:   NewSessionStore(),
+		toolCount:  0,
+	}
+	s.executor = toolserver.NewToolExecutor(config, s.documents, s.tools, logger)
+	s.webhooks = toolserver.NewWebhooks(config, logger)
+	s.bus = toolserver.NewEventBus(config, logger)
+	s.catalog = toolserver.NewSharedCatalog(config, s.tools, nil, nil, logger)
+	// Tools adopted from the shared catalog come without their lazy input schemas
+	s.tools.SetSchemaLoader(swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration).AttachInputSchema)
+	s.blobs = toolserver.NewBlobStore(config.Server.Blobs)
+	if config.Server.UsagePath != "" {
+		s.usage = toolserver.NewUsageStore(config.Server.UsagePath, logger)
+		if err := s.tools.SetUsageStore(s.usage); err != nil {
+			logger.Error("Failed to load tool usage", zap.Error(err), zap.String("path", config.Server.UsagePath))
+		}
+	}
+	s.createMCPServer()
+
+	return s, nil
+}
+
+// NewSharedSimpleMCPServer creates an MCP server using mcp-go library that serves the
+// tools and documents of shared state. Call AddSharedTools once the primary server of
+// the state has loaded them.
+func NewSharedSimpleMCPServer(config *types.ResolvedConfig, logger *utils.Logger, shared *toolserver.SharedState) (*SimpleMCPServer, error) {
+	s := &SimpleMCPServer{
+		config:     config,
+		logger:     logger,
+		httpClient: shared.HTTPClient,
+		tools:      shared.Tools,
+		documents:  shared.Documents,
+		executor:   shared.Executor,
+		webhooks:   shared.Webhooks,
+		bus:        shared.Bus,
+		blobs:      shared.Blobs,
+		sessions:   NewSessionStore(),
+		shared:     shared,
+		toolCount:  0,
+	}
+	s.createMCPServer()
+
+	return s, nil
+}
+
+// createMCPServer creates the mcp-go server
+func (s *SimpleMCPServer) createMCPServer() {
 	// Create the mcp-go server with basic capabilities
-	mcpServer := server.NewMCPServer(
+	s.mcpServer = server.NewMCPServer(
 		"swagger-docs-mcp",
 		version.GetSemanticVersion(),
 		server.WithToolCapabilities(false), // No list changed notifications
+		server.WithResourceCapabilities(false, false),
 		server.WithLogging(),
+		server.WithToolFilter(s.filterSessionTools),
+		server.WithToolFilter(s.loadLazySchemas),
 	)
 
-	return &SimpleMCPServer{
-		mcpServer: mcpServer,
-		config:    config,
-		logger:    logger,
-		toolCount: 0,
-	}, nil
+	// Binary responses of tool calls are kept for clients to read as resources
+	template := toolserver.BlobResourceTemplate()
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(template.URITemplate, template.Name, mcp.WithTemplateDescription(template.Description)),
+		s.readBlob,
+	)
+
+	if s.config.Server.Gateway {
+		s.addGatewayTools()
+	}
+}
+
+// AddSwaggerDocument keeps a parsed swagger document that mock responses of its tools
+// are derived from
+func (s *SimpleMCPServer) AddSwaggerDocument(info *types.SwaggerDocumentInfo, document *types.SwaggerDocument) {
+	s.documents.Put(info, document)
 }
 
 // AddSwaggerTool adds a swagger tool as an MCP tool
@@ -46,6 +128,85 @@ func (s *SimpleMCPServer) AddSwaggerTool(tool *types.GeneratedTool) error {
 		zap.String("method", tool.Endpoint.Method),
 		zap.String("path", tool.Endpoint.Path))
 
+	// Keep the generated tool for session filtering and usage statistics
+	if err := s.tools.RegisterTool(tool); err != nil {
+		return err
+	}
+
+	s.addMCPTool(tool)
+	return nil
+}
+
+// AddSharedTools adds the tools of the shared state as MCP tools, followed by the
+// composite tools, and keeps them in sync with later updates of the state's tools
+func (s *SimpleMCPServer) AddSharedTools() error {
+	for _, tool := range s.tools.GetAllTools() {
+		s.addMCPTool(tool)
+	}
+	if err := s.AddCompositeTools(); err != nil {
+		return err
+	}
+
+	s.shared.OnToolsChanged(s.syncTools)
+	return nil
+}
+
+// syncTools applies an update of the tools, by the shared state or the shared
+// registry, to the MCP tools
+func (s *SimpleMCPServer) syncTools(changes toolserver.ToolChanges) {
+	// Gateway tools find the state's tools when called
+	if s.config.Server.Gateway {
+		return
+	}
+
+	s.mcpServer.DeleteTools(append(append([]string{}, changes.Removed...), changes.Updated...)...)
+	s.toolCount -= len(changes.Removed) + len(changes.Updated)
+
+	for _, name := range append(append([]string{}, changes.Added...), changes.Updated...) {
+		if tool := s.tools.GetTool(name); tool != nil {
+			s.addMCPTool(tool)
+		}
+	}
+}
+
+// addMCPTool adds a swagger tool to the mcp-go server. In gateway mode the tool is only
+// called through the gateway tools.
+func (s *SimpleMCPServer) addMCPTool(tool *types.GeneratedTool) {
+	if s.config.Server.Gateway {
+		return
+	}
+
+	// Lazy schemas are generated when the tools are listed, not when they are added
+	var mcpTool mcp.Tool
+	if tool.InputSchema == nil && tool.LoadInputSchema != nil {
+		mcpTool = mcp.NewTool(tool.Name, mcp.WithDescription(tool.Description))
+	} else {
+		mcpTool = buildMCPTool(tool)
+	}
+
+	// Create tool handler
+	toolHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return s.executeTool(ctx, tool, request.GetArguments())
+	}
+
+	// Register the tool with the MCP server
+	s.mcpServer.AddTool(mcpTool, toolHandler)
+	s.toolCount++
+}
+
+// loadLazySchemas gives the listed tools with lazy schemas, which are added without
+// them, their schemas, as a tool filter
+func (s *SimpleMCPServer) loadLazySchemas(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	for i, listed := range tools {
+		if generated := s.tools.GetTool(listed.Name); generated != nil && generated.InputSchema == nil && generated.LoadInputSchema != nil {
+			tools[i] = buildMCPTool(generated)
+		}
+	}
+	return tools
+}
+
+// buildMCPTool converts a swagger tool and its input schema to an mcp-go tool
+func buildMCPTool(tool *types.GeneratedTool) mcp.Tool {
 	// Build tool options from swagger schema
 	var toolOptions []mcp.ToolOption
 
@@ -55,8 +216,8 @@ func (s *SimpleMCPServer) AddSwaggerTool(tool *types.GeneratedTool) error {
 	}
 
 	// Add parameters from swagger schema
-	if tool.InputSchema != nil {
-		if properties, exists := tool.InputSchema["properties"]; exists {
+	if schema := tool.Schema(); schema != nil {
+		if properties, exists := schema["properties"]; exists {
 			if propMap, ok := properties.(map[string]interface{}); ok {
 				for paramName, prop := range propMap {
 					if paramProp, ok := prop.(map[string]interface{}); ok {
@@ -80,7 +241,7 @@ func (s *SimpleMCPServer) AddSwaggerTool(tool *types.GeneratedTool) error {
 
 						// Check if required
 						required := false
-						if requiredFields, exists := tool.InputSchema["required"]; exists {
+						if requiredFields, exists := schema["required"]; exists {
 							if reqSlice, ok := requiredFields.([]interface{}); ok {
 								for _, reqField := range reqSlice {
 									if reqStr, ok := reqField.(string); ok && reqStr == paramName {
@@ -128,29 +289,62 @@ func (s *SimpleMCPServer) AddSwaggerTool(tool *types.GeneratedTool) error {
 	}
 
 	// Create the MCP tool
-	mcpTool := mcp.NewTool(tool.Name, toolOptions...)
-
-	// Create tool handler
-	toolHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Debug("Executing swagger tool via MCP",
-			zap.String("toolName", tool.Name),
-			zap.Any("arguments", request.Params.Arguments))
+	return mcp.NewTool(tool.Name, toolOptions...)
+}
 
-		// For now, return a simple response showing the tool was called
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent(fmt.Sprintf("Successfully called swagger tool '%s' with arguments: %v", tool.Name, request.Params.Arguments)),
-			},
-		}, nil
+// AddCompositeTools adds the configured composite tools as MCP tools. Call it after
+// adding the swagger tools: swagger tools take precedence over composite tools of the
+// same name.
+func (s *SimpleMCPServer) AddCompositeTools() error {
+	if s.config.Server.Gateway {
+		return nil
 	}
 
-	// Register the tool with the MCP server
-	s.mcpServer.AddTool(mcpTool, toolHandler)
-	s.toolCount++
+	for _, tool := range s.executor.CompositeTools() {
+		if s.tools.HasTool(tool.Name) {
+			s.logger.Warn("Composite tool is shadowed by a swagger tool of the same name", zap.String("name", tool.Name))
+			continue
+		}
 
+		schema, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			return fmt.Errorf("failed to encode input schema of composite tool %s: %w", tool.Name, err)
+		}
+
+		composite := tool
+		s.mcpServer.AddTool(mcp.NewToolWithRawSchema(tool.Name, tool.Description, schema), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return s.executeTool(ctx, composite, request.GetArguments())
+		})
+		s.toolCount++
+	}
 	return nil
 }
 
+// addGatewayTools adds the tools searching and calling the operations of the swagger
+// and composite tools, served in gateway mode instead of the tools themselves
+func (s *SimpleMCPServer) addGatewayTools() {
+	for _, gatewayTool := range toolserver.GatewayTools() {
+		schema, _ := json.Marshal(gatewayTool.InputSchema)
+		name := gatewayTool.Name
+		s.mcpServer.AddTool(mcp.NewToolWithRawSchema(name, gatewayTool.Description, schema), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if name == toolserver.GatewaySearchToolName {
+				result := s.executor.CallGatewaySearch(request.GetArguments(), toolserver.TenantFromContext(ctx))
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{mcp.NewTextContent(result.Content[0].Text)},
+					IsError: result.IsError,
+				}, nil
+			}
+
+			tool, arguments, err := s.executor.ResolveGatewayCall(request.GetArguments())
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return s.executeTool(ctx, tool, arguments)
+		})
+		s.toolCount++
+	}
+}
+
 // Start starts the MCP server (stdio mode)
 func (s *SimpleMCPServer) Start(ctx context.Context) error {
 	s.logger.Info("Starting MCP server (stdio mode)",
@@ -161,6 +355,23 @@ func (s *SimpleMCPServer) Start(ctx context.Context) error {
 	return server.ServeStdio(s.mcpServer)
 }
 
+// Close writes the buffered tool usage counters to the usage store, waits for the
+// queued webhook and message bus events to be sent, and stops sharing the tools, if
+// any is configured. Servers of shared state leave these to the state.
+func (s *SimpleMCPServer) Close() {
+	if s.shared == nil {
+		s.catalog.Close()
+		s.webhooks.Close()
+		s.bus.Close()
+	}
+	if s.usage == nil {
+		return
+	}
+	if err := s.usage.Flush(); err != nil {
+		s.logger.Error("Failed to save tool usage", zap.Error(err), zap.String("path", s.config.Server.UsagePath))
+	}
+}
+
 // StartHTTP starts the MCP server with HTTP transport (Streamable HTTP)
 func (s *SimpleMCPServer) StartHTTP(ctx context.Context, addr string) error {
 	s.logger.Info("Starting MCP HTTP server (Streamable HTTP)",
@@ -171,18 +382,52 @@ func (s *SimpleMCPServer) StartHTTP(ctx context.Context, addr string) error {
 	streamableServer := server.NewStreamableHTTPServer(
 		s.mcpServer,
 		server.WithEndpointPath("/mcp"),
+		server.WithHTTPContextFunc(s.bindSession),
 	)
 
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessions", s.handleSessions)
+	mux.HandleFunc("/stats/tools", s.handleToolStats)
+	if s.shared != nil {
+		mux.HandleFunc("/status/sources", s.handleSourceStatus)
+	}
+	mux.Handle("/", s.trackSessionTermination(streamableServer))
+
+	// Authentication sits inside CORS so that preflight requests are answered
+	authenticator, err := NewAuthenticator(ctx, s.config.Server.Auth, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to configure MCP HTTP authentication: %w", err)
+	}
+	if !authenticator.Enabled() {
+		s.logger.Warn("MCP HTTP endpoint has no authentication configured; set --mcp-auth-token or --mcp-oidc-issuer for remote deployments")
+	}
+
 	// Create HTTP server
 	httpServer := &http.Server{
 		Addr:    addr,
-		Handler: s.addCORSMiddleware(streamableServer),
+		Handler: s.addCORSMiddleware(authenticator.Middleware(mux)),
+	}
+
+	// Servers of shared state leave sharing the tools with other replicas to its
+	// primary server; others share the tools they were given before starting
+	if s.shared == nil {
+		loaded := make(chan struct{})
+		close(loaded)
+		s.catalog.Start(loaded, s.syncTools, func(bool, bool) {})
+	}
+
+	// Drop state for sessions whose clients went away without terminating them
+	go s.expireSessions(ctx)
+
+	listener, err := toolserver.Listen(addr, s.config.Server.SocketMode)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
 	// Start server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 			errChan <- err
 		}
 	}()
@@ -202,7 +447,7 @@ func (s *SimpleMCPServer) addCORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, Mcp-Session-Id")
 		
 		if r.Method == "OPTIONS" {
 			return
@@ -220,4 +465,251 @@ func (s *SimpleMCPServer) Stop() {
 // GetToolCount returns the number of registered tools
 func (s *SimpleMCPServer) GetToolCount() int {
 	return s.toolCount
-}
\ No newline at end of file
+}
+
+// TelemetryCounts returns the numbers of documents loaded and tools registered, for
+// telemetry reports
+func (s *SimpleMCPServer) TelemetryCounts() toolserver.TelemetryCounts {
+	return toolserver.TelemetryCounts{Documents: len(s.documents.All()), Tools: s.GetToolCount()}
+}
+
+// executeTool runs a swagger tool call using the calling session's settings
+func (s *SimpleMCPServer) executeTool(ctx context.Context, tool *types.GeneratedTool, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Executing swagger tool via MCP",
+		zap.String("toolName", tool.Name),
+		zap.Any("arguments", arguments))
+
+	// Composite tools are listed to every session and tenant, so they are not filtered
+	// here either
+	if tenant := toolserver.TenantFromContext(ctx); tool.Composite == nil && !tenant.Allows(tool) {
+		return mcp.NewToolResultError(fmt.Sprintf("tool %s is not available to tenant %s", tool.Name, tenant.Name)), nil
+	}
+
+	httpClient := s.httpClient
+	session := SessionStateFromContext(ctx)
+	if session != nil {
+		if tool.Composite == nil && !session.Filter().Matches(tool) {
+			return mcp.NewToolResultError(fmt.Sprintf("tool %s is not available in this session", tool.Name)), nil
+		}
+		if apiKey := session.APIKey(); apiKey != "" {
+			httpClient = session.HTTPClient(apiKey, s.sessionHTTPClient)
+		}
+	}
+
+	started := time.Now()
+	response, err := s.executor.Execute(ctx, httpClient, tool, arguments)
+	duration := time.Since(started)
+	if err != nil {
+		s.tools.RecordToolCall(tool.Name, duration, true)
+		s.toolCalled(ctx, toolserver.ToolCall{
+			Transport: types.TransportMCPHTTP,
+			ToolName:  tool.Name,
+			Arguments: arguments,
+			Duration:  duration,
+			Failed:    true,
+			Error:     err.Error(),
+		})
+		if session != nil {
+			session.RecordCall(tool.Name, true)
+		}
+		s.logger.Error("Tool execution failed", zap.Error(err), zap.String("toolName", tool.Name))
+		result := mcp.NewToolResultError(fmt.Sprintf("Error executing tool: %s", err.Error()))
+		result.Meta = toolserver.FailureMeta(err)
+		return result, nil
+	}
+
+	// Convert the response to MCP content, returning binary bodies as base64 and
+	// offloading or truncating large ones
+	result := toolserver.ResponseResult(response, s.blobs, s.config.ToolExecution)
+	s.tools.RecordToolCall(tool.Name, duration, result.IsError)
+	if session != nil {
+		session.RecordCall(tool.Name, result.IsError)
+	}
+
+	call := toolserver.ToolCall{
+		Transport: types.TransportMCPHTTP,
+		ToolName:  tool.Name,
+		Arguments: arguments,
+		Duration:  duration,
+		Failed:    result.IsError,
+	}
+	if result.IsError {
+		call.Error = result.Content[0].Text
+	}
+	s.toolCalled(ctx, call)
+
+	content := make([]mcp.Content, len(result.Content))
+	for i, item := range result.Content {
+		content[i] = toMCPContent(item)
+	}
+	return &mcp.CallToolResult{
+		Result:  mcp.Result{Meta: result.Meta},
+		Content: content,
+		IsError: result.IsError,
+	}, nil
+}
+
+// sessionHTTPClient builds the HTTP client of a session with an API key override. The
+// key also replaces the credentials configured for hosts and documents.
+func (s *SimpleMCPServer) sessionHTTPClient(apiKey string) *httpclient.Client {
+	return httpclient.NewSessionClient(s.config, apiKey, s.logger)
+}
+
+// toMCPContent converts the content of a tool result to its mcp-go type
+func toMCPContent(content types.MCPContent) mcp.Content {
+	switch content.Type {
+	case "image":
+		return mcp.NewImageContent(content.Data, content.MimeType)
+	case "audio":
+		return mcp.NewAudioContent(content.Data, content.MimeType)
+	case "resource":
+		if content.Resource == nil {
+			break
+		}
+		if content.Resource.Blob == "" {
+			return mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      content.Resource.URI,
+				MIMEType: content.Resource.MimeType,
+				Text:     content.Resource.Text,
+			})
+		}
+		return mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+			URI:      content.Resource.URI,
+			MIMEType: content.Resource.MimeType,
+			Blob:     content.Resource.Blob,
+		})
+	}
+	return mcp.NewTextContent(content.Text)
+}
+
+// readBlob handles resources/read requests for the binary responses of tool calls
+func (s *SimpleMCPServer) readBlob(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	content, ok := s.blobs.Get(request.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found or expired", request.Params.URI)
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      content.URI,
+		MIMEType: content.MimeType,
+		Blob:     content.Blob,
+	}}, nil
+}
+
+// toolCalled reports a completed tool call to the webhooks and the message bus, if
+// configured
+func (s *SimpleMCPServer) toolCalled(ctx context.Context, call toolserver.ToolCall) {
+	s.webhooks.ToolCalled(ctx, call)
+	s.bus.ToolCalled(ctx, call)
+}
+
+// filterSessionTools hides tools excluded by the calling session's dynamic filters or
+// by the calling client's tenant
+func (s *SimpleMCPServer) filterSessionTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	var filter toolserver.ToolFilter
+	if session := SessionStateFromContext(ctx); session != nil {
+		filter = session.Filter()
+	}
+	tenant := toolserver.TenantFromContext(ctx)
+	if filter.IsEmpty() && tenant == nil {
+		return tools
+	}
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		// Gateway, composite, and other tools that are not generated from a document
+		// stay listed; the session's filters and tenant apply to the generated tools
+		if generated := s.tools.GetTool(tool.Name); generated == nil || filter.Matches(generated) && tenant.Allows(generated) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// bindSession attaches the state of the request's MCP session to its context
+func (s *SimpleMCPServer) bindSession(ctx context.Context, r *http.Request) context.Context {
+	clientSession := server.ClientSessionFromContext(ctx)
+	if clientSession == nil || clientSession.SessionID() == "" {
+		return ctx
+	}
+	return withSessionState(ctx, s.sessions.Bind(clientSession.SessionID(), r))
+}
+
+// trackSessionTermination drops session state when a client terminates its session
+func (s *SimpleMCPServer) trackSessionTermination(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			if state := s.sessions.Delete(r.Header.Get("Mcp-Session-Id")); state != nil {
+				s.logSessionEnd(state, "terminated")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// expireSessions periodically drops idle session state until the context is cancelled
+func (s *SimpleMCPServer) expireSessions(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, state := range s.sessions.Expire(sessionIdleTimeout) {
+				s.logSessionEnd(state, "expired")
+			}
+		}
+	}
+}
+
+// logSessionEnd records a session's final usage counters
+func (s *SimpleMCPServer) logSessionEnd(state *SessionState, reason string) {
+	stats := state.Stats()
+	s.logger.Info("MCP HTTP session ended",
+		zap.String("sessionID", stats.ID),
+		zap.String("reason", reason),
+		zap.Int("calls", stats.Calls),
+		zap.Int("errors", stats.Errors),
+		zap.Duration("duration", stats.LastSeen.Sub(stats.CreatedAt)))
+}
+
+// handleSessions reports usage counters for active MCP HTTP sessions
+func (s *SimpleMCPServer) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessions := s.sessions.Stats()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+// handleToolStats reports per-tool call counts and error rates. Registered tools that
+// were never called are included with ?unused=true.
+func (s *SimpleMCPServer) handleToolStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	includeUnused := strings.ToLower(r.URL.Query().Get("unused")) == "true"
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.tools.UsageReport(includeUnused))
+}
+
+// handleSourceStatus reports which swagger paths and URLs of the shared state loaded
+func (s *SimpleMCPServer) handleSourceStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.shared.Sources.Report())
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	httpclient "swagger-docs-mcp/pkg/http"
+	toolserver "swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+func TestFilterSessionTools(t *testing.T) {
+	server := &SimpleMCPServer{config: types.DefaultConfig(), tools: toolserver.NewToolRegistry()}
+	for name, packageID := range map[string]string{"get_forecast": "weather", "get_tiles": "maps"} {
+		tool := &types.GeneratedTool{
+			Name:         name,
+			Endpoint:     &types.SwaggerEndpoint{Method: "get", Path: "/" + name},
+			DocumentInfo: &types.SwaggerDocumentInfo{Title: packageID, PackageIDs: []string{packageID}},
+		}
+		if err := server.tools.RegisterTool(tool); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The composite and gateway tools are not in the registry
+	tools := []mcp.Tool{
+		mcp.NewTool("get_forecast"),
+		mcp.NewTool("get_tiles"),
+		mcp.NewTool("forecast_and_tiles"),
+		mcp.NewTool(toolserver.GatewaySearchToolName),
+	}
+
+	tests := []struct {
+		name   string
+		filter toolserver.ToolFilter
+		tenant *toolserver.Tenant
+		want   []string
+	}{
+		{
+			name: "no filter or tenant",
+			want: []string{"get_forecast", "get_tiles", "forecast_and_tiles", toolserver.GatewaySearchToolName},
+		},
+		{
+			name:   "session filter",
+			filter: toolserver.ToolFilter{PackageIDs: []string{"weather"}},
+			want:   []string{"get_forecast", "forecast_and_tiles", toolserver.GatewaySearchToolName},
+		},
+		{
+			name:   "tenant",
+			tenant: &toolserver.Tenant{Name: "maps", Filter: toolserver.ToolFilter{PackageIDs: []string{"maps"}}},
+			want:   []string{"get_tiles", "forecast_and_tiles", toolserver.GatewaySearchToolName},
+		},
+		{
+			name:   "session filter and tenant",
+			filter: toolserver.ToolFilter{PackageIDs: []string{"weather"}},
+			tenant: &toolserver.Tenant{Name: "maps", Filter: toolserver.ToolFilter{PackageIDs: []string{"maps"}}},
+			want:   []string{"forecast_and_tiles", toolserver.GatewaySearchToolName},
+		},
+		{
+			name:   "metadata filter",
+			filter: toolserver.ToolFilter{Metadata: []string{"tier=premium"}},
+			want:   []string{"forecast_and_tiles", toolserver.GatewaySearchToolName},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := withSessionState(context.Background(), &SessionState{ID: "session", filter: test.filter})
+			if test.tenant != nil {
+				ctx = toolserver.WithTenant(ctx, test.tenant)
+			}
+
+			var got []string
+			for _, tool := range server.filterSessionTools(ctx, tools) {
+				got = append(got, tool.Name)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("filterSessionTools() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSessionHTTPClient(t *testing.T) {
+	const sessionKey = "session-key"
+
+	tests := []struct {
+		name string
+		// hostCredential is configured for the host of the upstream, if set
+		hostCredential *types.CredentialConfig
+		// documentCredential is the credential of the tool's document, if set
+		documentCredential *types.CredentialConfig
+		wantHeader         string
+		wantValue          string
+		wantQuery          string
+	}{
+		{
+			name:       "no credential",
+			wantHeader: "Authorization",
+			wantValue:  "Bearer " + sessionKey,
+		},
+		{
+			name:           "bearer host credential",
+			hostCredential: &types.CredentialConfig{Secret: "host-secret"},
+			wantHeader:     "Authorization",
+			wantValue:      "Bearer " + sessionKey,
+		},
+		{
+			name:           "apikey host credential",
+			hostCredential: &types.CredentialConfig{Scheme: types.CredentialSchemeAPIKey, Name: "X-Upstream-Key", Secret: "host-secret"},
+			wantHeader:     "X-Upstream-Key",
+			wantValue:      sessionKey,
+		},
+		{
+			name:           "query host credential",
+			hostCredential: &types.CredentialConfig{Scheme: types.CredentialSchemeQuery, Name: "key", Secret: "host-secret"},
+			wantQuery:      "key=" + sessionKey,
+		},
+		{
+			name:           "basic host credential",
+			hostCredential: &types.CredentialConfig{Scheme: types.CredentialSchemeBasic, Secret: "user:password"},
+			wantHeader:     "Authorization",
+			wantValue:      "Bearer " + sessionKey,
+		},
+		{
+			name:               "document credential",
+			hostCredential:     &types.CredentialConfig{Secret: "host-secret"},
+			documentCredential: &types.CredentialConfig{Scheme: types.CredentialSchemeAPIKey, Secret: "document-secret"},
+			wantHeader:         "X-API-Key",
+			wantValue:          sessionKey,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			received := make(chan *http.Request, 1)
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received <- r
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer upstream.Close()
+
+			config := types.DefaultConfig()
+			config.HTTP.Retries = 0
+			config.Auth.APIKey = "server-key"
+			if test.hostCredential != nil {
+				host, _ := url.Parse(upstream.URL)
+				config.Auth.Credentials = map[string]types.CredentialConfig{host.Host: *test.hostCredential}
+			}
+			server := &SimpleMCPServer{config: config, logger: utils.NewLogger(types.LoggingConfig{Level: "error"})}
+
+			endpoint := &types.SwaggerEndpoint{Path: "/forecast", Method: "get", Servers: []types.SwaggerServer{{URL: upstream.URL}}}
+			policy := httpclient.RequestPolicy{Credential: test.documentCredential}
+			if _, err := server.sessionHTTPClient(sessionKey).ExecuteRequestWithPolicy(context.Background(), endpoint, nil, policy); err != nil {
+				t.Fatalf("ExecuteRequestWithPolicy() error = %v", err)
+			}
+
+			got := <-received
+			if test.wantHeader != "" {
+				if value := got.Header.Get(test.wantHeader); value != test.wantValue {
+					t.Errorf("%s header = %q, want %q", test.wantHeader, value, test.wantValue)
+				}
+			}
+			if got.URL.RawQuery != test.wantQuery {
+				t.Errorf("query = %q, want %q", got.URL.RawQuery, test.wantQuery)
+			}
+			if config.Auth.APIKey != "server-key" {
+				t.Errorf("server API key = %q after building the session client, want it unchanged", config.Auth.APIKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// responseBatch collects the responses to the requests of a JSON-RPC batch, which are
+// written together as one array once every request has been answered
+type responseBatch struct {
+	pending   int
+	responses []types.MCPResponse
+}
+
+// batchedRequestID is the ID of a request of a batch. It carries the batch, so that
+// the response to the request is collected there and not mistaken for the response to
+// a request with the same ID outside the batch. It is written as the ID's JSON text.
+type batchedRequestID struct {
+	raw      json.RawMessage
+	batch    *responseBatch
+	answered bool
+}
+
+// MarshalJSON writes the ID as it was sent
+func (id *batchedRequestID) MarshalJSON() ([]byte, error) {
+	return id.raw, nil
+}
+
+// handleBatch handles a JSON-RPC batch: each request is dispatched as if it had been
+// sent alone, and their responses are written as one array. Notifications are not
+// answered, so a batch of notifications gets no response at all.
+func (s *MCPServer) handleBatch(ctx context.Context, line string) {
+	var messages []json.RawMessage
+	if err := json.Unmarshal([]byte(line), &messages); err != nil {
+		s.logger.Error("Failed to parse JSON-RPC batch", zap.Error(err), zap.String("rawMessage", line))
+		s.sendErrorResponse(nil, -32700, "Parse error", nil)
+		return
+	}
+	if len(messages) == 0 {
+		s.sendErrorResponse(nil, -32600, "Invalid Request", nil)
+		return
+	}
+
+	// Requests are registered before any is dispatched, so that the batch is only
+	// written once the last of them is answered
+	batch := &responseBatch{}
+	seen := make(map[string]bool)
+	var requests []*types.MCPRequest
+	for _, message := range messages {
+		request, err := decodeRequest(message)
+		if err != nil || request.Method == "" {
+			batch.responses = append(batch.responses, errorResponse(nil, -32600, "Invalid Request"))
+			continue
+		}
+		if request.ID != nil {
+			key := requestKey(request.ID)
+			if seen[key] {
+				batch.responses = append(batch.responses, errorResponse(request.ID, -32600, "Invalid Request: duplicate request ID"))
+				continue
+			}
+			seen[key] = true
+			batch.pending++
+			request.ID = &batchedRequestID{raw: request.ID.(json.RawMessage), batch: batch}
+		}
+		requests = append(requests, request)
+	}
+	answered := batch.pending == 0
+
+	s.logger.Debug("Handling JSON-RPC batch", zap.Int("messages", len(messages)), zap.Int("requests", batch.pending))
+
+	for _, request := range requests {
+		if err := s.handleRequest(ctx, request); err != nil {
+			s.logger.Error("Failed to handle request", zap.Error(err), zap.String("method", request.Method))
+		}
+		// Tool calls are answered in the background; other requests are answered by the
+		// time they are handled, or not at all
+		if request.ID != nil && request.Method != "tools/call" {
+			s.answerBatchedRequest(request.ID, nil)
+		}
+	}
+
+	if answered {
+		s.writeBatch(batch)
+	}
+}
+
+// answerBatchedRequest records the response to a request of a batch, or that it has
+// none, writing the batch once all its requests are answered. It reports whether the
+// request belongs to a batch.
+func (s *MCPServer) answerBatchedRequest(id interface{}, response *types.MCPResponse) bool {
+	batched, ok := id.(*batchedRequestID)
+	if !ok {
+		return false
+	}
+
+	s.batchMutex.Lock()
+	if batched.answered {
+		s.batchMutex.Unlock()
+		return true
+	}
+	batched.answered = true
+	batch := batched.batch
+	batch.pending--
+	if response != nil {
+		batch.responses = append(batch.responses, *response)
+	}
+	complete := batch.pending == 0
+	s.batchMutex.Unlock()
+
+	if complete {
+		s.writeBatch(batch)
+	}
+	return true
+}
+
+// writeBatch writes the responses of a batch as one array, unless it has none
+func (s *MCPServer) writeBatch(batch *responseBatch) {
+	if len(batch.responses) == 0 {
+		return
+	}
+	if err := s.sendMessage(batch.responses); err != nil {
+		s.logger.Error("Failed to send batch response", zap.Error(err))
+	}
+}
+
+// errorResponse returns a JSON-RPC error response
+func errorResponse(id interface{}, code int, message string) types.MCPResponse {
+	return types.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &types.MCPError{Code: code, Message: message},
+	}
+}
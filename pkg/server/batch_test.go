@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// batchOutcome summarizes a response as the JSON text of its ID, or <nil>, and its
+// error code, or "ok"
+func batchOutcome(response map[string]json.RawMessage) string {
+	id := "<nil>"
+	if raw, ok := response["id"]; ok && string(raw) != "null" {
+		id = string(raw)
+	}
+	outcome := "ok"
+	if raw, ok := response["error"]; ok {
+		var responseError types.MCPError
+		json.Unmarshal(raw, &responseError)
+		outcome = fmt.Sprintf("%d", responseError.Code)
+	}
+	return id + ":" + outcome
+}
+
+func TestHandleBatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch string
+		// want holds the outcomes of the responses of a batch written as one array,
+		// in any order; an empty want expects no output
+		want []string
+		// wantSingle expects a single response rather than an array
+		wantSingle string
+	}{
+		{
+			name:  "one request",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"tools/list"}]`,
+			want:  []string{"1:ok"},
+		},
+		{
+			name: "requests and notifications",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},
+				{"jsonrpc":"2.0","method":"notifications/unknown"},
+				{"jsonrpc":"2.0","id":"b","method":"prompts/list"}]`,
+			want: []string{"1:ok", `"b":ok`},
+		},
+		{
+			name:  "only notifications",
+			batch: `[{"jsonrpc":"2.0","method":"notifications/unknown"},{"jsonrpc":"2.0","method":"notifications/other"}]`,
+		},
+		{
+			name:  "unknown method",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"unknown/method"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`,
+			want:  []string{"1:-32601", "2:ok"},
+		},
+		{
+			name:  "invalid members",
+			batch: `[1, {"jsonrpc":"2.0","id":3}, {"jsonrpc":"2.0","id":1,"method":"tools/list"}]`,
+			want:  []string{"<nil>:-32600", "<nil>:-32600", "1:ok"},
+		},
+		{
+			name:  "only invalid members",
+			batch: `[1, 2]`,
+			want:  []string{"<nil>:-32600", "<nil>:-32600"},
+		},
+		{
+			name:  "duplicate request ID",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},{"jsonrpc":"2.0","id":1,"method":"prompts/list"}]`,
+			want:  []string{"1:-32600", "1:ok"},
+		},
+		{
+			name:  "string and number IDs",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},{"jsonrpc":"2.0","id":"1","method":"prompts/list"}]`,
+			want:  []string{"1:ok", `"1":ok`},
+		},
+		{
+			name:  "IDs are written as sent",
+			batch: `[{"jsonrpc":"2.0","id":1e6,"method":"tools/list"},{"jsonrpc":"2.0","id":1.50,"method":"prompts/list"}]`,
+			want:  []string{"1e6:ok", "1.50:ok"},
+		},
+		{
+			name:  "tool calls are answered in the background",
+			batch: `[{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"missing"}},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`,
+			want:  []string{"1:-32601", "2:ok"},
+		},
+		{
+			name:       "empty batch",
+			batch:      `[]`,
+			wantSingle: "<nil>:-32600",
+		},
+		{
+			name:       "malformed batch",
+			batch:      `[{"jsonrpc":"2.0","id":1,`,
+			wantSingle: "<nil>:-32700",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewMCPServer(types.DefaultConfig(), utils.NewLogger(types.LoggingConfig{Level: "error"}))
+			output := &bytes.Buffer{}
+			server.stdout = output
+
+			server.handleBatch(context.Background(), test.batch)
+			server.wg.Wait()
+
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			if output.Len() == 0 {
+				lines = nil
+			}
+
+			if test.wantSingle != "" {
+				if len(lines) != 1 {
+					t.Fatalf("handleBatch() wrote %d messages, want 1: %q", len(lines), lines)
+				}
+				var response map[string]json.RawMessage
+				if err := json.Unmarshal([]byte(lines[0]), &response); err != nil {
+					t.Fatalf("handleBatch() wrote %q, want a single response: %v", lines[0], err)
+				}
+				if got := batchOutcome(response); got != test.wantSingle {
+					t.Errorf("handleBatch() responded %s, want %s", got, test.wantSingle)
+				}
+				return
+			}
+
+			if len(test.want) == 0 {
+				if len(lines) != 0 {
+					t.Fatalf("handleBatch() wrote %q, want no response", lines)
+				}
+				return
+			}
+			if len(lines) != 1 {
+				t.Fatalf("handleBatch() wrote %d messages, want one array: %q", len(lines), lines)
+			}
+			var responses []map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(lines[0]), &responses); err != nil {
+				t.Fatalf("handleBatch() wrote %q, want an array of responses: %v", lines[0], err)
+			}
+			got := make([]string, len(responses))
+			for i, response := range responses {
+				got[i] = batchOutcome(response)
+			}
+			sort.Strings(got)
+			want := append([]string(nil), test.want...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("handleBatch() responded %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAnswerBatchedRequest(t *testing.T) {
+	server := NewMCPServer(types.DefaultConfig(), utils.NewLogger(types.LoggingConfig{Level: "error"}))
+	output := &bytes.Buffer{}
+	server.stdout = output
+
+	batch := &responseBatch{pending: 2}
+	first := &batchedRequestID{raw: json.RawMessage(`1`), batch: batch}
+	second := &batchedRequestID{raw: json.RawMessage(`"2"`), batch: batch}
+
+	// A request that does not belong to a batch is answered on its own, even when it
+	// has the ID of a pending request of a batch
+	for _, id := range []interface{}{json.RawMessage(`1`), json.RawMessage(`"2"`), 1} {
+		if server.answerBatchedRequest(id, nil) {
+			t.Fatalf("answerBatchedRequest(%v) = true for a request outside a batch", id)
+		}
+	}
+
+	response := errorResponse(first, -32603, "Internal error")
+	if !server.answerBatchedRequest(first, &response) {
+		t.Fatal("answerBatchedRequest() = false for a request of a batch")
+	}
+	if output.Len() != 0 {
+		t.Fatalf("answerBatchedRequest() wrote %q before the batch was complete", output.String())
+	}
+
+	// A request answered without a response, such as a notification, completes the
+	// batch without adding to it
+	if !server.answerBatchedRequest(second, nil) {
+		t.Fatal("answerBatchedRequest() = false for the last request of a batch")
+	}
+	var responses []map[string]json.RawMessage
+	if err := json.Unmarshal(output.Bytes(), &responses); err != nil {
+		t.Fatalf("answerBatchedRequest() wrote %q, want an array: %v", output.String(), err)
+	}
+	if got := len(responses); got != 1 || batchOutcome(responses[0]) != "1:-32603" {
+		t.Errorf("answerBatchedRequest() wrote %q, want the one error response", output.String())
+	}
+
+	// A request answered again belongs to its batch, which was already written
+	output.Reset()
+	if !server.answerBatchedRequest(first, &response) || output.Len() != 0 {
+		t.Errorf("answerBatchedRequest() of an answered request wrote %q, want nothing", output.String())
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	batch := &responseBatch{}
+	tests := []struct {
+		name string
+		id   interface{}
+		want string
+	}{
+		{name: "number", id: json.RawMessage(`1`), want: `1`},
+		{name: "string", id: json.RawMessage(`"1"`), want: `"1"`},
+		{name: "exponent", id: json.RawMessage(`1e6`), want: `1e6`},
+		{name: "batched request", id: &batchedRequestID{raw: json.RawMessage(`"1"`), batch: batch}, want: `"1"`},
+		{name: "decoded string", id: "1", want: `"1"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := requestKey(test.id); got != test.want {
+				t.Errorf("requestKey(%v) = %s, want %s", test.id, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"encoding/base64"
+	"mime"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// BlobURIPrefix prefixes the URIs of the responses kept in a blob store
+const BlobURIPrefix = "blob://responses/"
+
+// textMediaTypes are the media types outside text/* whose bodies are text
+var textMediaTypes = map[string]bool{
+	"application/json":                  true,
+	"application/xml":                   true,
+	"application/javascript":            true,
+	"application/ecmascript":            true,
+	"application/x-www-form-urlencoded": true,
+	"application/yaml":                  true,
+	"application/x-yaml":                true,
+	"application/graphql":               true,
+	"application/x-ndjson":              true,
+	"application/csv":                   true,
+}
+
+// BlobStore keeps the responses of tool calls for a while, so that clients can read
+// them as resources, in particular those too large to return inline
+type BlobStore struct {
+	config types.BlobsConfig
+
+	mutex sync.Mutex
+	blobs map[string]*storedBlob
+	// order holds the URIs of the blobs from oldest to newest. As the TTL is the
+	// same for all, they also expire in this order.
+	order []string
+	size  int
+}
+
+// storedBlob is a response kept in a blob store
+type storedBlob struct {
+	mimeType string
+	data     []byte
+	// text bodies are read as text rather than base64
+	text    bool
+	expires time.Time
+}
+
+// NewBlobStore creates an empty blob store
+func NewBlobStore(config types.BlobsConfig) *BlobStore {
+	return &BlobStore{
+		config: config,
+		blobs:  make(map[string]*storedBlob),
+	}
+}
+
+// Put keeps a response and returns the URI to read it from and when it expires,
+// dropping the oldest responses to make room. It returns false for responses larger
+// than the store.
+func (b *BlobStore) Put(data []byte, mimeType string, text bool) (string, time.Time, bool) {
+	if len(data) > b.config.MaxStoredSize {
+		return "", time.Time{}, false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.expire(time.Now())
+	for b.size+len(data) > b.config.MaxStoredSize && len(b.order) > 0 {
+		b.drop()
+	}
+
+	uri := BlobURIPrefix + uuid.New().String()
+	blob := &storedBlob{mimeType: mimeType, data: data, text: text, expires: time.Now().Add(b.config.TTL)}
+	b.blobs[uri] = blob
+	b.order = append(b.order, uri)
+	b.size += len(data)
+	return uri, blob.expires, true
+}
+
+// Get returns the resource content of a kept response, or false when it is unknown or
+// has expired
+func (b *BlobStore) Get(uri string) (types.MCPResourceContent, bool) {
+	if b == nil || !strings.HasPrefix(uri, BlobURIPrefix) {
+		return types.MCPResourceContent{}, false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.expire(time.Now())
+	blob, ok := b.blobs[uri]
+	if !ok {
+		return types.MCPResourceContent{}, false
+	}
+	if blob.text {
+		return types.MCPResourceContent{URI: uri, MimeType: blob.mimeType, Text: string(blob.data)}, true
+	}
+	return types.MCPResourceContent{
+		URI:      uri,
+		MimeType: blob.mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(blob.data),
+	}, true
+}
+
+// offloads reports whether a response body of size bytes is kept in the store rather
+// than returned inline
+func (b *BlobStore) offloads(size int, binary bool) bool {
+	if binary {
+		return size > b.config.MaxInlineSize
+	}
+	return b.config.MaxInlineTextSize > 0 && size > b.config.MaxInlineTextSize
+}
+
+// expire drops the blobs that expired by now. The caller holds the mutex.
+func (b *BlobStore) expire(now time.Time) {
+	for len(b.order) > 0 && !now.Before(b.blobs[b.order[0]].expires) {
+		b.drop()
+	}
+}
+
+// drop drops the oldest blob. The caller holds the mutex.
+func (b *BlobStore) drop() {
+	uri := b.order[0]
+	b.order = b.order[1:]
+	b.size -= len(b.blobs[uri].data)
+	delete(b.blobs, uri)
+}
+
+// BlobResourceTemplate describes the URIs responses kept in a blob store are read from
+func BlobResourceTemplate() types.MCPResourceTemplate {
+	return types.MCPResourceTemplate{
+		URITemplate: BlobURIPrefix + "{id}",
+		Name:        "Tool call response",
+		Description: "Response of a tool call too large to return inline, or an embedded binary response, readable for a while after the call",
+	}
+}
+
+// ResponseResult converts an upstream response to the result of a tool call. Upstream
+// errors are returned in one structured shape, whatever the API's error body. Text is
+// returned as text, images and audio as base64 data, and other binary bodies, e.g.
+// PDFs, as embedded blob resources, which are also kept in the blob store so that
+// their URIs can be read again. Bodies larger than the inline limits of
+// server.blobs are only kept in the blob store; the result summarizes them and names
+// the resource URI to read them from, under _meta.offloaded too. Text returned inline
+// is cut to its head and tail beyond toolExecution.maxResponseChars, as reported
+// under _meta.truncated.
+func ResponseResult(response *http.Response, blobs *BlobStore, execution types.ToolExecutionConfig) types.MCPCallToolResult {
+	result := types.MCPCallToolResult{
+		IsError: response.StatusCode >= 400,
+		Meta:    ResultMeta(response),
+	}
+	if result.IsError {
+		result.Content = []types.MCPContent{{
+			Type:     "text",
+			Text:     ErrorResultText(response),
+			MimeType: "application/json",
+		}}
+		return result
+	}
+
+	contentType := response.Headers["Content-Type"]
+	mediaType, binary := binaryMediaType(contentType, response.Body)
+	if blobs != nil && blobs.offloads(len(response.Body), binary) {
+		content, uri := offloadContent(response.Body, mediaType, binary, blobs)
+		result.Content = []types.MCPContent{content}
+		if uri != "" {
+			result.Meta["offloaded"] = map[string]interface{}{
+				"uri":      uri,
+				"mimeType": mediaType,
+				"size":     len(response.Body),
+			}
+		}
+		return result
+	}
+
+	if !binary {
+		text, truncated := truncateText(string(response.Body), execution.MaxResponseChars)
+		result.Content = []types.MCPContent{{
+			Type:     "text",
+			Text:     text,
+			MimeType: contentType,
+		}}
+		if truncated {
+			result.Meta["truncated"] = map[string]interface{}{
+				"originalChars": utf8.RuneCount(response.Body),
+				"originalSize":  len(response.Body),
+				"maxChars":      execution.MaxResponseChars,
+			}
+		}
+		return result
+	}
+
+	data := base64.StdEncoding.EncodeToString(response.Body)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		result.Content = []types.MCPContent{{Type: "image", Data: data, MimeType: mediaType}}
+	case strings.HasPrefix(mediaType, "audio/"):
+		result.Content = []types.MCPContent{{Type: "audio", Data: data, MimeType: mediaType}}
+	default:
+		uri := BlobURIPrefix + uuid.New().String()
+		if blobs != nil {
+			if stored, _, ok := blobs.Put(response.Body, mediaType, false); ok {
+				uri = stored
+			}
+		}
+		result.Content = []types.MCPContent{{
+			Type: "resource",
+			Resource: &types.MCPResourceContent{
+				URI:      uri,
+				MimeType: mediaType,
+				Blob:     data,
+			},
+		}}
+	}
+	return result
+}
+
+// binaryMediaType returns the media type of a response body and whether the body is
+// binary. Bodies without a content type are binary unless they are valid UTF-8, and
+// their media type is sniffed.
+func binaryMediaType(contentType string, body []byte) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if contentType == "" || err != nil {
+		if utf8.Valid(body) {
+			return "text/plain", false
+		}
+		mediaType, _, _ = mime.ParseMediaType(nethttp.DetectContentType(body))
+		return mediaType, true
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"),
+		textMediaTypes[mediaType]:
+		return mediaType, false
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "font/"),
+		mediaType == "application/octet-stream":
+		return mediaType, true
+	}
+	// Other application types are binary unless their body is text
+	return mediaType, !utf8.Valid(body)
+}
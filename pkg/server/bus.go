@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+const (
+	// busQueueSize bounds the events waiting to be published; events beyond it are
+	// dropped
+	busQueueSize = 10000
+	// busBatchSize bounds the events published at once
+	busBatchSize = 100
+	// busAttempts is how often a batch of events is published before it is dropped
+	busAttempts = 3
+	// busTimeout bounds connecting to a broker and each publish
+	busTimeout = 10 * time.Second
+	// busDrainTimeout bounds how long Close waits for queued events to be published
+	busDrainTimeout = 5 * time.Second
+)
+
+// busToolsUpdated is the type of the events published for changes to the tools
+const busToolsUpdated = "tools_updated"
+
+// BusEvent is an event published to the message bus in the json serialization
+type BusEvent struct {
+	ID     string      `json:"id"`
+	Type   string      `json:"type"`
+	Source string      `json:"source"`
+	Time   time.Time   `json:"time"`
+	Data   interface{} `json:"data"`
+}
+
+// cloudEvent is an event in the structured JSON format of CloudEvents 1.0
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Type            string      `json:"type"`
+	Source          string      `json:"source"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// busToolsUpdatedEvent is the data of a tools_updated event
+type busToolsUpdatedEvent struct {
+	Changes   ToolChanges `json:"changes"`
+	ToolCount int         `json:"toolCount"`
+}
+
+// busMessage is an encoded event and the key it is published under: the tool it is
+// about, or its type
+type busMessage struct {
+	key   string
+	value json.RawMessage
+}
+
+// busPublisher delivers messages to a message bus
+type busPublisher interface {
+	publish(messages []busMessage) error
+	close() error
+}
+
+// EventBus publishes the events of tool calls and of changes to the tools to a message
+// bus in the background, so that enterprise pipelines can consume the activity of
+// several server instances. Events are queued and published in order, in batches. A
+// nil EventBus publishes nothing.
+type EventBus struct {
+	config          types.BusConfig
+	source          string
+	sensitiveFields []string
+	publisher       busPublisher
+	queue           chan busMessage
+	done            chan struct{}
+	logger          *utils.Logger
+	closed          bool
+	mutex           sync.RWMutex
+}
+
+// NewEventBus starts publishing to the configured message bus, returning nil when
+// none is configured
+func NewEventBus(config *types.ResolvedConfig, logger *utils.Logger) *EventBus {
+	bus := config.Server.Bus
+	if bus.Type == "" {
+		return nil
+	}
+
+	// Events name the instance they come from, so that consumers can tell the
+	// instances publishing to one topic apart
+	hostname, _ := os.Hostname()
+	b := &EventBus{
+		config:          bus,
+		source:          "/swagger-docs-mcp/" + hostname,
+		sensitiveFields: config.Server.Events.SensitiveFields,
+		queue:           make(chan busMessage, busQueueSize),
+		done:            make(chan struct{}),
+		logger:          logger.Child("event-bus"),
+	}
+	switch bus.Type {
+	case types.BusKafka:
+		b.publisher = newKafkaPublisher(bus)
+	default:
+		b.publisher = newNATSPublisher(bus, b.logger)
+	}
+	go b.run()
+	return b
+}
+
+// ToolCalled publishes the tool_execution event of a completed tool call, with its
+// arguments redacted like those of SSE events
+func (b *EventBus) ToolCalled(ctx context.Context, call ToolCall) {
+	if b == nil {
+		return
+	}
+	b.publish(types.WebhookToolExecution, call.ToolName, newToolCallEvent(ctx, call, b.sensitiveFields))
+}
+
+// ToolsChanged publishes the tools_updated event of a change to the tools, such as a
+// refresh or reload of their documents
+func (b *EventBus) ToolsChanged(changes ToolChanges, toolCount int) {
+	if b == nil {
+		return
+	}
+	b.publish(busToolsUpdated, "", busToolsUpdatedEvent{Changes: changes, ToolCount: toolCount})
+}
+
+// publish serializes an event and queues it, keyed by its subject or otherwise its type
+func (b *EventBus) publish(eventType string, subject string, data interface{}) {
+	id := uuid.New().String()
+	now := time.Now().UTC()
+
+	var event interface{} = BusEvent{ID: id, Type: eventType, Source: b.source, Time: now, Data: data}
+	if b.config.Serialization == types.BusSerializationCloudEvents {
+		event = cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              id,
+			Type:            "swagger-docs-mcp." + eventType,
+			Source:          b.source,
+			Subject:         subject,
+			Time:            now,
+			DataContentType: "application/json",
+			Data:            data,
+		}
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		b.logger.Warn("Failed to encode bus event", zap.Error(err), zap.String("type", eventType))
+		return
+	}
+
+	key := subject
+	if key == "" {
+		key = eventType
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.queue <- busMessage{key: key, value: encoded}:
+	default:
+		b.logger.Warn("Dropping bus event, the queue is full", zap.String("type", eventType))
+	}
+}
+
+// Close stops queueing events and waits a few seconds for the queued ones to be
+// published
+func (b *EventBus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.mutex.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(busDrainTimeout):
+		b.logger.Warn("Gave up waiting for bus events to be published")
+	}
+}
+
+// run publishes the queued events in batches until the queue is closed
+func (b *EventBus) run() {
+	defer close(b.done)
+	defer b.publisher.close()
+
+	for message := range b.queue {
+		batch := []busMessage{message}
+	collect:
+		for len(batch) < busBatchSize {
+			select {
+			case next, ok := <-b.queue:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+		b.deliver(batch)
+	}
+}
+
+// deliver publishes a batch of events, trying again with backoff before dropping it
+func (b *EventBus) deliver(batch []busMessage) {
+	var err error
+	for attempt := 0; attempt < busAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if err = b.publisher.publish(batch); err == nil {
+			return
+		}
+	}
+	b.logger.Warn("Dropping bus events that failed to publish", zap.Error(err),
+		zap.String("type", b.config.Type), zap.String("topic", b.config.Topic), zap.Int("events", len(batch)))
+}
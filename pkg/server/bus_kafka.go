@@ -0,0 +1,123 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/version"
+)
+
+// kafkaContentType is the content type of records with JSON values of the v2 API of
+// the Kafka REST proxy
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// kafkaRecord is a record produced with the Kafka REST proxy
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaProduceResponse is the part of the answer of the Kafka REST proxy to a produce
+// request that reports records it failed to produce
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// kafkaPublisher publishes events to a Kafka topic through the v2 API of a Kafka REST
+// proxy, such as Confluent's, so that no Kafka client is needed. Brokers are the base
+// URLs of proxies, tried in order until one accepts the records; records are keyed by
+// the tool they are about, so that the events of a tool keep their order.
+type kafkaPublisher struct {
+	brokers []string
+	topic   string
+	headers map[string]string
+	client  *http.Client
+}
+
+// newKafkaPublisher creates a publisher to the topic of a bus configuration
+func newKafkaPublisher(config types.BusConfig) *kafkaPublisher {
+	return &kafkaPublisher{
+		brokers: config.Brokers,
+		topic:   config.Topic,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: busTimeout},
+	}
+}
+
+// publish produces messages to the topic with the first proxy that accepts them
+func (p *kafkaPublisher) publish(messages []busMessage) error {
+	records := make([]kafkaRecord, len(messages))
+	for i, message := range messages {
+		records[i] = kafkaRecord{Key: message.key, Value: message.value}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+
+	var lastErr error
+	for _, broker := range p.brokers {
+		retry, err := p.produce(broker, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s: %w", RedactURL(broker), err)
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("failed to publish to Kafka: %w", lastErr)
+}
+
+// produce posts records to the topic with a proxy. retry reports whether another
+// proxy may succeed where this one failed.
+func (p *kafkaPublisher) produce(broker string, body []byte) (retry bool, err error) {
+	endpoint := strings.TrimSuffix(broker, "/") + "/topics/" + url.PathEscape(p.topic)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json, application/json")
+	req.Header.Set("User-Agent", "swagger-docs-mcp/"+version.GetSemanticVersion())
+	for name, value := range p.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 300 {
+		err = fmt.Errorf("proxy answered with status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+	}
+
+	// The proxy accepts a request even when it fails to produce some of its records
+	var produced kafkaProduceResponse
+	if json.Unmarshal(content, &produced) == nil {
+		for _, offset := range produced.Offsets {
+			if offset.ErrorCode != nil && *offset.ErrorCode != 0 {
+				return true, fmt.Errorf("proxy failed to produce a record: %s (error code %d)", offset.Error, *offset.ErrorCode)
+			}
+		}
+	}
+	return false, nil
+}
+
+// close has nothing to release, as requests share no connection
+func (p *kafkaPublisher) close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// natsPublisher publishes events to a subject of NATS core. It connects to the first
+// broker it can reach, in order, and the NATS client reconnects to the brokers when
+// the connection is lost. Credentials are taken from the user info of the broker
+// URLs: a user and password, or a token as the user.
+type natsPublisher struct {
+	brokers []string
+	subject string
+	logger  *utils.Logger
+
+	conn  *nats.Conn
+	mutex sync.Mutex
+}
+
+// newNATSPublisher creates a publisher to the subject of a bus configuration
+func newNATSPublisher(config types.BusConfig, logger *utils.Logger) *natsPublisher {
+	return &natsPublisher{brokers: config.Brokers, subject: config.Topic, logger: logger}
+}
+
+// publish sends messages to the subject, connecting first when there is no
+// connection. It returns once the server has received them, so that a failed batch
+// is published again.
+func (p *natsPublisher) publish(messages []busMessage) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+
+	for _, message := range messages {
+		if err := p.conn.Publish(p.subject, message.value); err != nil {
+			return fmt.Errorf("failed to publish to NATS: %w", err)
+		}
+	}
+	if err := p.conn.FlushTimeout(busTimeout); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	return nil
+}
+
+// close closes the connection, if any, once the published messages are sent
+func (p *natsPublisher) close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	return nil
+}
+
+// connect connects to the first broker that accepts a connection
+func (p *natsPublisher) connect() error {
+	conn, err := nats.Connect(strings.Join(p.brokers, ","),
+		nats.Name("swagger-docs-mcp"),
+		nats.Timeout(busTimeout),
+		nats.DontRandomize(),
+		nats.MaxReconnects(-1),
+		// Publishes fail while reconnecting rather than being buffered, so that the
+		// bus retries them and drops them once it gives up
+		nats.ReconnectBufSize(-1),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			if err != nil {
+				p.logger.Warn("Lost connection to NATS server", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			p.logger.Info("Reconnected to NATS server", zap.String("broker", RedactURL(conn.ConnectedUrl())))
+		}),
+		nats.ErrorHandler(func(conn *nats.Conn, subscription *nats.Subscription, err error) {
+			p.logger.Warn("NATS server reported an error", zap.Error(err))
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to any NATS server: %w", err)
+	}
+	p.conn = conn
+	p.logger.Info("Connected to NATS server", zap.String("broker", RedactURL(conn.ConnectedUrl())), zap.String("subject", p.subject))
+	return nil
+}
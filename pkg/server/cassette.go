@@ -0,0 +1,230 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// cassetteFileUnsafe matches characters that are replaced in cassette file names
+var cassetteFileUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// cassetteHeaders are the response headers kept in recordings; Link holds the next
+// links of paginated responses. Others, such as dates and cookies, would make
+// recordings differ between runs or leak session state.
+var cassetteHeaders = []string{"Content-Type", "Link"}
+
+// CassetteInteraction is a recorded tool call: its arguments and the upstream response
+type CassetteInteraction struct {
+	Arguments  map[string]interface{} `json:"arguments"`
+	StatusCode int                    `json:"statusCode"`
+	Headers    map[string]string      `json:"headers,omitempty"`
+	Body       string                 `json:"body"`
+	RecordedAt time.Time              `json:"recordedAt"`
+}
+
+// cassetteFile is the stored form of the recorded calls of one tool
+type cassetteFile struct {
+	Tool         string                 `json:"tool"`
+	Interactions []*CassetteInteraction `json:"interactions"`
+}
+
+// Cassette records the upstream responses of tool calls to disk and replays them for
+// later calls with the same tool and arguments. Each tool's calls are kept in a JSON
+// file of its own, so recordings can be reviewed and committed alongside tests.
+type Cassette struct {
+	dir    string
+	mode   types.CassetteMode
+	logger *utils.Logger
+
+	// files caches loaded cassette files by tool name
+	files map[string]*cassetteFile
+	mutex sync.Mutex
+}
+
+// NewCassette creates a cassette kept in the configured directory, or returns nil when
+// no directory is configured
+func NewCassette(config types.CassetteConfig, logger *utils.Logger) *Cassette {
+	if config.Dir == "" {
+		return nil
+	}
+
+	mode := config.Mode
+	if mode == "" {
+		mode = types.CassetteModeAuto
+	}
+
+	return &Cassette{
+		dir:    config.Dir,
+		mode:   mode,
+		logger: logger.Child("cassette"),
+		files:  make(map[string]*cassetteFile),
+	}
+}
+
+// Replay returns the recorded response of a tool call. It reports false when the call
+// is to be executed instead, and fails in replay mode when the call was not recorded.
+func (c *Cassette) Replay(toolName string, arguments map[string]interface{}) (*http.Response, bool, error) {
+	if c.mode == types.CassetteModeRecord {
+		return nil, false, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file, err := c.load(toolName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if interaction := file.find(arguments); interaction != nil {
+		c.logger.Debug("Replaying recorded tool call", zap.String("toolName", toolName))
+		headers := make(map[string]string, len(interaction.Headers))
+		for name, value := range interaction.Headers {
+			headers[name] = value
+		}
+		return &http.Response{
+			StatusCode: interaction.StatusCode,
+			Headers:    headers,
+			Body:       []byte(interaction.Body),
+			Source:     http.SourceCassette,
+		}, true, nil
+	}
+
+	if c.mode == types.CassetteModeReplay {
+		return nil, false, fmt.Errorf("no recorded response for %s with arguments %s in %s", toolName, canonicalArguments(arguments), c.path(toolName))
+	}
+	return nil, false, nil
+}
+
+// Record stores the upstream response of a tool call, replacing an earlier recording
+// of a call with the same arguments
+func (c *Cassette) Record(toolName string, arguments map[string]interface{}, response *http.Response) error {
+	if c.mode == types.CassetteModeReplay {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file, err := c.load(toolName)
+	if err != nil {
+		return err
+	}
+
+	interaction := &CassetteInteraction{
+		Arguments:  arguments,
+		StatusCode: response.StatusCode,
+		Body:       string(response.Body),
+		RecordedAt: time.Now().UTC(),
+	}
+	if interaction.Arguments == nil {
+		interaction.Arguments = map[string]interface{}{}
+	}
+	for _, name := range cassetteHeaders {
+		if value, exists := response.Headers[name]; exists {
+			if interaction.Headers == nil {
+				interaction.Headers = make(map[string]string)
+			}
+			interaction.Headers[name] = value
+		}
+	}
+
+	if existing := file.find(arguments); existing != nil {
+		*existing = *interaction
+	} else {
+		file.Interactions = append(file.Interactions, interaction)
+	}
+
+	if err := c.save(file); err != nil {
+		return err
+	}
+
+	c.logger.Debug("Recorded tool call", zap.String("toolName", toolName), zap.Int("statusCode", response.StatusCode))
+	return nil
+}
+
+// load returns the cassette file of a tool, reading it from disk on first use
+func (c *Cassette) load(toolName string) (*cassetteFile, error) {
+	if file, exists := c.files[toolName]; exists {
+		return file, nil
+	}
+
+	file := &cassetteFile{Tool: toolName}
+	data, err := os.ReadFile(c.path(toolName))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette %s: %w", c.path(toolName), err)
+	default:
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", c.path(toolName), err)
+		}
+	}
+
+	c.files[toolName] = file
+	return file, nil
+}
+
+// save writes the cassette file of a tool, replacing the previous file atomically
+func (c *Cassette) save(file *cassetteFile) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette for %s: %w", file.Tool, err)
+	}
+
+	path := c.path(file.Tool)
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette %s: %w", path, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write cassette %s: %w", path, err)
+	}
+	return nil
+}
+
+// path returns the file a tool's calls are recorded in
+func (c *Cassette) path(toolName string) string {
+	return filepath.Join(c.dir, cassetteFileUnsafe.ReplaceAllString(toolName, "_")+".json")
+}
+
+// find returns the recorded call with the given arguments, or nil
+func (f *cassetteFile) find(arguments map[string]interface{}) *CassetteInteraction {
+	key := canonicalArguments(arguments)
+	for _, interaction := range f.Interactions {
+		if canonicalArguments(interaction.Arguments) == key {
+			return interaction
+		}
+	}
+	return nil
+}
+
+// canonicalArguments encodes tool call arguments so that equal arguments encode the
+// same regardless of key order. Missing and empty arguments are equal.
+func canonicalArguments(arguments map[string]interface{}) string {
+	if len(arguments) == 0 {
+		return "{}"
+	}
+	// Maps are encoded with sorted keys; numbers decode to float64 on both sides
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Sprintf("%v", arguments)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,399 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// catalogStopTimeout bounds how long Close waits for the sync loop to stop
+const catalogStopTimeout = 5 * time.Second
+
+// catalogLeaseScript renews the lease of a replica that holds it, or gives it to the
+// replica when it is free, returning 1 when the replica holds the lease. A replica
+// that took the lease with a command whose reply was lost thus finds it held by
+// itself rather than by another replica.
+const catalogLeaseScript = `local holder = redis.call("GET", KEYS[1])
+if holder == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) end
+if holder then return 0 end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1`
+
+// catalogReleaseScript gives up the lease of the leading replica, unless another
+// replica took it over
+const catalogReleaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`
+
+// storedCatalog is the tool catalog as it is stored in Redis: the tools of the leading
+// replica by document, in the form of registry snapshots, and its prompts and
+// resources. Prompts and resources are left out by replicas that do not serve them.
+type storedCatalog struct {
+	// Fingerprint identifies the configuration the tools were generated under, so
+	// that replicas of another configuration do not serve them
+	Fingerprint string          `json:"fingerprint"`
+	Leader      string          `json:"leader"`
+	SavedAt     time.Time       `json:"savedAt"`
+	Documents   json.RawMessage `json:"documents"`
+	Prompts     json.RawMessage `json:"prompts,omitempty"`
+	Resources   json.RawMessage `json:"resources,omitempty"`
+}
+
+// SharedCatalog shares one catalog of tools, prompts, and resources between the
+// replicas of a deployment through Redis, so that replicas behind a load balancer list
+// the same tools, prompts, and resources even when their scans of remote documents
+// drift apart. One replica at a time holds a lease on the catalog and publishes what
+// it loads; the others replace theirs with the published catalog whenever it differs
+// from their own, including after their own refreshes. A replica takes over the lease
+// once its holder stops renewing it.
+type SharedCatalog struct {
+	client            *redis.Client
+	key               string
+	interval          time.Duration
+	refreshLeaderOnly bool
+	fingerprint       string
+	instance          string
+	registry          *ToolRegistry
+	prompts           *PromptRegistry
+	resources         *ResourceRegistry
+	logger            *utils.Logger
+
+	// dirty reports that the catalog changed since its hash was computed, and wake
+	// asks the sync loop to sync without waiting for the next interval
+	dirty atomic.Bool
+	wake  chan struct{}
+
+	stop      chan struct{}
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	started   atomic.Bool
+
+	// leader reports that this replica holds the lease; the rest is the state of the
+	// sync loop
+	leader         atomic.Bool
+	localHash      string
+	localTools     json.RawMessage
+	localPrompts   json.RawMessage
+	localResources json.RawMessage
+	rejectedHash   string
+}
+
+// NewSharedCatalog creates the shared catalog of the tools, prompts, and resources of
+// the registries, returning nil unless a shared registry is configured. The prompt
+// and resource registries may be nil for servers without prompts or resources. Start
+// begins sharing the catalog.
+func NewSharedCatalog(config *types.ResolvedConfig, registry *ToolRegistry, prompts *PromptRegistry, resources *ResourceRegistry, logger *utils.Logger) *SharedCatalog {
+	shared := config.Server.SharedRegistry
+	if shared.URL == "" {
+		return nil
+	}
+
+	logger = logger.Child("shared-registry")
+	client, err := newRedisClient(shared.URL)
+	if err != nil {
+		logger.Error("Tools are not shared between replicas", zap.Error(err))
+		return nil
+	}
+
+	// Leases name the replica holding them, so that a replica only renews its own
+	hostname, _ := os.Hostname()
+	return &SharedCatalog{
+		client:            client,
+		key:               shared.Key,
+		interval:          shared.SyncInterval,
+		refreshLeaderOnly: shared.RefreshLeaderOnly,
+		fingerprint:       SnapshotFingerprint(config),
+		instance:          hostname + "-" + uuid.New().String()[:8],
+		registry:          registry,
+		prompts:           prompts,
+		resources:         resources,
+		logger:            logger,
+		wake:              make(chan struct{}, 1),
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start syncs the tools, prompts, and resources with the catalog in the background
+// once ready is closed, calling onChange with the changes of adopting the tools of
+// the catalog and onListsChanged with whether adopting it changed the prompts and
+// the list of resources. Later calls do nothing.
+func (c *SharedCatalog) Start(ready <-chan struct{}, onChange func(ToolChanges), onListsChanged func(prompts, resources bool)) {
+	if c == nil {
+		return
+	}
+	c.startOnce.Do(func() {
+		c.started.Store(true)
+		go c.run(ready, onChange, onListsChanged)
+	})
+}
+
+// Changed has the catalog published, or the catalog adopted again, after a change
+// to the tools, prompts, or resources
+func (c *SharedCatalog) Changed() {
+	if c == nil {
+		return
+	}
+	c.dirty.Store(true)
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Refreshes reports whether this replica refreshes the remote documents: every
+// replica does, unless refreshes are left to the replica holding the lease
+func (c *SharedCatalog) Refreshes() bool {
+	if c == nil || !c.refreshLeaderOnly {
+		return true
+	}
+	return c.leader.Load()
+}
+
+// Close stops syncing and gives up the lease on the catalog, if held
+func (c *SharedCatalog) Close() {
+	if c == nil {
+		return
+	}
+	c.stopOnce.Do(func() { close(c.stop) })
+	if c.started.Load() {
+		select {
+		case <-c.done:
+		case <-time.After(catalogStopTimeout):
+			c.logger.Warn("Gave up waiting for the shared registry to stop")
+		}
+	}
+	c.client.Close()
+}
+
+// run syncs with the catalog every interval, and after each change to the tools,
+// prompts, or resources, until Close
+func (c *SharedCatalog) run(ready <-chan struct{}, onChange func(ToolChanges), onListsChanged func(prompts, resources bool)) {
+	defer close(c.done)
+
+	select {
+	case <-ready:
+	case <-c.stop:
+		return
+	}
+	c.dirty.Store(true)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.sync(onChange, onListsChanged); err != nil {
+			c.logger.Warn("Failed to sync tools with the shared registry", zap.Error(err))
+		}
+		select {
+		case <-ticker.C:
+		case <-c.wake:
+		case <-c.stop:
+			c.release()
+			return
+		}
+	}
+}
+
+// sync takes or renews the lease on the catalog, then publishes the tools, prompts,
+// and resources when leading or adopts those of the catalog otherwise, unless they
+// are the same
+func (c *SharedCatalog) sync(onChange func(ToolChanges), onListsChanged func(prompts, resources bool)) error {
+	if err := c.lead(); err != nil {
+		return err
+	}
+
+	if c.dirty.Swap(false) {
+		if err := c.encodeLocal(); err != nil {
+			return err
+		}
+	}
+
+	remoteHash, err := c.get(c.key + ":catalog-hash")
+	if err != nil {
+		return err
+	}
+	if remoteHash == c.localHash {
+		return nil
+	}
+	if c.leader.Load() {
+		return c.publish()
+	}
+	return c.adopt(remoteHash, onChange, onListsChanged)
+}
+
+// encodeLocal encodes the tools, prompts, and resources of this replica in their
+// stored form, and hashes them
+func (c *SharedCatalog) encodeLocal() error {
+	documents, err := json.Marshal(groupSnapshotTools(c.registry.GetAllTools()))
+	if err != nil {
+		return fmt.Errorf("failed to encode tools: %w", err)
+	}
+	hash := sha256.New()
+	hash.Write(documents)
+
+	var prompts, resources json.RawMessage
+	if c.prompts != nil {
+		all := c.prompts.GetAllPrompts()
+		sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+		if prompts, err = json.Marshal(all); err != nil {
+			return fmt.Errorf("failed to encode prompts: %w", err)
+		}
+		hash.Write([]byte("\nprompts\n"))
+		hash.Write(prompts)
+	}
+	if c.resources != nil {
+		if resources, err = json.Marshal(c.resources.GetAllResources()); err != nil {
+			return fmt.Errorf("failed to encode resources: %w", err)
+		}
+		hash.Write([]byte("\nresources\n"))
+		hash.Write(resources)
+	}
+
+	c.localTools, c.localPrompts, c.localResources = documents, prompts, resources
+	c.localHash = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// lead renews the lease on the catalog when holding it, or takes it when it is free.
+// The lease expires after three sync intervals without renewal. A replica that fails
+// to reach Redis keeps leading until it learns that another replica took the lease.
+func (c *SharedCatalog) lead() error {
+	ttl := (3 * c.interval).Milliseconds()
+	held, err := c.client.Eval(context.Background(), catalogLeaseScript, []string{c.key + ":leader"}, c.instance, ttl).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to take or renew the lease: %w", err)
+	}
+
+	switch leading := c.leader.Load(); {
+	case held == 1 && !leading:
+		c.leader.Store(true)
+		// The tools are published unless the catalog already holds them
+		c.dirty.Store(true)
+		c.logger.Info("Leading the shared registry", zap.String("instance", c.instance), zap.String("key", c.key))
+	case held != 1 && leading:
+		c.leader.Store(false)
+		c.logger.Warn("Lost the lease on the shared registry to another replica")
+	}
+	return nil
+}
+
+// publish replaces the catalog with the tools of this replica
+func (c *SharedCatalog) publish() error {
+	encoded, err := json.Marshal(storedCatalog{
+		Fingerprint: c.fingerprint,
+		Leader:      c.instance,
+		SavedAt:     time.Now().UTC(),
+		Documents:   c.localTools,
+		Prompts:     c.localPrompts,
+		Resources:   c.localResources,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog: %w", err)
+	}
+
+	// The catalog and its hash are set at once, so that no replica reads one without the other
+	if err := c.client.MSet(context.Background(), c.key+":catalog", string(encoded), c.key+":catalog-hash", c.localHash).Err(); err != nil {
+		return fmt.Errorf("failed to publish catalog: %w", err)
+	}
+	c.logger.Info("Published tools to the shared registry", zap.Int("tools", c.registry.GetToolCount()))
+	return nil
+}
+
+// adopt replaces the tools of this replica with those of the catalog, and its prompts
+// and resources unless the leader left them out
+func (c *SharedCatalog) adopt(remoteHash string, onChange func(ToolChanges), onListsChanged func(prompts, resources bool)) error {
+	if remoteHash == "" || remoteHash == c.rejectedHash {
+		return nil
+	}
+
+	value, err := c.get(c.key + ":catalog")
+	if err != nil || value == "" {
+		return err
+	}
+	var catalog storedCatalog
+	if err := json.Unmarshal([]byte(value), &catalog); err != nil {
+		return fmt.Errorf("failed to decode catalog: %w", err)
+	}
+	if catalog.Fingerprint != c.fingerprint {
+		c.rejectedHash = remoteHash
+		c.logger.Warn("Ignoring shared registry published under a different configuration",
+			zap.String("leader", catalog.Leader), zap.String("key", c.key))
+		return nil
+	}
+
+	var byDocument map[string]snapshotDocument
+	if err := json.Unmarshal(catalog.Documents, &byDocument); err != nil {
+		return fmt.Errorf("failed to decode catalog: %w", err)
+	}
+	paths := make([]string, 0, len(byDocument))
+	for path := range byDocument {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	documents := make([]snapshotDocument, len(paths))
+	for i, path := range paths {
+		documents[i] = byDocument[path]
+	}
+
+	var prompts []*types.GeneratedPrompt
+	if c.prompts != nil && len(catalog.Prompts) > 0 {
+		if err := json.Unmarshal(catalog.Prompts, &prompts); err != nil {
+			return fmt.Errorf("failed to decode catalog prompts: %w", err)
+		}
+	}
+	var resources []*types.GeneratedResource
+	if c.resources != nil && len(catalog.Resources) > 0 {
+		if err := json.Unmarshal(catalog.Resources, &resources); err != nil {
+			return fmt.Errorf("failed to decode catalog resources: %w", err)
+		}
+	}
+
+	changes := c.registry.ReplaceAll(restoreSnapshotTools(documents))
+	promptsChanged := c.prompts != nil && len(catalog.Prompts) > 0 && c.prompts.ReplaceAll(prompts)
+	resourcesChanged := c.resources != nil && len(catalog.Resources) > 0 && c.resources.ReplaceAll(resources)
+	c.localHash = remoteHash
+	if !changes.IsEmpty() {
+		c.logger.Info("Adopted tools of the shared registry", zap.String("leader", catalog.Leader),
+			zap.Int("added", len(changes.Added)), zap.Int("removed", len(changes.Removed)), zap.Int("updated", len(changes.Updated)))
+		onChange(changes)
+	}
+	if promptsChanged || resourcesChanged {
+		c.logger.Info("Adopted prompts and resources of the shared registry", zap.String("leader", catalog.Leader),
+			zap.Bool("promptsChanged", promptsChanged), zap.Bool("resourcesChanged", resourcesChanged))
+		onListsChanged(promptsChanged, resourcesChanged)
+	}
+	return nil
+}
+
+// release gives up the lease on the catalog, if held, so that another replica takes
+// it over without waiting for it to expire
+func (c *SharedCatalog) release() {
+	if !c.leader.Load() {
+		return
+	}
+	if err := c.client.Eval(context.Background(), catalogReleaseScript, []string{c.key + ":leader"}, c.instance).Err(); err != nil {
+		c.logger.Warn("Failed to release the lease on the shared registry", zap.Error(err))
+	}
+	c.leader.Store(false)
+}
+
+// get returns the value of a key, or "" when it is not set
+func (c *SharedCatalog) get(key string) (string, error) {
+	value, err := c.client.Get(context.Background(), key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return value, err
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxCompletionValues is the most suggestions returned per request, as the MCP specification allows
+const maxCompletionValues = 100
+
+// knownGeocodes are "latitude,longitude" pairs for major cities, suggested for geocode arguments
+var knownGeocodes = []string{
+	"40.71,-74.01",  // New York
+	"34.05,-118.24", // Los Angeles
+	"41.88,-87.63",  // Chicago
+	"29.76,-95.37",  // Houston
+	"33.75,-84.39",  // Atlanta
+	"47.61,-122.33", // Seattle
+	"51.51,-0.13",   // London
+	"48.86,2.35",    // Paris
+	"35.68,139.69",  // Tokyo
+	"-33.87,151.21", // Sydney
+}
+
+// Completer suggests argument values for tools and prompts from swagger enum,
+// default, and example values
+type Completer struct {
+	tools   *ToolRegistry
+	prompts *PromptRegistry
+}
+
+// NewCompleter creates a completer; prompts may be nil when prompts are not served
+func NewCompleter(tools *ToolRegistry, prompts *PromptRegistry) *Completer {
+	return &Completer{
+		tools:   tools,
+		prompts: prompts,
+	}
+}
+
+// Complete returns the suggestions for an argument that match its partial value
+func (c *Completer) Complete(params types.MCPCompleteParams) (types.MCPCompletion, error) {
+	argument := params.Argument.Name
+	var candidates []string
+
+	switch params.Ref.Type {
+	case "ref/tool":
+		tool := c.tools.GetTool(params.Ref.Name)
+		if tool == nil {
+			return types.MCPCompletion{}, fmt.Errorf("tool not found: %s", params.Ref.Name)
+		}
+		candidates = schemaValues(toolProperty(tool, argument))
+	case "ref/prompt":
+		var prompt *types.GeneratedPrompt
+		if c.prompts != nil {
+			prompt = c.prompts.GetPrompt(params.Ref.Name)
+		}
+		if prompt == nil {
+			return types.MCPCompletion{}, fmt.Errorf("prompt not found: %s", params.Ref.Name)
+		}
+		candidates = c.promptValues(prompt, argument)
+	case "ref/resource":
+		// Resource URIs are concrete; there are no template variables to complete
+	default:
+		return types.MCPCompletion{}, fmt.Errorf("unsupported completion reference type: %s", params.Ref.Type)
+	}
+
+	if isGeocodeArgument(argument) {
+		candidates = append(candidates, c.geocodeValues()...)
+	}
+
+	return matchCompletions(candidates, params.Argument.Value), nil
+}
+
+// promptValues collects example values for a prompt argument, plus the values of
+// same-named parameters on tools generated from the prompt's source document
+func (c *Completer) promptValues(prompt *types.GeneratedPrompt, argument string) []string {
+	var values []string
+	for _, example := range prompt.Examples {
+		if value, exists := example.Arguments[argument]; exists {
+			values = append(values, scalarValues(value)...)
+		}
+	}
+
+	if prompt.Source != nil {
+		for _, tool := range c.tools.GetAllTools() {
+			if tool.DocumentInfo != nil && tool.DocumentInfo.FilePath == prompt.Source.FilePath {
+				values = append(values, schemaValues(toolProperty(tool, argument))...)
+			}
+		}
+	}
+
+	return values
+}
+
+// geocodeValues returns geocodes documented by any tool, followed by the built-in list
+func (c *Completer) geocodeValues() []string {
+	var values []string
+	for _, tool := range c.tools.GetAllTools() {
+		properties, _ := tool.Schema()["properties"].(map[string]interface{})
+		for name, property := range properties {
+			if isGeocodeArgument(name) {
+				schema, _ := property.(map[string]interface{})
+				values = append(values, schemaValues(schema)...)
+			}
+		}
+	}
+	return append(values, knownGeocodes...)
+}
+
+// toolProperty returns the input schema of a tool argument, if declared
+func toolProperty(tool *types.GeneratedTool, argument string) map[string]interface{} {
+	properties, _ := tool.Schema()["properties"].(map[string]interface{})
+	property, _ := properties[argument].(map[string]interface{})
+	return property
+}
+
+// schemaValues collects enum, default, and example values from an argument schema
+func schemaValues(schema map[string]interface{}) []string {
+	if schema == nil {
+		return nil
+	}
+
+	var values []string
+	values = append(values, scalarValues(schema["enum"])...)
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		values = append(values, scalarValues(items["enum"])...)
+	}
+	values = append(values, scalarValues(schema["default"])...)
+	values = append(values, scalarValues(schema["example"])...)
+	values = append(values, scalarValues(schema["examples"])...)
+	return values
+}
+
+// scalarValues formats a scalar or a list of scalars; objects are not useful suggestions
+func scalarValues(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, scalarValues(item)...)
+		}
+		return values
+	case map[string]interface{}:
+		return nil
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
+// isGeocodeArgument reports whether an argument takes a "latitude,longitude" geocode
+func isGeocodeArgument(name string) bool {
+	return strings.Contains(strings.ToLower(name), "geocode")
+}
+
+// matchCompletions returns distinct candidates matching the partial value, prefix
+// matches first, capped at maxCompletionValues
+func matchCompletions(candidates []string, partial string) types.MCPCompletion {
+	needle := strings.ToLower(partial)
+	seen := make(map[string]bool, len(candidates))
+	var prefixed, contained []string
+
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		lower := strings.ToLower(candidate)
+		switch {
+		case strings.HasPrefix(lower, needle):
+			prefixed = append(prefixed, candidate)
+		case strings.Contains(lower, needle):
+			contained = append(contained, candidate)
+		}
+	}
+
+	values := append(prefixed, contained...)
+	completion := types.MCPCompletion{
+		Values: values,
+		Total:  len(values),
+	}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	return completion
+}
+
+// handleComplete handles the completion/complete request
+func (s *MCPServer) handleComplete(request *types.MCPRequest) error {
+	paramsBytes, err := json.Marshal(request.Params)
+	if err != nil {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	var params types.MCPCompleteParams
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	completion, err := s.completer.Complete(params)
+	if err != nil {
+		return s.sendErrorResponse(request.ID, -32602, err.Error(), nil)
+	}
+
+	return s.sendResponse(request.ID, types.MCPCompleteResult{Completion: completion})
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxStepErrorBody bounds how much of a failed step's response is quoted in errors
+const maxStepErrorBody = 500
+
+// NewCompositeTool creates the tool of a composite tool definition. Without an input
+// schema, the tool takes no arguments.
+func NewCompositeTool(composite types.CompositeToolConfig) *types.GeneratedTool {
+	inputSchema := composite.InputSchema
+	if inputSchema == nil {
+		inputSchema = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		}
+	}
+
+	description := composite.Description
+	if description == "" {
+		stepTools := make([]string, len(composite.Steps))
+		for i, step := range composite.Steps {
+			stepTools[i] = step.Tool
+		}
+		description = fmt.Sprintf("Calls %s in turn", strings.Join(stepTools, ", then "))
+	}
+
+	return &types.GeneratedTool{
+		Name:        composite.Name,
+		Description: description,
+		InputSchema: inputSchema,
+		Composite:   &composite,
+	}
+}
+
+// CompositeTools returns the configured composite tools in configuration order
+func (e *ToolExecutor) CompositeTools() []*types.GeneratedTool {
+	return e.composites
+}
+
+// CompositeTool returns the configured composite tool with the given name, or nil
+func (e *ToolExecutor) CompositeTool(name string) *types.GeneratedTool {
+	for _, composite := range e.composites {
+		if composite.Name == name {
+			return composite
+		}
+	}
+	return nil
+}
+
+// executeComposite runs the steps of a composite tool in order, each a call of a
+// generated tool with arguments rendered from the tool's arguments and the responses
+// of earlier steps. The first failing step fails the call.
+func (e *ToolExecutor) executeComposite(ctx context.Context, client *http.Client, tool *types.GeneratedTool, arguments map[string]interface{}) (*http.Response, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	steps := make(map[string]interface{})
+	data := map[string]interface{}{"input": arguments, "steps": steps}
+	var warnings []string
+
+	// Resolve all steps first, so that a missing tool makes no upstream calls
+	stepTools := make([]*types.GeneratedTool, len(tool.Composite.Steps))
+	for i, step := range tool.Composite.Steps {
+		if stepTools[i] = e.tools.GetTool(step.Tool); stepTools[i] == nil {
+			return nil, fmt.Errorf("step %s of %s calls unknown tool %s", step.ID, tool.Name, step.Tool)
+		}
+	}
+
+	for i, step := range tool.Composite.Steps {
+		stepArguments, err := renderTemplate(step.Arguments, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render arguments of step %s of %s: %w", step.ID, tool.Name, err)
+		}
+		stepArgumentMap, _ := stepArguments.(map[string]interface{})
+
+		e.logger.Debug("Executing composite tool step",
+			zap.String("toolName", tool.Name), zap.String("step", step.ID), zap.String("stepTool", step.Tool))
+		response, err := e.Execute(ctx, client, stepTools[i], stepArgumentMap)
+		if err != nil {
+			return nil, fmt.Errorf("step %s (%s) of %s failed: %w", step.ID, step.Tool, tool.Name, err)
+		}
+		if response.StatusCode >= 400 {
+			body := string(response.Body)
+			if len(body) > maxStepErrorBody {
+				body = body[:maxStepErrorBody] + "..."
+			}
+			return nil, fmt.Errorf("step %s (%s) of %s failed with HTTP %d: %s", step.ID, step.Tool, tool.Name, response.StatusCode, body)
+		}
+
+		for _, warning := range response.Warnings {
+			warnings = append(warnings, fmt.Sprintf("step %s: %s", step.ID, warning))
+		}
+
+		var result interface{}
+		if err := json.Unmarshal(response.Body, &result); err != nil {
+			result = string(response.Body)
+		}
+		steps[step.ID] = result
+	}
+
+	var output interface{} = steps
+	if tool.Composite.Output != nil {
+		rendered, err := renderTemplate(tool.Composite.Output, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render output of %s: %w", tool.Name, err)
+		}
+		output = rendered
+	}
+
+	body, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output of %s: %w", tool.Name, err)
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+		Warnings:   warnings,
+		Source:     http.SourceLocal,
+	}, nil
+}
+
+// renderTemplate replaces the {{ expression }} placeholders in the strings of a
+// template with the results of their JMESPath expressions against data. A string that
+// is a single placeholder takes the result as it is; otherwise results are
+// interpolated as text. Object fields whose value renders to null are left out.
+func renderTemplate(template interface{}, data map[string]interface{}) (interface{}, error) {
+	switch value := template.(type) {
+	case string:
+		matches := types.CompositeTemplatePlaceholder.FindAllStringSubmatchIndex(value, -1)
+		if len(matches) == 0 {
+			return value, nil
+		}
+		if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(value) {
+			return jmespath.Search(value[matches[0][2]:matches[0][3]], data)
+		}
+
+		var rendered strings.Builder
+		last := 0
+		for _, match := range matches {
+			result, err := jmespath.Search(value[match[2]:match[3]], data)
+			if err != nil {
+				return nil, err
+			}
+			rendered.WriteString(value[last:match[0]])
+			rendered.WriteString(templateText(result))
+			last = match[1]
+		}
+		rendered.WriteString(value[last:])
+		return rendered.String(), nil
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(value))
+		for name, item := range value {
+			renderedItem, err := renderTemplate(item, data)
+			if err != nil {
+				return nil, err
+			}
+			if renderedItem != nil {
+				rendered[name] = renderedItem
+			}
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(value))
+		for i, item := range value {
+			renderedItem, err := renderTemplate(item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = renderedItem
+		}
+		return rendered, nil
+	}
+	return template, nil
+}
+
+// templateText returns the text a placeholder result is interpolated as: strings as
+// they are, null as nothing, and other values as JSON
+func templateText(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// StoredDocument is a parsed swagger document together with the info it was loaded from
+type StoredDocument struct {
+	Info     *types.SwaggerDocumentInfo
+	Document *types.SwaggerDocument
+	digest   [sha256.Size]byte
+}
+
+// DocumentStore keeps the parsed swagger documents that resources are rendered from,
+// keyed by file path or URL
+type DocumentStore struct {
+	documents map[string]*StoredDocument
+	mutex     sync.RWMutex
+}
+
+// NewDocumentStore creates an empty document store
+func NewDocumentStore() *DocumentStore {
+	return &DocumentStore{
+		documents: make(map[string]*StoredDocument),
+	}
+}
+
+// Put stores a parsed document and reports whether its content differs from the
+// version previously stored under the same path
+func (s *DocumentStore) Put(info *types.SwaggerDocumentInfo, document *types.SwaggerDocument) bool {
+	stored := &StoredDocument{
+		Info:     info,
+		Document: document,
+	}
+	// A document that cannot be encoded is always treated as changed
+	if encoded, err := json.Marshal(document); err == nil {
+		stored.digest = sha256.Sum256(encoded)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, exists := s.documents[info.FilePath]
+	s.documents[info.FilePath] = stored
+	return !exists || previous.digest != stored.digest || stored.digest == [sha256.Size]byte{}
+}
+
+// Get returns the stored document for a file path or URL, or nil
+func (s *DocumentStore) Get(path string) *StoredDocument {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.documents[path]
+}
+
+// Delete removes the stored document for a file path or URL and reports whether it existed
+func (s *DocumentStore) Delete(path string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, exists := s.documents[path]
+	delete(s.documents, path)
+	return exists
+}
+
+// LocalPaths returns the sorted file paths of documents loaded from disk
+func (s *DocumentStore) LocalPaths() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var paths []string
+	for path, stored := range s.documents {
+		if !stored.Info.IsRemote {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// All returns every stored document, ordered by path
+func (s *DocumentStore) All() []*StoredDocument {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	documents := make([]*StoredDocument, 0, len(s.documents))
+	for _, stored := range s.documents {
+		documents = append(documents, stored)
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].Info.FilePath < documents[j].Info.FilePath
+	})
+	return documents
+}
+
+// ResolveResource returns the resource a URI addresses and the document it is rendered
+// from. Registered resources are looked up directly; other URIs are matched against the
+// generator's resource templates for the document named in the URI.
+func (s *DocumentStore) ResolveResource(uri string, registry *ResourceRegistry, generator *swagger.ResourceGenerator) (*types.GeneratedResource, *StoredDocument) {
+	if resource := registry.GetResourceByURI(uri); resource != nil && resource.Source != nil {
+		if stored := s.Get(resource.Source.FilePath); stored != nil {
+			return resource, stored
+		}
+		return nil, nil
+	}
+
+	for _, stored := range s.All() {
+		if !strings.HasPrefix(uri, generator.ResourceURIPrefix(stored.Info)) {
+			continue
+		}
+		if resource, err := generator.ResolveTemplateResource(uri, stored.Document, stored.Info); err == nil {
+			return resource, stored
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// ToolExecutor performs the upstream requests of tool calls. Calls of mocked tools
+// return mock responses, and with a cassette configured, calls are replayed from and
+// recorded to it instead of always reaching the upstream API. Calls of configured
+// composite tools are run as calls of the tools in tools. With response validation
+// enabled, upstream responses are checked against their endpoints' response schemas.
+type ToolExecutor struct {
+	mock        types.MockConfig
+	validation  types.ResponseValidationConfig
+	documents   *DocumentStore
+	tools       *ToolRegistry
+	composites  []*types.GeneratedTool
+	cassette    *Cassette
+	filters     map[string]string
+	pagination  types.PaginationConfig
+	overrides   map[string]types.ToolOverrideConfig
+	credentials map[string]types.CredentialConfig
+	logger      *utils.Logger
+}
+
+// NewToolExecutor creates a tool executor. Mock response schemas are resolved against
+// the tools' documents in documents, which may be nil, and the steps of composite
+// tools call the tools of tools.
+func NewToolExecutor(config *types.ResolvedConfig, documents *DocumentStore, tools *ToolRegistry, logger *utils.Logger) *ToolExecutor {
+	composites := make([]*types.GeneratedTool, len(config.CompositeTools))
+	for i, composite := range config.CompositeTools {
+		composites[i] = NewCompositeTool(composite)
+	}
+
+	return &ToolExecutor{
+		mock:        config.Mock,
+		validation:  config.ResponseValidation,
+		documents:   documents,
+		tools:       tools,
+		composites:  composites,
+		cassette:    NewCassette(config.Cassette, logger),
+		filters:     config.ResponseFilters,
+		pagination:  config.Pagination,
+		overrides:   config.ToolOverrides,
+		credentials: config.Auth.Credentials,
+		logger:      logger.Child("tool-executor"),
+	}
+}
+
+// Execute performs the upstream request of a tool call with the given HTTP client.
+// With the _fetchAll argument, the pages of paginated endpoints are followed and
+// merged. A JMESPath expression in the _filter argument, or configured for the tool,
+// is applied to successful JSON responses. Composite tools run their steps instead.
+func (e *ToolExecutor) Execute(ctx context.Context, client *http.Client, tool *types.GeneratedTool, arguments map[string]interface{}) (*http.Response, error) {
+	if tool.Composite != nil {
+		// A composite tool's timeout bounds all of its steps
+		if timeout := e.overrides[tool.Name].Timeout; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return e.executeComposite(ctx, client, tool, arguments)
+	}
+
+	expression := e.filters[tool.Name]
+	if filter, ok := arguments[swagger.FilterArgument].(string); ok && filter != "" {
+		expression = filter
+	}
+	fetchAll, _ := arguments[swagger.FetchAllArgument].(bool)
+	arguments = withoutArguments(arguments, swagger.FilterArgument, swagger.FetchAllArgument)
+
+	var response *http.Response
+	var err error
+	if fetchAll && tool.Endpoint.Pagination != nil {
+		response, err = e.fetchAllPages(ctx, client, tool, arguments)
+	} else {
+		response, err = e.execute(ctx, client, tool, arguments)
+	}
+	if err != nil {
+		return response, err
+	}
+
+	e.logger.Debug("Tool call executed",
+		zap.String("toolName", tool.Name),
+		zap.String("source", response.Source),
+		zap.String("url", response.URL),
+		zap.Int("statusCode", response.StatusCode),
+		zap.Int("attempts", response.Attempts),
+		zap.Duration("duration", response.Duration))
+
+	// The filter may leave out what the schema declares, so the response is checked first
+	warnings := e.validateResponse(tool, response)
+	if expression != "" {
+		if response, err = FilterResponse(response, expression); err != nil {
+			return nil, err
+		}
+	}
+	if len(warnings) > 0 {
+		response.Warnings = warnings
+	}
+	return response, nil
+}
+
+// validateResponse checks an upstream response of a tool call against the response
+// schema of the tool's endpoint, when response validation is enabled, and returns the
+// mismatches found. Mock responses are derived from the schema and not checked.
+func (e *ToolExecutor) validateResponse(tool *types.GeneratedTool, response *http.Response) []string {
+	if !e.validation.Enabled || tool.Endpoint == nil || Mocks(e.mock, tool) {
+		return nil
+	}
+
+	warnings := swagger.ValidateResponse(e.document(tool), tool.Endpoint, response.StatusCode,
+		response.Headers["Content-Type"], response.Body)
+	if len(warnings) > 0 {
+		e.logger.Warn("Response does not match the declared response schema",
+			zap.String("toolName", tool.Name),
+			zap.Int("statusCode", response.StatusCode),
+			zap.Strings("warnings", warnings))
+	}
+	return warnings
+}
+
+// ResultMeta returns the metadata of the MCP result of a tool call for its response:
+// under "execution", how the response was obtained, under "responseValidation", the
+// mismatches of the response with its declared schema, and under "upstream", the
+// details of responses with a status other than 2xx.
+func ResultMeta(response *http.Response) map[string]interface{} {
+	meta := map[string]interface{}{
+		"execution": ExecutionMeta(response),
+	}
+	if len(response.Warnings) > 0 {
+		meta["responseValidation"] = map[string]interface{}{
+			"valid":    false,
+			"warnings": response.Warnings,
+		}
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		meta["upstream"] = UpstreamMeta(response)
+	}
+	return meta
+}
+
+// ExecutionMeta describes how the response of a tool call was obtained: where it came
+// from, the final URL and status of the request, the time its attempts took, how many
+// of them were retries, and whether it was a cache hit, replayed from a cassette
+// rather than fetched
+func ExecutionMeta(response *http.Response) map[string]interface{} {
+	source := response.Source
+	if source == "" {
+		source = http.SourceUpstream
+	}
+	meta := map[string]interface{}{
+		"source":   source,
+		"status":   response.StatusCode,
+		"cacheHit": source == http.SourceCassette,
+	}
+	if response.URL != "" {
+		meta["url"] = response.URL
+	}
+	// Mocked and replayed responses were not timed
+	if response.Attempts > 0 {
+		meta["durationMs"] = float64(response.Duration.Microseconds()) / 1000
+		meta["attempts"] = response.Attempts
+		meta["retries"] = response.Retries
+	}
+	return meta
+}
+
+// document returns the parsed document of a tool, or nil when it is not kept
+func (e *ToolExecutor) document(tool *types.GeneratedTool) *types.SwaggerDocument {
+	if e.documents == nil || tool.DocumentInfo == nil {
+		return nil
+	}
+	if stored := e.documents.Get(tool.DocumentInfo.FilePath); stored != nil {
+		return stored.Document
+	}
+	return nil
+}
+
+// withoutArguments returns the arguments without the named ones. These are not API
+// parameters, so they are left out of requests and recordings.
+func withoutArguments(arguments map[string]interface{}, names ...string) map[string]interface{} {
+	present := false
+	for _, name := range names {
+		if _, exists := arguments[name]; exists {
+			present = true
+		}
+	}
+	if !present {
+		return arguments
+	}
+
+	trimmed := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		trimmed[name] = value
+	}
+	for _, name := range names {
+		delete(trimmed, name)
+	}
+	return trimmed
+}
+
+// execute performs the upstream request of a tool call, mocking or replaying it as configured
+func (e *ToolExecutor) execute(ctx context.Context, client *http.Client, tool *types.GeneratedTool, arguments map[string]interface{}) (*http.Response, error) {
+	if Mocks(e.mock, tool) {
+		return MockResponse(e.document(tool), tool)
+	}
+
+	if e.cassette == nil {
+		return client.ExecuteRequestWithPolicy(ctx, tool.Endpoint, arguments, e.requestPolicy(tool))
+	}
+
+	response, replayed, err := e.cassette.Replay(tool.Name, arguments)
+	if err != nil || replayed {
+		return response, err
+	}
+
+	response, err = client.ExecuteRequestWithPolicy(ctx, tool.Endpoint, arguments, e.requestPolicy(tool))
+	if err != nil {
+		return nil, err
+	}
+	// A recording failure does not fail the call that was already made
+	if err := e.cassette.Record(tool.Name, arguments, response); err != nil {
+		e.logger.Error("Failed to record tool call", zap.Error(err), zap.String("toolName", tool.Name))
+	}
+	return response, nil
+}
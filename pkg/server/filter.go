@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/url"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// ToolFilter selects tools at request time by document metadata, free text, and
+// vendor extension metadata. Empty criteria match every tool.
+type ToolFilter struct {
+	PackageIDs     []string `json:"packageIds,omitempty"`
+	TWCDomains     []string `json:"twcDomains,omitempty"`
+	TWCPortfolios  []string `json:"twcPortfolios,omitempty"`
+	TWCGeographies []string `json:"twcGeographies,omitempty"`
+	Custom         []string `json:"custom,omitempty"`
+	Metadata       []string `json:"metadata,omitempty"`
+}
+
+// ToolFilterFromQuery reads filter criteria from comma-separated query parameters
+func ToolFilterFromQuery(query url.Values) ToolFilter {
+	return ToolFilter{
+		PackageIDs:     parseCommaSeparated(query.Get("package-ids")),
+		TWCDomains:     parseCommaSeparated(query.Get("twc-domains")),
+		TWCPortfolios:  parseCommaSeparated(query.Get("twc-portfolios")),
+		TWCGeographies: parseCommaSeparated(query.Get("twc-geographies")),
+		Custom:         parseCommaSeparated(query.Get("filter-custom")),
+		Metadata:       parseCommaSeparated(query.Get("metadata")),
+	}
+}
+
+// IsEmpty reports whether the filter has no criteria
+func (f ToolFilter) IsEmpty() bool {
+	return !f.hasDocumentCriteria() && len(f.Metadata) == 0
+}
+
+// Apply returns the tools that match the filter
+func (f ToolFilter) Apply(tools []*types.GeneratedTool) []*types.GeneratedTool {
+	if f.IsEmpty() {
+		return tools
+	}
+
+	var filtered []*types.GeneratedTool
+	for _, tool := range tools {
+		if f.Matches(tool) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// Matches reports whether a tool satisfies every criterion of the filter.
+// Metadata criteria are key=value pairs matched against vendor extensions;
+// a bare key matches any tool carrying that extension.
+func (f ToolFilter) Matches(tool *types.GeneratedTool) bool {
+	if f.hasDocumentCriteria() && !f.matchesDocument(tool) {
+		return false
+	}
+
+	for _, filter := range f.Metadata {
+		key, value, _ := strings.Cut(filter, "=")
+		if !MetadataMatches(tool, key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasDocumentCriteria reports whether any document or free-text criterion is set
+func (f ToolFilter) hasDocumentCriteria() bool {
+	return len(f.PackageIDs) > 0 || len(f.TWCDomains) > 0 || len(f.TWCPortfolios) > 0 ||
+		len(f.TWCGeographies) > 0 || len(f.Custom) > 0
+}
+
+// matchesDocument checks the document metadata and free-text criteria
+func (f ToolFilter) matchesDocument(tool *types.GeneratedTool) bool {
+	if tool.DocumentInfo == nil {
+		return false
+	}
+
+	if len(f.PackageIDs) > 0 && !hasAnyMatch(f.PackageIDs, tool.DocumentInfo.PackageIDs) {
+		return false
+	}
+	if len(f.TWCDomains) > 0 && !hasAnyMatch(f.TWCDomains, tool.DocumentInfo.TwcDomain) {
+		return false
+	}
+	if len(f.TWCPortfolios) > 0 && !hasAnyMatch(f.TWCPortfolios, tool.DocumentInfo.TwcDomainPortfolio) {
+		return false
+	}
+	if len(f.TWCGeographies) > 0 && !hasAnyMatch(f.TWCGeographies, tool.DocumentInfo.TwcGeography) {
+		return false
+	}
+
+	// Custom filters match the document title, tool description, or endpoint tags
+	if len(f.Custom) > 0 {
+		for _, filter := range f.Custom {
+			needle := strings.ToLower(filter)
+			if strings.Contains(strings.ToLower(tool.DocumentInfo.Title), needle) ||
+				strings.Contains(strings.ToLower(tool.Description), needle) {
+				return true
+			}
+			if tool.Endpoint != nil && containsInSlice(tool.Endpoint.Tags, needle) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// parseCommaSeparated splits a comma-separated value, dropping empty entries
+func parseCommaSeparated(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// hasAnyMatch checks if any item in the first slice matches any item in the second slice
+func hasAnyMatch(searchItems []string, targetItems []string) bool {
+	for _, searchItem := range searchItems {
+		for _, targetItem := range targetItems {
+			if searchItem == targetItem {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsInSlice checks if any string in the slice contains the search term (case-insensitive)
+func containsInSlice(slice []string, searchTerm string) bool {
+	searchLower := strings.ToLower(searchTerm)
+	for _, s := range slice {
+		if strings.Contains(strings.ToLower(s), searchLower) {
+			return true
+		}
+	}
+	return false
+}
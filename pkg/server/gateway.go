@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Names of the tools served in gateway mode instead of the generated tools
+const (
+	GatewayCallToolName   = "call_api"
+	GatewaySearchToolName = "search_operations"
+)
+
+// GatewayOperation is an operation found by the gateway search tool
+type GatewayOperation struct {
+	OperationID string                 `json:"operationId"`
+	Method      string                 `json:"method,omitempty"`
+	Path        string                 `json:"path,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// GatewaySearch is the response of the gateway search tool
+type GatewaySearch struct {
+	Query      string             `json:"query"`
+	Total      int                `json:"total"`
+	Operations []GatewayOperation `json:"operations"`
+}
+
+// GatewayTools describes the tools served in gateway mode: one searching the operations
+// of the generated and composite tools, and one calling an operation by its ID
+func GatewayTools() []types.MCPTool {
+	return []types.MCPTool{
+		{
+			Name:        GatewaySearchToolName,
+			Description: "Search the operations of the API, returning the operationId and input schema of each for call_api. Every word of the query must match the operation's ID, method, path, tags, or description; without a query every operation is listed.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Words to search for, e.g. \"daily forecast\"",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum number of operations (default %d, at most %d)", defaultSearchLimit, maxSearchLimit),
+					},
+				},
+			},
+		},
+		{
+			Name:        GatewayCallToolName,
+			Description: "Call an operation of the API found with search_operations, with arguments matching its input schema.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"operationId": map[string]interface{}{
+						"type":        "string",
+						"description": "The operationId of the operation, as returned by search_operations",
+					},
+					"arguments": map[string]interface{}{
+						"type":        "object",
+						"description": "The arguments of the operation",
+					},
+				},
+				"required": []string{"operationId"},
+			},
+		},
+	}
+}
+
+// IsGatewayTool reports whether a tool name is one of the gateway tools
+func IsGatewayTool(name string) bool {
+	return name == GatewayCallToolName || name == GatewaySearchToolName
+}
+
+// ResolveGatewayCall returns the tool and arguments a call_api call dispatches to. The
+// operationId names a generated or composite tool, or is the operationId of the endpoint
+// of exactly one generated tool.
+func (e *ToolExecutor) ResolveGatewayCall(arguments map[string]interface{}) (*types.GeneratedTool, map[string]interface{}, error) {
+	operationID, _ := arguments["operationId"].(string)
+	if strings.TrimSpace(operationID) == "" {
+		return nil, nil, fmt.Errorf("the operationId argument is required")
+	}
+
+	var toolArguments map[string]interface{}
+	switch value := arguments["arguments"].(type) {
+	case nil:
+		toolArguments = map[string]interface{}{}
+	case map[string]interface{}:
+		toolArguments = value
+	default:
+		return nil, nil, fmt.Errorf("the arguments argument must be an object")
+	}
+
+	if tool := e.tools.GetTool(operationID); tool != nil {
+		return tool, toolArguments, nil
+	}
+	if tool := e.CompositeTool(operationID); tool != nil {
+		return tool, toolArguments, nil
+	}
+
+	var matches []*types.GeneratedTool
+	for _, tool := range e.tools.GetAllTools() {
+		if tool.Endpoint != nil && tool.Endpoint.OperationID == operationID {
+			matches = append(matches, tool)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil, fmt.Errorf("unknown operation %s; use %s to find operations", operationID, GatewaySearchToolName)
+	case 1:
+		return matches[0], toolArguments, nil
+	default:
+		names := make([]string, len(matches))
+		for i, tool := range matches {
+			names[i] = tool.Name
+		}
+		sort.Strings(names)
+		return nil, nil, fmt.Errorf("operation %s is ambiguous; use one of %s", operationID, strings.Join(names, ", "))
+	}
+}
+
+// CallGatewaySearch runs the gateway search tool with the given arguments, finding the
+// operations the calling client's tenant is entitled to
+func (e *ToolExecutor) CallGatewaySearch(arguments map[string]interface{}, tenant *Tenant) types.MCPCallToolResult {
+	query, _ := arguments["query"].(string)
+	limit := defaultSearchLimit
+	if value, ok := arguments["limit"].(float64); ok && value > 0 {
+		limit = int(value)
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	tools := e.tools.GetAllTools()
+	for _, composite := range e.composites {
+		if !e.tools.HasTool(composite.Name) {
+			tools = append(tools, composite)
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	words := strings.Fields(strings.ToLower(query))
+	search := GatewaySearch{Query: query, Operations: []GatewayOperation{}}
+	for _, tool := range tools {
+		if !tenant.Allows(tool) || !gatewayOperationMatches(tool, words) {
+			continue
+		}
+		search.Total++
+		if len(search.Operations) < limit {
+			operation := GatewayOperation{
+				OperationID: tool.Name,
+				Description: tool.Description,
+				InputSchema: tool.Schema(),
+			}
+			if tool.Endpoint != nil {
+				operation.Method = tool.Endpoint.Method
+				operation.Path = tool.Endpoint.Path
+			}
+			search.Operations = append(search.Operations, operation)
+		}
+	}
+
+	content, err := json.MarshalIndent(search, "", "  ")
+	if err != nil {
+		return types.MCPCallToolResult{
+			Content: []types.MCPContent{{Type: "text", Text: fmt.Sprintf("Failed to encode operations: %s", err.Error())}},
+			IsError: true,
+		}
+	}
+	return types.MCPCallToolResult{
+		Content: []types.MCPContent{{Type: "text", Text: string(content)}},
+	}
+}
+
+// gatewayOperationMatches reports whether every word occurs in the name, operation ID,
+// method, path, tags, or description of a tool
+func gatewayOperationMatches(tool *types.GeneratedTool, words []string) bool {
+	fields := []string{tool.Name, tool.Description}
+	if tool.Endpoint != nil {
+		fields = append(fields, tool.Endpoint.OperationID, tool.Endpoint.Method, tool.Endpoint.Path)
+		fields = append(fields, tool.Endpoint.Tags...)
+	}
+	text := strings.ToLower(strings.Join(fields, " "))
+
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ListenAddress returns the address an HTTP server listens on: the configured listen
+// address if there is one, or the port on all interfaces
+func ListenAddress(listen string, port int) string {
+	if listen != "" {
+		return listen
+	}
+	return fmt.Sprintf(":%d", port)
+}
+
+// Listen opens the listener of an HTTP server. Addresses of the form unix:/path.sock
+// listen on a Unix domain socket created with the given octal file mode, replacing a
+// socket left behind by a server that did not shut down; others are TCP addresses.
+func Listen(address, socketMode string) (net.Listener, error) {
+	socketPath, ok := strings.CutPrefix(address, "unix:")
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+
+	if err := removeStaleSocket(socketPath); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if socketMode != "" {
+		mode, err := strconv.ParseUint(socketMode, 8, 32)
+		if err == nil {
+			err = os.Chmod(socketPath, os.FileMode(mode))
+		}
+		if err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to set the mode of socket %s: %w", socketPath, err)
+		}
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes the socket file at path if no server accepts connections
+// on it. Files that are not sockets are left alone, so that a typo does not delete them.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %s is in use by another server", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("failed to check socket %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// mcpLogLevels maps MCP (RFC 5424) log levels onto the nearest zap level
+var mcpLogLevels = map[string]zapcore.Level{
+	"debug":     zapcore.DebugLevel,
+	"info":      zapcore.InfoLevel,
+	"notice":    zapcore.InfoLevel,
+	"warning":   zapcore.WarnLevel,
+	"error":     zapcore.ErrorLevel,
+	"critical":  zapcore.ErrorLevel,
+	"alert":     zapcore.ErrorLevel,
+	"emergency": zapcore.ErrorLevel,
+}
+
+// handleSetLevel handles the logging/setLevel request by forwarding server log
+// entries at or above the requested level to the client as notifications/message,
+// and changes the egress sample rate when the request sets egressSampleRate
+func (s *MCPServer) handleSetLevel(request *types.MCPRequest) error {
+	paramsBytes, err := json.Marshal(request.Params)
+	if err != nil {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	var params types.MCPSetLevelParams
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	level, exists := mcpLogLevels[strings.ToLower(params.Level)]
+	if !exists {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", map[string]interface{}{
+			"reason": "unknown log level: " + params.Level,
+		})
+	}
+
+	if rate := params.EgressSampleRate; rate != nil {
+		if *rate < 0 || *rate > 1 {
+			return s.sendErrorResponse(request.ID, -32602, "Invalid params", map[string]interface{}{
+				"reason": "egressSampleRate must be between 0 and 1",
+			})
+		}
+		s.logger.SetEgressSampleRate(*rate)
+	}
+
+	s.logger.Debug("Setting client log level", zap.String("level", params.Level),
+		zap.Float64("egressSampleRate", s.logger.EgressSampleRate()))
+	s.logger.SetForwarder(level, s.forwardLog)
+
+	return s.sendResponse(request.ID, map[string]interface{}{})
+}
+
+// forwardLog sends a log entry to the client as a notifications/message notification.
+// Failures are dropped because reporting them would log and forward again.
+func (s *MCPServer) forwardLog(entry utils.LogEntry) {
+	data := make(map[string]interface{}, len(entry.Fields)+1)
+	for key, value := range entry.Fields {
+		data[key] = value
+	}
+	data["message"] = entry.Message
+
+	encoded, err := json.Marshal(types.MCPNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/message",
+		Params: types.MCPLogMessageParams{
+			Level:  mcpLevelName(entry.Level),
+			Logger: entry.Logger,
+			Data:   data,
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	_ = s.writeMessage(encoded)
+}
+
+// mcpLevelName maps a zap level onto the MCP log level name
+func mcpLevelName(level zapcore.Level) string {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return "debug"
+	case level == zapcore.InfoLevel:
+		return "info"
+	case level == zapcore.WarnLevel:
+		return "warning"
+	case level == zapcore.ErrorLevel:
+		return "error"
+	case level == zapcore.FatalLevel:
+		return "emergency"
+	default:
+		return "critical"
+	}
+}
@@ -2,12 +2,17 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"swagger-docs-mcp/pkg/http"
@@ -24,34 +29,132 @@ type MCPServer struct {
 	parser       *swagger.Parser
 	generator    *swagger.ToolGenerator
 	toolRegistry *ToolRegistry
+	completer    *Completer
+	refresher    *DocumentRefresher
+	watcher      *DocumentWatcher
+	snapshot     *RegistrySnapshot
 	httpClient   *http.Client
+	executor     *ToolExecutor
 	stdin        io.Reader
 	stdout       io.Writer
-	initialized  bool
+	writeMutex   sync.Mutex
+	initialized  atomic.Bool
 	shutdown     chan struct{}
 	wg           sync.WaitGroup
+
+	promptGenerator   *swagger.PromptGenerator
+	promptRegistry    *PromptRegistry
+	resourceGenerator *swagger.ResourceGenerator
+	resourceRegistry  *ResourceRegistry
+	documents         *DocumentStore
+	searchIndex       *SearchIndex
+	reloadMutex       sync.Mutex
+
+	subscriptions      map[string]bool
+	subscriptionsMutex sync.RWMutex
+
+	inFlight      map[string]*inFlightCall
+	inFlightMutex sync.Mutex
+
+	// batchMutex guards the JSON-RPC batches awaiting the responses of their requests
+	batchMutex sync.Mutex
+
+	// shared holds the tools and documents, which only the primary server loads
+	shared  *SharedState
+	primary bool
+	loading atomic.Bool
 }
 
 // NewMCPServer creates a new MCP server
 func NewMCPServer(config *types.ResolvedConfig, logger *utils.Logger) *MCPServer {
+	return NewSharedMCPServer(config, logger, NewSharedState(config, logger), true)
+}
+
+// NewSharedMCPServer creates an MCP server serving the tools and documents of shared
+// state. A primary server scans the documents into the state and keeps them up to
+// date; other servers serve them as the primary server of the state loads them.
+func NewSharedMCPServer(config *types.ResolvedConfig, logger *utils.Logger, shared *SharedState, primary bool) *MCPServer {
 	scanner := swagger.NewScanner(logger)
-	parser := swagger.NewParser(logger)
+	scanner.SetHostPolicy(http.NewHostPolicy(config))
+	parser := swagger.NewParserWithConfig(logger, &config.SwaggerProcessing)
+	parser.SetHostPolicy(http.NewHostPolicy(config))
 	generator := swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration)
-	toolRegistry := NewToolRegistry()
-	httpClient := http.NewClient(config, logger)
+	generator.SetAuth(&config.Auth)
+	toolRegistry := shared.Tools
+	toolRegistry.SetSchemaLoader(generator.AttachInputSchema)
 
-	return &MCPServer{
+	s := &MCPServer{
 		config:       config,
 		logger:       logger.Child("mcp-server"),
 		scanner:      scanner,
 		parser:       parser,
 		generator:    generator,
 		toolRegistry: toolRegistry,
-		httpClient:   httpClient,
+		completer:    NewCompleter(toolRegistry, shared.Prompts),
+		refresher:    NewDocumentRefresher(config, logger, toolRegistry),
+		watcher:      NewDocumentWatcher(logger),
+		httpClient:   shared.HTTPClient,
+		executor:     shared.Executor,
 		stdin:        os.Stdin,
 		stdout:       os.Stdout,
 		shutdown:     make(chan struct{}),
+
+		promptGenerator:   swagger.NewPromptGenerator(logger, &config.Prompts),
+		promptRegistry:    shared.Prompts,
+		resourceGenerator: swagger.NewResourceGenerator(logger, &config.Resources),
+		resourceRegistry:  shared.Resources,
+		documents:         shared.Documents,
+		searchIndex:       shared.SearchIndex,
+		subscriptions:     make(map[string]bool),
+		inFlight:          make(map[string]*inFlightCall),
+		shared:            shared,
+		primary:           primary,
+	}
+
+	s.promptGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+	s.resourceGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+
+	// Only the primary server writes the tools it loads to the snapshot
+	if config.Server.SnapshotPath != "" && primary {
+		s.snapshot = NewRegistrySnapshot(config.Server.SnapshotPath, logger)
 	}
+
+	s.shared.OnToolsChanged(func(changes ToolChanges) {
+		// Clients are only notified once initialized, and commands scanning the tools
+		// without serving them never are. The tools listed in gateway mode stay the same.
+		if !s.initialized.Load() || s.config.Server.Gateway {
+			return
+		}
+		if err := s.sendNotification("notifications/tools/list_changed", nil); err != nil {
+			s.logger.Error("Failed to send tools list changed notification", zap.Error(err))
+		}
+	})
+	s.shared.OnListsChanged(func(prompts, resources bool) {
+		if !s.initialized.Load() {
+			return
+		}
+		if prompts {
+			if err := s.sendNotification("notifications/prompts/list_changed", nil); err != nil {
+				s.logger.Error("Failed to send prompts list changed notification", zap.Error(err))
+			}
+		}
+		if resources {
+			if err := s.sendNotification("notifications/resources/list_changed", nil); err != nil {
+				s.logger.Error("Failed to send resources list changed notification", zap.Error(err))
+			}
+		}
+	})
+	s.refresher.OnChange(func(changes ToolChanges) {
+		s.shared.NotifyToolsChanged(changes)
+		s.saveSnapshot()
+	})
+	s.refresher.OnDocument(s.updateDocument)
+	s.refresher.SetSourceTracker(shared.Sources)
+	s.refresher.OnEvict(s.evictDocument)
+	s.refresher.SetLeaderCheck(shared.Catalog.Refreshes)
+	s.watcher.OnChange(s.reloadDocument)
+
+	return s
 }
 
 // Start starts the MCP server
@@ -59,7 +162,18 @@ func (s *MCPServer) Start(ctx context.Context) error {
 	s.logger.Info("Starting MCP server", zap.String("name", s.config.Name), zap.String("version", s.config.Version))
 
 	// Note: Tool initialization is now deferred until the first MCP initialize request
-	// This prevents issues with the MCP protocol handshake
+	// This prevents issues with the MCP protocol handshake. Tools from a previous run's
+	// snapshot are served in the meantime.
+	s.loadSnapshot()
+
+	// The primary server shares the tools it loads with the other replicas
+	if s.primary {
+		s.shared.SyncCatalog()
+	}
+
+	// In-flight tool calls are cancelled when the server stops
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	// Start message handling loop
 	s.wg.Add(1)
@@ -73,7 +187,8 @@ func (s *MCPServer) Start(ctx context.Context) error {
 		s.logger.Info("Shutdown signal received")
 	}
 
-	close(s.shutdown)
+	s.Stop()
+	cancel()
 	s.wg.Wait()
 
 	s.logger.Info("MCP server stopped")
@@ -104,6 +219,7 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to scan swagger documents: %w", err)
 	}
+	s.shared.Sources.RecordScan(scanResult)
 
 	s.logger.Info("Scan complete",
 		zap.Int("totalFiles", scanResult.Stats.TotalFiles),
@@ -132,8 +248,21 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 		s.logger.Debug("Filtered by dynamic filters", zap.Int("documentsRemaining", len(documents)))
 	}
 
+	// Load prompt templates first so that they take the place of generated prompts
+	if s.config.Prompts.Enabled {
+		if err := s.promptGenerator.LoadTemplates(); err != nil {
+			s.logger.Error("Failed to load prompt templates", zap.Error(err))
+		}
+		s.promptRegistry.Clear()
+	}
+
+	// Tools are collected into a fresh registry and swapped in at the end, so that tools
+	// loaded from a snapshot keep being served until the scan completes
+	registry := NewToolRegistry()
+
 	// Parse documents and generate tools
 	toolCount := 0
+	var registered []*types.GeneratedTool
 	for _, docInfo := range documents {
 		var parsedDoc *types.SwaggerDocument
 		var err error
@@ -152,6 +281,10 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 				zap.String("title", docInfo.Title),
 				zap.Int("contentSize", len(docInfo.Content)),
 				zap.Bool("isRemote", docInfo.IsRemote))
+			s.shared.Sources.RecordDocumentError(docInfo.FilePath, err)
+			if !s.config.SwaggerProcessing.IgnoreErrors {
+				return fmt.Errorf("failed to parse document %s: %w", docInfo.FilePath, err)
+			}
 			continue
 		}
 
@@ -164,12 +297,18 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 				zap.String("title", docInfo.Title),
 				zap.Int("pathCount", getPathCount(parsedDoc)),
 				zap.String("version", docInfo.Version))
+			s.shared.Sources.RecordDocumentError(docInfo.FilePath, err)
 			continue
 		}
+		s.shared.Sources.RecordDocumentLoaded(docInfo.FilePath)
+
+		// Keep the parsed document for resource reads and reloads
+		s.registerDocument(&docInfo, parsedDoc)
+		s.registerDocumentPrompts(&docInfo, parsedDoc)
 
 		// Register tools
 		for _, tool := range tools {
-			if err := s.toolRegistry.RegisterTool(tool); err != nil {
+			if err := registry.RegisterTool(tool); err != nil {
 				s.logger.Error("Failed to register tool",
 					zap.Error(err),
 					zap.String("toolName", tool.Name),
@@ -180,6 +319,7 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 				// Continue processing other tools even if one fails
 			} else {
 				toolCount++
+				registered = append(registered, tool)
 				s.logger.Debug("Successfully registered tool",
 					zap.String("toolName", tool.Name),
 					zap.String("method", tool.Endpoint.Method),
@@ -189,27 +329,80 @@ func (s *MCPServer) initializeTools(ctx context.Context) error {
 			}
 		}
 
-		// Check max tools limit
-		if s.config.Server.MaxTools > 0 && toolCount >= s.config.Server.MaxTools {
-			s.logger.Warn("Reached maximum tool limit, stopping tool generation", zap.Int("maxTools", s.config.Server.MaxTools))
-			break
+	}
+
+	// Drop the tools of the lowest priority beyond the maximum
+	if _, dropped := LimitTools(registered, s.generator, s.config); len(dropped) > 0 {
+		for _, tool := range dropped {
+			registry.UnregisterTool(tool.Name)
 		}
+		toolCount -= len(dropped)
+		s.logger.Warn("Reached maximum tool limit, dropping the tools of the lowest priority",
+			zap.Int("maxTools", s.config.Server.MaxTools),
+			zap.Int("toolsDropped", len(dropped)))
+	}
+
+	if s.config.Prompts.Enabled {
+		s.registerTemplatePrompts()
 	}
+	s.shared.NotifyListsChanged(s.config.Prompts.Enabled, s.config.Resources.Enabled)
+
+	changes := s.toolRegistry.ReplaceAll(registry.GetAllTools())
 
 	s.logger.Info("Tool initialization complete",
 		zap.Int("documentsProcessed", len(documents)),
 		zap.Int("toolsGenerated", toolCount),
-		zap.Int("toolsRegistered", s.toolRegistry.GetToolCount()))
+		zap.Int("toolsRegistered", s.toolRegistry.GetToolCount()),
+		zap.Int("promptsRegistered", s.promptRegistry.GetPromptCount()),
+		zap.Int("resourcesRegistered", s.resourceRegistry.GetResourceCount()))
+
+	// Clients may already have listed tools loaded from a snapshot
+	if !changes.IsEmpty() {
+		s.shared.NotifyToolsChanged(changes)
+	}
+	s.saveSnapshot()
 
 	return nil
 }
 
+// loadSnapshot registers the tools of the registry snapshot, if one is configured
+func (s *MCPServer) loadSnapshot() {
+	if s.snapshot == nil {
+		return
+	}
+
+	loaded, err := s.snapshot.Load(s.toolRegistry, SnapshotFingerprint(s.config))
+	if err != nil {
+		s.logger.Warn("Failed to load registry snapshot", zap.Error(err))
+		return
+	}
+	if loaded > 0 {
+		s.logger.Info("Loaded tools from registry snapshot", zap.Int("tools", loaded))
+	}
+}
+
+// saveSnapshot persists the tool registry, if a snapshot is configured
+func (s *MCPServer) saveSnapshot() {
+	if s.snapshot == nil {
+		return
+	}
+
+	if err := s.snapshot.Save(s.toolRegistry, SnapshotFingerprint(s.config)); err != nil {
+		s.logger.Warn("Failed to save registry snapshot", zap.Error(err))
+	}
+}
+
 // handleMessages handles incoming MCP messages
 func (s *MCPServer) handleMessages(ctx context.Context) {
 	defer s.wg.Done()
 
-	scanner := bufio.NewScanner(s.stdin)
-	for scanner.Scan() {
+	reader := bufio.NewReader(s.stdin)
+	for {
+		message, err := readMessage(reader, s.config.Server.MaxMessageSize)
+		if err == io.EOF {
+			return
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -218,34 +411,110 @@ func (s *MCPServer) handleMessages(ctx context.Context) {
 		default:
 		}
 
-		line := scanner.Text()
+		// The rest of a message that is too large was skipped, so reading goes on
+		if err == errMessageTooLarge {
+			s.logger.Error("Dropped JSON-RPC message larger than the limit", zap.Int("maxMessageSize", s.config.Server.MaxMessageSize))
+			s.sendErrorResponse(nil, -32600, "Message too large", map[string]interface{}{
+				"maxMessageSize": s.config.Server.MaxMessageSize,
+			})
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Error reading from stdin", zap.Error(err))
+			return
+		}
+
+		line := string(message)
 		if line == "" {
 			continue
 		}
 
 		s.logger.Debug("Received message", zap.String("message", line))
 
+		// Batches are arrays of requests and notifications
+		if trimmed := strings.TrimLeft(line, " \t\r"); strings.HasPrefix(trimmed, "[") {
+			s.handleBatch(ctx, trimmed)
+			continue
+		}
+
 		// Parse the JSON-RPC message
-		var request types.MCPRequest
-		if err := json.Unmarshal([]byte(line), &request); err != nil {
+		request, err := decodeRequest([]byte(line))
+		if err != nil {
 			s.logger.Error("Failed to parse JSON-RPC message", zap.Error(err), zap.String("rawMessage", line))
 			s.sendErrorResponse(nil, -32700, "Parse error", nil)
 			continue
 		}
 
 		// Handle the request
-		if err := s.handleRequest(&request); err != nil {
+		if err := s.handleRequest(ctx, request); err != nil {
 			s.logger.Error("Failed to handle request", zap.Error(err), zap.String("method", request.Method))
 		}
 	}
+}
+
+// decodeRequest parses a JSON-RPC message, keeping its ID and params as their JSON
+// text. Responses then carry the ID exactly as it was sent, and requests are told apart
+// by it: the string ID "1" and the number 1 are different requests.
+func decodeRequest(message []byte) (*types.MCPRequest, error) {
+	var decoded struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &decoded); err != nil {
+		return nil, err
+	}
 
-	if err := scanner.Err(); err != nil {
-		s.logger.Error("Error reading from stdin", zap.Error(err))
+	request := &types.MCPRequest{JSONRPC: decoded.JSONRPC, Method: decoded.Method}
+	if len(decoded.ID) > 0 && string(decoded.ID) != "null" {
+		request.ID = decoded.ID
 	}
+	if len(decoded.Params) > 0 {
+		request.Params = decoded.Params
+	}
+	return request, nil
+}
+
+// errMessageTooLarge is returned by readMessage for lines longer than the limit
+var errMessageTooLarge = errors.New("message too large")
+
+// readMessage reads one line of input without its line ending, growing its buffer up
+// to maxSize bytes. Longer lines are read to their end and dropped, returning
+// errMessageTooLarge, so that the next message can still be read. A last line without
+// line ending is returned before io.EOF.
+func readMessage(reader *bufio.Reader, maxSize int) ([]byte, error) {
+	var line []byte
+	tooLarge := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLarge {
+			if len(line)+len(bytes.TrimRight(chunk, "\r\n")) > maxSize {
+				tooLarge, line = true, nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && (len(line) > 0 || tooLarge) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	if tooLarge {
+		return nil, errMessageTooLarge
+	}
+	return bytes.TrimRight(line, "\r\n"), nil
 }
 
 // handleRequest handles a specific MCP request
-func (s *MCPServer) handleRequest(request *types.MCPRequest) error {
+func (s *MCPServer) handleRequest(ctx context.Context, request *types.MCPRequest) error {
 	switch request.Method {
 	case "initialize":
 		return s.handleInitialize(request)
@@ -254,7 +523,27 @@ func (s *MCPServer) handleRequest(request *types.MCPRequest) error {
 	case "tools/list":
 		return s.handleListTools(request)
 	case "tools/call":
-		return s.handleCallTool(request)
+		// Tool calls run concurrently so that cancellation notifications can be read
+		// meanwhile. The call is cancellable by request ID before it starts, so that a
+		// cancellation read right after the request is not missed.
+		callCtx, cancel := context.WithCancelCause(ctx)
+		call := s.trackInFlight(request.ID, cancel)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrackInFlight(request.ID, call)
+			defer cancel(nil)
+			if err := s.handleCallTool(callCtx, request); err != nil {
+				s.logger.Error("Failed to handle request", zap.Error(err), zap.String("method", request.Method))
+			}
+		}()
+		return nil
+	case "notifications/cancelled":
+		return s.handleCancelled(request)
+	case "logging/setLevel":
+		return s.handleSetLevel(request)
+	case "completion/complete":
+		return s.handleComplete(request)
 	case "prompts/list":
 		return s.handleListPrompts(request)
 	case "prompts/get":
@@ -263,6 +552,12 @@ func (s *MCPServer) handleRequest(request *types.MCPRequest) error {
 		return s.handleListResources(request)
 	case "resources/read":
 		return s.handleReadResource(request)
+	case "resources/templates/list":
+		return s.handleListResourceTemplates(request)
+	case "resources/subscribe":
+		return s.handleSubscribe(request)
+	case "resources/unsubscribe":
+		return s.handleUnsubscribe(request)
 	default:
 		// Check if this is a notification (no ID field)
 		if request.ID == nil {
@@ -294,7 +589,7 @@ func (s *MCPServer) handleInitialize(request *types.MCPRequest) error {
 	// Add resources capability if enabled
 	if s.config.Resources.Enabled {
 		capabilities.Resources = &types.MCPResourcesCapability{
-			Subscribe:   false,
+			Subscribe:   true,
 			ListChanged: true,
 		}
 	}
@@ -302,6 +597,9 @@ func (s *MCPServer) handleInitialize(request *types.MCPRequest) error {
 	// Add logging capability
 	capabilities.Logging = &types.MCPLoggingCapability{}
 
+	// Add argument completion capability
+	capabilities.Completions = &types.MCPCompletionsCapability{}
+
 	result := types.MCPInitializeResult{
 		ProtocolVersion: "2024-11-05",
 		Capabilities:    capabilities,
@@ -317,32 +615,149 @@ func (s *MCPServer) handleInitialize(request *types.MCPRequest) error {
 // handleInitialized handles the initialized notification
 func (s *MCPServer) handleInitialized(request *types.MCPRequest) error {
 	s.logger.Debug("Handling initialized notification")
-	s.initialized = true
+	s.initialized.Store(true)
 
 	// Now that MCP is initialized, trigger tool initialization in background
-	go func() {
-		ctx := context.Background()
-		if err := s.initializeTools(ctx); err != nil {
-			s.logger.Error("Failed to initialize tools after MCP handshake", zap.Error(err))
+	go s.LoadDocuments()
+
+	return nil
+}
+
+// LoadDocuments scans the documents into the tools and resources of the server and
+// keeps them up to date until the server stops. It is called in the background once
+// the client has initialized, or earlier to serve the tools to other transports
+// sooner; later calls and calls on servers that are not primary do nothing.
+func (s *MCPServer) LoadDocuments() {
+	if !s.primary || !s.loading.CompareAndSwap(false, true) {
+		return
+	}
+
+	ctx := context.Background()
+	err := s.initializeTools(ctx)
+	// Servers sharing the state serve whatever was loaded, even after a failure
+	s.shared.MarkReady()
+	if err != nil {
+		s.logger.Error("Failed to initialize tools", zap.Error(err))
+		if !s.config.SwaggerProcessing.IgnoreErrors {
+			s.Stop()
+			return
 		}
+	}
+
+	// Keep documents up to date until the server shuts down
+	updateCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-s.shutdown
+		cancel()
 	}()
 
-	return nil
+	if paths := s.documents.LocalPaths(); s.config.SwaggerProcessing.WatchFiles && len(paths) > 0 {
+		go func() {
+			if err := s.watcher.Run(updateCtx, paths); err != nil {
+				s.logger.Error("Failed to watch swagger documents", zap.Error(err))
+			}
+		}()
+	}
+
+	if s.refresher.Enabled() {
+		s.refresher.Run(updateCtx)
+	}
+}
+
+// ScanTools scans the documents into the tools of the server without serving them,
+// for commands that use the tools directly
+func (s *MCPServer) ScanTools(ctx context.Context) error {
+	return s.initializeTools(ctx)
+}
+
+// LoadSnapshot registers the tools of the registry snapshot, if one is configured,
+// and returns how many tools the server has
+func (s *MCPServer) LoadSnapshot() int {
+	s.loadSnapshot()
+	return s.toolRegistry.GetToolCount()
+}
+
+// Tools returns the generated tools of the server followed by its composite tools
+func (s *MCPServer) Tools() []*types.GeneratedTool {
+	tools := s.toolRegistry.GetAllTools()
+	for _, composite := range s.executor.CompositeTools() {
+		if !s.toolRegistry.HasTool(composite.Name) {
+			tools = append(tools, composite)
+		}
+	}
+	return tools
+}
+
+// CallTool executes a tool by name outside of an MCP session, as tools/call would
+func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (types.MCPCallToolResult, error) {
+	if s.config.Server.Gateway && name == GatewaySearchToolName {
+		return s.executor.CallGatewaySearch(arguments, nil), nil
+	}
+	if name == SearchToolName && s.searchToolEnabled() {
+		return CallSearchTool(s.searchIndex, arguments), nil
+	}
+
+	tool, arguments, err := s.callTarget(name, arguments)
+	if err != nil {
+		return types.MCPCallToolResult{}, err
+	}
+	if tool == nil {
+		return types.MCPCallToolResult{}, fmt.Errorf("tool not found: %s", name)
+	}
+
+	started := time.Now()
+	result, err := s.executeAPICall(ctx, tool, arguments)
+	s.toolRegistry.RecordToolCall(tool.Name, time.Since(started), err != nil || result.IsError)
+	return result, err
+}
+
+// callTarget returns the tool a call of the named tool runs and its arguments: the
+// generated or composite tool of that name, or in gateway mode the operation a call_api
+// call names. The tool is nil when there is none.
+func (s *MCPServer) callTarget(name string, arguments map[string]interface{}) (*types.GeneratedTool, map[string]interface{}, error) {
+	if s.config.Server.Gateway && name == GatewayCallToolName {
+		return s.executor.ResolveGatewayCall(arguments)
+	}
+
+	tool := s.toolRegistry.GetTool(name)
+	if tool == nil {
+		tool = s.executor.CompositeTool(name)
+	}
+	return tool, arguments, nil
 }
 
 // handleListTools handles the tools/list request
 func (s *MCPServer) handleListTools(request *types.MCPRequest) error {
 	s.logger.Debug("Handling tools/list request")
 
-	tools := s.toolRegistry.GetAllTools()
-	mcpTools := make([]types.MCPTool, len(tools))
-
-	for i, tool := range tools {
-		mcpTools[i] = types.MCPTool{
-			Name:        tool.Name,
-			Description: tool.Description,
-			InputSchema: tool.InputSchema,
+	var mcpTools []types.MCPTool
+	if s.config.Server.Gateway {
+		// The generated and composite tools are searched and called through the gateway tools
+		mcpTools = GatewayTools()
+	} else {
+		tools := s.toolRegistry.GetAllTools()
+		mcpTools = make([]types.MCPTool, len(tools))
+
+		for i, tool := range tools {
+			mcpTools[i] = types.MCPTool{
+				Name:        tool.Name,
+				Description: tool.Description,
+				InputSchema: tool.Schema(),
+			}
 		}
+		// Generated tools take precedence over composite tools of the same name
+		for _, composite := range s.executor.CompositeTools() {
+			if !s.toolRegistry.HasTool(composite.Name) {
+				mcpTools = append(mcpTools, types.MCPTool{
+					Name:        composite.Name,
+					Description: composite.Description,
+					InputSchema: composite.InputSchema,
+				})
+			}
+		}
+	}
+	if s.searchToolEnabled() {
+		mcpTools = append(mcpTools, SearchTool())
 	}
 
 	result := types.MCPListToolsResult{
@@ -353,8 +768,9 @@ func (s *MCPServer) handleListTools(request *types.MCPRequest) error {
 	return s.sendResponse(request.ID, result)
 }
 
-// handleCallTool handles the tools/call request
-func (s *MCPServer) handleCallTool(request *types.MCPRequest) error {
+// handleCallTool handles the tools/call request. The context is cancelled when the
+// client cancels the request.
+func (s *MCPServer) handleCallTool(ctx context.Context, request *types.MCPRequest) error {
 	s.logger.Debug("Handling tools/call request")
 
 	// Parse parameters
@@ -368,16 +784,43 @@ func (s *MCPServer) handleCallTool(request *types.MCPRequest) error {
 		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
 	}
 
+	if s.config.Server.Gateway && params.Name == GatewaySearchToolName {
+		return s.sendResponse(request.ID, s.executor.CallGatewaySearch(params.Arguments, nil))
+	}
+	if params.Name == SearchToolName && s.searchToolEnabled() {
+		return s.sendResponse(request.ID, CallSearchTool(s.searchIndex, params.Arguments))
+	}
+
 	// Get the tool
-	tool := s.toolRegistry.GetTool(params.Name)
+	tool, arguments, err := s.callTarget(params.Name, params.Arguments)
+	if err != nil {
+		return s.sendResponse(request.ID, types.MCPCallToolResult{
+			Content: []types.MCPContent{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		})
+	}
 	if tool == nil {
 		return s.sendErrorResponse(request.ID, -32601, "Tool not found", nil)
 	}
 
-	s.logger.Debug("Executing tool", zap.String("name", params.Name), zap.Any("arguments", params.Arguments))
+	s.logger.Debug("Executing tool", zap.String("name", tool.Name), zap.Any("arguments", arguments))
 
-	// Execute the tool
-	result, err := s.executeAPICall(tool, params.Arguments)
+	started := time.Now()
+	result, err := s.executeAPICall(ctx, tool, arguments)
+	duration := time.Since(started)
+	s.toolRegistry.RecordToolCall(tool.Name, duration, err != nil || result.IsError)
+	// Cancelled calls are reported too
+	s.shared.ToolCalled(context.WithoutCancel(ctx), CompletedToolCall(types.TransportStdio, tool.Name, arguments, duration, result, err))
+	if err != nil && ctx.Err() != nil {
+		s.logger.Info("Tool execution cancelled", zap.String("toolName", params.Name), zap.Error(context.Cause(ctx)))
+		return s.sendResponse(request.ID, types.MCPCallToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Tool execution cancelled: %s", context.Cause(ctx).Error()),
+			}},
+			IsError: true,
+		})
+	}
 	if err != nil {
 		s.logger.Error("Tool execution failed", zap.Error(err), zap.String("toolName", params.Name))
 		errorContent := types.MCPContent{
@@ -387,62 +830,134 @@ func (s *MCPServer) handleCallTool(request *types.MCPRequest) error {
 		return s.sendResponse(request.ID, types.MCPCallToolResult{
 			Content: []types.MCPContent{errorContent},
 			IsError: true,
+			Meta:    FailureMeta(err),
 		})
 	}
 
 	return s.sendResponse(request.ID, result)
 }
 
+// handleCancelled handles the notifications/cancelled notification by aborting the
+// referenced in-flight tool call. Unknown or already completed requests are ignored.
+func (s *MCPServer) handleCancelled(request *types.MCPRequest) error {
+	paramsBytes, err := json.Marshal(request.Params)
+	if err != nil {
+		return fmt.Errorf("invalid cancellation params: %w", err)
+	}
+
+	var params types.MCPCancelledParams
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		return fmt.Errorf("invalid cancellation params: %w", err)
+	}
+
+	s.inFlightMutex.Lock()
+	call, exists := s.inFlight[requestKey(params.RequestID)]
+	s.inFlightMutex.Unlock()
+
+	if !exists {
+		s.logger.Debug("Ignoring cancellation for unknown request", zap.Any("requestId", params.RequestID))
+		return nil
+	}
+
+	reason := params.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	s.logger.Debug("Cancelling tool call", zap.Any("requestId", params.RequestID), zap.String("reason", reason))
+	call.cancel(fmt.Errorf("cancelled by client: %s", reason))
+	return nil
+}
+
+// inFlightCall is a running request that the client can cancel
+type inFlightCall struct {
+	cancel context.CancelCauseFunc
+}
+
+// trackInFlight records the cancel function of a running request. A request reusing
+// the ID of a running one takes its place.
+func (s *MCPServer) trackInFlight(id interface{}, cancel context.CancelCauseFunc) *inFlightCall {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	call := &inFlightCall{cancel: cancel}
+	s.inFlight[requestKey(id)] = call
+	return call
+}
+
+// untrackInFlight forgets a completed request, unless a request with the same ID took
+// its place
+func (s *MCPServer) untrackInFlight(id interface{}, call *inFlightCall) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	if s.inFlight[requestKey(id)] == call {
+		delete(s.inFlight, requestKey(id))
+	}
+}
+
+// requestKey returns the JSON text of a JSON-RPC request ID, as decodeRequest keeps it
+func requestKey(id interface{}) string {
+	switch id := id.(type) {
+	case json.RawMessage:
+		return string(id)
+	case *batchedRequestID:
+		return string(id.raw)
+	}
+	encoded, _ := json.Marshal(id)
+	return string(encoded)
+}
+
 // handleListPrompts handles the prompts/list request
 func (s *MCPServer) handleListPrompts(request *types.MCPRequest) error {
 	s.logger.Debug("Handling prompts/list request")
-	// TODO: Implement prompts functionality
-	return s.sendResponse(request.ID, map[string]interface{}{"prompts": []interface{}{}})
+
+	prompts := s.promptRegistry.GetAllPrompts()
+	mcpPrompts := make([]types.MCPPrompt, len(prompts))
+	for i, prompt := range prompts {
+		mcpPrompts[i] = types.MCPPrompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   prompt.Arguments,
+		}
+	}
+
+	return s.sendResponse(request.ID, types.MCPListPromptsResult{
+		Prompts: mcpPrompts,
+	})
 }
 
 // handleGetPrompt handles the prompts/get request
 func (s *MCPServer) handleGetPrompt(request *types.MCPRequest) error {
 	s.logger.Debug("Handling prompts/get request")
-	// TODO: Implement prompts functionality
-	return s.sendErrorResponse(request.ID, -32601, "Prompt not found", nil)
-}
 
-// handleListResources handles the resources/list request
-func (s *MCPServer) handleListResources(request *types.MCPRequest) error {
-	s.logger.Debug("Handling resources/list request")
-	// TODO: Implement resources functionality
-	return s.sendResponse(request.ID, map[string]interface{}{"resources": []interface{}{}})
-}
+	var params types.MCPPromptGetParams
+	if err := decodeParams(request, &params); err != nil || params.Name == "" {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
 
-// handleReadResource handles the resources/read request
-func (s *MCPServer) handleReadResource(request *types.MCPRequest) error {
-	s.logger.Debug("Handling resources/read request")
-	// TODO: Implement resources functionality
-	return s.sendErrorResponse(request.ID, -32601, "Resource not found", nil)
-}
+	prompt := s.promptRegistry.GetPrompt(params.Name)
+	if prompt == nil {
+		return s.sendErrorResponse(request.ID, -32602, "Prompt not found", map[string]interface{}{"name": params.Name})
+	}
 
-// executeAPICall executes an API call using the HTTP client
-func (s *MCPServer) executeAPICall(tool *types.GeneratedTool, arguments map[string]interface{}) (types.MCPCallToolResult, error) {
-	// Execute the HTTP request
-	response, err := s.httpClient.ExecuteRequest(tool.Endpoint, arguments)
+	result, err := RenderPrompt(prompt, params.Arguments, s.readResource)
 	if err != nil {
-		return types.MCPCallToolResult{}, err
+		s.logger.Error("Failed to generate prompt content", zap.Error(err), zap.String("promptName", params.Name))
+		return s.sendErrorResponse(request.ID, -32603, "Failed to generate prompt", map[string]interface{}{"reason": err.Error()})
 	}
 
-	// Convert response to MCP content
-	content := types.MCPContent{
-		Type: "text",
-		Text: string(response.Body),
-	}
+	return s.sendResponse(request.ID, result)
+}
 
-	if response.Headers["Content-Type"] != "" {
-		content.MimeType = response.Headers["Content-Type"]
+// executeAPICall executes an API call using the HTTP client
+func (s *MCPServer) executeAPICall(ctx context.Context, tool *types.GeneratedTool, arguments map[string]interface{}) (types.MCPCallToolResult, error) {
+	// Execute the HTTP request, or mock it
+	response, err := s.executor.Execute(ctx, s.httpClient, tool, arguments)
+	if err != nil {
+		return types.MCPCallToolResult{}, err
 	}
 
-	return types.MCPCallToolResult{
-		Content: []types.MCPContent{content},
-		IsError: response.StatusCode >= 400,
-	}, nil
+	// Convert the response to MCP content, returning binary bodies as base64 and
+	// offloading or truncating large ones
+	return ResponseResult(response, s.shared.Blobs, s.config.ToolExecution), nil
 }
 
 // sendResponse sends a JSON-RPC response
@@ -453,7 +968,7 @@ func (s *MCPServer) sendResponse(id interface{}, result interface{}) error {
 		Result:  result,
 	}
 
-	return s.sendMessage(response)
+	return s.sendResponseMessage(response)
 }
 
 // sendErrorResponse sends a JSON-RPC error response
@@ -468,9 +983,28 @@ func (s *MCPServer) sendErrorResponse(id interface{}, code int, message string,
 		},
 	}
 
+	return s.sendResponseMessage(response)
+}
+
+// sendResponseMessage sends a JSON-RPC response, or adds it to the batch of its request
+func (s *MCPServer) sendResponseMessage(response types.MCPResponse) error {
+	if response.ID != nil && s.answerBatchedRequest(response.ID, &response) {
+		return nil
+	}
 	return s.sendMessage(response)
 }
 
+// sendNotification sends a JSON-RPC notification
+func (s *MCPServer) sendNotification(method string, params interface{}) error {
+	notification := types.MCPNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+
+	return s.sendMessage(notification)
+}
+
 // sendMessage sends a message to stdout
 func (s *MCPServer) sendMessage(message interface{}) error {
 	data, err := json.Marshal(message)
@@ -480,8 +1014,18 @@ func (s *MCPServer) sendMessage(message interface{}) error {
 
 	s.logger.Debug("Sending message", zap.String("message", string(data)))
 
+	return s.writeMessage(data)
+}
+
+// writeMessage writes an encoded message to stdout without logging it, so that
+// forwarded log notifications do not generate further log entries
+func (s *MCPServer) writeMessage(data []byte) error {
 	data = append(data, '\n')
 
+	// Notifications may be sent from background goroutines
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
 	if _, err := s.stdout.Write(data); err != nil {
 		return fmt.Errorf("failed to write message: %w", err)
 	}
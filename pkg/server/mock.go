@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Mocks reports whether calls to a tool return mock responses: either mocking is
+// enabled for all tools, or a document pattern matches the tool's document
+func Mocks(mock types.MockConfig, tool *types.GeneratedTool) bool {
+	if mock.Enabled {
+		return true
+	}
+	for _, pattern := range mock.Documents {
+		if swagger.MatchesDocument(pattern, tool.DocumentInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+// MockResponse builds the example response a mocked tool call returns
+func MockResponse(document *types.SwaggerDocument, tool *types.GeneratedTool) (*http.Response, error) {
+	statusCode, example := swagger.MockResponse(document, tool.Endpoint)
+
+	response := &http.Response{
+		StatusCode: statusCode,
+		Headers:    map[string]string{},
+		Source:     http.SourceMock,
+	}
+	if example == nil {
+		return response, nil
+	}
+
+	body, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mock response for %s: %w", tool.Name, err)
+	}
+	response.Headers["Content-Type"] = "application/json"
+	response.Body = body
+
+	return response, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// offloadPreviewSize bounds the start of an offloaded text response quoted in the
+// summary returned in its place
+const offloadPreviewSize = 1000
+
+// maxSummaryKeys bounds the keys of a JSON object named in the summary of an offloaded
+// response
+const maxSummaryKeys = 20
+
+// offloadContent keeps a response too large to return inline in the blob store and
+// returns the text content summarizing it in its place, with the URI it is kept
+// under. The URI is empty when the response is larger than the store.
+func offloadContent(body []byte, mediaType string, binary bool, blobs *BlobStore) (types.MCPContent, string) {
+	uri, expires, ok := blobs.Put(body, mediaType, !binary)
+
+	var summary strings.Builder
+	if ok {
+		fmt.Fprintf(&summary, "The response is %d bytes of %s, more than is returned inline", len(body), mediaType)
+	} else {
+		fmt.Fprintf(&summary, "The response is %d bytes of %s, more than can be returned or kept", len(body), mediaType)
+	}
+	if !binary {
+		if description := describeJSON(body); description != "" {
+			fmt.Fprintf(&summary, ": %s", description)
+		}
+	}
+	if ok {
+		fmt.Fprintf(&summary, ". Read it with resources/read from %s until %s.", uri, expires.UTC().Format(time.RFC3339))
+	} else {
+		summary.WriteString("; narrow the request to fetch less.")
+	}
+	if !binary {
+		summary.WriteString(" It starts with:\n")
+		summary.WriteString(preview(body))
+	}
+
+	return types.MCPContent{Type: "text", Text: summary.String()}, uri
+}
+
+// describeJSON describes the shape of a JSON body: the keys of an object, with the
+// lengths of the arrays they hold, or the length of an array. It is empty for bodies
+// that are not JSON objects or arrays.
+func describeJSON(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return ""
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, maxSummaryKeys+1)
+		for i, key := range keys {
+			if i == maxSummaryKeys {
+				parts = append(parts, fmt.Sprintf("and %d more", len(keys)-maxSummaryKeys))
+				break
+			}
+			if items, ok := typed[key].([]interface{}); ok {
+				parts = append(parts, fmt.Sprintf("%s (%d items)", key, len(items)))
+			} else {
+				parts = append(parts, key)
+			}
+		}
+		return fmt.Sprintf("a JSON object with the keys %s", strings.Join(parts, ", "))
+	case []interface{}:
+		return fmt.Sprintf("a JSON array of %d items", len(typed))
+	}
+	return ""
+}
+
+// preview returns the start of a text body, cut on a character boundary
+func preview(body []byte) string {
+	if len(body) <= offloadPreviewSize {
+		return string(body)
+	}
+	end := offloadPreviewSize
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+	return string(body[:end]) + "..."
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// pageItemFields are the names of the fields holding the items of a page, preferred
+// when a page object has several array fields
+var pageItemFields = []string{"items", "data", "results", "records", "features", "value", "entries", "content", "elements", "rows"}
+
+// fetchAllPages performs a tool call of a paginated endpoint and follows its pages, up
+// to the configured maximum. The items of all pages are merged into the last page, so
+// that its next link, if any, still points past the merged results. Responses that are
+// not JSON or hold no recognizable items are returned as they are.
+func (e *ToolExecutor) fetchAllPages(ctx context.Context, client *http.Client, tool *types.GeneratedTool, arguments map[string]interface{}) (*http.Response, error) {
+	response, err := e.execute(ctx, client, tool, arguments)
+	if err != nil || response.StatusCode >= 400 {
+		return response, err
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		return response, nil
+	}
+	items, field, ok := pageItems(body)
+	if !ok {
+		return response, nil
+	}
+
+	merged := items
+	visited := map[string]bool{canonicalArguments(arguments): true}
+	pages := 1
+	// The merged response reports the attempts and time of all pages
+	attempts, retries, duration := response.Attempts, response.Retries, response.Duration
+	for {
+		next, ok := nextPageArguments(tool.Endpoint, arguments, response, body, len(items))
+		if !ok || visited[canonicalArguments(next)] {
+			break
+		}
+		if pages >= e.pagination.MaxPages {
+			e.logger.Info("Stopped following pages at the configured maximum",
+				zap.String("toolName", tool.Name), zap.Int("maxPages", e.pagination.MaxPages))
+			break
+		}
+		visited[canonicalArguments(next)] = true
+
+		nextResponse, err := e.execute(ctx, client, tool, next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page %d of %s: %w", pages+1, tool.Name, err)
+		}
+		// A failing page fails the call rather than returning silently truncated results
+		if nextResponse.StatusCode >= 400 {
+			return nextResponse, nil
+		}
+
+		var nextBody interface{}
+		if err := json.Unmarshal(nextResponse.Body, &nextBody); err != nil {
+			break
+		}
+		nextItems, ok := fieldItems(nextBody, field)
+		if !ok || len(nextItems) == 0 {
+			break
+		}
+
+		merged = append(merged, nextItems...)
+		arguments, response, body, items = next, nextResponse, nextBody, nextItems
+		attempts += nextResponse.Attempts
+		retries += nextResponse.Retries
+		duration += nextResponse.Duration
+		pages++
+	}
+
+	if pages == 1 {
+		return response, nil
+	}
+	e.logger.Debug("Merged result pages", zap.String("toolName", tool.Name), zap.Int("pages", pages), zap.Int("items", len(merged)))
+
+	var mergedBody interface{} = merged
+	if field != "" {
+		object := make(map[string]interface{})
+		for name, value := range body.(map[string]interface{}) {
+			object[name] = value
+		}
+		object[field] = merged
+		mergedBody = object
+	}
+
+	encoded, err := json.Marshal(mergedBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged pages of %s: %w", tool.Name, err)
+	}
+
+	headers := make(map[string]string, len(response.Headers))
+	for name, value := range response.Headers {
+		headers[name] = value
+	}
+	headers["Content-Type"] = "application/json"
+
+	return &http.Response{
+		StatusCode: response.StatusCode,
+		Headers:    headers,
+		Body:       encoded,
+		URL:        response.URL,
+		Source:     response.Source,
+		Attempts:   attempts,
+		Retries:    retries,
+		Duration:   duration,
+	}, nil
+}
+
+// pageItems returns the items of a decoded page and the field holding them: the page
+// itself if it is an array, otherwise a preferred or its only array field
+func pageItems(body interface{}) ([]interface{}, string, bool) {
+	switch page := body.(type) {
+	case []interface{}:
+		return page, "", true
+	case map[string]interface{}:
+		for _, field := range pageItemFields {
+			for name, value := range page {
+				if items, ok := value.([]interface{}); ok && strings.EqualFold(name, field) {
+					return items, name, true
+				}
+			}
+		}
+
+		var items []interface{}
+		var field string
+		for name, value := range page {
+			if array, ok := value.([]interface{}); ok {
+				if field != "" {
+					return nil, "", false
+				}
+				items, field = array, name
+			}
+		}
+		return items, field, field != ""
+	}
+	return nil, "", false
+}
+
+// fieldItems returns the items a decoded page holds in a field, or the page itself
+// when field is empty
+func fieldItems(body interface{}, field string) ([]interface{}, bool) {
+	if field == "" {
+		items, ok := body.([]interface{})
+		return items, ok
+	}
+	page, _ := body.(map[string]interface{})
+	items, ok := page[field].([]interface{})
+	return items, ok
+}
+
+// nextPageArguments returns the arguments of the call fetching the page after the one
+// received for arguments, which held count items. Next links and cursors in the
+// response are followed; page and offset parameters are advanced otherwise, until a
+// page comes back empty or shorter than the requested page size.
+func nextPageArguments(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}, response *http.Response, body interface{}, count int) (map[string]interface{}, bool) {
+	pagination := endpoint.Pagination
+	next := make(map[string]interface{}, len(arguments)+1)
+	for name, value := range arguments {
+		next[name] = value
+	}
+
+	reference := linkHeaderNext(response.Headers["Link"])
+	if reference == "" {
+		reference = swagger.NextPageReference(body)
+	}
+	if reference != "" && applyNextLink(endpoint, next, reference) {
+		return next, true
+	}
+
+	switch pagination.Style {
+	case types.PaginationStyleCursor:
+		// A next link that could not be mapped onto the endpoint's parameters is no cursor
+		if link, err := url.Parse(reference); reference == "" || (err == nil && link.IsAbs()) {
+			return nil, false
+		}
+		next[pagination.Param] = reference
+		return next, true
+	case types.PaginationStylePage, types.PaginationStyleOffset:
+		if count == 0 {
+			return nil, false
+		}
+		if size, ok := intArgument(arguments[pagination.SizeParam]); ok && count < size {
+			return nil, false
+		}
+		if pagination.Style == types.PaginationStylePage {
+			page, ok := intArgument(arguments[pagination.Param])
+			if !ok {
+				page = firstPage(endpoint, pagination.Param)
+			}
+			next[pagination.Param] = page + 1
+		} else {
+			offset, _ := intArgument(arguments[pagination.Param])
+			next[pagination.Param] = offset + count
+		}
+		return next, true
+	}
+	return nil, false
+}
+
+// applyNextLink sets the query parameters of a next link URL that the endpoint
+// declares in arguments, and reports whether any argument changed
+func applyNextLink(endpoint *types.SwaggerEndpoint, arguments map[string]interface{}, reference string) bool {
+	link, err := url.Parse(reference)
+	if err != nil || link.RawQuery == "" {
+		return false
+	}
+
+	query := link.Query()
+	changed := false
+	for _, param := range endpoint.Parameters {
+		if param.In != "query" || !query.Has(param.Name) {
+			continue
+		}
+		value := query.Get(param.Name)
+		if current, exists := arguments[param.Name]; !exists || fmt.Sprintf("%v", current) != value {
+			arguments[param.Name] = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// linkHeaderNext returns the URL of the rel="next" link of a Link header, or ""
+func linkHeaderNext(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		start, end := strings.Index(link, "<"), strings.Index(link, ">")
+		if start < 0 || end < start {
+			continue
+		}
+		for _, param := range strings.Split(link[end+1:], ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(name, "rel") && containsFold(strings.Fields(strings.Trim(value, `"`)), "next") {
+				return link[start+1 : end]
+			}
+		}
+	}
+	return ""
+}
+
+// firstPage returns the number of the first page of an endpoint: the default or minimum
+// of its page parameter, or 1
+func firstPage(endpoint *types.SwaggerEndpoint, name string) int {
+	for _, param := range endpoint.Parameters {
+		if param.Name != name {
+			continue
+		}
+		schema, _ := param.Schema.(map[string]interface{})
+		for _, keyword := range []string{"default", "minimum"} {
+			if page, ok := intArgument(schema[keyword]); ok {
+				return page
+			}
+		}
+	}
+	return 1
+}
+
+// intArgument returns the integer value of a tool argument given as a number or string
+func intArgument(value interface{}) (int, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed), true
+	case int:
+		return typed, true
+	case int64:
+		return int(typed), true
+	case string:
+		number, err := strconv.Atoi(typed)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,31 +1,117 @@
 package server
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 
+	"swagger-docs-mcp/pkg/swagger"
 	"swagger-docs-mcp/pkg/types"
 )
 
 // PromptRegistry manages prompts
 type PromptRegistry struct {
-	prompts map[string]*types.GeneratedPrompt
-	mutex   sync.RWMutex
+	prompts   map[string]*types.GeneratedPrompt
+	policy    types.PromptConflictPolicy
+	conflicts []PromptConflict
+	mutex     sync.RWMutex
 }
 
-// NewPromptRegistry creates a new prompt registry
+// PromptConflict records a prompt whose name was already taken by a different prompt
+// from another document, and how the registry's conflict policy resolved it
+type PromptConflict struct {
+	Name             string `json:"name"`
+	Document         string `json:"document,omitempty"`
+	ExistingDocument string `json:"existingDocument,omitempty"`
+	Resolution       string `json:"resolution"`
+	RegisteredAs     string `json:"registeredAs,omitempty"`
+}
+
+// Prompt conflict resolutions
+const (
+	PromptConflictRejected = "rejected"
+	PromptConflictRenamed  = "renamed"
+	PromptConflictKept     = "kept-first"
+)
+
+// NewPromptRegistry creates a new prompt registry that rejects conflicting prompt names
 func NewPromptRegistry() *PromptRegistry {
+	return NewPromptRegistryWithPolicy(types.PromptConflictError)
+}
+
+// NewPromptRegistryWithPolicy creates a new prompt registry with the given conflict policy
+func NewPromptRegistryWithPolicy(policy types.PromptConflictPolicy) *PromptRegistry {
+	if policy == "" {
+		policy = types.PromptConflictError
+	}
 	return &PromptRegistry{
 		prompts: make(map[string]*types.GeneratedPrompt),
+		policy:  policy,
 	}
 }
 
-// RegisterPrompt registers a new prompt
+// RegisterPrompt registers a new prompt. A prompt from the same document as the
+// registered one replaces it, and an identical prompt from another document is
+// ignored; any other prompt with a taken name is resolved by the conflict policy.
 func (r *PromptRegistry) RegisterPrompt(prompt *types.GeneratedPrompt) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	
-	r.prompts[prompt.Name] = prompt
-	return nil
+
+	if prompt.Name == "" {
+		return fmt.Errorf("prompt name cannot be empty (document: %s)", promptDocument(prompt))
+	}
+
+	existing, exists := r.prompts[prompt.Name]
+	if !exists || promptDocument(existing) == promptDocument(prompt) {
+		r.prompts[prompt.Name] = prompt
+		return nil
+	}
+	if samePrompt(existing, prompt) {
+		return nil
+	}
+
+	conflict := PromptConflict{
+		Name:             prompt.Name,
+		Document:         promptDocument(prompt),
+		ExistingDocument: promptDocument(existing),
+	}
+
+	switch r.policy {
+	case types.PromptConflictKeepFirst:
+		conflict.Resolution = PromptConflictKept
+		r.conflicts = append(r.conflicts, conflict)
+		return nil
+
+	case types.PromptConflictSuffix:
+		suffix := promptDocumentSuffix(prompt)
+		name := prompt.Name + "-" + suffix
+		if other, taken := r.prompts[name]; suffix != "" && (!taken || promptDocument(other) == promptDocument(prompt)) {
+			renamed := *prompt
+			renamed.Name = name
+			r.prompts[name] = &renamed
+			conflict.Resolution = PromptConflictRenamed
+			conflict.RegisteredAs = name
+			r.conflicts = append(r.conflicts, conflict)
+			return nil
+		}
+	}
+
+	conflict.Resolution = PromptConflictRejected
+	r.conflicts = append(r.conflicts, conflict)
+	return fmt.Errorf("prompt with name '%s' already exists - conflict between:\n  New: %s\n  Existing: %s",
+		prompt.Name, conflict.Document, conflict.ExistingDocument)
+}
+
+// GetConflicts returns the prompt name conflicts seen since the registry was created
+// or last cleared, in the order they occurred
+func (r *PromptRegistry) GetConflicts() []PromptConflict {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	conflicts := make([]PromptConflict, len(r.conflicts))
+	copy(conflicts, r.conflicts)
+	return conflicts
 }
 
 // GetPrompt retrieves a prompt by name
@@ -76,6 +162,24 @@ func (r *PromptRegistry) Clear() {
 	defer r.mutex.Unlock()
 	
 	r.prompts = make(map[string]*types.GeneratedPrompt)
+	r.conflicts = nil
+}
+
+// ReplaceAll replaces the registered prompts with the given ones, as they are named,
+// and reports whether the prompts changed. The conflict policy does not apply, as
+// the prompts come from a registry that resolved their conflicts already.
+func (r *PromptRegistry) ReplaceAll(prompts []*types.GeneratedPrompt) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	replaced := make(map[string]*types.GeneratedPrompt, len(prompts))
+	for _, prompt := range prompts {
+		replaced[prompt.Name] = prompt
+	}
+	changed := !reflect.DeepEqual(r.prompts, replaced)
+	r.prompts = replaced
+	r.conflicts = nil
+	return changed
 }
 
 // HasPrompt checks if a prompt exists
@@ -100,4 +204,34 @@ func (r *PromptRegistry) GetPromptsByCategory(category types.WeatherPromptCatego
 	}
 	
 	return filtered
-}
\ No newline at end of file
+}
+
+// promptDocument returns the path of the document a prompt was generated from
+func promptDocument(prompt *types.GeneratedPrompt) string {
+	if prompt.Source == nil {
+		return ""
+	}
+	return prompt.Source.FilePath
+}
+
+// promptDocumentSuffix derives a prompt name suffix from the prompt's document name,
+// e.g. "currents-on-demand" for currents_on_demand.json
+func promptDocumentSuffix(prompt *types.GeneratedPrompt) string {
+	if prompt.Source == nil {
+		return ""
+	}
+	name := strings.ToLower(swagger.ResourceDocumentName(prompt.Source))
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name), "-")
+}
+
+// samePrompt reports whether two prompts would render and be listed identically
+func samePrompt(a, b *types.GeneratedPrompt) bool {
+	return a.Description == b.Description &&
+		a.Template == b.Template &&
+		reflect.DeepEqual(a.Arguments, b.Arguments)
+}
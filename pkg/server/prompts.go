@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// ReadResource reads the content of a resource of the shared state: the tool usage
+// and source status reports, a blob of a tool call, or a resource generated from a
+// loaded document, resolving resource template URIs. It returns nil if no resource
+// has the URI.
+func ReadResource(uri string, config *types.ResolvedConfig, shared *SharedState, generator *swagger.ResourceGenerator) (*types.MCPResourceContent, error) {
+	// The tool usage report is generated from the tool registry rather than a document
+	if uri == ToolStatsResourceURI && config.Resources.Enabled {
+		content, err := ToolStatsContent(shared.Tools)
+		if err != nil {
+			return nil, err
+		}
+		return &content, nil
+	}
+	if content, ok := shared.Blobs.Get(uri); ok {
+		return &content, nil
+	}
+	if uri == SourceStatusResourceURI && config.Resources.Enabled {
+		content, err := SourceStatusContent(shared.Sources)
+		if err != nil {
+			return nil, err
+		}
+		return &content, nil
+	}
+
+	resource, stored := shared.Documents.ResolveResource(uri, shared.Resources, generator)
+	if resource == nil {
+		return nil, nil
+	}
+
+	content, err := generator.GetResourceContent(resource, stored.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MCPResourceContent{
+		URI:      resource.URI,
+		MimeType: resource.MimeType,
+		Text:     content,
+	}, nil
+}
+
+// RenderPrompt renders a prompt with the given arguments. A prompt with a single
+// template is one user message; the messages of a multi-message prompt are rendered
+// in order, reading the resources they embed with read.
+func RenderPrompt(prompt *types.GeneratedPrompt, arguments map[string]interface{}, read func(string) (*types.MCPResourceContent, error)) (types.MCPPromptGetResult, error) {
+	result := types.MCPPromptGetResult{
+		Description: prompt.Description,
+	}
+
+	if len(prompt.Messages) == 0 {
+		result.Messages = []types.MCPPromptMessage{
+			{
+				Role: "user",
+				Content: types.MCPPromptContent{
+					Type: "text",
+					Text: RenderPromptTemplate(prompt.Template, arguments),
+				},
+			},
+		}
+		return result, nil
+	}
+
+	for _, message := range prompt.Messages {
+		role := message.Role
+		if role == "" {
+			role = "user"
+		}
+
+		if message.Resource == "" {
+			result.Messages = append(result.Messages, types.MCPPromptMessage{
+				Role: role,
+				Content: types.MCPPromptContent{
+					Type: "text",
+					Text: RenderPromptTemplate(message.Text, arguments),
+				},
+			})
+			continue
+		}
+
+		uri := RenderPromptTemplate(message.Resource, arguments)
+		content, err := read(uri)
+		if err != nil {
+			return result, fmt.Errorf("failed to read resource %s: %w", uri, err)
+		}
+		if content == nil {
+			return result, fmt.Errorf("resource not found: %s", uri)
+		}
+		result.Messages = append(result.Messages, types.MCPPromptMessage{
+			Role: role,
+			Content: types.MCPPromptContent{
+				Type:     "resource",
+				Resource: content,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// RenderPromptTemplate replaces the {{argument}} placeholders of a prompt template
+// with the values of the arguments
+func RenderPromptTemplate(template string, arguments map[string]interface{}) string {
+	result := template
+	for key, value := range arguments {
+		placeholder := fmt.Sprintf("{{%s}}", key)
+		if valueStr, ok := value.(string); ok {
+			result = strings.ReplaceAll(result, placeholder, valueStr)
+		} else {
+			result = strings.ReplaceAll(result, placeholder, fmt.Sprintf("%v", value))
+		}
+	}
+	return result
+}
+
+// registerDocumentPrompts registers the prompts generated from a document, if prompts
+// are enabled
+func (s *MCPServer) registerDocumentPrompts(info *types.SwaggerDocumentInfo, document *types.SwaggerDocument) {
+	if !s.config.Prompts.Enabled {
+		return
+	}
+
+	prompts, err := s.promptGenerator.GeneratePromptsFromDocument(document, info)
+	if err != nil {
+		s.logger.Error("Failed to generate prompts from document",
+			zap.Error(err),
+			zap.String("filePath", info.FilePath))
+		return
+	}
+	for _, prompt := range prompts {
+		if err := s.promptRegistry.RegisterPrompt(prompt); err != nil {
+			s.logger.Error("Failed to register prompt",
+				zap.Error(err),
+				zap.String("promptName", prompt.Name))
+		}
+	}
+}
+
+// registerTemplatePrompts registers the prompts loaded from the prompt template directory
+func (s *MCPServer) registerTemplatePrompts() {
+	for _, prompt := range s.promptGenerator.TemplatePrompts() {
+		if err := s.promptRegistry.RegisterPrompt(prompt); err != nil {
+			s.logger.Error("Failed to register prompt template",
+				zap.Error(err),
+				zap.String("promptName", prompt.Name))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// redactedArgument replaces the values of sensitive arguments
+const redactedArgument = "[REDACTED]"
+
+// defaultSensitiveFields are the argument name patterns always redacted from the
+// arguments of tool calls reported to others, as they commonly carry credentials
+var defaultSensitiveFields = []string{
+	"apikey", "api_key", "api-key", "authorization", "*password*", "*secret*", "*token*", "*credential*",
+}
+
+// RedactArguments returns a copy of the arguments of a tool call with the values of
+// sensitive fields replaced, at any depth. Fields are sensitive when their name
+// matches one of the default patterns or of sensitiveFields, regardless of case.
+func RedactArguments(arguments map[string]interface{}, sensitiveFields []string) map[string]interface{} {
+	return redactValue(arguments, sensitiveFields).(map[string]interface{})
+}
+
+// redactValue returns a copy of an argument value with the values of sensitive
+// fields nested in it replaced
+func redactValue(value interface{}, sensitiveFields []string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(typed))
+		for name, item := range typed {
+			if isSensitiveField(name, sensitiveFields) {
+				redacted[name] = redactedArgument
+			} else {
+				redacted[name] = redactValue(item, sensitiveFields)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(typed))
+		for i, item := range typed {
+			redacted[i] = redactValue(item, sensitiveFields)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// isSensitiveField reports whether an argument name matches one of the default or
+// configured sensitive field patterns, regardless of case
+func isSensitiveField(name string, sensitiveFields []string) bool {
+	lower := strings.ToLower(name)
+	for _, patterns := range [][]string{defaultSensitiveFields, sensitiveFields} {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(strings.ToLower(pattern), lower); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RedactURL returns a URL with the credentials in its user info replaced, so it can
+// be shown without disclosing them. URLs that cannot be parsed are returned as is.
+func RedactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+	parsed.User = url.User("REDACTED")
+	return parsed.String()
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds connecting to Redis and each command
+const redisTimeout = 5 * time.Second
+
+// newRedisClient creates a client of the Redis server of a redis:// or rediss:// URL.
+// Commands are not retried: a command whose reply was lost may still have been
+// applied, and the shared catalog syncs again at its next interval anyway.
+func newRedisClient(rawURL string) (*redis.Client, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	// A user info without a password is the password, as for redis-cli
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.User != nil {
+		if _, ok := parsed.User.Password(); !ok {
+			options.Username, options.Password = "", parsed.User.Username()
+		}
+	}
+
+	options.DialTimeout = redisTimeout
+	options.ReadTimeout = redisTimeout
+	options.WriteTimeout = redisTimeout
+	options.MaxRetries = -1
+	return redis.NewClient(options), nil
+}
@@ -0,0 +1,304 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// DocumentRefresher periodically re-fetches remote swagger documents and applies
+// the resulting tool changes to a tool registry
+type DocumentRefresher struct {
+	config     *types.ResolvedConfig
+	logger     *utils.Logger
+	scanner    *swagger.Scanner
+	parser     *swagger.Parser
+	generator  *swagger.ToolGenerator
+	registry   *ToolRegistry
+	onChange   func(ToolChanges)
+	onDocument func(*types.SwaggerDocumentInfo, *types.SwaggerDocument)
+	onEvict    func(string)
+	leading    func() bool
+	sources    *SourceTracker
+
+	// failingSince records when each remote document first failed to refresh
+	failingSince map[string]time.Time
+	staleMutex   sync.Mutex
+}
+
+// StaleDocument is a remote document whose last refreshes failed. Its tools stay
+// registered until the stale grace period has passed.
+type StaleDocument struct {
+	Path         string     `json:"path"`
+	FailingSince time.Time  `json:"failingSince"`
+	EvictAt      *time.Time `json:"evictAt,omitempty"`
+}
+
+// NewDocumentRefresher creates a new document refresher
+func NewDocumentRefresher(config *types.ResolvedConfig, logger *utils.Logger, registry *ToolRegistry) *DocumentRefresher {
+	generator := swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration)
+	generator.SetAuth(&config.Auth)
+	hosts := http.NewHostPolicy(config)
+	scanner := swagger.NewScanner(logger)
+	scanner.SetHostPolicy(hosts)
+	parser := swagger.NewParserWithConfig(logger, &config.SwaggerProcessing)
+	parser.SetHostPolicy(hosts)
+
+	return &DocumentRefresher{
+		config:    config,
+		logger:    logger.Child("refresher"),
+		scanner:   scanner,
+		parser:    parser,
+		generator: generator,
+		registry:  registry,
+
+		failingSince: make(map[string]time.Time),
+	}
+}
+
+// OnChange sets the callback invoked after a refresh changes the registered tools
+func (r *DocumentRefresher) OnChange(fn func(ToolChanges)) {
+	r.onChange = fn
+}
+
+// OnDocument sets the callback invoked with each successfully refreshed document,
+// whether or not its tools changed
+func (r *DocumentRefresher) OnDocument(fn func(*types.SwaggerDocumentInfo, *types.SwaggerDocument)) {
+	r.onDocument = fn
+}
+
+// SetSourceTracker sets the tracker the refreshes of the remote sources are recorded to
+func (r *DocumentRefresher) SetSourceTracker(sources *SourceTracker) {
+	r.sources = sources
+}
+
+// OnEvict sets the callback invoked with the path of each document evicted for staleness
+func (r *DocumentRefresher) OnEvict(fn func(string)) {
+	r.onEvict = fn
+}
+
+// SetLeaderCheck sets the check of whether this replica leads the refreshes of a
+// deployment. Periodic refreshes are skipped while it does not.
+func (r *DocumentRefresher) SetLeaderCheck(leading func() bool) {
+	r.leading = leading
+}
+
+// StaleDocuments returns the remote documents that are currently failing to refresh,
+// ordered by path
+func (r *DocumentRefresher) StaleDocuments() []StaleDocument {
+	r.staleMutex.Lock()
+	defer r.staleMutex.Unlock()
+
+	stale := make([]StaleDocument, 0, len(r.failingSince))
+	for path, since := range r.failingSince {
+		document := StaleDocument{Path: path, FailingSince: since}
+		if gracePeriod := r.config.SwaggerProcessing.StaleGracePeriod; gracePeriod > 0 {
+			evictAt := since.Add(gracePeriod)
+			document.EvictAt = &evictAt
+		}
+		stale = append(stale, document)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Path < stale[j].Path
+	})
+	return stale
+}
+
+// Enabled reports whether periodic refresh is configured
+func (r *DocumentRefresher) Enabled() bool {
+	return r.config.SwaggerProcessing.RefreshInterval > 0 && len(r.config.SwaggerURLs) > 0
+}
+
+// Run refreshes remote documents on the configured interval until the context is cancelled
+func (r *DocumentRefresher) Run(ctx context.Context) {
+	if !r.Enabled() {
+		return
+	}
+
+	interval := r.config.SwaggerProcessing.RefreshInterval
+	r.logger.Info("Starting periodic swagger refresh",
+		zap.Duration("interval", interval),
+		zap.Int("urls", len(r.config.SwaggerURLs)))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Debug("Stopping periodic swagger refresh")
+			return
+		case <-ticker.C:
+			if r.leading != nil && !r.leading() {
+				r.logger.Debug("Skipping swagger refresh, another replica leads the refreshes")
+				continue
+			}
+			if _, err := r.Refresh(); err != nil {
+				r.logger.Error("Failed to refresh swagger documents", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Refresh re-fetches all remote documents once and applies the changes to the registry.
+// Documents that fail to fetch or parse keep their previously registered tools until
+// they have been failing for longer than the stale grace period.
+func (r *DocumentRefresher) Refresh() (ToolChanges, error) {
+	scanResult, err := r.scanner.ScanPathsAndURLs(nil, r.config.SwaggerURLs, nil)
+	if err != nil {
+		return ToolChanges{}, fmt.Errorf("failed to scan swagger URLs: %w", err)
+	}
+	r.sources.RecordScan(scanResult)
+
+	documents := scanResult.Documents
+
+	if len(r.config.PackageIDs) > 0 {
+		documents = r.scanner.FilterDocumentsByPackageIDs(documents, r.config.PackageIDs)
+	}
+	if r.config.TWCFilters != nil {
+		documents = r.scanner.FilterDocumentsByTWCFilters(documents, r.config.TWCFilters)
+	}
+	if len(r.config.DynamicFilters) > 0 {
+		documents = r.scanner.FilterDocumentsByDynamicFilters(documents, r.config.DynamicFilters)
+	}
+
+	toolsByDocument := make(map[string][]*types.GeneratedTool)
+	for i := range documents {
+		docInfo := documents[i]
+
+		parsedDoc, err := r.parser.ParseDocumentWithContent(&docInfo)
+		if err != nil {
+			r.logger.Warn("Failed to parse refreshed document, keeping previous tools",
+				zap.Error(err),
+				zap.String("url", docInfo.FilePath))
+			r.sources.RecordDocumentError(docInfo.FilePath, err)
+			continue
+		}
+
+		tools, err := r.generator.GenerateToolsFromDocument(parsedDoc, &docInfo)
+		if err != nil {
+			r.logger.Warn("Failed to generate tools from refreshed document, keeping previous tools",
+				zap.Error(err),
+				zap.String("url", docInfo.FilePath))
+			r.sources.RecordDocumentError(docInfo.FilePath, err)
+			continue
+		}
+
+		toolsByDocument[docInfo.FilePath] = tools
+		r.sources.RecordDocumentLoaded(docInfo.FilePath)
+
+		if r.onDocument != nil {
+			r.onDocument(&docInfo, parsedDoc)
+		}
+	}
+
+	evicted := r.trackStaleDocuments(toolsByDocument)
+	for _, path := range evicted {
+		// Replacing a document with no tools removes the tools it registered
+		toolsByDocument[path] = nil
+	}
+
+	// The limit is applied by priority here, so the registry must not cap the
+	// tools again in the order it happens to visit the documents
+	dropped := limitDocumentTools(r.registry, toolsByDocument, r.generator, r.config)
+	changes := r.registry.ReplaceDocuments(toolsByDocument, 0)
+	changes.Evicted = evicted
+
+	for _, path := range evicted {
+		r.logger.Warn("Evicted tools of stale remote document",
+			zap.String("url", path),
+			zap.Duration("gracePeriod", r.config.SwaggerProcessing.StaleGracePeriod))
+		if r.onEvict != nil {
+			r.onEvict(path)
+		}
+	}
+
+	for _, name := range changes.Conflicts {
+		r.logger.Warn("Skipping refreshed tool with conflicting name", zap.String("toolName", name))
+	}
+
+	if dropped > 0 {
+		r.logger.Warn("Reached maximum tool limit, dropping the tools of the lowest priority",
+			zap.Int("maxTools", r.config.Server.MaxTools),
+			zap.Int("toolsDropped", dropped))
+	}
+
+	if changes.IsEmpty() && len(changes.Evicted) == 0 {
+		r.logger.Debug("Swagger refresh complete, no tool changes",
+			zap.Int("documents", len(toolsByDocument)))
+		return changes, nil
+	}
+
+	r.logger.Info("Swagger refresh updated tools",
+		zap.Int("added", len(changes.Added)),
+		zap.Int("removed", len(changes.Removed)),
+		zap.Int("updated", len(changes.Updated)),
+		zap.Int("toolsRegistered", r.registry.GetToolCount()))
+
+	if r.onChange != nil {
+		r.onChange(changes)
+	}
+
+	return changes, nil
+}
+
+// trackStaleDocuments updates the failure state of the remote documents that have
+// registered tools, given the documents refreshed this round, and returns the
+// documents that have now been failing for longer than the grace period
+func (r *DocumentRefresher) trackStaleDocuments(refreshed map[string][]*types.GeneratedTool) []string {
+	r.staleMutex.Lock()
+	defer r.staleMutex.Unlock()
+
+	now := time.Now().UTC()
+
+	for path := range refreshed {
+		if since, failing := r.failingSince[path]; failing {
+			r.logger.Info("Stale remote document refreshed again",
+				zap.String("url", path),
+				zap.Duration("failedFor", now.Sub(since)))
+			delete(r.failingSince, path)
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, path := range r.registry.RemoteDocumentPaths() {
+		registered[path] = true
+		if _, ok := refreshed[path]; ok {
+			continue
+		}
+		if _, failing := r.failingSince[path]; !failing {
+			r.logger.Warn("Remote document failed to refresh, marking its tools stale", zap.String("url", path))
+			r.failingSince[path] = now
+		}
+	}
+
+	// Documents whose tools are gone for other reasons are no longer tracked
+	for path := range r.failingSince {
+		if !registered[path] {
+			delete(r.failingSince, path)
+		}
+	}
+
+	gracePeriod := r.config.SwaggerProcessing.StaleGracePeriod
+	if gracePeriod <= 0 {
+		return nil
+	}
+
+	var evicted []string
+	for path, since := range r.failingSince {
+		if now.Sub(since) >= gracePeriod {
+			evicted = append(evicted, path)
+			delete(r.failingSince, path)
+		}
+	}
+	sort.Strings(evicted)
+	return evicted
+}
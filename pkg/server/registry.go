@@ -1,7 +1,12 @@
 package server
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"swagger-docs-mcp/pkg/types"
@@ -11,12 +16,52 @@ import (
 type ToolRegistry struct {
 	tools map[string]*types.GeneratedTool
 	mutex sync.RWMutex
+	// schemaLoader gives tools restored without their input schemas a way to
+	// generate them
+	schemaLoader func(*types.GeneratedTool)
+
+	usage      map[string]*toolUsage
+	usageStore *UsageStore
+	usageMutex sync.Mutex
+}
+
+// ToolChanges describes the tools added, removed, and updated by a registry update.
+// Evicted lists documents whose tools were dropped after failing to refresh.
+type ToolChanges struct {
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Updated   []string `json:"updated,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Evicted   []string `json:"evicted,omitempty"`
+}
+
+// IsEmpty reports whether the update changed the set of available tools
+func (c ToolChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0
 }
 
 // NewToolRegistry creates a new tool registry
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
 		tools: make(map[string]*types.GeneratedTool),
+		usage: make(map[string]*toolUsage),
+	}
+}
+
+// SetSchemaLoader sets the function that gives tools registered without their input
+// schema, as restored from a registry snapshot or the shared catalog, a way to
+// generate it, e.g. ToolGenerator.AttachInputSchema
+func (r *ToolRegistry) SetSchemaLoader(loader func(*types.GeneratedTool)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.schemaLoader = loader
+}
+
+// attachSchema lets the schema loader attach the input schema of a tool that has none
+func (r *ToolRegistry) attachSchema(tool *types.GeneratedTool) {
+	if r.schemaLoader != nil && tool.InputSchema == nil && tool.LoadInputSchema == nil {
+		r.schemaLoader(tool)
 	}
 }
 
@@ -37,6 +82,7 @@ func (r *ToolRegistry) RegisterTool(tool *types.GeneratedTool) error {
 			existing.Endpoint.Method, existing.Endpoint.Path, existing.DocumentInfo.Title)
 	}
 
+	r.attachSchema(tool)
 	r.tools[tool.Name] = tool
 	return nil
 }
@@ -49,7 +95,7 @@ func (r *ToolRegistry) GetTool(name string) *types.GeneratedTool {
 	return r.tools[name]
 }
 
-// GetAllTools returns all registered tools
+// GetAllTools returns all registered tools ordered by name
 func (r *ToolRegistry) GetAllTools() []*types.GeneratedTool {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -58,6 +104,9 @@ func (r *ToolRegistry) GetAllTools() []*types.GeneratedTool {
 	for _, tool := range r.tools {
 		tools = append(tools, tool)
 	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
 
 	return tools
 }
@@ -83,6 +132,25 @@ func (r *ToolRegistry) GetToolCount() int {
 	return len(r.tools)
 }
 
+// RemoteDocumentPaths returns the sorted URLs of the remote documents that have tools
+// registered
+func (r *ToolRegistry) RemoteDocumentPaths() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, tool := range r.tools {
+		if tool.DocumentInfo == nil || !tool.DocumentInfo.IsRemote || seen[tool.DocumentInfo.FilePath] {
+			continue
+		}
+		seen[tool.DocumentInfo.FilePath] = true
+		paths = append(paths, tool.DocumentInfo.FilePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 // HasTool checks if a tool with the given name exists
 func (r *ToolRegistry) HasTool(name string) bool {
 	r.mutex.RLock()
@@ -162,6 +230,45 @@ func (r *ToolRegistry) GetToolsByPackageIDs(packageIDs []string) []*types.Genera
 	return filtered
 }
 
+// GetToolsByMetadata returns tools whose metadata key matches the given value.
+// Array values match when any element equals the value; an empty value matches
+// any tool that has the key.
+func (r *ToolRegistry) GetToolsByMetadata(key, value string) []*types.GeneratedTool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var filtered []*types.GeneratedTool
+	for _, tool := range r.tools {
+		if MetadataMatches(tool, key, value) {
+			filtered = append(filtered, tool)
+		}
+	}
+
+	return filtered
+}
+
+// MetadataMatches reports whether a tool's metadata key matches the given value
+func MetadataMatches(tool *types.GeneratedTool, key, value string) bool {
+	metadataValue, exists := tool.Metadata[key]
+	if !exists {
+		return false
+	}
+	if value == "" {
+		return true
+	}
+
+	if values, ok := metadataValue.([]interface{}); ok {
+		for _, item := range values {
+			if strings.EqualFold(fmt.Sprintf("%v", item), value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return strings.EqualFold(fmt.Sprintf("%v", metadataValue), value)
+}
+
 // GetStatistics returns registry statistics
 func (r *ToolRegistry) GetStatistics() map[string]interface{} {
 	r.mutex.RLock()
@@ -191,5 +298,143 @@ func (r *ToolRegistry) GetStatistics() map[string]interface{} {
 	}
 	stats["toolsByDocument"] = documentCounts
 
+	// Summarize usage of the registered tools
+	r.usageMutex.Lock()
+	usedTools, totalCalls, totalErrors := 0, 0, 0
+	for name, usage := range r.usage {
+		if _, registered := r.tools[name]; registered {
+			usedTools++
+		}
+		totalCalls += usage.calls
+		totalErrors += usage.errors
+	}
+	r.usageMutex.Unlock()
+	stats["usedTools"] = usedTools
+	stats["totalCalls"] = totalCalls
+	stats["totalErrors"] = totalErrors
+
 	return stats
 }
+
+// ReplaceDocuments atomically replaces the tools generated from each of the given
+// documents (keyed by document file path or URL) and returns the resulting changes.
+// Tools whose names collide with a tool from another document are skipped and
+// reported as conflicts. A maxTools value greater than zero caps the registry size.
+func (r *ToolRegistry) ReplaceDocuments(toolsByDocument map[string][]*types.GeneratedTool, maxTools int) ToolChanges {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var changes ToolChanges
+
+	for documentPath, tools := range toolsByDocument {
+		// Collect the tools currently registered for this document
+		existing := make(map[string]*types.GeneratedTool)
+		for name, tool := range r.tools {
+			if tool.DocumentInfo != nil && tool.DocumentInfo.FilePath == documentPath {
+				existing[name] = tool
+			}
+		}
+
+		incoming := make(map[string]*types.GeneratedTool, len(tools))
+		for _, tool := range tools {
+			if other, exists := r.tools[tool.Name]; exists {
+				if _, own := existing[tool.Name]; !own && other.DocumentInfo != nil {
+					changes.Conflicts = append(changes.Conflicts, tool.Name)
+					continue
+				}
+			}
+			incoming[tool.Name] = tool
+		}
+
+		for name := range existing {
+			if _, keep := incoming[name]; !keep {
+				delete(r.tools, name)
+				changes.Removed = append(changes.Removed, name)
+			}
+		}
+
+		for name, tool := range incoming {
+			previous, exists := existing[name]
+			if !exists && maxTools > 0 && len(r.tools) >= maxTools {
+				continue
+			}
+			r.tools[name] = tool
+			if !exists {
+				changes.Added = append(changes.Added, name)
+			} else if toolChanged(previous, tool) {
+				changes.Updated = append(changes.Updated, name)
+			}
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Updated)
+	sort.Strings(changes.Conflicts)
+
+	return changes
+}
+
+// ReplaceAll swaps every registered tool for a new set, such as the result of a full
+// rescan, and returns the resulting changes
+func (r *ToolRegistry) ReplaceAll(tools []*types.GeneratedTool) ToolChanges {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var changes ToolChanges
+
+	incoming := make(map[string]*types.GeneratedTool, len(tools))
+	for _, tool := range tools {
+		r.attachSchema(tool)
+		incoming[tool.Name] = tool
+		if previous, exists := r.tools[tool.Name]; !exists {
+			changes.Added = append(changes.Added, tool.Name)
+		} else if toolChanged(previous, tool) {
+			changes.Updated = append(changes.Updated, tool.Name)
+		}
+	}
+	for name := range r.tools {
+		if _, keep := incoming[name]; !keep {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+	r.tools = incoming
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Updated)
+
+	return changes
+}
+
+// toolChanged reports whether a regenerated tool differs from its previous version
+func toolChanged(previous, current *types.GeneratedTool) bool {
+	if previous.Description != current.Description {
+		return true
+	}
+	if previous.Endpoint != nil && current.Endpoint != nil {
+		if previous.Endpoint.Method != current.Endpoint.Method || previous.Endpoint.Path != current.Endpoint.Path {
+			return true
+		}
+	}
+	if !sameJSON(previous.Metadata, current.Metadata) {
+		return true
+	}
+	// Lazy schemas are compared by the hash of their source instead of generated
+	if previous.SchemaHash != "" && current.SchemaHash != "" {
+		return previous.SchemaHash != current.SchemaHash
+	}
+	return !sameJSON(previous.Schema(), current.Schema())
+}
+
+// sameJSON reports whether two values have the same JSON encoding. Unlike
+// reflect.DeepEqual it treats a tool decoded from a snapshot ([]interface{},
+// float64) as equal to the freshly generated one ([]string, int).
+func sameJSON(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(encodedA, encodedB)
+}
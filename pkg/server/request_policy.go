@@ -0,0 +1,102 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Operation extensions overriding the timeout, retries, and hedge delay of a tool's
+// requests
+const (
+	TimeoutExtension    = "x-mcp-timeout"
+	RetriesExtension    = "x-mcp-retries"
+	HedgeDelayExtension = "x-mcp-hedge-delay"
+)
+
+// requestPolicy returns the timeout, retries, and hedge delay of the requests of a
+// tool: those configured for the tool, falling back to the x-mcp-timeout,
+// x-mcp-retries, and x-mcp-hedge-delay extensions of its operation. Requests are authenticated with the credential
+// configured for the tool's document, if any.
+func (e *ToolExecutor) requestPolicy(tool *types.GeneratedTool) http.RequestPolicy {
+	policy := http.RequestPolicy{
+		Credential: documentCredential(e.credentials, tool.DocumentInfo),
+	}
+	if tool.Endpoint != nil {
+		if value, exists := tool.Endpoint.Extensions[TimeoutExtension]; exists {
+			if timeout, ok := extensionDuration(value); ok {
+				policy.Timeout = timeout
+			} else {
+				e.logger.Warn("Ignoring invalid timeout extension", zap.String("toolName", tool.Name), zap.Any(TimeoutExtension, value))
+			}
+		}
+		if value, exists := tool.Endpoint.Extensions[RetriesExtension]; exists {
+			if retries, ok := intArgument(value); ok && retries >= 0 {
+				policy.Retries = &retries
+			} else {
+				e.logger.Warn("Ignoring invalid retries extension", zap.String("toolName", tool.Name), zap.Any(RetriesExtension, value))
+			}
+		}
+		if value, exists := tool.Endpoint.Extensions[HedgeDelayExtension]; exists {
+			if delay, ok := extensionDuration(value); ok {
+				policy.HedgeDelay = delay
+			} else {
+				e.logger.Warn("Ignoring invalid hedge delay extension", zap.String("toolName", tool.Name), zap.Any(HedgeDelayExtension, value))
+			}
+		}
+	}
+
+	if override, exists := e.overrides[tool.Name]; exists {
+		if override.Timeout > 0 {
+			policy.Timeout = override.Timeout
+		}
+		if override.Retries != nil {
+			policy.Retries = override.Retries
+		}
+		if override.HedgeDelay > 0 {
+			policy.HedgeDelay = override.HedgeDelay
+		}
+	}
+	return policy
+}
+
+// extensionDuration parses a timeout or hedge delay extension: a duration such as
+// "120s", or a number of seconds
+func extensionDuration(value interface{}) (time.Duration, bool) {
+	switch typed := value.(type) {
+	case string:
+		duration, err := time.ParseDuration(typed)
+		return duration, err == nil && duration > 0
+	case float64:
+		return time.Duration(typed * float64(time.Second)), typed > 0
+	case int:
+		return time.Duration(typed) * time.Second, typed > 0
+	}
+	return 0, false
+}
+
+// documentCredential returns the credential keyed by a document: by its path, file
+// name, or file name without the extension, or by the first pattern in key order that
+// matches one of these
+func documentCredential(credentials map[string]types.CredentialConfig, info *types.SwaggerDocumentInfo) *types.CredentialConfig {
+	if info == nil || len(credentials) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(credentials))
+	for key := range credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if swagger.MatchesDocument(key, info) {
+			credential := credentials[key]
+			return &credential
+		}
+	}
+	return nil
+}
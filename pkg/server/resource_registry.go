@@ -1,6 +1,7 @@
 package server
 
 import (
+	"sort"
 	"sync"
 
 	"swagger-docs-mcp/pkg/types"
@@ -47,15 +48,19 @@ func (r *ResourceRegistry) GetResourceByURI(uri string) *types.GeneratedResource
 	return r.uriIndex[uri]
 }
 
-// GetAllResources returns all registered resources
+// GetAllResources returns all registered resources ordered by URI. Resources are listed
+// by URI, as documents with the same title have resources with the same names.
 func (r *ResourceRegistry) GetAllResources() []*types.GeneratedResource {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
-	resources := make([]*types.GeneratedResource, 0, len(r.resources))
-	for _, resource := range r.resources {
+	resources := make([]*types.GeneratedResource, 0, len(r.uriIndex))
+	for _, resource := range r.uriIndex {
 		resources = append(resources, resource)
 	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].URI < resources[j].URI
+	})
 	
 	return resources
 }
@@ -65,7 +70,7 @@ func (r *ResourceRegistry) GetResourceCount() int {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
-	return len(r.resources)
+	return len(r.uriIndex)
 }
 
 // RemoveResource removes a resource by name
@@ -129,7 +134,7 @@ func (r *ResourceRegistry) GetResourcesByCategory(category types.ResourceCategor
 	defer r.mutex.RUnlock()
 	
 	var filtered []*types.GeneratedResource
-	for _, resource := range r.resources {
+	for _, resource := range r.uriIndex {
 		if resource.Category == category {
 			filtered = append(filtered, resource)
 		}
@@ -151,4 +156,62 @@ func (r *ResourceRegistry) GetResourcesByMimeType(mimeType string) []*types.Gene
 	}
 	
 	return filtered
-}
\ No newline at end of file
+}
+
+// ReplaceDocumentResources swaps the resources generated from one document for a
+// regenerated set and reports whether the set of URIs changed.
+func (r *ResourceRegistry) ReplaceDocumentResources(documentPath string, resources []*types.GeneratedResource) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var previous []string
+	for uri, resource := range r.uriIndex {
+		if resource.Source != nil && resource.Source.FilePath == documentPath {
+			previous = append(previous, uri)
+			delete(r.uriIndex, uri)
+			delete(r.resources, resource.Name)
+		}
+	}
+
+	listChanged := len(previous) != len(resources)
+	for _, resource := range resources {
+		if !containsURI(previous, resource.URI) {
+			listChanged = true
+		}
+		r.resources[resource.Name] = resource
+		r.uriIndex[resource.URI] = resource
+	}
+
+	return listChanged
+}
+
+// ReplaceAll replaces the registered resources with the given ones and reports
+// whether the set of URIs changed
+func (r *ResourceRegistry) ReplaceAll(resources []*types.GeneratedResource) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	listChanged := len(r.uriIndex) != len(resources)
+	r.resources = make(map[string]*types.GeneratedResource, len(resources))
+	uriIndex := make(map[string]*types.GeneratedResource, len(resources))
+	for _, resource := range resources {
+		if _, exists := r.uriIndex[resource.URI]; !exists {
+			listChanged = true
+		}
+		r.resources[resource.Name] = resource
+		uriIndex[resource.URI] = resource
+	}
+	r.uriIndex = uriIndex
+
+	return listChanged
+}
+
+// containsURI reports whether a URI is in the list
+func containsURI(uris []string, uri string) bool {
+	for _, candidate := range uris {
+		if candidate == uri {
+			return true
+		}
+	}
+	return false
+}
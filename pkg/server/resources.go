@@ -0,0 +1,249 @@
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// resourceNotFoundCode is the MCP error code for an unknown resource URI
+const resourceNotFoundCode = -32002
+
+// handleListResources handles the resources/list request
+func (s *MCPServer) handleListResources(request *types.MCPRequest) error {
+	s.logger.Debug("Handling resources/list request")
+
+	resources := s.resourceRegistry.GetAllResources()
+	mcpResources := make([]types.MCPResource, 0, len(resources)+1)
+	for _, resource := range resources {
+		mcpResources = append(mcpResources, types.MCPResource{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MimeType:    resource.MimeType,
+		})
+	}
+	if s.config.Resources.Enabled {
+		mcpResources = append(mcpResources, ToolStatsResource(), SourceStatusResource())
+	}
+
+	return s.sendResponse(request.ID, types.MCPListResourcesResult{
+		Resources: mcpResources,
+	})
+}
+
+// handleReadResource handles the resources/read request
+func (s *MCPServer) handleReadResource(request *types.MCPRequest) error {
+	s.logger.Debug("Handling resources/read request")
+
+	var params types.MCPReadResourceParams
+	if err := decodeParams(request, &params); err != nil {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	content, err := s.readResource(params.URI)
+	if err != nil {
+		return s.sendErrorResponse(request.ID, -32603, "Failed to read resource", map[string]interface{}{"reason": err.Error()})
+	}
+	if content == nil {
+		return s.sendErrorResponse(request.ID, resourceNotFoundCode, "Resource not found", map[string]interface{}{"uri": params.URI})
+	}
+
+	return s.sendResponse(request.ID, types.MCPReadResourceResult{
+		Contents: []types.MCPResourceContent{*content},
+	})
+}
+
+// readResource reads the content of a resource, or returns nil if no resource has the URI
+func (s *MCPServer) readResource(uri string) (*types.MCPResourceContent, error) {
+	content, err := ReadResource(uri, s.config, s.shared, s.resourceGenerator)
+	if err != nil {
+		s.logger.Error("Failed to generate resource content", zap.Error(err), zap.String("uri", uri))
+	}
+	return content, err
+}
+
+// handleListResourceTemplates handles the resources/templates/list request
+func (s *MCPServer) handleListResourceTemplates(request *types.MCPRequest) error {
+	s.logger.Debug("Handling resources/templates/list request")
+
+	templates := append(s.resourceGenerator.ResourceTemplates(), BlobResourceTemplate())
+
+	return s.sendResponse(request.ID, types.MCPListResourceTemplatesResult{
+		ResourceTemplates: templates,
+	})
+}
+
+// handleSubscribe handles the resources/subscribe request. Subscribed resources are
+// announced with notifications/resources/updated whenever their document changes.
+func (s *MCPServer) handleSubscribe(request *types.MCPRequest) error {
+	var params types.MCPSubscribeParams
+	if err := decodeParams(request, &params); err != nil || params.URI == "" {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	if resource, _ := s.documents.ResolveResource(params.URI, s.resourceRegistry, s.resourceGenerator); resource == nil {
+		return s.sendErrorResponse(request.ID, resourceNotFoundCode, "Resource not found", map[string]interface{}{"uri": params.URI})
+	}
+
+	s.subscriptionsMutex.Lock()
+	s.subscriptions[params.URI] = true
+	s.subscriptionsMutex.Unlock()
+
+	s.logger.Debug("Subscribed to resource", zap.String("uri", params.URI))
+	return s.sendResponse(request.ID, map[string]interface{}{})
+}
+
+// handleUnsubscribe handles the resources/unsubscribe request
+func (s *MCPServer) handleUnsubscribe(request *types.MCPRequest) error {
+	var params types.MCPSubscribeParams
+	if err := decodeParams(request, &params); err != nil || params.URI == "" {
+		return s.sendErrorResponse(request.ID, -32602, "Invalid params", nil)
+	}
+
+	s.subscriptionsMutex.Lock()
+	delete(s.subscriptions, params.URI)
+	s.subscriptionsMutex.Unlock()
+
+	s.logger.Debug("Unsubscribed from resource", zap.String("uri", params.URI))
+	return s.sendResponse(request.ID, map[string]interface{}{})
+}
+
+// registerDocument stores a parsed document and registers the resources generated
+// from it. It reports whether the document changed and whether the resource list changed.
+func (s *MCPServer) registerDocument(info *types.SwaggerDocumentInfo, document *types.SwaggerDocument) (bool, bool) {
+	if !s.documents.Put(info, document) {
+		return false, false
+	}
+	s.resourceGenerator.InvalidateDocument(info)
+	if s.searchIndex != nil {
+		if err := s.searchIndex.IndexDocument(info, document); err != nil {
+			s.logger.Error("Failed to index document for search", zap.Error(err), zap.String("filePath", info.FilePath))
+		}
+	}
+	if !s.config.Resources.Enabled {
+		return true, false
+	}
+
+	resources, err := s.resourceGenerator.GenerateResourcesFromDocument(document, info)
+	if err != nil {
+		s.logger.Error("Failed to generate resources from document",
+			zap.Error(err),
+			zap.String("filePath", info.FilePath))
+		return true, false
+	}
+
+	listChanged := s.resourceRegistry.ReplaceDocumentResources(info.FilePath, resources)
+	return true, listChanged
+}
+
+// updateDocument applies a reloaded document and notifies the client about changed
+// resources. Unchanged documents produce no notifications.
+func (s *MCPServer) updateDocument(info *types.SwaggerDocumentInfo, document *types.SwaggerDocument) {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	changed, listChanged := s.registerDocument(info, document)
+	if !changed {
+		return
+	}
+
+	s.logger.Info("Swagger document changed", zap.String("filePath", info.FilePath))
+
+	if listChanged {
+		s.shared.NotifyListsChanged(false, true)
+	}
+
+	// Every resource of the document, listed or templated, may render differently now
+	for _, uri := range s.subscribedURIs(s.resourceGenerator.ResourceURIPrefix(info)) {
+		if err := s.sendNotification("notifications/resources/updated", types.MCPResourceUpdatedParams{URI: uri}); err != nil {
+			s.logger.Error("Failed to send resource updated notification", zap.Error(err), zap.String("uri", uri))
+		}
+	}
+}
+
+// reloadDocument re-parses a local document after it changed on disk and applies the
+// resulting tool and resource changes. A document that no longer parses keeps its
+// previous tools and resources.
+func (s *MCPServer) reloadDocument(path string) {
+	stored := s.documents.Get(path)
+	if stored == nil {
+		return
+	}
+
+	document, err := s.parser.ParseDocument(path)
+	if err != nil {
+		s.logger.Warn("Failed to parse changed document, keeping previous version", zap.Error(err), zap.String("filePath", path))
+		return
+	}
+
+	tools, err := s.generator.GenerateToolsFromDocument(document, stored.Info)
+	if err != nil {
+		s.logger.Warn("Failed to generate tools from changed document, keeping previous version", zap.Error(err), zap.String("filePath", path))
+		return
+	}
+
+	toolsByDocument := map[string][]*types.GeneratedTool{path: tools}
+	if dropped := limitDocumentTools(s.toolRegistry, toolsByDocument, s.generator, s.config); dropped > 0 {
+		s.logger.Warn("Reached maximum tool limit, dropping the tools of the lowest priority",
+			zap.Int("maxTools", s.config.Server.MaxTools),
+			zap.Int("toolsDropped", dropped))
+	}
+	changes := s.toolRegistry.ReplaceDocuments(toolsByDocument, 0)
+	for _, name := range changes.Conflicts {
+		s.logger.Warn("Skipping reloaded tool with conflicting name", zap.String("toolName", name))
+	}
+	if !changes.IsEmpty() {
+		s.shared.NotifyToolsChanged(changes)
+		s.saveSnapshot()
+	}
+
+	s.updateDocument(stored.Info, document)
+}
+
+// evictDocument drops a remote document whose tools were evicted for staleness, along
+// with the resources generated from it
+func (s *MCPServer) evictDocument(path string) {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	if stored := s.documents.Get(path); stored != nil {
+		s.resourceGenerator.InvalidateDocument(stored.Info)
+	}
+	s.documents.Delete(path)
+	if s.searchIndex != nil {
+		if err := s.searchIndex.RemoveDocument(path); err != nil {
+			s.logger.Error("Failed to remove document from search index", zap.Error(err), zap.String("filePath", path))
+		}
+	}
+	if s.resourceRegistry.ReplaceDocumentResources(path, nil) {
+		s.shared.NotifyListsChanged(false, true)
+	}
+}
+
+// subscribedURIs returns the subscribed resource URIs with the given prefix
+func (s *MCPServer) subscribedURIs(prefix string) []string {
+	s.subscriptionsMutex.RLock()
+	defer s.subscriptionsMutex.RUnlock()
+
+	var uris []string
+	for uri := range s.subscriptions {
+		if strings.HasPrefix(uri, prefix) {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// decodeParams decodes the params of a request into the given value
+func decodeParams(request *types.MCPRequest, params interface{}) error {
+	paramsBytes, err := json.Marshal(request.Params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(paramsBytes, params)
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmespath/go-jmespath"
+	"swagger-docs-mcp/pkg/http"
+)
+
+// FilterResponse applies a JMESPath expression to the body of a successful JSON
+// response. Error responses and bodies that are not JSON are returned unchanged, so
+// that error details are not filtered away.
+func FilterResponse(response *http.Response, expression string) (*http.Response, error) {
+	if response.StatusCode >= 400 {
+		return response, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		return response, nil
+	}
+
+	compiled, err := jmespath.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JMESPath expression %q: %w", expression, err)
+	}
+
+	filtered, err := compiled.Search(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply JMESPath expression %q: %w", expression, err)
+	}
+
+	encoded, err := json.Marshal(filtered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode filtered response: %w", err)
+	}
+
+	headers := make(map[string]string, len(response.Headers))
+	for name, value := range response.Headers {
+		headers[name] = value
+	}
+	headers["Content-Type"] = "application/json"
+
+	return &http.Response{
+		StatusCode: response.StatusCode,
+		Headers:    headers,
+		Body:       encoded,
+		URL:        response.URL,
+		Source:     response.Source,
+		Attempts:   response.Attempts,
+		Retries:    response.Retries,
+		Duration:   response.Duration,
+	}, nil
+}
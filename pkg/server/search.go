@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// SearchToolName is the name of the built-in tool that searches the loaded documentation
+const SearchToolName = "search_documentation"
+
+// Limits on the number of documentation search results
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// DocumentationSearch is the response of a documentation search
+type DocumentationSearch struct {
+	Query   string                 `json:"query"`
+	Total   int                    `json:"total"`
+	Results []swagger.SearchResult `json:"results"`
+}
+
+// SearchTool describes the documentation search tool
+func SearchTool() types.MCPTool {
+	return types.MCPTool{
+		Name:        SearchToolName,
+		Description: "Search the loaded API documentation. Matches endpoint paths, summaries, descriptions, and parameter names, schema names and properties, and API overviews; every word of the query must match, allowing for word forms and small typos.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Words to search for, e.g. \"hourly forecast\"",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Maximum number of results (default %d, at most %d)", defaultSearchLimit, maxSearchLimit),
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// CallSearchTool runs the documentation search tool with the given arguments
+func CallSearchTool(index *SearchIndex, arguments map[string]interface{}) types.MCPCallToolResult {
+	query, _ := arguments["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return types.MCPCallToolResult{
+			Content: []types.MCPContent{{Type: "text", Text: "The query argument is required"}},
+			IsError: true,
+		}
+	}
+
+	limit := 0
+	if value, ok := arguments["limit"].(float64); ok {
+		limit = int(value)
+	}
+
+	search, err := index.Search(query, limit)
+	if err != nil {
+		return types.MCPCallToolResult{
+			Content: []types.MCPContent{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}
+	}
+
+	content, err := json.MarshalIndent(search, "", "  ")
+	if err != nil {
+		return types.MCPCallToolResult{
+			Content: []types.MCPContent{{Type: "text", Text: fmt.Sprintf("Failed to encode search results: %s", err.Error())}},
+			IsError: true,
+		}
+	}
+
+	return types.MCPCallToolResult{
+		Content: []types.MCPContent{{Type: "text", Text: string(content)}},
+	}
+}
+
+// searchToolEnabled reports whether the documentation search tool is offered. A
+// generated tool with the same name takes precedence.
+func (s *MCPServer) searchToolEnabled() bool {
+	return s.searchIndex != nil && !s.toolRegistry.HasTool(SearchToolName)
+}
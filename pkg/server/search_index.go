@@ -0,0 +1,231 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// searchFieldWeights boosts matches by the field they occur in
+var searchFieldWeights = map[string]float64{
+	"name":        3,
+	"summary":     2,
+	"tags":        2,
+	"parameters":  1.5,
+	"description": 1,
+}
+
+// indexedEntry is the form of a search entry stored in the full-text index
+type indexedEntry struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Parameters  string `json:"parameters"`
+	Tags        string `json:"tags"`
+}
+
+// SearchIndex is an in-memory full-text index over the endpoints, schemas, and
+// overviews of the loaded swagger documents. Queries are stemmed and matched
+// fuzzily, and results are ranked by relevance.
+type SearchIndex struct {
+	generator *swagger.ResourceGenerator
+	logger    *utils.Logger
+	index     bleve.Index
+
+	// results and documents map index IDs back to the results they return and
+	// document paths to the IDs of their entries
+	results   map[string]swagger.SearchResult
+	documents map[string][]string
+	nextID    int
+	mutex     sync.RWMutex
+}
+
+// NewSearchIndex creates an empty search index
+func NewSearchIndex(generator *swagger.ResourceGenerator, logger *utils.Logger) (*SearchIndex, error) {
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = en.AnalyzerName
+	textField.Store = false
+	textField.IncludeTermVectors = false
+
+	entryMapping := bleve.NewDocumentMapping()
+	for field := range searchFieldWeights {
+		entryMapping.AddFieldMappingsAt(field, textField)
+	}
+	kindField := bleve.NewKeywordFieldMapping()
+	kindField.Store = false
+	entryMapping.AddFieldMappingsAt("kind", kindField)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = entryMapping
+	indexMapping.DefaultAnalyzer = en.AnalyzerName
+
+	index, err := bleve.NewMemOnly(indexMapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	return &SearchIndex{
+		generator: generator,
+		logger:    logger.Child("search-index"),
+		index:     index,
+		results:   make(map[string]swagger.SearchResult),
+		documents: make(map[string][]string),
+	}, nil
+}
+
+// IndexDocument indexes the searchable parts of a document, replacing any entries
+// previously indexed for the same path
+func (i *SearchIndex) IndexDocument(info *types.SwaggerDocumentInfo, doc *types.SwaggerDocument) error {
+	entries := i.generator.SearchEntries(doc, info)
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	batch := i.index.NewBatch()
+	for _, id := range i.documents[info.FilePath] {
+		batch.Delete(id)
+	}
+
+	ids := make([]string, 0, len(entries))
+	results := make(map[string]swagger.SearchResult, len(entries))
+	for _, entry := range entries {
+		i.nextID++
+		id := strconv.Itoa(i.nextID)
+		if err := batch.Index(id, indexedEntry{
+			Kind:        entry.Result.Kind,
+			Name:        entry.Name,
+			Summary:     entry.Summary,
+			Description: entry.Description,
+			Parameters:  entry.Parameters,
+			Tags:        entry.Tags,
+		}); err != nil {
+			return fmt.Errorf("failed to index %s: %w", entry.Result.Title, err)
+		}
+		ids = append(ids, id)
+		results[id] = entry.Result
+	}
+
+	if err := i.index.Batch(batch); err != nil {
+		return fmt.Errorf("failed to index document %s: %w", info.FilePath, err)
+	}
+
+	for _, id := range i.documents[info.FilePath] {
+		delete(i.results, id)
+	}
+	for id, result := range results {
+		i.results[id] = result
+	}
+	i.documents[info.FilePath] = ids
+
+	i.logger.Debug("Indexed document for search", zap.String("filePath", info.FilePath), zap.Int("entries", len(ids)))
+	return nil
+}
+
+// RemoveDocument drops the indexed entries of a document
+func (i *SearchIndex) RemoveDocument(path string) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	ids, exists := i.documents[path]
+	if !exists {
+		return nil
+	}
+
+	batch := i.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(id)
+	}
+	if err := i.index.Batch(batch); err != nil {
+		return fmt.Errorf("failed to remove document %s from search index: %w", path, err)
+	}
+
+	for _, id := range ids {
+		delete(i.results, id)
+	}
+	delete(i.documents, path)
+	return nil
+}
+
+// Search returns the entries matching every word of the query, best matches first.
+// Words match exactly, by stem, by prefix, or within one edit for longer words.
+func (i *SearchIndex) Search(text string, limit int) (DocumentationSearch, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	search := DocumentationSearch{Query: text, Results: []swagger.SearchResult{}}
+
+	analyzer := i.index.Mapping().AnalyzerNamed(en.AnalyzerName)
+	tokens := analyzer.Analyze([]byte(text))
+	if len(tokens) == 0 {
+		return search, nil
+	}
+
+	termQueries := make([]query.Query, 0, len(tokens))
+	for _, token := range tokens {
+		termQueries = append(termQueries, searchTermQuery(string(token.Term)))
+	}
+
+	request := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(termQueries...), limit, 0, false)
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	response, err := i.index.Search(request)
+	if err != nil {
+		return search, fmt.Errorf("search failed: %w", err)
+	}
+
+	search.Total = int(response.Total)
+	for _, hit := range response.Hits {
+		result, exists := i.results[hit.ID]
+		if !exists {
+			continue
+		}
+		result.Score = hit.Score
+		search.Results = append(search.Results, result)
+	}
+	swagger.SortSearchResults(search.Results)
+
+	return search, nil
+}
+
+// searchTermQuery matches one analyzed query term in any field, preferring exact
+// matches over prefix and fuzzy ones
+func searchTermQuery(term string) query.Query {
+	var queries []query.Query
+	for field, weight := range searchFieldWeights {
+		exact := bleve.NewTermQuery(term)
+		exact.SetField(field)
+		exact.SetBoost(weight)
+		queries = append(queries, exact)
+
+		if len(term) >= 3 {
+			prefix := bleve.NewPrefixQuery(term)
+			prefix.SetField(field)
+			prefix.SetBoost(weight * 0.5)
+			queries = append(queries, prefix)
+		}
+
+		if len(term) >= 4 {
+			fuzzy := bleve.NewFuzzyQuery(term)
+			fuzzy.SetField(field)
+			fuzzy.SetFuzziness(1)
+			fuzzy.SetBoost(weight * 0.3)
+			queries = append(queries, fuzzy)
+		}
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
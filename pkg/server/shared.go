@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// SharedState is the state of the servers of one process, so that one scan of the
+// documents serves all of its transports: the tool, prompt and resource registries, the
+// parsed documents and their search index, and the HTTP client and executor of tool
+// calls, and the state of the swagger sources. The primary server scans the documents into the state and keeps them up to
+// date; the other servers serve them once it marks the state ready.
+type SharedState struct {
+	Tools       *ToolRegistry
+	Prompts     *PromptRegistry
+	Resources   *ResourceRegistry
+	Documents   *DocumentStore
+	SearchIndex *SearchIndex
+	HTTPClient  *http.Client
+	Executor    *ToolExecutor
+	Sources     *SourceTracker
+	// Usage persists the tool usage counters and the quota usage of API keys, nil
+	// unless server.usagePath is set
+	Usage *UsageStore
+	// Webhooks posts the events of tool calls, nil unless server.webhooks is set
+	Webhooks *Webhooks
+	// Bus publishes the events of tool calls and of changes to the tools to a message
+	// bus, nil unless server.bus is set
+	Bus *EventBus
+	// Catalog shares the tools, prompts, and resources with the other replicas of a
+	// deployment, nil unless server.sharedRegistry is set
+	Catalog *SharedCatalog
+	// Blobs keeps the binary responses of tool calls for clients to read as resources
+	Blobs *BlobStore
+
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	listeners      []func(ToolChanges)
+	listListeners  []func(prompts, resources bool)
+	listenersMutex sync.RWMutex
+}
+
+// NewSharedState creates the empty state of the servers of a process
+func NewSharedState(config *types.ResolvedConfig, logger *utils.Logger) *SharedState {
+	state := &SharedState{
+		Tools:      NewToolRegistry(),
+		Prompts:    NewPromptRegistryWithPolicy(config.Prompts.ConflictPolicy),
+		Resources:  NewResourceRegistry(),
+		Documents:  NewDocumentStore(),
+		HTTPClient: http.NewClient(config, logger),
+		Sources:    NewSourceTracker(config),
+		Blobs:      NewBlobStore(config.Server.Blobs),
+		ready:      make(chan struct{}),
+	}
+	state.Executor = NewToolExecutor(config, state.Documents, state.Tools, logger)
+	state.Webhooks = NewWebhooks(config, logger)
+	state.Bus = NewEventBus(config, logger)
+	state.Catalog = NewSharedCatalog(config, state.Tools, state.Prompts, state.Resources, logger)
+
+	if config.Server.UsagePath != "" {
+		state.Usage = NewUsageStore(config.Server.UsagePath, logger)
+		if err := state.Tools.SetUsageStore(state.Usage); err != nil {
+			logger.Error("Failed to load tool usage", zap.Error(err), zap.String("path", config.Server.UsagePath))
+		}
+	}
+
+	if resourceGenerator := swagger.NewResourceGenerator(logger, &config.Resources); resourceGenerator.SearchEnabled() {
+		if index, err := NewSearchIndex(resourceGenerator, logger); err != nil {
+			logger.Error("Documentation search is unavailable", zap.Error(err))
+		} else {
+			state.SearchIndex = index
+		}
+	}
+
+	return state
+}
+
+// MarkReady records that the primary server has scanned the documents, whether or
+// not the scan succeeded
+func (s *SharedState) MarkReady() {
+	s.readyOnce.Do(func() { close(s.ready) })
+}
+
+// Ready returns a channel that is closed once the primary server has scanned the documents
+func (s *SharedState) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// OnToolsChanged registers a function called with the changes of each update of the tools
+func (s *SharedState) OnToolsChanged(fn func(ToolChanges)) {
+	s.listenersMutex.Lock()
+	defer s.listenersMutex.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// NotifyToolsChanged calls the registered functions with the changes of an update of
+// the tools
+func (s *SharedState) NotifyToolsChanged(changes ToolChanges) {
+	s.listenersMutex.RLock()
+	listeners := append([]func(ToolChanges){}, s.listeners...)
+	s.listenersMutex.RUnlock()
+
+	for _, listener := range listeners {
+		listener(changes)
+	}
+	s.Bus.ToolsChanged(changes, s.Tools.GetToolCount())
+	s.Catalog.Changed()
+}
+
+// OnListsChanged registers a function called with whether the prompts and the list of
+// resources changed, on each update of either
+func (s *SharedState) OnListsChanged(fn func(prompts, resources bool)) {
+	s.listenersMutex.Lock()
+	defer s.listenersMutex.Unlock()
+	s.listListeners = append(s.listListeners, fn)
+}
+
+// NotifyListsChanged calls the registered functions with whether an update changed
+// the prompts and the list of resources
+func (s *SharedState) NotifyListsChanged(prompts, resources bool) {
+	s.listenersMutex.RLock()
+	listeners := append([]func(bool, bool){}, s.listListeners...)
+	s.listenersMutex.RUnlock()
+
+	for _, listener := range listeners {
+		listener(prompts, resources)
+	}
+	s.Catalog.Changed()
+}
+
+// SyncCatalog starts sharing the tools, prompts, and resources with the other
+// replicas of a deployment once the state is ready, if a shared registry is
+// configured. The primary server of the state calls it when it starts serving.
+func (s *SharedState) SyncCatalog() {
+	s.Catalog.Start(s.Ready(), s.NotifyToolsChanged, s.NotifyListsChanged)
+}
+
+// TelemetryCounts returns the numbers of documents loaded and tools generated, for
+// telemetry reports
+func (s *SharedState) TelemetryCounts() TelemetryCounts {
+	return TelemetryCounts{Documents: len(s.Documents.All()), Tools: s.Tools.GetToolCount()}
+}
+
+// ToolCalled reports a completed tool call to the webhooks and the message bus, if
+// configured
+func (s *SharedState) ToolCalled(ctx context.Context, call ToolCall) {
+	s.Webhooks.ToolCalled(ctx, call)
+	s.Bus.ToolCalled(ctx, call)
+}
+
+// Close writes the buffered usage counters to the usage store, waits for the queued
+// webhook and message bus events to be sent, and stops sharing the tools, if any is
+// configured
+func (s *SharedState) Close() {
+	s.Catalog.Close()
+	s.Webhooks.Close()
+	s.Bus.Close()
+	if s.Usage == nil {
+		return
+	}
+	if err := s.Usage.Flush(); err != nil {
+		s.Usage.logger.Error("Failed to save usage", zap.Error(err), zap.String("path", s.Usage.path))
+	}
+}
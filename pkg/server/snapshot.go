@@ -0,0 +1,225 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// snapshotFormat is bumped whenever the stored layout changes, invalidating older snapshots
+const snapshotFormat = 4
+
+// snapshotOpenTimeout bounds how long to wait for another process holding the snapshot file
+const snapshotOpenTimeout = time.Second
+
+var (
+	snapshotMetaBucket      = []byte("meta")
+	snapshotDocumentsBucket = []byte("documents")
+	snapshotFingerprintKey  = []byte("fingerprint")
+	snapshotSavedAtKey      = []byte("savedAt")
+)
+
+// snapshotDocument is the stored form of the tools generated from one document. The
+// document info is stored once rather than with every tool.
+type snapshotDocument struct {
+	Info  *types.SwaggerDocumentInfo `json:"info,omitempty"`
+	Tools []*types.GeneratedTool     `json:"tools"`
+	// SchemaRoot holds the components the lazy input schemas of the tools are
+	// generated against, as they are stored without their schemas
+	SchemaRoot map[string]interface{} `json:"schemaRoot,omitempty"`
+}
+
+// RegistrySnapshot persists the tool registry to a bbolt file so that a restarted
+// server can list tools before its first scan completes
+type RegistrySnapshot struct {
+	path   string
+	logger *utils.Logger
+}
+
+// NewRegistrySnapshot creates a snapshot stored at the given file path
+func NewRegistrySnapshot(path string, logger *utils.Logger) *RegistrySnapshot {
+	return &RegistrySnapshot{
+		path:   path,
+		logger: logger.Child("snapshot"),
+	}
+}
+
+// SnapshotFingerprint identifies the configuration that tools were generated under.
+// A snapshot saved under a different fingerprint is ignored on load.
+func SnapshotFingerprint(config *types.ResolvedConfig) string {
+	encoded, _ := json.Marshal(struct {
+		Format         int                        `json:"format"`
+		Version        string                     `json:"version"`
+		SwaggerPaths   []string                   `json:"swaggerPaths"`
+		SwaggerURLs    []string                   `json:"swaggerUrls"`
+		PackageIDs     []string                   `json:"packageIds"`
+		TWCFilters     *types.TWCFilters          `json:"twcFilters"`
+		DynamicFilters map[string]interface{}     `json:"dynamicFilters"`
+		ToolGeneration types.ToolGenerationConfig `json:"toolGeneration"`
+		MaxTools       int                        `json:"maxTools"`
+		ToolPriority   []string                   `json:"toolPriority"`
+	}{
+		Format:         snapshotFormat,
+		Version:        config.Version,
+		SwaggerPaths:   config.SwaggerPaths,
+		SwaggerURLs:    config.SwaggerURLs,
+		PackageIDs:     config.PackageIDs,
+		TWCFilters:     config.TWCFilters,
+		DynamicFilters: config.DynamicFilters,
+		ToolGeneration: config.ToolGeneration,
+		MaxTools:       config.Server.MaxTools,
+		ToolPriority:   config.Server.ToolPriority,
+	})
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Load registers the snapshot's tools in the registry and returns how many were loaded.
+// A missing snapshot, or one saved under another fingerprint, loads nothing; an empty
+// fingerprint loads the snapshot whatever configuration it was saved under.
+func (s *RegistrySnapshot) Load(registry *ToolRegistry, fingerprint string) (int, error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: snapshotOpenTimeout, ReadOnly: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to open registry snapshot: %w", err)
+	}
+	defer db.Close()
+
+	var documents []snapshotDocument
+	err = db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(snapshotMetaBucket)
+		if meta == nil || (fingerprint != "" && string(meta.Get(snapshotFingerprintKey)) != fingerprint) {
+			s.logger.Info("Ignoring registry snapshot saved under a different configuration", zap.String("path", s.path))
+			return nil
+		}
+
+		bucket := tx.Bucket(snapshotDocumentsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(key, value []byte) error {
+			var document snapshotDocument
+			if err := json.Unmarshal(value, &document); err != nil {
+				return fmt.Errorf("failed to decode snapshot of %s: %w", key, err)
+			}
+			documents = append(documents, document)
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := 0
+	for _, tool := range restoreSnapshotTools(documents) {
+		if err := registry.RegisterTool(tool); err != nil {
+			s.logger.Warn("Skipping snapshot tool", zap.Error(err), zap.String("toolName", tool.Name))
+			continue
+		}
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// Save replaces the snapshot with the registry's current tools
+func (s *RegistrySnapshot) Save(registry *ToolRegistry, fingerprint string) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	documents := groupSnapshotTools(registry.GetAllTools())
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: snapshotOpenTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open registry snapshot: %w", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(snapshotDocumentsBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		bucket, err := tx.CreateBucket(snapshotDocumentsBucket)
+		if err != nil {
+			return err
+		}
+		for key, document := range documents {
+			encoded, err := json.Marshal(document)
+			if err != nil {
+				return fmt.Errorf("failed to encode snapshot of %s: %w", key, err)
+			}
+			if err := bucket.Put([]byte(key), encoded); err != nil {
+				return err
+			}
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(snapshotMetaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put(snapshotFingerprintKey, []byte(fingerprint)); err != nil {
+			return err
+		}
+		return meta.Put(snapshotSavedAtKey, []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save registry snapshot: %w", err)
+	}
+
+	s.logger.Debug("Saved registry snapshot", zap.String("path", s.path), zap.Int("documents", len(documents)))
+	return nil
+}
+
+// groupSnapshotTools groups tools by document in their stored form, so that each
+// document's info is stored once. Tools without a document share the key "-", as
+// bbolt keys cannot be empty.
+func groupSnapshotTools(tools []*types.GeneratedTool) map[string]*snapshotDocument {
+	documents := make(map[string]*snapshotDocument)
+	for _, tool := range tools {
+		key := "-"
+		if tool.DocumentInfo != nil {
+			key = tool.DocumentInfo.FilePath
+		}
+		document, exists := documents[key]
+		if !exists {
+			document = &snapshotDocument{Info: tool.DocumentInfo}
+			documents[key] = document
+		}
+		// Lazy schemas are stored as their source, to be generated when restored
+		// tools are first used
+		if document.SchemaRoot == nil && tool.InputSchema == nil {
+			document.SchemaRoot = tool.SchemaRoot
+		}
+		stored := *tool
+		stored.DocumentInfo = nil
+		document.Tools = append(document.Tools, &stored)
+	}
+	return documents
+}
+
+// restoreSnapshotTools returns the tools of stored documents with their document info
+func restoreSnapshotTools(documents []snapshotDocument) []*types.GeneratedTool {
+	var tools []*types.GeneratedTool
+	for _, document := range documents {
+		for _, tool := range document.Tools {
+			tool.DocumentInfo = document.Info
+			tool.SchemaRoot = document.SchemaRoot
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
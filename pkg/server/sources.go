@@ -0,0 +1,204 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// SourceStatusResourceURI addresses the resource that serves the swagger source report
+const SourceStatusResourceURI = "status://sources"
+
+// SourceStatus is the state of a swagger path or URL as of its last scan
+type SourceStatus string
+
+const (
+	// SourcePending has not been scanned yet
+	SourcePending SourceStatus = "pending"
+	// SourceLoaded was reached, and none of its documents failed
+	SourceLoaded SourceStatus = "loaded"
+	// SourcePartial loaded some documents while others failed
+	SourcePartial SourceStatus = "partial"
+	// SourceFailed could not be reached, or none of its documents loaded
+	SourceFailed SourceStatus = "failed"
+	// SourceEmpty was reached but holds no swagger documents
+	SourceEmpty SourceStatus = "empty"
+)
+
+// SourceState reports the outcome of the last scan of a configured swagger path or URL
+type SourceState struct {
+	Source    string       `json:"source"`
+	IsRemote  bool         `json:"isRemote"`
+	Status    SourceStatus `json:"status"`
+	Documents int          `json:"documents"`
+	Loaded    int          `json:"loaded"`
+	// Errors are why the source or its documents failed in the last scan
+	Errors      []types.ScanError `json:"errors,omitempty"`
+	LastScanned *time.Time        `json:"lastScanned,omitempty"`
+	// LastLoaded is when a document of the source last loaded, at startup or on a
+	// refresh
+	LastLoaded *time.Time `json:"lastLoaded,omitempty"`
+}
+
+// SourceReport summarizes the configured swagger paths and URLs
+type SourceReport struct {
+	Total   int           `json:"total"`
+	Loaded  int           `json:"loaded"`
+	Failed  int           `json:"failed"`
+	Sources []SourceState `json:"sources"`
+}
+
+// SourceTracker records the scans of the configured swagger paths and URLs and the
+// documents loaded from them, to report which sources are reachable
+type SourceTracker struct {
+	sources    []*SourceState
+	byDocument map[string]*SourceState
+	mutex      sync.RWMutex
+}
+
+// NewSourceTracker creates a tracker of the swagger paths and URLs of a configuration,
+// all pending until their first scan
+func NewSourceTracker(config *types.ResolvedConfig) *SourceTracker {
+	t := &SourceTracker{byDocument: make(map[string]*SourceState)}
+	for _, path := range config.SwaggerPaths {
+		t.sources = append(t.sources, &SourceState{Source: path, Status: SourcePending})
+	}
+	for _, url := range config.SwaggerURLs {
+		t.sources = append(t.sources, &SourceState{Source: url, IsRemote: true, Status: SourcePending})
+	}
+	return t
+}
+
+// RecordScan records the outcome of a scan for the sources it scanned, whose documents
+// then count as loaded as RecordDocumentLoaded records them. A nil tracker records
+// nothing, like its other Record methods.
+func (t *SourceTracker) RecordScan(result *types.ScanResult) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now().UTC()
+	for _, scan := range result.Sources {
+		state := t.source(scan.Source, scan.IsRemote)
+		state.Documents = len(scan.Documents)
+		state.Loaded = 0
+		state.Errors = append([]types.ScanError(nil), scan.Errors...)
+		state.LastScanned = &now
+		for _, document := range scan.Documents {
+			t.byDocument[document] = state
+		}
+		state.Status = state.status()
+	}
+}
+
+// RecordDocumentLoaded records that a document of a scanned source loaded
+func (t *SourceTracker) RecordDocumentLoaded(document string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, ok := t.byDocument[document]
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	state.Loaded++
+	state.LastLoaded = &now
+	state.Status = state.status()
+}
+
+// RecordDocumentError records that a document of a scanned source failed to load
+func (t *SourceTracker) RecordDocumentError(document string, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, ok := t.byDocument[document]
+	if !ok {
+		return
+	}
+	state.Errors = append(state.Errors, types.ScanError{Path: document, Error: err.Error()})
+	state.Status = state.status()
+}
+
+// Report returns the state of every source, in the order they are configured
+func (t *SourceTracker) Report() SourceReport {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	report := SourceReport{
+		Total:   len(t.sources),
+		Sources: make([]SourceState, 0, len(t.sources)),
+	}
+	for _, state := range t.sources {
+		switch state.Status {
+		case SourceLoaded, SourcePartial:
+			report.Loaded++
+		case SourceFailed:
+			report.Failed++
+		}
+		state := *state
+		state.Errors = append([]types.ScanError(nil), state.Errors...)
+		report.Sources = append(report.Sources, state)
+	}
+	return report
+}
+
+// source returns the state of a source, adding sources scanned without being configured
+func (t *SourceTracker) source(source string, isRemote bool) *SourceState {
+	for _, state := range t.sources {
+		if state.Source == source {
+			return state
+		}
+	}
+	state := &SourceState{Source: source, IsRemote: isRemote}
+	t.sources = append(t.sources, state)
+	return state
+}
+
+// status derives the status of a scanned source from its documents and errors
+func (s *SourceState) status() SourceStatus {
+	switch {
+	case len(s.Errors) > 0 && s.Loaded == 0:
+		return SourceFailed
+	case len(s.Errors) > 0:
+		return SourcePartial
+	case s.Documents == 0:
+		return SourceEmpty
+	default:
+		return SourceLoaded
+	}
+}
+
+// SourceStatusResource describes the swagger source report as an MCP resource
+func SourceStatusResource() types.MCPResource {
+	return types.MCPResource{
+		URI:         SourceStatusResourceURI,
+		Name:        "Swagger Source Status",
+		Description: "Which configured swagger paths and URLs loaded, which failed and why, and when they last loaded",
+		MimeType:    "application/json",
+	}
+}
+
+// SourceStatusContent renders the report of a tracker as the source status resource content
+func SourceStatusContent(tracker *SourceTracker) (types.MCPResourceContent, error) {
+	content, err := json.MarshalIndent(tracker.Report(), "", "  ")
+	if err != nil {
+		return types.MCPResourceContent{}, fmt.Errorf("failed to marshal source status: %w", err)
+	}
+
+	return types.MCPResourceContent{
+		URI:      SourceStatusResourceURI,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+	"swagger-docs-mcp/pkg/version"
+)
+
+const (
+	// telemetryInterval is how often a running server reports its counts again
+	telemetryInterval = 24 * time.Hour
+	// telemetryTimeout bounds each post of a report
+	telemetryTimeout = 10 * time.Second
+	// telemetrySchemaVersion is the version of the report format, raised whenever
+	// a field is added so that the data policy can name what each version holds
+	telemetrySchemaVersion = 1
+)
+
+// TelemetryPolicy describes what telemetry reports hold, printed by the telemetry command
+const TelemetryPolicy = `Telemetry is off unless turned on with --telemetry=on, WX_MCP_TELEMETRY=on, or
+telemetry.mode: on in the config file, along with the endpoint reports are posted to.
+Setting DO_NOT_TRACK=1 keeps it off whatever the configuration says.
+
+When on, the server posts one JSON report once the documents are loaded and another
+every 24 hours while it runs. A report holds only:
+
+  - the server version, operating system, and architecture
+  - the transports served (stdio, sse, mcp-http)
+  - the number of documents loaded and of tools generated
+
+Reports hold no document names, paths, URLs, or contents, no tool names or
+arguments, no API keys or other credentials, no hostnames or addresses, and no
+identifier of the installation, so reports cannot be linked to each other. A report
+that fails to send is dropped, not retried or stored.`
+
+// TelemetryCounts are the aggregate counts of a telemetry report
+type TelemetryCounts struct {
+	Documents int `json:"documents"`
+	Tools     int `json:"tools"`
+}
+
+// TelemetryReport is the JSON payload posted to the telemetry endpoint. It must only
+// hold what TelemetryPolicy describes.
+type TelemetryReport struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Version       string            `json:"version"`
+	OS            string            `json:"os"`
+	Arch          string            `json:"arch"`
+	Transports    []types.Transport `json:"transports"`
+	TelemetryCounts
+}
+
+// NewTelemetryReport builds the report of a server serving transports with counts
+func NewTelemetryReport(transports []types.Transport, counts TelemetryCounts) TelemetryReport {
+	return TelemetryReport{
+		SchemaVersion:   telemetrySchemaVersion,
+		Version:         version.GetSemanticVersion(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Transports:      transports,
+		TelemetryCounts: counts,
+	}
+}
+
+// TelemetryEnabled reports whether telemetry reports are sent: only when telemetry is
+// explicitly on and DO_NOT_TRACK is not set
+func TelemetryEnabled(config types.TelemetryConfig) bool {
+	if doNotTrack := os.Getenv("DO_NOT_TRACK"); doNotTrack != "" && doNotTrack != "0" {
+		return false
+	}
+	return config.Mode == types.TelemetryOn && config.Endpoint != ""
+}
+
+// Telemetry posts the anonymous counts of a running server to the telemetry endpoint.
+// A nil Telemetry reports nothing.
+type Telemetry struct {
+	endpoint string
+	client   *http.Client
+	logger   *utils.Logger
+}
+
+// NewTelemetry creates the telemetry of a server, returning nil unless telemetry is on
+func NewTelemetry(config *types.ResolvedConfig, logger *utils.Logger) *Telemetry {
+	if !TelemetryEnabled(config.Telemetry) {
+		return nil
+	}
+
+	t := &Telemetry{
+		endpoint: config.Telemetry.Endpoint,
+		client:   &http.Client{Timeout: telemetryTimeout},
+		logger:   logger.Child("telemetry"),
+	}
+	t.logger.Info("Telemetry is on; anonymous usage counts are reported, see the telemetry command for what is sent",
+		zap.String("endpoint", t.endpoint))
+	return t
+}
+
+// Run reports the counts of a server serving transports once ready is closed and then
+// every day, until ctx is done. counts is called for each report.
+func (t *Telemetry) Run(ctx context.Context, transports []types.Transport, ready <-chan struct{}, counts func() TelemetryCounts) {
+	if t == nil {
+		return
+	}
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return
+	}
+
+	ticker := time.NewTicker(telemetryInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.send(ctx, NewTelemetryReport(transports, counts())); err != nil {
+			t.logger.Debug("Failed to send telemetry report", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send posts a report to the telemetry endpoint
+func (t *Telemetry) send(ctx context.Context, report TelemetryReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "swagger-docs-mcp/"+version.GetSemanticVersion())
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post telemetry report: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint answered with status %d", response.StatusCode)
+	}
+	return nil
+}
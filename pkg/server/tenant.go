@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Tenant is a client entitled by its API key to the tools matching its filter
+type Tenant struct {
+	Name   string
+	Filter ToolFilter
+	// Quotas replaces the server quotas that it sets
+	Quotas types.QuotaConfig
+}
+
+// Allows reports whether the tenant is entitled to a tool. A nil tenant, for clients
+// that are not restricted to one, is entitled to every tool.
+func (t *Tenant) Allows(tool *types.GeneratedTool) bool {
+	return t == nil || t.Filter.Matches(tool)
+}
+
+// Tenants resolves the client API keys of server.auth.tenants to their tenants
+type Tenants struct {
+	keys    [][]byte
+	tenants []*Tenant
+}
+
+// NewTenants creates the tenants of the server auth configuration
+func NewTenants(config map[string]types.TenantConfig) *Tenants {
+	t := &Tenants{}
+	for key, tenant := range config {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		t.keys = append(t.keys, []byte(key))
+		t.tenants = append(t.tenants, &Tenant{
+			Name: tenant.Name,
+			Filter: ToolFilter{
+				PackageIDs:     tenant.PackageIDs,
+				TWCPortfolios:  tenant.TWCPortfolios,
+				TWCDomains:     tenant.TWCDomains,
+				TWCGeographies: tenant.TWCGeographies,
+			},
+			Quotas: tenant.Quotas,
+		})
+	}
+	return t
+}
+
+// Enabled reports whether any tenant is configured
+func (t *Tenants) Enabled() bool {
+	return len(t.keys) > 0
+}
+
+// Lookup returns the tenant of a client API key, or nil. Keys are compared in constant
+// time, like bearer tokens.
+func (t *Tenants) Lookup(key string) *Tenant {
+	presented := []byte(key)
+	for i, expected := range t.keys {
+		if subtle.ConstantTimeCompare(presented, expected) == 1 {
+			return t.tenants[i]
+		}
+	}
+	return nil
+}
+
+// tenantKey is the context key for the tenant of the current request
+type tenantKey struct{}
+
+// WithTenant attaches the tenant of a request to its context
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the current request, or nil when the client
+// is not restricted to one
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return tenant
+}
+
+// BearerToken extracts the credential from an "Authorization: Bearer" header
+func BearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"sort"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// ToolDiff describes how the tools generated from one set of documents differ from
+// those generated from another, as seen by MCP clients
+type ToolDiff struct {
+	Added   []string       `json:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+	Renamed []ToolRename   `json:"renamed,omitempty"`
+	Changed []SchemaChange `json:"changed,omitempty"`
+}
+
+// ToolRename is a tool whose endpoint is served under a new tool name
+type ToolRename struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Endpoint string `json:"endpoint"`
+}
+
+// SchemaChange lists the changes of the input schema of a tool, by argument
+type SchemaChange struct {
+	Tool             string   `json:"tool"`
+	Added            []string `json:"added,omitempty"`
+	Removed          []string `json:"removed,omitempty"`
+	Changed          []string `json:"changed,omitempty"`
+	NowRequired      []string `json:"nowRequired,omitempty"`
+	NoLongerRequired []string `json:"noLongerRequired,omitempty"`
+}
+
+// IsEmpty reports whether the tools are the same
+func (d ToolDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0 && len(d.Changed) == 0
+}
+
+// Breaking reports whether clients calling the previous tools may fail against the
+// current ones: a tool was removed or renamed, or an argument was removed, changed,
+// or made required
+func (d ToolDiff) Breaking() bool {
+	if len(d.Removed) > 0 || len(d.Renamed) > 0 {
+		return true
+	}
+	for _, change := range d.Changed {
+		if len(change.Removed) > 0 || len(change.Changed) > 0 || len(change.NowRequired) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffTools compares the tools generated from two sets of documents. A removed and an
+// added tool are reported as a rename when they are the only ones with their method
+// and path.
+func DiffTools(previous, current []*types.GeneratedTool) ToolDiff {
+	var diff ToolDiff
+
+	previousTools := make(map[string]*types.GeneratedTool, len(previous))
+	for _, tool := range previous {
+		previousTools[tool.Name] = tool
+	}
+	currentTools := make(map[string]*types.GeneratedTool, len(current))
+	for _, tool := range current {
+		currentTools[tool.Name] = tool
+	}
+
+	removedByEndpoint := make(map[string][]*types.GeneratedTool)
+	for _, tool := range previous {
+		if _, exists := currentTools[tool.Name]; !exists {
+			removedByEndpoint[endpointKey(tool)] = append(removedByEndpoint[endpointKey(tool)], tool)
+		}
+	}
+	addedByEndpoint := make(map[string][]*types.GeneratedTool)
+	for _, tool := range current {
+		if _, exists := previousTools[tool.Name]; !exists {
+			addedByEndpoint[endpointKey(tool)] = append(addedByEndpoint[endpointKey(tool)], tool)
+		}
+	}
+
+	for key, removed := range removedByEndpoint {
+		added := addedByEndpoint[key]
+		if key != "" && len(removed) == 1 && len(added) == 1 {
+			diff.Renamed = append(diff.Renamed, ToolRename{From: removed[0].Name, To: added[0].Name, Endpoint: key})
+			if change, changed := diffSchemas(added[0].Name, removed[0].Schema(), added[0].Schema()); changed {
+				diff.Changed = append(diff.Changed, change)
+			}
+			delete(addedByEndpoint, key)
+			continue
+		}
+		for _, tool := range removed {
+			diff.Removed = append(diff.Removed, tool.Name)
+		}
+	}
+	for _, added := range addedByEndpoint {
+		for _, tool := range added {
+			diff.Added = append(diff.Added, tool.Name)
+		}
+	}
+
+	for _, tool := range current {
+		if previousTool, exists := previousTools[tool.Name]; exists {
+			if change, changed := diffSchemas(tool.Name, previousTool.Schema(), tool.Schema()); changed {
+				diff.Changed = append(diff.Changed, change)
+			}
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Renamed, func(i, j int) bool { return diff.Renamed[i].From < diff.Renamed[j].From })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Tool < diff.Changed[j].Tool })
+	return diff
+}
+
+// endpointKey identifies the endpoint of a tool by method and path, or is empty for
+// tools without an endpoint
+func endpointKey(tool *types.GeneratedTool) string {
+	if tool.Endpoint == nil {
+		return ""
+	}
+	return strings.ToUpper(tool.Endpoint.Method) + " " + tool.Endpoint.Path
+}
+
+// diffSchemas compares the arguments of two input schemas and reports whether they differ
+func diffSchemas(tool string, previous, current map[string]interface{}) (SchemaChange, bool) {
+	change := SchemaChange{Tool: tool}
+
+	previousProperties, _ := previous["properties"].(map[string]interface{})
+	currentProperties, _ := current["properties"].(map[string]interface{})
+	for name, property := range currentProperties {
+		previousProperty, exists := previousProperties[name]
+		if !exists {
+			change.Added = append(change.Added, name)
+		} else if !sameJSON(previousProperty, property) {
+			change.Changed = append(change.Changed, name)
+		}
+	}
+	for name := range previousProperties {
+		if _, exists := currentProperties[name]; !exists {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+
+	previousRequired := requiredArguments(previous)
+	currentRequired := requiredArguments(current)
+	for name := range currentRequired {
+		if !previousRequired[name] {
+			change.NowRequired = append(change.NowRequired, name)
+		}
+	}
+	for name := range previousRequired {
+		// Removed arguments are reported once, as removed
+		if _, exists := currentProperties[name]; exists && !currentRequired[name] {
+			change.NoLongerRequired = append(change.NoLongerRequired, name)
+		}
+	}
+
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Strings(change.Changed)
+	sort.Strings(change.NowRequired)
+	sort.Strings(change.NoLongerRequired)
+
+	changed := len(change.Added) > 0 || len(change.Removed) > 0 || len(change.Changed) > 0 ||
+		len(change.NowRequired) > 0 || len(change.NoLongerRequired) > 0
+	return change, changed
+}
+
+// requiredArguments returns the required arguments of an input schema, whose list is
+// a []string when generated and a []interface{} when decoded from a snapshot
+func requiredArguments(schema map[string]interface{}) map[string]bool {
+	required := make(map[string]bool)
+	switch names := schema["required"].(type) {
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	case []interface{}:
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	return required
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"sort"
+
+	"swagger-docs-mcp/pkg/swagger"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// LimitTools splits tools into the server.maxTools tools of the highest priority, by
+// the rules of the generator's PrioritizeTools, and the tools dropped to stay within
+// the maximum. Both keep the order of the tools.
+func LimitTools(tools []*types.GeneratedTool, generator *swagger.ToolGenerator, config *types.ResolvedConfig) (kept, dropped []*types.GeneratedTool) {
+	if config.Server.MaxTools <= 0 || len(tools) <= config.Server.MaxTools {
+		return tools, nil
+	}
+
+	keep := make(map[*types.GeneratedTool]bool, config.Server.MaxTools)
+	for _, tool := range generator.PrioritizeTools(tools, config.Server.ToolPriority, config.PackageIDs)[:config.Server.MaxTools] {
+		keep[tool] = true
+	}
+	for _, tool := range tools {
+		if keep[tool] {
+			kept = append(kept, tool)
+		} else {
+			dropped = append(dropped, tool)
+		}
+	}
+	return kept, dropped
+}
+
+// limitDocumentTools applies LimitTools to the tools that replacing the given documents
+// in the registry would leave registered, and drops the tools beyond the maximum from
+// toolsByDocument. A document not being replaced that loses tools to a tool of higher
+// priority is added with its remaining tools. It returns the number of dropped tools.
+func limitDocumentTools(registry *ToolRegistry, toolsByDocument map[string][]*types.GeneratedTool, generator *swagger.ToolGenerator, config *types.ResolvedConfig) int {
+	if config.Server.MaxTools <= 0 {
+		return 0
+	}
+
+	paths := make([]string, 0, len(toolsByDocument))
+	for path := range toolsByDocument {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var all []*types.GeneratedTool
+	for _, path := range paths {
+		all = append(all, toolsByDocument[path]...)
+	}
+	unchanged := make(map[string][]*types.GeneratedTool)
+	for _, tool := range registry.GetAllTools() {
+		if tool.DocumentInfo != nil {
+			if _, refreshed := toolsByDocument[tool.DocumentInfo.FilePath]; refreshed {
+				continue
+			}
+			unchanged[tool.DocumentInfo.FilePath] = append(unchanged[tool.DocumentInfo.FilePath], tool)
+		}
+		all = append(all, tool)
+	}
+
+	_, dropped := LimitTools(all, generator, config)
+	if len(dropped) == 0 {
+		return 0
+	}
+
+	drop := make(map[*types.GeneratedTool]bool, len(dropped))
+	for _, tool := range dropped {
+		drop[tool] = true
+	}
+	keep := func(tools []*types.GeneratedTool) []*types.GeneratedTool {
+		kept := make([]*types.GeneratedTool, 0, len(tools))
+		for _, tool := range tools {
+			if !drop[tool] {
+				kept = append(kept, tool)
+			}
+		}
+		return kept
+	}
+
+	for _, path := range paths {
+		toolsByDocument[path] = keep(toolsByDocument[path])
+	}
+	for path, tools := range unchanged {
+		if kept := keep(tools); len(kept) < len(tools) {
+			toolsByDocument[path] = kept
+		}
+	}
+	return len(dropped)
+}
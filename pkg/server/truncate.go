@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// truncateText shortens a text longer than maxChars characters to its first and last
+// maxChars/2 characters around a marker naming the characters left out and the
+// original size. It returns the text unchanged, and false, when it is short enough
+// or maxChars is zero.
+func truncateText(text string, maxChars int) (string, bool) {
+	if maxChars <= 0 {
+		return text, false
+	}
+	length := utf8.RuneCountInString(text)
+	if length <= maxChars {
+		return text, false
+	}
+
+	runes := []rune(text)
+	half := maxChars / 2
+	head := string(runes[:half])
+	tail := string(runes[length-half:])
+	marker := fmt.Sprintf("\n\n[... %d characters omitted; the response is %d characters (%d bytes) long ...]\n\n",
+		length-2*half, length, len(text))
+	return head + marker + tail, true
+}
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	httpclient "swagger-docs-mcp/pkg/http"
+)
+
+// maxErrorMessage bounds the message taken from a plain text error body
+const maxErrorMessage = 500
+
+// requestIDHeaders are the headers upstream APIs commonly return the ID of a request in,
+// in order of preference
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"X-Amzn-Requestid",
+	"X-Amz-Request-Id",
+	"Request-Id",
+	"X-Transaction-Id",
+	"X-Trace-Id",
+	"Cf-Ray",
+}
+
+// retryHeaders are the headers upstream APIs tell clients when to retry in, reported
+// in the metadata of failed calls along with the request ID headers
+var retryHeaders = []string{
+	"Retry-After",
+	"RateLimit-Reset",
+	"X-RateLimit-Reset",
+	"X-RateLimit-Remaining",
+}
+
+// UpstreamError is the structured error a failed tool call returns in place of the raw
+// error body of the upstream API, so that clients can handle the errors of all APIs
+// alike
+type UpstreamError struct {
+	// Status is the HTTP status code of the upstream response
+	Status int `json:"status"`
+	// Code is the error code of the upstream API, when its body has one
+	Code string `json:"code,omitempty"`
+	// Message describes the error, from the body or else the status
+	Message string `json:"message"`
+	// Retriable reports whether the call may succeed when made again later
+	Retriable bool `json:"retriable"`
+	// RetryAfter is the Retry-After header of the response, if any
+	RetryAfter string `json:"retryAfter,omitempty"`
+	// RequestID identifies the request to the upstream API's operators
+	RequestID string `json:"requestId,omitempty"`
+	// Details is the decoded JSON error body
+	Details interface{} `json:"details,omitempty"`
+}
+
+// NormalizeUpstreamError maps the error response of an upstream API to a structured
+// error. The code and message are taken from the common error body shapes: RFC 7807
+// problem details, {"error": {"code", "message"}}, {"errors": [...]}, OAuth's
+// {"error", "error_description"}, {"code", "message"}, and {"fault": {"faultstring"}}.
+func NormalizeUpstreamError(response *httpclient.Response) *UpstreamError {
+	upstreamError := &UpstreamError{
+		Status:     response.StatusCode,
+		Retriable:  httpclient.IsRetryableStatus(response.StatusCode),
+		RetryAfter: headerValue(response.Headers, "Retry-After"),
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(response.Body, &body); err == nil {
+		upstreamError.Details = body
+		if object, ok := body.(map[string]interface{}); ok {
+			upstreamError.Code, upstreamError.Message = errorCodeAndMessage(object)
+			upstreamError.RequestID = bodyRequestID(object)
+		}
+	} else if text := strings.TrimSpace(string(response.Body)); text != "" {
+		if len(text) > maxErrorMessage {
+			text = text[:maxErrorMessage] + "..."
+		}
+		upstreamError.Message = text
+	}
+
+	if upstreamError.Message == "" {
+		upstreamError.Message = http.StatusText(response.StatusCode)
+	}
+	for _, name := range requestIDHeaders {
+		if requestID := headerValue(response.Headers, name); requestID != "" {
+			upstreamError.RequestID = requestID
+			break
+		}
+	}
+
+	return upstreamError
+}
+
+// ErrorResultText renders the structured error of an upstream error response as the
+// text of a tool result
+func ErrorResultText(response *httpclient.Response) string {
+	encoded, err := json.MarshalIndent(map[string]interface{}{"error": NormalizeUpstreamError(response)}, "", "  ")
+	if err != nil {
+		return string(response.Body)
+	}
+	return string(encoded)
+}
+
+// UpstreamMeta describes the upstream response of a tool call for the metadata of its
+// result, so that clients can decide whether and when to retry: the status, the retry
+// and request ID headers, the latency in milliseconds, and the number of attempts
+func UpstreamMeta(response *httpclient.Response) map[string]interface{} {
+	headers := make(map[string]string)
+	for _, name := range append(retryHeaders, requestIDHeaders...) {
+		if value := headerValue(response.Headers, name); value != "" {
+			headers[name] = value
+		}
+	}
+
+	meta := map[string]interface{}{
+		"status":    response.StatusCode,
+		"retriable": httpclient.IsRetryableStatus(response.StatusCode),
+	}
+	if len(headers) > 0 {
+		meta["headers"] = headers
+	}
+	// Mocked and replayed responses were not timed
+	if response.Attempts > 0 {
+		meta["attempts"] = response.Attempts
+		meta["latencyMs"] = float64(response.Duration.Microseconds()) / 1000
+	}
+	return meta
+}
+
+// FailureMeta returns the metadata of the result of a tool call whose request failed
+// without a response, under "upstream" the number of attempts and the time spent on
+// them, and under "execution" the same as for responses, without a status. It is nil
+// for errors raised before the request was sent.
+func FailureMeta(err error) map[string]interface{} {
+	var requestErr *httpclient.RequestError
+	if !errors.As(err, &requestErr) {
+		return nil
+	}
+	latencyMs := float64(requestErr.Duration.Microseconds()) / 1000
+	return map[string]interface{}{
+		"execution": map[string]interface{}{
+			"source":     httpclient.SourceUpstream,
+			"url":        requestErr.URL,
+			"cacheHit":   false,
+			"durationMs": latencyMs,
+			"attempts":   requestErr.Attempts,
+			"retries":    requestErr.Attempts - 1,
+		},
+		"upstream": map[string]interface{}{
+			"attempts":  requestErr.Attempts,
+			"latencyMs": latencyMs,
+			"retriable": true,
+		},
+	}
+}
+
+// errorCodeAndMessage finds the error code and message of a decoded JSON error body
+func errorCodeAndMessage(body map[string]interface{}) (string, string) {
+	// RFC 7807 problem details
+	if title := stringField(body, "title"); title != "" && (body["type"] != nil || body["detail"] != nil) {
+		message := title
+		if detail := stringField(body, "detail"); detail != "" {
+			message = title + ": " + detail
+		}
+		return stringField(body, "type"), message
+	}
+
+	switch nested := body["error"].(type) {
+	case map[string]interface{}:
+		return errorCodeAndMessage(nested)
+	case string:
+		// OAuth errors
+		if description := stringField(body, "error_description"); description != "" {
+			return nested, description
+		}
+		return stringField(body, "code"), nested
+	}
+
+	if errors, ok := body["errors"].([]interface{}); ok && len(errors) > 0 {
+		if first, ok := errors[0].(map[string]interface{}); ok {
+			code, message := errorCodeAndMessage(first)
+			if len(errors) > 1 && message != "" {
+				message = fmt.Sprintf("%s (and %d more errors)", message, len(errors)-1)
+			}
+			return code, message
+		}
+	}
+
+	if fault, ok := body["fault"].(map[string]interface{}); ok {
+		detail, _ := fault["detail"].(map[string]interface{})
+		return stringField(detail, "errorcode"), stringField(fault, "faultstring")
+	}
+
+	message := ""
+	for _, name := range []string{"message", "error_message", "errorMessage", "detail", "description"} {
+		if message = stringField(body, name); message != "" {
+			break
+		}
+	}
+	code := ""
+	for _, name := range []string{"code", "error_code", "errorCode"} {
+		if code = stringField(body, name); code != "" {
+			break
+		}
+	}
+	return code, message
+}
+
+// bodyRequestID finds a request ID in a decoded JSON error body, e.g. the
+// metadata.transaction_id of The Weather Company APIs
+func bodyRequestID(body map[string]interface{}) string {
+	for _, object := range []map[string]interface{}{body, objectField(body, "metadata"), objectField(body, "meta")} {
+		for _, name := range []string{"requestId", "request_id", "transaction_id", "transactionId", "traceId", "trace_id"} {
+			if requestID := stringField(object, name); requestID != "" {
+				return requestID
+			}
+		}
+	}
+	return ""
+}
+
+// stringField returns a string or number field of a decoded JSON object as a string
+func stringField(object map[string]interface{}, name string) string {
+	switch value := object[name].(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%v", value)
+	default:
+		return ""
+	}
+}
+
+// objectField returns an object field of a decoded JSON object, or nil
+func objectField(object map[string]interface{}, name string) map[string]interface{} {
+	nested, _ := object[name].(map[string]interface{})
+	return nested
+}
+
+// headerValue looks up a response header regardless of the case of its name
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// ToolStatsResourceURI addresses the resource that serves the tool usage report
+const ToolStatsResourceURI = "stats://tools"
+
+// toolUsageBucket is the usage store bucket of the tool usage counters
+const toolUsageBucket = "tools"
+
+// ToolUsage reports how often a tool has been called and how often it failed
+type ToolUsage struct {
+	Name            string     `json:"name"`
+	Calls           int        `json:"calls"`
+	Errors          int        `json:"errors"`
+	ErrorRate       float64    `json:"errorRate"`
+	AverageDuration float64    `json:"averageDurationMs"`
+	LastUsed        *time.Time `json:"lastUsed,omitempty"`
+	LastError       *time.Time `json:"lastError,omitempty"`
+	Registered      bool       `json:"registered"`
+}
+
+// ToolUsageReport summarizes tool usage across a registry
+type ToolUsageReport struct {
+	TotalTools  int         `json:"totalTools"`
+	UsedTools   int         `json:"usedTools"`
+	TotalCalls  int         `json:"totalCalls"`
+	TotalErrors int         `json:"totalErrors"`
+	ErrorRate   float64     `json:"errorRate"`
+	Tools       []ToolUsage `json:"tools"`
+}
+
+// toolUsage holds the running usage counters of one tool
+type toolUsage struct {
+	calls     int
+	errors    int
+	duration  time.Duration
+	lastUsed  time.Time
+	lastError time.Time
+}
+
+// storedToolUsage is the form the usage counters of a tool are persisted in
+type storedToolUsage struct {
+	Calls     int           `json:"calls"`
+	Errors    int           `json:"errors"`
+	Duration  time.Duration `json:"duration"`
+	LastUsed  time.Time     `json:"lastUsed"`
+	LastError time.Time     `json:"lastError"`
+}
+
+// SetUsageStore restores the usage counters persisted in a store, and persists those of
+// later calls to it
+func (r *ToolRegistry) SetUsageStore(store *UsageStore) error {
+	r.usageMutex.Lock()
+	defer r.usageMutex.Unlock()
+
+	r.usageStore = store
+	return store.Load(toolUsageBucket, func(name string, value []byte) error {
+		var stored storedToolUsage
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return fmt.Errorf("failed to decode usage of %s: %w", name, err)
+		}
+		if _, exists := r.usage[name]; !exists {
+			r.usage[name] = &toolUsage{
+				calls:     stored.Calls,
+				errors:    stored.Errors,
+				duration:  stored.Duration,
+				lastUsed:  stored.LastUsed,
+				lastError: stored.LastError,
+			}
+		}
+		return nil
+	})
+}
+
+// RecordToolCall records a completed call of a tool. Counters are kept by tool name,
+// so they survive a tool being regenerated by a refresh, and persisted to the usage
+// store if one is set.
+func (r *ToolRegistry) RecordToolCall(name string, duration time.Duration, failed bool) {
+	r.usageMutex.Lock()
+	defer r.usageMutex.Unlock()
+
+	usage, exists := r.usage[name]
+	if !exists {
+		usage = &toolUsage{}
+		r.usage[name] = usage
+	}
+
+	now := time.Now().UTC()
+	usage.calls++
+	usage.duration += duration
+	usage.lastUsed = now
+	if failed {
+		usage.errors++
+		usage.lastError = now
+	}
+
+	if r.usageStore != nil {
+		r.usageStore.Put(toolUsageBucket, name, storedToolUsage{
+			Calls:     usage.calls,
+			Errors:    usage.errors,
+			Duration:  usage.duration,
+			LastUsed:  usage.lastUsed,
+			LastError: usage.lastError,
+		})
+	}
+}
+
+// GetToolUsage returns the usage of a single tool
+func (r *ToolRegistry) GetToolUsage(name string) ToolUsage {
+	registered := r.HasTool(name)
+
+	r.usageMutex.Lock()
+	defer r.usageMutex.Unlock()
+
+	return r.usage[name].report(name, registered)
+}
+
+// UsageReport returns per-tool usage, most used first. Registered tools that were never
+// called are listed only when includeUnused is set; tools removed since they were
+// called are always listed.
+func (r *ToolRegistry) UsageReport(includeUnused bool) ToolUsageReport {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	r.usageMutex.Lock()
+	defer r.usageMutex.Unlock()
+
+	report := ToolUsageReport{
+		TotalTools: len(r.tools),
+		Tools:      make([]ToolUsage, 0, len(r.usage)),
+	}
+
+	for name, usage := range r.usage {
+		_, registered := r.tools[name]
+		if registered {
+			report.UsedTools++
+		}
+		report.TotalCalls += usage.calls
+		report.TotalErrors += usage.errors
+		report.Tools = append(report.Tools, usage.report(name, registered))
+	}
+
+	if includeUnused {
+		for name := range r.tools {
+			if _, used := r.usage[name]; !used {
+				report.Tools = append(report.Tools, ToolUsage{Name: name, Registered: true})
+			}
+		}
+	}
+
+	if report.TotalCalls > 0 {
+		report.ErrorRate = float64(report.TotalErrors) / float64(report.TotalCalls)
+	}
+
+	sort.Slice(report.Tools, func(i, j int) bool {
+		if report.Tools[i].Calls != report.Tools[j].Calls {
+			return report.Tools[i].Calls > report.Tools[j].Calls
+		}
+		return report.Tools[i].Name < report.Tools[j].Name
+	})
+
+	return report
+}
+
+// ToolStatsResource describes the tool usage report as an MCP resource
+func ToolStatsResource() types.MCPResource {
+	return types.MCPResource{
+		URI:         ToolStatsResourceURI,
+		Name:        "Tool Usage Statistics",
+		Description: "Call counts, error rates, and last-used timestamps of the generated tools",
+		MimeType:    "application/json",
+	}
+}
+
+// ToolStatsContent renders the usage report of a registry as the tool stats resource content
+func ToolStatsContent(registry *ToolRegistry) (types.MCPResourceContent, error) {
+	content, err := json.MarshalIndent(registry.UsageReport(false), "", "  ")
+	if err != nil {
+		return types.MCPResourceContent{}, fmt.Errorf("failed to marshal tool usage: %w", err)
+	}
+
+	return types.MCPResourceContent{
+		URI:      ToolStatsResourceURI,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// report converts running counters into a usage snapshot; a nil usage is an unused tool
+func (u *toolUsage) report(name string, registered bool) ToolUsage {
+	usage := ToolUsage{Name: name, Registered: registered}
+	if u == nil {
+		return usage
+	}
+
+	usage.Calls = u.calls
+	usage.Errors = u.errors
+	if u.calls > 0 {
+		usage.ErrorRate = float64(u.errors) / float64(u.calls)
+		usage.AverageDuration = float64(u.duration.Microseconds()) / float64(u.calls) / 1000
+	}
+	if !u.lastUsed.IsZero() {
+		lastUsed := u.lastUsed
+		usage.LastUsed = &lastUsed
+	}
+	if !u.lastError.IsZero() {
+		lastError := u.lastError
+		usage.LastError = &lastError
+	}
+	return usage
+}
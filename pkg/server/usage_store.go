@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// usageFlushInterval is how often buffered usage counters are written to the store
+const usageFlushInterval = 5 * time.Second
+
+// UsageStore persists usage counters to a bbolt file, so that the usage statistics and
+// quotas of a restarted server carry on from those of the previous run. Counters are
+// buffered and written at most every usageFlushInterval, and on Flush; the file is
+// only open while it is read or written.
+type UsageStore struct {
+	path   string
+	logger *utils.Logger
+
+	// pending holds the encoded counters to write by bucket and key; a nil value
+	// deletes the key
+	pending   map[string]map[string][]byte
+	lastFlush time.Time
+	flushing  bool
+	mutex     sync.Mutex
+
+	// writeMutex keeps flushes from opening the file at the same time
+	writeMutex sync.Mutex
+}
+
+// NewUsageStore creates a usage store at the given file path
+func NewUsageStore(path string, logger *utils.Logger) *UsageStore {
+	return &UsageStore{
+		path:    path,
+		logger:  logger.Child("usage-store"),
+		pending: make(map[string]map[string][]byte),
+	}
+}
+
+// Load calls fn with each counter stored in a bucket. A missing store loads nothing.
+func (s *UsageStore) Load(bucket string, fn func(key string, value []byte) error) error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: snapshotOpenTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open usage store: %w", err)
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		stored := tx.Bucket([]byte(bucket))
+		if stored == nil {
+			return nil
+		}
+		return stored.ForEach(func(key, value []byte) error {
+			return fn(string(key), value)
+		})
+	})
+}
+
+// Put buffers a counter to be written to a bucket
+func (s *UsageStore) Put(bucket, key string, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		s.logger.Warn("Failed to encode usage", zap.Error(err), zap.String("bucket", bucket), zap.String("key", key))
+		return
+	}
+	s.buffer(bucket, key, encoded)
+}
+
+// Delete buffers the removal of a counter from a bucket
+func (s *UsageStore) Delete(bucket, key string) {
+	s.buffer(bucket, key, nil)
+}
+
+// buffer queues a write of a counter, flushing the queued writes in the background
+// once the flush interval has passed
+func (s *UsageStore) buffer(bucket, key string, value []byte) {
+	s.mutex.Lock()
+	if s.pending[bucket] == nil {
+		s.pending[bucket] = make(map[string][]byte)
+	}
+	s.pending[bucket][key] = value
+	due := !s.flushing && time.Since(s.lastFlush) >= usageFlushInterval
+	if due {
+		s.flushing = true
+	}
+	s.mutex.Unlock()
+
+	if due {
+		go func() {
+			if err := s.Flush(); err != nil {
+				s.logger.Warn("Failed to save usage", zap.Error(err), zap.String("path", s.path))
+			}
+		}()
+	}
+}
+
+// Flush writes the buffered counters to the store. Counters that fail to be written
+// stay buffered for the next flush.
+func (s *UsageStore) Flush() error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.mutex.Lock()
+	pending := s.pending
+	s.pending = make(map[string]map[string][]byte)
+	s.lastFlush = time.Now()
+	s.flushing = false
+	s.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := s.write(pending); err != nil {
+		s.requeue(pending)
+		return err
+	}
+	return nil
+}
+
+// write stores counters in the store file
+func (s *UsageStore) write(pending map[string]map[string][]byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create usage store directory: %w", err)
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: snapshotOpenTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open usage store: %w", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for bucket, values := range pending {
+			stored, err := tx.CreateBucketIfNotExists([]byte(bucket))
+			if err != nil {
+				return err
+			}
+			for key, value := range values {
+				if value == nil {
+					err = stored.Delete([]byte(key))
+				} else {
+					err = stored.Put([]byte(key), value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save usage: %w", err)
+	}
+	return nil
+}
+
+// requeue buffers counters that failed to be written again, unless they were
+// updated since
+func (s *UsageStore) requeue(pending map[string]map[string][]byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for bucket, values := range pending {
+		if s.pending[bucket] == nil {
+			s.pending[bucket] = make(map[string][]byte)
+		}
+		for key, value := range values {
+			if _, updated := s.pending[bucket][key]; !updated {
+				s.pending[bucket][key] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// watchDebounce coalesces the burst of events an editor produces when saving a file
+const watchDebounce = 250 * time.Millisecond
+
+// DocumentWatcher reports changes to local swagger documents on disk
+type DocumentWatcher struct {
+	logger   *utils.Logger
+	onChange func(path string)
+}
+
+// NewDocumentWatcher creates a new document watcher
+func NewDocumentWatcher(logger *utils.Logger) *DocumentWatcher {
+	return &DocumentWatcher{
+		logger: logger.Child("watcher"),
+	}
+}
+
+// OnChange sets the callback invoked with the path of a document that changed
+func (w *DocumentWatcher) OnChange(fn func(path string)) {
+	w.onChange = fn
+}
+
+// Run watches the given document files until the context is cancelled. Parent
+// directories are watched rather than the files themselves so that editors which
+// save by renaming a temporary file over the original are still noticed. A path that
+// is a directory reports files created, changed, or removed in it as a change to the
+// directory.
+func (w *DocumentWatcher) Run(ctx context.Context, paths []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Events report cleaned paths; map them back to the paths documents were loaded from
+	watched := make(map[string]string, len(paths))
+	watchedDirectories := make(map[string]string)
+	directories := make(map[string]bool)
+	for _, path := range paths {
+		directory := filepath.Dir(path)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			directory = filepath.Clean(path)
+			watchedDirectories[directory] = path
+		} else {
+			watched[filepath.Clean(path)] = path
+		}
+		if directories[directory] {
+			continue
+		}
+		if err := watcher.Add(directory); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", directory, err)
+		}
+		directories[directory] = true
+	}
+
+	w.logger.Info("Watching swagger documents for changes",
+		zap.Int("documents", len(watched)),
+		zap.Int("watchedDirectories", len(watchedDirectories)),
+		zap.Int("directories", len(directories)))
+
+	var timersMutex sync.Mutex
+	timers := make(map[string]*time.Timer)
+	defer func() {
+		timersMutex.Lock()
+		defer timersMutex.Unlock()
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Warn("File watcher error", zap.Error(err))
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			path, exists := watched[filepath.Clean(event.Name)]
+			if exists {
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+			} else if path, exists = watchedDirectories[filepath.Dir(filepath.Clean(event.Name))]; !exists || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			timersMutex.Lock()
+			if timer, pending := timers[path]; pending {
+				timer.Reset(watchDebounce)
+			} else {
+				timers[path] = time.AfterFunc(watchDebounce, func() {
+					timersMutex.Lock()
+					delete(timers, path)
+					timersMutex.Unlock()
+
+					w.logger.Debug("Swagger document changed", zap.String("path", path))
+					if w.onChange != nil {
+						w.onChange(path)
+					}
+				})
+			}
+			timersMutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+	"swagger-docs-mcp/pkg/version"
+)
+
+const (
+	// webhookQueueSize bounds the events waiting to be posted to a webhook; events
+	// beyond it are dropped
+	webhookQueueSize = 1000
+	// webhookTimeout bounds each post of an event
+	webhookTimeout = 10 * time.Second
+	// webhookAttempts is how often an event is posted when the webhook cannot be
+	// reached or answers with a 429 or 5xx status
+	webhookAttempts = 3
+	// webhookDrainTimeout bounds how long Close waits for queued events to be posted
+	webhookDrainTimeout = 5 * time.Second
+	// maxEventError bounds the error message of a tool call event
+	maxEventError = 1024
+)
+
+// WebhookEvent is the JSON payload posted to webhooks for a tool call
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	ToolCallEvent
+}
+
+// ToolCallEvent describes a completed tool call to external systems
+type ToolCallEvent struct {
+	Transport  types.Transport        `json:"transport"`
+	ToolName   string                 `json:"toolName"`
+	Tenant     string                 `json:"tenant,omitempty"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	IsError    bool                   `json:"isError"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs float64                `json:"durationMs"`
+	ExecutedAt time.Time              `json:"executedAt"`
+}
+
+// ToolCall describes a completed tool call reported to webhooks
+type ToolCall struct {
+	Transport types.Transport
+	ToolName  string
+	Arguments map[string]interface{}
+	Duration  time.Duration
+	// Failed reports that the call failed or returned an error, and Error says why
+	Failed bool
+	Error  string
+}
+
+// Webhooks posts the events of tool calls to the configured webhooks in the
+// background, so that external systems can react to them without holding an SSE
+// connection open. Each webhook has its own queue, posted in order. A nil Webhooks
+// posts nothing.
+type Webhooks struct {
+	sinks           []*webhookSink
+	sensitiveFields []string
+	client          *http.Client
+	logger          *utils.Logger
+	closed          bool
+	mutex           sync.RWMutex
+	wg              sync.WaitGroup
+}
+
+// webhookSink is a webhook and the queue of events waiting to be posted to it
+type webhookSink struct {
+	config types.WebhookConfig
+	queue  chan WebhookEvent
+}
+
+// NewWebhooks starts posting to the configured webhooks, returning nil when there
+// are none
+func NewWebhooks(config *types.ResolvedConfig, logger *utils.Logger) *Webhooks {
+	if len(config.Server.Webhooks) == 0 {
+		return nil
+	}
+
+	w := &Webhooks{
+		sensitiveFields: config.Server.Events.SensitiveFields,
+		client:          &http.Client{Timeout: webhookTimeout},
+		logger:          logger.Child("webhooks"),
+	}
+	for _, webhook := range config.Server.Webhooks {
+		sink := &webhookSink{config: webhook, queue: make(chan WebhookEvent, webhookQueueSize)}
+		w.sinks = append(w.sinks, sink)
+		w.wg.Add(1)
+		go w.deliver(sink)
+	}
+	return w
+}
+
+// ToolCalled queues the tool_execution event of a completed tool call, and the
+// tool_error event of a failed one, for the webhooks subscribed to them. The
+// arguments are redacted like those of SSE events.
+func (w *Webhooks) ToolCalled(ctx context.Context, call ToolCall) {
+	if w == nil {
+		return
+	}
+
+	event := WebhookEvent{ToolCallEvent: newToolCallEvent(ctx, call, w.sensitiveFields)}
+	w.enqueue(event, types.WebhookToolExecution)
+	if call.Failed {
+		w.enqueue(event, types.WebhookToolError)
+	}
+}
+
+// enqueue queues an event of a type for the webhooks subscribed to it
+func (w *Webhooks) enqueue(event WebhookEvent, eventType string) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return
+	}
+
+	event.Type = eventType
+	for _, sink := range w.sinks {
+		if !sink.subscribes(eventType) {
+			continue
+		}
+		event.ID = uuid.New().String()
+		select {
+		case sink.queue <- event:
+		default:
+			w.logger.Warn("Dropping webhook event, the queue is full",
+				zap.String("url", sink.config.URL), zap.String("type", eventType), zap.String("toolName", event.ToolName))
+		}
+	}
+}
+
+// Close stops queueing events and waits a few seconds for the queued ones to be posted
+func (w *Webhooks) Close() {
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return
+	}
+	w.closed = true
+	for _, sink := range w.sinks {
+		close(sink.queue)
+	}
+	w.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(webhookDrainTimeout):
+		w.logger.Warn("Gave up waiting for webhook events to be posted")
+	}
+}
+
+// deliver posts the events queued for a webhook until its queue is closed
+func (w *Webhooks) deliver(sink *webhookSink) {
+	defer w.wg.Done()
+	for event := range sink.queue {
+		if err := w.post(sink.config, event); err != nil {
+			w.logger.Warn("Failed to post webhook event", zap.Error(err),
+				zap.String("url", sink.config.URL), zap.String("type", event.Type), zap.String("toolName", event.ToolName))
+		}
+	}
+}
+
+// post posts an event to a webhook, trying again with backoff when the webhook cannot
+// be reached or answers with a 429 or 5xx status
+func (w *Webhooks) post(config types.WebhookConfig, event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < webhookAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, config.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "swagger-docs-mcp/"+version.GetSemanticVersion())
+		req.Header.Set("X-Webhook-Event", event.Type)
+		for name, value := range config.Headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := w.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// subscribes reports whether a webhook receives events of a type
+func (s *webhookSink) subscribes(eventType string) bool {
+	if len(s.config.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range s.config.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// newToolCallEvent describes a tool call to external systems, with its arguments
+// redacted and its error message cut short
+func newToolCallEvent(ctx context.Context, call ToolCall, sensitiveFields []string) ToolCallEvent {
+	event := ToolCallEvent{
+		Transport:  call.Transport,
+		ToolName:   call.ToolName,
+		Arguments:  RedactArguments(call.Arguments, sensitiveFields),
+		IsError:    call.Failed,
+		Error:      call.Error,
+		DurationMs: float64(call.Duration.Microseconds()) / 1000,
+		ExecutedAt: time.Now().UTC(),
+	}
+	if len(event.Error) > maxEventError {
+		event.Error = event.Error[:maxEventError] + "...(truncated)"
+	}
+	if tenant := TenantFromContext(ctx); tenant != nil {
+		event.Tenant = tenant.Name
+	}
+	return event
+}
+
+// CompletedToolCall describes a tool call that completed with a result or an error
+func CompletedToolCall(transport types.Transport, toolName string, arguments map[string]interface{}, duration time.Duration, result types.MCPCallToolResult, err error) ToolCall {
+	call := ToolCall{Transport: transport, ToolName: toolName, Arguments: arguments, Duration: duration}
+	if err != nil {
+		call.Failed, call.Error = true, err.Error()
+	} else if result.IsError {
+		call.Failed, call.Error = true, resultErrorText(result)
+	}
+	return call
+}
+
+// resultErrorText returns the text of an error result of a tool call
+func resultErrorText(result types.MCPCallToolResult) string {
+	var text bytes.Buffer
+	for _, content := range result.Content {
+		if content.Text == "" {
+			continue
+		}
+		if text.Len() > 0 {
+			text.WriteString("\n")
+		}
+		text.WriteString(content.Text)
+	}
+	return text.String()
+}
@@ -0,0 +1,74 @@
+package sse
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// docsRenderers are the renderers /docs/{id} pages can be asked for with ?renderer=
+var docsRenderers = map[string]bool{
+	"swagger-ui": true,
+	"redoc":      true,
+}
+
+//go:embed ui/docs.html
+var docsPageSource string
+
+// docsPage renders a document with Swagger UI or Redoc, loaded from the assets CDN
+var docsPage = template.Must(template.New("docs").Parse(docsPageSource))
+
+// docsPageData fills the docs page template
+type docsPageData struct {
+	ID        string
+	RawURL    string
+	Renderer  string
+	AssetsURL string
+}
+
+// handleDocs handles GET /docs/{id} requests, serving a page that renders the raw
+// content of a document with Swagger UI, or with Redoc given ?renderer=redoc. The
+// page fetches the content from /documents/{id}/raw, which reports unknown documents.
+func (s *SSEServer) handleDocs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	renderer := r.URL.Query().Get("renderer")
+	if renderer == "" {
+		renderer = "swagger-ui"
+	}
+	if !docsRenderers[renderer] {
+		s.sendDocumentError(w, http.StatusBadRequest, fmt.Sprintf("Unknown renderer %s; use swagger-ui or redoc", renderer))
+		return
+	}
+
+	assetsURL := strings.TrimSuffix(s.config.Server.DocsAssetsURL, "/")
+	assetsOrigin := assetsURL
+	if parsed, err := url.Parse(assetsURL); err == nil {
+		assetsOrigin = parsed.Scheme + "://" + parsed.Host
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Scripts and styles only come from the page and the assets CDN, and the document
+	// is only fetched from this server
+	w.Header().Set("Content-Security-Policy", fmt.Sprintf(
+		"default-src 'self'; script-src 'unsafe-inline' %[1]s; style-src 'unsafe-inline' %[1]s; font-src data: %[1]s; img-src 'self' data: %[1]s; connect-src 'self' blob:; worker-src blob:; frame-ancestors 'none'",
+		assetsOrigin))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	err := docsPage.Execute(w, docsPageData{
+		ID:        id,
+		RawURL:    fmt.Sprintf("/documents/%s/raw", url.PathEscape(id)),
+		Renderer:  renderer,
+		AssetsURL: assetsURL,
+	})
+	if err != nil {
+		s.logger.Error("Failed to render docs page", zap.Error(err), zap.String("documentId", id))
+	}
+}
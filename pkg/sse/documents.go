@@ -0,0 +1,123 @@
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"swagger-docs-mcp/pkg/server"
+)
+
+// DocumentSummary describes a loaded swagger document in GET /documents responses
+type DocumentSummary struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Version       string     `json:"version"`
+	Specification string     `json:"specification,omitempty"`
+	FilePath      string     `json:"filePath"`
+	IsRemote      bool       `json:"isRemote"`
+	Format        string     `json:"format"`
+	Size          int        `json:"size"`
+	PathCount     int        `json:"pathCount"`
+	LastModified  *time.Time `json:"lastModified,omitempty"`
+	RawURL        string     `json:"rawUrl"`
+}
+
+// documentContentTypes maps the formats documents are parsed as to the content type
+// their raw content is served with
+var documentContentTypes = map[string]string{
+	"json":     "application/json",
+	"yaml":     "application/yaml",
+	"graphql":  "application/graphql",
+	"protoset": "application/octet-stream",
+}
+
+// handleListDocuments handles GET /documents requests
+func (s *SSEServer) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	stored := s.documents.All()
+	documents := make([]DocumentSummary, 0, len(stored))
+	for _, document := range stored {
+		documents = append(documents, s.documentSummary(document))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"documents": documents,
+		"count":     len(documents),
+	})
+}
+
+// documentSummary describes a loaded document
+func (s *SSEServer) documentSummary(document *server.StoredDocument) DocumentSummary {
+	id := s.resourceGenerator.DocumentID(document.Info)
+	return DocumentSummary{
+		ID:            id,
+		Title:         document.Info.Title,
+		Version:       document.Info.Version,
+		Specification: document.Info.Specification,
+		FilePath:      document.Info.FilePath,
+		IsRemote:      document.Info.IsRemote,
+		Format:        s.parser.DetectFormat(document.Info.FilePath, document.Info.Content),
+		Size:          len(document.Info.Content),
+		PathCount:     getPathCount(document.Document),
+		LastModified:  document.Info.LastModified,
+		RawURL:        fmt.Sprintf("/documents/%s/raw", url.PathEscape(id)),
+	}
+}
+
+// handleGetRawDocument handles GET /documents/{id}/raw requests, returning the content
+// a document was parsed from as it was loaded
+func (s *SSEServer) handleGetRawDocument(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var matches []*server.StoredDocument
+	for _, stored := range s.documents.All() {
+		if s.resourceGenerator.DocumentID(stored.Info) == id {
+			matches = append(matches, stored)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		s.sendDocumentError(w, http.StatusNotFound, fmt.Sprintf("Document not found: %s", id))
+		return
+	case len(matches) > 1:
+		// Documents with the same file name share an ID under the default naming
+		s.sendDocumentError(w, http.StatusConflict, fmt.Sprintf(
+			"Document ID %s matches %d documents; use resources.documentNaming file-name-hash or resources.documentIds to tell them apart",
+			id, len(matches)))
+		return
+	}
+
+	info := matches[0].Info
+	if len(info.Content) == 0 {
+		s.sendDocumentError(w, http.StatusNotFound, fmt.Sprintf("Content of document %s was not kept", id))
+		return
+	}
+
+	contentType, ok := documentContentTypes[s.parser.DetectFormat(info.FilePath, info.Content)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if info.LastModified != nil {
+		w.Header().Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(info.Content)
+}
+
+// sendDocumentError writes a JSON error response for the document endpoints
+func (s *SSEServer) sendDocumentError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": message,
+		"code":  status,
+	})
+}
@@ -0,0 +1,51 @@
+package sse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// sendJSONWithETag writes a JSON response tagged with a hash of its body, or 304 Not
+// Modified without the body when the request's If-None-Match already names that tag.
+// The body is derived from the registries, so the tag changes exactly when they do.
+func (s *SSEServer) sendJSONWithETag(w http.ResponseWriter, r *http.Request, value interface{}) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(value); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to encode response",
+			"code":  500,
+		})
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	// Polling clients must revalidate rather than reuse a stale copy
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// etagMatches reports whether an If-None-Match header names an entity tag, comparing
+// weakly as RFC 9110 requires
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
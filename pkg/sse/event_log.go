@@ -0,0 +1,462 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+const (
+	// defaultHistoryLimit and maxHistoryLimit bound the events of one /events/history
+	// response
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+	// eventLogAgeCheckInterval is how often at most the log is compacted to drop
+	// events past their maximum age
+	eventLogAgeCheckInterval = time.Minute
+)
+
+// loggedEvent is an event as it is stored in the event log, one JSON object per line
+type loggedEvent struct {
+	Seq  int64     `json:"seq"`
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Tool names the tool an event is about, so that replays only send it to the
+	// clients whose tenant is entitled to the tool
+	Tool string          `json:"tool,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// HistoryEvent is an event reported by GET /events/history
+type HistoryEvent struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// eventLogEntry indexes an event of the log file: the event without its data, and the
+// offset and length of its line
+type eventLogEntry struct {
+	loggedEvent
+	offset int64
+	length int
+}
+
+// eventLog appends the events broadcast to SSE clients to a file, one JSON object per
+// line, so that clients can backfill the events they missed while disconnected, also
+// across restarts. The events are indexed in memory, so that only the lines of the
+// events a query returns are read back. The file is compacted in the background to
+// the retained events once it holds a tenth more events than retained, or events
+// past their maximum age.
+type eventLog struct {
+	path           string
+	config         types.EventLogConfig
+	logger         *utils.Logger
+	file           *os.File
+	size           int64
+	entries        []eventLogEntry
+	nextSeq        int64
+	lastCompaction time.Time
+	compactions    chan struct{}
+	done           chan struct{}
+	wg             sync.WaitGroup
+	mutex          sync.Mutex
+}
+
+// openEventLog opens the event log of a configuration, dropping the events it no
+// longer retains
+func openEventLog(config types.EventLogConfig, logger *utils.Logger) (*eventLog, error) {
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event log directory: %w", err)
+	}
+
+	l := &eventLog{
+		path:        config.Path,
+		config:      config,
+		logger:      logger,
+		nextSeq:     1,
+		compactions: make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	var err error
+	l.file, err = os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	if err := l.index(); err != nil {
+		l.file.Close()
+		return nil, err
+	}
+	if len(l.entries) > 0 {
+		l.nextSeq = l.entries[len(l.entries)-1].Seq + 1
+	}
+	// Compacting on open also drops a line cut short by a crash, which the next
+	// event would otherwise be appended to
+	if err := l.compact(time.Now()); err != nil {
+		l.file.Close()
+		return nil, err
+	}
+
+	l.wg.Add(1)
+	go l.compactInBackground()
+	return l, nil
+}
+
+// append adds an event to the log, about a tool unless tool is empty
+func (l *eventLog) append(event SSEEvent, tool string) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now().UTC()
+	entry := loggedEvent{Seq: l.nextSeq, ID: event.ID, Type: event.Type, Time: now, Tool: tool, Data: data}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+	entry.Data = nil
+	l.entries = append(l.entries, eventLogEntry{loggedEvent: entry, offset: l.size, length: len(line)})
+	l.size += int64(len(line)) + 1
+	l.nextSeq++
+
+	if l.compactionDue(now) {
+		select {
+		case l.compactions <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// query returns up to limit retained events after an event ID or a time, or from the
+// oldest when since is empty, that match include. include is given the events
+// without their data, which is only read for the events returned. found is false
+// when since names an event the log no longer holds; more reports events beyond the
+// limit.
+func (l *eventLog) query(since string, limit int, include func(loggedEvent) bool) (events []loggedEvent, found bool, more bool, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stored := l.retained(l.entries, time.Now())
+
+	start := 0
+	found = true
+	if since != "" {
+		if after, parseErr := time.Parse(time.RFC3339Nano, since); parseErr == nil {
+			start = sort.Search(len(stored), func(i int) bool {
+				return stored[i].Time.After(after)
+			})
+		} else {
+			found = false
+			for i := len(stored) - 1; i >= 0; i-- {
+				if stored[i].ID == since {
+					start, found = i+1, true
+					break
+				}
+			}
+			if !found {
+				return nil, false, false, nil
+			}
+		}
+	}
+
+	for _, entry := range stored[start:] {
+		if !include(entry.loggedEvent) {
+			continue
+		}
+		if limit > 0 && len(events) == limit {
+			more = true
+			break
+		}
+		event, err := l.load(entry)
+		if err != nil {
+			return nil, false, false, err
+		}
+		events = append(events, event)
+	}
+	return events, found, more, nil
+}
+
+// close stops the background compaction and closes the log file
+func (l *eventLog) close() error {
+	close(l.done)
+	l.wg.Wait()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
+
+// index reads the index of the events of the log file, oldest first. Lines that
+// cannot be decoded, such as one cut short by a crash, are skipped.
+func (l *eventLog) index() error {
+	reader := bufio.NewReaderSize(io.NewSectionReader(l.file, 0, 1<<62), 64*1024)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			length := len(bytes.TrimSuffix(line, []byte("\n")))
+			var event loggedEvent
+			if json.Unmarshal(line[:length], &event) == nil {
+				event.Data = nil
+				l.entries = append(l.entries, eventLogEntry{loggedEvent: event, offset: offset, length: length})
+			}
+			offset += int64(len(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read event log: %w", err)
+		}
+	}
+	l.size = offset
+	return nil
+}
+
+// load reads an indexed event of the log file with its data
+func (l *eventLog) load(entry eventLogEntry) (loggedEvent, error) {
+	line := make([]byte, entry.length)
+	if _, err := l.file.ReadAt(line, entry.offset); err != nil {
+		return loggedEvent{}, fmt.Errorf("failed to read event log: %w", err)
+	}
+	var event loggedEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		return loggedEvent{}, fmt.Errorf("failed to decode event %s: %w", entry.ID, err)
+	}
+	return event, nil
+}
+
+// retained returns the events the log retains at a time: the latest MaxEvents events
+// no older than MaxAge
+func (l *eventLog) retained(entries []eventLogEntry, now time.Time) []eventLogEntry {
+	if l.config.MaxAge > 0 {
+		cutoff := now.Add(-l.config.MaxAge)
+		start := sort.Search(len(entries), func(i int) bool {
+			return !entries[i].Time.Before(cutoff)
+		})
+		entries = entries[start:]
+	}
+	if l.config.MaxEvents > 0 && len(entries) > l.config.MaxEvents {
+		entries = entries[len(entries)-l.config.MaxEvents:]
+	}
+	return entries
+}
+
+// compactionDue reports whether the log holds enough events it no longer retains to
+// be compacted
+func (l *eventLog) compactionDue(now time.Time) bool {
+	if len(l.entries) == 0 {
+		return false
+	}
+	if l.config.MaxEvents > 0 && len(l.entries) > l.config.MaxEvents+l.config.MaxEvents/10 {
+		return true
+	}
+	return l.config.MaxAge > 0 && now.Sub(l.entries[0].Time) > l.config.MaxAge && now.Sub(l.lastCompaction) >= eventLogAgeCheckInterval
+}
+
+// compactInBackground compacts the log whenever append finds a compaction due, until
+// the log is closed
+func (l *eventLog) compactInBackground() {
+	defer l.wg.Done()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-l.compactions:
+			l.mutex.Lock()
+			err := l.compact(time.Now())
+			l.mutex.Unlock()
+			if err != nil {
+				l.logger.Error("Failed to compact event log", zap.Error(err), zap.String("path", l.path))
+			}
+		}
+	}
+}
+
+// compact replaces the log file with the lines of the events it retains and reopens
+// it for appending. The caller holds the mutex, except while opening the log.
+func (l *eventLog) compact(now time.Time) error {
+	entries := l.retained(l.entries, now)
+
+	temporary := l.path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	compacted := make([]eventLogEntry, 0, len(entries))
+	var size int64
+	for _, entry := range entries {
+		line := make([]byte, entry.length)
+		if _, err := l.file.ReadAt(line, entry.offset); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to compact event log: %w", err)
+		}
+		writer.Write(append(line, '\n'))
+		entry.offset = size
+		compacted = append(compacted, entry)
+		size += int64(entry.length) + 1
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+
+	// The log is closed first, as Windows cannot replace an open file
+	l.file.Close()
+	renameErr := os.Rename(temporary, l.path)
+	l.file, err = os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	if renameErr != nil {
+		// The log file was kept, so its index still holds
+		return fmt.Errorf("failed to compact event log: %w", renameErr)
+	}
+	l.entries = compacted
+	l.size = size
+	l.lastCompaction = now
+	return nil
+}
+
+// logEvent appends a broadcast event to the event log, if one is configured
+func (s *SSEServer) logEvent(event SSEEvent, tool string) {
+	if s.eventLog == nil {
+		return
+	}
+	if err := s.eventLog.append(event, tool); err != nil {
+		s.logger.Error("Failed to log event", zap.Error(err), zap.String("type", event.Type))
+	}
+}
+
+// eventVisible reports whether a logged event may be sent to a client of a tenant:
+// events about a tool only when the tenant is entitled to the tool
+func (s *SSEServer) eventVisible(event loggedEvent, tenant *server.Tenant) bool {
+	if event.Tool == "" || tenant == nil {
+		return true
+	}
+	tool := s.toolRegistry.GetTool(event.Tool)
+	return tool != nil && tenant.Allows(tool)
+}
+
+// replayEvents sends a reconnecting client the logged events after the last one it
+// received, named by its Last-Event-ID header
+func (s *SSEServer) replayEvents(client *SSEClient, lastEventID string) {
+	tenant := server.TenantFromContext(client.Context)
+	events, found, _, err := s.eventLog.query(lastEventID, 0, func(event loggedEvent) bool {
+		return s.eventVisible(event, tenant)
+	})
+	if err != nil {
+		s.logger.Error("Failed to replay events", zap.Error(err), zap.String("clientID", client.ID))
+		return
+	}
+	if !found {
+		s.logger.Debug("Last event of reconnecting client is not in the event log", zap.String("clientID", client.ID), zap.String("lastEventID", lastEventID))
+		return
+	}
+	for _, event := range events {
+		s.sendEventToClient(client, SSEEvent{Type: event.Type, Data: event.Data, ID: event.ID})
+	}
+}
+
+// handleEventHistory handles GET /events/history?since=&limit=&type= requests,
+// reporting the logged events after an event ID or an RFC 3339 time so that clients
+// can backfill the events they missed
+func (s *SSEServer) handleEventHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.eventLog == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "The event log is disabled",
+			"code":  404,
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultHistoryLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "limit must be a positive number",
+				"code":  400,
+			})
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	eventTypes := make(map[string]bool)
+	for _, eventType := range strings.Split(query.Get("type"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes[eventType] = true
+		}
+	}
+
+	tenant := server.TenantFromContext(r.Context())
+	since := query.Get("since")
+	events, found, more, err := s.eventLog.query(since, limit, func(event loggedEvent) bool {
+		return (len(eventTypes) == 0 || eventTypes[event.Type]) && s.eventVisible(event, tenant)
+	})
+	if err != nil {
+		s.logger.Error("Failed to read event log", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to read the event log",
+			"code":  500,
+		})
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusGone)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": fmt.Sprintf("Event %s is no longer in the event log; fetch the current state instead", since),
+			"code":  410,
+		})
+		return
+	}
+
+	history := make([]HistoryEvent, len(events))
+	for i, event := range events {
+		history[i] = HistoryEvent{ID: event.ID, Type: event.Type, Time: event.Time, Data: event.Data}
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events":  history,
+		"hasMore": more,
+	})
+}
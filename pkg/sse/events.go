@@ -0,0 +1,30 @@
+package sse
+
+import (
+	"encoding/json"
+	"time"
+
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// toolExecutionEvent builds the tool_execution event of a call to broadcast to every
+// client: its arguments with sensitive fields redacted, and its result unless it is
+// larger than the configured maximum
+func (s *SSEServer) toolExecutionEvent(toolName string, arguments map[string]interface{}, result types.MCPCallToolResult) ToolExecutionEvent {
+	event := ToolExecutionEvent{
+		ToolName:   toolName,
+		Arguments:  server.RedactArguments(arguments, s.config.Server.Events.SensitiveFields),
+		Result:     result,
+		ExecutedAt: time.Now().UTC(),
+	}
+
+	if maxSize := s.config.Server.Events.MaxResultSize; maxSize > 0 {
+		if encoded, err := json.Marshal(result); err == nil && len(encoded) > maxSize {
+			event.Result = types.MCPCallToolResult{Content: []types.MCPContent{}, IsError: result.IsError}
+			event.ResultOmitted = true
+			event.ResultSize = len(encoded)
+		}
+	}
+	return event
+}
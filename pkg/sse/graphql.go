@@ -0,0 +1,715 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	graphqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// graphqlMaxDepth bounds how deeply the fields of a query nest, so that queries
+// following tools to their documents and back cannot grow without limit
+const graphqlMaxDepth = 12
+
+// catalogSchemaSource is the schema of the read-only GraphQL API over the catalog
+const catalogSchemaSource = `
+"Any JSON value"
+scalar JSON
+
+"An RFC 3339 timestamp"
+scalar Time
+
+type Query {
+  "Tools matching every given criterion, ordered by name"
+  tools(
+    "Case-insensitive text the name or description contains"
+    search: String
+    "ID of the document the tools were generated from"
+    document: String
+    "Tag of the operation"
+    tag: String
+    "HTTP method of the operation"
+    method: String
+    "The criteria of the dynamic filters of GET /tools"
+    filter: ToolFilter
+    first: Int
+    offset: Int = 0
+  ): [Tool!]!
+  tool(name: String!): Tool
+  "Number of tools matching the same criteria as tools"
+  toolCount(search: String, document: String, tag: String, method: String, filter: ToolFilter): Int!
+  "Loaded documents, ordered by file path or URL"
+  documents(search: String, specification: String, remote: Boolean): [Document!]!
+  document(id: String!): Document
+  prompts(search: String, category: String, tag: String): [Prompt!]!
+  prompt(name: String!): Prompt
+  resources(search: String, mimeType: String): [Resource!]!
+  "Tool usage statistics, as reported by GET /stats/tools"
+  usage(unused: Boolean = false): UsageReport!
+}
+
+input ToolFilter {
+  packageIds: [String!]
+  twcDomains: [String!]
+  twcPortfolios: [String!]
+  twcGeographies: [String!]
+  custom: [String!]
+  "key=value pairs, or keys, of vendor extensions"
+  metadata: [String!]
+}
+
+type Tool {
+  name: String!
+  description: String!
+  method: String
+  path: String
+  operationId: String
+  tags: [String!]!
+  deprecated: Boolean!
+  "Whether the tool chains other tools"
+  composite: Boolean!
+  document: Document
+  inputSchema: JSON
+  metadata: JSON
+  usage: ToolUsage!
+}
+
+type Document {
+  id: String!
+  title: String!
+  version: String!
+  specification: String
+  filePath: String!
+  isRemote: Boolean!
+  format: String!
+  size: Int!
+  pathCount: Int!
+  lastModified: Time
+  rawUrl: String!
+  tools(first: Int, offset: Int = 0): [Tool!]!
+}
+
+type Prompt {
+  name: String!
+  description: String!
+  category: String
+  tags: [String!]!
+  arguments: [PromptArgument!]!
+  document: Document
+}
+
+type PromptArgument {
+  name: String!
+  description: String!
+  required: Boolean!
+}
+
+type Resource {
+  uri: String!
+  name: String!
+  description: String
+  mimeType: String
+}
+
+type UsageReport {
+  totalTools: Int!
+  usedTools: Int!
+  totalCalls: Int!
+  totalErrors: Int!
+  errorRate: Float!
+  tools: [ToolUsage!]!
+}
+
+type ToolUsage {
+  name: String!
+  calls: Int!
+  errors: Int!
+  errorRate: Float!
+  averageDurationMs: Float!
+  lastUsed: Time
+  lastError: Time
+  registered: Boolean!
+}
+`
+
+// graphqlRequest is a GraphQL query sent over HTTP
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphqlHandler returns the handler of GET and POST /graphql requests, answering
+// read-only queries over the tools, documents, prompts, resources, and usage
+// statistics. Queries are sent as ?query=&operationName=&variables= or as a JSON body.
+func (s *SSEServer) graphqlHandler() http.HandlerFunc {
+	schema := graphql.MustParseSchema(catalogSchemaSource, &graphqlQuery{s: s},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(graphqlMaxDepth),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var request graphqlRequest
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			request.Query = query.Get("query")
+			request.OperationName = query.Get("operationName")
+			if variables := query.Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+					s.sendGraphQLError(w, "variables must be a JSON object")
+					return
+				}
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			s.sendGraphQLError(w, "Invalid request body")
+			return
+		}
+		if request.Query == "" {
+			s.sendGraphQLError(w, "query is required")
+			return
+		}
+
+		response := schema.Exec(r.Context(), request.Query, request.OperationName, request.Variables)
+
+		// Queries that fail validation have no data, and are bad requests
+		if response.Data == nil && len(response.Errors) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// sendGraphQLError writes a GraphQL response of a request that could not be read
+func (s *SSEServer) sendGraphQLError(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(graphql.Response{Errors: []*graphqlerrors.QueryError{graphqlerrors.Errorf("%s", message)}})
+}
+
+// graphqlJSON is a value of the JSON scalar
+type graphqlJSON struct {
+	value interface{}
+}
+
+// ImplementsGraphQLType reports that the type is the JSON scalar
+func (graphqlJSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL takes any input value as is
+func (j *graphqlJSON) UnmarshalGraphQL(input interface{}) error {
+	j.value = input
+	return nil
+}
+
+// MarshalJSON writes the value as JSON
+func (j graphqlJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.value)
+}
+
+// newGraphQLJSON returns a JSON scalar of an object, or nil when there is none
+func newGraphQLJSON(value map[string]interface{}) *graphqlJSON {
+	if value == nil {
+		return nil
+	}
+	return &graphqlJSON{value: value}
+}
+
+// graphqlTime returns a Time scalar, or nil when there is no time
+func graphqlTime(value *time.Time) *graphql.Time {
+	if value == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *value}
+}
+
+// optionalString returns a nullable String, nil when empty
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// stringValue returns the value of an optional String argument, "" when not set
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// graphqlPage returns the bounds of the page of a list of a length selected by the
+// offset and first arguments, up to first items from offset
+func graphqlPage(length int, first *int32, offset int32) (int, int, error) {
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+	start := int(offset)
+	if start > length {
+		start = length
+	}
+	end := length
+	if first != nil && *first >= 0 && start+int(*first) < end {
+		end = start + int(*first)
+	}
+	return start, end, nil
+}
+
+// graphqlQuery resolves the fields of the Query type. Clients of a tenant only see
+// the tenant's tools.
+type graphqlQuery struct {
+	s *SSEServer
+}
+
+// graphqlToolCriteria holds the criteria of the tools and toolCount fields
+type graphqlToolCriteria struct {
+	Search   *string
+	Document *string
+	Tag      *string
+	Method   *string
+	Filter   *graphqlToolFilter
+}
+
+// graphqlToolFilter is the ToolFilter input, the dynamic filters of GET /tools
+type graphqlToolFilter struct {
+	PackageIDs     *[]string
+	TWCDomains     *[]string
+	TWCPortfolios  *[]string
+	TWCGeographies *[]string
+	Custom         *[]string
+	Metadata       *[]string
+}
+
+// toolFilter returns the tool filter of the input
+func (f *graphqlToolFilter) toolFilter() server.ToolFilter {
+	if f == nil {
+		return server.ToolFilter{}
+	}
+	value := func(list *[]string) []string {
+		if list == nil {
+			return nil
+		}
+		return *list
+	}
+	return server.ToolFilter{
+		PackageIDs:     value(f.PackageIDs),
+		TWCDomains:     value(f.TWCDomains),
+		TWCPortfolios:  value(f.TWCPortfolios),
+		TWCGeographies: value(f.TWCGeographies),
+		Custom:         value(f.Custom),
+		Metadata:       value(f.Metadata),
+	}
+}
+
+// Tools resolves the tools matching the criteria, ordered by name
+func (q *graphqlQuery) Tools(ctx context.Context, args struct {
+	graphqlToolCriteria
+	First  *int32
+	Offset int32
+}) ([]*graphqlTool, error) {
+	tenant := server.TenantFromContext(ctx)
+	tools := q.s.graphqlTools(tenant, args.graphqlToolCriteria)
+	start, end, err := graphqlPage(len(tools), args.First, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return q.s.graphqlToolObjects(tools[start:end], tenant), nil
+}
+
+// Tool resolves a tool by name
+func (q *graphqlQuery) Tool(ctx context.Context, args struct{ Name string }) *graphqlTool {
+	tenant := server.TenantFromContext(ctx)
+	tool := q.s.toolRegistry.GetTool(args.Name)
+	if tool == nil {
+		tool = q.s.executor.CompositeTool(args.Name)
+	}
+	if tool == nil || !tenant.Allows(tool) {
+		return nil
+	}
+	return &graphqlTool{s: q.s, tool: tool, tenant: tenant}
+}
+
+// ToolCount resolves the number of tools matching the criteria
+func (q *graphqlQuery) ToolCount(ctx context.Context, args graphqlToolCriteria) int32 {
+	return int32(len(q.s.graphqlTools(server.TenantFromContext(ctx), args)))
+}
+
+// Documents resolves the loaded documents, ordered by file path or URL
+func (q *graphqlQuery) Documents(ctx context.Context, args struct {
+	Search        *string
+	Specification *string
+	Remote        *bool
+}) []*graphqlDocument {
+	tenant := server.TenantFromContext(ctx)
+	search := strings.ToLower(stringValue(args.Search))
+	specification := stringValue(args.Specification)
+	stored := q.s.documents.All()
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].Info.FilePath < stored[j].Info.FilePath
+	})
+
+	documents := []*graphqlDocument{}
+	for _, document := range stored {
+		info := document.Info
+		if search != "" && !strings.Contains(strings.ToLower(info.Title+" "+info.FilePath), search) {
+			continue
+		}
+		if specification != "" && info.Specification != specification {
+			continue
+		}
+		if args.Remote != nil && info.IsRemote != *args.Remote {
+			continue
+		}
+		documents = append(documents, q.s.graphqlDocument(document, tenant))
+	}
+	return documents
+}
+
+// Document resolves a document by ID
+func (q *graphqlQuery) Document(ctx context.Context, args struct{ ID string }) *graphqlDocument {
+	for _, document := range q.s.documents.All() {
+		if q.s.resourceGenerator.DocumentID(document.Info) == args.ID {
+			return q.s.graphqlDocument(document, server.TenantFromContext(ctx))
+		}
+	}
+	return nil
+}
+
+// Prompts resolves the prompts matching the criteria
+func (q *graphqlQuery) Prompts(ctx context.Context, args struct {
+	Search   *string
+	Category *string
+	Tag      *string
+}) []*graphqlPrompt {
+	tenant := server.TenantFromContext(ctx)
+	search := strings.ToLower(stringValue(args.Search))
+	category, tag := stringValue(args.Category), stringValue(args.Tag)
+
+	prompts := []*graphqlPrompt{}
+	for _, prompt := range q.s.promptRegistry.GetAllPrompts() {
+		if search != "" && !strings.Contains(strings.ToLower(prompt.Name+" "+prompt.Description), search) {
+			continue
+		}
+		if category != "" && string(prompt.Category) != category {
+			continue
+		}
+		if tag != "" && !containsString(prompt.Tags, tag) {
+			continue
+		}
+		prompts = append(prompts, &graphqlPrompt{s: q.s, prompt: prompt, tenant: tenant})
+	}
+	return prompts
+}
+
+// Prompt resolves a prompt by name
+func (q *graphqlQuery) Prompt(ctx context.Context, args struct{ Name string }) *graphqlPrompt {
+	if prompt := q.s.promptRegistry.GetPrompt(args.Name); prompt != nil {
+		return &graphqlPrompt{s: q.s, prompt: prompt, tenant: server.TenantFromContext(ctx)}
+	}
+	return nil
+}
+
+// Resources resolves the resources matching the criteria
+func (q *graphqlQuery) Resources(args struct {
+	Search   *string
+	MimeType *string
+}) []*graphqlResource {
+	search := strings.ToLower(stringValue(args.Search))
+	mimeType := stringValue(args.MimeType)
+
+	var listed []types.MCPResource
+	for _, resource := range q.s.resourceRegistry.GetAllResources() {
+		listed = append(listed, types.MCPResource{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MimeType:    resource.MimeType,
+		})
+	}
+	if q.s.config.Resources.Enabled {
+		listed = append(listed, server.ToolStatsResource(), server.SourceStatusResource())
+	}
+
+	resources := []*graphqlResource{}
+	for _, resource := range listed {
+		if search != "" && !strings.Contains(strings.ToLower(resource.URI+" "+resource.Name+" "+resource.Description), search) {
+			continue
+		}
+		if mimeType != "" && resource.MimeType != mimeType {
+			continue
+		}
+		resources = append(resources, &graphqlResource{resource: resource})
+	}
+	return resources
+}
+
+// Usage resolves the tool usage statistics, as reported by GET /stats/tools
+func (q *graphqlQuery) Usage(args struct{ Unused bool }) *graphqlUsageReport {
+	return &graphqlUsageReport{report: q.s.toolRegistry.UsageReport(args.Unused)}
+}
+
+// graphqlTools returns the generated and composite tools of a tenant matching the
+// criteria of the tools field, ordered by name
+func (s *SSEServer) graphqlTools(tenant *server.Tenant, criteria graphqlToolCriteria) []*types.GeneratedTool {
+	tools := s.toolRegistry.GetAllTools()
+	// Generated tools take precedence over composite tools of the same name
+	for _, composite := range s.executor.CompositeTools() {
+		if !s.toolRegistry.HasTool(composite.Name) {
+			tools = append(tools, composite)
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	filter := criteria.Filter.toolFilter()
+	search := strings.ToLower(stringValue(criteria.Search))
+	document, tag := stringValue(criteria.Document), stringValue(criteria.Tag)
+	method := strings.ToUpper(stringValue(criteria.Method))
+
+	matching := []*types.GeneratedTool{}
+	for _, tool := range tools {
+		if !tenant.Allows(tool) || !filter.Matches(tool) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(tool.Name+" "+tool.Description), search) {
+			continue
+		}
+		if document != "" && (tool.DocumentInfo == nil || s.resourceGenerator.DocumentID(tool.DocumentInfo) != document) {
+			continue
+		}
+		if tag != "" && (tool.Endpoint == nil || !containsString(tool.Endpoint.Tags, tag)) {
+			continue
+		}
+		if method != "" && (tool.Endpoint == nil || strings.ToUpper(tool.Endpoint.Method) != method) {
+			continue
+		}
+		matching = append(matching, tool)
+	}
+	return matching
+}
+
+// graphqlToolObjects returns the Tool objects of tools
+func (s *SSEServer) graphqlToolObjects(tools []*types.GeneratedTool, tenant *server.Tenant) []*graphqlTool {
+	objects := make([]*graphqlTool, len(tools))
+	for i, tool := range tools {
+		objects[i] = &graphqlTool{s: s, tool: tool, tenant: tenant}
+	}
+	return objects
+}
+
+// graphqlDocument returns the Document object of a loaded document
+func (s *SSEServer) graphqlDocument(document *server.StoredDocument, tenant *server.Tenant) *graphqlDocument {
+	return &graphqlDocument{s: s, summary: s.documentSummary(document), tenant: tenant}
+}
+
+// graphqlTool resolves the fields of a Tool
+type graphqlTool struct {
+	s      *SSEServer
+	tool   *types.GeneratedTool
+	tenant *server.Tenant
+}
+
+func (t *graphqlTool) Name() string        { return t.tool.Name }
+func (t *graphqlTool) Description() string { return t.tool.Description }
+func (t *graphqlTool) Composite() bool     { return t.tool.Composite != nil }
+
+func (t *graphqlTool) Method() *string {
+	if t.tool.Endpoint == nil {
+		return nil
+	}
+	method := strings.ToUpper(t.tool.Endpoint.Method)
+	return &method
+}
+
+func (t *graphqlTool) Path() *string {
+	if t.tool.Endpoint == nil {
+		return nil
+	}
+	return &t.tool.Endpoint.Path
+}
+
+func (t *graphqlTool) OperationID() *string {
+	if t.tool.Endpoint == nil {
+		return nil
+	}
+	return optionalString(t.tool.Endpoint.OperationID)
+}
+
+func (t *graphqlTool) Tags() []string {
+	if t.tool.Endpoint == nil || t.tool.Endpoint.Tags == nil {
+		return []string{}
+	}
+	return t.tool.Endpoint.Tags
+}
+
+func (t *graphqlTool) Deprecated() bool {
+	return t.tool.Endpoint != nil && t.tool.Endpoint.Deprecated
+}
+
+// Document resolves the document the tool was generated from, if any
+func (t *graphqlTool) Document() *graphqlDocument {
+	if t.tool.DocumentInfo == nil {
+		return nil
+	}
+	if document := t.s.documents.Get(t.tool.DocumentInfo.FilePath); document != nil {
+		return t.s.graphqlDocument(document, t.tenant)
+	}
+	return nil
+}
+
+// InputSchema resolves the input schema, which lazy schemas only generate when selected
+func (t *graphqlTool) InputSchema() *graphqlJSON { return newGraphQLJSON(t.tool.Schema()) }
+func (t *graphqlTool) Metadata() *graphqlJSON    { return newGraphQLJSON(t.tool.Metadata) }
+
+func (t *graphqlTool) Usage() *graphqlToolUsage {
+	return &graphqlToolUsage{usage: t.s.toolRegistry.GetToolUsage(t.tool.Name)}
+}
+
+// graphqlDocument resolves the fields of a Document
+type graphqlDocument struct {
+	s       *SSEServer
+	summary DocumentSummary
+	tenant  *server.Tenant
+}
+
+func (d *graphqlDocument) ID() string                  { return d.summary.ID }
+func (d *graphqlDocument) Title() string               { return d.summary.Title }
+func (d *graphqlDocument) Version() string             { return d.summary.Version }
+func (d *graphqlDocument) Specification() *string      { return optionalString(d.summary.Specification) }
+func (d *graphqlDocument) FilePath() string            { return d.summary.FilePath }
+func (d *graphqlDocument) IsRemote() bool              { return d.summary.IsRemote }
+func (d *graphqlDocument) Format() string              { return d.summary.Format }
+func (d *graphqlDocument) Size() int32                 { return int32(d.summary.Size) }
+func (d *graphqlDocument) PathCount() int32            { return int32(d.summary.PathCount) }
+func (d *graphqlDocument) LastModified() *graphql.Time { return graphqlTime(d.summary.LastModified) }
+func (d *graphqlDocument) RawURL() string              { return d.summary.RawURL }
+
+// Tools resolves the tools generated from the document that the tenant may see
+func (d *graphqlDocument) Tools(args struct {
+	First  *int32
+	Offset int32
+}) ([]*graphqlTool, error) {
+	var tools []*types.GeneratedTool
+	for _, tool := range d.s.toolRegistry.GetAllTools() {
+		if tool.DocumentInfo != nil && tool.DocumentInfo.FilePath == d.summary.FilePath && d.tenant.Allows(tool) {
+			tools = append(tools, tool)
+		}
+	}
+	start, end, err := graphqlPage(len(tools), args.First, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return d.s.graphqlToolObjects(tools[start:end], d.tenant), nil
+}
+
+// graphqlPrompt resolves the fields of a Prompt
+type graphqlPrompt struct {
+	s      *SSEServer
+	prompt *types.GeneratedPrompt
+	tenant *server.Tenant
+}
+
+func (p *graphqlPrompt) Name() string        { return p.prompt.Name }
+func (p *graphqlPrompt) Description() string { return p.prompt.Description }
+func (p *graphqlPrompt) Category() *string   { return optionalString(string(p.prompt.Category)) }
+
+func (p *graphqlPrompt) Tags() []string {
+	if p.prompt.Tags == nil {
+		return []string{}
+	}
+	return p.prompt.Tags
+}
+
+func (p *graphqlPrompt) Arguments() []*graphqlPromptArgument {
+	arguments := make([]*graphqlPromptArgument, len(p.prompt.Arguments))
+	for i := range p.prompt.Arguments {
+		arguments[i] = &graphqlPromptArgument{argument: p.prompt.Arguments[i]}
+	}
+	return arguments
+}
+
+// Document resolves the document the prompt was generated from, if any
+func (p *graphqlPrompt) Document() *graphqlDocument {
+	if p.prompt.Source == nil {
+		return nil
+	}
+	if document := p.s.documents.Get(p.prompt.Source.FilePath); document != nil {
+		return p.s.graphqlDocument(document, p.tenant)
+	}
+	return nil
+}
+
+// graphqlPromptArgument resolves the fields of a PromptArgument
+type graphqlPromptArgument struct {
+	argument types.MCPPromptArgument
+}
+
+func (a *graphqlPromptArgument) Name() string        { return a.argument.Name }
+func (a *graphqlPromptArgument) Description() string { return a.argument.Description }
+func (a *graphqlPromptArgument) Required() bool      { return a.argument.Required }
+
+// graphqlResource resolves the fields of a Resource
+type graphqlResource struct {
+	resource types.MCPResource
+}
+
+func (r *graphqlResource) URI() string          { return r.resource.URI }
+func (r *graphqlResource) Name() string         { return r.resource.Name }
+func (r *graphqlResource) Description() *string { return optionalString(r.resource.Description) }
+func (r *graphqlResource) MimeType() *string    { return optionalString(r.resource.MimeType) }
+
+// graphqlUsageReport resolves the fields of a UsageReport
+type graphqlUsageReport struct {
+	report server.ToolUsageReport
+}
+
+func (u *graphqlUsageReport) TotalTools() int32  { return int32(u.report.TotalTools) }
+func (u *graphqlUsageReport) UsedTools() int32   { return int32(u.report.UsedTools) }
+func (u *graphqlUsageReport) TotalCalls() int32  { return int32(u.report.TotalCalls) }
+func (u *graphqlUsageReport) TotalErrors() int32 { return int32(u.report.TotalErrors) }
+func (u *graphqlUsageReport) ErrorRate() float64 { return u.report.ErrorRate }
+
+func (u *graphqlUsageReport) Tools() []*graphqlToolUsage {
+	tools := make([]*graphqlToolUsage, len(u.report.Tools))
+	for i, usage := range u.report.Tools {
+		tools[i] = &graphqlToolUsage{usage: usage}
+	}
+	return tools
+}
+
+// graphqlToolUsage resolves the fields of a ToolUsage
+type graphqlToolUsage struct {
+	usage server.ToolUsage
+}
+
+func (u *graphqlToolUsage) Name() string               { return u.usage.Name }
+func (u *graphqlToolUsage) Calls() int32               { return int32(u.usage.Calls) }
+func (u *graphqlToolUsage) Errors() int32              { return int32(u.usage.Errors) }
+func (u *graphqlToolUsage) ErrorRate() float64         { return u.usage.ErrorRate }
+func (u *graphqlToolUsage) AverageDurationMs() float64 { return u.usage.AverageDuration }
+func (u *graphqlToolUsage) LastUsed() *graphql.Time    { return graphqlTime(u.usage.LastUsed) }
+func (u *graphqlToolUsage) LastError() *graphql.Time   { return graphqlTime(u.usage.LastError) }
+func (u *graphqlToolUsage) Registered() bool           { return u.usage.Registered }
+
+// containsString reports whether a list holds a string
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	s := NewSSEServer(types.DefaultConfig(), utils.NewLogger(types.LoggingConfig{Level: "error"}))
+	for _, tool := range []*types.GeneratedTool{
+		{
+			Name:         "get_forecast",
+			Description:  "Daily forecast",
+			Endpoint:     &types.SwaggerEndpoint{Method: "get", Path: "/forecast", OperationID: "getForecast", Tags: []string{"weather"}},
+			DocumentInfo: &types.SwaggerDocumentInfo{FilePath: "weather.yaml", PackageIDs: []string{"weather"}},
+			Metadata:     map[string]interface{}{"x-tier": "free"},
+		},
+		{
+			Name:         "get_tiles",
+			Description:  "Map tiles",
+			Endpoint:     &types.SwaggerEndpoint{Method: "get", Path: "/tiles", Deprecated: true},
+			DocumentInfo: &types.SwaggerDocumentInfo{FilePath: "maps.yaml", PackageIDs: []string{"maps"}},
+		},
+		{
+			Name:         "post_alert",
+			Description:  "Create an alert",
+			Endpoint:     &types.SwaggerEndpoint{Method: "post", Path: "/alerts"},
+			DocumentInfo: &types.SwaggerDocumentInfo{FilePath: "weather.yaml", PackageIDs: []string{"weather"}},
+		},
+	} {
+		if err := s.toolRegistry.RegisterTool(tool); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := s.graphqlHandler()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		query  url.Values
+		tenant *server.Tenant
+		// want is the compact JSON response
+		want       string
+		wantStatus int
+		// wantError is a part of the message of the first error
+		wantError string
+	}{
+		{
+			name:       "tools in the order of the query",
+			method:     http.MethodPost,
+			body:       `{"query": "{ tools(method: \"get\") { path name method tags deprecated metadata } toolCount }"}`,
+			want:       `{"data":{"tools":[{"path":"/forecast","name":"get_forecast","method":"GET","tags":["weather"],"deprecated":false,"metadata":{"x-tier":"free"}},{"path":"/tiles","name":"get_tiles","method":"GET","tags":[],"deprecated":true,"metadata":null}],"toolCount":3}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "criteria and paging",
+			method:     http.MethodPost,
+			body:       `{"query": "query Page($first: Int) { tools(filter: {packageIds: [\"weather\"]}, first: $first, offset: 1) { name } toolCount(search: \"FORECAST\") }", "variables": {"first": 1}}`,
+			want:       `{"data":{"tools":[{"name":"post_alert"}],"toolCount":1}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "GET with variables",
+			method:     http.MethodGet,
+			query:      url.Values{"query": {"query Tool($name: String!) { tool(name: $name) { operationId usage { calls lastUsed } } }"}, "variables": {`{"name": "get_forecast"}`}},
+			want:       `{"data":{"tool":{"operationId":"getForecast","usage":{"calls":0,"lastUsed":null}}}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "tenant",
+			method:     http.MethodPost,
+			body:       `{"query": "{ tools { name } tool(name: \"get_forecast\") { name } }"}`,
+			tenant:     &server.Tenant{Name: "maps", Filter: server.ToolFilter{PackageIDs: []string{"maps"}}},
+			want:       `{"data":{"tools":[{"name":"get_tiles"}],"tool":null}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "introspection",
+			method:     http.MethodPost,
+			body:       `{"query": "{ __type(name: \"ToolFilter\") { kind inputFields { name } } }"}`,
+			want:       `{"data":{"__type":{"kind":"INPUT_OBJECT","inputFields":[{"name":"packageIds"},{"name":"twcDomains"},{"name":"twcPortfolios"},{"name":"twcGeographies"},{"name":"custom"},{"name":"metadata"}]}}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown field",
+			method:     http.MethodPost,
+			body:       `{"query": "{ tools { secret } }"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `Cannot query field "secret"`,
+		},
+		{
+			name:       "mutation",
+			method:     http.MethodPost,
+			body:       `{"query": "mutation { tools { name } }"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "no mutations are offered by the schema",
+		},
+		{
+			name:       "nested too deeply",
+			method:     http.MethodPost,
+			body:       `{"query": "{` + strings.Repeat(" tools { document {", 7) + " id" + strings.Repeat(" } }", 7) + ` }"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "exceeds max depth 12",
+		},
+		{
+			name:       "negative offset",
+			method:     http.MethodPost,
+			body:       `{"query": "{ tools(offset: -1) { name } }"}`,
+			wantStatus: http.StatusOK,
+			wantError:  "offset must not be negative",
+		},
+		{
+			name:       "missing query",
+			method:     http.MethodPost,
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "query is required",
+		},
+		{
+			name:       "invalid variables",
+			method:     http.MethodGet,
+			query:      url.Values{"query": {"{ toolCount }"}, "variables": {"[1]"}},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "variables must be a JSON object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, "/graphql?"+test.query.Encode(), strings.NewReader(test.body))
+			if test.tenant != nil {
+				request = request.WithContext(server.WithTenant(request.Context(), test.tenant))
+			}
+			recorder := httptest.NewRecorder()
+			handler(recorder, request)
+
+			if recorder.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, test.wantStatus, recorder.Body.String())
+			}
+
+			var response struct {
+				Data   json.RawMessage `json:"data"`
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("response %q is not JSON: %v", recorder.Body.String(), err)
+			}
+			if test.wantError != "" {
+				if len(response.Errors) == 0 || !strings.Contains(response.Errors[0].Message, test.wantError) {
+					t.Errorf("errors = %+v, want one containing %q", response.Errors, test.wantError)
+				}
+				return
+			}
+			if len(response.Errors) > 0 {
+				t.Fatalf("errors = %+v, want none", response.Errors)
+			}
+
+			var got bytes.Buffer
+			if err := json.Compact(&got, recorder.Body.Bytes()); err != nil || got.String() != test.want {
+				t.Errorf("response = %s, want %s", recorder.Body.String(), test.want)
+			}
+		})
+	}
+}
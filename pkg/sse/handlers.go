@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/server"
 	"swagger-docs-mcp/pkg/types"
 	"swagger-docs-mcp/pkg/version"
 )
@@ -34,6 +36,9 @@ func (s *SSEServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 		"tools":     s.toolRegistry.GetToolCount(),
 		"clients":   len(s.clients),
 	}
+	if stale := s.refresher.StaleDocuments(); len(stale) > 0 {
+		health["staleDocuments"] = stale
+	}
 	
 	json.NewEncoder(w).Encode(health)
 }
@@ -94,9 +99,12 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		mcpTools[i] = types.MCPTool{
 			Name:        tool.Name,
 			Description: tool.Description,
-			InputSchema: tool.InputSchema,
+			InputSchema: tool.Schema(),
 		}
 	}
+	if s.config.Server.Gateway {
+		mcpTools = server.GatewayTools()
+	}
 
 	s.sendEventToClient(client, SSEEvent{
 		Type: "tools",
@@ -104,6 +112,11 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		ID:   uuid.New().String(),
 	})
 
+	// A reconnecting client is sent the events it missed
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" && s.eventLog != nil {
+		s.replayEvents(client, lastEventID)
+	}
+
 	// Keep connection alive and handle client disconnect
 	heartbeat := time.NewTicker(30 * time.Second)
 	defer heartbeat.Stop()
@@ -131,42 +144,62 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 func (s *SSEServer) handleListTools(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	// In gateway mode the generated and composite tools are searched and called through
+	// the gateway tools
+	if s.config.Server.Gateway {
+		mcpTools := server.GatewayTools()
+		if s.searchToolEnabled() {
+			mcpTools = append(mcpTools, server.SearchTool())
+		}
+		s.sendJSONWithETag(w, r, map[string]interface{}{
+			"tools": mcpTools,
+			"count": len(mcpTools),
+		})
+		return
+	}
+
 	// Parse query parameters for dynamic filtering
-	queryParams := r.URL.Query()
-	
-	// Extract filtering parameters from query string
-	packageIDs := parseCommaSeparated(queryParams.Get("package-ids"))
-	twcDomains := parseCommaSeparated(queryParams.Get("twc-domains"))
-	twcPortfolios := parseCommaSeparated(queryParams.Get("twc-portfolios"))
-	twcGeographies := parseCommaSeparated(queryParams.Get("twc-geographies"))
-	customFilters := parseCommaSeparated(queryParams.Get("filter-custom"))
-	
-	s.logger.Debug("Dynamic filtering requested",
-		zap.Strings("packageIDs", packageIDs),
-		zap.Strings("twcDomains", twcDomains),
-		zap.Strings("twcPortfolios", twcPortfolios),
-		zap.Strings("twcGeographies", twcGeographies),
-		zap.Strings("customFilters", customFilters))
+	filter := server.ToolFilterFromQuery(r.URL.Query())
+
+	s.logger.Debug("Dynamic filtering requested", zap.Any("filter", filter))
 
 	// Get all tools first
 	allTools := s.toolRegistry.GetAllTools()
-	
+
 	// Apply dynamic filtering if any filters are specified
-	filteredTools := allTools
-	if len(packageIDs) > 0 || len(twcDomains) > 0 || len(twcPortfolios) > 0 || len(twcGeographies) > 0 || len(customFilters) > 0 {
-		filteredTools = s.applyDynamicFilters(allTools, packageIDs, twcDomains, twcPortfolios, twcGeographies, customFilters)
+	tenant := server.TenantFromContext(r.Context())
+	filteredTools := filter.Apply(allTools)
+	if !filter.IsEmpty() {
 		s.logger.Debug("Applied dynamic filters", 
 			zap.Int("originalCount", len(allTools)), 
 			zap.Int("filteredCount", len(filteredTools)))
 	}
 
 	// Convert to MCP format
-	mcpTools := make([]types.MCPTool, len(filteredTools))
-	for i, tool := range filteredTools {
-		mcpTools[i] = types.MCPTool{
+	mcpTools := make([]types.MCPTool, 0, len(filteredTools))
+	for _, tool := range filteredTools {
+		if !tenant.Allows(tool) {
+			continue
+		}
+		mcpTools = append(mcpTools, types.MCPTool{
 			Name:        tool.Name,
 			Description: tool.Description,
-			InputSchema: tool.InputSchema,
+			InputSchema: tool.Schema(),
+		})
+	}
+	if filter.IsEmpty() {
+		// Generated tools take precedence over composite tools of the same name
+		for _, composite := range s.executor.CompositeTools() {
+			if !s.toolRegistry.HasTool(composite.Name) && tenant.Allows(composite) {
+				mcpTools = append(mcpTools, types.MCPTool{
+					Name:        composite.Name,
+					Description: composite.Description,
+					InputSchema: composite.InputSchema,
+				})
+			}
+		}
+		if s.searchToolEnabled() {
+			mcpTools = append(mcpTools, server.SearchTool())
 		}
 	}
 
@@ -175,6 +208,54 @@ func (s *SSEServer) handleListTools(w http.ResponseWriter, r *http.Request) {
 		"count": len(mcpTools),
 	}
 
+	s.sendJSONWithETag(w, r, result)
+}
+
+// handleGetTool handles GET /tools/{name} requests
+func (s *SSEServer) handleGetTool(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	toolName := vars["name"]
+
+	w.Header().Set("Content-Type", "application/json")
+
+	tool := s.toolRegistry.GetTool(toolName)
+	if tool == nil {
+		tool = s.executor.CompositeTool(toolName)
+	}
+	// Tools the client's tenant is not entitled to are not found either
+	if tool == nil || !server.TenantFromContext(r.Context()).Allows(tool) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Tool not found",
+			"code":  404,
+		})
+		return
+	}
+
+	result := map[string]interface{}{
+		"name":        tool.Name,
+		"description": tool.Description,
+		"inputSchema": tool.Schema(),
+		"metadata":    tool.Metadata,
+	}
+	if tool.Endpoint != nil {
+		result["method"] = tool.Endpoint.Method
+		result["path"] = tool.Endpoint.Path
+		result["operationId"] = tool.Endpoint.OperationID
+		result["tags"] = tool.Endpoint.Tags
+		result["deprecated"] = tool.Endpoint.Deprecated
+	}
+	if tool.Composite != nil {
+		result["steps"] = tool.Composite.Steps
+	}
+	if tool.DocumentInfo != nil {
+		result["document"] = map[string]interface{}{
+			"title":    tool.DocumentInfo.Title,
+			"version":  tool.DocumentInfo.Version,
+			"filePath": tool.DocumentInfo.FilePath,
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(result)
 }
@@ -186,9 +267,19 @@ func (s *SSEServer) handleExecuteTool(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
+	if toolName == server.SearchToolName && s.searchToolEnabled() {
+		s.handleExecuteSearchTool(w, r)
+		return
+	}
+
 	// Get the tool
 	tool := s.toolRegistry.GetTool(toolName)
 	if tool == nil {
+		tool = s.executor.CompositeTool(toolName)
+	}
+	tenant := server.TenantFromContext(r.Context())
+	gateway := s.config.Server.Gateway && server.IsGatewayTool(toolName)
+	if (tool == nil || !tenant.Allows(tool)) && !gateway {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"error": "Tool not found",
@@ -212,6 +303,33 @@ func (s *SSEServer) handleExecuteTool(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Gateway tools search the operations, or call the one named by their arguments
+	if gateway {
+		if toolName == server.GatewaySearchToolName {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(s.executor.CallGatewaySearch(request.Arguments, tenant))
+			return
+		}
+		resolved, arguments, err := s.executor.ResolveGatewayCall(request.Arguments)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": err.Error(),
+				"code":  400,
+			})
+			return
+		}
+		if !tenant.Allows(resolved) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "Tool not found",
+				"code":  404,
+			})
+			return
+		}
+		tool, toolName, request.Arguments = resolved, resolved.Name, arguments
+	}
+
 	s.logger.Debug("Executing tool", zap.String("name", toolName), zap.Any("arguments", request.Arguments))
 
 	// Check if API key is provided in arguments for dynamic override
@@ -226,30 +344,39 @@ func (s *SSEServer) handleExecuteTool(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Count the call against the quotas of the caller's API key
+	if !s.checkQuota(w, r) {
+		return
+	}
+
 	// Execute the tool with dynamic API key if provided
+	started := time.Now()
 	result, err := s.executeAPICallWithAPIKey(tool, request.Arguments, apiKey)
+	duration := time.Since(started)
+	s.toolRegistry.RecordToolCall(toolName, duration, err != nil || result.IsError)
+	s.shared.ToolCalled(r.Context(), server.CompletedToolCall(types.TransportSSE, toolName, request.Arguments, duration, result, err))
 	if err != nil {
 		s.logger.Error("Tool execution failed", zap.Error(err), zap.String("toolName", toolName))
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		body := map[string]interface{}{
 			"error": fmt.Sprintf("Error executing tool: %s", err.Error()),
 			"code":  500,
-		})
+		}
+		if meta := server.FailureMeta(err); meta != nil {
+			body["upstream"] = meta["upstream"]
+			body["execution"] = meta["execution"]
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(body)
 		return
 	}
 
 	// Send execution event to all SSE clients
 	executionEvent := SSEEvent{
 		Type: "tool_execution",
-		Data: ToolExecutionEvent{
-			ToolName:   toolName,
-			Arguments:  request.Arguments,
-			Result:     result,
-			ExecutedAt: time.Now().UTC(),
-		},
-		ID: uuid.New().String(),
+		Data: s.toolExecutionEvent(toolName, request.Arguments, result),
+		ID:   uuid.New().String(),
 	}
-	s.broadcastEvent(executionEvent)
+	s.broadcastToolEvent(tool, executionEvent)
 
 	// Return result
 	w.WriteHeader(http.StatusOK)
@@ -270,6 +397,7 @@ func (s *SSEServer) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 		"swaggerURLs":  s.config.SwaggerURLs,
 		"server": map[string]interface{}{
 			"port":     s.config.Server.Port,
+			"listen":   s.config.Server.Listen,
 			"timeout":  s.config.Server.Timeout.String(),
 			"maxTools": s.config.Server.MaxTools,
 		},
@@ -280,8 +408,7 @@ func (s *SSEServer) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(config)
+	s.sendJSONWithETag(w, r, config)
 }
 
 // sendEventToClient sends an SSE event to a specific client
@@ -318,6 +445,8 @@ func (s *SSEServer) sendEventToClient(client *SSEClient, event SSEEvent) {
 
 // broadcastEvent sends an SSE event to all connected clients
 func (s *SSEServer) broadcastEvent(event SSEEvent) {
+	s.logEvent(event, "")
+
 	s.clientsMutex.RLock()
 	defer s.clientsMutex.RUnlock()
 
@@ -345,152 +474,35 @@ func (s *SSEServer) executeAPICallWithAPIKey(tool *types.GeneratedTool, argument
 		s.logger.Debug("Created temporary HTTP client with dynamic API key")
 	}
 
-	// Execute the HTTP request
-	response, err := httpClient.ExecuteRequest(tool.Endpoint, arguments)
+	// Execute the HTTP request, or mock it
+	response, err := s.executor.Execute(context.Background(), httpClient, tool, arguments)
 	if err != nil {
 		return types.MCPCallToolResult{}, err
 	}
 
-	// Convert response to MCP content
-	content := types.MCPContent{
-		Type: "text",
-		Text: string(response.Body),
-	}
-
-	if response.Headers["Content-Type"] != "" {
-		content.MimeType = response.Headers["Content-Type"]
-	}
-
-	return types.MCPCallToolResult{
-		Content: []types.MCPContent{content},
-		IsError: response.StatusCode >= 400,
-	}, nil
-}
-
-
-// parseCommaSeparated parses a comma-separated string into a slice
-func parseCommaSeparated(value string) []string {
-	if value == "" {
-		return []string{}
-	}
-	
-	parts := strings.Split(value, ",")
-	result := make([]string, 0, len(parts))
-	
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed != "" {
-			result = append(result, trimmed)
-		}
-	}
-	
-	return result
+	// Convert the response to MCP content, returning binary bodies as base64 and
+	// offloading or truncating large ones
+	return server.ResponseResult(response, s.shared.Blobs, s.config.ToolExecution), nil
 }
 
-// applyDynamicFilters applies runtime filtering to tools based on query parameters
-func (s *SSEServer) applyDynamicFilters(tools []*types.GeneratedTool, packageIDs, twcDomains, twcPortfolios, twcGeographies, customFilters []string) []*types.GeneratedTool {
-	var filtered []*types.GeneratedTool
-	
-	for _, tool := range tools {
-		// Check if tool matches any of the filtering criteria
-		if s.matchesTool(tool, packageIDs, twcDomains, twcPortfolios, twcGeographies, customFilters) {
-			filtered = append(filtered, tool)
-		}
-	}
-	
-	return filtered
-}
 
-// matchesTool checks if a tool matches the filtering criteria
-func (s *SSEServer) matchesTool(tool *types.GeneratedTool, packageIDs, twcDomains, twcPortfolios, twcGeographies, customFilters []string) bool {
-	if tool.DocumentInfo == nil {
-		s.logger.Debug("Tool has no document info, skipping filters", zap.String("toolName", tool.Name))
-		return len(packageIDs) == 0 && len(twcDomains) == 0 && len(twcPortfolios) == 0 && len(twcGeographies) == 0 && len(customFilters) == 0
-	}
-	
-	// Filter by package IDs
-	if len(packageIDs) > 0 {
-		if !hasAnyMatch(packageIDs, tool.DocumentInfo.PackageIDs) {
-			return false
-		}
-	}
-	
-	// Filter by TWC domains  
-	if len(twcDomains) > 0 {
-		if !hasAnyMatch(twcDomains, tool.DocumentInfo.TwcDomain) {
-			return false
-		}
-	}
-	
-	// Filter by TWC portfolios
-	if len(twcPortfolios) > 0 {
-		if !hasAnyMatch(twcPortfolios, tool.DocumentInfo.TwcDomainPortfolio) {
-			return false
-		}
-	}
-	
-	// Filter by TWC geographies
-	if len(twcGeographies) > 0 {
-		if !hasAnyMatch(twcGeographies, tool.DocumentInfo.TwcGeography) {
-			return false
-		}
-	}
-	
-	// Filter by custom filters (check title, description, endpoint tags)
-	if len(customFilters) > 0 {
-		matched := false
-		for _, filter := range customFilters {
-			if strings.Contains(strings.ToLower(tool.DocumentInfo.Title), strings.ToLower(filter)) ||
-			   strings.Contains(strings.ToLower(tool.Description), strings.ToLower(filter)) {
-				matched = true
-				break
-			}
-			
-			// Check endpoint tags if available
-			if tool.Endpoint != nil && containsInSlice(tool.Endpoint.Tags, filter) {
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			return false
-		}
-	}
-	
-	return true
-}
+// handleToolStats handles GET /stats/tools requests. Registered tools that were never
+// called are included with ?unused=true.
+func (s *SSEServer) handleToolStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-// contains checks if a slice contains a specific string
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
+	includeUnused := strings.ToLower(r.URL.Query().Get("unused")) == "true"
 
-// hasAnyMatch checks if any item in the first slice matches any item in the second slice
-func hasAnyMatch(searchItems []string, targetItems []string) bool {
-	for _, searchItem := range searchItems {
-		for _, targetItem := range targetItems {
-			if searchItem == targetItem {
-				return true
-			}
-		}
-	}
-	return false
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s.toolRegistry.UsageReport(includeUnused))
 }
 
-// containsInSlice checks if any string in the slice contains the search term (case-insensitive)
-func containsInSlice(slice []string, searchTerm string) bool {
-	searchLower := strings.ToLower(searchTerm)
-	for _, s := range slice {
-		if strings.Contains(strings.ToLower(s), searchLower) {
-			return true
-		}
-	}
-	return false
+// handleSourceStatus handles GET /status/sources requests, reporting which swagger
+// paths and URLs loaded
+func (s *SSEServer) handleSourceStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s.shared.Sources.Report())
 }
 
 // handleGetVersion handles version information requests
@@ -568,7 +580,7 @@ func (s *SSEServer) handleGetPrompt(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate prompt content
-	result, err := s.generatePromptContent(prompt, request.Arguments)
+	result, err := server.RenderPrompt(prompt, request.Arguments, s.readResource)
 	if err != nil {
 		s.logger.Error("Failed to generate prompt content", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -583,6 +595,34 @@ func (s *SSEServer) handleGetPrompt(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+// handleComplete handles POST /completion/complete requests
+func (s *SSEServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request types.MCPCompleteParams
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid request body",
+			"code":  400,
+		})
+		return
+	}
+
+	completion, err := s.completer.Complete(request)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+			"code":  404,
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(types.MCPCompleteResult{Completion: completion})
+}
+
 // handleListResources handles GET /resources requests
 func (s *SSEServer) handleListResources(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -591,131 +631,148 @@ func (s *SSEServer) handleListResources(w http.ResponseWriter, r *http.Request)
 	resources := s.resourceRegistry.GetAllResources()
 	
 	// Convert to MCP format
-	mcpResources := make([]types.MCPResource, len(resources))
-	for i, resource := range resources {
-		mcpResources[i] = types.MCPResource{
+	mcpResources := make([]types.MCPResource, 0, len(resources)+1)
+	for _, resource := range resources {
+		mcpResources = append(mcpResources, types.MCPResource{
 			URI:         resource.URI,
 			Name:        resource.Name,
 			Description: resource.Description,
 			MimeType:    resource.MimeType,
-		}
+		})
+	}
+	if s.config.Resources.Enabled {
+		mcpResources = append(mcpResources, server.ToolStatsResource(), server.SourceStatusResource())
 	}
 
 	result := types.MCPListResourcesResult{
 		Resources: mcpResources,
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(result)
+	s.sendJSONWithETag(w, r, result)
 }
 
-// handleReadResource handles GET /resources/read requests
-func (s *SSEServer) handleReadResource(w http.ResponseWriter, r *http.Request) {
+// handleSearchResources handles GET /resources/search?q=&limit= requests
+func (s *SSEServer) handleSearchResources(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// Parse request body
-	var request types.MCPReadResourceParams
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if s.searchIndex == nil {
+		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Invalid request body",
-			"code":  400,
+			"error": "Documentation search is disabled",
+			"code":  404,
 		})
 		return
 	}
 
-	// Get the resource
-	resource := s.resourceRegistry.GetResourceByURI(request.URI)
-	if resource == nil {
-		w.WriteHeader(http.StatusNotFound)
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource not found",
-			"code":  404,
+			"error": "Query parameter q is required",
+			"code":  400,
 		})
 		return
 	}
 
-	// Generate resource content
-	content, err := s.generateResourceContent(resource)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	search, err := s.searchIndex.Search(query, limit)
 	if err != nil {
-		s.logger.Error("Failed to generate resource content", zap.Error(err))
+		s.logger.Error("Documentation search failed", zap.Error(err), zap.String("query", query))
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": fmt.Sprintf("Error reading resource: %s", err.Error()),
+			"error": err.Error(),
 			"code":  500,
 		})
 		return
 	}
 
-	result := types.MCPReadResourceResult{
-		Contents: []types.MCPResourceContent{
-			{
-				URI:      resource.URI,
-				MimeType: resource.MimeType,
-				Text:     content,
-			},
-		},
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(search)
+}
+
+// handleExecuteSearchTool handles POST /tools/search_documentation/execute requests
+func (s *SSEServer) handleExecuteSearchTool(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid request body",
+			"code":  400,
+		})
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(result)
+	json.NewEncoder(w).Encode(server.CallSearchTool(s.searchIndex, request.Arguments))
 }
 
-// generatePromptContent generates the actual content for a prompt
-func (s *SSEServer) generatePromptContent(prompt *types.GeneratedPrompt, arguments map[string]interface{}) (types.MCPPromptGetResult, error) {
-	// Process the template with arguments
-	processedTemplate := s.processPromptTemplate(prompt.Template, arguments)
-	
-	// Create the result
-	result := types.MCPPromptGetResult{
-		Description: prompt.Description,
-		Messages: []types.MCPPromptMessage{
-			{
-				Role: "user",
-				Content: types.MCPPromptContent{
-					Type: "text",
-					Text: processedTemplate,
-				},
-			},
-		},
-	}
+// searchToolEnabled reports whether the documentation search tool is offered. A
+// generated tool with the same name takes precedence.
+func (s *SSEServer) searchToolEnabled() bool {
+	return s.searchIndex != nil && !s.toolRegistry.HasTool(server.SearchToolName)
+}
+
+// handleListResourceTemplates handles GET /resources/templates requests
+func (s *SSEServer) handleListResourceTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	return result, nil
+	templates := append(s.resourceGenerator.ResourceTemplates(), server.BlobResourceTemplate())
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(types.MCPListResourceTemplatesResult{
+		ResourceTemplates: templates,
+	})
 }
 
-// processPromptTemplate processes a prompt template with arguments
-func (s *SSEServer) processPromptTemplate(template string, arguments map[string]interface{}) string {
-	result := template
-	
-	// Simple template processing - replace {{arg}} with argument values
-	for key, value := range arguments {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		if valueStr, ok := value.(string); ok {
-			result = strings.ReplaceAll(result, placeholder, valueStr)
-		} else {
-			// Convert to string representation
-			result = strings.ReplaceAll(result, placeholder, fmt.Sprintf("%v", value))
-		}
+// handleReadResource handles GET /resources/read requests
+func (s *SSEServer) handleReadResource(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Parse request body
+	var request types.MCPReadResourceParams
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid request body",
+			"code":  400,
+		})
+		return
+	}
+
+	content, err := s.readResource(request.URI)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": fmt.Sprintf("Error reading resource: %s", err.Error()),
+			"code":  500,
+		})
+		return
+	}
+	if content == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Resource not found",
+			"code":  404,
+		})
+		return
 	}
-	
-	return result
-}
 
-// generateResourceContent generates the actual content for a resource
-func (s *SSEServer) generateResourceContent(resource *types.GeneratedResource) (string, error) {
-	// Get the parsed document for this resource
-	doc := s.getDocumentForResource(resource)
-	if doc == nil {
-		return "", fmt.Errorf("document not found for resource")
+	result := types.MCPReadResourceResult{
+		Contents: []types.MCPResourceContent{*content},
 	}
 
-	// Use the resource generator to get content
-	return s.resourceGenerator.GetResourceContent(resource, doc)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
 }
 
-// getDocumentForResource gets the parsed document for a resource
-func (s *SSEServer) getDocumentForResource(resource *types.GeneratedResource) *types.SwaggerDocument {
-	// This would need to be implemented based on how documents are stored
-	// For now, return nil to indicate document not found
-	return nil
-}
\ No newline at end of file
+// readResource reads the content of a resource, or returns nil if no resource has the URI
+func (s *SSEServer) readResource(uri string) (*types.MCPResourceContent, error) {
+	content, err := server.ReadResource(uri, s.config, s.shared, s.resourceGenerator)
+	if err != nil {
+		s.logger.Error("Failed to generate resource content", zap.Error(err), zap.String("uri", uri))
+	}
+	return content, err
+}
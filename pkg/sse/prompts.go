@@ -0,0 +1,77 @@
+package sse
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/server"
+)
+
+// registerTemplatePrompts registers the prompts loaded from the prompt template directory
+func (s *SSEServer) registerTemplatePrompts() {
+	for _, prompt := range s.promptGenerator.TemplatePrompts() {
+		if err := s.promptRegistry.RegisterPrompt(prompt); err != nil {
+			s.logger.Error("Failed to register prompt template",
+				zap.Error(err),
+				zap.String("promptName", prompt.Name))
+		}
+	}
+}
+
+// watchPromptTemplates reloads the prompts whenever the prompt template directory
+// changes, until the context is cancelled
+func (s *SSEServer) watchPromptTemplates(ctx context.Context) {
+	watcher := server.NewDocumentWatcher(s.logger)
+	watcher.OnChange(func(string) {
+		s.reloadPrompts()
+	})
+	if err := watcher.Run(ctx, []string{s.config.Prompts.TemplateDir}); err != nil {
+		s.logger.Error("Failed to watch prompt templates", zap.Error(err))
+	}
+}
+
+// reloadPrompts reloads the prompt templates and registers them again together with
+// the prompts generated from the stored documents, as a template that was removed
+// brings back the generated prompt it replaced
+func (s *SSEServer) reloadPrompts() {
+	if err := s.promptGenerator.LoadTemplates(); err != nil {
+		s.logger.Error("Failed to reload prompt templates", zap.Error(err))
+		return
+	}
+
+	s.promptRegistry.Clear()
+	for _, stored := range s.documents.All() {
+		prompts, err := s.promptGenerator.GeneratePromptsFromDocument(stored.Document, stored.Info)
+		if err != nil {
+			s.logger.Error("Failed to generate prompts from document",
+				zap.Error(err),
+				zap.String("filePath", stored.Info.FilePath))
+			continue
+		}
+		for _, prompt := range prompts {
+			if err := s.promptRegistry.RegisterPrompt(prompt); err != nil {
+				s.logger.Error("Failed to register prompt",
+					zap.Error(err),
+					zap.String("promptName", prompt.Name))
+			}
+		}
+	}
+	s.registerTemplatePrompts()
+
+	s.logger.Info("Reloaded prompts", zap.Int("promptCount", s.promptRegistry.GetPromptCount()))
+	s.shared.NotifyListsChanged(true, false)
+}
+
+// broadcastPromptsUpdated tells the clients that the prompts changed
+func (s *SSEServer) broadcastPromptsUpdated() {
+	s.broadcastEvent(SSEEvent{
+		Type: "prompts_updated",
+		Data: PromptsUpdatedEvent{
+			PromptCount: s.promptRegistry.GetPromptCount(),
+			UpdatedAt:   time.Now().UTC(),
+		},
+		ID: uuid.New().String(),
+	})
+}
@@ -0,0 +1,325 @@
+package sse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// anonymousKey identifies the tool calls of clients without a verified API key or a
+// remote address
+const anonymousKey = "anonymous"
+
+// keyUsageBucket is the usage store bucket of the quota usage of API keys
+const keyUsageBucket = "keys"
+
+// quotaTracker counts the tool calls of each API key in the current clock minute and
+// UTC day, rejecting calls beyond the key's quotas. With a usage store, the counts
+// are persisted so that a restart does not reset them.
+type quotaTracker struct {
+	mutex     sync.Mutex
+	usage     map[string]*keyUsage
+	lastSweep time.Time
+	store     *server.UsageStore
+}
+
+// keyUsage is the tool calls of one API key in the current windows
+type keyUsage struct {
+	label       string
+	quotas      types.QuotaConfig
+	minuteStart time.Time
+	minuteCalls int
+	dayStart    time.Time
+	dayCalls    int
+}
+
+// storedKeyUsage is the form the usage of an API key is persisted in
+type storedKeyUsage struct {
+	Label       string            `json:"label"`
+	Quotas      types.QuotaConfig `json:"quotas"`
+	MinuteStart time.Time         `json:"minuteStart"`
+	MinuteCalls int               `json:"minuteCalls"`
+	DayStart    time.Time         `json:"dayStart"`
+	DayCalls    int               `json:"dayCalls"`
+}
+
+// QuotaWindow reports the usage of an API key in one quota window
+type QuotaWindow struct {
+	// Limit is the quota of the window, or 0 when it is unlimited
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining *int      `json:"remaining,omitempty"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// KeyUsageReport reports the usage of an API key, identified by its tenant's name or a
+// hash of the key rather than the key itself
+type KeyUsageReport struct {
+	Key    string      `json:"key"`
+	Minute QuotaWindow `json:"minute"`
+	Day    QuotaWindow `json:"day"`
+}
+
+// newQuotaTracker creates an empty quota tracker, persisting its counts to a usage
+// store unless it is nil
+func newQuotaTracker(store *server.UsageStore) *quotaTracker {
+	return &quotaTracker{usage: make(map[string]*keyUsage), store: store}
+}
+
+// load restores the counts of the keys that called a tool today from the usage store
+func (t *quotaTracker) load(now time.Time) error {
+	if t.store == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	today := dayStart(now)
+	var stale []string
+	err := t.store.Load(keyUsageBucket, func(id string, value []byte) error {
+		var stored storedKeyUsage
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return fmt.Errorf("failed to decode quota usage of %s: %w", id, err)
+		}
+		if stored.DayStart.Before(today) {
+			stale = append(stale, id)
+			return nil
+		}
+		t.usage[id] = &keyUsage{
+			label:       stored.Label,
+			quotas:      stored.Quotas,
+			minuteStart: stored.MinuteStart,
+			minuteCalls: stored.MinuteCalls,
+			dayStart:    stored.DayStart,
+			dayCalls:    stored.DayCalls,
+		}
+		return nil
+	})
+
+	// Keys that made no calls today are forgotten
+	for _, id := range stale {
+		t.store.Delete(keyUsageBucket, id)
+	}
+	return err
+}
+
+// quotaKey identifies the caller of a tool call: the tenant key or bearer token the
+// client authenticated with, otherwise its remote address. Unverified credentials,
+// such as the apiKey argument of a call or a bearer token without server.auth, are
+// chosen by the client and do not identify it. It returns the caller's ID, the label
+// reported for it, and its quotas.
+func (s *SSEServer) quotaKey(r *http.Request) (string, string, types.QuotaConfig) {
+	quotas := s.config.Server.Quotas
+	key, ok := server.BearerToken(r)
+	if !ok || !s.tenants.Enabled() {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil || host == "" {
+			return anonymousKey, anonymousKey, quotas
+		}
+		sum := sha256.Sum256([]byte("addr:" + host))
+		id := hex.EncodeToString(sum[:])
+		return id, "addr-" + id[:12], quotas
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	id := hex.EncodeToString(sum[:])
+	label := "key-" + id[:12]
+	if tenant := server.TenantFromContext(r.Context()); tenant != nil {
+		if tenant.Name != "" {
+			label = tenant.Name
+		}
+		if tenant.Quotas.PerMinute > 0 {
+			quotas.PerMinute = tenant.Quotas.PerMinute
+		}
+		if tenant.Quotas.PerDay > 0 {
+			quotas.PerDay = tenant.Quotas.PerDay
+		}
+	}
+	return id, label, quotas
+}
+
+// record counts a tool call of an API key, returning the window whose quota the call
+// exceeds and its usage, or "" when the call is within its quotas
+func (t *quotaTracker) record(id, label string, quotas types.QuotaConfig, now time.Time) (string, QuotaWindow) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.sweep(now)
+	usage := t.current(id, label, quotas, now)
+	if quotas.PerMinute > 0 && usage.minuteCalls >= quotas.PerMinute {
+		return "minute", usage.report().Minute
+	}
+	if quotas.PerDay > 0 && usage.dayCalls >= quotas.PerDay {
+		return "day", usage.report().Day
+	}
+	usage.minuteCalls++
+	usage.dayCalls++
+	if t.store != nil {
+		t.store.Put(keyUsageBucket, id, storedKeyUsage{
+			Label:       usage.label,
+			Quotas:      usage.quotas,
+			MinuteStart: usage.minuteStart,
+			MinuteCalls: usage.minuteCalls,
+			DayStart:    usage.dayStart,
+			DayCalls:    usage.dayCalls,
+		})
+	}
+	return "", QuotaWindow{}
+}
+
+// report returns the usage of an API key
+func (t *quotaTracker) report(id, label string, quotas types.QuotaConfig, now time.Time) KeyUsageReport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.current(id, label, quotas, now).report()
+}
+
+// reportAll returns the usage of every API key that called a tool today, by label
+func (t *quotaTracker) reportAll(now time.Time) []KeyUsageReport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.sweep(now)
+	reports := make([]KeyUsageReport, 0, len(t.usage))
+	for _, usage := range t.usage {
+		usage.advance(now)
+		reports = append(reports, usage.report())
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Key < reports[j].Key })
+	return reports
+}
+
+// current returns the usage of an API key in the windows of now
+func (t *quotaTracker) current(id, label string, quotas types.QuotaConfig, now time.Time) *keyUsage {
+	usage, exists := t.usage[id]
+	if !exists {
+		usage = &keyUsage{}
+		t.usage[id] = usage
+	}
+	usage.label = label
+	usage.quotas = quotas
+	usage.advance(now)
+	return usage
+}
+
+// sweep forgets the keys that made no calls today, at most once a minute
+func (t *quotaTracker) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < time.Minute {
+		return
+	}
+	t.lastSweep = now
+	today := dayStart(now)
+	for id, usage := range t.usage {
+		if usage.dayStart.Before(today) {
+			delete(t.usage, id)
+			if t.store != nil {
+				t.store.Delete(keyUsageBucket, id)
+			}
+		}
+	}
+}
+
+// advance starts new windows once the current ones are over
+func (u *keyUsage) advance(now time.Time) {
+	if minute := now.UTC().Truncate(time.Minute); !minute.Equal(u.minuteStart) {
+		u.minuteStart, u.minuteCalls = minute, 0
+	}
+	if day := dayStart(now); !day.Equal(u.dayStart) {
+		u.dayStart, u.dayCalls = day, 0
+	}
+}
+
+// report returns the usage of the key in its windows
+func (u *keyUsage) report() KeyUsageReport {
+	return KeyUsageReport{
+		Key:    u.label,
+		Minute: quotaWindow(u.quotas.PerMinute, u.minuteCalls, u.minuteStart.Add(time.Minute)),
+		Day:    quotaWindow(u.quotas.PerDay, u.dayCalls, u.dayStart.AddDate(0, 0, 1)),
+	}
+}
+
+// quotaWindow reports the usage of a window
+func quotaWindow(limit, used int, resetAt time.Time) QuotaWindow {
+	window := QuotaWindow{Limit: limit, Used: used, ResetAt: resetAt}
+	if limit > 0 {
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		window.Remaining = &remaining
+	}
+	return window
+}
+
+// dayStart returns the start of the UTC day of a time
+func dayStart(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// checkQuota counts a tool call against the quotas of its caller, writing a 429 Too
+// Many Requests response with the reset time of the exceeded quota and returning
+// false when the call exceeds them
+func (s *SSEServer) checkQuota(w http.ResponseWriter, r *http.Request) bool {
+	id, label, quotas := s.quotaKey(r)
+	now := time.Now()
+	window, usage := s.quotas.record(id, label, quotas, now)
+	if window == "" {
+		return true
+	}
+
+	retryAfter := int(usage.ResetAt.Sub(now).Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(usage.Limit))
+	w.Header().Set("X-RateLimit-Remaining", "0")
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(usage.ResetAt.Unix(), 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": fmt.Sprintf("Quota of %d tool calls per %s exceeded", usage.Limit, window),
+		"code":  429,
+		"quota": map[string]interface{}{
+			"key":     label,
+			"window":  window,
+			"limit":   usage.Limit,
+			"used":    usage.Used,
+			"resetAt": usage.ResetAt,
+		},
+	})
+	return false
+}
+
+// handleUsage handles GET /usage requests, reporting the tool calls and quotas of the
+// caller's API key. Clients not restricted to a tenant can add ?all=true to report
+// every key that called a tool today.
+func (s *SSEServer) handleUsage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if strings.ToLower(r.URL.Query().Get("all")) == "true" {
+		if server.TenantFromContext(r.Context()) != nil {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "Tenant keys can only report their own usage",
+				"code":  403,
+			})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.quotas.reportAll(time.Now())})
+		return
+	}
+
+	id, label, quotas := s.quotaKey(r)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s.quotas.report(id, label, quotas, time.Now()))
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	httpclient "swagger-docs-mcp/pkg/http"
@@ -28,7 +29,16 @@ type SSEServer struct {
 	toolRegistry      *server.ToolRegistry
 	promptRegistry    *server.PromptRegistry
 	resourceRegistry  *server.ResourceRegistry
+	documents         *server.DocumentStore
+	searchIndex       *server.SearchIndex
+	completer         *server.Completer
+	refresher         *server.DocumentRefresher
 	httpClient        *httpclient.Client
+	executor          *server.ToolExecutor
+	tenants           *server.Tenants
+	quotas            *quotaTracker
+	eventLog          *eventLog
+	shared            *server.SharedState
 	server            *http.Server
 	clients           map[string]*SSEClient
 	clientsMutex      sync.RWMutex
@@ -65,6 +75,23 @@ type ToolExecutionEvent struct {
 	Arguments  map[string]interface{}  `json:"arguments"`
 	Result     types.MCPCallToolResult `json:"result"`
 	ExecutedAt time.Time               `json:"executedAt"`
+	// ResultOmitted reports that the content of a result larger than the configured
+	// maximum was left out, and ResultSize is the size of the result in bytes
+	ResultOmitted bool `json:"resultOmitted,omitempty"`
+	ResultSize    int  `json:"resultSize,omitempty"`
+}
+
+// ToolsUpdatedEvent is sent when a refresh of remote documents changes the available tools
+type ToolsUpdatedEvent struct {
+	Changes   server.ToolChanges `json:"changes"`
+	ToolCount int                `json:"toolCount"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// PromptsUpdatedEvent is sent when a change to the prompt template directory reloads the prompts
+type PromptsUpdatedEvent struct {
+	PromptCount int       `json:"promptCount"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 // ErrorEvent is sent when an error occurs
@@ -75,17 +102,28 @@ type ErrorEvent struct {
 
 // NewSSEServer creates a new SSE server
 func NewSSEServer(config *types.ResolvedConfig, logger *utils.Logger) *SSEServer {
+	return NewSharedSSEServer(config, logger, server.NewSharedState(config, logger))
+}
+
+// NewSharedSSEServer creates an SSE server that is the primary server of shared state:
+// it scans the documents into the state and keeps them up to date for all servers
+// sharing it
+func NewSharedSSEServer(config *types.ResolvedConfig, logger *utils.Logger, shared *server.SharedState) *SSEServer {
 	scanner := swagger.NewScanner(logger)
-	parser := swagger.NewParser(logger)
+	scanner.SetHostPolicy(httpclient.NewHostPolicy(config))
+	parser := swagger.NewParserWithConfig(logger, &config.SwaggerProcessing)
+	parser.SetHostPolicy(httpclient.NewHostPolicy(config))
 	generator := swagger.NewToolGeneratorWithConfig(logger, &config.ToolGeneration)
+	generator.SetAuth(&config.Auth)
 	promptGenerator := swagger.NewPromptGenerator(logger, &config.Prompts)
 	resourceGenerator := swagger.NewResourceGenerator(logger, &config.Resources)
-	toolRegistry := server.NewToolRegistry()
-	promptRegistry := server.NewPromptRegistry()
-	resourceRegistry := server.NewResourceRegistry()
-	httpClient := httpclient.NewClient(config, logger)
+	promptGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+	resourceGenerator.SetEndpointFilter(generator.EnabledEndpoints)
+	toolRegistry := shared.Tools
+	toolRegistry.SetSchemaLoader(generator.AttachInputSchema)
+	promptRegistry := shared.Prompts
 
-	return &SSEServer{
+	s := &SSEServer{
 		config:            config,
 		logger:            logger.Child("sse-server"),
 		scanner:           scanner,
@@ -95,11 +133,66 @@ func NewSSEServer(config *types.ResolvedConfig, logger *utils.Logger) *SSEServer
 		resourceGenerator: resourceGenerator,
 		toolRegistry:      toolRegistry,
 		promptRegistry:    promptRegistry,
-		resourceRegistry:  resourceRegistry,
-		httpClient:        httpClient,
+		resourceRegistry:  shared.Resources,
+		documents:         shared.Documents,
+		searchIndex:       shared.SearchIndex,
+		completer:         server.NewCompleter(toolRegistry, promptRegistry),
+		refresher:         server.NewDocumentRefresher(config, logger, toolRegistry),
+		httpClient:        shared.HTTPClient,
+		executor:          shared.Executor,
+		tenants:           server.NewTenants(config.Server.Auth.Tenants),
+		quotas:            newQuotaTracker(shared.Usage),
+		shared:            shared,
 		clients:           make(map[string]*SSEClient),
 		shutdown:          make(chan struct{}),
 	}
+
+	if config.Server.Events.Log.Path != "" {
+		if log, err := openEventLog(config.Server.Events.Log, s.logger); err != nil {
+			s.logger.Error("Event history is unavailable", zap.Error(err), zap.String("path", config.Server.Events.Log.Path))
+		} else {
+			s.eventLog = log
+		}
+	}
+	if err := s.quotas.load(time.Now()); err != nil {
+		s.logger.Error("Failed to load quota usage", zap.Error(err), zap.String("path", config.Server.UsagePath))
+	}
+
+	s.refresher.OnChange(s.shared.NotifyToolsChanged)
+	s.refresher.SetSourceTracker(shared.Sources)
+	s.refresher.SetLeaderCheck(shared.Catalog.Refreshes)
+	s.shared.OnToolsChanged(func(changes server.ToolChanges) {
+		s.broadcastEvent(SSEEvent{
+			Type: "tools_updated",
+			Data: ToolsUpdatedEvent{
+				Changes:   changes,
+				ToolCount: s.toolRegistry.GetToolCount(),
+				UpdatedAt: time.Now().UTC(),
+			},
+			ID: uuid.New().String(),
+		})
+	})
+	s.shared.OnListsChanged(func(prompts, resources bool) {
+		if prompts {
+			s.broadcastPromptsUpdated()
+		}
+	})
+	s.refresher.OnEvict(func(path string) {
+		if stored := s.documents.Get(path); stored != nil {
+			s.resourceGenerator.InvalidateDocument(stored.Info)
+		}
+		s.documents.Delete(path)
+		if s.resourceRegistry.ReplaceDocumentResources(path, nil) {
+			s.shared.NotifyListsChanged(false, true)
+		}
+		if s.searchIndex != nil {
+			if err := s.searchIndex.RemoveDocument(path); err != nil {
+				s.logger.Error("Failed to remove document from search index", zap.Error(err), zap.String("filePath", path))
+			}
+		}
+	})
+
+	return s
 }
 
 // Start starts the SSE server
@@ -109,10 +202,13 @@ func (s *SSEServer) Start(ctx context.Context) error {
 		zap.String("version", s.config.Version),
 		zap.Duration("timeout", s.config.Server.Timeout))
 
-	// Initialize tools first
-	if err := s.initializeTools(ctx); err != nil {
+	// Initialize tools first. Servers sharing the state serve whatever was loaded.
+	err := s.initializeTools(ctx)
+	s.shared.MarkReady()
+	if err != nil {
 		return fmt.Errorf("failed to initialize tools: %w", err)
 	}
+	s.shared.SyncCatalog()
 
 	// Setup HTTP router
 	router := mux.NewRouter()
@@ -120,7 +216,7 @@ func (s *SSEServer) Start(ctx context.Context) error {
 
 	// Create HTTP server
 	s.server = &http.Server{
-		Addr:         fmt.Sprintf(":%d", s.config.Server.Port),
+		Addr:         server.ListenAddress(s.config.Server.Listen, s.config.Server.Port),
 		Handler:      s.addMiddleware(router),
 		ReadTimeout:  s.config.Server.Timeout,
 		WriteTimeout: s.config.Server.Timeout,
@@ -131,12 +227,24 @@ func (s *SSEServer) Start(ctx context.Context) error {
 	s.wg.Add(1)
 	go s.cleanupClients()
 
+	// Start periodic refresh of remote documents
+	refreshCtx, cancelRefresh := context.WithCancel(ctx)
+	defer cancelRefresh()
+	go s.refresher.Run(refreshCtx)
+	if s.config.Prompts.Enabled && s.config.Prompts.TemplateDir != "" {
+		go s.watchPromptTemplates(refreshCtx)
+	}
+
 	// Start server
+	listener, err := server.Listen(s.server.Addr, s.config.Server.SocketMode)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
 	s.logger.Info("SSE server listening", zap.String("address", s.server.Addr))
 	
 	serverErr := make(chan error, 1)
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 		}
 	}()
@@ -183,9 +291,15 @@ func (s *SSEServer) stop() error {
 	s.clientsMutex.Unlock()
 
 	// Wait for cleanup routine
-	close(s.shutdown)
+	s.Stop()
 	s.wg.Wait()
 
+	if s.eventLog != nil {
+		if err := s.eventLog.close(); err != nil {
+			s.logger.Error("Error closing event log", zap.Error(err))
+		}
+	}
+
 	s.logger.Info("SSE server stopped")
 	return nil
 }
@@ -203,6 +317,7 @@ func (s *SSEServer) setupRoutes(router *mux.Router) {
 	
 	// Tool management
 	router.HandleFunc("/tools", s.handleListTools).Methods("GET")
+	router.HandleFunc("/tools/{name}", s.handleGetTool).Methods("GET")
 	router.HandleFunc("/tools/{name}/execute", s.handleExecuteTool).Methods("POST")
 	
 	// Prompt management
@@ -212,12 +327,39 @@ func (s *SSEServer) setupRoutes(router *mux.Router) {
 	// Resource management
 	router.HandleFunc("/resources", s.handleListResources).Methods("GET")
 	router.HandleFunc("/resources/read", s.handleReadResource).Methods("POST")
+	router.HandleFunc("/resources/templates", s.handleListResourceTemplates).Methods("GET")
+	router.HandleFunc("/resources/search", s.handleSearchResources).Methods("GET")
+
+	// Raw documents
+	router.HandleFunc("/documents", s.handleListDocuments).Methods("GET")
+	router.HandleFunc("/documents/{id}/raw", s.handleGetRawDocument).Methods("GET")
+	router.HandleFunc("/docs/{id}", s.handleDocs).Methods("GET")
+
+	// Read-only GraphQL API over the catalog
+	router.HandleFunc("/graphql", s.graphqlHandler()).Methods("GET", "POST")
+
+	// Argument completion
+	router.HandleFunc("/completion/complete", s.handleComplete).Methods("POST")
 	
+	// Usage statistics
+	router.HandleFunc("/stats/tools", s.handleToolStats).Methods("GET")
+
+	// Swagger source status
+	router.HandleFunc("/status/sources", s.handleSourceStatus).Methods("GET")
+
+	// Tool call quotas
+	router.HandleFunc("/usage", s.handleUsage).Methods("GET")
+	router.HandleFunc("/events/history", s.handleEventHistory).Methods("GET")
+
 	// Configuration
 	router.HandleFunc("/config", s.handleGetConfig).Methods("GET")
 	
 	// Version information
 	router.HandleFunc("/version", s.handleGetVersion).Methods("GET")
+
+	// Operator dashboard
+	router.HandleFunc("/ui", s.handleUI).Methods("GET")
+	router.HandleFunc("/ui/", s.handleUI).Methods("GET")
 	
 	// Root endpoint (must be last to avoid conflicts)
 	router.HandleFunc("/", s.handleRoot).Methods("GET")
@@ -231,7 +373,8 @@ func (s *SSEServer) addMiddleware(handler http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag")
 			
 			if r.Method == "OPTIONS" {
 				return
@@ -254,7 +397,7 @@ func (s *SSEServer) addMiddleware(handler http.Handler) http.Handler {
 		})
 	}
 
-	return corsHandler(loggingHandler(handler))
+	return corsHandler(loggingHandler(s.authenticateTenant(handler)))
 }
 
 // cleanupClients removes inactive clients
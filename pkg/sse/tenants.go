@@ -0,0 +1,77 @@
+package sse
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/server"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// unauthenticatedPaths stay open to clients without credentials, for health checks
+// and for the dashboard page, which asks for credentials to read anything. The pages
+// of /docs/ are open too, and read documents with the credentials of the dashboard.
+var unauthenticatedPaths = map[string]bool{
+	"/health":  true,
+	"/healthz": true,
+	"/ready":   true,
+	"/readyz":  true,
+	"/ui":      true,
+	"/ui/":     true,
+}
+
+// authenticateTenant requires clients to present a tenant's API key, restricting them
+// to the tenant's tools, or one of server.auth.bearerTokens once tenants are configured
+func (s *SSEServer) authenticateTenant(next http.Handler) http.Handler {
+	if !s.tenants.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unauthenticatedPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/docs/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := server.BearerToken(r)
+		if ok {
+			if tenant := s.tenants.Lookup(token); tenant != nil {
+				next.ServeHTTP(w, r.WithContext(server.WithTenant(r.Context(), tenant)))
+				return
+			}
+			for _, expected := range s.config.Server.Auth.BearerTokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			s.logger.Debug("Rejected SSE request with an unknown API key", zap.String("remote", r.RemoteAddr))
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="swagger-docs-mcp"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "A tenant API key or bearer token is required",
+			"code":  401,
+		})
+	})
+}
+
+// broadcastToolEvent sends an event about a tool to the clients whose tenant is
+// entitled to the tool
+func (s *SSEServer) broadcastToolEvent(tool *types.GeneratedTool, event SSEEvent) {
+	s.logEvent(event, tool.Name)
+
+	s.clientsMutex.RLock()
+	defer s.clientsMutex.RUnlock()
+
+	for _, client := range s.clients {
+		if server.TenantFromContext(client.Context).Allows(tool) {
+			go s.sendEventToClient(client, event)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package sse
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/server"
 	"swagger-docs-mcp/pkg/types"
 )
 
@@ -21,6 +23,7 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to scan swagger documents: %w", err)
 	}
+	s.shared.Sources.RecordScan(scanResult)
 
 	s.logger.Info("Scan complete",
 		zap.Int("totalFiles", scanResult.Stats.TotalFiles),
@@ -49,17 +52,30 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 		s.logger.Debug("Filtered by dynamic filters", zap.Int("documentsRemaining", len(documents)))
 	}
 
+	// Load prompt templates first so that they take the place of generated prompts
+	if s.config.Prompts.Enabled {
+		if err := s.promptGenerator.LoadTemplates(); err != nil {
+			s.logger.Error("Failed to load prompt templates", zap.Error(err))
+		}
+	}
+
 	// Parse documents and generate tools
 	toolCount := 0
+	var registered []*types.GeneratedTool
 	for _, docInfo := range documents {
 		var parsedDoc *types.SwaggerDocument
 		var err error
 
-		// Use appropriate parsing method based on whether content is available
-		if docInfo.IsRemote && len(docInfo.Content) > 0 {
+		// Keep the content of local documents as well, so GET /documents/{id}/raw
+		// returns exactly what was parsed even after the file changes
+		if !docInfo.IsRemote {
+			docInfo.Content, err = os.ReadFile(docInfo.FilePath)
+			if err != nil {
+				err = fmt.Errorf("failed to read file %s: %w", docInfo.FilePath, err)
+			}
+		}
+		if err == nil {
 			parsedDoc, err = s.parser.ParseDocumentWithContent(&docInfo)
-		} else {
-			parsedDoc, err = s.parser.ParseDocument(docInfo.FilePath)
 		}
 
 		if err != nil {
@@ -69,6 +85,10 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 				zap.String("title", docInfo.Title),
 				zap.Int("contentSize", len(docInfo.Content)),
 				zap.Bool("isRemote", docInfo.IsRemote))
+			s.shared.Sources.RecordDocumentError(docInfo.FilePath, err)
+			if !s.config.SwaggerProcessing.IgnoreErrors {
+				return fmt.Errorf("failed to parse document %s: %w", docInfo.FilePath, err)
+			}
 			continue
 		}
 
@@ -81,8 +101,10 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 				zap.String("title", docInfo.Title),
 				zap.Int("pathCount", getPathCount(parsedDoc)),
 				zap.String("version", docInfo.Version))
+			s.shared.Sources.RecordDocumentError(docInfo.FilePath, err)
 			continue
 		}
+		s.shared.Sources.RecordDocumentLoaded(docInfo.FilePath)
 
 		// Register tools
 		for _, tool := range tools {
@@ -97,6 +119,7 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 				// Continue processing other tools even if one fails
 			} else {
 				toolCount++
+				registered = append(registered, tool)
 				s.logger.Debug("Successfully registered tool",
 					zap.String("toolName", tool.Name),
 					zap.String("method", tool.Endpoint.Method),
@@ -129,6 +152,16 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 			}
 		}
 
+		// Keep the parsed document for resource reads and search
+		s.documents.Put(&docInfo, parsedDoc)
+		if s.searchIndex != nil {
+			if err := s.searchIndex.IndexDocument(&docInfo, parsedDoc); err != nil {
+				s.logger.Error("Failed to index document for search",
+					zap.Error(err),
+					zap.String("filePath", docInfo.FilePath))
+			}
+		}
+
 		// Generate and register resources
 		if s.config.Resources.Enabled {
 			resources, err := s.resourceGenerator.GenerateResourcesFromDocument(parsedDoc, &docInfo)
@@ -153,11 +186,35 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 			}
 		}
 
-		// Check max tools limit
-		if s.config.Server.MaxTools > 0 && toolCount >= s.config.Server.MaxTools {
-			s.logger.Warn("Reached maximum tool limit, stopping tool generation", zap.Int("maxTools", s.config.Server.MaxTools))
-			break
+	}
+
+	// Drop the tools of the lowest priority beyond the maximum
+	if _, dropped := server.LimitTools(registered, s.generator, s.config); len(dropped) > 0 {
+		for _, tool := range dropped {
+			s.toolRegistry.UnregisterTool(tool.Name)
+		}
+		toolCount -= len(dropped)
+		s.logger.Warn("Reached maximum tool limit, dropping the tools of the lowest priority",
+			zap.Int("maxTools", s.config.Server.MaxTools),
+			zap.Int("toolsDropped", len(dropped)))
+	}
+
+	if s.config.Prompts.Enabled {
+		s.registerTemplatePrompts()
+	}
+
+	promptConflicts := s.promptRegistry.GetConflicts()
+	for _, conflict := range promptConflicts {
+		if conflict.Resolution == server.PromptConflictRejected {
+			// Rejected prompts were already logged when registration failed
+			continue
 		}
+		s.logger.Warn("Resolved prompt name conflict",
+			zap.String("promptName", conflict.Name),
+			zap.String("document", conflict.Document),
+			zap.String("existingDocument", conflict.ExistingDocument),
+			zap.String("resolution", conflict.Resolution),
+			zap.String("registeredAs", conflict.RegisteredAs))
 	}
 
 	s.logger.Info("Initialization complete",
@@ -165,6 +222,7 @@ func (s *SSEServer) initializeTools(ctx context.Context) error {
 		zap.Int("toolsGenerated", toolCount),
 		zap.Int("toolsRegistered", s.toolRegistry.GetToolCount()),
 		zap.Int("promptsRegistered", s.promptRegistry.GetPromptCount()),
+		zap.Int("promptConflicts", len(promptConflicts)),
 		zap.Int("resourcesRegistered", s.resourceRegistry.GetResourceCount()))
 
 	return nil
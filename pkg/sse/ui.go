@@ -0,0 +1,24 @@
+package sse
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// uiPage is the single-page dashboard served at /ui. It holds no data of its own and
+// reads the documents, tools, prompts, and resources from the JSON endpoints of the
+// server, sending the API key entered on the page when tenants are configured.
+//
+//go:embed ui/index.html
+var uiPage []byte
+
+// handleUI handles GET /ui requests, serving the dashboard
+func (s *SSEServer) handleUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	// The page only runs its own script and only talks to this server
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(uiPage)
+}
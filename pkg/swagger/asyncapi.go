@@ -0,0 +1,290 @@
+package swagger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// extractAsyncAPIEndpoints converts AsyncAPI channel operations into endpoints.
+// AsyncAPI 2.x declares subscribe/publish operations on channels; AsyncAPI 3.x declares
+// send/receive operations at the top level that reference channels. Only operations a
+// client can subscribe to (2.x subscribe, 3.x send) are returned, since those describe
+// data the application streams to its consumers.
+func (p *Parser) extractAsyncAPIEndpoints(document *types.SwaggerDocument) []types.SwaggerEndpoint {
+	servers := p.parseAsyncAPIServers(document.AsyncAPIServers)
+
+	var endpoints []types.SwaggerEndpoint
+	if strings.HasPrefix(document.AsyncAPI, "2.") {
+		endpoints = p.extractAsyncAPIv2Endpoints(document, servers)
+	} else {
+		endpoints = p.extractAsyncAPIv3Endpoints(document, servers)
+	}
+
+	// Inline message and payload references so message schemas are self-contained
+	flattener := newSchemaFlattener(document)
+	for i := range endpoints {
+		for j, message := range endpoints[i].Messages {
+			endpoints[i].Messages[j] = flattenAsyncAPIMessage(flattener, message)
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Path < endpoints[j].Path
+	})
+
+	p.logger.Debug("Extracted AsyncAPI endpoints",
+		zap.String("asyncapi", document.AsyncAPI),
+		zap.Int("count", len(endpoints)))
+
+	return endpoints
+}
+
+// extractAsyncAPIv2Endpoints extracts subscribe operations from AsyncAPI 2.x channels
+func (p *Parser) extractAsyncAPIv2Endpoints(document *types.SwaggerDocument, servers []types.SwaggerServer) []types.SwaggerEndpoint {
+	var endpoints []types.SwaggerEndpoint
+
+	for channelName, channelInterface := range document.Channels {
+		channel, ok := channelInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		operation, ok := channel["subscribe"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		endpoint := p.newAsyncAPIEndpoint(channelName, channel, operation, servers)
+		endpoint.Messages = asyncAPIMessages(operation["message"])
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// extractAsyncAPIv3Endpoints extracts send operations from AsyncAPI 3.x documents
+func (p *Parser) extractAsyncAPIv3Endpoints(document *types.SwaggerDocument, servers []types.SwaggerServer) []types.SwaggerEndpoint {
+	var endpoints []types.SwaggerEndpoint
+
+	for operationID, operationInterface := range document.Operations {
+		operation, ok := operationInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if action, _ := operation["action"].(string); action != "send" {
+			continue
+		}
+
+		channelName, channel := p.lookupAsyncAPIChannel(document, operation["channel"])
+		if channel == nil {
+			p.logger.Debug("Skipping AsyncAPI operation with unknown channel", zap.String("operationId", operationID))
+			continue
+		}
+
+		endpoint := p.newAsyncAPIEndpoint(channelName, channel, operation, servers)
+		if endpoint.OperationID == "" {
+			endpoint.OperationID = operationID
+		}
+
+		// Operations may narrow the channel's messages; otherwise all channel messages apply
+		if messages, ok := operation["messages"].([]interface{}); ok && len(messages) > 0 {
+			endpoint.Messages = messages
+		} else if messages, ok := channel["messages"].(map[string]interface{}); ok {
+			endpoint.Messages = asyncAPIMessages(messages)
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// newAsyncAPIEndpoint builds an endpoint for a channel operation
+func (p *Parser) newAsyncAPIEndpoint(channelName string, channel, operation map[string]interface{}, servers []types.SwaggerServer) types.SwaggerEndpoint {
+	address := channelName
+	if channelAddress, ok := channel["address"].(string); ok && channelAddress != "" {
+		address = channelAddress
+	}
+
+	endpoint := types.SwaggerEndpoint{
+		Path:     address,
+		Method:   "SUBSCRIBE",
+		Protocol: types.EndpointProtocolAsyncAPI,
+		Servers:  servers,
+	}
+
+	if operationID, ok := operation["operationId"].(string); ok {
+		endpoint.OperationID = operationID
+	}
+	if summary, ok := operation["summary"].(string); ok {
+		endpoint.Summary = summary
+	}
+	if description, ok := operation["description"].(string); ok {
+		endpoint.Description = description
+	} else if description, ok := channel["description"].(string); ok {
+		endpoint.Description = description
+	}
+
+	if tags, ok := operation["tags"].([]interface{}); ok {
+		for _, tagInterface := range tags {
+			if tag, ok := tagInterface.(map[string]interface{}); ok {
+				if name, ok := tag["name"].(string); ok {
+					endpoint.Tags = append(endpoint.Tags, name)
+				}
+			}
+		}
+	}
+
+	// Channel parameters become tool arguments used to build the channel address
+	if parameters, ok := channel["parameters"].(map[string]interface{}); ok {
+		for name, parameterInterface := range parameters {
+			param := types.SwaggerParameter{
+				Name:     name,
+				In:       "channel",
+				Required: true,
+			}
+			if parameter, ok := parameterInterface.(map[string]interface{}); ok {
+				if description, ok := parameter["description"].(string); ok {
+					param.Description = description
+				}
+				if schema, ok := parameter["schema"]; ok {
+					param.Schema = schema
+				} else if enum, ok := parameter["enum"]; ok {
+					param.Schema = map[string]interface{}{"type": "string", "enum": enum}
+				}
+			}
+			endpoint.Parameters = append(endpoint.Parameters, param)
+		}
+		sort.Slice(endpoint.Parameters, func(i, j int) bool {
+			return endpoint.Parameters[i].Name < endpoint.Parameters[j].Name
+		})
+	}
+
+	endpoint.Extensions = extractExtensions(channel, operation)
+
+	return endpoint
+}
+
+// lookupAsyncAPIChannel resolves an AsyncAPI 3.x channel reference
+func (p *Parser) lookupAsyncAPIChannel(document *types.SwaggerDocument, channelRef interface{}) (string, map[string]interface{}) {
+	refMap, ok := channelRef.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+
+	// Channels may already be inlined by reference resolution
+	if _, isRef := refMap["$ref"]; !isRef {
+		if address, ok := refMap["address"].(string); ok {
+			return address, refMap
+		}
+		return "", refMap
+	}
+
+	ref, _ := refMap["$ref"].(string)
+	name := strings.TrimPrefix(ref, "#/channels/")
+	if name == ref {
+		return "", nil
+	}
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+
+	channel, ok := document.Channels[name].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+
+	return name, channel
+}
+
+// parseAsyncAPIServers converts AsyncAPI servers (2.x url or 3.x host/pathname) into server entries
+func (p *Parser) parseAsyncAPIServers(servers map[string]interface{}) []types.SwaggerServer {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []types.SwaggerServer
+	for _, name := range names {
+		serverMap, ok := servers[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		server := types.SwaggerServer{Description: name}
+		if protocol, ok := serverMap["protocol"].(string); ok {
+			server.Protocol = protocol
+		}
+		if description, ok := serverMap["description"].(string); ok {
+			server.Description = fmt.Sprintf("%s: %s", name, description)
+		}
+		if variables, ok := serverMap["variables"].(map[string]interface{}); ok {
+			server.Variables = variables
+		}
+
+		if serverURL, ok := serverMap["url"].(string); ok {
+			server.URL = serverURL
+		} else if host, ok := serverMap["host"].(string); ok {
+			pathname, _ := serverMap["pathname"].(string)
+			server.URL = host + pathname
+			if server.Protocol != "" {
+				server.URL = fmt.Sprintf("%s://%s", server.Protocol, server.URL)
+			}
+		}
+
+		if server.URL != "" {
+			result = append(result, server)
+		}
+	}
+
+	return result
+}
+
+// asyncAPIMessages normalizes a message definition (single, oneOf list, or named map) into a list
+func asyncAPIMessages(message interface{}) []interface{} {
+	switch value := message.(type) {
+	case map[string]interface{}:
+		if oneOf, ok := value["oneOf"].([]interface{}); ok {
+			return oneOf
+		}
+		// AsyncAPI 3.x channel messages are keyed by name
+		if _, hasPayload := value["payload"]; !hasPayload {
+			if _, isRef := value["$ref"]; !isRef {
+				names := make([]string, 0, len(value))
+				for name := range value {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				messages := make([]interface{}, 0, len(value))
+				for _, name := range names {
+					messages = append(messages, value[name])
+				}
+				return messages
+			}
+		}
+		return []interface{}{value}
+	case []interface{}:
+		return value
+	default:
+		return nil
+	}
+}
+
+// flattenAsyncAPIMessage resolves a message reference and flattens its payload schema
+func flattenAsyncAPIMessage(flattener *schemaFlattener, message interface{}) interface{} {
+	messageMap, ok := flattener.flatten(message).(map[string]interface{})
+	if !ok {
+		return message
+	}
+
+	if payload, ok := messageMap["payload"]; ok {
+		messageMap["payload"] = flattener.flatten(payload)
+	}
+
+	return messageMap
+}
@@ -0,0 +1,242 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	httpclient "swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// sampleAPIKeyVariable is the environment variable code samples read the API key from
+const sampleAPIKeyVariable = "API_KEY"
+
+// sampleRequest is the request a code sample sends
+type sampleRequest struct {
+	method  string
+	url     string
+	headers [][2]string
+	body    interface{}
+}
+
+// endpointSample renders a code sample that calls an endpoint in the given language
+func (g *ResourceGenerator) endpointSample(endpoint *types.SwaggerEndpoint, language string) (string, error) {
+	request := g.sampleRequest(endpoint)
+
+	switch language {
+	case "curl":
+		return curlSample(request), nil
+	case "go":
+		return goSample(request)
+	case "python":
+		return pythonSample(request), nil
+	default:
+		return "", fmt.Errorf("unknown code sample language: %s", language)
+	}
+}
+
+// sampleRequest builds the request of a code sample from the endpoint's servers and the
+// documented example values of its parameters and request body. Required parameters
+// without an example are filled with a <name> placeholder.
+func (g *ResourceGenerator) sampleRequest(endpoint *types.SwaggerEndpoint) sampleRequest {
+	baseURL, _ := httpclient.EndpointBaseURL(endpoint, httpclient.DefaultBaseURL)
+
+	request := sampleRequest{
+		method:  strings.ToUpper(endpoint.Method),
+		headers: [][2]string{{"Accept", "application/json"}},
+	}
+
+	path := endpoint.Path
+	query := url.Values{}
+	for _, param := range endpoint.Parameters {
+		if param.In == "body" {
+			// Swagger 2.0 carries the request body as a parameter
+			request.body = schemaExample(param.Schema)
+			if request.body == nil {
+				request.body = map[string]interface{}{}
+			}
+			continue
+		}
+
+		value, ok := sampleValue(param)
+		if !ok {
+			continue
+		}
+		switch param.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", value)
+		case "query":
+			query.Add(param.Name, value)
+		case "header":
+			request.headers = append(request.headers, [2]string{param.Name, value})
+		}
+	}
+
+	request.url = strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		request.url += "?" + query.Encode()
+	}
+
+	if requestBody, ok := endpoint.RequestBody.(map[string]interface{}); ok {
+		request.body = mediaExample(requestBody)
+		if request.body == nil {
+			request.body = map[string]interface{}{}
+		}
+	}
+	if request.body != nil {
+		request.headers = append(request.headers, [2]string{"Content-Type", "application/json"})
+	}
+
+	return request
+}
+
+// sampleValue returns the value a code sample passes for a parameter, and whether the
+// parameter is passed at all
+func sampleValue(param types.SwaggerParameter) (string, bool) {
+	var example interface{}
+	switch {
+	case param.Example != nil:
+		example = param.Example
+	case len(param.Examples) > 0:
+		example = param.Examples[0]
+	default:
+		example = schemaExample(param.Schema)
+	}
+
+	if example != nil {
+		return fmt.Sprintf("%v", example), true
+	}
+	if param.Required || param.In == "path" {
+		return "<" + param.Name + ">", true
+	}
+	return "", false
+}
+
+// curlSample renders a request as a curl command
+func curlSample(request sampleRequest) string {
+	lines := []string{fmt.Sprintf("curl -X %s %s", request.method, shellQuote(request.url))}
+	for _, header := range request.headers {
+		lines = append(lines, "-H "+shellQuote(header[0]+": "+header[1]))
+	}
+	lines = append(lines, fmt.Sprintf(`-H "Authorization: Bearer $%s"`, sampleAPIKeyVariable))
+	if request.body != nil {
+		body, _ := json.Marshal(request.body)
+		lines = append(lines, "-d "+shellQuote(string(body)))
+	}
+	return strings.Join(lines, " \\\n  ") + "\n"
+}
+
+// goSample renders a request as a Go program using net/http
+func goSample(request sampleRequest) (string, error) {
+	var sample strings.Builder
+
+	sample.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"os\"\n")
+	if request.body != nil {
+		sample.WriteString("\t\"strings\"\n")
+	}
+	sample.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if request.body != nil {
+		encoded, err := json.MarshalIndent(request.body, "\t", "\t")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode sample request body: %w", err)
+		}
+		literal := "`" + string(encoded) + "`"
+		if strings.Contains(string(encoded), "`") {
+			literal = strconv.Quote(string(encoded))
+		}
+		fmt.Fprintf(&sample, "\tbody := strings.NewReader(%s)\n", literal)
+		body = "body"
+	}
+
+	fmt.Fprintf(&sample, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(request.method), strconv.Quote(request.url), body)
+	sample.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, header := range request.headers {
+		fmt.Fprintf(&sample, "\treq.Header.Set(%s, %s)\n", strconv.Quote(header[0]), strconv.Quote(header[1]))
+	}
+	fmt.Fprintf(&sample, "\treq.Header.Set(\"Authorization\", \"Bearer \"+os.Getenv(%s))\n\n", strconv.Quote(sampleAPIKeyVariable))
+
+	sample.WriteString("\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\tdefer resp.Body.Close()\n\n")
+	sample.WriteString("\tdata, err := io.ReadAll(resp.Body)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	sample.WriteString("\tfmt.Println(resp.Status)\n\tfmt.Println(string(data))\n}\n")
+
+	return sample.String(), nil
+}
+
+// pythonSample renders a request as a Python script using requests
+func pythonSample(request sampleRequest) string {
+	var sample strings.Builder
+
+	sample.WriteString("import os\n\nimport requests\n\nresponse = requests.request(\n")
+	fmt.Fprintf(&sample, "    %s,\n    %s,\n", strconv.Quote(request.method), strconv.Quote(request.url))
+	sample.WriteString("    headers={\n")
+	for _, header := range request.headers {
+		if header[0] == "Content-Type" && request.body != nil {
+			// requests sets the content type of json= bodies itself
+			continue
+		}
+		fmt.Fprintf(&sample, "        %s: %s,\n", strconv.Quote(header[0]), strconv.Quote(header[1]))
+	}
+	fmt.Fprintf(&sample, "        \"Authorization\": \"Bearer \" + os.environ[%s],\n    },\n", strconv.Quote(sampleAPIKeyVariable))
+	if request.body != nil {
+		fmt.Fprintf(&sample, "    json=%s,\n", pythonLiteral(request.body, "    "))
+	}
+	sample.WriteString(")\nresponse.raise_for_status()\nprint(response.json())\n")
+
+	return sample.String()
+}
+
+// pythonLiteral renders a decoded JSON value as a Python literal
+func pythonLiteral(value interface{}, indent string) string {
+	switch typed := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if typed {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(typed)
+	case map[string]interface{}:
+		if len(typed) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var literal strings.Builder
+		literal.WriteString("{\n")
+		for _, key := range keys {
+			fmt.Fprintf(&literal, "%s    %s: %s,\n", indent, strconv.Quote(key), pythonLiteral(typed[key], indent+"    "))
+		}
+		literal.WriteString(indent + "}")
+		return literal.String()
+	case []interface{}:
+		if len(typed) == 0 {
+			return "[]"
+		}
+		var literal strings.Builder
+		literal.WriteString("[\n")
+		for _, item := range typed {
+			fmt.Fprintf(&literal, "%s    %s,\n", indent, pythonLiteral(item, indent+"    "))
+		}
+		literal.WriteString(indent + "]")
+		return literal.String()
+	default:
+		encoded, _ := json.Marshal(typed)
+		return string(encoded)
+	}
+}
+
+// shellQuote quotes a string for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,189 @@
+package swagger
+
+import (
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxCompositionDepth bounds schema flattening for deeply nested or recursive schemas
+const maxCompositionDepth = 32
+
+// schemaFlattener resolves local references and allOf/oneOf/anyOf compositions so
+// generated tool schemas are self-contained
+type schemaFlattener struct {
+	root  map[string]interface{}
+	stack []string
+}
+
+// newSchemaFlattener creates a schema flattener that resolves local references
+// against the components of the given document
+func newSchemaFlattener(document *types.SwaggerDocument) *schemaFlattener {
+	root := make(map[string]interface{})
+	if document != nil {
+		if document.Components != nil {
+			root["components"] = document.Components
+		}
+		if document.Definitions != nil {
+			root["definitions"] = document.Definitions
+		}
+	}
+
+	return &schemaFlattener{root: root}
+}
+
+// flatten returns a copy of schema with local references inlined, allOf members merged
+// into a single object schema, and oneOf/anyOf variants flattened individually
+func (f *schemaFlattener) flatten(schema interface{}) interface{} {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+
+	if len(f.stack) >= maxCompositionDepth {
+		return map[string]interface{}{"type": "object"}
+	}
+
+	// Inline local references
+	if ref, ok := schemaMap["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+		for _, visited := range f.stack {
+			if visited == ref {
+				// Recursive schema; stop expanding
+				return map[string]interface{}{"type": "object"}
+			}
+		}
+
+		target, err := resolvePointer(f.root, strings.TrimPrefix(ref, "#"))
+		if err != nil {
+			return schemaMap
+		}
+
+		f.stack = append(f.stack, ref)
+		resolved := f.flatten(target)
+		f.stack = f.stack[:len(f.stack)-1]
+
+		return mergeSiblingKeys(resolved, schemaMap)
+	}
+
+	result := make(map[string]interface{}, len(schemaMap))
+	for key, value := range schemaMap {
+		switch key {
+		case "allOf":
+			continue
+		case "oneOf", "anyOf":
+			if variants, ok := value.([]interface{}); ok {
+				flattened := make([]interface{}, len(variants))
+				for i, variant := range variants {
+					flattened[i] = f.flatten(variant)
+				}
+				result[key] = flattened
+				continue
+			}
+		case "properties":
+			if properties, ok := value.(map[string]interface{}); ok {
+				flattened := make(map[string]interface{}, len(properties))
+				for name, property := range properties {
+					flattened[name] = f.flatten(property)
+				}
+				result[key] = flattened
+				continue
+			}
+		case "items", "additionalProperties", "not":
+			result[key] = f.flatten(value)
+			continue
+		}
+		result[key] = value
+	}
+
+	if members, ok := schemaMap["allOf"].([]interface{}); ok {
+		for _, member := range members {
+			if memberMap, ok := f.flatten(member).(map[string]interface{}); ok {
+				mergeAllOfMember(result, memberMap)
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeAllOfMember merges a flattened allOf member into the composed schema.
+// Properties and required lists are combined; other keywords keep the first value seen.
+func mergeAllOfMember(target, member map[string]interface{}) {
+	for key, value := range member {
+		switch key {
+		case "properties":
+			properties, _ := target["properties"].(map[string]interface{})
+			if properties == nil {
+				properties = make(map[string]interface{})
+			}
+			if memberProperties, ok := value.(map[string]interface{}); ok {
+				for name, property := range memberProperties {
+					if _, exists := properties[name]; !exists {
+						properties[name] = property
+					}
+				}
+			}
+			target["properties"] = properties
+		case "required":
+			target["required"] = mergeRequired(target["required"], value)
+		default:
+			if _, exists := target[key]; !exists {
+				target[key] = value
+			}
+		}
+	}
+
+	if _, hasType := target["type"]; !hasType {
+		if _, hasProperties := target["properties"]; hasProperties {
+			target["type"] = "object"
+		}
+	}
+}
+
+// mergeRequired combines two required lists without duplicates
+func mergeRequired(existing, additional interface{}) []interface{} {
+	var merged []interface{}
+	seen := make(map[string]bool)
+
+	for _, list := range []interface{}{existing, additional} {
+		switch values := list.(type) {
+		case []interface{}:
+			for _, value := range values {
+				if name, ok := value.(string); ok && !seen[name] {
+					seen[name] = true
+					merged = append(merged, name)
+				}
+			}
+		case []string:
+			for _, name := range values {
+				if !seen[name] {
+					seen[name] = true
+					merged = append(merged, name)
+				}
+			}
+		}
+	}
+
+	return merged
+}
+
+// mergeSiblingKeys applies keys that sit next to a $ref (such as description) on top
+// of the resolved schema
+func mergeSiblingKeys(resolved interface{}, refNode map[string]interface{}) interface{} {
+	resolvedMap, ok := resolved.(map[string]interface{})
+	if !ok || len(refNode) <= 1 {
+		return resolved
+	}
+
+	merged := make(map[string]interface{}, len(resolvedMap)+len(refNode))
+	for key, value := range resolvedMap {
+		merged[key] = value
+	}
+	for key, value := range refNode {
+		if key != "$ref" {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,65 @@
+package swagger
+
+import (
+	"path/filepath"
+	"sort"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// MatchesDocument reports whether a pattern matches a document's path, file name, or
+// file name without the extension. A nil document matches no pattern.
+func MatchesDocument(pattern string, info *types.SwaggerDocumentInfo) bool {
+	if info == nil {
+		return false
+	}
+
+	candidates := []string{
+		info.FilePath,
+		filepath.Base(info.FilePath),
+		ResourceDocumentName(info),
+	}
+	for _, candidate := range candidates {
+		if matched, _ := filepath.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// documentFormats returns the preferred and ignored formats of a document. The first
+// documentFormats entry in key order matching the document takes precedence, then the
+// document's x-mcp-prefer-format extension, then the global preferFormat and
+// ignoreFormats.
+func (g *ToolGenerator) documentFormats(docInfo *types.SwaggerDocumentInfo) (string, []string) {
+	if g.config == nil {
+		return "", nil
+	}
+	preferFormat, ignoreFormats := g.config.PreferFormat, g.config.IgnoreFormats
+	if docInfo == nil {
+		return preferFormat, ignoreFormats
+	}
+	if docInfo.PreferFormat != "" {
+		preferFormat = docInfo.PreferFormat
+	}
+
+	keys := make([]string, 0, len(g.config.DocumentFormats))
+	for key := range g.config.DocumentFormats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !MatchesDocument(key, docInfo) {
+			continue
+		}
+		formats := g.config.DocumentFormats[key]
+		if formats.PreferFormat != "" {
+			preferFormat = formats.PreferFormat
+		}
+		if formats.IgnoreFormats != nil {
+			ignoreFormats = formats.IgnoreFormats
+		}
+		break
+	}
+	return preferFormat, ignoreFormats
+}
@@ -1,19 +1,56 @@
 package swagger
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
 
 	"go.uber.org/zap"
+	httpclient "swagger-docs-mcp/pkg/http"
 	"swagger-docs-mcp/pkg/types"
 	"swagger-docs-mcp/pkg/utils"
 )
 
+// FilterArgument is the optional tool argument holding a JMESPath expression that is
+// applied to the JSON response of the call
+const FilterArgument = "_filter"
+
+// SkipReason is why no tool was generated for an endpoint
+type SkipReason string
+
+const (
+	// SkipReasonDeprecated skips deprecated endpoints unless includeDeprecated is set
+	SkipReasonDeprecated SkipReason = "deprecated"
+	// SkipReasonIgnoredFormat skips endpoints whose format is in ignoreFormats
+	SkipReasonIgnoredFormat SkipReason = "ignored-format"
+	// SkipReasonNonPreferredFormat skips endpoints also served in the preferred format
+	SkipReasonNonPreferredFormat SkipReason = "non-preferred-format"
+	// SkipReasonGenerationError skips endpoints whose tool failed to generate
+	SkipReasonGenerationError SkipReason = "generation-error"
+	// SkipReasonDisabled skips endpoints whose tool is disabled by disabledTools
+	SkipReasonDisabled SkipReason = "disabled"
+	// SkipReasonUnsupportedAuth skips endpoints whose security requirements cannot be met
+	// by the configured auth when unsupportedAuth is skip
+	SkipReasonUnsupportedAuth SkipReason = "unsupported-auth"
+)
+
 // ToolGenerator generates MCP tools from swagger documents
 type ToolGenerator struct {
 	logger *utils.Logger
 	config *types.ToolGenerationConfig
+	onSkip func(endpoint *types.SwaggerEndpoint, reason SkipReason)
+	// schemas caches the input schemas of tools generated with lazy schemas
+	schemas *schemaCache
+	// auth decides whether the security requirements of endpoints can be met
+	auth *types.AuthConfig
+	// descriptionTemplate renders tool descriptions when descriptionTemplate is set
+	descriptionTemplate *template.Template
 }
 
 // NewToolGenerator creates a new tool generator
@@ -26,10 +63,34 @@ func NewToolGenerator(logger *utils.Logger) *ToolGenerator {
 
 // NewToolGeneratorWithConfig creates a new tool generator with configuration
 func NewToolGeneratorWithConfig(logger *utils.Logger, config *types.ToolGenerationConfig) *ToolGenerator {
-	return &ToolGenerator{
+	generator := &ToolGenerator{
 		logger: logger.Child("generator"),
 		config: config,
 	}
+	if config != nil && config.LazySchemas {
+		generator.schemas = newSchemaCache(config.SchemaCacheSize)
+	}
+	if config != nil && config.DescriptionTemplate != "" {
+		descriptionTemplate, err := types.ParseDescriptionTemplate(config.DescriptionTemplate)
+		if err != nil {
+			generator.logger.Error("Invalid description template, using the default descriptions", zap.Error(err))
+		} else {
+			generator.descriptionTemplate = descriptionTemplate
+		}
+	}
+	return generator
+}
+
+// OnSkip registers a function called for each endpoint no tool is generated for
+func (g *ToolGenerator) OnSkip(fn func(endpoint *types.SwaggerEndpoint, reason SkipReason)) {
+	g.onSkip = fn
+}
+
+// skip reports an endpoint no tool is generated for
+func (g *ToolGenerator) skip(endpoint *types.SwaggerEndpoint, reason SkipReason) {
+	if g.onSkip != nil {
+		g.onSkip(endpoint, reason)
+	}
 }
 
 // GenerateToolsFromDocument generates MCP tools from a parsed swagger document
@@ -43,17 +104,128 @@ func (g *ToolGenerator) GenerateToolsFromDocument(document *types.SwaggerDocumen
 		return nil, fmt.Errorf("failed to extract endpoints: %w", err)
 	}
 
+	filteredEndpoints := g.selectEndpoints(endpoints, docInfo, g.skip)
+
+	// Resolve schema compositions against the document's components
+	flattener := newSchemaFlattener(document)
+	rootHash := ""
+	if g.schemas != nil {
+		rootHash = hashSchemaSource(flattener.root)
+	}
+
+	var tools []*types.GeneratedTool
+	for _, endpoint := range filteredEndpoints {
+		if g.toolDisabled(g.generateToolName(&endpoint, docInfo, filteredEndpoints)) {
+			g.skip(&endpoint, SkipReasonDisabled)
+			continue
+		}
+
+		unsupported := ""
+		if g.config != nil && (g.config.UnsupportedAuth == types.UnsupportedAuthMark || g.config.UnsupportedAuth == types.UnsupportedAuthSkip) {
+			unsupported = g.unsupportedSecurity(&endpoint)
+		}
+		if unsupported != "" && g.config.UnsupportedAuth == types.UnsupportedAuthSkip {
+			g.logger.Debug("Skipping endpoint with unsupported security requirements", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.String("security", unsupported))
+			g.skip(&endpoint, SkipReasonUnsupportedAuth)
+			continue
+		}
+
+		tool, err := g.generateToolFromEndpoint(&endpoint, docInfo, filteredEndpoints, flattener)
+		if err != nil {
+			g.logger.Error("Failed to generate tool for endpoint", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.Error(err))
+			g.skip(&endpoint, SkipReasonGenerationError)
+			continue
+		}
+		if unsupported != "" {
+			tool.Description = fmt.Sprintf("%s [Requires %s authentication, which is not configured]", tool.Description, unsupported)
+		}
+		if tool.LoadInputSchema != nil {
+			tool.SchemaHash = hashSchemaSource(map[string]interface{}{"endpoint": tool.Endpoint, "root": rootHash})
+		}
+
+		tools = append(tools, tool)
+	}
+
+	g.uniqueToolNames(tools)
+
+	g.logger.Debug("Generated tools from document", zap.Int("toolCount", len(tools)), zap.String("title", docInfo.Title))
+	return tools, nil
+}
+
+// uniqueToolNames renames the tools of a document whose names collide, e.g. getCafé
+// and getCafe once romanized. Of each set of colliding tools, the tool of the first
+// endpoint by path and method keeps the name and the others get a _2, _3, ... suffix,
+// so that the names do not depend on the order of the endpoints in the document.
+func (g *ToolGenerator) uniqueToolNames(tools []*types.GeneratedTool) {
+	const maxToolNameLength = 64
+
+	byName := make(map[string][]*types.GeneratedTool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = append(byName[tool.Name], tool)
+	}
+
+	var colliding []*types.GeneratedTool
+	for _, named := range byName {
+		if len(named) > 1 {
+			colliding = append(colliding, named...)
+		}
+	}
+	slices.SortStableFunc(colliding, func(a, b *types.GeneratedTool) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Endpoint.Path, b.Endpoint.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Endpoint.Method, b.Endpoint.Method)
+	})
+
+	seen := make(map[string]bool, len(colliding))
+	for _, tool := range colliding {
+		name := tool.Name
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			suffix := fmt.Sprintf("_%d", n)
+			base := name
+			if len(base)+len(suffix) > maxToolNameLength {
+				base = strings.TrimSuffix(base[:maxToolNameLength-len(suffix)], "_")
+			}
+			candidate := base + suffix
+			if _, taken := byName[candidate]; !taken && !seen[candidate] {
+				g.logger.Warn("Renaming tool whose name collides with another tool of the document",
+					zap.String("toolName", name),
+					zap.String("renamedTo", candidate),
+					zap.String("method", tool.Endpoint.Method),
+					zap.String("path", tool.Endpoint.Path))
+				tool.Name = candidate
+				seen[candidate] = true
+				break
+			}
+		}
+	}
+}
+
+// selectEndpoints returns the endpoints tools are generated for, leaving out deprecated
+// endpoints and endpoints in ignored or non-preferred formats, which are passed to skip
+func (g *ToolGenerator) selectEndpoints(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo, skip func(endpoint *types.SwaggerEndpoint, reason SkipReason)) []types.SwaggerEndpoint {
+	preferFormat, _ := g.documentFormats(docInfo)
+
 	// Filter endpoints by format preference first
 	var filteredEndpoints []types.SwaggerEndpoint
 	for _, endpoint := range endpoints {
 		// Skip deprecated endpoints if configured
 		if g.config != nil && !g.config.IncludeDeprecated && endpoint.Deprecated {
 			g.logger.Debug("Skipping deprecated endpoint", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path))
+			skip(&endpoint, SkipReasonDeprecated)
 			continue
 		}
 
 		// Skip endpoints based on format filtering
-		if g.shouldSkipEndpointByFormat(&endpoint) {
+		if g.shouldSkipEndpointByFormat(&endpoint, docInfo) {
+			skip(&endpoint, SkipReasonIgnoredFormat)
 			continue
 		}
 
@@ -61,7 +233,7 @@ func (g *ToolGenerator) GenerateToolsFromDocument(document *types.SwaggerDocumen
 	}
 
 	// Apply format preference logic - skip non-preferred formats if preference is set
-	if g.config != nil && g.config.PreferFormat != "" {
+	if preferFormat != "" {
 		var preferredEndpoints []types.SwaggerEndpoint
 		preferredFormats := make(map[string]bool)
 		
@@ -72,7 +244,7 @@ func (g *ToolGenerator) GenerateToolsFromDocument(document *types.SwaggerDocumen
 			baseMethod := endpoint.Method
 			key := fmt.Sprintf("%s:%s", baseMethod, basePath)
 			
-			if strings.EqualFold(format, g.config.PreferFormat) {
+			if strings.EqualFold(format, preferFormat) {
 				preferredEndpoints = append(preferredEndpoints, endpoint)
 				preferredFormats[key] = true
 			}
@@ -85,51 +257,99 @@ func (g *ToolGenerator) GenerateToolsFromDocument(document *types.SwaggerDocumen
 			baseMethod := endpoint.Method
 			key := fmt.Sprintf("%s:%s", baseMethod, basePath)
 			
-			if !strings.EqualFold(format, g.config.PreferFormat) && !preferredFormats[key] {
-				preferredEndpoints = append(preferredEndpoints, endpoint)
+			if !strings.EqualFold(format, preferFormat) {
+				if preferredFormats[key] {
+					skip(&endpoint, SkipReasonNonPreferredFormat)
+				} else {
+					preferredEndpoints = append(preferredEndpoints, endpoint)
+				}
 			}
 		}
 		
 		filteredEndpoints = preferredEndpoints
 	}
 
-	var tools []*types.GeneratedTool
-	for _, endpoint := range filteredEndpoints {
-		tool, err := g.generateToolFromEndpoint(&endpoint, docInfo, filteredEndpoints)
-		if err != nil {
-			g.logger.Error("Failed to generate tool for endpoint", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.Error(err))
-			continue
+	return filteredEndpoints
+}
+
+// EnabledEndpoints returns the endpoints of a document except those whose tools are
+// disabled by disabledTools or skipped by unsupportedAuth, for the prompts and
+// resources derived from endpoints to leave these tools out as well
+func (g *ToolGenerator) EnabledEndpoints(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []types.SwaggerEndpoint {
+	skipUnsupported := g.config != nil && g.config.UnsupportedAuth == types.UnsupportedAuthSkip
+	if g.config == nil || (len(g.config.DisabledTools) == 0 && !skipUnsupported) {
+		return endpoints
+	}
+
+	// Tool names depend on the other endpoints tools are generated for
+	selected := g.selectEndpoints(endpoints, docInfo, func(*types.SwaggerEndpoint, SkipReason) {})
+	disabled := make(map[string]bool)
+	for i := range selected {
+		if g.toolDisabled(g.generateToolName(&selected[i], docInfo, selected)) || (skipUnsupported && g.unsupportedSecurity(&selected[i]) != "") {
+			disabled[selected[i].Method+" "+selected[i].Path] = true
 		}
+	}
+	if len(disabled) == 0 {
+		return endpoints
+	}
 
-		tools = append(tools, tool)
+	enabled := make([]types.SwaggerEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !disabled[endpoint.Method+" "+endpoint.Path] {
+			enabled = append(enabled, endpoint)
+		}
 	}
+	return enabled
+}
 
-	g.logger.Debug("Generated tools from document", zap.Int("toolCount", len(tools)), zap.String("title", docInfo.Title))
-	return tools, nil
+// toolDisabled reports whether a tool name matches one of the disabledTools patterns
+func (g *ToolGenerator) toolDisabled(name string) bool {
+	if g.config == nil {
+		return false
+	}
+	for _, pattern := range g.config.DisabledTools {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
 }
 
 // generateToolFromEndpoint generates a single MCP tool from a swagger endpoint
-func (g *ToolGenerator) generateToolFromEndpoint(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo, allEndpoints []types.SwaggerEndpoint) (*types.GeneratedTool, error) {
+func (g *ToolGenerator) generateToolFromEndpoint(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo, allEndpoints []types.SwaggerEndpoint, flattener *schemaFlattener) (*types.GeneratedTool, error) {
 	// Generate tool name
 	toolName := g.generateToolName(endpoint, docInfo, allEndpoints)
 
 	// Generate tool description
 	description := g.generateToolDescription(endpoint, docInfo)
 
-	// Generate input schema
-	inputSchema, err := g.generateInputSchema(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate input schema: %w", err)
-	}
-
 	tool := &types.GeneratedTool{
 		Name:         toolName,
 		Description:  description,
-		InputSchema:  inputSchema,
 		Endpoint:     endpoint,
 		DocumentInfo: docInfo,
 	}
 
+	// Generate input schema, or have it generated on first use
+	if g.schemas != nil {
+		tool.SchemaRoot = flattener.root
+		tool.LoadInputSchema = g.lazyInputSchema(endpoint, flattener.root)
+	} else {
+		inputSchema, err := g.generateInputSchema(endpoint, flattener)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate input schema: %w", err)
+		}
+		tool.InputSchema = inputSchema
+	}
+
+	// Pass vendor extensions (cost tier, SLA, owner team, ...) through as metadata
+	if len(endpoint.Extensions) > 0 {
+		tool.Metadata = make(map[string]interface{}, len(endpoint.Extensions))
+		for key, value := range endpoint.Extensions {
+			tool.Metadata[key] = value
+		}
+	}
+
 	return tool, nil
 }
 
@@ -161,7 +381,7 @@ func (g *ToolGenerator) generateToolName(endpoint *types.SwaggerEndpoint, docInf
 
 	// Check if we should append format to the tool name
 	formatSuffix := ""
-	if g.shouldAppendFormatToToolName(endpoint, allEndpoints) {
+	if g.shouldAppendFormatToToolName(endpoint, docInfo, allEndpoints) {
 		format := g.detectEndpointFormat(endpoint)
 		formatSuffix = fmt.Sprintf("_%s", format)
 		g.logger.Debug("Appending format to tool name to avoid conflicts", 
@@ -318,11 +538,25 @@ func (g *ToolGenerator) abbreviateToolName(name string, maxLength int) string {
 	return abbreviated
 }
 
-// detectEndpointFormat detects the format of an endpoint from its path
+// detectEndpointFormat detects the format of an endpoint, json, xml, or yaml, from the
+// media types it produces: the content types of its responses or its Swagger 2.0
+// produces list. The path's extension decides only when these name none of the formats
+// or more than one, and endpoints default to json.
 func (g *ToolGenerator) detectEndpointFormat(endpoint *types.SwaggerEndpoint) string {
-	path := strings.ToLower(endpoint.Path)
-	
+	var formats []string
+	for _, mediaType := range endpoint.Produces {
+		format := mediaTypeFormatFamily(mediaType)
+		if format == "" || slices.Contains(formats, format) {
+			continue
+		}
+		formats = append(formats, format)
+	}
+	if len(formats) == 1 {
+		return formats[0]
+	}
+
 	// Check for format in path extension
+	path := strings.ToLower(endpoint.Path)
 	if strings.HasSuffix(path, ".json") {
 		return "json"
 	} else if strings.HasSuffix(path, ".xml") {
@@ -330,50 +564,42 @@ func (g *ToolGenerator) detectEndpointFormat(endpoint *types.SwaggerEndpoint) st
 	} else if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
 		return "yaml"
 	}
+
+	// Endpoints negotiating several formats count as json when they produce it
+	if len(formats) > 1 && !slices.Contains(formats, "json") {
+		return formats[0]
+	}
 	
-	// Check for format in query parameters or path segments
-	if strings.Contains(path, "json") {
+	// Default to json if no format detected
+	return "json"
+}
+
+// mediaTypeFormatFamily returns json, xml, or yaml for media types of these formats,
+// including structured syntax suffixes such as application/geo+json, and "" for others
+func mediaTypeFormatFamily(mediaType string) string {
+	format := httpclient.MediaTypeFormat(mediaType)
+	switch {
+	case format == "json" || strings.HasSuffix(format, "+json"):
 		return "json"
-	} else if strings.Contains(path, "xml") {
+	case format == "xml" || strings.HasSuffix(format, "+xml"):
 		return "xml"
-	} else if strings.Contains(path, "yaml") || strings.Contains(path, "yml") {
+	case format == "yaml" || format == "x-yaml" || strings.HasSuffix(format, "+yaml"):
 		return "yaml"
 	}
-	
-	// Check response content types if available
-	if endpoint.Responses != nil {
-		for _, responseInterface := range endpoint.Responses {
-			if responseMap, ok := responseInterface.(map[string]interface{}); ok {
-				if content, ok := responseMap["content"].(map[string]interface{}); ok {
-					for contentType := range content {
-						contentTypeLower := strings.ToLower(contentType)
-						if strings.Contains(contentTypeLower, "json") {
-							return "json"
-						} else if strings.Contains(contentTypeLower, "xml") {
-							return "xml"
-						} else if strings.Contains(contentTypeLower, "yaml") {
-							return "yaml"
-						}
-					}
-				}
-			}
-		}
-	}
-	
-	// Default to json if no format detected
-	return "json"
+	return ""
 }
 
 // shouldSkipEndpointByFormat checks if an endpoint should be skipped based on format filtering
-func (g *ToolGenerator) shouldSkipEndpointByFormat(endpoint *types.SwaggerEndpoint) bool {
+func (g *ToolGenerator) shouldSkipEndpointByFormat(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) bool {
 	if g.config == nil {
 		return false
 	}
 	
 	format := g.detectEndpointFormat(endpoint)
+	_, ignoreFormats := g.documentFormats(docInfo)
 	
 	// Check if format should be ignored
-	for _, ignoredFormat := range g.config.IgnoreFormats {
+	for _, ignoredFormat := range ignoreFormats {
 		if strings.EqualFold(format, ignoredFormat) {
 			g.logger.Debug("Skipping endpoint due to ignored format", 
 				zap.String("method", endpoint.Method), 
@@ -387,15 +613,16 @@ func (g *ToolGenerator) shouldSkipEndpointByFormat(endpoint *types.SwaggerEndpoi
 }
 
 // shouldAppendFormatToToolName checks if format should be appended to tool name
-func (g *ToolGenerator) shouldAppendFormatToToolName(endpoint *types.SwaggerEndpoint, endpoints []types.SwaggerEndpoint) bool {
+func (g *ToolGenerator) shouldAppendFormatToToolName(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo, endpoints []types.SwaggerEndpoint) bool {
 	if g.config == nil {
 		return false
 	}
+	preferFormat, _ := g.documentFormats(docInfo)
 	
 	// If there's a preferred format and this endpoint matches it, don't append format
-	if g.config.PreferFormat != "" {
+	if preferFormat != "" {
 		currentFormat := g.detectEndpointFormat(endpoint)
-		if strings.EqualFold(currentFormat, g.config.PreferFormat) {
+		if strings.EqualFold(currentFormat, preferFormat) {
 			return false
 		}
 	}
@@ -420,10 +647,10 @@ func (g *ToolGenerator) shouldAppendFormatToToolName(endpoint *types.SwaggerEndp
 	
 	// If there are multiple unique formats, append format unless there's a preference
 	if len(uniqueFormats) > 1 {
-		if g.config.PreferFormat != "" {
+		if preferFormat != "" {
 			// Only append format if it's not the preferred format
 			currentFormat := g.detectEndpointFormat(endpoint)
-			return !strings.EqualFold(currentFormat, g.config.PreferFormat)
+			return !strings.EqualFold(currentFormat, preferFormat)
 		}
 		return true
 	}
@@ -497,25 +724,28 @@ func (g *ToolGenerator) createDocumentSuffix(title string) string {
 
 // generateToolDescription generates a description for the tool
 func (g *ToolGenerator) generateToolDescription(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) string {
-	// Start with endpoint summary or description
-	description := endpoint.Summary
-	if description == "" {
-		description = endpoint.Description
-	}
+	description, rendered := g.renderToolDescription(endpoint, docInfo)
+	if !rendered {
+		// Start with endpoint summary or description
+		description = endpoint.Summary
+		if description == "" {
+			description = endpoint.Description
+		}
 
-	// If no description available, generate one
-	if description == "" {
-		description = fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
-	}
+		// If no description available, generate one
+		if description == "" {
+			description = fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
+		}
 
-	// Add API version info
-	if docInfo.Version != "" {
-		description = fmt.Sprintf("[v%s] %s", docInfo.Version, description)
-	}
+		// Add API version info
+		if docInfo.Version != "" {
+			description = fmt.Sprintf("[v%s] %s", docInfo.Version, description)
+		}
 
-	// Add tags if available
-	if len(endpoint.Tags) > 0 {
-		description = fmt.Sprintf("%s (Tags: %s)", description, strings.Join(endpoint.Tags, ", "))
+		// Add tags if available
+		if len(endpoint.Tags) > 0 {
+			description = fmt.Sprintf("%s (Tags: %s)", description, strings.Join(endpoint.Tags, ", "))
+		}
 	}
 
 	// Truncate if too long (default max 200 characters)
@@ -527,8 +757,84 @@ func (g *ToolGenerator) generateToolDescription(endpoint *types.SwaggerEndpoint,
 	return description
 }
 
+// renderToolDescription renders the description of a tool with the description
+// template, reporting false when there is none or it fails to render
+func (g *ToolGenerator) renderToolDescription(endpoint *types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) (string, bool) {
+	if g.descriptionTemplate == nil {
+		return "", false
+	}
+
+	data := types.ToolDescriptionData{
+		Summary:     endpoint.Summary,
+		Description: endpoint.Description,
+		Method:      endpoint.Method,
+		Path:        endpoint.Path,
+		OperationID: endpoint.OperationID,
+		Tags:        endpoint.Tags,
+		Deprecated:  endpoint.Deprecated,
+		Title:       docInfo.Title,
+		Version:     docInfo.Version,
+		PackageIDs:  docInfo.PackageIDs,
+		Portfolios:  docInfo.TwcDomainPortfolio,
+		Domains:     docInfo.TwcDomain,
+		Usages:      docInfo.TwcUsageClassification,
+		Geographies: docInfo.TwcGeography,
+	}
+	var description strings.Builder
+	if err := g.descriptionTemplate.Execute(&description, data); err != nil {
+		g.logger.Warn("Failed to render tool description", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.Error(err))
+		return "", false
+	}
+	return strings.TrimSpace(description.String()), true
+}
+
+// lazyInputSchema returns a function generating the input schema of an endpoint
+// through the schema cache. Each generation resolves references with its own
+// flattener, as schemas may be generated concurrently.
+func (g *ToolGenerator) lazyInputSchema(endpoint *types.SwaggerEndpoint, root map[string]interface{}) func() map[string]interface{} {
+	return func() map[string]interface{} {
+		return g.schemas.get(endpoint, func() map[string]interface{} {
+			schema, err := g.generateInputSchema(endpoint, &schemaFlattener{root: root})
+			if err != nil {
+				g.logger.Error("Failed to generate input schema", zap.String("method", endpoint.Method), zap.String("path", endpoint.Path), zap.Error(err))
+				return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			}
+			return schema
+		})
+	}
+}
+
+// AttachInputSchema gives a tool restored without its input schema, e.g. from a
+// registry snapshot or the shared catalog, a way to generate it from its endpoint and
+// schema root: lazily when schemas are lazy, and right away otherwise
+func (g *ToolGenerator) AttachInputSchema(tool *types.GeneratedTool) {
+	if tool.InputSchema != nil || tool.LoadInputSchema != nil || tool.Endpoint == nil {
+		return
+	}
+	if g.schemas != nil {
+		tool.LoadInputSchema = g.lazyInputSchema(tool.Endpoint, tool.SchemaRoot)
+		return
+	}
+	schema, err := g.generateInputSchema(tool.Endpoint, &schemaFlattener{root: tool.SchemaRoot})
+	if err != nil {
+		g.logger.Error("Failed to generate input schema", zap.String("toolName", tool.Name), zap.Error(err))
+		schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	tool.InputSchema = schema
+}
+
+// hashSchemaSource returns the SHA-256 hash of the JSON encoding of a schema source
+func hashSchemaSource(source interface{}) string {
+	encoded, err := json.Marshal(source)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
 // generateInputSchema generates JSON schema for tool input parameters
-func (g *ToolGenerator) generateInputSchema(endpoint *types.SwaggerEndpoint) (map[string]interface{}, error) {
+func (g *ToolGenerator) generateInputSchema(endpoint *types.SwaggerEndpoint, flattener *schemaFlattener) (map[string]interface{}, error) {
 	schema := map[string]interface{}{
 		"type":       "object",
 		"properties": make(map[string]interface{}),
@@ -540,7 +846,7 @@ func (g *ToolGenerator) generateInputSchema(endpoint *types.SwaggerEndpoint) (ma
 
 	// Add parameters to schema
 	for _, param := range endpoint.Parameters {
-		paramSchema := g.generateParameterSchema(&param)
+		paramSchema := g.generateParameterSchema(&param, flattener)
 		properties[param.Name] = paramSchema
 
 		if param.Required {
@@ -557,7 +863,7 @@ func (g *ToolGenerator) generateInputSchema(endpoint *types.SwaggerEndpoint) (ma
 					if strings.Contains(contentType, "json") {
 						if schemaMap, ok := contentSchema.(map[string]interface{}); ok {
 							if schema, ok := schemaMap["schema"].(map[string]interface{}); ok {
-								properties["requestBody"] = schema
+								properties["requestBody"] = flattener.flatten(schema)
 
 								// Check if request body is required
 								if requiredVal, ok := requestBodyMap["required"].(bool); ok && requiredVal {
@@ -572,12 +878,47 @@ func (g *ToolGenerator) generateInputSchema(endpoint *types.SwaggerEndpoint) (ma
 		}
 	}
 
+	// Let callers choose among the representations the endpoint responds with
+	if formats := httpclient.EndpointFormats(endpoint); len(formats) > 1 {
+		if _, taken := properties[httpclient.FormatArgument]; !taken {
+			enum := make([]interface{}, len(formats))
+			for i, format := range formats {
+				enum[i] = format
+			}
+			properties[httpclient.FormatArgument] = map[string]interface{}{
+				"type":        "string",
+				"enum":        enum,
+				"description": "Optional response format, sent as the Accept header",
+			}
+		}
+	}
+
+	// Let callers of paginated endpoints have the pages followed for them
+	if endpoint.Pagination != nil {
+		if _, taken := properties[FetchAllArgument]; !taken {
+			properties[FetchAllArgument] = map[string]interface{}{
+				"type":        "boolean",
+				"description": "Optionally follow the result pages, up to the configured maximum, and return their merged results",
+			}
+		}
+	}
+
+	// Let callers narrow verbose JSON responses down to the fields they need
+	if g.config == nil || !g.config.OmitFilterArgument {
+		if _, taken := properties[FilterArgument]; !taken {
+			properties[FilterArgument] = map[string]interface{}{
+				"type":        "string",
+				"description": "Optional JMESPath expression applied to the JSON response, e.g. \"items[].{name: name, id: id}\"",
+			}
+		}
+	}
+
 	schema["required"] = required
 	return schema, nil
 }
 
 // generateParameterSchema generates schema for a single parameter
-func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) map[string]interface{} {
+func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter, flattener *schemaFlattener) map[string]interface{} {
 	schema := map[string]interface{}{
 		"type": "string", // Default to string
 	}
@@ -588,7 +929,7 @@ func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) m
 
 	// Extract type from parameter schema
 	if param.Schema != nil {
-		if schemaMap, ok := param.Schema.(map[string]interface{}); ok {
+		if schemaMap, ok := flattener.flatten(param.Schema).(map[string]interface{}); ok {
 			// Copy relevant schema properties
 			if paramType, ok := schemaMap["type"].(string); ok {
 				schema["type"] = paramType
@@ -599,6 +940,12 @@ func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) m
 			if enum, ok := schemaMap["enum"].([]interface{}); ok {
 				schema["enum"] = enum
 			}
+			if defaultValue, ok := schemaMap["default"]; ok {
+				schema["default"] = defaultValue
+			}
+			if examples, ok := schemaMap["examples"].([]interface{}); ok {
+				schema["examples"] = examples
+			}
 			if minimum, ok := schemaMap["minimum"]; ok {
 				schema["minimum"] = minimum
 			}
@@ -608,6 +955,25 @@ func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) m
 			if pattern, ok := schemaMap["pattern"].(string); ok {
 				schema["pattern"] = pattern
 			}
+			if items, ok := schemaMap["items"]; ok {
+				schema["items"] = items
+			}
+			if properties, ok := schemaMap["properties"]; ok {
+				schema["properties"] = properties
+			}
+			if required, ok := schemaMap["required"]; ok {
+				schema["required"] = required
+			}
+
+			// Expose composition variants; the variants carry their own types
+			for _, keyword := range []string{"oneOf", "anyOf"} {
+				if variants, ok := schemaMap[keyword].([]interface{}); ok {
+					schema[keyword] = variants
+					if _, hasType := schemaMap["type"]; !hasType {
+						delete(schema, "type")
+					}
+				}
+			}
 		}
 	}
 
@@ -615,6 +981,9 @@ func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) m
 	if param.Example != nil {
 		schema["example"] = param.Example
 	}
+	if len(param.Examples) > 0 {
+		schema["examples"] = param.Examples
+	}
 
 	// Add parameter location as metadata
 	schema["x-parameter-in"] = param.In
@@ -622,10 +991,14 @@ func (g *ToolGenerator) generateParameterSchema(param *types.SwaggerParameter) m
 	return schema
 }
 
-// sanitizeToolName sanitizes a tool name to be valid
+// sanitizeToolName sanitizes a tool name to be valid. Latin, Greek, and Cyrillic
+// letters are romanized; names losing letters of other scripts get a hash of the
+// original name appended, so that distinct names stay distinct.
 func (g *ToolGenerator) sanitizeToolName(name string) string {
-	// Convert to lowercase
-	name = strings.ToLower(name)
+	original := name
+
+	// Convert to lowercase and romanize non-ASCII letters
+	name, lossy := transliterate(strings.ToLower(name))
 
 	// Replace invalid characters with underscores
 	reg := regexp.MustCompile(`[^a-z0-9_]`)
@@ -638,6 +1011,14 @@ func (g *ToolGenerator) sanitizeToolName(name string) string {
 	// Remove leading/trailing underscores
 	name = strings.Trim(name, "_")
 
+	// Tell apart names whose letters were lost, and names of punctuation only
+	if lossy || (name == "" && strings.TrimSpace(original) != "") {
+		if name == "" {
+			name = "tool"
+		}
+		name = fmt.Sprintf("%s_%s", name, nameHash(original))
+	}
+
 	// Ensure name is not empty
 	if name == "" {
 		name = "unknown_tool"
@@ -0,0 +1,505 @@
+package swagger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	gqlparser "github.com/vektah/gqlparser/v2/parser"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Selection sets are generated to this depth so nested objects return useful data
+// without producing unbounded queries for recursive types
+const graphQLSelectionDepth = 3
+
+// isGraphQLExtension reports whether a file extension denotes GraphQL SDL
+func isGraphQLExtension(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".graphql", ".graphqls", ".gql":
+		return true
+	}
+	return false
+}
+
+// parseGraphQLSDL parses GraphQL schema definition language into a document
+func (p *Parser) parseGraphQLSDL(content []byte, location string) (*types.SwaggerDocument, error) {
+	schemaDocument, err := gqlparser.ParseSchema(&ast.Source{Name: location, Input: string(content)})
+	if err != nil {
+		return nil, fmt.Errorf("GraphQL SDL parsing error: %w", err)
+	}
+
+	schema := &types.GraphQLSchema{
+		QueryType:    "Query",
+		MutationType: "Mutation",
+		Types:        make(map[string]*types.GraphQLType),
+	}
+
+	for _, definition := range schemaDocument.Schema {
+		for _, operationType := range definition.OperationTypes {
+			switch operationType.Operation {
+			case ast.Query:
+				schema.QueryType = operationType.Type
+			case ast.Mutation:
+				schema.MutationType = operationType.Type
+			}
+		}
+	}
+
+	definitions := append(ast.DefinitionList{}, schemaDocument.Definitions...)
+	definitions = append(definitions, schemaDocument.Extensions...)
+	for _, definition := range definitions {
+		graphQLType, exists := schema.Types[definition.Name]
+		if !exists {
+			graphQLType = &types.GraphQLType{
+				Kind:        string(definition.Kind),
+				Name:        definition.Name,
+				Description: definition.Description,
+			}
+			schema.Types[definition.Name] = graphQLType
+		}
+
+		for _, field := range definition.Fields {
+			if definition.Kind == ast.InputObject {
+				graphQLType.InputFields = append(graphQLType.InputFields, types.GraphQLInputValue{
+					Name:         field.Name,
+					Description:  field.Description,
+					Type:         convertGraphQLASTType(field.Type),
+					DefaultValue: graphQLValueString(field.DefaultValue),
+				})
+				continue
+			}
+
+			graphQLField := types.GraphQLField{
+				Name:        field.Name,
+				Description: field.Description,
+				Type:        convertGraphQLASTType(field.Type),
+				Deprecated:  field.Directives.ForName("deprecated") != nil,
+			}
+			for _, argument := range field.Arguments {
+				graphQLField.Args = append(graphQLField.Args, types.GraphQLInputValue{
+					Name:         argument.Name,
+					Description:  argument.Description,
+					Type:         convertGraphQLASTType(argument.Type),
+					DefaultValue: graphQLValueString(argument.DefaultValue),
+				})
+			}
+			graphQLType.Fields = append(graphQLType.Fields, graphQLField)
+		}
+
+		for _, enumValue := range definition.EnumValues {
+			graphQLType.EnumValues = append(graphQLType.EnumValues, enumValue.Name)
+		}
+		graphQLType.PossibleTypes = append(graphQLType.PossibleTypes, definition.Types...)
+	}
+
+	return p.newGraphQLDocument(schema, location)
+}
+
+// parseGraphQLIntrospection converts an introspection query result into a document.
+// Both the bare {"__schema": ...} form and the {"data": {"__schema": ...}} response are accepted.
+func (p *Parser) parseGraphQLIntrospection(introspection map[string]interface{}, location string) (*types.SwaggerDocument, error) {
+	rawSchema, ok := introspection["__schema"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("introspection result has no __schema object")
+	}
+
+	schema := &types.GraphQLSchema{
+		Types: make(map[string]*types.GraphQLType),
+	}
+	if queryType, ok := rawSchema["queryType"].(map[string]interface{}); ok {
+		schema.QueryType, _ = queryType["name"].(string)
+	}
+	if mutationType, ok := rawSchema["mutationType"].(map[string]interface{}); ok {
+		schema.MutationType, _ = mutationType["name"].(string)
+	}
+
+	rawTypes, _ := rawSchema["types"].([]interface{})
+	for _, rawTypeInterface := range rawTypes {
+		rawType, ok := rawTypeInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		graphQLType := &types.GraphQLType{}
+		graphQLType.Kind, _ = rawType["kind"].(string)
+		graphQLType.Name, _ = rawType["name"].(string)
+		graphQLType.Description, _ = rawType["description"].(string)
+		if graphQLType.Name == "" {
+			continue
+		}
+
+		rawFields, _ := rawType["fields"].([]interface{})
+		for _, rawFieldInterface := range rawFields {
+			rawField, ok := rawFieldInterface.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			field := types.GraphQLField{
+				Type: convertGraphQLIntrospectionType(rawField["type"]),
+			}
+			field.Name, _ = rawField["name"].(string)
+			field.Description, _ = rawField["description"].(string)
+			field.Deprecated, _ = rawField["isDeprecated"].(bool)
+			field.Args = convertGraphQLIntrospectionInputValues(rawField["args"])
+			graphQLType.Fields = append(graphQLType.Fields, field)
+		}
+
+		graphQLType.InputFields = convertGraphQLIntrospectionInputValues(rawType["inputFields"])
+
+		rawEnumValues, _ := rawType["enumValues"].([]interface{})
+		for _, rawEnumValue := range rawEnumValues {
+			if enumValue, ok := rawEnumValue.(map[string]interface{}); ok {
+				if name, ok := enumValue["name"].(string); ok {
+					graphQLType.EnumValues = append(graphQLType.EnumValues, name)
+				}
+			}
+		}
+
+		rawPossibleTypes, _ := rawType["possibleTypes"].([]interface{})
+		for _, rawPossibleType := range rawPossibleTypes {
+			if possibleType, ok := rawPossibleType.(map[string]interface{}); ok {
+				if name, ok := possibleType["name"].(string); ok {
+					graphQLType.PossibleTypes = append(graphQLType.PossibleTypes, name)
+				}
+			}
+		}
+
+		schema.Types[graphQLType.Name] = graphQLType
+	}
+
+	return p.newGraphQLDocument(schema, location)
+}
+
+// newGraphQLDocument wraps a GraphQL schema in a document named after its source
+func (p *Parser) newGraphQLDocument(schema *types.GraphQLSchema, location string) (*types.SwaggerDocument, error) {
+	if schema.Types[schema.QueryType] == nil && schema.Types[schema.MutationType] == nil {
+		return nil, fmt.Errorf("GraphQL schema defines no query or mutation type")
+	}
+
+	title := "GraphQL API"
+	if location != "" {
+		title = strings.TrimSuffix(filepath.Base(location), filepath.Ext(location))
+	}
+
+	p.logger.Debug("Parsed GraphQL schema",
+		zap.String("location", location),
+		zap.Int("types", len(schema.Types)))
+
+	return &types.SwaggerDocument{
+		Info: &types.SwaggerInfo{
+			Title:   title,
+			Version: "1",
+		},
+		GraphQL: schema,
+	}, nil
+}
+
+// extractGraphQLEndpoints generates one endpoint per query and mutation field
+func (p *Parser) extractGraphQLEndpoints(document *types.SwaggerDocument) []types.SwaggerEndpoint {
+	schema := document.GraphQL
+
+	var endpoints []types.SwaggerEndpoint
+	for _, operation := range []struct {
+		keyword  string
+		typeName string
+	}{
+		{"query", schema.QueryType},
+		{"mutation", schema.MutationType},
+	} {
+		rootType, ok := schema.Types[operation.typeName]
+		if !ok {
+			continue
+		}
+
+		for _, field := range rootType.Fields {
+			if field.Name == "" {
+				continue
+			}
+			endpoints = append(endpoints, p.newGraphQLEndpoint(schema, operation.keyword, &field))
+		}
+	}
+
+	p.logger.Debug("Extracted GraphQL endpoints", zap.Int("count", len(endpoints)))
+	return endpoints
+}
+
+// newGraphQLEndpoint builds an endpoint whose query invokes a single root field
+func (p *Parser) newGraphQLEndpoint(schema *types.GraphQLSchema, keyword string, field *types.GraphQLField) types.SwaggerEndpoint {
+	endpoint := types.SwaggerEndpoint{
+		Path:        fmt.Sprintf("/%s/%s", keyword, field.Name),
+		Method:      strings.ToUpper(keyword),
+		OperationID: field.Name,
+		Description: field.Description,
+		Tags:        []string{"graphql", keyword},
+		Deprecated:  field.Deprecated,
+		Protocol:    types.EndpointProtocolGraphQL,
+	}
+	if endpoint.Description == "" {
+		endpoint.Description = fmt.Sprintf("GraphQL %s %s", keyword, field.Name)
+	}
+
+	var variables, arguments []string
+	for _, argument := range field.Args {
+		endpoint.Parameters = append(endpoint.Parameters, types.SwaggerParameter{
+			Name:        argument.Name,
+			In:          "argument",
+			Description: argument.Description,
+			Required:    argument.Type != nil && argument.Type.Kind == types.GraphQLKindNonNull && argument.DefaultValue == "",
+			Schema:      graphQLInputSchema(schema, argument.Type, 0),
+		})
+		variables = append(variables, fmt.Sprintf("$%s: %s", argument.Name, graphQLTypeString(argument.Type)))
+		arguments = append(arguments, fmt.Sprintf("%s: $%s", argument.Name, argument.Name))
+	}
+
+	var query strings.Builder
+	query.WriteString(keyword)
+	query.WriteString(" ")
+	query.WriteString(strings.ToUpper(field.Name[:1]) + field.Name[1:])
+	if len(variables) > 0 {
+		query.WriteString("(" + strings.Join(variables, ", ") + ")")
+	}
+	query.WriteString(" { ")
+	query.WriteString(field.Name)
+	if len(arguments) > 0 {
+		query.WriteString("(" + strings.Join(arguments, ", ") + ")")
+	}
+	if selection := graphQLSelectionSet(schema, field.Type, 0); selection != "" {
+		query.WriteString(" " + selection)
+	}
+	query.WriteString(" }")
+	endpoint.Query = query.String()
+
+	return endpoint
+}
+
+// graphQLSelectionSet builds a selection set for the named type behind a type reference.
+// Fields that require arguments are skipped; types without selectable fields fall back
+// to __typename.
+func graphQLSelectionSet(schema *types.GraphQLSchema, typeRef *types.GraphQLTypeRef, depth int) string {
+	graphQLType := schema.Types[graphQLNamedType(typeRef)]
+	if graphQLType == nil {
+		return ""
+	}
+
+	switch graphQLType.Kind {
+	case types.GraphQLKindObject, types.GraphQLKindInterface:
+	case types.GraphQLKindUnion:
+		return "{ __typename }"
+	default:
+		return ""
+	}
+
+	var selections []string
+	for _, field := range graphQLType.Fields {
+		if graphQLHasRequiredArgs(&field) {
+			continue
+		}
+
+		fieldType := schema.Types[graphQLNamedType(field.Type)]
+		if fieldType == nil || fieldType.Kind == types.GraphQLKindScalar || fieldType.Kind == types.GraphQLKindEnum {
+			selections = append(selections, field.Name)
+			continue
+		}
+
+		if depth+1 < graphQLSelectionDepth {
+			if nested := graphQLSelectionSet(schema, field.Type, depth+1); nested != "" {
+				selections = append(selections, field.Name+" "+nested)
+			}
+		}
+	}
+
+	if len(selections) == 0 {
+		selections = []string{"__typename"}
+	}
+
+	return "{ " + strings.Join(selections, " ") + " }"
+}
+
+// graphQLInputSchema converts a GraphQL input type into a JSON schema
+func graphQLInputSchema(schema *types.GraphQLSchema, typeRef *types.GraphQLTypeRef, depth int) map[string]interface{} {
+	if typeRef == nil {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch typeRef.Kind {
+	case types.GraphQLKindNonNull:
+		return graphQLInputSchema(schema, typeRef.OfType, depth)
+	case types.GraphQLKindList:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": graphQLInputSchema(schema, typeRef.OfType, depth),
+		}
+	}
+
+	switch typeRef.Name {
+	case "Int":
+		return map[string]interface{}{"type": "integer"}
+	case "Float":
+		return map[string]interface{}{"type": "number"}
+	case "Boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case "String", "ID":
+		return map[string]interface{}{"type": "string"}
+	}
+
+	graphQLType := schema.Types[typeRef.Name]
+	if graphQLType == nil {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch graphQLType.Kind {
+	case types.GraphQLKindEnum:
+		enum := make([]interface{}, len(graphQLType.EnumValues))
+		for i, value := range graphQLType.EnumValues {
+			enum[i] = value
+		}
+		return map[string]interface{}{"type": "string", "enum": enum}
+	case types.GraphQLKindInputObject:
+		result := map[string]interface{}{"type": "object"}
+		if graphQLType.Description != "" {
+			result["description"] = graphQLType.Description
+		}
+		if depth >= graphQLSelectionDepth {
+			return result
+		}
+
+		properties := make(map[string]interface{}, len(graphQLType.InputFields))
+		var required []interface{}
+		for _, inputField := range graphQLType.InputFields {
+			property := graphQLInputSchema(schema, inputField.Type, depth+1)
+			if inputField.Description != "" {
+				property["description"] = inputField.Description
+			}
+			properties[inputField.Name] = property
+			if inputField.Type != nil && inputField.Type.Kind == types.GraphQLKindNonNull && inputField.DefaultValue == "" {
+				required = append(required, inputField.Name)
+			}
+		}
+		result["properties"] = properties
+		if len(required) > 0 {
+			result["required"] = required
+		}
+		return result
+	}
+
+	// Custom scalars are passed through as strings
+	return map[string]interface{}{"type": "string"}
+}
+
+// graphQLTypeString renders a type reference in GraphQL syntax (e.g. [String!]!)
+func graphQLTypeString(typeRef *types.GraphQLTypeRef) string {
+	if typeRef == nil {
+		return "String"
+	}
+
+	switch typeRef.Kind {
+	case types.GraphQLKindNonNull:
+		return graphQLTypeString(typeRef.OfType) + "!"
+	case types.GraphQLKindList:
+		return "[" + graphQLTypeString(typeRef.OfType) + "]"
+	default:
+		return typeRef.Name
+	}
+}
+
+// graphQLNamedType unwraps NON_NULL and LIST modifiers
+func graphQLNamedType(typeRef *types.GraphQLTypeRef) string {
+	for typeRef != nil && typeRef.OfType != nil {
+		typeRef = typeRef.OfType
+	}
+	if typeRef == nil {
+		return ""
+	}
+	return typeRef.Name
+}
+
+// graphQLHasRequiredArgs reports whether a field cannot be selected without arguments
+func graphQLHasRequiredArgs(field *types.GraphQLField) bool {
+	for _, argument := range field.Args {
+		if argument.Type != nil && argument.Type.Kind == types.GraphQLKindNonNull && argument.DefaultValue == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// convertGraphQLASTType converts a parsed SDL type into a type reference
+func convertGraphQLASTType(astType *ast.Type) *types.GraphQLTypeRef {
+	if astType == nil {
+		return nil
+	}
+
+	var typeRef *types.GraphQLTypeRef
+	if astType.Elem != nil {
+		typeRef = &types.GraphQLTypeRef{Kind: types.GraphQLKindList, OfType: convertGraphQLASTType(astType.Elem)}
+	} else {
+		typeRef = &types.GraphQLTypeRef{Name: astType.NamedType}
+	}
+
+	if astType.NonNull {
+		return &types.GraphQLTypeRef{Kind: types.GraphQLKindNonNull, OfType: typeRef}
+	}
+	return typeRef
+}
+
+// convertGraphQLIntrospectionType converts an introspection type reference
+func convertGraphQLIntrospectionType(value interface{}) *types.GraphQLTypeRef {
+	rawType, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	typeRef := &types.GraphQLTypeRef{
+		OfType: convertGraphQLIntrospectionType(rawType["ofType"]),
+	}
+	typeRef.Kind, _ = rawType["kind"].(string)
+	typeRef.Name, _ = rawType["name"].(string)
+
+	return typeRef
+}
+
+// convertGraphQLIntrospectionInputValues converts introspection arguments or input fields
+func convertGraphQLIntrospectionInputValues(value interface{}) []types.GraphQLInputValue {
+	rawValues, _ := value.([]interface{})
+
+	var inputValues []types.GraphQLInputValue
+	for _, rawValueInterface := range rawValues {
+		rawValue, ok := rawValueInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		inputValue := types.GraphQLInputValue{
+			Type: convertGraphQLIntrospectionType(rawValue["type"]),
+		}
+		inputValue.Name, _ = rawValue["name"].(string)
+		inputValue.Description, _ = rawValue["description"].(string)
+		inputValue.DefaultValue, _ = rawValue["defaultValue"].(string)
+		inputValues = append(inputValues, inputValue)
+	}
+
+	return inputValues
+}
+
+// graphQLValueString renders an SDL default value
+func graphQLValueString(value *ast.Value) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+// graphQLIntrospectionSchema returns the __schema object of an introspection result, if any
+func graphQLIntrospectionSchema(document map[string]interface{}) map[string]interface{} {
+	if data, ok := document["data"].(map[string]interface{}); ok {
+		document = data
+	}
+	if _, ok := document["__schema"].(map[string]interface{}); ok {
+		return document
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package swagger
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// pathTemplateVariable matches google.api.http path variables such as {name} or {name=projects/*}
+var pathTemplateVariable = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+// isProtoDescriptorExtension reports whether a file extension denotes a protobuf descriptor set
+func isProtoDescriptorExtension(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".pb", ".protoset", ".desc":
+		return true
+	}
+	return false
+}
+
+// parseProtoDescriptorSet parses a binary FileDescriptorSet (protoc --descriptor_set_out)
+// into a document describing its gRPC services
+func (p *Parser) parseProtoDescriptorSet(content []byte, location string) (*types.SwaggerDocument, error) {
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &descriptorSet); err != nil {
+		return nil, fmt.Errorf("protobuf descriptor set parsing error: %w", err)
+	}
+
+	// Descriptor sets built without --include_imports may reference unknown files
+	files, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(&descriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set: %w", err)
+	}
+
+	schema := &types.GRPCSchema{}
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			schema.Services = append(schema.Services, p.convertGRPCService(services.Get(i)))
+		}
+		return true
+	})
+
+	if len(schema.Services) == 0 {
+		return nil, fmt.Errorf("protobuf descriptor set defines no services")
+	}
+
+	title := "gRPC API"
+	if location != "" {
+		title = strings.TrimSuffix(filepath.Base(location), filepath.Ext(location))
+	}
+
+	p.logger.Debug("Parsed protobuf descriptor set",
+		zap.String("location", location),
+		zap.Int("services", len(schema.Services)))
+
+	return &types.SwaggerDocument{
+		Info: &types.SwaggerInfo{
+			Title:   title,
+			Version: "1",
+		},
+		GRPC: schema,
+	}, nil
+}
+
+// convertGRPCService converts a service descriptor, resolving the HTTP binding of each method
+func (p *Parser) convertGRPCService(service protoreflect.ServiceDescriptor) types.GRPCService {
+	result := types.GRPCService{
+		Name:        string(service.Name()),
+		FullName:    string(service.FullName()),
+		Description: protoComments(service),
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+
+		grpcMethod := types.GRPCMethod{
+			Name:            string(method.Name()),
+			FullName:        string(method.FullName()),
+			Description:     protoComments(method),
+			InputType:       string(method.Input().FullName()),
+			OutputType:      string(method.Output().FullName()),
+			ClientStreaming: method.IsStreamingClient(),
+			ServerStreaming: method.IsStreamingServer(),
+			InputSchema:     protoMessageSchema(method.Input(), nil),
+			OutputSchema:    protoMessageSchema(method.Output(), nil),
+		}
+
+		if options, ok := method.Options().(*descriptorpb.MethodOptions); ok && options != nil {
+			grpcMethod.Deprecated = options.GetDeprecated()
+			if rule, ok := proto.GetExtension(options, annotations.E_Http).(*annotations.HttpRule); ok && rule != nil {
+				grpcMethod.HTTPMethod, grpcMethod.HTTPPath = httpRulePattern(rule)
+				grpcMethod.Body = rule.GetBody()
+			}
+		}
+
+		// Without an HTTP binding, use the unary JSON mapping served by Connect and
+		// gRPC-JSON transcoders: POST /package.Service/Method with the message as body
+		if grpcMethod.HTTPPath == "" {
+			grpcMethod.HTTPMethod = http.MethodPost
+			grpcMethod.HTTPPath = fmt.Sprintf("/%s/%s", service.FullName(), method.Name())
+			grpcMethod.Body = "*"
+		}
+
+		result.Methods = append(result.Methods, grpcMethod)
+	}
+
+	return result
+}
+
+// extractGRPCEndpoints generates one HTTP/JSON endpoint per unary gRPC method
+func (p *Parser) extractGRPCEndpoints(document *types.SwaggerDocument) []types.SwaggerEndpoint {
+	var endpoints []types.SwaggerEndpoint
+
+	for _, service := range document.GRPC.Services {
+		for _, method := range service.Methods {
+			// Streaming methods cannot be transcoded into a single request/response
+			if method.ClientStreaming || method.ServerStreaming {
+				p.logger.Debug("Skipping streaming gRPC method", zap.String("method", method.FullName))
+				continue
+			}
+
+			endpoints = append(endpoints, p.newGRPCEndpoint(&service, &method))
+		}
+	}
+
+	p.logger.Debug("Extracted gRPC endpoints", zap.Int("count", len(endpoints)))
+	return endpoints
+}
+
+// newGRPCEndpoint maps a method's input message onto path, query, and body parameters
+// following google.api.http transcoding rules
+func (p *Parser) newGRPCEndpoint(service *types.GRPCService, method *types.GRPCMethod) types.SwaggerEndpoint {
+	endpoint := types.SwaggerEndpoint{
+		Path:        pathTemplateVariable.ReplaceAllString(method.HTTPPath, "{$1}"),
+		Method:      method.HTTPMethod,
+		OperationID: method.Name,
+		Summary:     firstLine(method.Description),
+		Description: method.Description,
+		Tags:        []string{service.Name, "grpc"},
+		Deprecated:  method.Deprecated,
+		Protocol:    types.EndpointProtocolGRPC,
+		Responses: map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": method.OutputType,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": method.OutputSchema},
+				},
+			},
+		},
+	}
+
+	properties, _ := method.InputSchema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if requiredFields, ok := method.InputSchema["required"].([]interface{}); ok {
+		for _, name := range requiredFields {
+			if fieldName, ok := name.(string); ok {
+				required[fieldName] = true
+			}
+		}
+	}
+
+	// Path variables bind (possibly nested) fields of the request message
+	pathFields := make(map[string]bool)
+	for _, match := range pathTemplateVariable.FindAllStringSubmatch(method.HTTPPath, -1) {
+		fieldPath := match[1]
+		pathFields[protoJSONName(strings.Split(fieldPath, ".")[0])] = true
+		endpoint.Parameters = append(endpoint.Parameters, types.SwaggerParameter{
+			Name:     fieldPath,
+			In:       "path",
+			Required: true,
+			Schema:   protoFieldSchemaAt(method.InputSchema, fieldPath),
+		})
+	}
+
+	switch method.Body {
+	case "*":
+		// Every field not bound to the path is sent in the body
+		bodyProperties := make(map[string]interface{})
+		var bodyRequired []interface{}
+		for _, name := range sortedKeys(properties) {
+			if pathFields[name] {
+				continue
+			}
+			bodyProperties[name] = properties[name]
+			if required[name] {
+				bodyRequired = append(bodyRequired, name)
+			}
+		}
+		if len(bodyProperties) > 0 {
+			bodySchema := map[string]interface{}{"type": "object", "properties": bodyProperties}
+			if len(bodyRequired) > 0 {
+				bodySchema["required"] = bodyRequired
+			}
+			endpoint.RequestBody = protoRequestBody(bodySchema, len(bodyRequired) > 0)
+		}
+	case "":
+		endpoint.Parameters = append(endpoint.Parameters, protoQueryParameters(properties, required, pathFields)...)
+	default:
+		// A single field is sent as the body; the rest become query parameters
+		bodyField := protoJSONName(method.Body)
+		if bodySchema, ok := properties[bodyField].(map[string]interface{}); ok {
+			endpoint.RequestBody = protoRequestBody(bodySchema, required[bodyField])
+		}
+		pathFields[bodyField] = true
+		endpoint.Parameters = append(endpoint.Parameters, protoQueryParameters(properties, required, pathFields)...)
+	}
+
+	return endpoint
+}
+
+// protoQueryParameters exposes top-level scalar, enum, and repeated scalar fields as query parameters
+func protoQueryParameters(properties map[string]interface{}, required, excluded map[string]bool) []types.SwaggerParameter {
+	var parameters []types.SwaggerParameter
+	for _, name := range sortedKeys(properties) {
+		if excluded[name] {
+			continue
+		}
+
+		schema, _ := properties[name].(map[string]interface{})
+		if schema["type"] == "object" {
+			continue
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok && items["type"] == "object" {
+			continue
+		}
+
+		description, _ := schema["description"].(string)
+		parameters = append(parameters, types.SwaggerParameter{
+			Name:        name,
+			In:          "query",
+			Description: description,
+			Required:    required[name],
+			Schema:      schema,
+		})
+	}
+	return parameters
+}
+
+// protoRequestBody wraps a JSON schema in an OpenAPI request body
+func protoRequestBody(schema map[string]interface{}, required bool) map[string]interface{} {
+	return map[string]interface{}{
+		"required": required,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// protoFieldSchemaAt returns the schema of a dotted field path within a message schema
+func protoFieldSchemaAt(schema map[string]interface{}, fieldPath string) map[string]interface{} {
+	current := schema
+	for _, part := range strings.Split(fieldPath, ".") {
+		properties, _ := current["properties"].(map[string]interface{})
+		next, ok := properties[protoJSONName(part)].(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{"type": "string"}
+		}
+		current = next
+	}
+	return current
+}
+
+// httpRulePattern returns the HTTP method and path template of a google.api.http rule
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch {
+	case rule.GetGet() != "":
+		return http.MethodGet, rule.GetGet()
+	case rule.GetPost() != "":
+		return http.MethodPost, rule.GetPost()
+	case rule.GetPut() != "":
+		return http.MethodPut, rule.GetPut()
+	case rule.GetPatch() != "":
+		return http.MethodPatch, rule.GetPatch()
+	case rule.GetDelete() != "":
+		return http.MethodDelete, rule.GetDelete()
+	case rule.GetCustom() != nil:
+		return strings.ToUpper(rule.GetCustom().GetKind()), rule.GetCustom().GetPath()
+	}
+	return "", ""
+}
+
+// protoMessageSchema converts a message descriptor into a JSON schema following the
+// proto3 JSON mapping. Recursive messages are cut off at the first repetition.
+func protoMessageSchema(message protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	if schema := protoWellKnownSchema(message.FullName()); schema != nil {
+		return schema
+	}
+
+	schema := map[string]interface{}{"type": "object"}
+	if description := protoComments(message); description != "" {
+		schema["description"] = description
+	}
+	if visited[message.FullName()] {
+		return schema
+	}
+
+	nested := make(map[protoreflect.FullName]bool, len(visited)+1)
+	for name := range visited {
+		nested[name] = true
+	}
+	nested[message.FullName()] = true
+
+	properties := make(map[string]interface{})
+	var required []interface{}
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		fieldSchema := protoFieldSchema(field, nested)
+		if description := protoComments(field); description != "" {
+			fieldSchema["description"] = description
+		}
+		properties[field.JSONName()] = fieldSchema
+
+		if protoFieldRequired(field) {
+			required = append(required, field.JSONName())
+		}
+	}
+
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// protoFieldSchema converts a field descriptor into a JSON schema
+func protoFieldSchema(field protoreflect.FieldDescriptor, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	if field.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": protoSingularSchema(field.MapValue(), visited),
+		}
+	}
+
+	if field.IsList() {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": protoSingularSchema(field, visited),
+		}
+	}
+
+	return protoSingularSchema(field, visited)
+}
+
+// protoSingularSchema converts the element type of a field into a JSON schema
+func protoSingularSchema(field protoreflect.FieldDescriptor, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64-bit integers are encoded as strings in proto3 JSON
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		enum := make([]interface{}, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			enum = append(enum, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": enum}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageSchema(field.Message(), visited)
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// protoWellKnownSchema returns the JSON mapping of google.protobuf well-known types
+func protoWellKnownSchema(name protoreflect.FullName) map[string]interface{} {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return map[string]interface{}{"type": "string"}
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return map[string]interface{}{"type": "object"}
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array"}
+	case "google.protobuf.Value":
+		return map[string]interface{}{}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return map[string]interface{}{"type": "string"}
+	case "google.protobuf.BoolValue":
+		return map[string]interface{}{"type": "boolean"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return map[string]interface{}{"type": "integer"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return map[string]interface{}{"type": "number"}
+	}
+	return nil
+}
+
+// protoFieldRequired reports whether a field is marked with (google.api.field_behavior) = REQUIRED
+func protoFieldRequired(field protoreflect.FieldDescriptor) bool {
+	options, ok := field.Options().(*descriptorpb.FieldOptions)
+	if !ok || options == nil {
+		return false
+	}
+
+	behaviors, _ := proto.GetExtension(options, annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, behavior := range behaviors {
+		if behavior == annotations.FieldBehavior_REQUIRED {
+			return true
+		}
+	}
+	return false
+}
+
+// protoComments returns the leading comments of a descriptor, when source info was included
+func protoComments(descriptor protoreflect.Descriptor) string {
+	location := descriptor.ParentFile().SourceLocations().ByDescriptor(descriptor)
+	return strings.TrimSpace(location.LeadingComments)
+}
+
+// protoJSONName converts a proto field name to its lowerCamelCase JSON name
+func protoJSONName(name string) string {
+	var builder strings.Builder
+	upperNext := false
+	for _, r := range name {
+		if r == '_' {
+			upperNext = true
+			continue
+		}
+		if upperNext && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upperNext = false
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// firstLine returns the first line of a multi-line description
+func firstLine(text string) string {
+	if index := strings.Index(text, "\n"); index >= 0 {
+		return strings.TrimSpace(text[:index])
+	}
+	return text
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
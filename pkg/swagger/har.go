@@ -0,0 +1,446 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// harIdentifierSegment matches path segments that look like identifiers rather than resource names
+	harIdentifierSegment = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,}|-?\d+\.\d+,-?\d+\.\d+)$`)
+
+	// harStaticExtensions lists asset extensions that are never API calls
+	harStaticExtensions = map[string]bool{
+		".js": true, ".css": true, ".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+		".svg": true, ".ico": true, ".woff": true, ".woff2": true, ".ttf": true, ".map": true, ".html": true,
+	}
+)
+
+// harOperation accumulates the recorded requests for one method and path template
+type harOperation struct {
+	method        string
+	path          string
+	origin        string
+	pathParams    []string
+	queryValues   map[string][]string
+	queryCounts   map[string]int
+	samples       int
+	requestBodies []interface{}
+	responses     map[string][]interface{}
+	contentTypes  map[string]string
+}
+
+// harLog returns the log object of a HAR capture, if the document is one
+func harLog(document map[string]interface{}) map[string]interface{} {
+	log, ok := document["log"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if _, ok := log["entries"].([]interface{}); !ok {
+		return nil
+	}
+	return log
+}
+
+// convertHAR infers an OpenAPI 3 document from the requests recorded in a HAR capture.
+// Paths are templated by replacing identifier-like segments with parameters, and query,
+// request body, and response schemas are inferred from the recorded values.
+func (p *Parser) convertHAR(log map[string]interface{}, location string) map[string]interface{} {
+	entries, _ := log["entries"].([]interface{})
+
+	operations := make(map[string]*harOperation)
+	var order []string
+	origins := make(map[string]int)
+
+	for _, entryInterface := range entries {
+		entry, ok := entryInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		request, _ := entry["request"].(map[string]interface{})
+		response, _ := entry["response"].(map[string]interface{})
+
+		method, _ := request["method"].(string)
+		rawURL, _ := request["url"].(string)
+		parsedURL, err := url.Parse(rawURL)
+		if err != nil || method == "" || !parsedURL.IsAbs() {
+			continue
+		}
+		if !harIsAPIRequest(parsedURL, response) {
+			continue
+		}
+
+		origin := parsedURL.Scheme + "://" + parsedURL.Host
+		origins[origin]++
+
+		templatedPath, pathParams := harTemplatePath(parsedURL.Path)
+		key := strings.ToLower(method) + " " + templatedPath
+		operation, exists := operations[key]
+		if !exists {
+			operation = &harOperation{
+				method:       strings.ToLower(method),
+				path:         templatedPath,
+				origin:       origin,
+				pathParams:   pathParams,
+				queryValues:  make(map[string][]string),
+				queryCounts:  make(map[string]int),
+				responses:    make(map[string][]interface{}),
+				contentTypes: make(map[string]string),
+			}
+			operations[key] = operation
+			order = append(order, key)
+		}
+		operation.samples++
+
+		for name, values := range parsedURL.Query() {
+			operation.queryCounts[name]++
+			operation.queryValues[name] = append(operation.queryValues[name], values...)
+		}
+
+		if postData, ok := request["postData"].(map[string]interface{}); ok {
+			if body := harJSONBody(postData["mimeType"], postData["text"]); body != nil {
+				operation.requestBodies = append(operation.requestBodies, body)
+			}
+		}
+
+		if status, ok := response["status"].(float64); ok && status > 0 {
+			code := strconv.Itoa(int(status))
+			content, _ := response["content"].(map[string]interface{})
+			mimeType, _ := content["mimeType"].(string)
+			operation.contentTypes[code] = mimeType
+			if body := harJSONBody(content["mimeType"], content["text"]); body != nil {
+				operation.responses[code] = append(operation.responses[code], body)
+			} else if _, seen := operation.responses[code]; !seen {
+				operation.responses[code] = nil
+			}
+		}
+	}
+
+	// The most frequently seen origin becomes the document server
+	primaryOrigin := ""
+	for origin, count := range origins {
+		if count > origins[primaryOrigin] || (count == origins[primaryOrigin] && origin < primaryOrigin) {
+			primaryOrigin = origin
+		}
+	}
+
+	paths := make(map[string]interface{})
+	for _, key := range order {
+		operation := operations[key]
+		pathItem, _ := paths[operation.path].(map[string]interface{})
+		if pathItem == nil {
+			pathItem = make(map[string]interface{})
+			paths[operation.path] = pathItem
+		}
+		pathItem[operation.method] = harOperationObject(operation, primaryOrigin)
+	}
+
+	title := "Recorded API"
+	if location != "" {
+		title = strings.TrimSuffix(filepath.Base(location), filepath.Ext(location))
+	}
+
+	document := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       title,
+			"version":     "1",
+			"description": fmt.Sprintf("Inferred from %d recorded requests", len(entries)),
+		},
+		"paths": paths,
+	}
+	if primaryOrigin != "" {
+		document["servers"] = []interface{}{map[string]interface{}{"url": primaryOrigin}}
+	}
+
+	p.logger.Debug("Converted HAR capture",
+		zap.String("location", location),
+		zap.Int("entries", len(entries)),
+		zap.Int("operations", len(order)))
+
+	return document
+}
+
+// harOperationObject builds the OpenAPI operation for the accumulated requests
+func harOperationObject(operation *harOperation, primaryOrigin string) map[string]interface{} {
+	result := map[string]interface{}{
+		"operationId": harOperationID(operation.method, operation.path),
+		"summary":     fmt.Sprintf("%s %s (recorded %d time(s))", strings.ToUpper(operation.method), operation.path, operation.samples),
+	}
+
+	var parameters []interface{}
+	for _, name := range operation.pathParams {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	queryNames := make([]string, 0, len(operation.queryValues))
+	for name := range operation.queryValues {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+	for _, name := range queryNames {
+		values := operation.queryValues[name]
+		parameter := map[string]interface{}{
+			"name":     name,
+			"in":       "query",
+			"required": operation.queryCounts[name] == operation.samples,
+			"schema":   harQuerySchema(values),
+		}
+		if len(values) > 0 {
+			parameter["example"] = values[0]
+		}
+		parameters = append(parameters, parameter)
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+
+	if len(operation.requestBodies) > 0 {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": inferJSONSchema(operation.requestBodies)},
+			},
+		}
+	}
+
+	responses := make(map[string]interface{})
+	for code, bodies := range operation.responses {
+		response := map[string]interface{}{"description": fmt.Sprintf("Recorded %s response", code)}
+		if len(bodies) > 0 {
+			contentType := operation.contentTypes[code]
+			if contentType == "" || !strings.Contains(contentType, "json") {
+				contentType = "application/json"
+			}
+			response["content"] = map[string]interface{}{
+				strings.Split(contentType, ";")[0]: map[string]interface{}{"schema": inferJSONSchema(bodies)},
+			}
+		}
+		responses[code] = response
+	}
+	if len(responses) == 0 {
+		responses["default"] = map[string]interface{}{"description": "Recorded response"}
+	}
+	result["responses"] = responses
+
+	if operation.origin != primaryOrigin {
+		result["servers"] = []interface{}{map[string]interface{}{"url": operation.origin}}
+	}
+
+	return result
+}
+
+// harIsAPIRequest filters out static assets and non-data responses
+func harIsAPIRequest(requestURL *url.URL, response map[string]interface{}) bool {
+	if harStaticExtensions[strings.ToLower(filepath.Ext(requestURL.Path))] {
+		return false
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	mimeType, _ := content["mimeType"].(string)
+	mimeType = strings.ToLower(mimeType)
+	for _, prefix := range []string{"image/", "font/", "text/css", "text/html", "application/javascript", "text/javascript"} {
+		if strings.HasPrefix(mimeType, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// harTemplatePath replaces identifier-like path segments with named parameters
+func harTemplatePath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	used := make(map[string]int)
+
+	for i, segment := range segments {
+		if !harIdentifierSegment.MatchString(segment) {
+			continue
+		}
+
+		name := "id"
+		if i > 0 && segments[i-1] != "" && !strings.HasPrefix(segments[i-1], "{") {
+			name = harParameterName(segments[i-1]) + "Id"
+		}
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, used[name])
+		}
+
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// harParameterName derives a parameter name from the preceding resource segment
+func harParameterName(segment string) string {
+	var builder strings.Builder
+	upperNext := false
+	for _, r := range strings.TrimSuffix(segment, "s") {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			if upperNext && builder.Len() > 0 {
+				builder.WriteString(strings.ToUpper(string(r)))
+			} else {
+				builder.WriteRune(r)
+			}
+			upperNext = false
+		default:
+			upperNext = true
+		}
+	}
+	if builder.Len() == 0 {
+		return "resource"
+	}
+	return builder.String()
+}
+
+// harOperationID derives an operation ID from the method and path template
+func harOperationID(method, path string) string {
+	parts := []string{method}
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// harJSONBody decodes a recorded body when it is JSON
+func harJSONBody(mimeType, text interface{}) interface{} {
+	mime, _ := mimeType.(string)
+	body, _ := text.(string)
+	if body == "" || (mime != "" && !strings.Contains(strings.ToLower(mime), "json")) {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// harQuerySchema infers the type of a query parameter from its recorded values
+func harQuerySchema(values []string) map[string]interface{} {
+	schemaType := ""
+	for _, value := range values {
+		valueType := "string"
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			valueType = "integer"
+		} else if _, err := strconv.ParseFloat(value, 64); err == nil {
+			valueType = "number"
+		} else if value == "true" || value == "false" {
+			valueType = "boolean"
+		}
+
+		switch {
+		case schemaType == "":
+			schemaType = valueType
+		case schemaType == "integer" && valueType == "number", schemaType == "number" && valueType == "integer":
+			schemaType = "number"
+		case schemaType != valueType:
+			schemaType = "string"
+		}
+	}
+	if schemaType == "" {
+		schemaType = "string"
+	}
+	return map[string]interface{}{"type": schemaType}
+}
+
+// inferJSONSchema infers a JSON schema that covers every sample value
+func inferJSONSchema(samples []interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+
+	var objects []map[string]interface{}
+	var items []interface{}
+	kinds := make(map[string]bool)
+
+	for _, sample := range samples {
+		switch value := sample.(type) {
+		case map[string]interface{}:
+			kinds["object"] = true
+			objects = append(objects, value)
+		case []interface{}:
+			kinds["array"] = true
+			items = append(items, value...)
+		case string:
+			kinds["string"] = true
+		case bool:
+			kinds["boolean"] = true
+		case float64:
+			if value == float64(int64(value)) {
+				kinds["integer"] = true
+			} else {
+				kinds["number"] = true
+			}
+		}
+	}
+
+	if kinds["integer"] && kinds["number"] {
+		delete(kinds, "integer")
+	}
+	if len(kinds) != 1 {
+		// Mixed or null-only samples cannot be narrowed to a single type
+		return schema
+	}
+
+	for schemaType := range kinds {
+		schema["type"] = schemaType
+	}
+
+	switch schema["type"] {
+	case "object":
+		valuesByKey := make(map[string][]interface{})
+		counts := make(map[string]int)
+		for _, object := range objects {
+			for key, value := range object {
+				valuesByKey[key] = append(valuesByKey[key], value)
+				counts[key]++
+			}
+		}
+
+		keys := make([]string, 0, len(valuesByKey))
+		for key := range valuesByKey {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		properties := make(map[string]interface{}, len(valuesByKey))
+		var required []interface{}
+		for _, key := range keys {
+			properties[key] = inferJSONSchema(valuesByKey[key])
+			if counts[key] == len(objects) {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 && len(objects) > 1 {
+			schema["required"] = required
+		}
+	case "array":
+		if len(items) > 0 {
+			schema["items"] = inferJSONSchema(items)
+		}
+	}
+
+	return schema
+}
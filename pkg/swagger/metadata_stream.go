@@ -0,0 +1,202 @@
+package swagger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// streamingMetadataSize is the size from which the metadata of JSON files is streamed
+// rather than decoded from the whole document; smaller files are cheaper to decode
+const streamingMetadataSize = 16 << 20
+
+// streamJSONMetadata reads the top-level fields of a JSON document that the scanner
+// uses (the specification version, info, and x- extensions), decoding only them and
+// skipping the values of the others, such as paths and components, without keeping
+// them in memory
+func streamJSONMetadata(reader io.Reader) (map[string]interface{}, error) {
+	stream := &metadataStream{reader: bufio.NewReaderSize(reader, 64<<10)}
+	document := make(map[string]interface{})
+
+	b, err := stream.next()
+	if err != nil {
+		return nil, err
+	}
+	if b != '{' {
+		return nil, fmt.Errorf("document is not a JSON object")
+	}
+
+	for {
+		b, err = stream.next()
+		if err != nil {
+			return nil, err
+		}
+		if b == '}' {
+			return document, nil
+		}
+		if b != '"' {
+			return nil, fmt.Errorf("expected a field name, found %q", b)
+		}
+
+		var encodedKey bytes.Buffer
+		encodedKey.WriteByte('"')
+		if err := stream.stringBody(&encodedKey); err != nil {
+			return nil, err
+		}
+		var key string
+		if err := json.Unmarshal(encodedKey.Bytes(), &key); err != nil {
+			return nil, fmt.Errorf("invalid field name: %w", err)
+		}
+
+		if b, err = stream.next(); err != nil {
+			return nil, err
+		}
+		if b != ':' {
+			return nil, fmt.Errorf("expected ':' after field %q, found %q", key, b)
+		}
+		if b, err = stream.next(); err != nil {
+			return nil, err
+		}
+
+		if isMetadataField(key) {
+			var encoded bytes.Buffer
+			if err := stream.value(b, &encoded); err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if err := json.Unmarshal(encoded.Bytes(), &value); err != nil {
+				return nil, fmt.Errorf("invalid value of field %q: %w", key, err)
+			}
+			document[key] = value
+		} else if err := stream.value(b, nil); err != nil {
+			return nil, err
+		}
+
+		if b, err = stream.next(); err != nil {
+			return nil, err
+		}
+		switch b {
+		case ',':
+		case '}':
+			return document, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or '}' after field %q, found %q", key, b)
+		}
+	}
+}
+
+// isMetadataField reports whether a top-level field is read by the scanner
+func isMetadataField(key string) bool {
+	switch key {
+	case "swagger", "openapi", "asyncapi", "info":
+		return true
+	}
+	return strings.HasPrefix(key, "x-")
+}
+
+// metadataStream reads JSON values byte by byte, copying them to a buffer when they
+// are decoded and dropping them when they are skipped
+type metadataStream struct {
+	reader *bufio.Reader
+}
+
+// next returns the next byte that is not whitespace
+func (s *metadataStream) next() (byte, error) {
+	for {
+		b, err := s.reader.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, nil
+	}
+}
+
+// value reads the rest of the value starting with first, writing it to buffer unless
+// buffer is nil. Syntax errors within skipped values are left to the full parse.
+func (s *metadataStream) value(first byte, buffer *bytes.Buffer) error {
+	if buffer != nil {
+		buffer.WriteByte(first)
+	}
+
+	switch first {
+	case '"':
+		return s.stringBody(buffer)
+	case '{', '[':
+		for depth := 1; depth > 0; {
+			b, err := s.reader.ReadByte()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			if buffer != nil {
+				buffer.WriteByte(b)
+			}
+			switch b {
+			case '"':
+				if err := s.stringBody(buffer); err != nil {
+					return err
+				}
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		return nil
+	default:
+		// Numbers, booleans, and null end at the next delimiter
+		for {
+			b, err := s.reader.ReadByte()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			switch b {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return s.reader.UnreadByte()
+			}
+			if buffer != nil {
+				buffer.WriteByte(b)
+			}
+		}
+	}
+}
+
+// stringBody reads a string up to and including its closing quote, the opening quote
+// having been read
+func (s *metadataStream) stringBody(buffer *bytes.Buffer) error {
+	for {
+		b, err := s.reader.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if buffer != nil {
+			buffer.WriteByte(b)
+		}
+		switch b {
+		case '\\':
+			escaped, err := s.reader.ReadByte()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			if buffer != nil {
+				buffer.WriteByte(escaped)
+			}
+		case '"':
+			return nil
+		}
+	}
+}
+
+// unexpectedEOF reports the end of the document within a value as an error
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package swagger
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxMockDepth bounds how deeply nested schemas are expanded into mock values, so that
+// recursive schemas terminate
+const maxMockDepth = 8
+
+// mockStringFormats are the mock values of string schemas by format
+var mockStringFormats = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+	"password":  "********",
+}
+
+// MockResponse returns the status code and body of an example response for an endpoint.
+// The first documented success response is used, falling back to the default response.
+// Its body is the documented example or, failing that, a value derived from its schema
+// with local references resolved against document, which may be nil; it is nil when
+// the response has neither.
+func MockResponse(document *types.SwaggerDocument, endpoint *types.SwaggerEndpoint) (int, interface{}) {
+	status := mockStatus(endpoint.Responses)
+	statusCode := http.StatusOK
+	if code, err := strconv.Atoi(status); err == nil {
+		statusCode = code
+	}
+
+	response, ok := endpoint.Responses[status].(map[string]interface{})
+	if !ok {
+		return statusCode, nil
+	}
+	if example := mediaExample(response); example != nil {
+		return statusCode, example
+	}
+	schema := newSchemaFlattener(document).flatten(responseSchema(response))
+	return statusCode, MockValue(schema)
+}
+
+// MockValue derives an example value from a schema: its example, default, or first enum
+// value if declared, otherwise a placeholder of its type with every object property filled
+func MockValue(schema interface{}) interface{} {
+	return mockValue(schema, 0)
+}
+
+// mockValue derives an example value from a schema nested depth levels deep
+func mockValue(schema interface{}, depth int) interface{} {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if example := schemaExample(schemaMap); example != nil {
+		return example
+	}
+	if value, exists := schemaMap["default"]; exists {
+		return value
+	}
+	if value, exists := schemaMap["const"]; exists {
+		return value
+	}
+	if enum, ok := schemaMap["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	// Compositions: allOf merges its object parts, oneOf and anyOf take the first option
+	if allOf, ok := schemaMap["allOf"].([]interface{}); ok && len(allOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, part := range allOf {
+			value := mockValue(part, depth)
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return value
+			}
+			for key, propertyValue := range object {
+				merged[key] = propertyValue
+			}
+		}
+		return merged
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := schemaMap[keyword].([]interface{}); ok && len(options) > 0 {
+			return mockValue(options[0], depth)
+		}
+	}
+
+	switch schemaType(schemaMap) {
+	case "object":
+		object := make(map[string]interface{})
+		if depth >= maxMockDepth {
+			return object
+		}
+		properties, _ := schemaMap["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(properties) {
+			object[name] = mockValue(properties[name], depth+1)
+		}
+		return object
+	case "array":
+		if depth >= maxMockDepth {
+			return []interface{}{}
+		}
+		if item := mockValue(schemaMap["items"], depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "string":
+		format, _ := schemaMap["format"].(string)
+		if value, exists := mockStringFormats[format]; exists {
+			return value
+		}
+		return "string"
+	case "integer":
+		if minimum, ok := schemaMap["minimum"].(float64); ok {
+			return int64(minimum)
+		}
+		return 0
+	case "number":
+		if minimum, ok := schemaMap["minimum"].(float64); ok {
+			return minimum
+		}
+		return 0.0
+	case "boolean":
+		return true
+	default:
+		return nil
+	}
+}
+
+// schemaType returns the type of a schema, inferring objects and arrays from their
+// keywords. Of OpenAPI 3.1 type lists, the first type other than null is used.
+func schemaType(schema map[string]interface{}) string {
+	switch typed := schema["type"].(type) {
+	case string:
+		return typed
+	case []interface{}:
+		for _, item := range typed {
+			if name, ok := item.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// mockStatus returns the key of the response a mock returns: the first success
+// response, otherwise the default response
+func mockStatus(responses map[string]interface{}) string {
+	for _, status := range sortedKeys(responses) {
+		if strings.HasPrefix(status, "2") {
+			return status
+		}
+	}
+	if _, exists := responses["default"]; exists {
+		return "default"
+	}
+	return ""
+}
+
+// responseSchema returns the schema of an OpenAPI 3 response, preferring JSON media
+// types, or of a Swagger 2.0 response
+func responseSchema(response map[string]interface{}) interface{} {
+	content, ok := response["content"].(map[string]interface{})
+	if !ok {
+		return response["schema"]
+	}
+
+	mediaTypes := sortedKeys(content)
+	for _, mediaType := range mediaTypes {
+		if strings.Contains(mediaType, "json") {
+			media, _ := content[mediaType].(map[string]interface{})
+			return media["schema"]
+		}
+	}
+	if len(mediaTypes) > 0 {
+		media, _ := content[mediaTypes[0]].(map[string]interface{})
+		return media["schema"]
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package swagger
+
+import (
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// FetchAllArgument is the optional boolean tool argument of paginated endpoints that
+// follows their pages and returns the merged results
+const FetchAllArgument = "_fetchAll"
+
+// Query parameter names recognized as pagination parameters, normalized by
+// normalizePaginationName
+var (
+	cursorParamNames = map[string]bool{
+		"cursor": true, "after": true, "marker": true, "pagetoken": true, "nexttoken": true,
+		"continuationtoken": true, "nextpagetoken": true, "startcursor": true,
+	}
+	pageParamNames = map[string]bool{
+		"page": true, "pagenumber": true, "pagenum": true, "pageindex": true, "pageno": true,
+	}
+	offsetParamNames = map[string]bool{
+		"offset": true, "skip": true, "startindex": true,
+	}
+	sizeParamNames = map[string]bool{
+		"limit": true, "pagesize": true, "perpage": true, "size": true, "count": true,
+		"maxresults": true, "pagelimit": true, "top": true,
+	}
+)
+
+// Response fields holding the reference to the next page, normalized by
+// normalizePaginationName. Next links hold a URL; next tokens hold a cursor.
+var (
+	nextLinkFields = map[string]bool{
+		"next": true, "nextlink": true, "nexturl": true, "nextpage": true, "nextpageurl": true, "nextpagelink": true,
+	}
+	nextTokenFields = map[string]bool{
+		"nextcursor": true, "nextpagetoken": true, "nexttoken": true, "continuationtoken": true,
+	}
+	// paginationContainerFields hold next links and tokens one level down, as in
+	// {"links": {"next": ...}} or {"meta": {"nextCursor": ...}}
+	paginationContainerFields = map[string]bool{
+		"links": true, "pagination": true, "paging": true, "meta": true,
+	}
+)
+
+// normalizePaginationName lowercases a name and drops separators, so that page_size,
+// page-size, and pageSize compare equal
+func normalizePaginationName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", "$", "").Replace(name))
+}
+
+// DetectPagination returns how a GET endpoint pages its results: by a cursor, page, or
+// offset query parameter it declares, or by next links in its success response (a
+// next field in the body or a Link header). It returns nil for endpoints that are not
+// recognizably paginated. Local references of the response schema are resolved
+// against document, which may be nil.
+func DetectPagination(endpoint *types.SwaggerEndpoint, document *types.SwaggerDocument) *types.EndpointPagination {
+	if endpoint.Protocol != "" || !strings.EqualFold(endpoint.Method, "get") {
+		return nil
+	}
+
+	var cursor, page, offset, size string
+	for _, param := range endpoint.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		name := normalizePaginationName(param.Name)
+		switch {
+		case cursorParamNames[name] && cursor == "":
+			cursor = param.Name
+		case pageParamNames[name] && page == "":
+			page = param.Name
+		case offsetParamNames[name] && offset == "":
+			offset = param.Name
+		case sizeParamNames[name] && size == "":
+			size = param.Name
+		}
+	}
+
+	switch {
+	case cursor != "":
+		return &types.EndpointPagination{Style: types.PaginationStyleCursor, Param: cursor, SizeParam: size}
+	case page != "":
+		return &types.EndpointPagination{Style: types.PaginationStylePage, Param: page, SizeParam: size}
+	case offset != "":
+		return &types.EndpointPagination{Style: types.PaginationStyleOffset, Param: offset, SizeParam: size}
+	case respondsWithNextLinks(endpoint, document):
+		return &types.EndpointPagination{Style: types.PaginationStyleLink, SizeParam: size}
+	}
+	return nil
+}
+
+// respondsWithNextLinks reports whether the success response of an endpoint declares a
+// Link header or a next link field in its body schema
+func respondsWithNextLinks(endpoint *types.SwaggerEndpoint, document *types.SwaggerDocument) bool {
+	response, ok := endpoint.Responses[mockStatus(endpoint.Responses)].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	headers, _ := response["headers"].(map[string]interface{})
+	for name := range headers {
+		if strings.EqualFold(name, "Link") {
+			return true
+		}
+	}
+
+	schema, _ := newSchemaFlattener(document).flatten(responseSchema(response)).(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		normalized := normalizePaginationName(name)
+		if nextLinkFields[normalized] || nextTokenFields[normalized] {
+			return true
+		}
+		if paginationContainerFields[normalized] {
+			container, _ := property.(map[string]interface{})
+			nested, _ := container["properties"].(map[string]interface{})
+			for nestedName := range nested {
+				if normalizedNested := normalizePaginationName(nestedName); nextLinkFields[normalizedNested] || nextTokenFields[normalizedNested] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// NextPageReference returns the reference to the next page found in a decoded response
+// body: a next link URL, or a cursor token. Next links may be strings or link objects
+// with an href. It returns "" when the body references no next page.
+func NextPageReference(body interface{}) string {
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	if reference := nextPageField(object); reference != "" {
+		return reference
+	}
+	for _, name := range sortedKeys(object) {
+		if container, ok := object[name].(map[string]interface{}); ok && paginationContainerFields[normalizePaginationName(name)] {
+			if reference := nextPageField(container); reference != "" {
+				return reference
+			}
+		}
+	}
+	return ""
+}
+
+// nextPageField returns the value of the next link or next token field of an object
+func nextPageField(object map[string]interface{}) string {
+	for _, name := range sortedKeys(object) {
+		normalized := normalizePaginationName(name)
+		if !nextLinkFields[normalized] && !nextTokenFields[normalized] {
+			continue
+		}
+		switch value := object[name].(type) {
+		case string:
+			return value
+		case map[string]interface{}:
+			if href, ok := value["href"].(string); ok {
+				return href
+			}
+		}
+	}
+	return ""
+}
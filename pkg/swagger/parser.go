@@ -6,17 +6,21 @@ import (
 	"io/ioutil"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
+	httpclient "swagger-docs-mcp/pkg/http"
 	"swagger-docs-mcp/pkg/types"
 	"swagger-docs-mcp/pkg/utils"
 )
 
 // Parser handles swagger document parsing and validation
 type Parser struct {
-	logger *utils.Logger
+	logger    *utils.Logger
+	config    *types.SwaggerProcessingConfig
+	resolver  *RefResolver
+	validator *Validator
 }
 
 // NewParser creates a new swagger document parser
@@ -26,6 +30,29 @@ func NewParser(logger *utils.Logger) *Parser {
 	}
 }
 
+// NewParserWithConfig creates a new swagger document parser with processing configuration
+func NewParserWithConfig(logger *utils.Logger, config *types.SwaggerProcessingConfig) *Parser {
+	parser := NewParser(logger)
+	parser.config = config
+
+	if config != nil && config.ResolveReferences {
+		parser.resolver = NewRefResolver(logger, config.AllowedRefHosts)
+	}
+	if config != nil && config.ValidateDocuments {
+		parser.validator = NewValidator(logger)
+	}
+
+	return parser
+}
+
+// SetHostPolicy restricts the hosts external references are fetched from, including
+// the targets of redirects
+func (p *Parser) SetHostPolicy(hosts *httpclient.HostPolicy) {
+	if p.resolver != nil {
+		p.resolver.SetHostPolicy(hosts)
+	}
+}
+
 // ParseDocument parses a swagger document from file or URL
 func (p *Parser) ParseDocument(filePath string) (*types.SwaggerDocument, error) {
 	p.logger.Debug("Parsing document", zap.String("filePath", filePath))
@@ -47,10 +74,10 @@ func (p *Parser) ParseDocument(filePath string) (*types.SwaggerDocument, error)
 	}
 
 	// Determine format from file extension or content
-	format := p.detectFormat(filePath, content)
+	format := p.DetectFormat(filePath, content)
 
 	// Parse the content
-	document, err := p.parseContent(content, format)
+	document, err := p.parseContent(content, format, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse document %s (format: %s, size: %d bytes): %w", filePath, format, len(content), err)
 	}
@@ -69,10 +96,10 @@ func (p *Parser) ParseDocumentWithContent(docInfo *types.SwaggerDocumentInfo) (*
 	}
 
 	// Determine format from file path or content
-	format := p.detectFormat(docInfo.FilePath, docInfo.Content)
+	format := p.DetectFormat(docInfo.FilePath, docInfo.Content)
 
 	// Parse the content
-	document, err := p.parseContent(docInfo.Content, format)
+	document, err := p.parseContent(docInfo.Content, format, docInfo.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pre-fetched document %s (format: %s, content size: %d bytes): %w", docInfo.FilePath, format, len(docInfo.Content), err)
 	}
@@ -83,39 +110,105 @@ func (p *Parser) ParseDocumentWithContent(docInfo *types.SwaggerDocumentInfo) (*
 
 // ParseContent parses swagger content from bytes
 func (p *Parser) ParseContent(content []byte, format string) (*types.SwaggerDocument, error) {
-	return p.parseContent(content, format)
+	return p.parseContent(content, format, "")
 }
 
-// parseContent parses the content based on format
-func (p *Parser) parseContent(content []byte, format string) (*types.SwaggerDocument, error) {
-	var document types.SwaggerDocument
+// parseContent parses the content based on format. Location is the file path or URL the
+// content was loaded from and is used to resolve relative external references.
+func (p *Parser) parseContent(content []byte, format string, location string) (*types.SwaggerDocument, error) {
+	var raw interface{}
 
 	switch strings.ToLower(format) {
+	case "graphql":
+		return p.parseGraphQLSDL(content, location)
+	case "protoset":
+		return p.parseProtoDescriptorSet(content, location)
 	case "json":
-		if err := json.Unmarshal(content, &document); err != nil {
+		if err := json.Unmarshal(content, &raw); err != nil {
 			return nil, fmt.Errorf("JSON parsing error (content preview: %.100s...): %w", string(content), err)
 		}
 	case "yaml", "yml":
-		if err := yaml.Unmarshal(content, &document); err != nil {
+		decoded, err := decodeYAML(content)
+		if err != nil {
 			return nil, fmt.Errorf("YAML parsing error (content preview: %.100s...): %w", string(content), err)
 		}
+		raw = decoded
 	default:
 		// Try JSON first, then YAML
-		jsonErr := json.Unmarshal(content, &document)
+		jsonErr := json.Unmarshal(content, &raw)
 		if jsonErr != nil {
-			yamlErr := yaml.Unmarshal(content, &document)
+			decoded, yamlErr := decodeYAML(content)
 			if yamlErr != nil {
 				return nil, fmt.Errorf("failed to parse as JSON (error: %v) or YAML (error: %v) - content preview: %.100s...", jsonErr, yamlErr, string(content))
 			}
+			raw = decoded
+		}
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("document is empty")
+	}
+
+	// GraphQL introspection results describe a schema rather than an OpenAPI document
+	if rawMap, ok := raw.(map[string]interface{}); ok {
+		if introspection := graphQLIntrospectionSchema(rawMap); introspection != nil {
+			return p.parseGraphQLIntrospection(introspection, location)
+		}
+
+		// HAR captures are converted into an equivalent OpenAPI document
+		if log := harLog(rawMap); log != nil {
+			raw = p.convertHAR(log, location)
 		}
 	}
 
+	// Inline external references before the document is mapped onto its typed form
+	if p.resolver != nil {
+		raw = p.resolver.ResolveExternal(raw, location)
+	}
+
+	// AsyncAPI servers are keyed by name rather than listed, so set them aside before
+	// mapping onto the typed document
+	var asyncAPIServers map[string]interface{}
+	if rawMap, ok := raw.(map[string]interface{}); ok {
+		if _, isAsyncAPI := rawMap["asyncapi"]; isAsyncAPI {
+			asyncAPIServers, _ = rawMap["servers"].(map[string]interface{})
+			delete(rawMap, "servers")
+		}
+	}
+
+	// Round-trip through JSON so nested objects share the map[string]interface{} representation
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize document: %w", err)
+	}
+
+	var document types.SwaggerDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("document structure error: %w", err)
+	}
+	document.AsyncAPIServers = asyncAPIServers
+
 	// Validate that it's a valid swagger/openapi document
 	if err := p.validateDocument(&document); err != nil {
 		return nil, fmt.Errorf("document validation failed - not a valid OpenAPI/Swagger document (openapi: %s, swagger: %s, info.title: %s): %w",
 			document.OpenAPI, document.Swagger, getInfoTitle(&document), err)
 	}
 
+	// Run full specification validation when enabled; AsyncAPI documents are not OpenAPI
+	if p.validator != nil && document.AsyncAPI == "" {
+		if violations := p.validator.Validate(data, &document); len(violations) > 0 {
+			for _, violation := range violations {
+				p.logger.Warn("Document violates OpenAPI specification",
+					zap.String("location", location),
+					zap.String("title", getInfoTitle(&document)),
+					zap.String("violation", violation))
+			}
+			if !p.config.IgnoreErrors {
+				return nil, fmt.Errorf("document failed OpenAPI validation with %d violation(s): %s", len(violations), strings.Join(violations, "; "))
+			}
+		}
+	}
+
 	return &document, nil
 }
 
@@ -123,10 +216,23 @@ func (p *Parser) parseContent(content []byte, format string) (*types.SwaggerDocu
 func (p *Parser) ExtractEndpoints(document *types.SwaggerDocument) ([]types.SwaggerEndpoint, error) {
 	var endpoints []types.SwaggerEndpoint
 
+	if document.AsyncAPI != "" {
+		return p.extractAsyncAPIEndpoints(document), nil
+	}
+
+	if document.GraphQL != nil {
+		return p.extractGraphQLEndpoints(document), nil
+	}
+
+	if document.GRPC != nil {
+		return p.extractGRPCEndpoints(document), nil
+	}
+
 	if document.Paths == nil {
 		return endpoints, nil
 	}
 
+	securitySchemes := documentSecuritySchemes(document)
 	for path, pathItemInterface := range document.Paths {
 		pathItem, ok := pathItemInterface.(map[string]interface{})
 		if !ok {
@@ -169,6 +275,12 @@ func (p *Parser) ExtractEndpoints(document *types.SwaggerDocument) ([]types.Swag
 				endpoint.Deprecated = deprecated
 			}
 
+			// Extract vendor extensions; operation-level values override path-level ones
+			endpoint.Extensions = extractExtensions(pathItem, operation)
+			if toolName, ok := endpoint.Extensions["x-mcp-tool-name"].(string); ok {
+				endpoint.MCPToolName = toolName
+			}
+
 			// Extract tags
 			if tagsInterface, ok := operation["tags"].([]interface{}); ok {
 				for _, tagInterface := range tagsInterface {
@@ -207,10 +319,37 @@ func (p *Parser) ExtractEndpoints(document *types.SwaggerDocument) ([]types.Swag
 			if responses, ok := operation["responses"].(map[string]interface{}); ok {
 				endpoint.Responses = responses
 			}
+			endpoint.Produces = responseMediaTypes(operation, document)
+			endpoint.Pagination = DetectPagination(&endpoint, document)
 
-			// Extract security
+			// Extract security, which defaults to the document's requirements
 			if security, ok := operation["security"].([]interface{}); ok {
 				endpoint.Security = security
+			} else {
+				endpoint.Security = document.Security
+			}
+			endpoint.SecuritySchemes = securitySchemes
+
+			// Extract callbacks pushed by this operation
+			if callbacks, ok := operation["callbacks"].(map[string]interface{}); ok {
+				parent := fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+				for name, callback := range callbacks {
+					if expressions, ok := callback.(map[string]interface{}); ok {
+						for expression, pathItemInterface := range expressions {
+							endpoint.Callbacks = append(endpoint.Callbacks,
+								p.parseCallbackPathItem(name, types.CallbackKindCallback, expression, parent, pathItemInterface)...)
+						}
+					}
+				}
+			}
+
+			// Use the most specific servers: operation, then path, then document
+			if servers := p.parseServers(operation["servers"]); len(servers) > 0 {
+				endpoint.Servers = servers
+			} else if servers := p.parseServers(pathItem["servers"]); len(servers) > 0 {
+				endpoint.Servers = servers
+			} else {
+				endpoint.Servers = document.Servers
 			}
 
 			endpoints = append(endpoints, endpoint)
@@ -249,19 +388,177 @@ func (p *Parser) parseParameter(paramMap map[string]interface{}) types.SwaggerPa
 		param.Example = example
 	}
 
+	// OpenAPI 3 named examples; keep their values in a stable order
+	if examples, ok := paramMap["examples"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if example, ok := examples[name].(map[string]interface{}); ok {
+				if value, exists := example["value"]; exists {
+					param.Examples = append(param.Examples, value)
+				}
+			}
+		}
+	}
+
 	return param
 }
 
-// detectFormat detects the format of the content
-func (p *Parser) detectFormat(filePath string, content []byte) string {
+// ExtractCallbacks extracts all operation callbacks and top-level webhooks from a swagger document
+func (p *Parser) ExtractCallbacks(document *types.SwaggerDocument) ([]types.SwaggerCallback, error) {
+	endpoints, err := p.ExtractEndpoints(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var callbacks []types.SwaggerCallback
+	for _, endpoint := range endpoints {
+		callbacks = append(callbacks, endpoint.Callbacks...)
+	}
+
+	for name, pathItemInterface := range document.Webhooks {
+		callbacks = append(callbacks, p.parseCallbackPathItem(name, types.CallbackKindWebhook, "", "", pathItemInterface)...)
+	}
+
+	// Endpoints come in no particular order, so callbacks are ordered by all of their
+	// identifying fields
+	sort.SliceStable(callbacks, func(i, j int) bool {
+		a, b := callbacks[i], callbacks[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Parent != b.Parent {
+			return a.Parent < b.Parent
+		}
+		return a.Expression < b.Expression
+	})
+
+	return callbacks, nil
+}
+
+// parseCallbackPathItem parses the operations of a callback or webhook path item
+func (p *Parser) parseCallbackPathItem(name, kind, expression, parent string, pathItemInterface interface{}) []types.SwaggerCallback {
+	pathItem, ok := pathItemInterface.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var callbacks []types.SwaggerCallback
+	for method, operationInterface := range pathItem {
+		if !isHTTPMethod(method) {
+			continue
+		}
+
+		operation, ok := operationInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		callback := types.SwaggerCallback{
+			Name:       name,
+			Kind:       kind,
+			Expression: expression,
+			Method:     strings.ToUpper(method),
+			Parent:     parent,
+		}
+		if operationID, ok := operation["operationId"].(string); ok {
+			callback.OperationID = operationID
+		}
+		if summary, ok := operation["summary"].(string); ok {
+			callback.Summary = summary
+		}
+		if description, ok := operation["description"].(string); ok {
+			callback.Description = description
+		}
+		if requestBody, ok := operation["requestBody"]; ok {
+			callback.RequestBody = requestBody
+		}
+		if responses, ok := operation["responses"].(map[string]interface{}); ok {
+			callback.Responses = responses
+		}
+
+		callbacks = append(callbacks, callback)
+	}
+
+	return callbacks
+}
+
+// extractExtensions collects x-* vendor extensions from the given objects, with later
+// objects taking precedence
+func extractExtensions(objects ...map[string]interface{}) map[string]interface{} {
+	var extensions map[string]interface{}
+	for _, object := range objects {
+		for key, value := range object {
+			if !strings.HasPrefix(key, "x-") {
+				continue
+			}
+			if extensions == nil {
+				extensions = make(map[string]interface{})
+			}
+			extensions[key] = value
+		}
+	}
+	return extensions
+}
+
+// parseServers parses an OpenAPI servers array
+func (p *Parser) parseServers(value interface{}) []types.SwaggerServer {
+	serversInterface, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var servers []types.SwaggerServer
+	for _, serverInterface := range serversInterface {
+		serverMap, ok := serverInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		server := types.SwaggerServer{}
+		if serverURL, ok := serverMap["url"].(string); ok {
+			server.URL = serverURL
+		}
+		if description, ok := serverMap["description"].(string); ok {
+			server.Description = description
+		}
+		if variables, ok := serverMap["variables"].(map[string]interface{}); ok {
+			server.Variables = variables
+		}
+
+		if server.URL != "" {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers
+}
+
+// DetectFormat detects the format of a document from its file extension or content
+func (p *Parser) DetectFormat(filePath string, content []byte) string {
 	// First try to detect from file extension
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
-	case ".json":
+	case ".json", ".har":
 		return "json"
 	case ".yaml", ".yml":
 		return "yaml"
 	}
+	if isGraphQLExtension(ext) {
+		return "graphql"
+	}
+	if isProtoDescriptorExtension(ext) {
+		return "protoset"
+	}
 
 	// Try to detect from content
 	trimmed := strings.TrimSpace(string(content))
@@ -283,8 +580,8 @@ func (p *Parser) fetchURL(urlStr string) ([]byte, error) {
 // validateDocument validates that the document is a valid swagger/openapi document
 func (p *Parser) validateDocument(document *types.SwaggerDocument) error {
 	// Check for OpenAPI or Swagger version
-	if document.OpenAPI == "" && document.Swagger == "" {
-		return fmt.Errorf("missing required version field - document must have either 'openapi' or 'swagger' field")
+	if document.OpenAPI == "" && document.Swagger == "" && document.AsyncAPI == "" {
+		return fmt.Errorf("missing required version field - document must have an 'openapi', 'swagger', or 'asyncapi' field")
 	}
 
 	// Check for info section
@@ -300,6 +597,14 @@ func (p *Parser) validateDocument(document *types.SwaggerDocument) error {
 		return fmt.Errorf("missing required 'info.version' field - API version is mandatory")
 	}
 
+	// AsyncAPI documents describe channels instead of paths
+	if document.AsyncAPI != "" {
+		if len(document.Channels) == 0 {
+			p.logger.Warn("AsyncAPI document has no channels defined - no streaming endpoints will be available for tool generation")
+		}
+		return nil
+	}
+
 	// Check for paths
 	if document.Paths == nil {
 		p.logger.Warn("Document has no paths defined - no API endpoints will be available for tool generation")
@@ -316,6 +621,54 @@ func isURL(str string) bool {
 	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
 }
 
+// responseMediaTypes returns the media types an operation can respond with: the content
+// types of its OpenAPI 3 success and default responses, or its Swagger 2.0 produces
+// list, which defaults to the document's
+func responseMediaTypes(operation map[string]interface{}, document *types.SwaggerDocument) []string {
+	if produces, ok := operation["produces"].([]interface{}); ok {
+		var mediaTypes []string
+		for _, mediaType := range produces {
+			if name, ok := mediaType.(string); ok {
+				mediaTypes = append(mediaTypes, name)
+			}
+		}
+		return mediaTypes
+	}
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	seen := make(map[string]bool)
+	var mediaTypes []string
+	for _, status := range sortedKeys(responses) {
+		// Error responses often have media types of their own, such as problem+json
+		if !strings.HasPrefix(status, "2") && status != "default" {
+			continue
+		}
+		response, _ := responses[status].(map[string]interface{})
+		content, _ := response["content"].(map[string]interface{})
+		for _, mediaType := range sortedKeys(content) {
+			if !seen[mediaType] {
+				seen[mediaType] = true
+				mediaTypes = append(mediaTypes, mediaType)
+			}
+		}
+	}
+	if len(mediaTypes) == 0 && document.Swagger != "" {
+		return document.Produces
+	}
+	return mediaTypes
+}
+
+// documentSecuritySchemes returns the security schemes of a document by name: its
+// OpenAPI 3 components.securitySchemes or its Swagger 2.0 securityDefinitions
+func documentSecuritySchemes(document *types.SwaggerDocument) map[string]interface{} {
+	if components, ok := document.Components.(map[string]interface{}); ok {
+		if schemes, ok := components["securitySchemes"].(map[string]interface{}); ok {
+			return schemes
+		}
+	}
+	return document.SecurityDefinitions
+}
+
 // isHTTPMethod checks if a string is a valid HTTP method
 func isHTTPMethod(method string) bool {
 	httpMethods := []string{
@@ -347,6 +700,7 @@ func (p *Parser) GetDocumentInfo(document *types.SwaggerDocument) types.SwaggerD
 	info.TwcDomain = p.extractStringArray(document.XTwcDomain)
 	info.TwcUsageClassification = p.extractStringArray(document.XTwcUsageClassification)
 	info.TwcGeography = p.extractStringArray(document.XTwcGeography)
+	info.PreferFormat = strings.TrimSpace(document.XMCPPreferFormat)
 
 	return info
 }
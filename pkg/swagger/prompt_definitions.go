@@ -0,0 +1,153 @@
+package swagger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// applyPromptDefinitions replaces and extends the prompts generated for a document with
+// its prompt definitions. Every definition takes the place of the generated prompt with
+// its name. A versioned definition is registered as name@version, and the name alone
+// refers to the unversioned definition, or else to the highest version.
+func (g *PromptGenerator) applyPromptDefinitions(prompts []*types.GeneratedPrompt, doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) []*types.GeneratedPrompt {
+	definitions := g.documentPromptDefinitions(doc, docInfo)
+	if len(definitions) == 0 {
+		return prompts
+	}
+
+	var names []string
+	byName := make(map[string][]types.PromptDefinition)
+	for _, definition := range definitions {
+		if definition.Name == "" || (strings.TrimSpace(definition.Template) == "" && len(definition.Messages) == 0) {
+			g.logger.Warn("Skipping prompt definition without a name or template",
+				zap.String("document", docInfo.FilePath),
+				zap.String("promptName", definition.Name))
+			continue
+		}
+		if err := validatePromptMessages(definition.Messages); err != nil {
+			g.logger.Warn("Skipping prompt definition with invalid messages",
+				zap.Error(err),
+				zap.String("document", docInfo.FilePath),
+				zap.String("promptName", definition.Name))
+			continue
+		}
+		if _, exists := byName[definition.Name]; !exists {
+			names = append(names, definition.Name)
+		}
+		byName[definition.Name] = append(byName[definition.Name], definition)
+	}
+
+	var defined []*types.GeneratedPrompt
+	for _, name := range names {
+		versions := byName[name]
+		var current *types.PromptDefinition
+		for i := range versions {
+			definition := &versions[i]
+			if definition.Version == "" {
+				current = definition
+				continue
+			}
+			defined = append(defined, definedPrompt(definition, name+"@"+definition.Version, docInfo))
+			if current == nil || (current.Version != "" && compareVersions(definition.Version, current.Version) > 0) {
+				current = definition
+			}
+		}
+		defined = append(defined, definedPrompt(current, name, docInfo))
+	}
+
+	kept := prompts[:0]
+	for _, prompt := range prompts {
+		if _, replaced := byName[prompt.Name]; !replaced {
+			kept = append(kept, prompt)
+		}
+	}
+	return append(kept, defined...)
+}
+
+// documentPromptDefinitions returns the prompt definitions of a document: those of its
+// x-mcp-prompts extension, then those of the matching prompts.documentPrompts entries
+// in key order. A later definition replaces an earlier one with the same name and
+// version.
+func (g *PromptGenerator) documentPromptDefinitions(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) []types.PromptDefinition {
+	definitions := append([]types.PromptDefinition(nil), doc.XMCPPrompts...)
+
+	keys := make([]string, 0, len(g.config.DocumentPrompts))
+	for key := range g.config.DocumentPrompts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if MatchesDocument(key, docInfo) {
+			definitions = append(definitions, g.config.DocumentPrompts[key]...)
+		}
+	}
+
+	index := make(map[string]int)
+	var merged []types.PromptDefinition
+	for _, definition := range definitions {
+		key := definition.Name + "@" + definition.Version
+		if i, exists := index[key]; exists {
+			merged[i] = definition
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, definition)
+	}
+	return merged
+}
+
+// definedPrompt creates the prompt of a prompt definition under the given name. Its
+// arguments default to the template's placeholders.
+func definedPrompt(definition *types.PromptDefinition, name string, docInfo *types.SwaggerDocumentInfo) *types.GeneratedPrompt {
+	arguments := definition.Arguments
+	if arguments == nil {
+		arguments = placeholderArguments(definition.Template, definition.Messages)
+	}
+	tags := definition.Tags
+	if definition.Version != "" {
+		tags = append(append([]string(nil), tags...), fmt.Sprintf("version:%s", definition.Version))
+	}
+
+	return &types.GeneratedPrompt{
+		Name:        name,
+		Description: definition.Description,
+		Arguments:   arguments,
+		Category:    types.WeatherPromptCategory(definition.Category),
+		Template:    strings.TrimSpace(definition.Template),
+		Examples:    definition.Examples,
+		Tags:        tags,
+		Source:      docInfo,
+		Messages:    definition.Messages,
+	}
+}
+
+// compareVersions compares two prompt versions by their dot-separated parts, numerically
+// where both parts are numbers, e.g. 1.10 is higher than 1.9
+func compareVersions(a, b string) int {
+	aParts := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+		case aParts[i] != bParts[i]:
+			if aParts[i] < bParts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(aParts) - len(bParts)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	"go.uber.org/zap"
 	"swagger-docs-mcp/pkg/types"
@@ -14,6 +15,11 @@ import (
 type PromptGenerator struct {
 	logger *utils.Logger
 	config *types.PromptsConfig
+	// endpointFilter leaves out endpoints no prompts are generated for
+	endpointFilter func(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []types.SwaggerEndpoint
+	// templates are the prompts loaded from prompts.templateDir, keyed by name
+	templates      map[string]*types.GeneratedPrompt
+	templatesMutex sync.RWMutex
 }
 
 // NewPromptGenerator creates a new prompt generator
@@ -24,6 +30,12 @@ func NewPromptGenerator(logger *utils.Logger, config *types.PromptsConfig) *Prom
 	}
 }
 
+// SetEndpointFilter sets a function selecting the endpoints of a document prompts are
+// generated from, such as ToolGenerator.EnabledEndpoints
+func (g *PromptGenerator) SetEndpointFilter(filter func(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []types.SwaggerEndpoint) {
+	g.endpointFilter = filter
+}
+
 // GeneratePromptsFromDocument generates prompts from a parsed Swagger document
 func (g *PromptGenerator) GeneratePromptsFromDocument(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) ([]*types.GeneratedPrompt, error) {
 	if !g.config.Enabled {
@@ -36,6 +48,9 @@ func (g *PromptGenerator) GeneratePromptsFromDocument(doc *types.SwaggerDocument
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract endpoints: %w", err)
 	}
+	if g.endpointFilter != nil {
+		endpoints = g.endpointFilter(endpoints, docInfo)
+	}
 
 	var prompts []*types.GeneratedPrompt
 	
@@ -53,9 +68,16 @@ func (g *PromptGenerator) GeneratePromptsFromDocument(doc *types.SwaggerDocument
 	categoryPrompts := g.generateCategoryPrompts(endpoints, docInfo)
 	prompts = append(prompts, categoryPrompts...)
 
-	// Generate comparison and analysis prompts
-	analysisPrompts := g.generateAnalysisPrompts(endpoints, docInfo)
-	prompts = append(prompts, analysisPrompts...)
+	// Generate comparison and analysis prompts, which are about weather
+	if !g.config.DisableWeatherCategories {
+		analysisPrompts := g.generateAnalysisPrompts(endpoints, docInfo)
+		prompts = append(prompts, analysisPrompts...)
+	}
+
+	// Prompts defined for the document, and then prompt templates, take the place of
+	// generated prompts with the same name
+	prompts = g.applyPromptDefinitions(prompts, doc, docInfo)
+	prompts = g.withoutOverridden(prompts)
 
 	g.logger.Debug("Generated prompts from document",
 		zap.String("document", docInfo.FilePath),
@@ -178,12 +200,28 @@ func (g *PromptGenerator) createCategoryPrompt(category types.WeatherPromptCateg
 	name := fmt.Sprintf("get-%s-overview", string(category))
 	description := fmt.Sprintf("Get comprehensive %s information", string(category))
 	
-	template := g.createCategoryTemplate(category, endpoints)
-	arguments := g.createCategoryArguments(category, endpoints)
-
+	var template string
+	var arguments []types.MCPPromptArgument
 	var examples []types.PromptExample
-	if g.config.IncludeExamples {
-		examples = g.createCategoryExamples(category, endpoints)
+	if definition := g.categoryDefinition(category); definition != nil {
+		// Configured categories describe their own prompt and have no weather examples
+		if definition.Description != "" {
+			description = definition.Description
+		}
+		template = definition.Template
+		if template == "" {
+			template = g.createDefinedCategoryTemplate(category, endpoints)
+		}
+		arguments = definition.Arguments
+		if arguments == nil {
+			arguments = placeholderArguments(template, nil)
+		}
+	} else {
+		template = g.createCategoryTemplate(category, endpoints)
+		arguments = g.createCategoryArguments(category, endpoints)
+		if g.config.IncludeExamples {
+			examples = g.createCategoryExamples(category, endpoints)
+		}
 	}
 
 	return &types.GeneratedPrompt{
@@ -296,6 +334,18 @@ func (g *PromptGenerator) categorizeEndpoint(endpoint *types.SwaggerEndpoint) ty
 	
 	text := fmt.Sprintf("%s %s %s", path, summary, description)
 
+	// Configured categories, in order
+	for _, definition := range g.config.CategoryDefinitions {
+		for _, keyword := range definition.Keywords {
+			if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+				return types.WeatherPromptCategory(definition.Name)
+			}
+		}
+	}
+	if g.config.DisableWeatherCategories {
+		return ""
+	}
+
 	// Current conditions
 	if g.containsAny(text, []string{"current", "conditions", "now", "present"}) {
 		return types.CurrentConditions
@@ -334,6 +384,17 @@ func (g *PromptGenerator) categorizeEndpoint(endpoint *types.SwaggerEndpoint) ty
 	return ""
 }
 
+// categoryDefinition returns the configured definition of a category, or nil for the
+// built-in weather categories
+func (g *PromptGenerator) categoryDefinition(category types.WeatherPromptCategory) *types.PromptCategoryDefinition {
+	for i := range g.config.CategoryDefinitions {
+		if g.config.CategoryDefinitions[i].Name == string(category) {
+			return &g.config.CategoryDefinitions[i]
+		}
+	}
+	return nil
+}
+
 // containsAny checks if text contains any of the given keywords
 func (g *PromptGenerator) containsAny(text string, keywords []string) bool {
 	for _, keyword := range keywords {
@@ -497,6 +558,26 @@ func (g *PromptGenerator) createCategoryTemplate(category types.WeatherPromptCat
 	return template
 }
 
+// createDefinedCategoryTemplate creates the default template for the prompt of a
+// configured category, listing the operations of its endpoints
+func (g *PromptGenerator) createDefinedCategoryTemplate(category types.WeatherPromptCategory, endpoints []*types.SwaggerEndpoint) string {
+	template := fmt.Sprintf("I need comprehensive %s information", string(category))
+	if len(endpoints) > 1 {
+		template += fmt.Sprintf(" from %d available data sources", len(endpoints))
+	}
+
+	template += "\n\nPlease use:"
+	for _, endpoint := range endpoints {
+		operation := endpoint.Summary
+		if operation == "" {
+			operation = fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
+		}
+		template += fmt.Sprintf("\n- %s", operation)
+	}
+
+	return template
+}
+
 // createCategoryArguments creates arguments for a category prompt
 func (g *PromptGenerator) createCategoryArguments(category types.WeatherPromptCategory, endpoints []*types.SwaggerEndpoint) []types.MCPPromptArgument {
 	var arguments []types.MCPPromptArgument
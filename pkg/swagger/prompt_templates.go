@@ -0,0 +1,226 @@
+package swagger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// promptTemplateFile is a prompt template as written in prompts.templateDir: a YAML
+// file, or a markdown file whose YAML front matter holds every field but the template
+type promptTemplateFile struct {
+	Name        string                        `yaml:"name"`
+	Description string                        `yaml:"description"`
+	Category    string                        `yaml:"category"`
+	Arguments   []types.MCPPromptArgument     `yaml:"arguments"`
+	Examples    []types.PromptExample         `yaml:"examples"`
+	Tags        []string                      `yaml:"tags"`
+	Template    string                        `yaml:"template"`
+	Messages    []types.PromptMessageTemplate `yaml:"messages"`
+}
+
+// promptPlaceholderPattern matches the {{argument}} placeholders of prompt templates
+var promptPlaceholderPattern = regexp.MustCompile(`\{\{([A-Za-z0-9_\-]+)\}\}`)
+
+// LoadTemplates loads the prompt templates of prompts.templateDir, replacing the ones
+// loaded before. Invalid files are logged and skipped rather than failing the load.
+func (g *PromptGenerator) LoadTemplates() error {
+	dir := g.config.TemplateDir
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read prompt template directory %s: %w", dir, err)
+	}
+
+	templates := make(map[string]*types.GeneratedPrompt)
+	for _, entry := range entries {
+		if entry.IsDir() || !isPromptTemplateFile(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		prompt, err := loadPromptTemplate(path)
+		if err != nil {
+			g.logger.Warn("Skipping invalid prompt template", zap.Error(err), zap.String("path", path))
+			continue
+		}
+		if _, exists := templates[prompt.Name]; exists {
+			g.logger.Warn("Skipping prompt template with a duplicate name",
+				zap.String("path", path),
+				zap.String("promptName", prompt.Name))
+			continue
+		}
+		templates[prompt.Name] = prompt
+	}
+
+	g.templatesMutex.Lock()
+	g.templates = templates
+	g.templatesMutex.Unlock()
+
+	g.logger.Info("Loaded prompt templates",
+		zap.String("directory", dir),
+		zap.Int("templateCount", len(templates)))
+	return nil
+}
+
+// TemplatePrompts returns the prompts loaded from prompts.templateDir, ordered by name
+func (g *PromptGenerator) TemplatePrompts() []*types.GeneratedPrompt {
+	g.templatesMutex.RLock()
+	defer g.templatesMutex.RUnlock()
+
+	prompts := make([]*types.GeneratedPrompt, 0, len(g.templates))
+	for _, prompt := range g.templates {
+		prompts = append(prompts, prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Name < prompts[j].Name
+	})
+	return prompts
+}
+
+// withoutOverridden drops the generated prompts a prompt template overrides by name
+func (g *PromptGenerator) withoutOverridden(prompts []*types.GeneratedPrompt) []*types.GeneratedPrompt {
+	g.templatesMutex.RLock()
+	defer g.templatesMutex.RUnlock()
+
+	if len(g.templates) == 0 {
+		return prompts
+	}
+	kept := prompts[:0]
+	for _, prompt := range prompts {
+		if _, overridden := g.templates[prompt.Name]; !overridden {
+			kept = append(kept, prompt)
+		}
+	}
+	return kept
+}
+
+// isPromptTemplateFile reports whether a file name has the extension of a prompt template
+func isPromptTemplateFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// loadPromptTemplate reads a prompt template file. Its name defaults to the file name
+// without the extension, and its arguments to the template's placeholders.
+func loadPromptTemplate(path string) (*types.GeneratedPrompt, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file promptTemplateFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+	default:
+		frontMatter, body := splitFrontMatter(content)
+		if frontMatter != nil {
+			if err := yaml.Unmarshal(frontMatter, &file); err != nil {
+				return nil, fmt.Errorf("invalid front matter: %w", err)
+			}
+		}
+		file.Template = string(body)
+	}
+
+	file.Template = strings.TrimSpace(file.Template)
+	if file.Template == "" && len(file.Messages) == 0 {
+		return nil, fmt.Errorf("template is empty")
+	}
+	if err := validatePromptMessages(file.Messages); err != nil {
+		return nil, err
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if file.Arguments == nil {
+		file.Arguments = placeholderArguments(file.Template, file.Messages)
+	}
+
+	return &types.GeneratedPrompt{
+		Name:        file.Name,
+		Description: file.Description,
+		Arguments:   file.Arguments,
+		Category:    types.WeatherPromptCategory(file.Category),
+		Template:    file.Template,
+		Examples:    file.Examples,
+		Tags:        file.Tags,
+		Messages:    file.Messages,
+	}, nil
+}
+
+// splitFrontMatter splits markdown into its YAML front matter, delimited by "---"
+// lines at the start of the file, and its body. Without front matter, the whole
+// content is the body.
+func splitFrontMatter(content []byte) ([]byte, []byte) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return nil, content
+	}
+
+	rest := normalized[len("---\n"):]
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return []byte{}, rest[len("---\n"):]
+	}
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end < 0 {
+		if bytes.HasSuffix(rest, []byte("\n---")) {
+			return rest[:len(rest)-len("\n---")], nil
+		}
+		return nil, content
+	}
+	return rest[:end], rest[end+len("\n---\n"):]
+}
+
+// placeholderArguments lists the {{argument}} placeholders of a template and the text
+// and resources of its messages as optional arguments, in the order they first appear
+func placeholderArguments(template string, messages []types.PromptMessageTemplate) []types.MCPPromptArgument {
+	texts := []string{template}
+	for _, message := range messages {
+		texts = append(texts, message.Text, message.Resource)
+	}
+
+	var arguments []types.MCPPromptArgument
+	seen := make(map[string]bool)
+	for _, match := range promptPlaceholderPattern.FindAllStringSubmatch(strings.Join(texts, "\n"), -1) {
+		if seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		arguments = append(arguments, types.MCPPromptArgument{Name: match[1]})
+	}
+	return arguments
+}
+
+// validatePromptMessages checks that every message of a multi-message prompt has a
+// known role and either text or a resource
+func validatePromptMessages(messages []types.PromptMessageTemplate) error {
+	for i, message := range messages {
+		switch message.Role {
+		case "", "user", "assistant", "system":
+		default:
+			return fmt.Errorf("message %d has an unknown role %q; use user, assistant, or system", i, message.Role)
+		}
+		if (message.Text == "") == (message.Resource == "") {
+			return fmt.Errorf("message %d must have either text or a resource", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	httpclient "swagger-docs-mcp/pkg/http"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+const (
+	// refFetchTimeout bounds the fetch of a remote reference document
+	refFetchTimeout = 30 * time.Second
+	// maxRefDocumentSize bounds the size of a remote reference document
+	maxRefDocumentSize = 32 << 20
+)
+
+// RefResolver inlines external $ref references (relative files and URLs) into a
+// decoded swagger document
+type RefResolver struct {
+	logger       *utils.Logger
+	client       *http.Client
+	hosts        *httpclient.HostPolicy
+	allowedHosts []string
+}
+
+// refResolution holds the state of a single ResolveExternal call
+type refResolution struct {
+	rootHost string
+	cache    map[string]interface{}
+}
+
+// NewRefResolver creates a new external reference resolver. Remote references are only
+// fetched from hosts in allowedHosts ("*" allows any host) or from the host serving the
+// document being resolved.
+func NewRefResolver(logger *utils.Logger, allowedHosts []string) *RefResolver {
+	return &RefResolver{
+		logger:       logger.Child("ref-resolver"),
+		client:       &http.Client{Timeout: refFetchTimeout},
+		allowedHosts: allowedHosts,
+	}
+}
+
+// SetHostPolicy restricts the hosts remote references are fetched from, and redirected
+// to, to those the outbound host policy allows as well as the allowed reference hosts
+func (r *RefResolver) SetHostPolicy(hosts *httpclient.HostPolicy) {
+	r.hosts = hosts
+	r.client = hosts.Client(refFetchTimeout)
+}
+
+// ResolveExternal returns a copy of document with every external $ref replaced by the
+// referenced content. Relative references are resolved against location, which may be
+// a file path or a URL. Local references (#/...) in the root document are left intact.
+// References that cannot be resolved are logged and left unchanged.
+func (r *RefResolver) ResolveExternal(document interface{}, location string) interface{} {
+	state := &refResolution{
+		cache: make(map[string]interface{}),
+	}
+	if isURL(location) {
+		if parsed, err := url.Parse(location); err == nil {
+			state.rootHost = parsed.Host
+		}
+	}
+
+	return r.resolveNode(state, document, location, true, nil)
+}
+
+// resolveNode walks a decoded document, resolving references relative to base
+func (r *RefResolver) resolveNode(state *refResolution, node interface{}, base string, isRoot bool, stack []string) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			return r.resolveRef(state, value, ref, base, isRoot, stack)
+		}
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = r.resolveNode(state, item, base, isRoot, stack)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = r.resolveNode(state, item, base, isRoot, stack)
+		}
+		return result
+	default:
+		return node
+	}
+}
+
+// resolveRef replaces a single reference object with its target
+func (r *RefResolver) resolveRef(state *refResolution, node map[string]interface{}, ref, base string, isRoot bool, stack []string) interface{} {
+	refLocation, fragment := splitRef(ref)
+
+	// Local references in the root document keep working once external content is inlined
+	if refLocation == "" && isRoot {
+		return node
+	}
+
+	target := base
+	if refLocation != "" {
+		target = resolveRefLocation(base, refLocation)
+	}
+
+	key := target + "#" + fragment
+	for _, visited := range stack {
+		if visited == key {
+			r.logger.Warn("Circular external reference detected", zap.String("ref", key))
+			return map[string]interface{}{
+				"description": fmt.Sprintf("Circular reference to %s", key),
+			}
+		}
+	}
+
+	document, err := r.load(state, target)
+	if err != nil {
+		r.logger.Warn("Failed to load external reference", zap.Error(err), zap.String("ref", ref), zap.String("base", base))
+		return node
+	}
+
+	resolved, err := resolvePointer(document, fragment)
+	if err != nil {
+		r.logger.Warn("Failed to resolve reference fragment", zap.Error(err), zap.String("ref", ref), zap.String("location", target))
+		return node
+	}
+
+	resolved = r.resolveNode(state, resolved, target, false, append(stack, key))
+
+	// Preserve sibling keys (e.g. description) alongside the resolved schema
+	if resolvedMap, ok := resolved.(map[string]interface{}); ok && len(node) > 1 {
+		merged := make(map[string]interface{}, len(resolvedMap)+len(node))
+		for k, v := range resolvedMap {
+			merged[k] = v
+		}
+		for k, v := range node {
+			if k != "$ref" {
+				merged[k] = r.resolveNode(state, v, base, isRoot, stack)
+			}
+		}
+		return merged
+	}
+
+	return resolved
+}
+
+// load reads and decodes the document at location, using the per-resolution cache
+func (r *RefResolver) load(state *refResolution, location string) (interface{}, error) {
+	if document, ok := state.cache[location]; ok {
+		return document, nil
+	}
+
+	var content []byte
+	var err error
+
+	if isURL(location) {
+		content, err = r.fetch(state, location)
+	} else {
+		content, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	trimmed := strings.TrimSpace(string(content))
+	if strings.HasSuffix(strings.ToLower(location), ".json") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON reference %s: %w", location, err)
+		}
+	} else {
+		document, err = decodeYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML reference %s: %w", location, err)
+		}
+	}
+
+	state.cache[location] = document
+	r.logger.Debug("Loaded external reference document", zap.String("location", location))
+
+	return document, nil
+}
+
+// fetch retrieves a remote reference document if its host is allowed
+func (r *RefResolver) fetch(state *refResolution, location string) ([]byte, error) {
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference URL %s: %w", location, err)
+	}
+
+	if !r.isHostAllowed(parsed.Host, state.rootHost) {
+		return nil, fmt.Errorf("remote reference host %q is not in the allowed reference hosts", parsed.Host)
+	}
+	if err := r.hosts.Check(parsed); err != nil {
+		return nil, fmt.Errorf("refused to fetch reference %s: %w", location, err)
+	}
+
+	// Redirects must stay within the allowed reference hosts and the host policy
+	client := *r.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !r.isHostAllowed(req.URL.Host, state.rootHost) {
+			return fmt.Errorf("redirect refused: remote reference host %q is not in the allowed reference hosts", req.URL.Host)
+		}
+		if r.client.CheckRedirect != nil {
+			return r.client.CheckRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after %d redirects", len(via))
+		}
+		return nil
+	}
+
+	req, err := http.NewRequest("GET", location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", location, err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml, text/yaml, */*")
+	req.Header.Set("User-Agent", "swagger-docs-mcp/1.0.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d fetching %s", resp.StatusCode, location)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRefDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", location, err)
+	}
+	if len(content) > maxRefDocumentSize {
+		return nil, fmt.Errorf("reference document %s exceeds %d bytes", location, maxRefDocumentSize)
+	}
+	return content, nil
+}
+
+// isHostAllowed checks a remote reference host against the allowlist
+func (r *RefResolver) isHostAllowed(host, rootHost string) bool {
+	if rootHost != "" && strings.EqualFold(host, rootHost) {
+		return true
+	}
+	for _, allowed := range r.allowedHosts {
+		if allowed == "*" || strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRef splits a reference into its document location and JSON pointer fragment
+func splitRef(ref string) (string, string) {
+	if index := strings.Index(ref, "#"); index >= 0 {
+		return ref[:index], ref[index+1:]
+	}
+	return ref, ""
+}
+
+// resolveRefLocation resolves a reference location relative to the referencing document
+func resolveRefLocation(base, location string) string {
+	if isURL(location) {
+		return location
+	}
+
+	if isURL(base) {
+		baseURL, err := url.Parse(base)
+		if err == nil {
+			if refURL, err := url.Parse(location); err == nil {
+				return baseURL.ResolveReference(refURL).String()
+			}
+		}
+		return location
+	}
+
+	if filepath.IsAbs(location) || base == "" {
+		return filepath.Clean(location)
+	}
+
+	return filepath.Join(filepath.Dir(base), location)
+}
+
+// resolvePointer evaluates a JSON pointer fragment against a decoded document
+func resolvePointer(document interface{}, fragment string) (interface{}, error) {
+	if fragment == "" || fragment == "/" {
+		return document, nil
+	}
+
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", fragment)
+	}
+
+	current := document
+	for _, token := range strings.Split(fragment[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[token]
+			if !ok {
+				return nil, fmt.Errorf("JSON pointer %q: key %q not found", fragment, token)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("JSON pointer %q: invalid array index %q", fragment, token)
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("JSON pointer %q: cannot descend into %T", fragment, current)
+		}
+	}
+
+	return current, nil
+}
@@ -0,0 +1,290 @@
+package swagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// writeRefFiles writes the files of a test, by path relative to a temporary directory
+// that it returns
+func writeRefFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func newTestRefResolver(allowedHosts []string) *RefResolver {
+	return NewRefResolver(utils.NewLogger(types.LoggingConfig{Level: "error"}), allowedHosts)
+}
+
+func TestResolveExternal(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		document interface{}
+		want     interface{}
+	}{
+		{
+			name:     "local references in the root document are kept",
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/definitions/Pet"}},
+			want:     map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/definitions/Pet"}},
+		},
+		{
+			name:     "whole file reference",
+			files:    map[string]string{"pet.yaml": "type: object\n"},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "pet.yaml"}},
+			want:     map[string]interface{}{"schema": map[string]interface{}{"type": "object"}},
+		},
+		{
+			name:     "JSON pointer into a JSON file",
+			files:    map[string]string{"defs.json": `{"definitions": {"Pet": {"type": "object"}}}`},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "defs.json#/definitions/Pet"}},
+			want:     map[string]interface{}{"schema": map[string]interface{}{"type": "object"}},
+		},
+		{
+			name:  "sibling keys are preserved",
+			files: map[string]string{"pet.yaml": "type: object\n"},
+			document: map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "pet.yaml", "description": "A pet"},
+			},
+			want: map[string]interface{}{
+				"schema": map[string]interface{}{"type": "object", "description": "A pet"},
+			},
+		},
+		{
+			name: "references resolve relative to the referencing file",
+			files: map[string]string{
+				"schemas/pet.yaml":   "owner:\n  $ref: owner.yaml\n",
+				"schemas/owner.yaml": "type: string\n",
+			},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "schemas/pet.yaml"}},
+			want: map[string]interface{}{
+				"schema": map[string]interface{}{"owner": map[string]interface{}{"type": "string"}},
+			},
+		},
+		{
+			name: "local references in a referenced file resolve against that file",
+			files: map[string]string{
+				"defs.yaml": "Pet:\n  properties:\n    id:\n      $ref: '#/Id'\nId:\n  type: integer\n",
+			},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "defs.yaml#/Pet"}},
+			want: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}},
+				},
+			},
+		},
+		{
+			name: "circular references between files are cut",
+			files: map[string]string{
+				"a.yaml": "A:\n  next:\n    $ref: b.yaml#/B\n",
+				"b.yaml": "B:\n  next:\n    $ref: a.yaml#/A\n",
+			},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "a.yaml#/A"}},
+			want: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"next": map[string]interface{}{
+						"next": map[string]interface{}{"description": "Circular reference to {dir}/a.yaml#/A"},
+					},
+				},
+			},
+		},
+		{
+			name:     "a self-referencing schema is cut",
+			files:    map[string]string{"node.yaml": "Node:\n  next:\n    $ref: '#/Node'\n"},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "node.yaml#/Node"}},
+			want: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"next": map[string]interface{}{"description": "Circular reference to {dir}/node.yaml#/Node"},
+				},
+			},
+		},
+		{
+			name:     "the same reference twice is not a cycle",
+			files:    map[string]string{"id.yaml": "type: integer\n"},
+			document: map[string]interface{}{"a": map[string]interface{}{"$ref": "id.yaml"}, "b": map[string]interface{}{"$ref": "id.yaml"}},
+			want: map[string]interface{}{
+				"a": map[string]interface{}{"type": "integer"},
+				"b": map[string]interface{}{"type": "integer"},
+			},
+		},
+		{
+			name:     "a missing file is left unchanged",
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "missing.yaml"}},
+			want:     map[string]interface{}{"schema": map[string]interface{}{"$ref": "missing.yaml"}},
+		},
+		{
+			name:     "a missing fragment is left unchanged",
+			files:    map[string]string{"defs.yaml": "Pet:\n  type: object\n"},
+			document: map[string]interface{}{"schema": map[string]interface{}{"$ref": "defs.yaml#/Owner"}},
+			want:     map[string]interface{}{"schema": map[string]interface{}{"$ref": "defs.yaml#/Owner"}},
+		},
+		{
+			name:     "references inside arrays",
+			files:    map[string]string{"id.yaml": "type: integer\n"},
+			document: map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": "id.yaml"}, "x"}},
+			want:     map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"type": "integer"}, "x"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := writeRefFiles(t, test.files)
+			got := newTestRefResolver(nil).ResolveExternal(test.document, filepath.Join(dir, "root.yaml"))
+			want := replaceDirPlaceholder(test.want, dir)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ResolveExternal() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+// replaceDirPlaceholder replaces {dir} in the strings of a value with a directory
+func replaceDirPlaceholder(value interface{}, dir string) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return strings.ReplaceAll(typed, "{dir}", dir)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[key] = replaceDirPlaceholder(item, dir)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = replaceDirPlaceholder(item, dir)
+		}
+		return result
+	}
+	return value
+}
+
+func TestResolveExternalRemote(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pet.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type": "object"}`))
+	})
+	api := httptest.NewServer(mux)
+	defer api.Close()
+	other := httptest.NewServer(mux)
+	defer other.Close()
+
+	tests := []struct {
+		name         string
+		location     string
+		ref          string
+		allowedHosts []string
+		resolved     bool
+	}{
+		{name: "relative to a remote document", location: api.URL + "/openapi.json", ref: "pet.json", resolved: true},
+		{name: "the host of the document", location: api.URL + "/openapi.json", ref: api.URL + "/pet.json", resolved: true},
+		{name: "a host that is not allowed", location: api.URL + "/openapi.json", ref: other.URL + "/pet.json"},
+		{name: "a remote host from a local document", location: "/specs/openapi.json", ref: api.URL + "/pet.json"},
+		{name: "an allowed host", location: "/specs/openapi.json", ref: other.URL + "/pet.json", allowedHosts: []string{other.Listener.Addr().String()}, resolved: true},
+		{name: "any host", location: "/specs/openapi.json", ref: other.URL + "/pet.json", allowedHosts: []string{"*"}, resolved: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := map[string]interface{}{"schema": map[string]interface{}{"$ref": test.ref}}
+			got := newTestRefResolver(test.allowedHosts).ResolveExternal(document, test.location)
+
+			want := interface{}(document)
+			if test.resolved {
+				want = map[string]interface{}{"schema": map[string]interface{}{"type": "object"}}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ResolveExternal() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestResolvePointer(t *testing.T) {
+	document := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/pets/{id}": map[string]interface{}{"get": "operation"},
+		},
+		"a~b":  "tilde",
+		"tags": []interface{}{"first", "second"},
+	}
+
+	tests := []struct {
+		name     string
+		fragment string
+		want     interface{}
+		wantErr  bool
+	}{
+		{name: "empty pointer", fragment: "", want: document},
+		{name: "root pointer", fragment: "/", want: document},
+		{name: "escaped slash", fragment: "/paths/~1pets~1{id}/get", want: "operation"},
+		{name: "percent-encoded", fragment: "/paths/~1pets~1%7Bid%7D/get", want: "operation"},
+		{name: "escaped tilde", fragment: "/a~0b", want: "tilde"},
+		{name: "array index", fragment: "/tags/1", want: "second"},
+		{name: "array index out of range", fragment: "/tags/2", wantErr: true},
+		{name: "array index not a number", fragment: "/tags/first", wantErr: true},
+		{name: "missing key", fragment: "/components", wantErr: true},
+		{name: "descending into a scalar", fragment: "/a~0b/c", wantErr: true},
+		{name: "relative pointer", fragment: "paths", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolvePointer(document, test.fragment)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("resolvePointer(%q) = %v, want an error", test.fragment, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePointer(%q) error = %v", test.fragment, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resolvePointer(%q) = %#v, want %#v", test.fragment, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveRefLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		location string
+		want     string
+	}{
+		{name: "sibling file", base: "/specs/api.yaml", location: "pet.yaml", want: "/specs/pet.yaml"},
+		{name: "parent directory", base: "/specs/v1/api.yaml", location: "../common.yaml", want: "/specs/common.yaml"},
+		{name: "absolute path", base: "/specs/api.yaml", location: "/shared/pet.yaml", want: "/shared/pet.yaml"},
+		{name: "no base", base: "", location: "./pet.yaml", want: "pet.yaml"},
+		{name: "relative to a URL", base: "https://example.com/specs/api.yaml", location: "pet.yaml", want: "https://example.com/specs/pet.yaml"},
+		{name: "absolute URL", base: "/specs/api.yaml", location: "https://example.com/pet.yaml", want: "https://example.com/pet.yaml"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resolveRefLocation(test.base, test.location); got != filepath.FromSlash(test.want) && got != test.want {
+				t.Errorf("resolveRefLocation(%q, %q) = %q, want %q", test.base, test.location, got, test.want)
+			}
+		})
+	}
+}
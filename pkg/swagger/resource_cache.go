@@ -0,0 +1,93 @@
+package swagger
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// defaultResourceCacheSize is how many rendered resource contents are kept when the
+// configuration sets no size
+const defaultResourceCacheSize = 256
+
+// resourceCache keeps the most recently read resource contents, keyed by URI. Each
+// content is only served for the parsed document it was rendered from, so a reloaded
+// document is rendered again even before its contents are invalidated.
+type resourceCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// resourceCacheEntry is a rendered resource content of the cache
+type resourceCacheEntry struct {
+	uri      string
+	document *types.SwaggerDocument
+	content  string
+}
+
+// newResourceCache creates a cache keeping up to capacity contents
+func newResourceCache(capacity int) *resourceCache {
+	if capacity <= 0 {
+		capacity = defaultResourceCacheSize
+	}
+	return &resourceCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the content of a resource of a document, rendering and caching it when
+// it is not cached, and evicting the least recently read content when the cache is
+// full. Rendering errors are not cached.
+func (c *resourceCache) get(uri string, document *types.SwaggerDocument, render func() (string, error)) (string, error) {
+	c.mutex.Lock()
+	if element, exists := c.entries[uri]; exists {
+		if entry := element.Value.(*resourceCacheEntry); entry.document == document {
+			c.order.MoveToFront(element)
+			c.mutex.Unlock()
+			return entry.content, nil
+		}
+	}
+	c.mutex.Unlock()
+
+	// Contents are rendered outside the lock; concurrent misses of one resource at
+	// worst render it twice
+	content, err := render()
+	if err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry := &resourceCacheEntry{uri: uri, document: document, content: content}
+	if element, exists := c.entries[uri]; exists {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return content, nil
+	}
+	c.entries[uri] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resourceCacheEntry).uri)
+	}
+	return content, nil
+}
+
+// invalidatePrefix drops the contents of the resources whose URI has the prefix
+func (c *resourceCache) invalidatePrefix(prefix string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for uri, element := range c.entries {
+		if strings.HasPrefix(uri, prefix) {
+			c.order.Remove(element)
+			delete(c.entries, uri)
+		}
+	}
+}
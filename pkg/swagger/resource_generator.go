@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"go.uber.org/zap"
@@ -16,16 +17,27 @@ import (
 type ResourceGenerator struct {
 	logger *utils.Logger
 	config *types.ResourcesConfig
+	// endpointFilter leaves out endpoints no resources are generated for
+	endpointFilter func(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []types.SwaggerEndpoint
+	// contents keeps rendered resource contents so that reads need not render them again
+	contents *resourceCache
 }
 
 // NewResourceGenerator creates a new resource generator
 func NewResourceGenerator(logger *utils.Logger, config *types.ResourcesConfig) *ResourceGenerator {
 	return &ResourceGenerator{
-		logger: logger.Child("resource-generator"),
-		config: config,
+		logger:   logger.Child("resource-generator"),
+		config:   config,
+		contents: newResourceCache(config.ContentCacheSize),
 	}
 }
 
+// SetEndpointFilter sets a function selecting the endpoints of a document resources are
+// generated from, such as ToolGenerator.EnabledEndpoints
+func (g *ResourceGenerator) SetEndpointFilter(filter func(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []types.SwaggerEndpoint) {
+	g.endpointFilter = filter
+}
+
 // GenerateResourcesFromDocument generates resources from a parsed Swagger document
 func (g *ResourceGenerator) GenerateResourcesFromDocument(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) ([]*types.GeneratedResource, error) {
 	if !g.config.Enabled {
@@ -38,6 +50,9 @@ func (g *ResourceGenerator) GenerateResourcesFromDocument(doc *types.SwaggerDocu
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract endpoints: %w", err)
 	}
+	if g.endpointFilter != nil {
+		endpoints = g.endpointFilter(endpoints, docInfo)
+	}
 
 	var resources []*types.GeneratedResource
 	
@@ -51,9 +66,18 @@ func (g *ResourceGenerator) GenerateResourcesFromDocument(doc *types.SwaggerDocu
 	schemaResources := g.generateSchemaResources(doc, docInfo)
 	resources = append(resources, schemaResources...)
 
-	// Generate example resources
-	exampleResources := g.generateExampleResources(endpoints, docInfo)
-	resources = append(resources, exampleResources...)
+	// Per-endpoint examples and schemas are addressed through resource templates
+	// rather than enumerated, since large documents have thousands of endpoints
+
+	// Generate callback and webhook discovery resources
+	callbackResources, err := g.generateCallbackResources(doc, docInfo)
+	if err != nil {
+		g.logger.Warn("Failed to generate callback resources", zap.Error(err), zap.String("document", docInfo.FilePath))
+	}
+	resources = append(resources, callbackResources...)
+
+	// Generate channel discovery resources for AsyncAPI documents
+	resources = append(resources, g.generateChannelResources(doc, docInfo)...)
 
 	// Generate endpoint discovery resources
 	if g.config.AllowEndpointDiscovery {
@@ -156,37 +180,6 @@ func (g *ResourceGenerator) generateSchemaResources(doc *types.SwaggerDocument,
 	return resources
 }
 
-// generateExampleResources generates example resources
-func (g *ResourceGenerator) generateExampleResources(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []*types.GeneratedResource {
-	var resources []*types.GeneratedResource
-
-	// Generate examples for each endpoint
-	for _, endpoint := range endpoints {
-		if !g.hasExamples(&endpoint) {
-			continue
-		}
-
-		exampleResource := &types.GeneratedResource{
-			URI:         g.createEndpointResourceURI(docInfo, &endpoint, "example", "json"),
-			Name:        fmt.Sprintf("%s %s Example", strings.ToUpper(endpoint.Method), endpoint.Path),
-			Description: fmt.Sprintf("Example request and response for %s %s", endpoint.Method, endpoint.Path),
-			MimeType:    "application/json",
-			Category:    types.ResourceCategoryExample,
-			Tags:        []string{"example", "request", "response", endpoint.Method},
-			Source:      docInfo,
-			Metadata: map[string]interface{}{
-				"method":   endpoint.Method,
-				"path":     endpoint.Path,
-				"summary":  endpoint.Summary,
-				"hasAuth":  len(endpoint.Security) > 0,
-			},
-		}
-		resources = append(resources, exampleResource)
-	}
-
-	return resources
-}
-
 // generateEndpointResources generates endpoint discovery resources
 func (g *ResourceGenerator) generateEndpointResources(endpoints []types.SwaggerEndpoint, docInfo *types.SwaggerDocumentInfo) []*types.GeneratedResource {
 	var resources []*types.GeneratedResource
@@ -234,25 +227,177 @@ func (g *ResourceGenerator) generateEndpointResources(endpoints []types.SwaggerE
 	return resources
 }
 
+// generateCallbackResources generates documentation resources for callbacks and webhooks.
+// These describe requests the API pushes to the client, so they cannot be exposed as tools.
+func (g *ResourceGenerator) generateCallbackResources(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) ([]*types.GeneratedResource, error) {
+	parser := NewParser(g.logger)
+	callbacks, err := parser.ExtractCallbacks(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract callbacks: %w", err)
+	}
+
+	if len(callbacks) == 0 {
+		return nil, nil
+	}
+
+	var resources []*types.GeneratedResource
+
+	catalogResource := &types.GeneratedResource{
+		URI:         g.createResourceURI(docInfo, "callbacks", "json"),
+		Name:        g.createResourceName(docInfo, "Callbacks and Webhooks"),
+		Description: fmt.Sprintf("Requests pushed by %s to subscribers (callbacks and webhooks)", docInfo.Title),
+		MimeType:    "application/json",
+		Category:    types.ResourceCategoryCallback,
+		Tags:        []string{"callbacks", "webhooks", "discovery"},
+		Source:      docInfo,
+		Metadata: map[string]interface{}{
+			"callbackCount": len(callbacks),
+		},
+	}
+	resources = append(resources, catalogResource)
+
+	for _, callback := range callbacks {
+		description := callback.Summary
+		if description == "" {
+			description = callback.Description
+		}
+		if description == "" {
+			description = fmt.Sprintf("%s %s delivered as %s", strings.Title(callback.Kind), callback.Name, callback.Method)
+		}
+		if callback.Parent != "" {
+			description = fmt.Sprintf("%s (registered by %s)", description, callback.Parent)
+		}
+
+		callbackResource := &types.GeneratedResource{
+			URI:         g.createResourceURI(docInfo, g.createCallbackIdentifier(&callback), "json"),
+			Name:        fmt.Sprintf("%s %s", strings.Title(callback.Kind), callback.Name),
+			Description: description,
+			MimeType:    "application/json",
+			Category:    types.ResourceCategoryCallback,
+			Tags:        []string{callback.Kind, callback.Name, callback.Method},
+			Source:      docInfo,
+			Metadata: map[string]interface{}{
+				"kind":       callback.Kind,
+				"method":     callback.Method,
+				"expression": callback.Expression,
+				"parent":     callback.Parent,
+			},
+		}
+		resources = append(resources, callbackResource)
+	}
+
+	return resources, nil
+}
+
+// generateChannelResources generates a discovery resource listing the channels, servers,
+// and message schemas of an AsyncAPI document
+func (g *ResourceGenerator) generateChannelResources(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) []*types.GeneratedResource {
+	if doc.AsyncAPI == "" || len(doc.Channels) == 0 {
+		return nil
+	}
+
+	channelResource := &types.GeneratedResource{
+		URI:         g.createResourceURI(docInfo, "channels", "json"),
+		Name:        g.createResourceName(docInfo, "Channels"),
+		Description: fmt.Sprintf("Streaming channels and message schemas published by %s", docInfo.Title),
+		MimeType:    "application/json",
+		Category:    types.ResourceCategoryChannel,
+		Tags:        []string{"asyncapi", "channels", "discovery"},
+		Source:      docInfo,
+		Metadata: map[string]interface{}{
+			"asyncapi":     doc.AsyncAPI,
+			"channelCount": len(doc.Channels),
+		},
+	}
+
+	return []*types.GeneratedResource{channelResource}
+}
+
 // Helper methods
 
+// createCallbackIdentifier creates a safe identifier for a callback or webhook, prefixed by its kind.
+// The expression of a callback is part of it, as one callback name may hold several expressions.
+func (g *ResourceGenerator) createCallbackIdentifier(callback *types.SwaggerCallback) string {
+	parts := []string{callback.Kind}
+	if callback.Parent != "" {
+		parts = append(parts, callback.Parent)
+	}
+	parts = append(parts, callback.Name)
+	if callback.Expression != "" {
+		parts = append(parts, callback.Expression)
+	}
+	parts = append(parts, callback.Method)
+
+	identifier := strings.ToLower(strings.Join(parts, "-"))
+	var builder strings.Builder
+	lastDash := false
+	for _, r := range identifier {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			builder.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	return strings.Trim(builder.String(), "-")
+}
+
 // createResourceURI creates a URI for a resource
 func (g *ResourceGenerator) createResourceURI(docInfo *types.SwaggerDocumentInfo, resourceType, format string) string {
-	base := filepath.Base(docInfo.FilePath)
-	name := strings.TrimSuffix(base, filepath.Ext(base))
-	
-	return fmt.Sprintf("swagger://%s/%s.%s", name, resourceType, format)
+	return fmt.Sprintf("%s%s.%s", g.ResourceURIPrefix(docInfo), resourceType, format)
 }
 
 // createEndpointResourceURI creates a URI for an endpoint-specific resource
 func (g *ResourceGenerator) createEndpointResourceURI(docInfo *types.SwaggerDocumentInfo, endpoint *types.SwaggerEndpoint, resourceType, format string) string {
-	base := filepath.Base(docInfo.FilePath)
-	name := strings.TrimSuffix(base, filepath.Ext(base))
-	
 	// Create safe endpoint identifier
 	endpointID := g.createEndpointIdentifier(endpoint)
-	
-	return fmt.Sprintf("swagger://%s/endpoints/%s/%s.%s", name, endpointID, resourceType, format)
+
+	return fmt.Sprintf("%sendpoints/%s/%s.%s", g.ResourceURIPrefix(docInfo), endpointID, resourceType, format)
+}
+
+// ResourceURIPrefix returns the prefix shared by all resource URIs of a document
+func (g *ResourceGenerator) ResourceURIPrefix(docInfo *types.SwaggerDocumentInfo) string {
+	return fmt.Sprintf("%s://%s/", g.uriScheme(), g.DocumentID(docInfo))
+}
+
+// DocumentID returns the {doc} segment of a document's resource URIs: the ID of the
+// first resources.documentIds entry in key order matching the document, or else its
+// file name without the extension, followed by a hash of its path or URL under the
+// file-name-hash naming
+func (g *ResourceGenerator) DocumentID(docInfo *types.SwaggerDocumentInfo) string {
+	keys := make([]string, 0, len(g.config.DocumentIDs))
+	for key := range g.config.DocumentIDs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if MatchesDocument(key, docInfo) {
+			return g.config.DocumentIDs[key]
+		}
+	}
+
+	name := ResourceDocumentName(docInfo)
+	if g.config.DocumentNaming == types.ResourceNamingFileNameHash {
+		name = fmt.Sprintf("%s-%s", name, nameHash(docInfo.FilePath))
+	}
+	return name
+}
+
+// uriScheme returns the scheme of resource URIs
+func (g *ResourceGenerator) uriScheme() string {
+	if g.config.URIScheme == "" {
+		return "swagger"
+	}
+	return g.config.URIScheme
+}
+
+// ResourceDocumentName returns a document's file name without the extension, which
+// names it in resource URIs by default
+func ResourceDocumentName(docInfo *types.SwaggerDocumentInfo) string {
+	base := filepath.Base(docInfo.FilePath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
 }
 
 // createResourceName creates a display name for a resource
@@ -298,21 +443,6 @@ func (g *ResourceGenerator) getSchemaNames(schemas map[string]interface{}) []str
 	return names
 }
 
-// hasExamples checks if an endpoint has examples
-func (g *ResourceGenerator) hasExamples(endpoint *types.SwaggerEndpoint) bool {
-	// Check if endpoint has parameter examples
-	for _, param := range endpoint.Parameters {
-		if param.Example != nil {
-			return true
-		}
-	}
-	
-	// Check responses for examples - responses are map[string]interface{}
-	// so we can't directly access Example field
-	// For now, assume some endpoints have examples if they have responses
-	return len(endpoint.Responses) > 0
-}
-
 // getUniqueMethods gets unique HTTP methods from endpoints
 func (g *ResourceGenerator) getUniqueMethods(endpoints []types.SwaggerEndpoint) []string {
 	methodSet := make(map[string]bool)
@@ -397,8 +527,22 @@ func (g *ResourceGenerator) containsAny(text string, keywords []string) bool {
 	return false
 }
 
-// GetResourceContent generates the actual content for a resource
+// GetResourceContent returns the content of a resource, rendering it unless it was
+// already rendered from the same parsed document
 func (g *ResourceGenerator) GetResourceContent(resource *types.GeneratedResource, doc *types.SwaggerDocument) (string, error) {
+	return g.contents.get(resource.URI, doc, func() (string, error) {
+		return g.renderResourceContent(resource, doc)
+	})
+}
+
+// InvalidateDocument drops the cached contents of a document's resources, for when
+// the document changed or was removed
+func (g *ResourceGenerator) InvalidateDocument(docInfo *types.SwaggerDocumentInfo) {
+	g.contents.invalidatePrefix(g.ResourceURIPrefix(docInfo))
+}
+
+// renderResourceContent generates the actual content for a resource
+func (g *ResourceGenerator) renderResourceContent(resource *types.GeneratedResource, doc *types.SwaggerDocument) (string, error) {
 	uri, err := url.Parse(resource.URI)
 	if err != nil {
 		return "", fmt.Errorf("invalid resource URI: %w", err)
@@ -428,8 +572,14 @@ func (g *ResourceGenerator) GetResourceContent(resource *types.GeneratedResource
 		category := strings.TrimPrefix(resourceType, "endpoints-")
 		category = strings.TrimSuffix(category, ".json")
 		return g.generateCategoryEndpointsContent(doc, category)
-	case strings.HasPrefix(resourceType, "endpoints/"):
-		// Handle endpoint-specific resources
+	case resourceType == "callbacks.json":
+		return g.generateCallbacksContent(doc, "")
+	case strings.HasPrefix(resourceType, types.CallbackKindCallback+"-"), strings.HasPrefix(resourceType, types.CallbackKindWebhook+"-"):
+		return g.generateCallbacksContent(doc, strings.TrimSuffix(resourceType, ".json"))
+	case resourceType == "channels.json":
+		return g.generateChannelsContent(doc)
+	case resourceType == "endpoints" && len(pathParts) == 3:
+		// Handle endpoint-specific resources addressed through resource templates
 		return g.generateEndpointSpecificContent(doc, pathParts)
 	default:
 		return "", fmt.Errorf("unknown resource type: %s", resourceType)
@@ -570,9 +720,90 @@ func (g *ResourceGenerator) generateCategoryEndpointsContent(doc *types.SwaggerD
 	return string(content), nil
 }
 
-// generateEndpointSpecificContent generates content for endpoint-specific resources
+// generateCallbacksContent generates content for all callbacks, or for the callback
+// matching identifier when one is given
+func (g *ResourceGenerator) generateCallbacksContent(doc *types.SwaggerDocument, identifier string) (string, error) {
+	parser := NewParser(g.logger)
+	callbacks, err := parser.ExtractCallbacks(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract callbacks: %w", err)
+	}
+
+	var result interface{} = callbacks
+	if identifier != "" {
+		var found *types.SwaggerCallback
+		for i := range callbacks {
+			if g.createCallbackIdentifier(&callbacks[i]) == identifier {
+				found = &callbacks[i]
+				break
+			}
+		}
+		if found == nil {
+			return "", fmt.Errorf("callback not found: %s", identifier)
+		}
+		result = found
+	}
+
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal callbacks: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// generateChannelsContent generates content describing an AsyncAPI document's channels
+func (g *ResourceGenerator) generateChannelsContent(doc *types.SwaggerDocument) (string, error) {
+	if doc.AsyncAPI == "" {
+		return "", fmt.Errorf("document is not an AsyncAPI document")
+	}
+
+	result := map[string]interface{}{
+		"asyncapi": doc.AsyncAPI,
+		"channels": doc.Channels,
+	}
+	if len(doc.AsyncAPIServers) > 0 {
+		result["servers"] = doc.AsyncAPIServers
+	}
+	if len(doc.Operations) > 0 {
+		result["operations"] = doc.Operations
+	}
+
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal channels: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// generateEndpointSpecificContent generates content for endpoint-specific resources,
+// addressed as endpoints/{endpointId}/{example,schema}.json or
+// endpoints/{endpointId}/samples.{curl,go,python}
 func (g *ResourceGenerator) generateEndpointSpecificContent(doc *types.SwaggerDocument, pathParts []string) (string, error) {
-	// This would handle endpoint-specific resources like examples
-	// Implementation depends on the specific structure needed
-	return "{}", nil
-}
\ No newline at end of file
+	endpoint, err := g.findEndpoint(doc, pathParts[1])
+	if err != nil {
+		return "", err
+	}
+
+	if language, isSample := strings.CutPrefix(pathParts[2], "samples."); isSample {
+		return g.endpointSample(endpoint, language)
+	}
+
+	var result map[string]interface{}
+	switch pathParts[2] {
+	case "example.json":
+		result = g.endpointExample(endpoint)
+	case "schema.json":
+		result = g.endpointSchema(endpoint)
+	default:
+		return "", fmt.Errorf("unknown endpoint resource: %s", pathParts[2])
+	}
+
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal endpoint resource: %w", err)
+	}
+
+	return string(content), nil
+}
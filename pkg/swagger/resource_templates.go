@@ -0,0 +1,300 @@
+package swagger
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// endpointResourceKinds describes the per-endpoint resources that are addressed
+// through resource templates instead of being listed up front
+var endpointResourceKinds = map[string]struct {
+	name        string
+	description string
+	category    types.ResourceCategory
+	mimeType    string
+}{
+	"example.json":   {"Example", "Example request and response for %s", types.ResourceCategoryExample, "application/json"},
+	"schema.json":    {"Schema", "Parameter, request body, and response schemas for %s", types.ResourceCategorySchema, "application/json"},
+	"samples.curl":   {"curl Sample", "curl command calling %s", types.ResourceCategoryExample, "text/x-shellscript"},
+	"samples.go":     {"Go Sample", "Go net/http program calling %s", types.ResourceCategoryExample, "text/x-go"},
+	"samples.python": {"Python Sample", "Python requests script calling %s", types.ResourceCategoryExample, "text/x-python"},
+}
+
+// ResourceTemplates returns the URI templates of resources that are resolved on demand.
+// {doc} names a document as in its other resource URIs, by default its file name
+// without the extension; {endpointId} is the lowercase HTTP method followed by the path
+// with slashes replaced by dashes and braces removed.
+func (g *ResourceGenerator) ResourceTemplates() []types.MCPResourceTemplate {
+	if !g.config.Enabled {
+		return nil
+	}
+
+	scheme := g.uriScheme()
+	return []types.MCPResourceTemplate{
+		{
+			URITemplate: scheme + "://{doc}/endpoints/{endpointId}/example.json",
+			Name:        "Endpoint Example",
+			Description: "Example request and response for one endpoint, e.g. " + scheme + "://petstore/endpoints/get-pets-petId/example.json",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: scheme + "://{doc}/endpoints/{endpointId}/schema.json",
+			Name:        "Endpoint Schema",
+			Description: "Parameter, request body, and response schemas for one endpoint, e.g. " + scheme + "://petstore/endpoints/get-pets-petId/schema.json",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: scheme + "://{doc}/endpoints/{endpointId}/samples.{lang}",
+			Name:        "Endpoint Code Sample",
+			Description: "Code calling one endpoint with its documented example values; {lang} is curl, go, or python, e.g. " + scheme + "://petstore/endpoints/get-pets-petId/samples.curl",
+			MimeType:    "text/plain",
+		},
+	}
+}
+
+// ResolveTemplateResource returns the resource a templated URI addresses in a document.
+// It fails if the URI names another document, does not match a template, or refers
+// to an endpoint the document does not define.
+func (g *ResourceGenerator) ResolveTemplateResource(uri string, doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) (*types.GeneratedResource, error) {
+	if !g.config.Enabled {
+		return nil, fmt.Errorf("resources are disabled")
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource URI: %w", err)
+	}
+	if !strings.EqualFold(parsed.Scheme, g.uriScheme()) || parsed.Host != g.DocumentID(docInfo) {
+		return nil, fmt.Errorf("resource URI does not belong to document %s", docInfo.FilePath)
+	}
+
+	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(pathParts) != 3 || pathParts[0] != "endpoints" {
+		return nil, fmt.Errorf("resource URI does not match a resource template: %s", uri)
+	}
+
+	kind, exists := endpointResourceKinds[pathParts[2]]
+	if !exists {
+		return nil, fmt.Errorf("unknown endpoint resource: %s", pathParts[2])
+	}
+
+	endpoint, err := g.findEndpoint(doc, pathParts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	dot := strings.LastIndex(pathParts[2], ".")
+	resourceType, format := pathParts[2][:dot], pathParts[2][dot+1:]
+
+	return &types.GeneratedResource{
+		URI:         g.createEndpointResourceURI(docInfo, endpoint, resourceType, format),
+		Name:        fmt.Sprintf("%s %s %s", strings.ToUpper(endpoint.Method), endpoint.Path, kind.name),
+		Description: fmt.Sprintf(kind.description, strings.ToUpper(endpoint.Method)+" "+endpoint.Path),
+		MimeType:    kind.mimeType,
+		Category:    kind.category,
+		Tags:        []string{resourceType, endpoint.Method},
+		Source:      docInfo,
+		Metadata: map[string]interface{}{
+			"method":  endpoint.Method,
+			"path":    endpoint.Path,
+			"summary": endpoint.Summary,
+		},
+	}, nil
+}
+
+// findEndpoint returns the endpoint of a document with the given identifier
+func (g *ResourceGenerator) findEndpoint(doc *types.SwaggerDocument, endpointID string) (*types.SwaggerEndpoint, error) {
+	parser := NewParser(g.logger)
+	endpoints, err := parser.ExtractEndpoints(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract endpoints: %w", err)
+	}
+
+	for i := range endpoints {
+		if g.createEndpointIdentifier(&endpoints[i]) == endpointID {
+			return &endpoints[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("endpoint not found: %s", endpointID)
+}
+
+// endpointExample collects the documented example values of an endpoint's parameters,
+// request body, and responses
+func (g *ResourceGenerator) endpointExample(endpoint *types.SwaggerEndpoint) map[string]interface{} {
+	parameters := make(map[string]interface{})
+	var body interface{}
+
+	for _, param := range endpoint.Parameters {
+		if param.In == "body" {
+			// Swagger 2.0 carries the request body as a parameter
+			body = schemaExample(param.Schema)
+			continue
+		}
+		switch {
+		case param.Example != nil:
+			parameters[param.Name] = param.Example
+		case len(param.Examples) > 0:
+			parameters[param.Name] = param.Examples[0]
+		default:
+			if example := schemaExample(param.Schema); example != nil {
+				parameters[param.Name] = example
+			}
+		}
+	}
+
+	if requestBody, ok := endpoint.RequestBody.(map[string]interface{}); ok {
+		body = mediaExample(requestBody)
+	}
+
+	responses := make(map[string]interface{})
+	for status, response := range endpoint.Responses {
+		if responseMap, ok := response.(map[string]interface{}); ok {
+			if example := mediaExample(responseMap); example != nil {
+				responses[status] = example
+			}
+		}
+	}
+
+	request := map[string]interface{}{
+		"parameters": parameters,
+	}
+	if body != nil {
+		request["body"] = body
+	}
+
+	return map[string]interface{}{
+		"method":    strings.ToUpper(endpoint.Method),
+		"path":      endpoint.Path,
+		"request":   request,
+		"responses": responses,
+	}
+}
+
+// ExampleArguments returns tool arguments for an endpoint's parameters built from their
+// documented examples, defaults, or first enum values, and the required parameters
+// that have none. Request bodies are left out.
+func ExampleArguments(endpoint *types.SwaggerEndpoint) (map[string]interface{}, []string) {
+	arguments := make(map[string]interface{})
+	var missing []string
+
+	for _, param := range endpoint.Parameters {
+		if param.In == "body" {
+			continue
+		}
+
+		var value interface{}
+		schema, _ := param.Schema.(map[string]interface{})
+		switch {
+		case param.Example != nil:
+			value = param.Example
+		case len(param.Examples) > 0:
+			value = param.Examples[0]
+		case schemaExample(schema) != nil:
+			value = schemaExample(schema)
+		case schema["default"] != nil:
+			value = schema["default"]
+		default:
+			if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+				value = enum[0]
+			}
+		}
+
+		if value != nil {
+			arguments[param.Name] = value
+		} else if param.Required {
+			missing = append(missing, param.Name)
+		}
+	}
+
+	return arguments, missing
+}
+
+// endpointSchema collects the parameter, request body, and response schemas of an endpoint
+func (g *ResourceGenerator) endpointSchema(endpoint *types.SwaggerEndpoint) map[string]interface{} {
+	parameters := make([]map[string]interface{}, 0, len(endpoint.Parameters))
+	for _, param := range endpoint.Parameters {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        param.Name,
+			"in":          param.In,
+			"required":    param.Required,
+			"description": param.Description,
+			"schema":      param.Schema,
+		})
+	}
+
+	result := map[string]interface{}{
+		"method":     strings.ToUpper(endpoint.Method),
+		"path":       endpoint.Path,
+		"parameters": parameters,
+		"responses":  endpoint.Responses,
+	}
+	if endpoint.OperationID != "" {
+		result["operationId"] = endpoint.OperationID
+	}
+	if endpoint.RequestBody != nil {
+		result["requestBody"] = endpoint.RequestBody
+	}
+
+	return result
+}
+
+// mediaExample extracts an example from an OpenAPI 3 request body or response
+// ("content" by media type) or a Swagger 2.0 response ("examples" by media type)
+func mediaExample(object map[string]interface{}) interface{} {
+	if content, ok := object["content"].(map[string]interface{}); ok {
+		for _, mediaType := range sortedKeys(content) {
+			media, _ := content[mediaType].(map[string]interface{})
+			if example := namedExample(media); example != nil {
+				return example
+			}
+			if example := schemaExample(media["schema"]); example != nil {
+				return example
+			}
+		}
+	}
+
+	if examples, ok := object["examples"].(map[string]interface{}); ok {
+		if mediaTypes := sortedKeys(examples); len(mediaTypes) > 0 {
+			return examples[mediaTypes[0]]
+		}
+	}
+
+	return schemaExample(object["schema"])
+}
+
+// namedExample returns a media type's "example", or the value of the first of its named "examples"
+func namedExample(media map[string]interface{}) interface{} {
+	if media == nil {
+		return nil
+	}
+	if example, exists := media["example"]; exists {
+		return example
+	}
+	if examples, ok := media["examples"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(examples) {
+			if example, ok := examples[name].(map[string]interface{}); ok && example["value"] != nil {
+				return example["value"]
+			}
+		}
+	}
+	return nil
+}
+
+// schemaExample returns the example declared on a schema, if any
+func schemaExample(schema interface{}) interface{} {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if example, exists := schemaMap["example"]; exists {
+		return example
+	}
+	if examples, ok := schemaMap["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	return nil
+}
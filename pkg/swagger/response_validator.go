@@ -0,0 +1,281 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// maxResponseWarnings bounds the mismatches reported for one response, so that a
+// response of an entirely different shape does not produce a warning per item
+const maxResponseWarnings = 20
+
+// ValidateResponse checks a response against the endpoint's responses: that its
+// status code is declared, and that a JSON body matches the schema declared for the
+// status, with local references resolved against document, which may be nil. It
+// returns the mismatches found, e.g. "$.items[0].id: expected integer, got string",
+// or nil when the response matches or nothing is declared to check it against.
+// Formats and numeric and length bounds are not checked; the mismatches are meant to
+// catch drift between the documented and the actual shape of responses.
+func ValidateResponse(document *types.SwaggerDocument, endpoint *types.SwaggerEndpoint, statusCode int, contentType string, body []byte) []string {
+	if len(endpoint.Responses) == 0 {
+		return nil
+	}
+
+	status := declaredStatus(endpoint.Responses, statusCode)
+	if status == "" {
+		return []string{fmt.Sprintf("status %d is not declared in the responses of %s %s",
+			statusCode, endpoint.Method, endpoint.Path)}
+	}
+
+	flattener := newSchemaFlattener(document)
+	response, ok := flattener.flatten(endpoint.Responses[status]).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	schema, ok := flattener.flatten(responseSchema(response)).(map[string]interface{})
+	if !ok || len(schema) == 0 || len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if contentType != "" && !strings.Contains(strings.ToLower(contentType), "json") {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("response body is not valid JSON: %v", err)}
+	}
+
+	validator := &responseValidator{}
+	validator.validate("$", value, schema)
+	if validator.omitted > 0 {
+		validator.warnings = append(validator.warnings, fmt.Sprintf("%d more mismatches omitted", validator.omitted))
+	}
+	return validator.warnings
+}
+
+// declaredStatus returns the key of the response declared for a status code: the
+// code itself, its range as "2XX", or the default response
+func declaredStatus(responses map[string]interface{}, statusCode int) string {
+	code := strconv.Itoa(statusCode)
+	if _, exists := responses[code]; exists {
+		return code
+	}
+	for _, statusRange := range []string{code[:1] + "XX", code[:1] + "xx"} {
+		if _, exists := responses[statusRange]; exists {
+			return statusRange
+		}
+	}
+	if _, exists := responses["default"]; exists {
+		return "default"
+	}
+	return ""
+}
+
+// responseValidator collects the mismatches of a decoded JSON value with a flattened
+// schema
+type responseValidator struct {
+	warnings []string
+	omitted  int
+}
+
+// mismatch records a mismatch at a location of the value
+func (v *responseValidator) mismatch(location, format string, args ...interface{}) {
+	if len(v.warnings) >= maxResponseWarnings {
+		v.omitted++
+		return
+	}
+	v.warnings = append(v.warnings, location+": "+fmt.Sprintf(format, args...))
+}
+
+// validate checks a value at a location against a schema
+func (v *responseValidator) validate(location string, value interface{}, schema map[string]interface{}) {
+	if value == nil {
+		if schemaAllowsNull(schema) {
+			return
+		}
+		if expected := schemaTypes(schema); len(expected) > 0 {
+			v.mismatch(location, "expected %s, got null", strings.Join(expected, " or "))
+		}
+		return
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if variants, ok := schema[keyword].([]interface{}); ok && len(variants) > 0 {
+			if !v.matchesAny(location, value, variants) {
+				v.mismatch(location, "does not match any schema of %s", keyword)
+			}
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 && !enumContains(enum, value) {
+		v.mismatch(location, "%s is not one of the declared enum values", formatValue(value))
+		return
+	}
+
+	if expected := schemaTypes(schema); len(expected) > 0 {
+		actual := jsonType(value)
+		matched := false
+		for _, name := range expected {
+			if name == actual || (name == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.mismatch(location, "expected %s, got %s", strings.Join(expected, " or "), actual)
+			return
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(location, typed, schema)
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range typed {
+			v.validate(fmt.Sprintf("%s[%d]", location, i), item, items)
+		}
+	}
+}
+
+// validateObject checks the required, declared, and additional properties of an object
+func (v *responseValidator) validateObject(location string, object map[string]interface{}, schema map[string]interface{}) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok {
+				if _, exists := object[name]; !exists {
+					v.mismatch(location, "missing required property %q", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	additional := schema["additionalProperties"]
+	for _, name := range sortedKeys(object) {
+		propertyLocation := location + "." + name
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			v.validate(propertyLocation, object[name], property)
+			continue
+		}
+		switch typed := additional.(type) {
+		case bool:
+			if !typed {
+				v.mismatch(propertyLocation, "property is not declared")
+			}
+		case map[string]interface{}:
+			v.validate(propertyLocation, object[name], typed)
+		}
+	}
+}
+
+// matchesAny reports whether a value matches any of the variants of a oneOf or anyOf
+func (v *responseValidator) matchesAny(location string, value interface{}, variants []interface{}) bool {
+	for _, variant := range variants {
+		variantSchema, ok := variant.(map[string]interface{})
+		if !ok {
+			return true
+		}
+		trial := &responseValidator{}
+		trial.validate(location, value, variantSchema)
+		if len(trial.warnings) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypes returns the types a schema declares, from a type or an OpenAPI 3.1 type
+// list, leaving out null, or the type inferred from its keywords
+func schemaTypes(schema map[string]interface{}) []string {
+	var names []string
+	switch typed := schema["type"].(type) {
+	case string:
+		if typed != "null" && typed != "file" {
+			names = append(names, typed)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			if name, ok := item.(string); ok && name != "null" {
+				names = append(names, name)
+			}
+		}
+	default:
+		if inferred := schemaType(schema); inferred != "" {
+			names = append(names, inferred)
+		}
+	}
+	return names
+}
+
+// schemaAllowsNull reports whether a schema allows null, through nullable, the Swagger
+// 2.0 x-nullable extension, or a type list holding null. Schemas without a type allow
+// any value.
+func schemaAllowsNull(schema map[string]interface{}) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	if nullable, _ := schema["x-nullable"].(bool); nullable {
+		return true
+	}
+	if list, ok := schema["type"].([]interface{}); ok {
+		for _, item := range list {
+			if item == "null" {
+				return true
+			}
+		}
+	}
+	return schema["type"] == "null"
+}
+
+// jsonType returns the JSON schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if typed == math.Trunc(typed) && !math.IsInf(typed, 0) {
+			return "integer"
+		}
+		return "number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumContains reports whether an enum lists a value, comparing by JSON encoding
+func enumContains(enum []interface{}, value interface{}) bool {
+	encoded := formatValue(value)
+	for _, item := range enum {
+		if formatValue(item) == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue encodes a value for comparisons and messages
+func formatValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
@@ -14,7 +14,7 @@ import (
 	"time"
 
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
+	httpclient "swagger-docs-mcp/pkg/http"
 	"swagger-docs-mcp/pkg/types"
 	"swagger-docs-mcp/pkg/utils"
 )
@@ -23,6 +23,7 @@ import (
 type Scanner struct {
 	logger         *utils.Logger
 	defaultOptions *types.ScanOptions
+	hosts          *httpclient.HostPolicy
 }
 
 // NewScanner creates a new swagger document scanner
@@ -33,6 +34,12 @@ func NewScanner(logger *utils.Logger) *Scanner {
 	}
 }
 
+// SetHostPolicy restricts the hosts remote documents are fetched from, including the
+// URLs listed by remote URL arrays and the targets of redirects
+func (s *Scanner) SetHostPolicy(hosts *httpclient.HostPolicy) {
+	s.hosts = hosts
+}
+
 // ScanPaths scans multiple paths for swagger documents
 func (s *Scanner) ScanPaths(paths []string, options *types.ScanOptions) (*types.ScanResult, error) {
 	startTime := time.Now()
@@ -100,6 +107,7 @@ func (s *Scanner) ScanPathsAndURLs(paths []string, urls []string, options *types
 
 	allDocuments := []types.SwaggerDocumentInfo{}
 	allErrors := []types.ScanError{}
+	sources := make([]types.SourceScan, 0, len(paths)+len(urls))
 	totalFiles := 0
 
 	// Scan local paths
@@ -107,14 +115,17 @@ func (s *Scanner) ScanPathsAndURLs(paths []string, urls []string, options *types
 		result, err := s.scanSinglePath(path, resolvedOptions)
 		if err != nil {
 			s.logger.Error("Failed to scan path", zap.String("path", path), zap.Error(err))
-			allErrors = append(allErrors, types.ScanError{
+			scanError := types.ScanError{
 				Path:  path,
 				Error: err.Error(),
-			})
+			}
+			allErrors = append(allErrors, scanError)
+			sources = append(sources, types.SourceScan{Source: path, Errors: []types.ScanError{scanError}})
 			continue
 		}
 		allDocuments = append(allDocuments, result.Documents...)
 		allErrors = append(allErrors, result.Errors...)
+		sources = append(sources, sourceScan(path, false, result))
 		totalFiles += result.Stats.TotalFiles
 	}
 
@@ -123,14 +134,17 @@ func (s *Scanner) ScanPathsAndURLs(paths []string, urls []string, options *types
 		result, err := s.scanSingleURL(u)
 		if err != nil {
 			s.logger.Error("Failed to scan URL", zap.String("url", u), zap.Error(err))
-			allErrors = append(allErrors, types.ScanError{
+			scanError := types.ScanError{
 				Path:  u,
 				Error: err.Error(),
-			})
+			}
+			allErrors = append(allErrors, scanError)
+			sources = append(sources, types.SourceScan{Source: u, IsRemote: true, Errors: []types.ScanError{scanError}})
 			continue
 		}
 		allDocuments = append(allDocuments, result.Documents...)
 		allErrors = append(allErrors, result.Errors...)
+		sources = append(sources, sourceScan(u, true, result))
 		totalFiles += result.Stats.TotalFiles
 	}
 
@@ -152,9 +166,24 @@ func (s *Scanner) ScanPathsAndURLs(paths []string, urls []string, options *types
 		Documents: allDocuments,
 		Errors:    allErrors,
 		Stats:     stats,
+		Sources:   sources,
 	}, nil
 }
 
+// sourceScan summarizes the result of scanning one path or URL
+func sourceScan(source string, isRemote bool, result *types.ScanResult) types.SourceScan {
+	scan := types.SourceScan{
+		Source:    source,
+		IsRemote:  isRemote,
+		Documents: make([]string, 0, len(result.Documents)),
+		Errors:    result.Errors,
+	}
+	for _, document := range result.Documents {
+		scan.Documents = append(scan.Documents, document.FilePath)
+	}
+	return scan
+}
+
 // scanSinglePath scans a single path for swagger documents
 func (s *Scanner) scanSinglePath(path string, options *types.ScanOptions) (*types.ScanResult, error) {
 	s.logger.Debug("Scanning path", zap.String("path", path))
@@ -315,6 +344,7 @@ func (s *Scanner) scanSingleFile(filePath string) (*types.ScanResult, error) {
 	}
 
 	// Copy metadata
+	documentInfo.Specification = metadata.Specification
 	if metadata.PackageIDs != nil {
 		documentInfo.PackageIDs = metadata.PackageIDs
 	}
@@ -330,6 +360,7 @@ func (s *Scanner) scanSingleFile(filePath string) (*types.ScanResult, error) {
 	if metadata.TwcGeography != nil {
 		documentInfo.TwcGeography = metadata.TwcGeography
 	}
+	documentInfo.PreferFormat = metadata.PreferFormat
 
 	return &types.ScanResult{
 		Documents: []types.SwaggerDocumentInfo{documentInfo},
@@ -357,11 +388,13 @@ func (s *Scanner) scanSingleURL(rawURL string) (*types.ScanResult, error) {
 		return nil, fmt.Errorf("unsupported protocol '%s' in URL '%s' - only HTTP/HTTPS supported", parsedURL.Scheme, rawURL)
 	}
 
-	// Fetch the document
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	if err := s.hosts.Check(parsedURL); err != nil {
+		return nil, fmt.Errorf("refused to fetch URL '%s': %w", rawURL, err)
 	}
 
+	// Fetch the document
+	client := s.hosts.Client(30 * time.Second)
+
 	req, err := http.NewRequest("GET", rawURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request for URL '%s': %w", rawURL, err)
@@ -392,10 +425,42 @@ func (s *Scanner) scanSingleURL(rawURL string) (*types.ScanResult, error) {
 		strings.HasSuffix(rawURL, ".yaml") ||
 		strings.HasSuffix(rawURL, ".yml")
 
+	// GraphQL SDL and protobuf descriptor sets are not JSON/YAML; record them as-is
+	// and let the parser handle them
+	specification := ""
+	switch ext := filepath.Ext(parsedURL.Path); {
+	case isGraphQLExtension(ext) || strings.Contains(contentType, "graphql"):
+		specification = types.SpecificationGraphQL
+	case isProtoDescriptorExtension(ext) || strings.Contains(contentType, "protobuf"):
+		specification = types.SpecificationGRPC
+	}
+	if specification != "" {
+		documentInfo := types.SwaggerDocumentInfo{
+			FilePath:      rawURL,
+			Version:       s.extractVersionFromURL(rawURL),
+			Title:         s.createTitleFromURL(rawURL),
+			Endpoints:     []types.SwaggerEndpoint{},
+			IsRemote:      true,
+			Specification: specification,
+			Content:       content,
+		}
+
+		return &types.ScanResult{
+			Documents: []types.SwaggerDocumentInfo{documentInfo},
+			Errors:    []types.ScanError{},
+			Stats: types.ScanStats{
+				TotalFiles:     1,
+				ValidDocuments: 1,
+				Errors:         0,
+				ScanTime:       0,
+			},
+		}, nil
+	}
+
 	// Parse the content first to check if it's an array of URLs
 	var parsedContent interface{}
 	if isYAML {
-		err = yaml.Unmarshal(content, &parsedContent)
+		parsedContent, err = decodeYAML(content)
 	} else {
 		err = json.Unmarshal(content, &parsedContent)
 	}
@@ -438,6 +503,7 @@ func (s *Scanner) scanSingleURL(rawURL string) (*types.ScanResult, error) {
 	}
 
 	// Copy metadata
+	documentInfo.Specification = metadata.Specification
 	if metadata.PackageIDs != nil {
 		documentInfo.PackageIDs = metadata.PackageIDs
 	}
@@ -453,6 +519,7 @@ func (s *Scanner) scanSingleURL(rawURL string) (*types.ScanResult, error) {
 	if metadata.TwcGeography != nil {
 		documentInfo.TwcGeography = metadata.TwcGeography
 	}
+	documentInfo.PreferFormat = metadata.PreferFormat
 
 	s.logger.Debug("Successfully scanned URL",
 		zap.String("url", rawURL),
@@ -714,6 +781,15 @@ func (s *Scanner) createTitleFromURL(rawURL string) string {
 
 // extractDocumentMetadata extracts metadata from a swagger document file
 func (s *Scanner) extractDocumentMetadata(filePath string, extension string) (*types.SwaggerDocumentInfo, error) {
+	// The whole of a large JSON document is only read when tools are generated from it
+	if extension == ".json" {
+		if info, err := os.Stat(filePath); err == nil && info.Size() >= streamingMetadataSize {
+			if metadata, ok := s.streamDocumentMetadata(filePath); ok {
+				return metadata, nil
+			}
+		}
+	}
+
 	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file '%s' (size: %s): %w", filePath, getFileSize(filePath), err)
@@ -722,25 +798,72 @@ func (s *Scanner) extractDocumentMetadata(filePath string, extension string) (*t
 	var document map[string]interface{}
 
 	switch extension {
-	case ".json":
+	case ".json", ".har":
 		if err := json.Unmarshal(content, &document); err != nil {
 			return nil, fmt.Errorf("failed to parse JSON file '%s' (size: %d bytes): %w", filePath, len(content), err)
 		}
 	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(content, &document); err != nil {
+		decoded, err := decodeYAML(content)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse YAML file '%s' (size: %d bytes): %w", filePath, len(content), err)
 		}
+		document, _ = decoded.(map[string]interface{})
 	default:
+		if isGraphQLExtension(extension) {
+			return &types.SwaggerDocumentInfo{Specification: types.SpecificationGraphQL}, nil
+		}
+		if isProtoDescriptorExtension(extension) {
+			return &types.SwaggerDocumentInfo{Specification: types.SpecificationGRPC}, nil
+		}
 		return &types.SwaggerDocumentInfo{}, nil
 	}
 
 	return s.extractMetadataFromDocument(document), nil
 }
 
+// streamDocumentMetadata extracts the metadata of a JSON swagger document by streaming
+// its top-level fields. It reports false when the document cannot be streamed or is not
+// recognized from its top-level fields alone, e.g. a GraphQL introspection result or a
+// HAR capture, for the whole document to be decoded instead.
+func (s *Scanner) streamDocumentMetadata(filePath string) (*types.SwaggerDocumentInfo, bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	document, err := streamJSONMetadata(file)
+	if err != nil {
+		s.logger.Debug("Failed to stream document metadata", zap.String("filePath", filePath), zap.Error(err))
+		return nil, false
+	}
+
+	metadata := s.extractMetadataFromDocument(document)
+	if metadata.Specification == "" {
+		return nil, false
+	}
+	s.logger.Debug("Streamed document metadata", zap.String("filePath", filePath), zap.String("size", getFileSize(filePath)))
+	return metadata, true
+}
+
 // extractMetadataFromDocument extracts metadata from a parsed swagger document
 func (s *Scanner) extractMetadataFromDocument(document map[string]interface{}) *types.SwaggerDocumentInfo {
 	result := &types.SwaggerDocumentInfo{}
 
+	// Recognize the document specification
+	switch {
+	case document["asyncapi"] != nil:
+		result.Specification = types.SpecificationAsyncAPI
+	case document["swagger"] != nil:
+		result.Specification = types.SpecificationSwagger
+	case document["openapi"] != nil:
+		result.Specification = types.SpecificationOpenAPI
+	case graphQLIntrospectionSchema(document) != nil:
+		result.Specification = types.SpecificationGraphQL
+	case harLog(document) != nil:
+		result.Specification = types.SpecificationHAR
+	}
+
 	// Extract package IDs
 	result.PackageIDs = s.extractStringArrayFromInterface(document["x-package-ids"])
 
@@ -756,6 +879,11 @@ func (s *Scanner) extractMetadataFromDocument(document map[string]interface{}) *
 	// Extract TWC geography
 	result.TwcGeography = s.extractStringArrayFromInterface(document["x-twc-geography"])
 
+	// Extract the preferred format
+	if preferFormat, ok := document["x-mcp-prefer-format"].(string); ok {
+		result.PreferFormat = strings.TrimSpace(preferFormat)
+	}
+
 	return result
 }
 
@@ -0,0 +1,70 @@
+package swagger
+
+import (
+	"container/list"
+	"sync"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// defaultSchemaCacheSize is how many lazily generated input schemas are kept when
+// the configuration sets no size
+const defaultSchemaCacheSize = 512
+
+// schemaCache keeps the most recently used input schemas of tools generated with lazy
+// schemas, keyed by endpoint
+type schemaCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[*types.SwaggerEndpoint]*list.Element
+}
+
+// schemaCacheEntry is an input schema of the cache with its endpoint
+type schemaCacheEntry struct {
+	endpoint *types.SwaggerEndpoint
+	schema   map[string]interface{}
+}
+
+// newSchemaCache creates a cache keeping up to capacity schemas
+func newSchemaCache(capacity int) *schemaCache {
+	if capacity <= 0 {
+		capacity = defaultSchemaCacheSize
+	}
+	return &schemaCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[*types.SwaggerEndpoint]*list.Element),
+	}
+}
+
+// get returns the schema of an endpoint, generating and caching it when it is not
+// cached, and evicting the least recently used schema when the cache is full
+func (c *schemaCache) get(endpoint *types.SwaggerEndpoint, generate func() map[string]interface{}) map[string]interface{} {
+	c.mutex.Lock()
+	if element, exists := c.entries[endpoint]; exists {
+		c.order.MoveToFront(element)
+		schema := element.Value.(*schemaCacheEntry).schema
+		c.mutex.Unlock()
+		return schema
+	}
+	c.mutex.Unlock()
+
+	// Schemas are generated outside the lock; concurrent misses of one endpoint at
+	// worst generate its schema twice
+	schema := generate()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, exists := c.entries[endpoint]; exists {
+		c.order.MoveToFront(element)
+		return element.Value.(*schemaCacheEntry).schema
+	}
+	c.entries[endpoint] = c.order.PushFront(&schemaCacheEntry{endpoint: endpoint, schema: schema})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*schemaCacheEntry).endpoint)
+	}
+	return schema
+}
@@ -0,0 +1,231 @@
+package swagger
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+)
+
+// Kinds of documentation search results
+const (
+	SearchResultEndpoint = "endpoint"
+	SearchResultSchema   = "schema"
+	SearchResultOverview = "overview"
+)
+
+// searchSnippetLength bounds the length of the text excerpt returned with a search result
+const searchSnippetLength = 200
+
+// SearchResult is a part of a swagger document that matches a documentation search
+type SearchResult struct {
+	Kind         string  `json:"kind"`
+	Title        string  `json:"title"`
+	Snippet      string  `json:"snippet,omitempty"`
+	Method       string  `json:"method,omitempty"`
+	Path         string  `json:"path,omitempty"`
+	Document     string  `json:"document"`
+	DocumentPath string  `json:"documentPath"`
+	URI          string  `json:"uri,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// SearchEntry is a searchable part of a swagger document: an endpoint, a schema, or
+// the API overview. The text fields are what a search index matches queries against;
+// Result is what a match returns.
+type SearchEntry struct {
+	Result      SearchResult
+	Name        string
+	Summary     string
+	Description string
+	Parameters  string
+	Tags        string
+}
+
+// SearchEnabled reports whether documentation search is configured
+func (g *ResourceGenerator) SearchEnabled() bool {
+	return g.config.Enabled && g.config.EnableDocumentationSearch
+}
+
+// SearchEntries returns the searchable endpoints, schemas, and overview of a document.
+// Names are the endpoint path and operation ID, the schema name, or the API title.
+func (g *ResourceGenerator) SearchEntries(doc *types.SwaggerDocument, docInfo *types.SwaggerDocumentInfo) []SearchEntry {
+	var entries []SearchEntry
+	add := func(entry SearchEntry) {
+		entry.Result.Document = docInfo.Title
+		entry.Result.DocumentPath = docInfo.FilePath
+		entries = append(entries, entry)
+	}
+
+	// API overview: title, description, and tags
+	overview := SearchEntry{
+		Result: SearchResult{
+			Kind:  SearchResultOverview,
+			Title: g.createResourceName(docInfo, "API Overview"),
+			URI:   g.createResourceURI(docInfo, "overview", "md"),
+		},
+		Name: docInfo.Title,
+	}
+	if doc.Info != nil {
+		overview.Name = searchText(docInfo.Title, doc.Info.Title)
+		overview.Description = doc.Info.Description
+		overview.Result.Snippet = searchSnippet(doc.Info.Description)
+	}
+	var tagNames, tagDescriptions []string
+	for _, tag := range doc.Tags {
+		if tagMap, ok := tag.(map[string]interface{}); ok {
+			name, _ := tagMap["name"].(string)
+			description, _ := tagMap["description"].(string)
+			tagNames = append(tagNames, name)
+			tagDescriptions = append(tagDescriptions, description)
+		}
+	}
+	overview.Tags = searchText(tagNames...)
+	overview.Description = searchText(append([]string{overview.Description}, tagDescriptions...)...)
+	add(overview)
+
+	// Endpoints
+	parser := NewParser(g.logger)
+	endpoints, err := parser.ExtractEndpoints(doc)
+	if err != nil {
+		g.logger.Warn("Failed to extract endpoints for search", zap.Error(err), zap.String("filePath", docInfo.FilePath))
+	}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+
+		var parameterNames, parameterDescriptions []string
+		for _, param := range endpoint.Parameters {
+			parameterNames = append(parameterNames, param.Name)
+			parameterDescriptions = append(parameterDescriptions, param.Description)
+		}
+
+		snippet := endpoint.Summary
+		if snippet == "" {
+			snippet = endpoint.Description
+		}
+		add(SearchEntry{
+			Result: SearchResult{
+				Kind:    SearchResultEndpoint,
+				Title:   strings.ToUpper(endpoint.Method) + " " + endpoint.Path,
+				Snippet: searchSnippet(snippet),
+				Method:  strings.ToUpper(endpoint.Method),
+				Path:    endpoint.Path,
+				URI:     g.createEndpointResourceURI(docInfo, endpoint, "example", "json"),
+			},
+			Name:        searchText(endpoint.Path, endpoint.OperationID),
+			Summary:     endpoint.Summary,
+			Description: searchText(append([]string{endpoint.Description}, parameterDescriptions...)...),
+			Parameters:  searchText(parameterNames...),
+			Tags:        searchText(endpoint.Tags...),
+		})
+	}
+
+	// Schemas from OpenAPI 3 components or Swagger 2.0 definitions
+	schemas := documentSchemas(doc)
+	for _, name := range sortedKeys(schemas) {
+		schema, _ := schemas[name].(map[string]interface{})
+		title, _ := schema["title"].(string)
+		description, _ := schema["description"].(string)
+
+		// Property names and descriptions document the schema too
+		var propertyNames, propertyDescriptions []string
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for _, property := range sortedKeys(properties) {
+				propertyNames = append(propertyNames, property)
+				if propertyMap, ok := properties[property].(map[string]interface{}); ok {
+					propertyDescription, _ := propertyMap["description"].(string)
+					propertyDescriptions = append(propertyDescriptions, propertyDescription)
+				}
+			}
+		}
+
+		add(SearchEntry{
+			Result: SearchResult{
+				Kind:    SearchResultSchema,
+				Title:   name,
+				Snippet: searchSnippet(description),
+				URI:     g.createResourceURI(docInfo, "swagger", "json"),
+			},
+			Name:        searchText(name, title),
+			Description: searchText(append([]string{description}, propertyDescriptions...)...),
+			Parameters:  searchText(propertyNames...),
+		})
+	}
+
+	return entries
+}
+
+// SortSearchResults orders search results by descending score, then by document and title
+func SortSearchResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].DocumentPath != results[j].DocumentPath {
+			return results[i].DocumentPath < results[j].DocumentPath
+		}
+		return results[i].Title < results[j].Title
+	})
+}
+
+// documentSchemas returns the named schemas a document defines
+func documentSchemas(doc *types.SwaggerDocument) map[string]interface{} {
+	if components, ok := doc.Components.(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			return schemas
+		}
+	}
+	if definitions, ok := doc.Definitions.(map[string]interface{}); ok {
+		return definitions
+	}
+	return nil
+}
+
+// searchText joins pieces of text for indexing. Identifiers are also added split into
+// words, so that "listPets" and "pet_id" match searches for "pets" and "id".
+func searchText(parts ...string) string {
+	var words []string
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		words = append(words, part)
+		if split := splitIdentifier(part); split != part {
+			words = append(words, split)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitIdentifier separates the words of camelCase, snake_case, and path identifiers
+func splitIdentifier(identifier string) string {
+	var builder strings.Builder
+	runes := []rune(identifier)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '/' || r == '{' || r == '}' || r == '.':
+			builder.WriteRune(' ')
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			builder.WriteRune(' ')
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// searchSnippet shortens text to the snippet length on a word boundary
+func searchSnippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= searchSnippetLength {
+		return text
+	}
+	cut := strings.LastIndex(text[:searchSnippetLength], " ")
+	if cut <= 0 {
+		cut = searchSnippetLength
+	}
+	return text[:cut] + "..."
+}
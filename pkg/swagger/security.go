@@ -0,0 +1,126 @@
+package swagger
+
+import (
+	"fmt"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// SetAuth sets the auth configuration that decides whether the security requirements
+// of endpoints can be met, for the toolGeneration.unsupportedAuth policy. Without it,
+// every endpoint is generated as if its requirements were met.
+func (g *ToolGenerator) SetAuth(auth *types.AuthConfig) {
+	g.auth = auth
+}
+
+// unsupportedSecurity describes the security requirements of an endpoint when none of
+// its alternatives can be met by the configured auth, and returns "" otherwise
+func (g *ToolGenerator) unsupportedSecurity(endpoint *types.SwaggerEndpoint) string {
+	if g.auth == nil || len(endpoint.Security) == 0 {
+		return ""
+	}
+
+	schemes := g.availableCredentialSchemes()
+	var alternatives []string
+	for _, requirementInterface := range endpoint.Security {
+		requirement, _ := requirementInterface.(map[string]interface{})
+		if securityRequirementMet(requirement, endpoint.SecuritySchemes, schemes) {
+			return ""
+		}
+
+		var names []string
+		for _, name := range sortedKeys(requirement) {
+			names = append(names, describeSecurityScheme(name, endpoint.SecuritySchemes[name]))
+		}
+		alternatives = append(alternatives, strings.Join(names, " and "))
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// availableCredentialSchemes returns the credential schemes requests can be sent with:
+// the API key's, and those of the configured credentials. Credentials keyed by a host
+// count for every endpoint, as the host of a request is only known when it is made.
+func (g *ToolGenerator) availableCredentialSchemes() map[types.CredentialScheme]bool {
+	schemes := make(map[types.CredentialScheme]bool)
+	if g.auth.APIKey != "" {
+		if g.auth.DefaultScheme == "apikey" {
+			schemes[types.CredentialSchemeAPIKey] = true
+		} else {
+			schemes[types.CredentialSchemeBearer] = true
+		}
+	}
+	for _, credential := range g.auth.Credentials {
+		switch credential.Scheme {
+		case "", types.CredentialSchemeBearer, types.CredentialSchemeJWT:
+			schemes[types.CredentialSchemeBearer] = true
+		default:
+			schemes[credential.Scheme] = true
+		}
+	}
+	return schemes
+}
+
+// securityRequirementMet reports whether every scheme of a security requirement can be
+// met. An empty requirement makes authentication optional, and schemes that are not
+// defined by the document are given the benefit of the doubt.
+func securityRequirementMet(requirement map[string]interface{}, definitions map[string]interface{}, schemes map[types.CredentialScheme]bool) bool {
+	for name := range requirement {
+		definition, ok := definitions[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !securitySchemeMet(definition, schemes) {
+			return false
+		}
+	}
+	return true
+}
+
+// securitySchemeMet reports whether a security scheme definition can be met with one of
+// the credential schemes. OAuth 2 and OpenID Connect schemes are met by bearer tokens,
+// which may be access tokens obtained out of band; cookies and mutual TLS never are.
+func securitySchemeMet(definition map[string]interface{}, schemes map[types.CredentialScheme]bool) bool {
+	schemeType, _ := definition["type"].(string)
+	switch strings.ToLower(schemeType) {
+	case "http":
+		scheme, _ := definition["scheme"].(string)
+		switch strings.ToLower(scheme) {
+		case "bearer":
+			return schemes[types.CredentialSchemeBearer]
+		case "basic":
+			return schemes[types.CredentialSchemeBasic]
+		}
+		return false
+	case "basic":
+		return schemes[types.CredentialSchemeBasic]
+	case "apikey":
+		in, _ := definition["in"].(string)
+		switch strings.ToLower(in) {
+		case "header":
+			return schemes[types.CredentialSchemeAPIKey]
+		case "query":
+			return schemes[types.CredentialSchemeQuery]
+		}
+		return false
+	case "oauth2", "openidconnect":
+		return schemes[types.CredentialSchemeBearer]
+	case "mutualtls":
+		return false
+	}
+	return true
+}
+
+// describeSecurityScheme names a security scheme with its type, e.g.
+// "petstore_auth (oauth2)"
+func describeSecurityScheme(name string, definitionInterface interface{}) string {
+	definition, _ := definitionInterface.(map[string]interface{})
+	schemeType, _ := definition["type"].(string)
+	if scheme, ok := definition["scheme"].(string); ok && strings.EqualFold(schemeType, "http") {
+		schemeType = scheme
+	}
+	if schemeType == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, schemeType)
+}
@@ -0,0 +1,92 @@
+package swagger
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"swagger-docs-mcp/pkg/types"
+)
+
+// PrioritizeTools orders tools from the most to the least worth keeping when there are
+// more than the server serves. Tools are ranked by the first of the priority patterns
+// matching their name, then by the first of the package IDs their document has, then
+// non-deprecated before deprecated, then the preferred format before others. Ties are
+// ordered by the order their documents first appear in, then by name, so that the same
+// tools are kept from one scan to the next.
+func (g *ToolGenerator) PrioritizeTools(tools []*types.GeneratedTool, priority []string, packageIDs []string) []*types.GeneratedTool {
+	documents := make(map[*types.SwaggerDocumentInfo]int)
+	ranks := make(map[*types.GeneratedTool][5]int, len(tools))
+	for _, tool := range tools {
+		if _, exists := documents[tool.DocumentInfo]; !exists {
+			documents[tool.DocumentInfo] = len(documents)
+		}
+		ranks[tool] = [5]int{
+			patternRank(tool.Name, priority),
+			packageIDRank(tool.DocumentInfo, packageIDs),
+			deprecatedRank(tool.Endpoint),
+			g.formatRank(tool),
+			documents[tool.DocumentInfo],
+		}
+	}
+
+	prioritized := append([]*types.GeneratedTool{}, tools...)
+	sort.Slice(prioritized, func(i, j int) bool {
+		left, right := ranks[prioritized[i]], ranks[prioritized[j]]
+		for k := range left {
+			if left[k] != right[k] {
+				return left[k] < right[k]
+			}
+		}
+		return prioritized[i].Name < prioritized[j].Name
+	})
+	return prioritized
+}
+
+// patternRank is the index of the first pattern matching a tool name, or the number of
+// patterns when none does
+func patternRank(name string, patterns []string) int {
+	for i, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return i
+		}
+	}
+	return len(patterns)
+}
+
+// packageIDRank is the index of the first package ID of a document, or the number of
+// package IDs when the document has none of them
+func packageIDRank(document *types.SwaggerDocumentInfo, packageIDs []string) int {
+	if document != nil {
+		for i, packageID := range packageIDs {
+			for _, documentPackageID := range document.PackageIDs {
+				if documentPackageID == packageID {
+					return i
+				}
+			}
+		}
+	}
+	return len(packageIDs)
+}
+
+// deprecatedRank ranks deprecated endpoints, which are only generated with
+// includeDeprecated, after the others
+func deprecatedRank(endpoint *types.SwaggerEndpoint) int {
+	if endpoint != nil && endpoint.Deprecated {
+		return 1
+	}
+	return 0
+}
+
+// formatRank ranks endpoints in another format than the one preferred for their
+// document after the others
+func (g *ToolGenerator) formatRank(tool *types.GeneratedTool) int {
+	if g.config == nil || tool.Endpoint == nil || tool.Endpoint.Protocol != "" {
+		return 0
+	}
+	preferFormat, _ := g.documentFormats(tool.DocumentInfo)
+	if preferFormat == "" || strings.EqualFold(g.detectEndpointFormat(tool.Endpoint), preferFormat) {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,73 @@
+package swagger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// transliterations maps lowercase letters of the Latin, Greek, and Cyrillic scripts to
+// their ASCII romanizations
+var transliterations = func() map[rune]string {
+	groups := map[string]string{
+		// Latin
+		"àáâãäåāăą": "a", "æ": "ae", "çćĉċč": "c", "ďđð": "d", "èéêëēĕėęě": "e",
+		"ĝğġģ": "g", "ĥħ": "h", "ìíîïĩīĭįı": "i", "ĳ": "ij", "ĵ": "j", "ķĸ": "k",
+		"ĺļľŀł": "l", "ñńņňŉŋ": "n", "òóôõöøōŏő": "o", "œ": "oe", "ŕŗř": "r",
+		"śŝşšș": "s", "ß": "ss", "ţťŧț": "t", "þ": "th", "ùúûüũūŭůűų": "u", "ŵ": "w",
+		"ýÿŷ": "y", "źżž": "z",
+		// Greek
+		"αά": "a", "β": "v", "γ": "g", "δ": "d", "εέ": "e", "ζ": "z", "ηή": "i",
+		"θ": "th", "ιίϊΐ": "i", "κ": "k", "λ": "l", "μ": "m", "ν": "n", "ξ": "x",
+		"οό": "o", "π": "p", "ρ": "r", "σς": "s", "τ": "t", "υύϋΰ": "y", "φ": "f",
+		"χ": "ch", "ψ": "ps", "ωώ": "o",
+		// Cyrillic
+		"а": "a", "б": "b", "в": "v", "гґ": "g", "д": "d", "еёэ": "e", "є": "ye",
+		"ж": "zh", "з": "z", "иі": "i", "ї": "yi", "й": "y", "к": "k", "л": "l",
+		"м": "m", "н": "n", "о": "o", "п": "p", "р": "r", "с": "s", "т": "t",
+		"у": "u", "ф": "f", "х": "kh", "ц": "ts", "ч": "ch", "ш": "sh", "щ": "shch",
+		"ъь": "", "ы": "y", "ю": "yu", "я": "ya",
+	}
+
+	table := make(map[rune]string)
+	for letters, romanized := range groups {
+		for _, letter := range letters {
+			table[letter] = romanized
+		}
+	}
+	return table
+}()
+
+// transliterate replaces the non-ASCII letters of a lowercase name with their ASCII
+// romanizations and drops combining marks, reporting whether a letter or digit had no
+// romanization and was lost
+func transliterate(name string) (string, bool) {
+	var result strings.Builder
+	lossy := false
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII:
+			result.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks, e.g. the dot of a lowercased İ, carry no letter
+		default:
+			if romanized, ok := transliterations[r]; ok {
+				result.WriteString(romanized)
+				continue
+			}
+			if unicode.IsLetter(r) || unicode.IsNumber(r) {
+				lossy = true
+			}
+			result.WriteRune('_')
+		}
+	}
+	return result.String(), lossy
+}
+
+// nameHash returns a short hash of a name, telling apart names that would otherwise
+// collide, such as tool names whose letters could not be romanized
+func nameHash(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:4])
+}
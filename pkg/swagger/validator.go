@@ -0,0 +1,75 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/types"
+	"swagger-docs-mcp/pkg/utils"
+)
+
+// Validator performs structural validation of OpenAPI 3.x and Swagger 2.0 documents
+type Validator struct {
+	logger *utils.Logger
+}
+
+// NewValidator creates a new document validator
+func NewValidator(logger *utils.Logger) *Validator {
+	return &Validator{
+		logger: logger.Child("validator"),
+	}
+}
+
+// Validate checks a normalized JSON document against the OpenAPI specification and
+// returns the structural violations found. Swagger 2.0 documents are converted to
+// OpenAPI 3 before validation.
+func (v *Validator) Validate(data []byte, document *types.SwaggerDocument) []string {
+	var spec *openapi3.T
+
+	if document.Swagger != "" {
+		var spec2 openapi2.T
+		if err := json.Unmarshal(data, &spec2); err != nil {
+			return []string{fmt.Sprintf("invalid Swagger 2.0 structure: %v", err)}
+		}
+
+		converted, err := openapi2conv.ToV3(&spec2)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to convert Swagger 2.0 document: %v", err)}
+		}
+		spec = converted
+	} else {
+		loader := openapi3.NewLoader()
+		loaded, err := loader.LoadFromData(data)
+		if err != nil {
+			return []string{fmt.Sprintf("invalid OpenAPI structure: %v", err)}
+		}
+		spec = loaded
+	}
+
+	err := spec.Validate(context.Background(), openapi3.DisableExamplesValidation())
+	if err == nil {
+		return nil
+	}
+
+	var violations []string
+	var multiErr openapi3.MultiError
+	if errors.As(err, &multiErr) {
+		for _, item := range multiErr {
+			violations = append(violations, item.Error())
+		}
+	} else {
+		violations = append(violations, err.Error())
+	}
+
+	v.logger.Debug("Document validation found violations",
+		zap.String("title", getInfoTitle(document)),
+		zap.Int("violations", len(violations)))
+
+	return violations
+}
@@ -0,0 +1,91 @@
+package swagger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAML decodes every document in a YAML stream and merges them into a single
+// value. Merge keys (<<) and anchors are expanded by the decoder; mappings from later
+// documents are deep-merged over earlier ones, sequences are appended, and scalars
+// from later documents replace earlier values. Mapping keys are normalized to strings
+// so the result can be re-encoded as JSON.
+func decodeYAML(content []byte) (interface{}, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+	var merged interface{}
+	for index := 0; ; index++ {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("YAML document %d: %w", index+1, err)
+		}
+
+		// Skip empty documents such as a leading or trailing "---"
+		if document == nil {
+			continue
+		}
+
+		merged = mergeYAMLValues(merged, normalizeYAMLValue(document))
+	}
+
+	return merged, nil
+}
+
+// normalizeYAMLValue converts YAML mappings with non-string keys (such as numeric
+// response codes) into map[string]interface{}
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAMLValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// mergeYAMLValues merges overlay into base following multi-document stream semantics
+func mergeYAMLValues(base, overlay interface{}) interface{} {
+	if base == nil {
+		return overlay
+	}
+
+	switch overlayValue := overlay.(type) {
+	case map[string]interface{}:
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			return overlay
+		}
+		for key, item := range overlayValue {
+			baseMap[key] = mergeYAMLValues(baseMap[key], item)
+		}
+		return baseMap
+	case []interface{}:
+		baseSlice, ok := base.([]interface{})
+		if !ok {
+			return overlay
+		}
+		return append(baseSlice, overlayValue...)
+	default:
+		return overlay
+	}
+}
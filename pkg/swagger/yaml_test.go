@@ -0,0 +1,148 @@
+package swagger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "single document",
+			content: "openapi: 3.0.0\ninfo:\n  title: API\n",
+			want: map[string]interface{}{
+				"openapi": "3.0.0",
+				"info":    map[string]interface{}{"title": "API"},
+			},
+		},
+		{
+			name:    "numeric keys become strings",
+			content: "responses:\n  200:\n    description: OK\n  404:\n    description: Missing\n",
+			want: map[string]interface{}{
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "OK"},
+					"404": map[string]interface{}{"description": "Missing"},
+				},
+			},
+		},
+		{
+			name:    "later documents are deep-merged",
+			content: "info:\n  title: API\n  version: \"1\"\n---\ninfo:\n  version: \"2\"\npaths: {}\n",
+			want: map[string]interface{}{
+				"info":  map[string]interface{}{"title": "API", "version": "2"},
+				"paths": map[string]interface{}{},
+			},
+		},
+		{
+			name:    "sequences are appended",
+			content: "tags:\n  - a\n---\ntags:\n  - b\n",
+			want:    map[string]interface{}{"tags": []interface{}{"a", "b"}},
+		},
+		{
+			name:    "empty documents are skipped",
+			content: "---\ntitle: API\n---\n",
+			want:    map[string]interface{}{"title": "API"},
+		},
+		{
+			name:    "anchors and merge keys are expanded",
+			content: "base: &base\n  type: string\nschema:\n  <<: *base\n  format: date\n",
+			want: map[string]interface{}{
+				"base":   map[string]interface{}{"type": "string"},
+				"schema": map[string]interface{}{"type": "string", "format": "date"},
+			},
+		},
+		{
+			name:    "empty stream",
+			content: "",
+			want:    nil,
+		},
+		{
+			name:    "invalid document",
+			content: "title: API\n---\ntitle: [unterminated\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := decodeYAML([]byte(test.content))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("decodeYAML() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("decodeYAML() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMergeYAMLValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    interface{}
+		overlay interface{}
+		want    interface{}
+	}{
+		{
+			name:    "nil base takes the overlay",
+			base:    nil,
+			overlay: map[string]interface{}{"a": 1},
+			want:    map[string]interface{}{"a": 1},
+		},
+		{
+			name:    "mappings are merged key by key",
+			base:    map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}},
+			overlay: map[string]interface{}{"b": map[string]interface{}{"d": 3}},
+			want:    map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2, "d": 3}},
+		},
+		{
+			name:    "sequences are appended",
+			base:    []interface{}{1},
+			overlay: []interface{}{2, 3},
+			want:    []interface{}{1, 2, 3},
+		},
+		{
+			name:    "scalars are replaced",
+			base:    "old",
+			overlay: "new",
+			want:    "new",
+		},
+		{
+			name:    "a mapping replaces a scalar",
+			base:    "old",
+			overlay: map[string]interface{}{"a": 1},
+			want:    map[string]interface{}{"a": 1},
+		},
+		{
+			name:    "a sequence replaces a mapping",
+			base:    map[string]interface{}{"a": 1},
+			overlay: []interface{}{1},
+			want:    []interface{}{1},
+		},
+		{
+			name:    "a nil overlay clears a value",
+			base:    map[string]interface{}{"a": 1},
+			overlay: map[string]interface{}{"a": nil},
+			want:    map[string]interface{}{"a": nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := mergeYAMLValues(test.base, test.overlay); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("mergeYAMLValues() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
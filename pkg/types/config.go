@@ -1,6 +1,11 @@
 package types
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
 
 // CLIOptions represents command-line interface options
 type CLIOptions struct {
@@ -22,9 +27,213 @@ type CLIOptions struct {
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
-	Port     int           `mapstructure:"port" yaml:"port" json:"port"`
-	Timeout  time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
-	MaxTools int           `mapstructure:"max_tools" yaml:"maxTools" json:"maxTools"`
+	Port     int              `mapstructure:"port" yaml:"port" json:"port"`
+	Timeout  time.Duration    `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+	MaxTools int              `mapstructure:"max_tools" yaml:"maxTools" json:"maxTools"`
+	Auth     ServerAuthConfig `mapstructure:"auth" yaml:"auth" json:"auth"`
+	// MaxMessageSize is the size in bytes of the largest JSON-RPC message read from
+	// stdin; larger messages are answered with an error and skipped
+	MaxMessageSize int `mapstructure:"max_message_size" yaml:"maxMessageSize" json:"maxMessageSize"`
+	// Listen is the address the SSE or MCP HTTP server listens on instead of Port,
+	// host:port for TCP or unix:/path.sock for a Unix domain socket
+	Listen string `mapstructure:"listen" yaml:"listen" json:"listen,omitempty"`
+	// SocketMode is the octal file mode Unix domain sockets are created with, e.g.
+	// 0660 to let the group of the server connect
+	SocketMode string `mapstructure:"socket_mode" yaml:"socketMode" json:"socketMode,omitempty"`
+	// SnapshotPath is a file the tool registry is persisted to after each scan and
+	// loaded from on startup. Empty disables the snapshot.
+	SnapshotPath string `mapstructure:"snapshot_path" yaml:"snapshotPath" json:"snapshotPath"`
+	// UsagePath is a file the tool usage counters and the quota usage of API keys are
+	// persisted to, so that they carry on across restarts. Empty keeps them in memory.
+	UsagePath string `mapstructure:"usage_path" yaml:"usagePath" json:"usagePath"`
+	// DocsAssetsURL is the npm CDN the Swagger UI and Redoc pages of /docs load their
+	// scripts and styles from, e.g. an internal mirror for networks without internet
+	DocsAssetsURL string `mapstructure:"docs_assets_url" yaml:"docsAssetsUrl" json:"docsAssetsUrl"`
+	// Transports are served side by side by one process, sharing one scan of the
+	// documents. Empty serves the single transport selected by --sse or --mcp-http.
+	Transports []Transport `mapstructure:"transports" yaml:"transports" json:"transports"`
+	// MCPHTTPPort is the port of the MCP HTTP transport when it is served alongside
+	// the SSE transport, Port+1 by default
+	MCPHTTPPort int `mapstructure:"mcp_http_port" yaml:"mcpHttpPort" json:"mcpHttpPort"`
+	// MCPHTTPListen is the address of the MCP HTTP transport when it is served
+	// alongside the SSE transport, like Listen; it replaces MCPHTTPPort
+	MCPHTTPListen string `mapstructure:"mcp_http_listen" yaml:"mcpHttpListen" json:"mcpHttpListen,omitempty"`
+	// ToolPriority holds glob patterns of tool names kept first when there are more
+	// tools than MaxTools, in order of priority
+	ToolPriority []string `mapstructure:"tool_priority" yaml:"toolPriority" json:"toolPriority"`
+	// Gateway serves a tool searching the operations of the generated tools and a tool
+	// calling one by operation ID instead of one tool per operation, for clients that
+	// limit the number of tools
+	Gateway bool `mapstructure:"gateway" yaml:"gateway" json:"gateway"`
+	// Blobs controls how the binary responses of tool calls, e.g. images and PDFs, are
+	// returned
+	Blobs BlobsConfig `mapstructure:"blobs" yaml:"blobs" json:"blobs"`
+	// Events controls what the tool_execution events broadcast to SSE clients disclose
+	Events EventsConfig `mapstructure:"events" yaml:"events" json:"events"`
+	// Quotas limits the tool calls each API key makes through the SSE REST endpoints
+	Quotas QuotaConfig `mapstructure:"quotas" yaml:"quotas" json:"quotas"`
+	// Webhooks are endpoints the tool calls of every transport are posted to, with
+	// their arguments redacted like those of events
+	Webhooks []WebhookConfig `mapstructure:"webhooks" yaml:"webhooks" json:"webhooks,omitempty"`
+	// Bus publishes tool call and tool change events to a message bus, so that the
+	// activity of several server instances can be consumed in one place
+	Bus BusConfig `mapstructure:"bus" yaml:"bus" json:"bus"`
+	// SharedRegistry shares one tool catalog between the replicas of a deployment
+	// through Redis, so that replicas behind a load balancer list the same tools
+	SharedRegistry SharedRegistryConfig `mapstructure:"shared_registry" yaml:"sharedRegistry" json:"sharedRegistry"`
+}
+
+// SharedRegistryConfig configures the Redis store of the tool catalog shared by the
+// replicas of a deployment. One replica at a time leads, publishing the tools it
+// loads; the others serve the published catalog.
+type SharedRegistryConfig struct {
+	// URL is the Redis server, redis:// or rediss:// with TLS, with credentials as
+	// user:password@ or :password@ and the database as the path. Empty shares nothing.
+	URL string `mapstructure:"url" yaml:"url" json:"url,omitempty"`
+	// Key prefixes the Redis keys of the catalog, so that deployments can share a server
+	Key string `mapstructure:"key" yaml:"key" json:"key,omitempty"`
+	// SyncInterval is how often replicas check the catalog for changes and renew
+	// the lease of the leading replica, which expires after three intervals
+	SyncInterval time.Duration `mapstructure:"sync_interval" yaml:"syncInterval" json:"syncInterval,omitempty"`
+	// RefreshLeaderOnly leaves the periodic refreshes of the remote documents to the
+	// leading replica, which publishes their tools to the others
+	RefreshLeaderOnly bool `mapstructure:"refresh_leader_only" yaml:"refreshLeaderOnly" json:"refreshLeaderOnly,omitempty"`
+}
+
+// Message buses events are published to
+const (
+	BusNATS  = "nats"
+	BusKafka = "kafka"
+)
+
+// Serializations of the events published to a message bus
+const (
+	// BusSerializationJSON publishes events as JSON objects with their id, type,
+	// source, time, and data
+	BusSerializationJSON = "json"
+	// BusSerializationCloudEvents publishes events in the structured JSON format of
+	// CloudEvents 1.0
+	BusSerializationCloudEvents = "cloudevents"
+)
+
+// BusConfig configures the message bus events are published to
+type BusConfig struct {
+	// Type is the bus: nats, or kafka through a Kafka REST proxy. Empty publishes nothing.
+	Type string `mapstructure:"type" yaml:"type" json:"type,omitempty"`
+	// Brokers are the URLs of the NATS servers (nats:// or tls://, with credentials
+	// as user:password@ or token@) or of the Kafka REST proxies, tried in order
+	Brokers []string `mapstructure:"brokers" yaml:"brokers" json:"brokers,omitempty"`
+	// Topic is the NATS subject or Kafka topic events are published to
+	Topic string `mapstructure:"topic" yaml:"topic" json:"topic,omitempty"`
+	// Serialization is the format of the published events, json or cloudevents
+	Serialization string `mapstructure:"serialization" yaml:"serialization" json:"serialization,omitempty"`
+	// Headers are sent with every request to a Kafka REST proxy, e.g. to authenticate
+	Headers map[string]string `mapstructure:"headers" yaml:"headers" json:"headers,omitempty"`
+}
+
+// Webhook event types
+const (
+	// WebhookToolExecution is posted for every completed tool call
+	WebhookToolExecution = "tool_execution"
+	// WebhookToolError is posted for tool calls that failed or returned an error
+	WebhookToolError = "tool_error"
+)
+
+// WebhookConfig is an endpoint tool call events are posted to as JSON
+type WebhookConfig struct {
+	URL string `mapstructure:"url" yaml:"url" json:"url"`
+	// Headers are sent with every post, e.g. to authenticate with the endpoint
+	Headers map[string]string `mapstructure:"headers" yaml:"headers" json:"headers,omitempty"`
+	// Events are the event types posted, tool_execution and tool_error. Empty posts both.
+	Events []string `mapstructure:"events" yaml:"events" json:"events,omitempty"`
+}
+
+// QuotaConfig limits the tool calls of an API key per clock minute and per UTC day.
+// Zero is unlimited.
+type QuotaConfig struct {
+	PerMinute int `mapstructure:"per_minute" yaml:"perMinute" json:"perMinute"`
+	PerDay    int `mapstructure:"per_day" yaml:"perDay" json:"perDay"`
+}
+
+// EventsConfig controls the tool_execution events the SSE transport broadcasts to
+// every connected client, which may see the calls of other clients
+type EventsConfig struct {
+	// SensitiveFields are argument names, or glob patterns of them, whose values are
+	// redacted from events at any depth, matched regardless of case. Names like
+	// apiKey, password, secret, and token are always redacted.
+	SensitiveFields []string `mapstructure:"sensitive_fields" yaml:"sensitiveFields" json:"sensitiveFields,omitempty"`
+	// MaxResultSize omits the content of results larger than this many bytes from
+	// events. Zero broadcasts results of any size.
+	MaxResultSize int `mapstructure:"max_result_size" yaml:"maxResultSize" json:"maxResultSize"`
+	// Log persists the broadcast events so that clients can backfill those they missed
+	Log EventLogConfig `mapstructure:"log" yaml:"log" json:"log"`
+}
+
+// BlobsConfig controls the large and binary responses of tool calls. Binary responses
+// up to MaxInlineSize are returned as base64 content; larger ones, and text responses
+// larger than MaxInlineTextSize, are kept in memory and returned as a summary and a
+// resource URI to read them from.
+type BlobsConfig struct {
+	// MaxInlineSize is the size in bytes of the largest binary response returned as
+	// base64 content
+	MaxInlineSize int `mapstructure:"max_inline_size" yaml:"maxInlineSize" json:"maxInlineSize"`
+	// MaxInlineTextSize is the size in bytes of the largest text response returned
+	// inline. Zero returns text responses of any size.
+	MaxInlineTextSize int `mapstructure:"max_inline_text_size" yaml:"maxInlineTextSize" json:"maxInlineTextSize"`
+	// TTL is how long a larger response can be read as a resource
+	TTL time.Duration `mapstructure:"ttl" yaml:"ttl" json:"ttl"`
+	// MaxStoredSize bounds the bytes of the responses kept as resources; the oldest
+	// are dropped first
+	MaxStoredSize int `mapstructure:"max_stored_size" yaml:"maxStoredSize" json:"maxStoredSize"`
+}
+
+// EventLogConfig configures the append-only log of the events broadcast to SSE clients
+// and how long events are retained in it
+type EventLogConfig struct {
+	// Path is the file events are appended to. Empty disables the log.
+	Path string `mapstructure:"path" yaml:"path" json:"path"`
+	// MaxAge drops events older than this from the log. Zero keeps events of any age.
+	MaxAge time.Duration `mapstructure:"max_age" yaml:"maxAge" json:"maxAge"`
+	// MaxEvents keeps only this many of the latest events. Zero keeps any number.
+	MaxEvents int `mapstructure:"max_events" yaml:"maxEvents" json:"maxEvents"`
+}
+
+// Transport is a way of serving the tools
+type Transport string
+
+const (
+	// TransportStdio serves MCP over standard input and output
+	TransportStdio Transport = "stdio"
+	// TransportSSE serves the REST and Server-Sent Events API
+	TransportSSE Transport = "sse"
+	// TransportMCPHTTP serves MCP over Streamable HTTP
+	TransportMCPHTTP Transport = "mcp-http"
+)
+
+// ServerAuthConfig represents authentication for the MCP streamable HTTP endpoint.
+// Requests must present either one of the static bearer tokens or a JWT issued by
+// the configured OIDC issuer for the configured audience.
+type ServerAuthConfig struct {
+	BearerTokens []string `mapstructure:"bearer_tokens" yaml:"bearerTokens" json:"bearerTokens"`
+	OIDCIssuer   string   `mapstructure:"oidc_issuer" yaml:"oidcIssuer" json:"oidcIssuer"`
+	OIDCAudience string   `mapstructure:"oidc_audience" yaml:"oidcAudience" json:"oidcAudience"`
+	// Tenants maps client API keys, presented as bearer tokens, to the tools the
+	// clients holding them may list and call. With tenants, the SSE REST endpoints
+	// require a tenant key or one of the bearer tokens too.
+	Tenants map[string]TenantConfig `mapstructure:"tenants" yaml:"tenants" json:"tenants"`
+}
+
+// TenantConfig entitles a tenant's clients to the tools of the documents matching its
+// criteria, which match like the package-ids and twc-* tool filters. A tenant without
+// criteria is entitled to every tool.
+type TenantConfig struct {
+	Name           string   `mapstructure:"name" yaml:"name" json:"name"`
+	PackageIDs     []string `mapstructure:"package_ids" yaml:"packageIds" json:"packageIds"`
+	TWCPortfolios  []string `mapstructure:"twc_portfolios" yaml:"twcPortfolios" json:"twcPortfolios"`
+	TWCDomains     []string `mapstructure:"twc_domains" yaml:"twcDomains" json:"twcDomains"`
+	TWCGeographies []string `mapstructure:"twc_geographies" yaml:"twcGeographies" json:"twcGeographies"`
+	// Quotas replaces the server quotas that it sets for the tenant's key
+	Quotas QuotaConfig `mapstructure:"quotas" yaml:"quotas" json:"quotas"`
 }
 
 // HTTPConfig represents HTTP client configuration
@@ -32,19 +241,186 @@ type HTTPConfig struct {
 	Timeout   time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
 	Retries   int           `mapstructure:"retries" yaml:"retries" json:"retries"`
 	UserAgent string        `mapstructure:"user_agent" yaml:"userAgent" json:"userAgent"`
+	// HedgeDelay sends a second copy of a GET request that has not completed after
+	// this long, taking whichever response arrives first. Zero disables hedging.
+	HedgeDelay time.Duration `mapstructure:"hedge_delay" yaml:"hedgeDelay" json:"hedgeDelay"`
+	// Transport tunes the connection pool of the requests tool calls make
+	Transport TransportConfig `mapstructure:"transport" yaml:"transport" json:"transport"`
+	// DNS resolves the hosts of the requests tool calls make
+	DNS DNSConfig `mapstructure:"dns" yaml:"dns" json:"dns"`
+	// AllowedHosts restricts tool calls and fetched documents to these hosts and the
+	// hosts of the configured swagger URLs, base URLs, gRPC gateway, and GraphQL
+	// endpoint. Patterns are host names, "host:port", "*.example.com", IP addresses, or
+	// CIDR ranges. Empty allows any host.
+	AllowedHosts []string `mapstructure:"allowed_hosts" yaml:"allowedHosts" json:"allowedHosts,omitempty"`
+	// DeniedHosts are never contacted, even when allowed, with the same patterns. IP
+	// addresses and CIDR ranges are also checked against the addresses host names
+	// resolve to.
+	DeniedHosts []string `mapstructure:"denied_hosts" yaml:"deniedHosts" json:"deniedHosts,omitempty"`
+	// Concurrency limits the upstream requests tool calls make at the same time
+	Concurrency ConcurrencyConfig `mapstructure:"concurrency" yaml:"concurrency" json:"concurrency"`
+}
+
+// ConcurrencyConfig limits concurrent upstream requests, so that a burst of tool calls
+// neither exhausts sockets nor overloads the upstream API. Calls beyond the limits
+// wait in a bounded queue; calls that find the queue full, or wait longer than the
+// queue timeout, are rejected. Zero values are unlimited.
+type ConcurrencyConfig struct {
+	// MaxRequests caps the upstream requests in flight across all hosts
+	MaxRequests int `mapstructure:"max_requests" yaml:"maxRequests" json:"maxRequests"`
+	// MaxPerHost caps the upstream requests in flight to each host
+	MaxPerHost int `mapstructure:"max_per_host" yaml:"maxPerHost" json:"maxPerHost"`
+	// QueueSize caps the calls waiting for a request slot. Zero rejects calls beyond
+	// the limits at once.
+	QueueSize int `mapstructure:"queue_size" yaml:"queueSize" json:"queueSize"`
+	// QueueTimeout rejects calls that waited this long for a slot. Zero waits until
+	// the call is cancelled or times out.
+	QueueTimeout time.Duration `mapstructure:"queue_timeout" yaml:"queueTimeout" json:"queueTimeout"`
+	// Rejection decides how rejected calls fail
+	Rejection ConcurrencyRejection `mapstructure:"rejection" yaml:"rejection" json:"rejection,omitempty"`
+}
+
+// ConcurrencyRejection is how a tool call rejected by the concurrency limits fails
+type ConcurrencyRejection string
+
+const (
+	// RejectionError fails the call with an error (the default)
+	RejectionError ConcurrencyRejection = "error"
+	// RejectionBusy returns a 503 Service Unavailable result with a Retry-After
+	// header, which clients see as a retriable upstream error
+	RejectionBusy ConcurrencyRejection = "busy"
+)
+
+// DNSConfig configures how the hosts of upstream requests are resolved, e.g. for
+// internal API host names that only a custom DNS server knows
+type DNSConfig struct {
+	// CacheTTL keeps resolved addresses for this long. Zero disables caching.
+	CacheTTL time.Duration `mapstructure:"cache_ttl" yaml:"cacheTtl" json:"cacheTtl"`
+	// Servers are DNS servers, as "host:port" or a host using port 53, queried in
+	// order instead of the system resolver
+	Servers []string `mapstructure:"servers" yaml:"servers" json:"servers,omitempty"`
+	// Hosts maps host names to IP addresses, resolved without DNS like /etc/hosts
+	Hosts map[string]string `mapstructure:"hosts" yaml:"hosts" json:"hosts,omitempty"`
+}
+
+// TransportConfig tunes the HTTP transport of upstream requests, e.g. for
+// high-throughput deployments. Zero values keep the defaults of Go's transport.
+type TransportConfig struct {
+	// MaxIdleConns caps the idle connections kept across all hosts (default 100)
+	MaxIdleConns int `mapstructure:"max_idle_conns" yaml:"maxIdleConns" json:"maxIdleConns"`
+	// MaxIdleConnsPerHost caps the idle connections kept per host (default 2)
+	MaxIdleConnsPerHost int `mapstructure:"max_idle_conns_per_host" yaml:"maxIdleConnsPerHost" json:"maxIdleConnsPerHost"`
+	// MaxConnsPerHost caps the connections per host, including those in use; requests
+	// beyond it wait for a connection (default unlimited)
+	MaxConnsPerHost int `mapstructure:"max_conns_per_host" yaml:"maxConnsPerHost" json:"maxConnsPerHost"`
+	// IdleConnTimeout closes connections idle for this long (default 90s)
+	IdleConnTimeout time.Duration `mapstructure:"idle_conn_timeout" yaml:"idleConnTimeout" json:"idleConnTimeout"`
+	// TLSHandshakeTimeout bounds TLS handshakes (default 10s)
+	TLSHandshakeTimeout time.Duration `mapstructure:"tls_handshake_timeout" yaml:"tlsHandshakeTimeout" json:"tlsHandshakeTimeout"`
+	// DisableHTTP2 keeps requests on HTTP/1.1 instead of negotiating HTTP/2 over TLS
+	DisableHTTP2 bool `mapstructure:"disable_http2" yaml:"disableHttp2" json:"disableHttp2"`
 }
 
 // AuthConfig represents authentication configuration
 type AuthConfig struct {
-	APIKey        string            `mapstructure:"api_key" yaml:"apiKey" json:"apiKey"`
-	DefaultScheme string            `mapstructure:"default_scheme" yaml:"defaultScheme" json:"defaultScheme"`
-	Credentials   map[string]string `mapstructure:"credentials" yaml:"credentials" json:"credentials"`
+	APIKey        string `mapstructure:"api_key" yaml:"apiKey" json:"apiKey"`
+	DefaultScheme string `mapstructure:"default_scheme" yaml:"defaultScheme" json:"defaultScheme"`
+	// Credentials are sent instead of the API key to the APIs they are keyed by: a
+	// document, matched like mock.documents, or a request host such as
+	// "api.example.com", "api.example.com:8443", or "*.example.com"
+	Credentials map[string]CredentialConfig `mapstructure:"credentials" yaml:"credentials" json:"credentials"`
+	// Signing signs the requests to matching hosts with HMAC signatures
+	Signing []SigningConfig `mapstructure:"signing" yaml:"signing" json:"signing"`
+}
+
+// CredentialConfig is a secret and the scheme it is sent with
+type CredentialConfig struct {
+	Scheme CredentialScheme `mapstructure:"scheme" yaml:"scheme" json:"scheme"`
+	Secret string           `mapstructure:"secret" yaml:"secret" json:"secret"`
+	// Name is the header of the apikey scheme, X-API-Key by default, or the query
+	// parameter of the query scheme, apiKey by default
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	// JWT configures the assertions of the jwt scheme
+	JWT JWTAssertionConfig `mapstructure:"jwt" yaml:"jwt" json:"jwt"`
+}
+
+// JWTAssertionConfig configures the short-lived JWTs of the jwt credential scheme.
+// They are signed with the PEM private key in the credential's secret or in
+// PrivateKeyFile: RS256 for RSA keys, ES256/ES384/ES512 for EC keys, and EdDSA for
+// Ed25519 keys.
+type JWTAssertionConfig struct {
+	PrivateKeyFile string `mapstructure:"private_key_file" yaml:"privateKeyFile" json:"privateKeyFile"`
+	// KeyID is sent as the kid header, if set
+	KeyID    string `mapstructure:"key_id" yaml:"keyId" json:"keyId"`
+	Issuer   string `mapstructure:"issuer" yaml:"issuer" json:"issuer"`
+	Audience string `mapstructure:"audience" yaml:"audience" json:"audience"`
+	// Subject is the sub claim, the issuer by default
+	Subject string `mapstructure:"subject" yaml:"subject" json:"subject"`
+	// TTL is how long each JWT is valid, 5 minutes by default. JWTs are reused until
+	// shortly before they expire.
+	TTL time.Duration `mapstructure:"ttl" yaml:"ttl" json:"ttl"`
+	// Claims are added to the registered claims, e.g. a scope
+	Claims map[string]interface{} `mapstructure:"claims" yaml:"claims" json:"claims"`
+}
+
+// CredentialScheme is how a credential's secret is sent
+type CredentialScheme string
+
+const (
+	// CredentialSchemeBearer sends the secret as a bearer token; it is the default
+	CredentialSchemeBearer CredentialScheme = "bearer"
+	// CredentialSchemeAPIKey sends the secret in a header
+	CredentialSchemeAPIKey CredentialScheme = "apikey"
+	// CredentialSchemeBasic sends a "user:password" secret with basic authentication
+	CredentialSchemeBasic CredentialScheme = "basic"
+	// CredentialSchemeQuery sends the secret as a query parameter
+	CredentialSchemeQuery CredentialScheme = "query"
+	// CredentialSchemeJWT sends a JWT signed with the private key in the secret as a
+	// bearer token
+	CredentialSchemeJWT CredentialScheme = "jwt"
+)
+
+// SigningConfig configures the HMAC signing of upstream requests. The signed message
+// and the signature header value are templates with the placeholders {timestamp} (Unix
+// seconds), {method}, {host}, {path}, {query}, {body}, and {bodySha256}; the header
+// value also has {signature}.
+type SigningConfig struct {
+	// Hosts are the request hosts or host patterns to sign requests to; empty signs all requests
+	Hosts     []string         `mapstructure:"hosts" yaml:"hosts" json:"hosts"`
+	Key       string           `mapstructure:"key" yaml:"key" json:"key"`
+	Algorithm SigningAlgorithm `mapstructure:"algorithm" yaml:"algorithm" json:"algorithm"`
+	// Message is the signed message, {timestamp}{path} by default
+	Message string `mapstructure:"message" yaml:"message" json:"message"`
+	// Header receives the signature, X-Signature by default
+	Header string `mapstructure:"header" yaml:"header" json:"header"`
+	// HeaderTemplate is the value of the signature header, {signature} by default
+	HeaderTemplate string `mapstructure:"header_template" yaml:"headerTemplate" json:"headerTemplate"`
+	// TimestampHeader receives the signed timestamp, X-Timestamp by default
+	TimestampHeader string `mapstructure:"timestamp_header" yaml:"timestampHeader" json:"timestampHeader"`
+	// Encoding is how the signature is encoded: hex, the default, or base64
+	Encoding string `mapstructure:"encoding" yaml:"encoding" json:"encoding"`
 }
 
+// SigningAlgorithm is the HMAC hash function of a request signature
+type SigningAlgorithm string
+
+const (
+	// SigningAlgorithmSHA256 signs with HMAC-SHA256; it is the default
+	SigningAlgorithmSHA256 SigningAlgorithm = "hmac-sha256"
+	// SigningAlgorithmSHA1 signs with HMAC-SHA1
+	SigningAlgorithmSHA1 SigningAlgorithm = "hmac-sha1"
+	// SigningAlgorithmSHA512 signs with HMAC-SHA512
+	SigningAlgorithmSHA512 SigningAlgorithm = "hmac-sha512"
+)
+
 // LoggingConfig represents logging configuration
 type LoggingConfig struct {
 	Level   string `mapstructure:"level" yaml:"level" json:"level"`
 	Enabled bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// EgressSampleRate logs this fraction, from 0 to 1, of upstream requests in full,
+	// with their credentials redacted. MCP clients can change it at runtime through
+	// logging/setLevel.
+	EgressSampleRate float64 `mapstructure:"egress_sample_rate" yaml:"egressSampleRate" json:"egressSampleRate"`
 }
 
 // ToolGenerationConfig represents tool generation configuration
@@ -55,13 +431,90 @@ type ToolGenerationConfig struct {
 	TagPrefix            string   `mapstructure:"tag_prefix" yaml:"tagPrefix" json:"tagPrefix"`
 	IgnoreFormats        []string `mapstructure:"ignore_formats" yaml:"ignoreFormats" json:"ignoreFormats"`
 	PreferFormat         string   `mapstructure:"prefer_format" yaml:"preferFormat" json:"preferFormat"`
+	// OmitFilterArgument leaves the optional _filter argument, a JMESPath expression
+	// applied to the JSON response, out of generated tools
+	OmitFilterArgument bool `mapstructure:"omit_filter_argument" yaml:"omitFilterArgument" json:"omitFilterArgument"`
+	// LazySchemas generates the input schema of each tool on first use instead of
+	// upfront, keeping the SchemaCacheSize most recently used schemas
+	LazySchemas     bool `mapstructure:"lazy_schemas" yaml:"lazySchemas" json:"lazySchemas"`
+	SchemaCacheSize int  `mapstructure:"schema_cache_size" yaml:"schemaCacheSize" json:"schemaCacheSize"`
+	// DisabledTools holds tool names or glob patterns of tools that are not generated,
+	// along with the prompts and resources derived from their endpoints
+	DisabledTools []string `mapstructure:"disabled_tools" yaml:"disabledTools" json:"disabledTools"`
+	// DocumentFormats overrides preferFormat and ignoreFormats for documents keyed by
+	// their path or URL, file name, file name without the extension, or a pattern
+	// matching one of these
+	DocumentFormats map[string]DocumentFormatConfig `mapstructure:"document_formats" yaml:"documentFormats" json:"documentFormats"`
+	// UnsupportedAuth decides what happens to endpoints whose security requirements
+	// none of the configured credentials can meet
+	UnsupportedAuth UnsupportedAuthPolicy `mapstructure:"unsupported_auth" yaml:"unsupportedAuth" json:"unsupportedAuth"`
+	// DescriptionTemplate is a Go template rendering tool descriptions from a
+	// ToolDescriptionData instead of the default "[vN] summary (Tags: ...)" format
+	DescriptionTemplate string `mapstructure:"description_template" yaml:"descriptionTemplate" json:"descriptionTemplate"`
+}
+
+// ToolDescriptionData is what description templates render tool descriptions from
+type ToolDescriptionData struct {
+	Summary     string
+	Description string
+	Method      string
+	Path        string
+	OperationID string
+	Tags        []string
+	Deprecated  bool
+	// Title and Version are those of the endpoint's document
+	Title   string
+	Version string
+	// PackageIDs and the TWC metadata come from the document's x-package-ids and
+	// x-twc-* extensions
+	PackageIDs  []string
+	Portfolios  []string
+	Domains     []string
+	Usages      []string
+	Geographies []string
+}
+
+// ParseDescriptionTemplate parses a tool description template. Besides the built-in
+// functions, templates may use join (e.g. {{join .Tags ", "}}), lower, and upper.
+func ParseDescriptionTemplate(text string) (*template.Template, error) {
+	return template.New("description").Funcs(template.FuncMap{
+		"join":  func(items []string, separator string) string { return strings.Join(items, separator) },
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}).Parse(text)
+}
+
+// UnsupportedAuthPolicy is how tools are generated for endpoints whose security
+// requirements cannot be met by the configured auth
+type UnsupportedAuthPolicy string
+
+const (
+	// UnsupportedAuthInclude generates their tools like any other; it is the default
+	UnsupportedAuthInclude UnsupportedAuthPolicy = "include"
+	// UnsupportedAuthMark notes the missing authentication in their tool descriptions
+	UnsupportedAuthMark UnsupportedAuthPolicy = "mark"
+	// UnsupportedAuthSkip generates no tools for them
+	UnsupportedAuthSkip UnsupportedAuthPolicy = "skip"
+)
+
+// DocumentFormatConfig holds the format filtering of one document. Empty fields keep
+// the document's x-mcp-prefer-format extension or the global setting.
+type DocumentFormatConfig struct {
+	IgnoreFormats []string `mapstructure:"ignore_formats" yaml:"ignoreFormats" json:"ignoreFormats"`
+	PreferFormat  string   `mapstructure:"prefer_format" yaml:"preferFormat" json:"preferFormat"`
 }
 
 // SwaggerProcessingConfig represents swagger processing configuration
 type SwaggerProcessingConfig struct {
-	ValidateDocuments bool `mapstructure:"validate_documents" yaml:"validateDocuments" json:"validateDocuments"`
-	ResolveReferences bool `mapstructure:"resolve_references" yaml:"resolveReferences" json:"resolveReferences"`
-	IgnoreErrors      bool `mapstructure:"ignore_errors" yaml:"ignoreErrors" json:"ignoreErrors"`
+	ValidateDocuments bool          `mapstructure:"validate_documents" yaml:"validateDocuments" json:"validateDocuments"`
+	ResolveReferences bool          `mapstructure:"resolve_references" yaml:"resolveReferences" json:"resolveReferences"`
+	IgnoreErrors      bool          `mapstructure:"ignore_errors" yaml:"ignoreErrors" json:"ignoreErrors"`
+	RefreshInterval   time.Duration `mapstructure:"refresh_interval" yaml:"refreshInterval" json:"refreshInterval"`
+	AllowedRefHosts   []string      `mapstructure:"allowed_ref_hosts" yaml:"allowedRefHosts" json:"allowedRefHosts"`
+	WatchFiles        bool          `mapstructure:"watch_files" yaml:"watchFiles" json:"watchFiles"`
+	// StaleGracePeriod is how long a remote document may keep failing to refresh before
+	// its tools are evicted. Zero keeps them indefinitely.
+	StaleGracePeriod time.Duration `mapstructure:"stale_grace_period" yaml:"staleGracePeriod" json:"staleGracePeriod"`
 }
 
 // TWCFilters represents TWC-specific filtering options
@@ -78,54 +531,347 @@ type PromptsConfig struct {
 	IncludeExamples       bool     `mapstructure:"include_examples" yaml:"includeExamples" json:"includeExamples"`
 	GenerateFromEndpoints bool     `mapstructure:"generate_from_endpoints" yaml:"generateFromEndpoints" json:"generateFromEndpoints"`
 	Categories            []string `mapstructure:"categories" yaml:"categories" json:"categories"`
+	// ConflictPolicy decides what happens when documents generate different prompts
+	// with the same name
+	ConflictPolicy PromptConflictPolicy `mapstructure:"conflict_policy" yaml:"conflictPolicy" json:"conflictPolicy"`
+	// TemplateDir holds markdown and YAML prompt templates that are served alongside
+	// the generated prompts, replacing those with the same name
+	TemplateDir string `mapstructure:"template_dir" yaml:"templateDir" json:"templateDir"`
+	// CategoryDefinitions are prompt categories matched before the built-in weather
+	// categories, for APIs whose endpoints the weather keywords do not describe
+	CategoryDefinitions []PromptCategoryDefinition `mapstructure:"category_definitions" yaml:"categoryDefinitions" json:"categoryDefinitions"`
+	// DisableWeatherCategories leaves endpoints no category definition matches
+	// uncategorized instead of matching them against the weather categories, and
+	// leaves out the weather comparison and analysis prompts
+	DisableWeatherCategories bool `mapstructure:"disable_weather_categories" yaml:"disableWeatherCategories" json:"disableWeatherCategories"`
+	// DocumentPrompts defines prompts for documents like their x-mcp-prompts extension,
+	// keyed like toolGeneration.documentFormats; they take precedence over the extension
+	DocumentPrompts map[string][]PromptDefinition `mapstructure:"document_prompts" yaml:"documentPrompts" json:"documentPrompts"`
 }
 
+// PromptCategoryDefinition defines a prompt category: the keywords that put an endpoint
+// in it, and the skeleton of its overview prompt
+type PromptCategoryDefinition struct {
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	// Keywords are matched case-insensitively against the path, summary, and
+	// description of endpoints
+	Keywords    []string `mapstructure:"keywords" yaml:"keywords" json:"keywords"`
+	Description string   `mapstructure:"description" yaml:"description" json:"description,omitempty"`
+	// Template is the template of the category's overview prompt, with {{argument}}
+	// placeholders; it defaults to a request covering the category's endpoints
+	Template string `mapstructure:"template" yaml:"template" json:"template,omitempty"`
+	// Arguments default to an optional argument per placeholder of the template
+	Arguments []MCPPromptArgument `mapstructure:"arguments" yaml:"arguments" json:"arguments,omitempty"`
+}
+
+// PromptConflictPolicy is how a prompt registry resolves a name that is already taken
+type PromptConflictPolicy string
+
+const (
+	// PromptConflictError rejects the later prompt, like conflicting tool names
+	PromptConflictError PromptConflictPolicy = "error"
+	// PromptConflictSuffix registers the later prompt under its name suffixed with its document
+	PromptConflictSuffix PromptConflictPolicy = "suffix-with-document"
+	// PromptConflictKeepFirst keeps the earlier prompt and drops the later one without an error
+	PromptConflictKeepFirst PromptConflictPolicy = "keep-first"
+)
+
 // ResourcesConfig represents resources configuration
 type ResourcesConfig struct {
 	Enabled                   bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
 	ExposeSwaggerDocs         bool `mapstructure:"expose_swagger_docs" yaml:"exposeSwaggerDocs" json:"exposeSwaggerDocs"`
 	EnableDocumentationSearch bool `mapstructure:"enable_documentation_search" yaml:"enableDocumentationSearch" json:"enableDocumentationSearch"`
 	AllowEndpointDiscovery    bool `mapstructure:"allow_endpoint_discovery" yaml:"allowEndpointDiscovery" json:"allowEndpointDiscovery"`
+	// URIScheme is the scheme of resource URIs
+	URIScheme string `mapstructure:"uri_scheme" yaml:"uriScheme" json:"uriScheme"`
+	// DocumentNaming decides how documents are named in resource URIs
+	DocumentNaming ResourceDocumentNaming `mapstructure:"document_naming" yaml:"documentNaming" json:"documentNaming"`
+	// DocumentIDs names the documents matching a pattern in resource URIs, taking
+	// precedence over DocumentNaming. Keys are matched like toolGeneration.documentFormats.
+	DocumentIDs map[string]string `mapstructure:"document_ids" yaml:"documentIds" json:"documentIds"`
+	// ContentCacheSize is how many rendered resource contents are kept for later reads
+	// (default 256)
+	ContentCacheSize int `mapstructure:"content_cache_size" yaml:"contentCacheSize" json:"contentCacheSize"`
+}
+
+// ResourceDocumentNaming is how documents are named in resource URIs
+type ResourceDocumentNaming string
+
+const (
+	// ResourceNamingFileName names documents after their file name without the
+	// extension, which documents in different directories may share
+	ResourceNamingFileName ResourceDocumentNaming = "file-name"
+	// ResourceNamingFileNameHash appends a short hash of the document's path or URL to
+	// its file name, keeping the names of documents sharing a file name apart
+	ResourceNamingFileNameHash ResourceDocumentNaming = "file-name-hash"
+)
+
+// GraphQLConfig represents configuration for executing GraphQL-backed tools
+type GraphQLConfig struct {
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+}
+
+// MockConfig represents mock execution configuration. Mocked tool calls do not reach
+// the upstream API; they return example responses from the endpoint's response
+// examples or derived from its response schema.
+type MockConfig struct {
+	// Enabled mocks every tool call
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// Documents mocks the tool calls of matching documents only. Patterns are matched
+	// against a document's path, file name, and file name without the extension.
+	Documents []string `mapstructure:"documents" yaml:"documents" json:"documents"`
+}
+
+// ResponseValidationConfig represents configuration for checking the responses of
+// tool calls against the response schemas their endpoints declare. Mismatches do not
+// fail calls; they are attached to the results as warnings.
+type ResponseValidationConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+}
+
+// CassetteConfig represents record/replay configuration. Upstream responses of tool
+// calls are recorded to cassette files, one per tool, keyed by the call's arguments,
+// and replayed for later calls with the same arguments.
+type CassetteConfig struct {
+	// Dir is the directory cassette files are kept in. Empty disables recording and replay.
+	Dir  string       `mapstructure:"dir" yaml:"dir" json:"dir"`
+	Mode CassetteMode `mapstructure:"mode" yaml:"mode" json:"mode"`
+}
+
+// CassetteMode decides when tool calls are replayed from and recorded to cassettes
+type CassetteMode string
+
+const (
+	// CassetteModeAuto replays recorded calls and records calls that were not recorded yet
+	CassetteModeAuto CassetteMode = "auto"
+	// CassetteModeRecord always calls the upstream API and records the response,
+	// replacing earlier recordings of the same call
+	CassetteModeRecord CassetteMode = "record"
+	// CassetteModeReplay only replays recorded calls; calls that were not recorded fail
+	CassetteModeReplay CassetteMode = "replay"
+)
+
+// PaginationConfig represents configuration for following the pages of paginated
+// endpoints when a tool call passes the _fetchAll argument
+type PaginationConfig struct {
+	// MaxPages caps the number of pages fetched and merged for one tool call
+	MaxPages int `mapstructure:"max_pages" yaml:"maxPages" json:"maxPages"`
+}
+
+// ToolExecutionConfig controls the results of tool calls returned to clients
+type ToolExecutionConfig struct {
+	// MaxResponseChars truncates text responses longer than this many characters to
+	// their first and last MaxResponseChars/2 characters around an elision marker
+	// naming the original size. Zero returns responses of any length.
+	MaxResponseChars int `mapstructure:"max_response_chars" yaml:"maxResponseChars" json:"maxResponseChars"`
+}
+
+// TelemetryConfig configures the reports of anonymous, aggregate usage counts, which
+// are only sent when explicitly turned on
+type TelemetryConfig struct {
+	Mode TelemetryMode `mapstructure:"mode" yaml:"mode" json:"mode"`
+	// Endpoint is the URL the reports are posted to as JSON
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint,omitempty"`
+}
+
+// TelemetryMode decides whether telemetry reports are sent
+type TelemetryMode string
+
+const (
+	// TelemetryOff sends no reports. It is the default.
+	TelemetryOff TelemetryMode = "off"
+	// TelemetryOn sends reports to the configured endpoint
+	TelemetryOn TelemetryMode = "on"
+)
+
+// UpdateConfig configures the check for newer releases of the server on GitHub
+type UpdateConfig struct {
+	// Check looks up the latest release at startup and logs a notice when it is newer
+	Check bool `mapstructure:"check" yaml:"check" json:"check"`
+	// Repository is the GitHub repository, owner/name, releases are looked up in
+	Repository string `mapstructure:"repository" yaml:"repository" json:"repository,omitempty"`
+	// APIURL is the GitHub API releases are read from, e.g. that of GitHub Enterprise
+	APIURL string `mapstructure:"api_url" yaml:"apiUrl" json:"apiUrl,omitempty"`
+}
+
+// PluginConfig configures an external executable hooked into the upstream requests of
+// tool calls. The executable is run for each request it applies to, reading a JSON
+// document describing the request or response on stdin and writing its changes as
+// JSON to stdout, so that it can be written in any language.
+type PluginConfig struct {
+	// Name identifies the plugin in logs and errors, the base name of its executable
+	// by default
+	Name string `mapstructure:"name" yaml:"name" json:"name,omitempty"`
+	// Hook is when the plugin runs: pre-request, auth, or post-response
+	Hook PluginHook `mapstructure:"hook" yaml:"hook" json:"hook"`
+	// Command is the executable and its arguments
+	Command []string `mapstructure:"command" yaml:"command" json:"command"`
+	// Hosts are the request hosts or host patterns the plugin applies to; empty applies
+	// to all requests
+	Hosts []string `mapstructure:"hosts" yaml:"hosts" json:"hosts,omitempty"`
+	// Timeout bounds each run of the plugin, 5s by default
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// PluginHook is the point of an upstream request a plugin runs at
+type PluginHook string
+
+const (
+	// PluginHookPreRequest plugins may change the method, URL, headers, and body of a
+	// request before it is authenticated and sent
+	PluginHookPreRequest PluginHook = "pre-request"
+	// PluginHookAuth plugins return headers and query parameters authenticating or
+	// signing the final request, e.g. for bespoke signature schemes
+	PluginHookAuth PluginHook = "auth"
+	// PluginHookPostResponse plugins may change the status, headers, and body of a
+	// response before it is returned, e.g. to scrub sensitive fields
+	PluginHookPostResponse PluginHook = "post-response"
+)
+
+// ParsePluginSpec parses a plugin given as hook=command, e.g. on the command line,
+// splitting the command into its executable and arguments at spaces
+func ParsePluginSpec(spec string) PluginConfig {
+	hook, command, _ := strings.Cut(spec, "=")
+	return PluginConfig{Hook: PluginHook(strings.TrimSpace(hook)), Command: strings.Fields(command)}
+}
+
+// ToolOverrideConfig overrides HTTP settings for the calls of one tool. It takes
+// precedence over the x-mcp-timeout and x-mcp-retries extensions of the tool's
+// operation.
+type ToolOverrideConfig struct {
+	// Timeout is the deadline of a call, retries included. Zero keeps the default.
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+	// Retries replaces the configured number of HTTP retries when set
+	Retries *int `mapstructure:"retries" yaml:"retries" json:"retries"`
+	// HedgeDelay replaces the configured delay before GET requests are hedged. Zero
+	// keeps the default.
+	HedgeDelay time.Duration `mapstructure:"hedge_delay" yaml:"hedgeDelay" json:"hedgeDelay"`
+}
+
+// CompositeToolConfig defines a tool that chains calls of generated tools, e.g.
+// geocoding an address and then fetching the conditions at the location found. Step
+// arguments and the output are templates: strings may hold {{ expression }}
+// placeholders, JMESPath expressions evaluated against the tool's arguments as
+// "input" and the decoded responses of earlier steps by ID as "steps".
+type CompositeToolConfig struct {
+	Name        string                 `mapstructure:"name" yaml:"name" json:"name"`
+	Description string                 `mapstructure:"description" yaml:"description" json:"description"`
+	InputSchema map[string]interface{} `mapstructure:"input_schema" yaml:"inputSchema" json:"inputSchema"`
+	Steps       []CompositeToolStep    `mapstructure:"steps" yaml:"steps" json:"steps"`
+	// Output is the template of the tool's result. It defaults to the responses of all
+	// steps by ID.
+	Output interface{} `mapstructure:"output" yaml:"output" json:"output,omitempty"`
+}
+
+// CompositeToolStep is a call of a generated tool made by a composite tool
+type CompositeToolStep struct {
+	ID        string                 `mapstructure:"id" yaml:"id" json:"id"`
+	Tool      string                 `mapstructure:"tool" yaml:"tool" json:"tool"`
+	Arguments map[string]interface{} `mapstructure:"arguments" yaml:"arguments" json:"arguments"`
+}
+
+// CompositeTemplatePlaceholder matches the {{ expression }} placeholders of composite
+// tool templates; its submatch is the expression
+var CompositeTemplatePlaceholder = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+
+// GRPCConfig represents configuration for executing tools generated from gRPC services
+type GRPCConfig struct {
+	GatewayURL string `mapstructure:"gateway_url" yaml:"gatewayUrl" json:"gatewayUrl"`
 }
 
 // ConfigFile represents the configuration file format
 type ConfigFile struct {
-	Name              string                   `mapstructure:"name" yaml:"name" json:"name"`
-	Version           string                   `mapstructure:"version" yaml:"version" json:"version"`
-	SwaggerPaths      []string                 `mapstructure:"swagger_paths" yaml:"swaggerPaths" json:"swaggerPaths"`
-	SwaggerURLs       []string                 `mapstructure:"swagger_urls" yaml:"swaggerUrls" json:"swaggerUrls"`
-	PackageIDs        []string                 `mapstructure:"package_ids" yaml:"packageIds" json:"packageIds"`
-	TWCFilters        *TWCFilters              `mapstructure:"twc_filters" yaml:"twcFilters" json:"twcFilters"`
-	DynamicFilters    map[string]interface{}   `mapstructure:"dynamic_filters" yaml:"dynamicFilters" json:"dynamicFilters"`
-	Server            *ServerConfig            `mapstructure:"server" yaml:"server" json:"server"`
-	HTTP              *HTTPConfig              `mapstructure:"http" yaml:"http" json:"http"`
-	Auth              *AuthConfig              `mapstructure:"auth" yaml:"auth" json:"auth"`
-	Debug             bool                     `mapstructure:"debug" yaml:"debug" json:"debug"`
-	Logging           *LoggingConfig           `mapstructure:"logging" yaml:"logging" json:"logging"`
-	ToolGeneration    *ToolGenerationConfig    `mapstructure:"tool_generation" yaml:"toolGeneration" json:"toolGeneration"`
-	SwaggerProcessing *SwaggerProcessingConfig `mapstructure:"swagger_processing" yaml:"swaggerProcessing" json:"swaggerProcessing"`
-	Prompts           *PromptsConfig           `mapstructure:"prompts" yaml:"prompts" json:"prompts"`
-	Resources         *ResourcesConfig         `mapstructure:"resources" yaml:"resources" json:"resources"`
+	Name               string                        `mapstructure:"name" yaml:"name" json:"name"`
+	Version            string                        `mapstructure:"version" yaml:"version" json:"version"`
+	SwaggerPaths       []string                      `mapstructure:"swagger_paths" yaml:"swaggerPaths" json:"swaggerPaths"`
+	SwaggerURLs        []string                      `mapstructure:"swagger_urls" yaml:"swaggerUrls" json:"swaggerUrls"`
+	PackageIDs         []string                      `mapstructure:"package_ids" yaml:"packageIds" json:"packageIds"`
+	TWCFilters         *TWCFilters                   `mapstructure:"twc_filters" yaml:"twcFilters" json:"twcFilters"`
+	DynamicFilters     map[string]interface{}        `mapstructure:"dynamic_filters" yaml:"dynamicFilters" json:"dynamicFilters"`
+	Server             *ServerConfig                 `mapstructure:"server" yaml:"server" json:"server"`
+	HTTP               *HTTPConfig                   `mapstructure:"http" yaml:"http" json:"http"`
+	Auth               *AuthConfig                   `mapstructure:"auth" yaml:"auth" json:"auth"`
+	Debug              bool                          `mapstructure:"debug" yaml:"debug" json:"debug"`
+	Logging            *LoggingConfig                `mapstructure:"logging" yaml:"logging" json:"logging"`
+	ToolGeneration     *ToolGenerationConfig         `mapstructure:"tool_generation" yaml:"toolGeneration" json:"toolGeneration"`
+	SwaggerProcessing  *SwaggerProcessingConfig      `mapstructure:"swagger_processing" yaml:"swaggerProcessing" json:"swaggerProcessing"`
+	Prompts            *PromptsConfig                `mapstructure:"prompts" yaml:"prompts" json:"prompts"`
+	Resources          *ResourcesConfig              `mapstructure:"resources" yaml:"resources" json:"resources"`
+	GraphQL            *GraphQLConfig                `mapstructure:"graphql" yaml:"graphql" json:"graphql"`
+	GRPC               *GRPCConfig                   `mapstructure:"grpc" yaml:"grpc" json:"grpc"`
+	Mock               *MockConfig                   `mapstructure:"mock" yaml:"mock" json:"mock"`
+	Cassette           *CassetteConfig               `mapstructure:"cassette" yaml:"cassette" json:"cassette"`
+	ResponseValidation *ResponseValidationConfig     `mapstructure:"response_validation" yaml:"responseValidation" json:"responseValidation"`
+	ResponseFilters    map[string]string             `mapstructure:"response_filters" yaml:"responseFilters" json:"responseFilters"`
+	Pagination         *PaginationConfig             `mapstructure:"pagination" yaml:"pagination" json:"pagination"`
+	ToolExecution      *ToolExecutionConfig          `mapstructure:"tool_execution" yaml:"toolExecution" json:"toolExecution"`
+	Telemetry          *TelemetryConfig              `mapstructure:"telemetry" yaml:"telemetry" json:"telemetry"`
+	Update             *UpdateConfig                 `mapstructure:"update" yaml:"update" json:"update"`
+	Plugins            []PluginConfig                `mapstructure:"plugins" yaml:"plugins" json:"plugins"`
+	CompositeTools     []CompositeToolConfig         `mapstructure:"composite_tools" yaml:"compositeTools" json:"compositeTools"`
+	ToolOverrides      map[string]ToolOverrideConfig `mapstructure:"tool_overrides" yaml:"toolOverrides" json:"toolOverrides"`
+	BaseURLs           map[string]string             `mapstructure:"base_urls" yaml:"baseUrls" json:"baseUrls"`
+	// Profiles are named overrides of the swagger sources, auth, and base URLs, one of
+	// which is applied over the rest of the file when selected by --profile or
+	// WX_MCP_PROFILE
+	Profiles map[string]ConfigProfile `mapstructure:"profiles" yaml:"profiles" json:"profiles"`
+}
+
+// ConfigProfile is a named set of overrides in a config file, e.g. for a dev, staging,
+// or prod environment. Its fields replace the file's fields of the same name.
+type ConfigProfile struct {
+	SwaggerPaths []string          `mapstructure:"swagger_paths" yaml:"swaggerPaths" json:"swaggerPaths"`
+	SwaggerURLs  []string          `mapstructure:"swagger_urls" yaml:"swaggerUrls" json:"swaggerUrls"`
+	Auth         *AuthConfig       `mapstructure:"auth" yaml:"auth" json:"auth"`
+	BaseURLs     map[string]string `mapstructure:"base_urls" yaml:"baseUrls" json:"baseUrls"`
 }
 
 // ResolvedConfig represents the final merged configuration
 type ResolvedConfig struct {
-	Name              string                  `json:"name"`
-	Version           string                  `json:"version"`
-	SwaggerPaths      []string                `json:"swaggerPaths"`
-	SwaggerURLs       []string                `json:"swaggerUrls,omitempty"`
-	PackageIDs        []string                `json:"packageIds,omitempty"`
-	TWCFilters        *TWCFilters             `json:"twcFilters,omitempty"`
-	DynamicFilters    map[string]interface{}  `json:"dynamicFilters,omitempty"`
-	Server            ServerConfig            `json:"server"`
-	HTTP              HTTPConfig              `json:"http"`
-	Auth              AuthConfig              `json:"auth"`
-	Debug             bool                    `json:"debug"`
-	Logging           LoggingConfig           `json:"logging"`
-	ToolGeneration    ToolGenerationConfig    `json:"toolGeneration"`
-	SwaggerProcessing SwaggerProcessingConfig `json:"swaggerProcessing"`
-	Prompts           PromptsConfig           `json:"prompts"`
-	Resources         ResourcesConfig         `json:"resources"`
+	Name               string                   `json:"name"`
+	Version            string                   `json:"version"`
+	SwaggerPaths       []string                 `json:"swaggerPaths"`
+	SwaggerURLs        []string                 `json:"swaggerUrls,omitempty"`
+	PackageIDs         []string                 `json:"packageIds,omitempty"`
+	TWCFilters         *TWCFilters              `json:"twcFilters,omitempty"`
+	DynamicFilters     map[string]interface{}   `json:"dynamicFilters,omitempty"`
+	Server             ServerConfig             `json:"server"`
+	HTTP               HTTPConfig               `json:"http"`
+	Auth               AuthConfig               `json:"auth"`
+	Debug              bool                     `json:"debug"`
+	Logging            LoggingConfig            `json:"logging"`
+	ToolGeneration     ToolGenerationConfig     `json:"toolGeneration"`
+	SwaggerProcessing  SwaggerProcessingConfig  `json:"swaggerProcessing"`
+	Prompts            PromptsConfig            `json:"prompts"`
+	Resources          ResourcesConfig          `json:"resources"`
+	GraphQL            GraphQLConfig            `json:"graphql"`
+	GRPC               GRPCConfig               `json:"grpc"`
+	Mock               MockConfig               `json:"mock"`
+	Cassette           CassetteConfig           `json:"cassette"`
+	ResponseValidation ResponseValidationConfig `json:"responseValidation"`
+	// ResponseFilters maps tool names to JMESPath expressions applied to their JSON
+	// responses when a call passes no _filter argument
+	ResponseFilters map[string]string     `json:"responseFilters,omitempty"`
+	Pagination      PaginationConfig      `json:"pagination"`
+	ToolExecution   ToolExecutionConfig   `json:"toolExecution"`
+	Telemetry       TelemetryConfig       `json:"telemetry"`
+	Update          UpdateConfig          `json:"update"`
+	Plugins         []PluginConfig        `json:"plugins,omitempty"`
+	CompositeTools  []CompositeToolConfig `json:"compositeTools,omitempty"`
+	// ToolOverrides maps tool names to their timeout and retry overrides
+	ToolOverrides map[string]ToolOverrideConfig `json:"toolOverrides,omitempty"`
+	// BaseURLs maps the hosts of server URLs, as "api.example.com",
+	// "api.example.com:8443", or "*.example.com", to base URLs replacing their scheme
+	// and host, ahead of their path, e.g. to send requests to a staging host
+	BaseURLs map[string]string `json:"baseUrls,omitempty"`
+	// Profile is the config file profile applied, if any
+	Profile string `json:"profile,omitempty"`
+	// ConfigChecksums verify remote config files, as "sha256:<hex>" digests or URLs of
+	// checksum files
+	ConfigChecksums []string `json:"configChecksums,omitempty"`
+	// ConfigRefresh is how often remote config files are fetched again to restart the
+	// server when they change, or 0 to load them once
+	ConfigRefresh time.Duration `json:"configRefresh,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
@@ -138,6 +884,24 @@ func DefaultConfig() *ResolvedConfig {
 			Port:     8080,
 			Timeout:  30 * time.Second,
 			MaxTools: 1000,
+			Events: EventsConfig{
+				Log: EventLogConfig{
+					MaxAge:    24 * time.Hour,
+					MaxEvents: 10000,
+				},
+			},
+			Blobs: BlobsConfig{
+				MaxInlineSize: 1 << 20,
+				TTL:           15 * time.Minute,
+				MaxStoredSize: 256 << 20,
+			},
+			DocsAssetsURL:  "https://cdn.jsdelivr.net/npm",
+			SocketMode:     "0600",
+			MaxMessageSize: 16 << 20,
+			SharedRegistry: SharedRegistryConfig{
+				Key:          "swagger-docs-mcp",
+				SyncInterval: 10 * time.Second,
+			},
 		},
 		HTTP: HTTPConfig{
 			Timeout:   10 * time.Second,
@@ -156,10 +920,11 @@ func DefaultConfig() *ResolvedConfig {
 			UseOperationID:       true,
 			IgnoreFormats:        []string{},
 			PreferFormat:         "",
+			UnsupportedAuth:      UnsupportedAuthInclude,
 		},
 		SwaggerProcessing: SwaggerProcessingConfig{
 			ValidateDocuments: false,
-			ResolveReferences: false,
+			ResolveReferences: true,
 			IgnoreErrors:      true,
 		},
 		Prompts: PromptsConfig{
@@ -177,12 +942,24 @@ func DefaultConfig() *ResolvedConfig {
 				"analysis",
 				"comparison",
 			},
+			ConflictPolicy: PromptConflictError,
 		},
 		Resources: ResourcesConfig{
 			Enabled:                   true,
 			ExposeSwaggerDocs:         true,
 			EnableDocumentationSearch: true,
 			AllowEndpointDiscovery:    true,
+			URIScheme:                 "swagger",
+			DocumentNaming:            ResourceNamingFileName,
+		},
+		Cassette: CassetteConfig{
+			Mode: CassetteModeAuto,
+		},
+		Pagination: PaginationConfig{
+			MaxPages: 10,
+		},
+		Telemetry: TelemetryConfig{
+			Mode: TelemetryOff,
 		},
 	}
 }
@@ -0,0 +1,55 @@
+package types
+
+// GraphQLSchema represents a GraphQL schema loaded from SDL or an introspection result
+type GraphQLSchema struct {
+	QueryType    string                  `json:"queryType,omitempty"`
+	MutationType string                  `json:"mutationType,omitempty"`
+	Types        map[string]*GraphQLType `json:"types"`
+}
+
+// GraphQLType represents a named GraphQL type
+type GraphQLType struct {
+	Kind          string              `json:"kind"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description,omitempty"`
+	Fields        []GraphQLField      `json:"fields,omitempty"`
+	InputFields   []GraphQLInputValue `json:"inputFields,omitempty"`
+	EnumValues    []string            `json:"enumValues,omitempty"`
+	PossibleTypes []string            `json:"possibleTypes,omitempty"`
+}
+
+// GraphQLField represents a field of an object or interface type
+type GraphQLField struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Args        []GraphQLInputValue `json:"args,omitempty"`
+	Type        *GraphQLTypeRef     `json:"type"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+}
+
+// GraphQLInputValue represents a field argument or input object field
+type GraphQLInputValue struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	Type         *GraphQLTypeRef `json:"type"`
+	DefaultValue string          `json:"defaultValue,omitempty"`
+}
+
+// GraphQLTypeRef references a type, wrapped in NON_NULL and LIST modifiers
+type GraphQLTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name,omitempty"`
+	OfType *GraphQLTypeRef `json:"ofType,omitempty"`
+}
+
+// GraphQL type kinds, matching the introspection __TypeKind enum
+const (
+	GraphQLKindScalar      = "SCALAR"
+	GraphQLKindObject      = "OBJECT"
+	GraphQLKindInterface   = "INTERFACE"
+	GraphQLKindUnion       = "UNION"
+	GraphQLKindEnum        = "ENUM"
+	GraphQLKindInputObject = "INPUT_OBJECT"
+	GraphQLKindList        = "LIST"
+	GraphQLKindNonNull     = "NON_NULL"
+)
@@ -0,0 +1,32 @@
+package types
+
+// GRPCSchema represents the gRPC services loaded from a protobuf descriptor set
+type GRPCSchema struct {
+	Services []GRPCService `json:"services"`
+}
+
+// GRPCService represents a protobuf service definition
+type GRPCService struct {
+	Name        string       `json:"name"`
+	FullName    string       `json:"fullName"`
+	Description string       `json:"description,omitempty"`
+	Methods     []GRPCMethod `json:"methods"`
+}
+
+// GRPCMethod represents a service method and how it is transcoded to HTTP/JSON.
+// HTTPMethod, HTTPPath, and Body follow google.api.http annotation semantics.
+type GRPCMethod struct {
+	Name            string                 `json:"name"`
+	FullName        string                 `json:"fullName"`
+	Description     string                 `json:"description,omitempty"`
+	InputType       string                 `json:"inputType"`
+	OutputType      string                 `json:"outputType"`
+	HTTPMethod      string                 `json:"httpMethod"`
+	HTTPPath        string                 `json:"httpPath"`
+	Body            string                 `json:"body,omitempty"`
+	ClientStreaming bool                   `json:"clientStreaming,omitempty"`
+	ServerStreaming bool                   `json:"serverStreaming,omitempty"`
+	Deprecated      bool                   `json:"deprecated,omitempty"`
+	InputSchema     map[string]interface{} `json:"inputSchema,omitempty"`
+	OutputSchema    map[string]interface{} `json:"outputSchema,omitempty"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // MCP protocol types for Model Context Protocol
 
 // MCPRequest represents a generic MCP request
@@ -57,6 +59,8 @@ type MCPContent struct {
 	Text     string `json:"text,omitempty"`
 	Data     string `json:"data,omitempty"`
 	MimeType string `json:"mimeType,omitempty"`
+	// Resource is the embedded resource of content of type "resource"
+	Resource *MCPResourceContent `json:"resource,omitempty"`
 }
 
 // MCPPrompt represents an MCP prompt
@@ -91,10 +95,11 @@ type MCPResourceTemplate struct {
 
 // MCPCapabilities represents MCP server capabilities
 type MCPCapabilities struct {
-	Tools     *MCPToolsCapability     `json:"tools,omitempty"`
-	Prompts   *MCPPromptsCapability   `json:"prompts,omitempty"`
-	Resources *MCPResourcesCapability `json:"resources,omitempty"`
-	Logging   *MCPLoggingCapability   `json:"logging,omitempty"`
+	Tools       *MCPToolsCapability       `json:"tools,omitempty"`
+	Prompts     *MCPPromptsCapability     `json:"prompts,omitempty"`
+	Resources   *MCPResourcesCapability   `json:"resources,omitempty"`
+	Logging     *MCPLoggingCapability     `json:"logging,omitempty"`
+	Completions *MCPCompletionsCapability `json:"completions,omitempty"`
 }
 
 // MCPToolsCapability represents tools capability
@@ -116,6 +121,56 @@ type MCPResourcesCapability struct {
 // MCPLoggingCapability represents logging capability
 type MCPLoggingCapability struct{}
 
+// MCPCompletionsCapability represents argument completion capability
+type MCPCompletionsCapability struct{}
+
+// MCPCompleteParams represents the parameters of a completion/complete request
+type MCPCompleteParams struct {
+	Ref      MCPCompletionReference `json:"ref"`
+	Argument MCPCompletionArgument  `json:"argument"`
+}
+
+// MCPCompletionReference identifies what is being completed. Type is "ref/prompt"
+// or "ref/resource" per the MCP specification, or "ref/tool" for tool arguments.
+type MCPCompletionReference struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// MCPCompletionArgument represents the argument being completed and its partial value
+type MCPCompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// MCPCompleteResult represents the result of a completion/complete request
+type MCPCompleteResult struct {
+	Completion MCPCompletion `json:"completion"`
+}
+
+// MCPCompletion represents completion suggestions
+type MCPCompletion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// MCPSetLevelParams represents the parameters of a logging/setLevel request
+type MCPSetLevelParams struct {
+	Level string `json:"level"`
+	// EgressSampleRate, an extension of the MCP parameters, changes the fraction of
+	// upstream requests logged in full
+	EgressSampleRate *float64 `json:"egressSampleRate,omitempty"`
+}
+
+// MCPLogMessageParams represents the parameters of a notifications/message notification
+type MCPLogMessageParams struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
 // MCPInitializeParams represents initialization parameters
 type MCPInitializeParams struct {
 	ProtocolVersion string          `json:"protocolVersion"`
@@ -153,13 +208,21 @@ type MCPCallToolParams struct {
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
 }
 
+// MCPCancelledParams represents the parameters of a notifications/cancelled notification
+type MCPCancelledParams struct {
+	RequestID json.RawMessage `json:"requestId"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
 // MCPCallToolResult represents the result of calling a tool
 type MCPCallToolResult struct {
-	Content []MCPContent `json:"content"`
-	IsError bool         `json:"isError,omitempty"`
+	Content []MCPContent           `json:"content"`
+	IsError bool                   `json:"isError,omitempty"`
+	Meta    map[string]interface{} `json:"_meta,omitempty"`
 }
 
-// WeatherPromptCategory represents weather prompt categories
+// WeatherPromptCategory represents prompt categories. The constants are the built-in
+// weather categories; prompts.categoryDefinitions names others.
 type WeatherPromptCategory string
 
 const (
@@ -181,6 +244,28 @@ type GeneratedTool struct {
 	InputSchema  map[string]interface{} `json:"inputSchema"`
 	Endpoint     *SwaggerEndpoint       `json:"endpoint"`
 	DocumentInfo *SwaggerDocumentInfo   `json:"documentInfo"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	// Composite is the definition of a configured tool chaining other tools; its
+	// Endpoint and DocumentInfo are nil
+	Composite *CompositeToolConfig `json:"composite,omitempty"`
+	// LoadInputSchema generates the input schema of a tool generated with lazy
+	// schemas, whose InputSchema is nil; Schema returns the schema of any tool
+	LoadInputSchema func() map[string]interface{} `json:"-"`
+	// SchemaHash identifies the source a lazy input schema is generated from, its
+	// endpoint and document components, so that tools can be compared without
+	// generating their schemas
+	SchemaHash string `json:"schemaHash,omitempty"`
+	// SchemaRoot holds the document components a lazy input schema resolves its
+	// references against, shared by the tools of a document
+	SchemaRoot map[string]interface{} `json:"-"`
+}
+
+// Schema returns the input schema of the tool, generating it if it is lazy
+func (t *GeneratedTool) Schema() map[string]interface{} {
+	if t.InputSchema == nil && t.LoadInputSchema != nil {
+		return t.LoadInputSchema()
+	}
+	return t.InputSchema
 }
 
 // GeneratedPrompt represents a prompt generated from Swagger documentation
@@ -193,6 +278,33 @@ type GeneratedPrompt struct {
 	Examples    []PromptExample          `json:"examples,omitempty"`
 	Tags        []string                 `json:"tags,omitempty"`
 	Source      *SwaggerDocumentInfo     `json:"source,omitempty"`
+	// Messages, when set, render the prompt as several messages instead of Template
+	Messages []PromptMessageTemplate `json:"messages,omitempty"`
+}
+
+// PromptMessageTemplate is one message of a multi-message prompt: text, or a resource
+// embedded by URI, with {{argument}} placeholders in either
+type PromptMessageTemplate struct {
+	// Role is user, assistant, or system, and defaults to user
+	Role     string `json:"role,omitempty" yaml:"role,omitempty" mapstructure:"role"`
+	Text     string `json:"text,omitempty" yaml:"text,omitempty" mapstructure:"text"`
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty" mapstructure:"resource"`
+}
+
+// PromptDefinition is a prompt defined for a document by its x-mcp-prompts extension or
+// by prompts.documentPrompts. It replaces the generated prompt with the same name, and
+// a version registers it under a pinned name as well.
+type PromptDefinition struct {
+	Name        string              `json:"name" yaml:"name" mapstructure:"name"`
+	Version     string              `json:"version,omitempty" yaml:"version,omitempty" mapstructure:"version"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description"`
+	Category    string              `json:"category,omitempty" yaml:"category,omitempty" mapstructure:"category"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty" yaml:"arguments,omitempty" mapstructure:"arguments"`
+	Template    string              `json:"template,omitempty" yaml:"template,omitempty" mapstructure:"template"`
+	Examples    []PromptExample     `json:"examples,omitempty" yaml:"examples,omitempty" mapstructure:"examples"`
+	Tags        []string            `json:"tags,omitempty" yaml:"tags,omitempty" mapstructure:"tags"`
+	// Messages replace Template with several messages
+	Messages []PromptMessageTemplate `json:"messages,omitempty" yaml:"messages,omitempty" mapstructure:"messages"`
 }
 
 // PromptExample represents a prompt usage example
@@ -219,10 +331,12 @@ type ResourceCategory string
 
 const (
 	ResourceCategoryDocumentation ResourceCategory = "documentation"
-	ResourceCategorySchema       ResourceCategory = "schema"
-	ResourceCategoryExample      ResourceCategory = "example"
-	ResourceCategoryReference    ResourceCategory = "reference"
-	ResourceCategoryEndpoint     ResourceCategory = "endpoint"
+	ResourceCategorySchema        ResourceCategory = "schema"
+	ResourceCategoryExample       ResourceCategory = "example"
+	ResourceCategoryReference     ResourceCategory = "reference"
+	ResourceCategoryEndpoint      ResourceCategory = "endpoint"
+	ResourceCategoryCallback      ResourceCategory = "callback"
+	ResourceCategoryChannel       ResourceCategory = "channel"
 )
 
 // MCPPromptGetParams represents parameters for getting a prompt
@@ -243,10 +357,11 @@ type MCPPromptMessage struct {
 	Content MCPPromptContent `json:"content"`
 }
 
-// MCPPromptContent represents content in a prompt message
+// MCPPromptContent represents content in a prompt message: text, or an embedded resource
 type MCPPromptContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type     string              `json:"type"`
+	Text     string              `json:"text,omitempty"`
+	Resource *MCPResourceContent `json:"resource,omitempty"`
 }
 
 // MCPListPromptsResult represents the result of listing prompts
@@ -259,11 +374,26 @@ type MCPListResourcesResult struct {
 	Resources []MCPResource `json:"resources"`
 }
 
+// MCPListResourceTemplatesResult represents the result of listing resource templates
+type MCPListResourceTemplatesResult struct {
+	ResourceTemplates []MCPResourceTemplate `json:"resourceTemplates"`
+}
+
 // MCPReadResourceParams represents parameters for reading a resource
 type MCPReadResourceParams struct {
 	URI string `json:"uri"`
 }
 
+// MCPSubscribeParams represents parameters for subscribing to or unsubscribing from a resource
+type MCPSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// MCPResourceUpdatedParams represents parameters of the notifications/resources/updated notification
+type MCPResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
 // MCPReadResourceResult represents the result of reading a resource
 type MCPReadResourceResult struct {
 	Contents []MCPResourceContent `json:"contents"`
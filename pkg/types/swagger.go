@@ -6,13 +6,22 @@ import "time"
 type SwaggerDocument struct {
 	OpenAPI      string                 `json:"openapi,omitempty" yaml:"openapi,omitempty"`
 	Swagger      string                 `json:"swagger,omitempty" yaml:"swagger,omitempty"`
+	AsyncAPI     string                 `json:"asyncapi,omitempty" yaml:"asyncapi,omitempty"`
 	Info         *SwaggerInfo           `json:"info,omitempty" yaml:"info,omitempty"`
 	Servers      []SwaggerServer        `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Paths        map[string]interface{} `json:"paths,omitempty" yaml:"paths,omitempty"`
 	Components   interface{}            `json:"components,omitempty" yaml:"components,omitempty"`
+	Definitions  interface{}            `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	Webhooks     map[string]interface{} `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	Channels     map[string]interface{} `json:"channels,omitempty" yaml:"channels,omitempty"`
+	Operations   map[string]interface{} `json:"operations,omitempty" yaml:"operations,omitempty"`
 	Security     []interface{}          `json:"security,omitempty" yaml:"security,omitempty"`
 	Tags         []interface{}          `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs interface{}            `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	// Produces lists the response media types of all operations of a Swagger 2.0 document
+	Produces []string `json:"produces,omitempty" yaml:"produces,omitempty"`
+	// SecurityDefinitions holds the security schemes of a Swagger 2.0 document by name
+	SecurityDefinitions map[string]interface{} `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
 
 	// Extension fields - use interface{} to handle both strings and arrays
 	XSolaraPackageIDs       interface{} `json:"x-package-ids,omitempty" yaml:"x-package-ids,omitempty"`
@@ -20,6 +29,18 @@ type SwaggerDocument struct {
 	XTwcDomain              interface{} `json:"x-twc-domain,omitempty" yaml:"x-twc-domain,omitempty"`
 	XTwcUsageClassification interface{} `json:"x-twc-usage-classification,omitempty" yaml:"x-twc-usage-classification,omitempty"`
 	XTwcGeography           interface{} `json:"x-twc-geography,omitempty" yaml:"x-twc-geography,omitempty"`
+	XMCPPreferFormat        string      `json:"x-mcp-prefer-format,omitempty" yaml:"x-mcp-prefer-format,omitempty"`
+	// XMCPPrompts replaces and extends the prompts generated for the document
+	XMCPPrompts []PromptDefinition `json:"x-mcp-prompts,omitempty" yaml:"x-mcp-prompts,omitempty"`
+
+	// GraphQL holds the schema of GraphQL sources (SDL or introspection results)
+	GraphQL *GraphQLSchema `json:"graphql,omitempty" yaml:"-"`
+
+	// GRPC holds the services of protobuf descriptor set sources
+	GRPC *GRPCSchema `json:"grpc,omitempty" yaml:"-"`
+
+	// AsyncAPIServers holds AsyncAPI servers, which are keyed by name rather than listed
+	AsyncAPIServers map[string]interface{} `json:"-" yaml:"-"`
 }
 
 // SwaggerInfo represents swagger info section
@@ -37,6 +58,7 @@ type SwaggerServer struct {
 	URL         string                 `json:"url" yaml:"url"`
 	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	Variables   map[string]interface{} `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Protocol    string                 `json:"protocol,omitempty" yaml:"protocol,omitempty"`
 }
 
 // SwaggerEndpoint represents a swagger endpoint
@@ -53,16 +75,86 @@ type SwaggerEndpoint struct {
 	Security    []interface{}          `json:"security,omitempty"`
 	Deprecated  bool                   `json:"deprecated,omitempty"`
 	MCPToolName string                 `json:"x-mcp-tool-name,omitempty"`
+	Servers     []SwaggerServer        `json:"servers,omitempty"`
+	Callbacks   []SwaggerCallback      `json:"callbacks,omitempty"`
+	Extensions  map[string]interface{} `json:"extensions,omitempty"`
+	Protocol    string                 `json:"protocol,omitempty"`
+	Messages    []interface{}          `json:"messages,omitempty"`
+	Query       string                 `json:"query,omitempty"`
+	// Produces lists the media types the endpoint can respond with
+	Produces []string `json:"produces,omitempty"`
+	// Pagination describes how the endpoint pages its results, if it does
+	Pagination *EndpointPagination `json:"pagination,omitempty"`
+	// SecuritySchemes holds the security schemes of the endpoint's document by name, as
+	// referenced by Security
+	SecuritySchemes map[string]interface{} `json:"-"`
+}
+
+// EndpointPagination describes how an endpoint pages its results
+type EndpointPagination struct {
+	Style string `json:"style"`
+	// Param is the query parameter selecting the page: its number, its offset, or the
+	// cursor of a cursor-paged endpoint. Endpoints paged with next links have none.
+	Param string `json:"param,omitempty"`
+	// SizeParam is the query parameter limiting the results per page, if any
+	SizeParam string `json:"sizeParam,omitempty"`
 }
 
+// Pagination styles of endpoints
+const (
+	PaginationStylePage   = "page"
+	PaginationStyleOffset = "offset"
+	PaginationStyleCursor = "cursor"
+	PaginationStyleLink   = "link"
+)
+
+// Endpoint protocols; an empty protocol means a plain HTTP operation
+const (
+	EndpointProtocolAsyncAPI = "asyncapi"
+	EndpointProtocolGraphQL  = "graphql"
+	EndpointProtocolGRPC     = "grpc"
+)
+
+// Document specifications recognized during scanning
+const (
+	SpecificationOpenAPI  = "openapi"
+	SpecificationSwagger  = "swagger"
+	SpecificationAsyncAPI = "asyncapi"
+	SpecificationGraphQL  = "graphql"
+	SpecificationGRPC     = "grpc"
+	SpecificationHAR      = "har"
+)
+
+// SwaggerCallback represents an out-of-band request the API pushes to the client,
+// declared either as an operation callback or as a top-level webhook
+type SwaggerCallback struct {
+	Name        string                 `json:"name"`
+	Kind        string                 `json:"kind"`
+	Expression  string                 `json:"expression,omitempty"`
+	Method      string                 `json:"method"`
+	OperationID string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	RequestBody interface{}            `json:"requestBody,omitempty"`
+	Responses   map[string]interface{} `json:"responses,omitempty"`
+	Parent      string                 `json:"parent,omitempty"`
+}
+
+// Callback kinds
+const (
+	CallbackKindCallback = "callback"
+	CallbackKindWebhook  = "webhook"
+)
+
 // SwaggerParameter represents a swagger parameter
 type SwaggerParameter struct {
-	Name        string      `json:"name"`
-	In          string      `json:"in"`
-	Description string      `json:"description,omitempty"`
-	Required    bool        `json:"required,omitempty"`
-	Schema      interface{} `json:"schema,omitempty"`
-	Example     interface{} `json:"example,omitempty"`
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Schema      interface{}   `json:"schema,omitempty"`
+	Example     interface{}   `json:"example,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`
 }
 
 // SwaggerDocumentInfo represents metadata about a swagger document
@@ -72,13 +164,16 @@ type SwaggerDocumentInfo struct {
 	Title                  string            `json:"title"`
 	Endpoints              []SwaggerEndpoint `json:"endpoints"`
 	IsRemote               bool              `json:"isRemote,omitempty"`
+	Specification          string            `json:"specification,omitempty"`
 	PackageIDs             []string          `json:"packageIds,omitempty"`
 	TwcDomainPortfolio     []string          `json:"twcDomainPortfolio,omitempty"`
 	TwcDomain              []string          `json:"twcDomain,omitempty"`
 	TwcUsageClassification []string          `json:"twcUsageClassification,omitempty"`
 	TwcGeography           []string          `json:"twcGeography,omitempty"`
 	LastModified           *time.Time        `json:"lastModified,omitempty"`
-	Content                []byte            `json:"-"` // Store fetched content for remote docs
+	Content                []byte            `json:"-"` // Raw content the document was parsed from, when kept
+	// PreferFormat is the format preferred by the document's x-mcp-prefer-format extension
+	PreferFormat string `json:"preferFormat,omitempty"`
 }
 
 // ScanOptions represents options for scanning swagger documents
@@ -93,6 +188,17 @@ type ScanResult struct {
 	Documents []SwaggerDocumentInfo `json:"documents"`
 	Errors    []ScanError           `json:"errors"`
 	Stats     ScanStats             `json:"stats"`
+	// Sources are the outcomes of the scanned paths and URLs, in the order scanned
+	Sources []SourceScan `json:"sources"`
+}
+
+// SourceScan is the outcome of scanning one swagger path or URL
+type SourceScan struct {
+	Source   string `json:"source"`
+	IsRemote bool   `json:"isRemote"`
+	// Documents are the file paths or URLs of the documents found
+	Documents []string    `json:"documents"`
+	Errors    []ScanError `json:"errors,omitempty"`
 }
 
 // ScanError represents an error that occurred during scanning
@@ -113,7 +219,7 @@ type ScanStats struct {
 func DefaultScanOptions() *ScanOptions {
 	return &ScanOptions{
 		IncludeSubdirectories: true,
-		SupportedExtensions:   []string{".json", ".yaml", ".yml"},
+		SupportedExtensions:   []string{".json", ".yaml", ".yml", ".graphql", ".graphqls", ".gql", ".pb", ".protoset", ".desc", ".har"},
 		MaxDepth:              3,
 	}
 }
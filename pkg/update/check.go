@@ -0,0 +1,28 @@
+package update
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"swagger-docs-mcp/pkg/utils"
+	"swagger-docs-mcp/pkg/version"
+)
+
+// LogNewerRelease looks up the latest release and logs a notice when it is newer than
+// the running version. Failures are only logged at debug level, so that servers
+// without access to GitHub start as quietly as before.
+func LogNewerRelease(ctx context.Context, client *Client, logger *utils.Logger) {
+	release, err := client.Latest(ctx)
+	if err != nil {
+		logger.Debug("Failed to check for a newer version", zap.Error(err))
+		return
+	}
+
+	current := version.GetSemanticVersion()
+	if Newer(release.Version(), current) {
+		logger.Info("A newer version is available; run swagger-docs-mcp self-update to install it",
+			zap.String("currentVersion", current),
+			zap.String("latestVersion", release.Version()),
+			zap.String("releaseUrl", release.HTMLURL))
+	}
+}
@@ -0,0 +1,93 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ParseChecksum returns the SHA-256 checksum of the file named name from the content
+// of a checksum file: either the checksum alone, or lines of a checksum and a file
+// name as written by sha256sum
+func ParseChecksum(content, name string) (string, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 1 && isSHA256(fields[0]) {
+		return strings.ToLower(fields[0]), nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !isSHA256(fields[0]) {
+			continue
+		}
+		// sha256sum marks files read in binary mode with a *
+		listed := strings.TrimPrefix(fields[len(fields)-1], "*")
+		if filepath.Base(listed) == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no SHA-256 checksum listed for %s", name)
+}
+
+// Verify checks data against a hex-encoded SHA-256 checksum
+func Verify(data []byte, checksum string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(checksum)) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", strings.ToLower(strings.TrimSpace(checksum)), actual)
+	}
+	return nil
+}
+
+// Replace replaces the binary at executable with data, keeping its file mode. The new
+// binary is written next to it and renamed over it, so that the binary is never left
+// half written. On Windows, whose running binaries cannot be replaced, the old binary
+// is moved aside to executable.old first.
+func Replace(executable string, data []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", executable, err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(executable), "."+filepath.Base(executable)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary next to %s: %w", executable, err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to make the new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", executable, err)
+		}
+	}
+	if err := os.Rename(temp.Name(), executable); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	return nil
+}
+
+// isSHA256 reports whether s is a hex-encoded SHA-256 checksum
+func isSHA256(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
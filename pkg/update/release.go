@@ -0,0 +1,204 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"swagger-docs-mcp/pkg/version"
+)
+
+const (
+	// DefaultRepository is the GitHub repository, owner/name, releases are looked up in
+	DefaultRepository = "wpsmith/wx-mcp-go"
+	// DefaultAPIURL is the GitHub API releases are read from
+	DefaultAPIURL = "https://api.github.com"
+	// BinaryName prefixes the names of the release binaries, as built by make build-all
+	BinaryName = "swagger-docs-mcp"
+
+	// requestTimeout bounds the release lookups
+	requestTimeout = 30 * time.Second
+	// downloadTimeout bounds the download of a release binary
+	downloadTimeout = 10 * time.Minute
+	// maxDownloadSize bounds the size of a downloaded release binary or checksum file
+	maxDownloadSize = 512 << 20
+)
+
+// checksumAssets are the names of the release assets listing the SHA-256 checksums of
+// all binaries, in the format of sha256sum, in order of preference
+var checksumAssets = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+// Release is a GitHub release of the server
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Name    string  `json:"name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Version returns the version of the release, its tag without a v prefix
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Asset returns the asset of the release with the given name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// AssetName returns the name of the release binary for an operating system and
+// architecture, e.g. swagger-docs-mcp-linux-amd64
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("%s-%s-%s", BinaryName, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// CurrentAssetName returns the name of the release binary for this platform
+func CurrentAssetName() string {
+	return AssetName(runtime.GOOS, runtime.GOARCH)
+}
+
+// Client reads the releases of a GitHub repository. GITHUB_TOKEN, when set,
+// authenticates the requests, raising GitHub's rate limit.
+type Client struct {
+	repository string
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the releases of repository, owner/name, read from the
+// GitHub API at apiURL, e.g. that of GitHub Enterprise. Empty values take the defaults.
+func NewClient(repository, apiURL string) *Client {
+	if repository == "" {
+		repository = DefaultRepository
+	}
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		repository: repository,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      os.Getenv("GITHUB_TOKEN"),
+		httpClient: &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// Repository returns the repository releases are looked up in
+func (c *Client) Repository() string {
+	return c.repository
+}
+
+// Latest returns the latest release, leaving out drafts and prereleases
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	return c.release(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", c.apiURL, c.repository))
+}
+
+// Release returns the release with the given tag, with or without its v prefix
+func (c *Client) Release(ctx context.Context, tag string) (*Release, error) {
+	release, err := c.release(ctx, fmt.Sprintf("%s/repos/%s/releases/tags/%s", c.apiURL, c.repository, url.PathEscape(tag)))
+	if err != nil && !strings.HasPrefix(tag, "v") {
+		if prefixed, prefixedErr := c.Release(ctx, "v"+tag); prefixedErr == nil {
+			return prefixed, nil
+		}
+	}
+	return release, err
+}
+
+// release reads a release from the GitHub API
+func (c *Client) release(ctx context.Context, releaseURL string) (*Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	body, err := c.get(ctx, releaseURL, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up release of %s: %w", c.repository, err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to decode release of %s: %w", c.repository, err)
+	}
+	return &release, nil
+}
+
+// Download returns the content of a release asset
+func (c *Client) Download(ctx context.Context, asset *Asset) ([]byte, error) {
+	body, err := c.get(ctx, asset.URL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	return body, nil
+}
+
+// Checksum returns the SHA-256 checksum the release publishes for one of its assets,
+// from a <name>.sha256 asset or a checksums file listing all assets
+func (c *Client) Checksum(ctx context.Context, release *Release, name string) (string, error) {
+	candidates := append([]string{name + ".sha256"}, checksumAssets...)
+	for _, candidate := range candidates {
+		asset := release.Asset(candidate)
+		if asset == nil {
+			continue
+		}
+		content, err := c.Download(ctx, asset)
+		if err != nil {
+			return "", err
+		}
+		return ParseChecksum(string(content), name)
+	}
+	return "", fmt.Errorf("release %s publishes no checksum for %s (looked for %s)",
+		release.TagName, name, strings.Join(candidates, ", "))
+}
+
+// get reads a URL, failing for statuses other than 2xx
+func (c *Client) get(ctx context.Context, target, accept string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", accept)
+	request.Header.Set("User-Agent", BinaryName+"/"+version.GetSemanticVersion())
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s answered with status %d", target, response.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDownloadSize {
+		return nil, fmt.Errorf("GET %s returned more than %d bytes", target, maxDownloadSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,60 @@
+package update
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Newer reports whether version candidate is newer than version current. Versions are
+// semantic, major.minor.patch with an optional -prerelease, and may have a v prefix.
+func Newer(candidate, current string) bool {
+	return Compare(candidate, current) > 0
+}
+
+// Compare compares two semantic versions, returning -1, 0, or 1 as a is older than,
+// the same as, or newer than b. Missing or non-numeric parts count as zero, and a
+// prerelease is older than its release.
+func Compare(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if aCore[i] != bCore[i] {
+			if aCore[i] < bCore[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	}
+	return 1
+}
+
+// splitVersion splits a version into its major, minor, and patch numbers and its
+// prerelease, ignoring build metadata
+func splitVersion(version string) ([3]int, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	prerelease := ""
+	if i := strings.Index(version, "-"); i >= 0 {
+		version, prerelease = version[:i], version[i+1:]
+	}
+
+	var core [3]int
+	for i, part := range strings.SplitN(version, ".", 3) {
+		core[i], _ = strconv.Atoi(part)
+	}
+	return core, prerelease
+}
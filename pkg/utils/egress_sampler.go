@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// egressSampler decides which upstream requests are logged in full. It is shared by a
+// logger and its children, so that changing the rate at runtime applies to every
+// client logging through them.
+type egressSampler struct {
+	mutex sync.RWMutex
+	rate  float64
+}
+
+// set replaces the sample rate, clamped to [0, 1]
+func (s *egressSampler) set(rate float64) {
+	if rate < 0 {
+		rate = 0
+	} else if rate > 1 {
+		rate = 1
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rate = rate
+}
+
+// get returns the sample rate
+func (s *egressSampler) get() float64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.rate
+}
+
+// sample reports whether the next request should be logged in full
+func (s *egressSampler) sample() bool {
+	rate := s.get()
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// SetEgressSampleRate logs this fraction, from 0 to 1, of upstream requests in full.
+// The rate is shared with every child logger.
+func (l *Logger) SetEgressSampleRate(rate float64) {
+	l.egress.set(rate)
+}
+
+// EgressSampleRate returns the fraction of upstream requests logged in full
+func (l *Logger) EgressSampleRate() float64 {
+	return l.egress.get()
+}
+
+// SampleEgress reports whether an upstream request should be logged in full, by the
+// egress sample rate
+func (l *Logger) SampleEgress() bool {
+	return l.enabled() && l.egress.sample()
+}
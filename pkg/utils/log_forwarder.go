@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogEntry is a log event delivered to a forwarding handler
+type LogEntry struct {
+	Level   zapcore.Level
+	Logger  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// LogHandler receives forwarded log entries. It is called synchronously from the
+// logging call, so it must not log through the same logger.
+type LogHandler func(entry LogEntry)
+
+// logForwarder holds the forwarding handler shared by a logger and its children
+type logForwarder struct {
+	mutex   sync.RWMutex
+	level   zapcore.Level
+	handler LogHandler
+}
+
+// set replaces the handler and minimum level; a nil handler disables forwarding
+func (f *logForwarder) set(level zapcore.Level, handler LogHandler) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.level = level
+	f.handler = handler
+}
+
+// active reports whether a handler is installed
+func (f *logForwarder) active() bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.handler != nil
+}
+
+// enabled reports whether entries at the level should be forwarded
+func (f *logForwarder) enabled(level zapcore.Level) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.handler != nil && level >= f.level
+}
+
+// forward delivers an entry to the current handler
+func (f *logForwarder) forward(entry LogEntry) {
+	f.mutex.RLock()
+	handler := f.handler
+	level := f.level
+	f.mutex.RUnlock()
+
+	if handler != nil && entry.Level >= level {
+		handler(entry)
+	}
+}
+
+// wrapCore tees the logger's output into the forwarder
+func (f *logForwarder) wrapCore() zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, &forwardingCore{forwarder: f})
+	})
+}
+
+// forwardingCore is a zapcore.Core that hands entries to a logForwarder
+type forwardingCore struct {
+	forwarder *logForwarder
+	fields    []zapcore.Field
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *forwardingCore) Enabled(level zapcore.Level) bool {
+	return c.forwarder.enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *forwardingCore) With(fields []zapcore.Field) zapcore.Core {
+	combined := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	combined = append(combined, c.fields...)
+	combined = append(combined, fields...)
+	return &forwardingCore{forwarder: c.forwarder, fields: combined}
+}
+
+// Check implements zapcore.Core
+func (c *forwardingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core
+func (c *forwardingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	c.forwarder.forward(LogEntry{
+		Level:   entry.Level,
+		Logger:  entry.LoggerName,
+		Message: entry.Message,
+		Fields:  encoder.Fields,
+	})
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *forwardingCore) Sync() error {
+	return nil
+}
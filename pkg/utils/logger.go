@@ -14,13 +14,18 @@ import (
 type Logger struct {
 	zapLogger *zap.Logger
 	config    types.LoggingConfig
+	forwarder *logForwarder
+	egress    *egressSampler
 }
 
 // NewLogger creates a new logger with the given configuration
 func NewLogger(config types.LoggingConfig) *Logger {
 	zapConfig := buildZapConfig(config)
+	forwarder := &logForwarder{}
+	egress := &egressSampler{}
+	egress.set(config.EgressSampleRate)
 
-	logger, err := zapConfig.Build()
+	logger, err := zapConfig.Build(forwarder.wrapCore())
 	if err != nil {
 		// Fallback to a basic logger if config fails
 		logger = zap.NewNop()
@@ -29,6 +34,8 @@ func NewLogger(config types.LoggingConfig) *Logger {
 	return &Logger{
 		zapLogger: logger,
 		config:    config,
+		forwarder: forwarder,
+		egress:    egress,
 	}
 }
 
@@ -37,12 +44,26 @@ func (l *Logger) Child(namespace string) *Logger {
 	return &Logger{
 		zapLogger: l.zapLogger.Named(namespace),
 		config:    l.config,
+		forwarder: l.forwarder,
+		egress:    l.egress,
 	}
 }
 
+// SetForwarder sends entries at or above level to handler, in addition to the
+// configured output and regardless of whether that output is enabled. The
+// forwarder is shared with every child logger; a nil handler stops forwarding.
+func (l *Logger) SetForwarder(level zapcore.Level, handler LogHandler) {
+	l.forwarder.set(level, handler)
+}
+
+// enabled reports whether log calls should reach zap at all
+func (l *Logger) enabled() bool {
+	return l.config.Enabled || l.forwarder.active()
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(message string, fields ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 
@@ -56,7 +77,7 @@ func (l *Logger) Debug(message string, fields ...interface{}) {
 
 // Info logs an info message
 func (l *Logger) Info(message string, fields ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 
@@ -70,7 +91,7 @@ func (l *Logger) Info(message string, fields ...interface{}) {
 
 // Warn logs a warning message
 func (l *Logger) Warn(message string, fields ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 
@@ -84,7 +105,7 @@ func (l *Logger) Warn(message string, fields ...interface{}) {
 
 // Error logs an error message
 func (l *Logger) Error(message string, fields ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 
@@ -132,7 +153,7 @@ func (l *Logger) UpdateConfig(config types.LoggingConfig) {
 
 	// Rebuild logger with new config
 	zapConfig := buildZapConfig(config)
-	newLogger, err := zapConfig.Build()
+	newLogger, err := zapConfig.Build(l.forwarder.wrapCore())
 	if err != nil {
 		l.Error("Failed to update logger config", zap.Error(err))
 		return
@@ -277,7 +298,7 @@ func (l *Logger) LogWarn(message string, context ...map[string]interface{}) {
 
 // Debugf logs a debug message with printf-style formatting
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 	l.zapLogger.Debug(fmt.Sprintf(format, args...))
@@ -285,7 +306,7 @@ func (l *Logger) Debugf(format string, args ...interface{}) {
 
 // Infof logs an info message with printf-style formatting
 func (l *Logger) Infof(format string, args ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 	l.zapLogger.Info(fmt.Sprintf(format, args...))
@@ -293,7 +314,7 @@ func (l *Logger) Infof(format string, args ...interface{}) {
 
 // Warnf logs a warning message with printf-style formatting
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 	l.zapLogger.Warn(fmt.Sprintf(format, args...))
@@ -301,7 +322,7 @@ func (l *Logger) Warnf(format string, args ...interface{}) {
 
 // Errorf logs an error message with printf-style formatting
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	if !l.config.Enabled {
+	if !l.enabled() {
 		return
 	}
 	l.zapLogger.Error(fmt.Sprintf(format, args...))